@@ -0,0 +1,292 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-external_dns is a kubectl plugin showing what a running ExternalDNS
+// derives from the cluster and what it manages on the DNS provider. It queries
+// the debug endpoints of ExternalDNS through the Kubernetes API server proxy.
+//
+//	kubectl external-dns status
+//	kubectl external-dns describe app.example.org
+//	kubectl external-dns describe ingress/default/app
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/external-dns/controller"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/tlsutils"
+	"sigs.k8s.io/external-dns/source"
+)
+
+const requestTimeout = 30 * time.Second
+
+// fetcher retrieves a debug endpoint of ExternalDNS.
+type fetcher interface {
+	get(ctx context.Context, path string, params map[string]string) ([]byte, error)
+}
+
+// credentials authenticate the plugin with a bearer token read from TokenFile or with the client
+// certificate of CertFile and KeyFile, and verify the server certificate with the CAs of CAFile.
+type credentials struct {
+	TokenFile string
+	CAFile    string
+	CertFile  string
+	KeyFile   string
+}
+
+// serviceProxyFetcher reaches ExternalDNS through the API server proxy of its service.
+type serviceProxyFetcher struct {
+	client    kubernetes.Interface
+	scheme    string
+	namespace string
+	service   string
+	port      string
+}
+
+// newServiceProxyFetcher returns a fetcher using the kubeconfig, whose credentials for the API server
+// are replaced by the ones that are set.
+func newServiceProxyFetcher(kubeConfig, scheme, namespace, service, port string, creds credentials) (*serviceProxyFetcher, error) {
+	config, err := source.GetRestConfig(kubeConfig, "")
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = requestTimeout
+	if creds.TokenFile != "" {
+		config.BearerToken = ""
+		config.BearerTokenFile = creds.TokenFile
+	}
+	if creds.CAFile != "" {
+		config.TLSClientConfig.CAData = nil
+		config.TLSClientConfig.CAFile = creds.CAFile
+	}
+	if creds.CertFile != "" {
+		config.TLSClientConfig.CertData = nil
+		config.TLSClientConfig.KeyData = nil
+		config.TLSClientConfig.CertFile = creds.CertFile
+		config.TLSClientConfig.KeyFile = creds.KeyFile
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &serviceProxyFetcher{client: client, scheme: scheme, namespace: namespace, service: service, port: port}, nil
+}
+
+func (f *serviceProxyFetcher) get(ctx context.Context, path string, params map[string]string) ([]byte, error) {
+	return f.client.CoreV1().Services(f.namespace).ProxyGet(f.scheme, f.service, f.port, path, params).DoRaw(ctx)
+}
+
+// urlFetcher reaches ExternalDNS directly, e.g. through kubectl port-forward.
+type urlFetcher struct {
+	baseURL   string
+	tokenFile string
+	client    *http.Client
+}
+
+// newURLFetcher returns a fetcher authenticating with the credentials to ExternalDNS at the URL.
+func newURLFetcher(baseURL string, creds credentials) (*urlFetcher, error) {
+	tlsConfig, err := tlsutils.NewTLSConfig(creds.CertFile, creds.KeyFile, creds.CAFile, "", false, tls.VersionTLS12)
+	if err != nil {
+		return nil, err
+	}
+	return &urlFetcher{
+		baseURL:   baseURL,
+		tokenFile: creds.TokenFile,
+		client:    &http.Client{Timeout: requestTimeout, Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig}},
+	}, nil
+}
+
+func (f *urlFetcher) get(ctx context.Context, path string, params map[string]string) ([]byte, error) {
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	target := strings.TrimSuffix(f.baseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if f.tokenFile != "" {
+		token, err := os.ReadFile(f.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the bearer token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s: %s", target, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func getJSON(ctx context.Context, f fetcher, path string, params map[string]string, v interface{}) error {
+	body, err := f.get(ctx, path, params)
+	if err != nil {
+		return fmt.Errorf("querying ExternalDNS: %w", err)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// filterParams returns the query parameters selecting a hostname or a resource given as kind/namespace/name.
+func filterParams(arg string) map[string]string {
+	if strings.Contains(arg, "/") {
+		return map[string]string{"resource": strings.ToLower(arg)}
+	}
+	if name, err := endpoint.NormalizeDNSName(arg); err == nil {
+		arg = name
+	}
+	return map[string]string{"name": arg}
+}
+
+func printStatus(w io.Writer, status controller.Status, now time.Time) {
+	fmt.Fprintf(w, "Last sync attempt:\t%s\n", formatTime(status.LastAttempt, now))
+	fmt.Fprintf(w, "Last successful sync:\t%s\n", formatTime(status.LastSuccess, now))
+	if status.LastError != "" {
+		fmt.Fprintf(w, "Last error:\t%s\n", status.LastError)
+	}
+	fmt.Fprintf(w, "Desired endpoints:\t%d\n", len(status.Endpoints))
+}
+
+func formatTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return fmt.Sprintf("%s (%s ago)", t.Format(time.RFC3339), now.Sub(t).Round(time.Second))
+}
+
+func printEndpoints(w io.Writer, title string, endpoints []*endpoint.Endpoint) {
+	fmt.Fprintf(w, "\n%s:\n", title)
+	if len(endpoints) == 0 {
+		fmt.Fprintln(w, "  <none>")
+		return
+	}
+	fmt.Fprintln(w, "  NAME\tTYPE\tTTL\tTARGETS\tOWNER\tRESOURCE")
+	for _, ep := range endpoints {
+		fmt.Fprintf(w, "  %s\t%s\t%d\t%s\t%s\t%s\n",
+			endpoint.DisplayDNSName(ep.DNSName), ep.RecordType, ep.RecordTTL, strings.Join(ep.Targets, ","),
+			valueOrNone(ep.Labels[endpoint.OwnerLabelKey]), valueOrNone(ep.Labels[endpoint.ResourceLabelKey]))
+	}
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+func runStatus(ctx context.Context, f fetcher, out io.Writer) error {
+	var status controller.Status
+	if err := getJSON(ctx, f, "/debug/status", nil, &status); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	printStatus(w, status, time.Now())
+	return w.Flush()
+}
+
+func runDescribe(ctx context.Context, f fetcher, out io.Writer, arg string) error {
+	params := filterParams(arg)
+	var status controller.Status
+	if err := getJSON(ctx, f, "/debug/status", params, &status); err != nil {
+		return err
+	}
+	var records []*endpoint.Endpoint
+	if err := getJSON(ctx, f, "/debug/records", params, &records); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	printStatus(w, status, time.Now())
+	printEndpoints(w, "Desired endpoints (derived from the sources)", status.Endpoints)
+	printEndpoints(w, "Registry records (present on the DNS provider)", records)
+	return w.Flush()
+}
+
+func main() {
+	app := kingpin.New("kubectl-external_dns", "Inspect a running ExternalDNS: its last synchronization, the endpoints it derives from resources and the records it owns.")
+	kubeConfig := app.Flag("kubeconfig", "Path to the kubeconfig file").Default("").String()
+	namespace := app.Flag("namespace", "Namespace ExternalDNS runs in").Short('n').Default("external-dns").String()
+	service := app.Flag("service", "Name of the service exposing the ExternalDNS metrics port").Default("external-dns").String()
+	port := app.Flag("port", "Name or number of the service port exposing the ExternalDNS metrics").Default("http").String()
+	scheme := app.Flag("scheme", "Scheme the API server proxy uses to reach the service port, https when ExternalDNS serves its metrics with TLS").Default("http").Enum("http", "https")
+	baseURL := app.Flag("url", "Query ExternalDNS at this URL instead of through the API server proxy, e.g. http://localhost:7979 with kubectl port-forward").Default("").String()
+	var creds credentials
+	app.Flag("token-file", "File containing the bearer token sent to ExternalDNS with --url, or to the API server instead of the one of the kubeconfig").Default("").StringVar(&creds.TokenFile)
+	app.Flag("ca-file", "File containing the CAs verifying the certificate of ExternalDNS with --url, or of the API server instead of the ones of the kubeconfig").Default("").StringVar(&creds.CAFile)
+	app.Flag("cert-file", "File containing the client certificate presented to ExternalDNS with --url, or to the API server instead of the one of the kubeconfig; requires --key-file").Default("").StringVar(&creds.CertFile)
+	app.Flag("key-file", "File containing the key of --cert-file").Default("").StringVar(&creds.KeyFile)
+
+	statusCmd := app.Command("status", "Show the outcome of the last synchronization")
+	describeCmd := app.Command("describe", "Show the desired endpoints and the registry records of a hostname or a resource")
+	describeArg := describeCmd.Arg("hostname-or-resource", "A hostname, or a resource as kind/namespace/name, e.g. ingress/default/app").Required().String()
+
+	command := kingpin.MustParse(app.Parse(os.Args[1:]))
+	if (creds.CertFile == "") != (creds.KeyFile == "") {
+		app.Fatalf("--cert-file and --key-file must be set together")
+	}
+
+	var (
+		f   fetcher
+		err error
+	)
+	if *baseURL != "" {
+		f, err = newURLFetcher(*baseURL, creds)
+	} else {
+		f, err = newServiceProxyFetcher(*kubeConfig, *scheme, *namespace, *service, *port, creds)
+	}
+	if err != nil {
+		app.Fatalf("%v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	switch command {
+	case statusCmd.FullCommand():
+		err = runStatus(ctx, f, os.Stdout)
+	case describeCmd.FullCommand():
+		err = runDescribe(ctx, f, os.Stdout, *describeArg)
+	}
+	if err != nil {
+		app.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/controller"
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestFilterParams(t *testing.T) {
+	assert.Equal(t, map[string]string{"name": "app.example.org"}, filterParams("app.example.org"))
+	assert.Equal(t, map[string]string{"name": "xn--bcher-kva.example.org"}, filterParams("bücher.example.org"))
+	assert.Equal(t, map[string]string{"resource": "ingress/default/app"}, filterParams("Ingress/default/app"))
+}
+
+func TestDescribe(t *testing.T) {
+	desired := endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.2.3.4")
+	desired.Labels[endpoint.ResourceLabelKey] = "ingress/default/app"
+	record := endpoint.NewEndpointWithTTL("app.example.org", endpoint.RecordTypeA, 300, "1.2.3.4")
+	record.Labels[endpoint.OwnerLabelKey] = "my-cluster"
+	record.Labels[endpoint.ResourceLabelKey] = "ingress/default/app"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ingress/default/app", r.URL.Query().Get("resource"))
+		switch r.URL.Path {
+		case "/debug/status":
+			json.NewEncoder(w).Encode(controller.Status{
+				LastAttempt: time.Now().Add(-time.Minute),
+				LastSuccess: time.Now().Add(-2 * time.Minute),
+				LastError:   "provider unavailable",
+				Endpoints:   []*endpoint.Endpoint{desired},
+			})
+		case "/debug/records":
+			json.NewEncoder(w).Encode([]*endpoint.Endpoint{record})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	f := &urlFetcher{baseURL: server.URL, client: server.Client()}
+	require.NoError(t, runDescribe(context.Background(), f, &out, "ingress/default/app"))
+
+	assert.Regexp(t, `Last error:\s+provider unavailable`, out.String())
+	assert.Regexp(t, `Desired endpoints:\s+1`, out.String())
+	assert.Regexp(t, `app\.example\.org\s+A\s+0\s+1\.2\.3\.4\s+<none>\s+ingress/default/app`, out.String())
+	assert.Regexp(t, `app\.example\.org\s+A\s+300\s+1\.2\.3\.4\s+my-cluster\s+ingress/default/app`, out.String())
+}
+
+func TestStatusUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	f := &urlFetcher{baseURL: server.URL, client: server.Client()}
+	assert.ErrorContains(t, runStatus(context.Background(), f, &bytes.Buffer{}), "404")
+}
+
+// writeCredentials writes the CA of the TLS server and a bearer token into files.
+func writeCredentials(t *testing.T, server *httptest.Server) credentials {
+	dir := t.TempDir()
+	creds := credentials{CAFile: filepath.Join(dir, "ca.crt"), TokenFile: filepath.Join(dir, "token")}
+	require.NoError(t, os.WriteFile(creds.CAFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+	require.NoError(t, os.WriteFile(creds.TokenFile, []byte("secret\n"), 0o600))
+	return creds
+}
+
+func TestURLFetcherCredentials(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(controller.Status{})
+	}))
+	defer server.Close()
+
+	f, err := newURLFetcher(server.URL, writeCredentials(t, server))
+	require.NoError(t, err)
+	assert.NoError(t, runStatus(context.Background(), f, &bytes.Buffer{}))
+
+	f, err = newURLFetcher(server.URL, credentials{})
+	require.NoError(t, err)
+	assert.ErrorContains(t, runStatus(context.Background(), f, &bytes.Buffer{}), "certificate")
+}
+
+func TestServiceProxyFetcherCredentials(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/namespaces/external-dns/services/https:external-dns:http/proxy/debug/status", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(controller.Status{})
+	}))
+	defer server.Close()
+
+	kubeConfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeConfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: `+server.URL+`
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: other
+`), 0o600))
+
+	f, err := newServiceProxyFetcher(kubeConfig, "https", "external-dns", "external-dns", "http", writeCredentials(t, server))
+	require.NoError(t, err)
+	assert.NoError(t, runStatus(context.Background(), f, &bytes.Buffer{}))
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// approveChanges holds the changes to apply until the ApprovalGate approved them,
+// which stay pending as the next synchronizations plan them again.
+func (c *Controller) approveChanges(ctx context.Context, changes, held *plan.Changes) (apply, stillHeld *plan.Changes, err error) {
+	if c.ApprovalGate == nil || !changes.HasChanges() {
+		return changes, held, nil
+	}
+	approved, err := c.ApprovalGate.Approved(ctx, changes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("requesting the approval of changes: %w", err)
+	}
+	if approved {
+		return changes, held, nil
+	}
+	return &plan.Changes{}, &plan.Changes{
+		Create:    append(held.Create, changes.Create...),
+		UpdateOld: append(held.UpdateOld, changes.UpdateOld...),
+		UpdateNew: append(held.UpdateNew, changes.UpdateNew...),
+		Delete:    append(held.Delete, changes.Delete...),
+	}, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// fakeGate approves changes once approve is set, and records the results of applying them.
+type fakeGate struct {
+	approve bool
+	err     error
+	asked   []*plan.Changes
+	results []error
+}
+
+func (g *fakeGate) Approved(ctx context.Context, changes *plan.Changes) (bool, error) {
+	g.asked = append(g.asked, changes)
+	return g.approve, g.err
+}
+
+func (g *fakeGate) Applied(ctx context.Context, changes *plan.Changes, err error) error {
+	g.results = append(g.results, err)
+	return nil
+}
+
+func newApprovalController(t *testing.T, gate *fakeGate) (*Controller, *filteredMockProvider) {
+	p := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	return &Controller{
+		Source: &staticSource{endpoints: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		}},
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		ApprovalGate:       gate,
+	}, p
+}
+
+func TestRunOnceHoldsChangesUntilApproved(t *testing.T) {
+	gate := &fakeGate{}
+	ctrl, p := newApprovalController(t, gate)
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, gate.asked, 1)
+	assert.Len(t, gate.asked[0].Create, 1)
+	assert.Empty(t, p.ApplyChangesCalls)
+	assert.Empty(t, gate.results)
+	assert.Equal(t, 1, ctrl.status.get().PendingChanges)
+	assert.Nil(t, ctrl.status.get().NextChangeWindow)
+
+	gate.approve = true
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, p.ApplyChangesCalls, 1)
+	assert.Len(t, p.ApplyChangesCalls[0].Create, 1)
+	assert.Equal(t, []error{nil}, gate.results)
+	assert.Zero(t, ctrl.status.get().PendingChanges)
+}
+
+func TestRunOnceFailsWhenApprovalFails(t *testing.T) {
+	gate := &fakeGate{err: errors.New("forbidden")}
+	ctrl, p := newApprovalController(t, gate)
+
+	assert.Error(t, ctrl.RunOnce(context.Background()))
+	assert.Empty(t, p.ApplyChangesCalls)
+}
+
+func TestRunOnceDryRunDoesNotReportApprovedChangesApplied(t *testing.T) {
+	gate := &fakeGate{approve: true}
+	ctrl, p := newApprovalController(t, gate)
+	ctrl.DryRun = true
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, gate.asked, 1)
+	require.Len(t, p.ApplyChangesCalls, 1)
+	assert.Empty(t, gate.results)
+}
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -26,6 +27,13 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/approval"
+	"sigs.k8s.io/external-dns/pkg/audit"
+	"sigs.k8s.io/external-dns/pkg/changewindow"
+	"sigs.k8s.io/external-dns/pkg/dnspolicy"
+	"sigs.k8s.io/external-dns/pkg/export"
+	"sigs.k8s.io/external-dns/pkg/notify"
+	"sigs.k8s.io/external-dns/pkg/verify"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 	"sigs.k8s.io/external-dns/registry"
@@ -196,10 +204,83 @@ type Controller struct {
 	ExcludeRecordTypes []string
 	// MinEventSyncInterval is used as window for batching events
 	MinEventSyncInterval time.Duration
+	// IgnoreTTL makes differences in TTL alone not trigger updates
+	IgnoreTTL bool
+	// MinTTL is the minimum TTL enforced by the provider, used when comparing TTLs
+	MinTTL endpoint.TTL
+	// MetadataSensitive makes changes of registry labels trigger updates
+	MetadataSensitive bool
+	// TargetNormalizations are applied to targets before comparing current and desired records
+	TargetNormalizations []plan.TargetNormalization
+	// DeletionGracePeriod is how long owned records stay tombstoned before being deleted
+	DeletionGracePeriod time.Duration
+	// WildcardPolicy controls the creation of records shadowing wildcard records of the owner
+	WildcardPolicy plan.WildcardPolicy
+	// FQDNPolicy controls the canonical form of the desired DNS names and host name targets
+	FQDNPolicy plan.FQDNPolicy
+	// WeightProperty is the provider specific property holding the weights of the records of DNS names with canary records
+	WeightProperty string
+	// AuditSink, if set, receives an audit entry for every applied change
+	AuditSink audit.Sink
+	// DriftRecorder, if set, records the records found changed outside ExternalDNS
+	DriftRecorder DriftRecorder
+	// DryRun is set when the provider does not actually apply changes, so that nothing relies on them taking effect
+	DryRun bool
+	// FullReconcileInterval enables incremental synchronizations. In between full reconciliations,
+	// which happen at least this often, only the DNS names whose desired endpoints changed are planned.
+	FullReconcileInterval time.Duration
+	// DrainTimeout bounds how long Run lets an in-flight synchronization finish after its context is canceled
+	DrainTimeout time.Duration
+	// ChangeWindows, if set, restrict when changes are applied; outside of them, changes stay pending
+	ChangeWindows changewindow.Windows
+	// ChangeWindowDeletionsOnly restricts only the deletions to the ChangeWindows
+	ChangeWindowDeletionsOnly bool
+	// ApprovalGate, if set, holds changes until they are approved
+	ApprovalGate approval.Gate
+	// DNSPolicies, if set, drops the desired endpoints the resources they originate from are not entitled to
+	DNSPolicies dnspolicy.Enforcer
+	// StateExporter, if set, receives the managed records after every successful synchronization
+	StateExporter export.Exporter
+	// Notifier, if set, is notified of the changes applied, or failed to be applied, by every synchronization
+	Notifier notify.Notifier
+	// Verifier, if set, verifies that the records applied by every synchronization resolve
+	Verifier verify.Verifier
+	// SyncReporters, if set, receive the outcome of every synchronization for the desired endpoints
+	SyncReporters []source.SyncReporter
+	// DebugStatusEndpoint keeps a copy of the desired endpoints of the last synchronization for NewStatusHandler
+	DebugStatusEndpoint bool
+	// DebugRecordsEndpoint keeps a copy of the registry records of the last synchronization for SyncedRecords
+	DebugRecordsEndpoint bool
+	// PendingChangesFile, if set, persists the changes which could not be applied before shutting down,
+	// so that they are resumed by the first synchronization of the next run
+	PendingChangesFile string
+	// The desired state of the last successful synchronization
+	lastDesired desiredState
+	// The time of the last successful full reconciliation
+	lastFullReconcile time.Time
+	// The outcome of the last synchronization, exposed by NewStatusHandler
+	status syncStatus
+	// The records applied by previous synchronizations, used to detect changes made outside ExternalDNS
+	applied appliedRecords
+	// The applied records which did not resolve yet, verified again by every synchronization
+	unverified map[endpoint.EndpointKey]unverifiedRecord
+	// Whether the pending changes of a previous run were looked for
+	pendingResumed bool
+	// The runMux serializes the synchronizations with the changes of the configuration made by Reconfigure
+	runMux sync.Mutex
 }
 
 // RunOnce runs a single iteration of a reconciliation loop.
 func (c *Controller) RunOnce(ctx context.Context) error {
+	c.runMux.Lock()
+	defer c.runMux.Unlock()
+	attempt := time.Now()
+	err := c.runOnce(ctx)
+	c.status.finish(attempt, err)
+	return err
+}
+
+func (c *Controller) runOnce(ctx context.Context) error {
 	lastReconcileTimestamp.SetToCurrentTime()
 
 	records, err := c.Registry.Records(ctx)
@@ -209,13 +290,27 @@ func (c *Controller) RunOnce(ctx context.Context) error {
 		return err
 	}
 
+	if c.DebugRecordsEndpoint {
+		c.status.setRecords(records)
+	}
 	registryEndpointsTotal.Set(float64(len(records)))
 	regARecords, regAAAARecords := countAddressRecords(records)
 	registryARecords.Set(float64(regARecords))
 	registryAAAARecords.Set(float64(regAAAARecords))
 	ctx = context.WithValue(ctx, provider.RecordsContextKey, records)
 
-	endpoints, err := c.Source.Endpoints(ctx)
+	if c.PendingChangesFile != "" && !c.pendingResumed {
+		resumed, err := c.resumePendingChanges(ctx, records)
+		if err != nil || resumed {
+			return err
+		}
+	}
+
+	normalizations := c.FQDNPolicy.Normalizations(c.TargetNormalizations)
+	c.recordDrift(ctx, records, normalizations)
+
+	sourcesCtx, unavailable := source.WithUnavailableSources(ctx)
+	endpoints, err := c.Source.Endpoints(sourcesCtx)
 	if err != nil {
 		sourceErrorsTotal.Inc()
 		deprecatedSourceErrors.Inc()
@@ -232,30 +327,134 @@ func (c *Controller) RunOnce(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("adjusting endpoints: %w", err)
 	}
+	c.FQDNPolicy.Canonicalize(endpoints)
+	if c.DNSPolicies != nil {
+		endpoints, err = c.DNSPolicies.Enforce(ctx, endpoints)
+		if err != nil {
+			return fmt.Errorf("enforcing DNS policies: %w", err)
+		}
+	}
+	if c.DebugStatusEndpoint {
+		c.status.setEndpoints(endpoints)
+	}
 	registryFilter := c.Registry.GetDomainFilter()
 
+	current, desired := records, endpoints
+	state := newDesiredState(endpoints)
+	fullReconcile := c.FullReconcileInterval <= 0 || c.lastDesired == nil || time.Since(c.lastFullReconcile) >= c.FullReconcileInterval
+	if !fullReconcile {
+		changed := state.changedNames(c.lastDesired)
+		// tombstoned records have to be planned until they are deleted
+		for _, r := range records {
+			if _, ok := r.Labels[endpoint.TombstoneLabelKey]; ok {
+				changed[dnsNameKey(r.DNSName)] = true
+			}
+		}
+		log.Debugf("Planning %d DNS names whose desired endpoints changed", len(changed))
+		current = filterByNames(records, changed)
+		desired = filterByNames(endpoints, changed)
+	}
+
+	policies := []plan.Policy{c.Policy}
+	if names := unavailable.Names(); len(names) > 0 {
+		// the records of the unavailable sources are unknown, they must not be deleted as undesired
+		log.Warnf("Not deleting any records while the sources %v are unavailable", names)
+		policies = append(policies, &plan.UpsertOnlyPolicy{})
+	}
+
 	plan := &plan.Plan{
-		Policies:       []plan.Policy{c.Policy},
-		Current:        records,
-		Desired:        endpoints,
+		Policies:       policies,
+		Current:        current,
+		Existing:       records,
+		Desired:        desired,
 		DomainFilter:   endpoint.MatchAllDomainFilters{&c.DomainFilter, &registryFilter},
 		ManagedRecords: c.ManagedRecordTypes,
 		ExcludeRecords: c.ExcludeRecordTypes,
 		OwnerID:        c.Registry.OwnerID(),
+
+		IgnoreTTL:            c.IgnoreTTL,
+		MinTTL:               c.MinTTL,
+		MetadataSensitive:    c.MetadataSensitive,
+		TargetNormalizations: normalizations,
+		DeletionGracePeriod:  c.DeletionGracePeriod,
+		WildcardPolicy:       c.WildcardPolicy,
+		WeightProperty:       c.WeightProperty,
 	}
 
 	plan = plan.Calculate()
+	now := time.Now()
+	changes, held := c.holdChanges(plan.Changes, now)
+	changes, held, err = c.approveChanges(ctx, changes, held)
+	if err != nil {
+		return err
+	}
+	c.reportPendingChanges(held, now)
 
-	if plan.Changes.HasChanges() {
-		err = c.Registry.ApplyChanges(ctx, plan.Changes)
+	if changes.HasChanges() {
+		err = c.Registry.ApplyChanges(ctx, changes)
+		applied := time.Now()
+		if c.ApprovalGate != nil && !c.DryRun {
+			if err := c.ApprovalGate.Applied(ctx, changes, err); err != nil {
+				log.Errorf("Failed to record the result of approved changes: %v", err)
+			}
+		}
+		if c.Notifier != nil && !c.DryRun {
+			if err := c.Notifier.Notify(ctx, notify.NewNotification(changes, c.Registry.OwnerID(), time.Now(), err)); err != nil {
+				log.Errorf("Failed to send the notification of changes: %v", err)
+			}
+		}
 		if err != nil {
 			registryErrorsTotal.Inc()
 			deprecatedRegistryErrors.Inc()
+			// plan all DNS names again during the next synchronization
+			c.lastDesired = nil
+			c.savePendingChanges(ctx, changes)
+			c.reportSync(ctx, endpoints, records, changes, held, err)
 			return err
 		}
+		if !c.DryRun {
+			if c.applied == nil {
+				c.applied = appliedRecords{}
+			}
+			c.applied.update(changes)
+		}
+		if c.AuditSink != nil && !c.DryRun {
+			if err := c.AuditSink.Write(ctx, audit.NewEntries(changes, c.Registry.OwnerID(), time.Now())); err != nil {
+				log.Errorf("Failed to write audit entries: %v", err)
+			}
+		}
+		c.verifyChanges(ctx, changes, applied)
 	} else {
-		controllerNoChangesTotal.Inc()
-		log.Info("All records are already up to date")
+		if !held.HasChanges() {
+			controllerNoChangesTotal.Inc()
+			log.Info("All records are already up to date")
+		}
+		// records which did not resolve yet are verified again
+		c.verifyChanges(ctx, changes, time.Now())
+	}
+
+	if !c.DryRun {
+		if err := registry.CollectGarbage(ctx, c.Registry); err != nil {
+			log.Errorf("Failed to collect the garbage of the registry: %v", err)
+		}
+	}
+
+	c.reportSync(ctx, endpoints, records, changes, held, nil)
+
+	if c.StateExporter != nil {
+		if err := c.StateExporter.Export(ctx, managedRecords(records, changes, c.Registry.OwnerID())); err != nil {
+			log.Errorf("Failed to export the managed records: %v", err)
+		}
+	}
+
+	if held.HasChanges() {
+		// plan the DNS names with held changes again during the next synchronization
+		c.lastDesired = nil
+	} else if c.FullReconcileInterval > 0 {
+		c.lastDesired = state
+		if fullReconcile {
+			c.lastFullReconcile = time.Now()
+		}
 	}
 
 	lastSyncTimestamp.SetToCurrentTime()
@@ -263,6 +462,49 @@ func (c *Controller) RunOnce(ctx context.Context) error {
 	return nil
 }
 
+// resumePendingChanges applies the changes persisted by a previous run which was interrupted while applying them,
+// skipping the ones which already took effect. It reports whether changes were resumed.
+func (c *Controller) resumePendingChanges(ctx context.Context, records []*endpoint.Endpoint) (bool, error) {
+	pending, err := loadPendingChanges(c.PendingChangesFile)
+	if err != nil {
+		return false, fmt.Errorf("reading pending changes: %w", err)
+	}
+	if pending != nil {
+		changes := remainingChanges(pending, records, c.FQDNPolicy.Normalizations(c.TargetNormalizations), c.MinTTL)
+		if changes.HasChanges() {
+			log.Infof("Resuming %d creations, %d updates and %d deletions interrupted by the previous shutdown",
+				len(changes.Create), len(changes.UpdateNew), len(changes.Delete))
+			if err := c.Registry.ApplyChanges(ctx, changes); err != nil {
+				registryErrorsTotal.Inc()
+				deprecatedRegistryErrors.Inc()
+				return false, err
+			}
+		}
+		if err := os.Remove(c.PendingChangesFile); err != nil {
+			return false, fmt.Errorf("removing pending changes: %w", err)
+		}
+	}
+	c.pendingResumed = true
+	if pending == nil || !pending.HasChanges() {
+		return false, nil
+	}
+	// the records changed, plan the desired state right after
+	c.ScheduleRunOnce(time.Now())
+	return true, nil
+}
+
+// savePendingChanges persists the changes whose application was aborted by the drain timeout.
+func (c *Controller) savePendingChanges(ctx context.Context, changes *plan.Changes) {
+	if c.PendingChangesFile == "" || ctx.Err() == nil {
+		return
+	}
+	if err := savePendingChanges(c.PendingChangesFile, changes); err != nil {
+		log.Errorf("Failed to persist pending changes: %v", err)
+		return
+	}
+	log.Infof("Persisted the pending changes to %s", c.PendingChangesFile)
+}
+
 // Counts the intersections of A and AAAA records in endpoint and registry.
 func countMatchingAddressRecords(endpoints []*endpoint.Endpoint, registryRecords []*endpoint.Endpoint) (int, int) {
 	recordsMap := make(map[string]map[string]struct{})
@@ -324,13 +566,31 @@ func (c *Controller) ShouldRunOnce(now time.Time) bool {
 	return true
 }
 
-// Run runs RunOnce in a loop with a delay until context is canceled
+// Run runs RunOnce in a loop with a delay until context is canceled.
+// Once the context is canceled, no new synchronization is started, but the one in flight
+// is given DrainTimeout to finish applying its changes.
 func (c *Controller) Run(ctx context.Context) {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(c.DrainTimeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-runCtx.Done():
+		}
+		cancel()
+	})
+	defer stop()
 	for {
-		if c.ShouldRunOnce(time.Now()) {
-			if err := c.RunOnce(ctx); err != nil {
+		if ctx.Err() == nil && c.ShouldRunOnce(time.Now()) {
+			if err := c.RunOnce(runCtx); err != nil {
+				if ctx.Err() != nil {
+					log.Errorf("Synchronization interrupted by shutdown: %v", err)
+					return
+				}
 				log.Fatal(err)
 			}
 		}
@@ -30,9 +30,11 @@ import (
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/internal/testutils"
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
+	"sigs.k8s.io/external-dns/pkg/audit"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 	"sigs.k8s.io/external-dns/registry"
+	"sigs.k8s.io/external-dns/source"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -684,3 +686,155 @@ func TestAAAARecords(t *testing.T) {
 	assert.Equal(t, math.Float64bits(2), valueFromMetric(sourceAAAARecords))
 	assert.Equal(t, math.Float64bits(1), valueFromMetric(registryAAAARecords))
 }
+
+// fakeEnforcer allows the endpoints of the allowed DNS names.
+type fakeEnforcer struct {
+	allowed map[string]bool
+	err     error
+}
+
+func (e *fakeEnforcer) Enforce(ctx context.Context, endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	var allowed []*endpoint.Endpoint
+	for _, ep := range endpoints {
+		if e.allowed[ep.DNSName] {
+			allowed = append(allowed, ep)
+		}
+	}
+	return allowed, nil
+}
+
+func TestRunOnceEnforcesDNSPolicies(t *testing.T) {
+	p := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	enforcer := &fakeEnforcer{allowed: map[string]bool{"a.used.tld": true}}
+	ctrl := &Controller{
+		Source: &staticSource{endpoints: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+			endpoint.NewEndpoint("b.used.tld", endpoint.RecordTypeA, "1.1.1.2"),
+		}},
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		DNSPolicies:        enforcer,
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, p.ApplyChangesCalls, 1)
+	require.Len(t, p.ApplyChangesCalls[0].Create, 1)
+	assert.Equal(t, "a.used.tld", p.ApplyChangesCalls[0].Create[0].DNSName)
+
+	enforcer.err = errors.New("forbidden")
+	assert.Error(t, ctrl.RunOnce(context.Background()))
+	assert.Len(t, p.ApplyChangesCalls, 1)
+}
+
+type auditRecorder struct {
+	entries []audit.Entry
+}
+
+func (r *auditRecorder) Write(_ context.Context, entries []audit.Entry) error {
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func TestRunOnceAuditSink(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		src := &staticSource{endpoints: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("audit.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		}}
+		p := &filteredMockProvider{}
+		r, err := registry.NewNoopRegistry(p)
+		require.NoError(t, err)
+
+		sink := &auditRecorder{}
+		ctrl := &Controller{
+			Source:             src,
+			Registry:           r,
+			Policy:             &plan.SyncPolicy{},
+			ManagedRecordTypes: []string{endpoint.RecordTypeA},
+			AuditSink:          sink,
+			DryRun:             dryRun,
+		}
+		require.NoError(t, ctrl.RunOnce(context.Background()))
+		if dryRun {
+			assert.Empty(t, sink.entries, "changes which were not applied should not be audited")
+		} else {
+			assert.Len(t, sink.entries, 1)
+		}
+	}
+}
+
+// garbageCollectingRegistry counts the garbage collections of the registry.
+type garbageCollectingRegistry struct {
+	registry.Registry
+	collections int
+}
+
+func (r *garbageCollectingRegistry) CollectGarbage(context.Context) error {
+	r.collections++
+	return nil
+}
+
+func TestRunOnceCollectsGarbage(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		noop, err := registry.NewNoopRegistry(&filteredMockProvider{})
+		require.NoError(t, err)
+		r := &garbageCollectingRegistry{Registry: noop}
+
+		ctrl := &Controller{
+			Source:             &staticSource{},
+			Registry:           registry.NewCachedRegistry(r, time.Minute),
+			Policy:             &plan.SyncPolicy{},
+			ManagedRecordTypes: []string{endpoint.RecordTypeA},
+			DryRun:             dryRun,
+		}
+		// the garbage is collected even when there are no changes
+		require.NoError(t, ctrl.RunOnce(context.Background()))
+		if dryRun {
+			assert.Zero(t, r.collections, "the garbage should not be collected in dry-run mode")
+		} else {
+			assert.Equal(t, 1, r.collections)
+		}
+	}
+}
+
+// erroringSource fails to collect its endpoints.
+type erroringSource struct{}
+
+func (erroringSource) Endpoints(context.Context) ([]*endpoint.Endpoint, error) {
+	return nil, errors.New("no matches for kind DNSEndpoint")
+}
+
+func (erroringSource) AddEventHandler(context.Context, func()) {}
+
+func TestRunOnceKeepsRecordsOfUnavailableSources(t *testing.T) {
+	p := &filteredMockProvider{
+		RecordsStore: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("crd.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		},
+	}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source: source.NewMultiSource([]source.Source{
+			source.NewTolerantSource(erroringSource{}, "crd", time.Hour),
+			&staticSource{endpoints: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("svc.used.tld", endpoint.RecordTypeA, "2.2.2.2"),
+			}},
+		}, nil, source.ConflictPolicyNone),
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, p.ApplyChangesCalls, 1)
+	require.Len(t, p.ApplyChangesCalls[0].Create, 1)
+	assert.Equal(t, "svc.used.tld", p.ApplyChangesCalls[0].Create[0].DNSName)
+	assert.Empty(t, p.ApplyChangesCalls[0].Delete, "the records of the unavailable source should be kept")
+}
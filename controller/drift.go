@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+var driftedRecordsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "external_dns",
+		Subsystem: "controller",
+		Name:      "drifted_records_total",
+		Help:      "Number of records found changed on the DNS provider since they were last applied.",
+	},
+	[]string{"record_type"},
+)
+
+func init() {
+	prometheus.MustRegister(driftedRecordsTotal)
+}
+
+// DriftRecorder records the records found changed outside ExternalDNS, e.g. as Kubernetes Events.
+type DriftRecorder interface {
+	RecordDrift(ctx context.Context, applied, observed *endpoint.Endpoint) error
+}
+
+// drift is a record found changed outside ExternalDNS.
+type drift struct {
+	applied  *endpoint.Endpoint
+	observed *endpoint.Endpoint
+}
+
+// appliedRecords remembers the records applied by the controller, so that
+// changes made to them outside ExternalDNS can be detected.
+type appliedRecords map[endpoint.EndpointKey]*endpoint.Endpoint
+
+func driftKey(e *endpoint.Endpoint) endpoint.EndpointKey {
+	return endpoint.EndpointKey{
+		DNSName:       dnsNameKey(e.DNSName),
+		RecordType:    e.RecordType,
+		SetIdentifier: e.SetIdentifier,
+	}
+}
+
+// update remembers the records created or updated by changes and forgets the deleted ones.
+func (a appliedRecords) update(changes *plan.Changes) {
+	for _, e := range changes.Delete {
+		delete(a, driftKey(e))
+	}
+	for _, e := range append(changes.Create, changes.UpdateNew...) {
+		a[driftKey(e)] = e.DeepCopy()
+	}
+}
+
+// detectDrift reports the records whose targets or TTL differ from the ones last applied, the applied
+// TTLs being raised to minTTL as the provider does. The observed values are remembered, so that a
+// drift which is not corrected is only reported once.
+func (a appliedRecords) detectDrift(records []*endpoint.Endpoint, normalizations []plan.TargetNormalization, minTTL endpoint.TTL) []drift {
+	if len(normalizations) == 0 {
+		normalizations = plan.DefaultTargetNormalizations
+	}
+	var drifted []drift
+	for _, observed := range records {
+		key := driftKey(observed)
+		applied, ok := a[key]
+		if !ok || !hasDrifted(applied, observed, normalizations, minTTL) {
+			continue
+		}
+		drifted = append(drifted, drift{applied: applied, observed: observed})
+		driftedRecordsTotal.WithLabelValues(observed.RecordType).Inc()
+		log.Warnf("Record %s %s was changed outside ExternalDNS: applied targets %v with TTL %d, found targets %v with TTL %d",
+			observed.DNSName, observed.RecordType, applied.Targets, applied.RecordTTL, observed.Targets, observed.RecordTTL)
+		a[key] = observed.DeepCopy()
+	}
+	return drifted
+}
+
+// recordDrift detects the records changed outside ExternalDNS and passes them to the DriftRecorder.
+func (c *Controller) recordDrift(ctx context.Context, records []*endpoint.Endpoint, normalizations []plan.TargetNormalization) {
+	for _, d := range c.applied.detectDrift(records, normalizations, c.MinTTL) {
+		if c.DriftRecorder == nil {
+			continue
+		}
+		if err := c.DriftRecorder.RecordDrift(ctx, d.applied, d.observed); err != nil {
+			log.Errorf("Failed to record the drift of %s %s: %v", d.observed.DNSName, d.observed.RecordType, err)
+		}
+	}
+}
+
+// hasDrifted compares the targets and, when the provider reports it, the TTL of two records,
+// the applied TTL being raised to minTTL like the plan does.
+func hasDrifted(applied, observed *endpoint.Endpoint, normalizations []plan.TargetNormalization, minTTL endpoint.TTL) bool {
+	if applied.RecordTTL.IsConfigured() && observed.RecordTTL.IsConfigured() && plan.ClampTTL(applied.RecordTTL, minTTL) != observed.RecordTTL {
+		return true
+	}
+	return !slices.Equal(
+		plan.NormalizeTargets(normalizations, applied.RecordType, applied.Targets),
+		plan.NormalizeTargets(normalizations, observed.RecordType, observed.Targets),
+	)
+}
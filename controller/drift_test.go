@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+func TestDetectDrift(t *testing.T) {
+	applied := appliedRecords{}
+	applied.update(&plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("a.example.org", endpoint.RecordTypeA, 300, "1.1.1.1"),
+			endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeCNAME, "lb.example.org"),
+			endpoint.NewEndpoint("c.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+		},
+	})
+	applied.update(&plan.Changes{
+		Delete: []*endpoint.Endpoint{endpoint.NewEndpoint("c.example.org", endpoint.RecordTypeA, "3.3.3.3")},
+	})
+
+	observed := []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("a.example.org.", endpoint.RecordTypeA, 300, "1.1.1.1"),
+		endpoint.NewEndpointWithTTL("b.example.org", endpoint.RecordTypeCNAME, 60, "LB.example.org"),
+		endpoint.NewEndpoint("c.example.org", endpoint.RecordTypeA, "9.9.9.9"),
+	}
+	assert.Empty(t, applied.detectDrift(observed, nil, 0), "equivalent records should not drift")
+
+	observed[0] = endpoint.NewEndpointWithTTL("a.example.org", endpoint.RecordTypeA, 600, "1.1.1.1")
+	observed[1] = endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeCNAME, "other.example.org")
+	assert.Len(t, applied.detectDrift(observed, nil, 0), 2)
+	assert.Empty(t, applied.detectDrift(observed, nil, 0), "drift should only be reported once")
+}
+
+func TestDetectDriftMinTTL(t *testing.T) {
+	applied := appliedRecords{}
+	applied.update(&plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("a.example.org", endpoint.RecordTypeA, 30, "1.1.1.1")},
+	})
+
+	// the provider raised the TTL to its minimum
+	observed := []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("a.example.org", endpoint.RecordTypeA, 60, "1.1.1.1")}
+	assert.Empty(t, applied.detectDrift(observed, nil, 60), "TTLs raised to the minimum should not drift")
+
+	observed[0] = endpoint.NewEndpointWithTTL("a.example.org", endpoint.RecordTypeA, 120, "1.1.1.1")
+	assert.Len(t, applied.detectDrift(observed, nil, 60), 1)
+}
+
+type driftRecorder struct {
+	drifted []*endpoint.Endpoint
+}
+
+func (r *driftRecorder) RecordDrift(_ context.Context, _, observed *endpoint.Endpoint) error {
+	r.drifted = append(r.drifted, observed)
+	return nil
+}
+
+func TestRunOnceReportsDrift(t *testing.T) {
+	ctx := context.Background()
+	src := &staticSource{endpoints: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("drift.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+	}}
+	p := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	recorder := &driftRecorder{}
+	ctrl := &Controller{
+		Source:             src,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		DriftRecorder:      recorder,
+	}
+	before := testutil.ToFloat64(driftedRecordsTotal.WithLabelValues(endpoint.RecordTypeA))
+
+	require.NoError(t, ctrl.RunOnce(ctx))
+	require.Len(t, p.ApplyChangesCalls, 1)
+
+	p.RecordsStore = []*endpoint.Endpoint{endpoint.NewEndpoint("drift.used.tld", endpoint.RecordTypeA, "6.6.6.6")}
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Equal(t, before+1, testutil.ToFloat64(driftedRecordsTotal.WithLabelValues(endpoint.RecordTypeA)))
+	require.Len(t, recorder.drifted, 1)
+	assert.Equal(t, endpoint.Targets{"6.6.6.6"}, recorder.drifted[0].Targets)
+
+	// the drift is corrected
+	require.Len(t, p.ApplyChangesCalls, 2)
+	require.Len(t, p.ApplyChangesCalls[1].UpdateNew, 1)
+	assert.Equal(t, endpoint.Targets{"1.1.1.1"}, p.ApplyChangesCalls[1].UpdateNew[0].Targets)
+}
+
+func TestRunOnceDryRunDoesNotReportDrift(t *testing.T) {
+	ctx := context.Background()
+	src := &staticSource{endpoints: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("dry.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+	}}
+	p := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	recorder := &driftRecorder{}
+	ctrl := &Controller{
+		Source:             src,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		DriftRecorder:      recorder,
+		DryRun:             true,
+	}
+
+	require.NoError(t, ctrl.RunOnce(ctx))
+	p.RecordsStore = []*endpoint.Endpoint{endpoint.NewEndpoint("dry.used.tld", endpoint.RecordTypeA, "6.6.6.6")}
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Empty(t, recorder.drifted, "changes which were not applied should not be taken as drift")
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// managedRecords returns the records owned by ownerID once the applied changes took effect.
+func managedRecords(records []*endpoint.Endpoint, applied *plan.Changes, ownerID string) []*endpoint.Endpoint {
+	removed := make(map[endpoint.EndpointKey]bool, len(applied.UpdateOld)+len(applied.Delete))
+	for _, ep := range applied.UpdateOld {
+		removed[ep.Key()] = true
+	}
+	for _, ep := range applied.Delete {
+		removed[ep.Key()] = true
+	}
+
+	managed := make([]*endpoint.Endpoint, 0, len(records)+len(applied.Create))
+	for _, r := range records {
+		if (ownerID == "" || r.IsOwnedBy(ownerID)) && !removed[r.Key()] {
+			managed = append(managed, r)
+		}
+	}
+	managed = append(managed, applied.Create...)
+	return append(managed, applied.UpdateNew...)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// fakeExporter records the exported records.
+type fakeExporter struct {
+	exports [][]*endpoint.Endpoint
+}
+
+func (e *fakeExporter) Export(ctx context.Context, records []*endpoint.Endpoint) error {
+	e.exports = append(e.exports, records)
+	return nil
+}
+
+func TestManagedRecords(t *testing.T) {
+	owned := func(ep *endpoint.Endpoint, owner string) *endpoint.Endpoint {
+		ep.Labels = endpoint.Labels{endpoint.OwnerLabelKey: owner}
+		return ep
+	}
+	records := []*endpoint.Endpoint{
+		owned(endpoint.NewEndpoint("kept.used.tld", endpoint.RecordTypeA, "1.1.1.1"), "owner"),
+		owned(endpoint.NewEndpoint("updated.used.tld", endpoint.RecordTypeA, "2.2.2.2"), "owner"),
+		owned(endpoint.NewEndpoint("deleted.used.tld", endpoint.RecordTypeA, "3.3.3.3"), "owner"),
+		owned(endpoint.NewEndpoint("other.used.tld", endpoint.RecordTypeA, "4.4.4.4"), "other"),
+	}
+	changes := &plan.Changes{
+		Create:    []*endpoint.Endpoint{endpoint.NewEndpoint("new.used.tld", endpoint.RecordTypeA, "5.5.5.5")},
+		UpdateOld: []*endpoint.Endpoint{records[1]},
+		UpdateNew: []*endpoint.Endpoint{owned(endpoint.NewEndpoint("updated.used.tld", endpoint.RecordTypeA, "6.6.6.6"), "owner")},
+		Delete:    []*endpoint.Endpoint{records[2]},
+	}
+
+	managed := managedRecords(records, changes, "owner")
+	require.Len(t, managed, 3)
+	assert.Equal(t, records[0], managed[0])
+	assert.Equal(t, changes.Create[0], managed[1])
+	assert.Equal(t, changes.UpdateNew[0], managed[2])
+
+	assert.Len(t, managedRecords(records, &plan.Changes{}, ""), 4)
+}
+
+func TestRunOnceExportsManagedRecords(t *testing.T) {
+	p := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	exporter := &fakeExporter{}
+	ctrl := &Controller{
+		Source: &staticSource{endpoints: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		}},
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		StateExporter:      exporter,
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, exporter.exports, 1)
+	require.Len(t, exporter.exports[0], 1)
+	assert.Equal(t, "a.used.tld", exporter.exports[0][0].DNSName)
+}
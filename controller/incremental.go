@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// desiredState fingerprints the desired endpoints of every DNS name, so that
+// the names whose desired endpoints changed between two synchronizations can
+// be determined.
+type desiredState map[string]string
+
+func dnsNameKey(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func newDesiredState(endpoints []*endpoint.Endpoint) desiredState {
+	byName := map[string][]string{}
+	for _, ep := range endpoints {
+		key := dnsNameKey(ep.DNSName)
+		byName[key] = append(byName[key], ep.String()+" "+ep.Labels.SerializePlain(false))
+	}
+
+	state := make(desiredState, len(byName))
+	for name, fingerprints := range byName {
+		sort.Strings(fingerprints)
+		state[name] = strings.Join(fingerprints, "\n")
+	}
+	return state
+}
+
+// changedNames returns the DNS names whose desired endpoints differ from the previous state,
+// including the names which are not desired anymore.
+func (s desiredState) changedNames(previous desiredState) map[string]bool {
+	changed := map[string]bool{}
+	for name, fingerprint := range s {
+		if previous[name] != fingerprint {
+			changed[name] = true
+		}
+	}
+	for name := range previous {
+		if _, ok := s[name]; !ok {
+			changed[name] = true
+		}
+	}
+	return changed
+}
+
+// filterByNames returns the endpoints with one of the given DNS names.
+func filterByNames(endpoints []*endpoint.Endpoint, names map[string]bool) []*endpoint.Endpoint {
+	filtered := make([]*endpoint.Endpoint, 0, len(names))
+	for _, ep := range endpoints {
+		if names[dnsNameKey(ep.DNSName)] {
+			filtered = append(filtered, ep)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// staticSource returns the endpoints it holds.
+type staticSource struct {
+	endpoints []*endpoint.Endpoint
+}
+
+func (s *staticSource) Endpoints(context.Context) ([]*endpoint.Endpoint, error) {
+	result := make([]*endpoint.Endpoint, 0, len(s.endpoints))
+	for _, ep := range s.endpoints {
+		result = append(result, ep.DeepCopy())
+	}
+	return result, nil
+}
+
+func (s *staticSource) AddEventHandler(context.Context, func()) {}
+
+func TestDesiredStateChangedNames(t *testing.T) {
+	previous := newDesiredState([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+		endpoint.NewEndpoint("c.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+	})
+	state := newDesiredState([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "2.2.2.3"),
+		endpoint.NewEndpoint("d.example.org", endpoint.RecordTypeA, "4.4.4.4"),
+	})
+
+	assert.Equal(t, map[string]bool{
+		"b.example.org": true,
+		"c.example.org": true,
+		"d.example.org": true,
+	}, state.changedNames(previous))
+}
+
+func TestRunOnceIncremental(t *testing.T) {
+	ctx := context.Background()
+	src := &staticSource{endpoints: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("b.used.tld", endpoint.RecordTypeA, "2.2.2.2"),
+	}}
+	p := &filteredMockProvider{
+		RecordsStore: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+			endpoint.NewEndpoint("b.used.tld", endpoint.RecordTypeA, "2.2.2.2"),
+		},
+	}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:                src,
+		Registry:              r,
+		Policy:                &plan.SyncPolicy{},
+		ManagedRecordTypes:    []string{endpoint.RecordTypeA},
+		FullReconcileInterval: time.Hour,
+	}
+
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Empty(t, p.ApplyChangesCalls)
+
+	// Changes made outside are not noticed by incremental synchronizations.
+	p.RecordsStore[1] = endpoint.NewEndpoint("b.used.tld", endpoint.RecordTypeA, "9.9.9.9")
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Empty(t, p.ApplyChangesCalls)
+
+	// Only the DNS names whose desired endpoints changed are planned.
+	src.endpoints[0] = endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.2")
+	require.NoError(t, ctrl.RunOnce(ctx))
+	require.Len(t, p.ApplyChangesCalls, 1)
+	require.Len(t, p.ApplyChangesCalls[0].UpdateNew, 1)
+	assert.Equal(t, "a.used.tld", p.ApplyChangesCalls[0].UpdateNew[0].DNSName)
+	p.RecordsStore[0] = endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.2")
+
+	// The periodic full reconciliation plans all DNS names.
+	ctrl.lastFullReconcile = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, ctrl.RunOnce(ctx))
+	require.Len(t, p.ApplyChangesCalls, 2)
+	require.Len(t, p.ApplyChangesCalls[1].UpdateNew, 1)
+	assert.Equal(t, "b.used.tld", p.ApplyChangesCalls[1].UpdateNew[0].DNSName)
+	assert.WithinDuration(t, time.Now(), ctrl.lastFullReconcile, time.Minute)
+}
+
+func TestRunOnceIncrementalPlansTombstones(t *testing.T) {
+	ctx := context.Background()
+	src := &staticSource{endpoints: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+	}}
+	tombstoned := endpoint.NewEndpoint("b.used.tld", endpoint.RecordTypeA, "2.2.2.2")
+	tombstoned.Labels = endpoint.Labels{
+		endpoint.OwnerLabelKey:     "pwner",
+		endpoint.TombstoneLabelKey: "0",
+	}
+	p := &filteredMockProvider{
+		RecordsStore: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		},
+	}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:                src,
+		Registry:              r,
+		Policy:                &plan.SyncPolicy{},
+		ManagedRecordTypes:    []string{endpoint.RecordTypeA},
+		FullReconcileInterval: time.Hour,
+		DeletionGracePeriod:   time.Minute,
+	}
+
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Empty(t, p.ApplyChangesCalls)
+
+	// The expired tombstone is deleted although the desired endpoints did not change.
+	p.RecordsStore = append(p.RecordsStore, tombstoned)
+	require.NoError(t, ctrl.RunOnce(ctx))
+	require.Len(t, p.ApplyChangesCalls, 1)
+	require.Len(t, p.ApplyChangesCalls[0].Delete, 1)
+	assert.Equal(t, "b.used.tld", p.ApplyChangesCalls[0].Delete[0].DNSName)
+}
+
+func TestRunOnceIncrementalWildcardPolicy(t *testing.T) {
+	ctx := context.Background()
+	src := &staticSource{endpoints: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("*.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+	}}
+	p := &filteredMockProvider{
+		RecordsStore: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("*.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		},
+	}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:                src,
+		Registry:              r,
+		Policy:                &plan.SyncPolicy{},
+		ManagedRecordTypes:    []string{endpoint.RecordTypeA},
+		FullReconcileInterval: time.Hour,
+		WildcardPolicy:        plan.WildcardPolicyBlock,
+	}
+
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Empty(t, p.ApplyChangesCalls)
+
+	// The wildcard record, whose desired endpoints did not change, is not planned but still blocks the record.
+	src.endpoints = append(src.endpoints, endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "2.2.2.2"))
+	require.NoError(t, ctrl.RunOnce(ctx))
+	assert.Empty(t, p.ApplyChangesCalls)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/notify"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// fakeNotifier records the notifications.
+type fakeNotifier struct {
+	notifications []notify.Notification
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, notification notify.Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+// failingProvider fails to apply changes.
+type failingProvider struct {
+	filteredMockProvider
+}
+
+func (p *failingProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	return errors.New("throttled")
+}
+
+func newNotifyingController(t *testing.T, p provider.Provider, notifier *fakeNotifier) *Controller {
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	return &Controller{
+		Source: &staticSource{endpoints: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		}},
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		Notifier:           notifier,
+	}
+}
+
+func TestRunOnceNotifiesChanges(t *testing.T) {
+	notifier := &fakeNotifier{}
+	require.NoError(t, newNotifyingController(t, &filteredMockProvider{}, notifier).RunOnce(context.Background()))
+	require.Len(t, notifier.notifications, 1)
+	assert.Equal(t, 1, notifier.notifications[0].Creates)
+	assert.False(t, notifier.notifications[0].Failed())
+
+	notifier = &fakeNotifier{}
+	assert.Error(t, newNotifyingController(t, &failingProvider{}, notifier).RunOnce(context.Background()))
+	require.Len(t, notifier.notifications, 1)
+	assert.Equal(t, "throttled", notifier.notifications[0].Error)
+}
+
+func TestRunOnceDryRunDoesNotNotify(t *testing.T) {
+	notifier := &fakeNotifier{}
+	ctrl := newNotifyingController(t, &filteredMockProvider{}, notifier)
+	ctrl.DryRun = true
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Empty(t, notifier.notifications, "changes which were not applied should not be notified")
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// savePendingChanges persists changes which could not be applied before shutting down.
+func savePendingChanges(path string, changes *plan.Changes) error {
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// loadPendingChanges reads the changes persisted by a previous run, if any.
+func loadPendingChanges(path string) (*plan.Changes, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	changes := &plan.Changes{}
+	if err := json.Unmarshal(data, changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// remainingChanges drops the pending changes which are already reflected by the current records,
+// because they were applied before the previous run was interrupted.
+func remainingChanges(pending *plan.Changes, records []*endpoint.Endpoint, normalizations []plan.TargetNormalization, minTTL endpoint.TTL) *plan.Changes {
+	if len(normalizations) == 0 {
+		normalizations = plan.DefaultTargetNormalizations
+	}
+	current := make(map[endpoint.EndpointKey]*endpoint.Endpoint, len(records))
+	for _, r := range records {
+		current[driftKey(r)] = r
+	}
+
+	remaining := &plan.Changes{}
+	for _, e := range pending.Create {
+		if _, ok := current[driftKey(e)]; !ok {
+			remaining.Create = append(remaining.Create, e)
+		}
+	}
+	for _, e := range pending.UpdateNew {
+		cur, ok := current[driftKey(e)]
+		switch {
+		case !ok:
+			remaining.Create = append(remaining.Create, e)
+		case hasDrifted(e, cur, normalizations, minTTL):
+			remaining.UpdateOld = append(remaining.UpdateOld, cur)
+			remaining.UpdateNew = append(remaining.UpdateNew, e)
+		}
+	}
+	for _, e := range pending.Delete {
+		if cur, ok := current[driftKey(e)]; ok {
+			remaining.Delete = append(remaining.Delete, cur)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// blockingProvider blocks ApplyChanges until released or until its context is canceled.
+type blockingProvider struct {
+	filteredMockProvider
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	close(p.started)
+	select {
+	case <-p.release:
+		return p.filteredMockProvider.ApplyChanges(ctx, changes)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newBlockingController(t *testing.T, drainTimeout time.Duration) (*Controller, *blockingProvider) {
+	p := &blockingProvider{started: make(chan struct{}), release: make(chan struct{})}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	return &Controller{
+		Source: &staticSource{endpoints: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		}},
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		Interval:           time.Hour,
+		DrainTimeout:       drainTimeout,
+		PendingChangesFile: filepath.Join(t.TempDir(), "pending.json"),
+	}, p
+}
+
+func TestRunFinishesInFlightChanges(t *testing.T) {
+	ctrl, p := newBlockingController(t, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ctrl.Run(ctx)
+		close(done)
+	}()
+
+	<-p.started
+	cancel()
+	close(p.release)
+	<-done
+
+	require.Len(t, p.ApplyChangesCalls, 1)
+	assert.Len(t, p.ApplyChangesCalls[0].Create, 1)
+	assert.NoFileExists(t, ctrl.PendingChangesFile)
+}
+
+func TestRunPersistsChangesAbortedByDrainTimeout(t *testing.T) {
+	ctrl, p := newBlockingController(t, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ctrl.Run(ctx)
+		close(done)
+	}()
+
+	<-p.started
+	cancel()
+	<-done
+
+	assert.Empty(t, p.ApplyChangesCalls)
+	pending, err := loadPendingChanges(ctrl.PendingChangesFile)
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+	require.Len(t, pending.Create, 1)
+	assert.Equal(t, "a.used.tld", pending.Create[0].DNSName)
+}
+
+func TestRunOnceResumesPendingChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending.json")
+	require.NoError(t, savePendingChanges(path, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+			endpoint.NewEndpoint("b.used.tld", endpoint.RecordTypeA, "2.2.2.2"),
+		},
+	}))
+	p := &filteredMockProvider{
+		RecordsStore: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		},
+	}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	ctrl := &Controller{
+		Source:             &staticSource{},
+		Registry:           r,
+		Policy:             &plan.UpsertOnlyPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		PendingChangesFile: path,
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, p.ApplyChangesCalls, 1)
+	require.Len(t, p.ApplyChangesCalls[0].Create, 1)
+	assert.Equal(t, "b.used.tld", p.ApplyChangesCalls[0].Create[0].DNSName)
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// the pending changes are only resumed once
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Len(t, p.ApplyChangesCalls, 1)
+}
+
+func TestRemainingChanges(t *testing.T) {
+	records := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("created.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("updated.used.tld", endpoint.RecordTypeA, "2.2.2.3"),
+		endpoint.NewEndpoint("outdated.used.tld", endpoint.RecordTypeA, "3.3.3.3"),
+		endpoint.NewEndpoint("undeleted.used.tld", endpoint.RecordTypeA, "4.4.4.4"),
+	}
+	pending := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("created.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+			endpoint.NewEndpoint("uncreated.used.tld", endpoint.RecordTypeA, "5.5.5.5"),
+		},
+		UpdateOld: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("updated.used.tld", endpoint.RecordTypeA, "2.2.2.2"),
+			endpoint.NewEndpoint("outdated.used.tld", endpoint.RecordTypeA, "3.3.3.3"),
+		},
+		UpdateNew: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("updated.used.tld", endpoint.RecordTypeA, "2.2.2.3"),
+			endpoint.NewEndpoint("outdated.used.tld", endpoint.RecordTypeA, "3.3.3.4"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("deleted.used.tld", endpoint.RecordTypeA, "6.6.6.6"),
+			endpoint.NewEndpoint("undeleted.used.tld", endpoint.RecordTypeA, "4.4.4.4"),
+		},
+	}
+
+	remaining := remainingChanges(pending, records, nil, 0)
+
+	assert.Equal(t, []*endpoint.Endpoint{pending.Create[1]}, remaining.Create)
+	assert.Equal(t, []*endpoint.Endpoint{records[2]}, remaining.UpdateOld)
+	assert.Equal(t, []*endpoint.Endpoint{pending.UpdateNew[1]}, remaining.UpdateNew)
+	assert.Equal(t, []*endpoint.Endpoint{records[3]}, remaining.Delete)
+}
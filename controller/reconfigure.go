@@ -0,0 +1,35 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/source"
+)
+
+// Reconfigure replaces the source, the domain filter and the sync reporters, e.g. once the configuration was
+// reloaded, while keeping the registry and the provider along with their state. It waits for the synchronization
+// in flight to finish, and the next synchronization plans all the DNS names since the desired endpoints may change
+// for any of them.
+func (c *Controller) Reconfigure(src source.Source, domainFilter endpoint.DomainFilter, syncReporters []source.SyncReporter) {
+	c.runMux.Lock()
+	defer c.runMux.Unlock()
+	c.Source = src
+	c.DomainFilter = domainFilter
+	c.SyncReporters = syncReporters
+	c.lastDesired = nil
+}
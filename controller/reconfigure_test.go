@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/source"
+)
+
+func TestReconfigure(t *testing.T) {
+	p := &filteredMockProvider{}
+	ctrl := newNotifyingController(t, p, &fakeNotifier{})
+	ctrl.FullReconcileInterval = time.Hour
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.NotNil(t, ctrl.lastDesired)
+
+	reporter := &fakeSyncReporter{}
+	ctrl.Reconfigure(&staticSource{endpoints: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("b.other.tld", endpoint.RecordTypeA, "2.2.2.2"),
+		endpoint.NewEndpoint("c.excluded.tld", endpoint.RecordTypeA, "3.3.3.3"),
+	}}, endpoint.NewDomainFilter([]string{"other.tld"}), []source.SyncReporter{reporter})
+	assert.Nil(t, ctrl.lastDesired)
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, p.ApplyChangesCalls, 2)
+	require.Len(t, p.ApplyChangesCalls[1].Create, 1)
+	assert.Equal(t, "b.other.tld", p.ApplyChangesCalls[1].Create[0].DNSName)
+	assert.Len(t, reporter.reports, 1)
+}
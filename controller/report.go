@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/source"
+)
+
+const (
+	heldRecordError       = "the change of the record is held until a change window opens or it is approved"
+	foreignRecordError    = "the record is owned by another owner"
+	unmanagedRecordError  = "the record is not managed, it may be excluded by the domain filter or the managed record types"
+	notAppliedRecordError = "the change of the record could not be applied: "
+)
+
+// reportSync reports the outcome of the synchronization of the desired endpoints to the SyncReporters.
+func (c *Controller) reportSync(ctx context.Context, desired, records []*endpoint.Endpoint, changes, held *plan.Changes, applyErr error) {
+	if len(c.SyncReporters) == 0 {
+		return
+	}
+	report := newSyncReport(desired, records, changes, held, applyErr, c.Registry.OwnerID(), time.Now())
+	for _, r := range c.SyncReporters {
+		r.ReportSync(ctx, report)
+	}
+}
+
+// newSyncReport tells, for every desired endpoint, whether its record is in the DNS provider once the changes
+// were applied, or failed to be applied with applyErr.
+func newSyncReport(desired, records []*endpoint.Endpoint, changes, held *plan.Changes, applyErr error, ownerID string, now time.Time) source.SyncReport {
+	current := make(map[endpoint.EndpointKey]*endpoint.Endpoint, len(records))
+	for _, r := range records {
+		current[r.Key()] = r
+	}
+	changed := changedKeys(changes)
+	pending := changedKeys(held)
+
+	report := source.SyncReport{Time: now, Records: make([]source.RecordSyncResult, 0, len(desired))}
+	for _, ep := range desired {
+		result := source.RecordSyncResult{Endpoint: ep}
+		key := ep.Key()
+		switch r, exists := current[key]; {
+		case changed[key] && applyErr != nil:
+			result.Error = notAppliedRecordError + applyErr.Error()
+		case changed[key]:
+			result.Synced, result.Applied = true, true
+		case pending[key]:
+			result.Error = heldRecordError
+		case exists && ownerID != "" && !r.IsOwnedBy(ownerID):
+			result.Error = foreignRecordError
+		case exists:
+			result.Synced = true
+		default:
+			result.Error = unmanagedRecordError
+		}
+		report.Records = append(report.Records, result)
+	}
+	return report
+}
+
+// changedKeys returns the keys of the records created or updated by the changes.
+func changedKeys(changes *plan.Changes) map[endpoint.EndpointKey]bool {
+	keys := map[endpoint.EndpointKey]bool{}
+	if changes == nil {
+		return keys
+	}
+	for _, ep := range changes.Create {
+		keys[ep.Key()] = true
+	}
+	for _, ep := range changes.UpdateNew {
+		keys[ep.Key()] = true
+	}
+	return keys
+}
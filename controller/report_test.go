@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/source"
+)
+
+// fakeSyncReporter records the sync reports.
+type fakeSyncReporter struct {
+	reports []source.SyncReport
+}
+
+func (r *fakeSyncReporter) ReportSync(ctx context.Context, report source.SyncReport) {
+	r.reports = append(r.reports, report)
+}
+
+func TestNewSyncReport(t *testing.T) {
+	owned := endpoint.NewEndpoint("owned.example.org", endpoint.RecordTypeA, "1.1.1.1")
+	owned.Labels[endpoint.OwnerLabelKey] = "owner"
+	foreign := endpoint.NewEndpoint("foreign.example.org", endpoint.RecordTypeA, "1.1.1.1")
+	foreign.Labels[endpoint.OwnerLabelKey] = "other"
+	created := endpoint.NewEndpoint("created.example.org", endpoint.RecordTypeA, "1.1.1.1")
+	held := endpoint.NewEndpoint("held.example.org", endpoint.RecordTypeA, "1.1.1.1")
+	unmanaged := endpoint.NewEndpoint("unmanaged.example.org", endpoint.RecordTypeA, "1.1.1.1")
+	desired := []*endpoint.Endpoint{owned, foreign, created, held, unmanaged}
+	records := []*endpoint.Endpoint{owned, foreign}
+	now := time.Now()
+
+	report := newSyncReport(desired, records, &plan.Changes{Create: []*endpoint.Endpoint{created}}, &plan.Changes{Create: []*endpoint.Endpoint{held}}, nil, "owner", now)
+	assert.Equal(t, now, report.Time)
+	assert.Equal(t, []source.RecordSyncResult{
+		{Endpoint: owned, Synced: true},
+		{Endpoint: foreign, Error: foreignRecordError},
+		{Endpoint: created, Synced: true, Applied: true},
+		{Endpoint: held, Error: heldRecordError},
+		{Endpoint: unmanaged, Error: unmanagedRecordError},
+	}, report.Records)
+
+	report = newSyncReport(desired, records, &plan.Changes{Create: []*endpoint.Endpoint{created}}, &plan.Changes{}, errors.New("throttled"), "owner", now)
+	assert.Equal(t, source.RecordSyncResult{Endpoint: created, Error: notAppliedRecordError + "throttled"}, report.Records[2])
+}
+
+func TestRunOnceReportsSync(t *testing.T) {
+	reporter := &fakeSyncReporter{}
+	ctrl := newNotifyingController(t, &filteredMockProvider{}, &fakeNotifier{})
+	ctrl.SyncReporters = []source.SyncReporter{reporter}
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, reporter.reports, 1)
+	require.Len(t, reporter.reports[0].Records, 1)
+	assert.True(t, reporter.reports[0].Records[0].Synced)
+	assert.True(t, reporter.reports[0].Records[0].Applied)
+
+	reporter = &fakeSyncReporter{}
+	ctrl = newNotifyingController(t, &failingProvider{}, &fakeNotifier{})
+	ctrl.SyncReporters = []source.SyncReporter{reporter}
+	assert.Error(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, reporter.reports, 1)
+	require.Len(t, reporter.reports[0].Records, 1)
+	assert.False(t, reporter.reports[0].Records[0].Synced)
+	assert.Equal(t, notAppliedRecordError+"throttled", reporter.reports[0].Records[0].Error)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// Status describes the outcome of the last synchronizations of a Controller.
+type Status struct {
+	// LastAttempt is the start time of the last synchronization
+	LastAttempt time.Time `json:"lastAttempt,omitempty"`
+	// LastSuccess is the start time of the last successful synchronization
+	LastSuccess time.Time `json:"lastSuccess,omitempty"`
+	// LastError is the error of the last synchronization, if it failed
+	LastError string `json:"lastError,omitempty"`
+	// Endpoints are the desired endpoints of the last synchronization
+	Endpoints []*endpoint.Endpoint `json:"endpoints"`
+	// PendingChanges is the number of changes held until the next change window
+	PendingChanges int `json:"pendingChanges,omitempty"`
+	// NextChangeWindow is when the next change window opens, if changes are pending
+	NextChangeWindow *time.Time `json:"nextChangeWindow,omitempty"`
+	// Degraded is why the applied records are not in effect, e.g. because they do not resolve
+	Degraded string `json:"degraded,omitempty"`
+}
+
+// syncStatus keeps track of the Status of a Controller.
+type syncStatus struct {
+	mutex  sync.Mutex
+	status Status
+	// records are the registry records read by the last synchronization
+	records []*endpoint.Endpoint
+}
+
+func (s *syncStatus) setEndpoints(endpoints []*endpoint.Endpoint) {
+	copied := copyEndpoints(endpoints)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.status.Endpoints = copied
+}
+
+func (s *syncStatus) setRecords(records []*endpoint.Endpoint) {
+	copied := copyEndpoints(records)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.records = copied
+}
+
+func (s *syncStatus) getRecords() []*endpoint.Endpoint {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.records
+}
+
+func copyEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	copied := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		copied = append(copied, ep.DeepCopy())
+	}
+	return copied
+}
+
+func (s *syncStatus) setPending(pending int, next time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.status.PendingChanges = pending
+	s.status.NextChangeWindow = nil
+	if pending > 0 && !next.IsZero() {
+		s.status.NextChangeWindow = &next
+	}
+}
+
+func (s *syncStatus) setDegraded(reason string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.status.Degraded = reason
+}
+
+func (s *syncStatus) finish(attempt time.Time, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.status.LastAttempt = attempt
+	if err != nil {
+		s.status.LastError = err.Error()
+		return
+	}
+	s.status.LastSuccess = attempt
+	s.status.LastError = ""
+}
+
+func (s *syncStatus) get() Status {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.status
+}
+
+// SyncAgeCheck returns a readiness check failing when the last successful synchronization started
+// more than maxAge ago, or when none succeeded within maxAge after since, e.g. the start of ExternalDNS.
+func (c *Controller) SyncAgeCheck(maxAge time.Duration, since time.Time) func() error {
+	return func() error {
+		status := c.status.get()
+		if status.LastSuccess.IsZero() {
+			if age := time.Since(since); age > maxAge {
+				return fmt.Errorf("no successful synchronization in %s, last error: %s", age.Round(time.Second), status.LastError)
+			}
+			return nil
+		}
+		if age := time.Since(status.LastSuccess); age > maxAge {
+			return fmt.Errorf("last successful synchronization %s ago, last error: %s", age.Round(time.Second), status.LastError)
+		}
+		return nil
+	}
+}
+
+// SyncedRecords returns the records read from the registry by the last synchronization,
+// together with their labels, without calling the provider. They are only kept when
+// DebugRecordsEndpoint is set.
+func (c *Controller) SyncedRecords() []*endpoint.Endpoint {
+	return c.status.getRecords()
+}
+
+// NewStatusHandler returns an http.Handler reporting the Status of the controller as JSON, which
+// only includes the desired endpoints when DebugStatusEndpoint is set.
+// The optional "name" and "resource" query parameters restrict the endpoints returned to the
+// ones with the given DNS name or originating from the given resource, e.g. ingress/default/foo.
+func NewStatusHandler(c *Controller) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		status := c.status.get()
+		query := req.URL.Query()
+		name := strings.TrimSuffix(query.Get("name"), ".")
+		resource := query.Get("resource")
+		endpoints := make([]*endpoint.Endpoint, 0, len(status.Endpoints))
+		for _, ep := range status.Endpoints {
+			if name != "" && !strings.EqualFold(strings.TrimSuffix(ep.DNSName, "."), name) {
+				continue
+			}
+			if resource != "" && ep.Labels[endpoint.ResourceLabelKey] != resource {
+				continue
+			}
+			endpoints = append(endpoints, ep)
+		}
+		status.Endpoints = endpoints
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			log.Errorf("Failed to encode status for the debug endpoint: %v", err)
+		}
+	})
+}
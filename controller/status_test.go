@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+func TestStatusHandler(t *testing.T) {
+	foo := endpoint.NewEndpoint("foo.used.tld", endpoint.RecordTypeA, "1.1.1.1")
+	foo.Labels[endpoint.ResourceLabelKey] = "ingress/default/foo"
+	bar := endpoint.NewEndpoint("bar.used.tld", endpoint.RecordTypeA, "2.2.2.2")
+	bar.Labels[endpoint.ResourceLabelKey] = "service/default/bar"
+
+	p := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	ctrl := &Controller{
+		Source:              &staticSource{endpoints: []*endpoint.Endpoint{foo, bar}},
+		Registry:            r,
+		Policy:              &plan.SyncPolicy{},
+		ManagedRecordTypes:  []string{endpoint.RecordTypeA},
+		DebugStatusEndpoint: true,
+	}
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+
+	handler := NewStatusHandler(ctrl)
+	get := func(target string) Status {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var status Status
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+		return status
+	}
+
+	status := get("/debug/status")
+	assert.False(t, status.LastSuccess.IsZero())
+	assert.Equal(t, status.LastAttempt, status.LastSuccess)
+	assert.Empty(t, status.LastError)
+	assert.Len(t, status.Endpoints, 2)
+
+	status = get("/debug/status?name=FOO.used.tld.")
+	require.Len(t, status.Endpoints, 1)
+	assert.Equal(t, "foo.used.tld", status.Endpoints[0].DNSName)
+
+	status = get("/debug/status?resource=service/default/bar")
+	require.Len(t, status.Endpoints, 1)
+	assert.Equal(t, "bar.used.tld", status.Endpoints[0].DNSName)
+
+	ctrl.status.finish(status.LastSuccess.Add(1), errors.New("provider unavailable"))
+	status = get("/debug/status")
+	assert.Equal(t, "provider unavailable", status.LastError)
+	assert.True(t, status.LastAttempt.After(status.LastSuccess))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/status", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestSyncedRecords(t *testing.T) {
+	foo := endpoint.NewEndpoint("foo.used.tld", endpoint.RecordTypeA, "1.1.1.1")
+
+	p := &filteredMockProvider{RecordsStore: []*endpoint.Endpoint{foo}}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	ctrl := &Controller{
+		Source:               &staticSource{endpoints: []*endpoint.Endpoint{foo}},
+		Registry:             r,
+		Policy:               &plan.SyncPolicy{},
+		ManagedRecordTypes:   []string{endpoint.RecordTypeA},
+		DebugRecordsEndpoint: true,
+	}
+	assert.Empty(t, ctrl.SyncedRecords())
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	calls := p.RecordsCallCount
+	records := ctrl.SyncedRecords()
+	require.Len(t, records, 1)
+	assert.Equal(t, "foo.used.tld", records[0].DNSName)
+	assert.Equal(t, calls, p.RecordsCallCount)
+}
+
+func TestStatusWithoutDebugEndpoints(t *testing.T) {
+	foo := endpoint.NewEndpoint("foo.used.tld", endpoint.RecordTypeA, "1.1.1.1")
+
+	p := &filteredMockProvider{RecordsStore: []*endpoint.Endpoint{foo}}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	ctrl := &Controller{
+		Source:             &staticSource{endpoints: []*endpoint.Endpoint{foo}},
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+	}
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+
+	status := ctrl.status.get()
+	assert.False(t, status.LastSuccess.IsZero())
+	assert.Empty(t, status.Endpoints, "the endpoints should only be kept for the debug status endpoint")
+	assert.Empty(t, ctrl.SyncedRecords(), "the records should only be kept for the debug records endpoint")
+}
+
+func TestSyncAgeCheck(t *testing.T) {
+	ctrl := &Controller{}
+	start := time.Now()
+
+	// within the grace period after the start
+	assert.NoError(t, ctrl.SyncAgeCheck(time.Minute, start)())
+	ctrl.status.finish(start, errors.New("provider unavailable"))
+	assert.NoError(t, ctrl.SyncAgeCheck(time.Minute, start)())
+	assert.ErrorContains(t, ctrl.SyncAgeCheck(time.Minute, start.Add(-2*time.Minute))(), "no successful synchronization in 2m0s, last error: provider unavailable")
+
+	ctrl.status.finish(start.Add(-5*time.Minute), nil)
+	ctrl.status.finish(start, errors.New("provider unavailable"))
+	assert.ErrorContains(t, ctrl.SyncAgeCheck(time.Minute, start)(), "last successful synchronization 5m0s ago, last error: provider unavailable")
+
+	ctrl.status.finish(start, nil)
+	assert.NoError(t, ctrl.SyncAgeCheck(time.Minute, start.Add(-2*time.Minute))())
+}
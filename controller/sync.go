@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TriggerRunNow schedules a synchronization as soon as possible, ignoring MinEventSyncInterval.
+func (c *Controller) TriggerRunNow(now time.Time) {
+	c.nextRunAtMux.Lock()
+	defer c.nextRunAtMux.Unlock()
+	if c.nextRunAt.After(now) {
+		c.nextRunAt = now
+	}
+}
+
+// SyncHandler returns a handler triggering a synchronization on POST requests, e.g. from
+// CI pipelines which just created resources and do not want to wait for the interval.
+func (c *Controller) SyncHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		log.Infof("Synchronization triggered by %s", r.RemoteAddr)
+		c.TriggerRunNow(time.Now())
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("synchronization triggered\n"))
+	})
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncHandler(t *testing.T) {
+	ctrl := &Controller{Interval: time.Hour, MinEventSyncInterval: time.Minute}
+	now := time.Now()
+	assert.True(t, ctrl.ShouldRunOnce(now))
+	assert.False(t, ctrl.ShouldRunOnce(now.Add(time.Second)))
+
+	rec := httptest.NewRecorder()
+	ctrl.SyncHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sync", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.False(t, ctrl.ShouldRunOnce(time.Now()))
+
+	rec = httptest.NewRecorder()
+	ctrl.SyncHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/sync", nil))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	// the synchronization is not delayed by the minimum event sync interval
+	assert.True(t, ctrl.ShouldRunOnce(time.Now()))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/verify"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+var (
+	propagationLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "propagation_latency_seconds",
+			Help:      "Time from applying a record until it resolved on all verification resolvers.",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12),
+		},
+		[]string{"record_type"},
+	)
+	unverifiedRecords = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "unverified_records",
+			Help:      "Number of applied records which did not resolve on all verification resolvers yet.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(propagationLatency)
+	prometheus.MustRegister(unverifiedRecords)
+}
+
+// unverifiedRecord is an applied record which did not resolve yet.
+type unverifiedRecord struct {
+	endpoint *endpoint.Endpoint
+	applied  time.Time
+}
+
+// verifyChanges verifies that the records created or updated by changes, applied at the given time, resolve,
+// along with the records of previous synchronizations which did not resolve yet. The synchronization is
+// reported as degraded as long as some records don't resolve.
+func (c *Controller) verifyChanges(ctx context.Context, changes *plan.Changes, applied time.Time) {
+	// in dry-run mode, the changes were not applied and are not expected to resolve
+	if c.Verifier == nil || c.DryRun {
+		return
+	}
+	if c.unverified == nil {
+		c.unverified = map[endpoint.EndpointKey]unverifiedRecord{}
+	}
+	// records replaced or deleted since they were applied don't have to resolve anymore
+	for _, e := range changes.UpdateOld {
+		delete(c.unverified, driftKey(e))
+	}
+	for _, e := range changes.Delete {
+		delete(c.unverified, driftKey(e))
+	}
+	for _, changed := range [][]*endpoint.Endpoint{changes.Create, changes.UpdateNew} {
+		for _, e := range changed {
+			if verify.Supported(e) {
+				c.unverified[driftKey(e)] = unverifiedRecord{endpoint: e.DeepCopy(), applied: applied}
+			}
+		}
+	}
+	if len(c.unverified) == 0 {
+		c.status.setDegraded("")
+		unverifiedRecords.Set(0)
+		return
+	}
+
+	records := make([]*endpoint.Endpoint, 0, len(c.unverified))
+	for _, r := range c.unverified {
+		records = append(records, r.endpoint)
+	}
+	var lastErr error
+	for _, result := range c.Verifier.Verify(ctx, records) {
+		key := driftKey(result.Endpoint)
+		if result.Err != nil {
+			lastErr = result.Err
+			log.Warnf("Record %s %s does not resolve: %v", result.Endpoint.RecordType, result.Endpoint.DNSName, result.Err)
+			continue
+		}
+		latency := result.Resolved.Sub(c.unverified[key].applied)
+		propagationLatency.WithLabelValues(result.Endpoint.RecordType).Observe(latency.Seconds())
+		log.Debugf("Record %s %s resolved %s after it was applied", result.Endpoint.RecordType, result.Endpoint.DNSName, latency.Round(time.Millisecond))
+		delete(c.unverified, key)
+	}
+
+	unverifiedRecords.Set(float64(len(c.unverified)))
+	if len(c.unverified) == 0 {
+		c.status.setDegraded("")
+		return
+	}
+	c.status.setDegraded(fmt.Sprintf("%d applied records do not resolve, last error: %v", len(c.unverified), lastErr))
+}
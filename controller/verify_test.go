@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/verify"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// fakeVerifier resolves the records whose DNS names it knows.
+type fakeVerifier struct {
+	resolved map[string]bool
+	verified []string
+}
+
+func (v *fakeVerifier) Verify(ctx context.Context, records []*endpoint.Endpoint) []verify.Result {
+	results := make([]verify.Result, 0, len(records))
+	for _, ep := range records {
+		v.verified = append(v.verified, ep.DNSName)
+		if v.resolved[ep.DNSName] {
+			results = append(results, verify.Result{Endpoint: ep, Resolved: time.Now()})
+			continue
+		}
+		results = append(results, verify.Result{Endpoint: ep, Err: errors.New("NXDOMAIN")})
+	}
+	return results
+}
+
+func TestVerifyChanges(t *testing.T) {
+	ctx := context.Background()
+	verifier := &fakeVerifier{resolved: map[string]bool{"a.used.tld": true}}
+	ctrl := &Controller{Verifier: verifier}
+
+	ctrl.verifyChanges(ctx, &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("b.used.tld", endpoint.RecordTypeA, "2.2.2.2"),
+		endpoint.NewEndpoint("c.used.tld", endpoint.RecordTypeA, "3.3.3.3").WithSetIdentifier("eu"),
+	}}, time.Now())
+	assert.ElementsMatch(t, []string{"a.used.tld", "b.used.tld"}, verifier.verified)
+	assert.Contains(t, ctrl.status.get().Degraded, "1 applied records do not resolve")
+
+	// unresolved records are verified again by the next synchronizations
+	verifier.verified = nil
+	ctrl.verifyChanges(ctx, &plan.Changes{}, time.Now())
+	assert.Equal(t, []string{"b.used.tld"}, verifier.verified)
+	assert.NotEmpty(t, ctrl.status.get().Degraded)
+
+	verifier.resolved["b.used.tld"] = true
+	ctrl.verifyChanges(ctx, &plan.Changes{}, time.Now())
+	assert.Empty(t, ctrl.status.get().Degraded)
+	assert.Empty(t, ctrl.unverified)
+
+	// deleted records don't have to resolve anymore
+	ctrl.verifyChanges(ctx, &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("d.used.tld", endpoint.RecordTypeA, "4.4.4.4"),
+	}}, time.Now())
+	assert.NotEmpty(t, ctrl.status.get().Degraded)
+	verifier.verified = nil
+	ctrl.verifyChanges(ctx, &plan.Changes{Delete: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("d.used.tld", endpoint.RecordTypeA, "4.4.4.4"),
+	}}, time.Now())
+	assert.Empty(t, verifier.verified)
+	assert.Empty(t, ctrl.status.get().Degraded)
+}
+
+func TestRunOnceVerifiesChanges(t *testing.T) {
+	r, err := registry.NewNoopRegistry(&filteredMockProvider{})
+	require.NoError(t, err)
+	verifier := &fakeVerifier{}
+	ctrl := &Controller{
+		Source: &staticSource{endpoints: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		}},
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		Verifier:           verifier,
+	}
+
+	// a record which does not resolve degrades, but doesn't fail, the synchronization
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, []string{"a.used.tld"}, verifier.verified)
+	status := ctrl.status.get()
+	assert.Empty(t, status.LastError)
+	assert.NotEmpty(t, status.Degraded)
+}
+
+func TestRunOnceDryRunDoesNotVerifyChanges(t *testing.T) {
+	r, err := registry.NewNoopRegistry(&filteredMockProvider{})
+	require.NoError(t, err)
+	verifier := &fakeVerifier{}
+	ctrl := &Controller{
+		Source: &staticSource{endpoints: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		}},
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		Verifier:           verifier,
+		DryRun:             true,
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Empty(t, verifier.verified)
+	assert.Empty(t, ctrl.status.get().Degraded)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/plan"
+)
+
+var pendingChanges = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "external_dns",
+		Subsystem: "controller",
+		Name:      "pending_changes",
+		Help:      "Number of changes held until the next change window or until they are approved, by action.",
+	},
+	[]string{"action"},
+)
+
+func init() {
+	prometheus.MustRegister(pendingChanges)
+}
+
+// holdChanges splits the planned changes into the changes to apply now and the changes held until the
+// next change window, which stay pending as the next synchronizations plan them again.
+func (c *Controller) holdChanges(changes *plan.Changes, now time.Time) (apply, held *plan.Changes) {
+	held = &plan.Changes{}
+	switch {
+	case c.ChangeWindows.Open(now):
+		apply = changes
+	case c.ChangeWindowDeletionsOnly:
+		// the creations replacing deleted records, e.g. when their type changes, are held with the deletions
+		deleted := map[string]bool{}
+		for _, ep := range changes.Delete {
+			deleted[dnsNameKey(ep.DNSName)] = true
+		}
+		apply = &plan.Changes{UpdateOld: changes.UpdateOld, UpdateNew: changes.UpdateNew}
+		for _, ep := range changes.Create {
+			if deleted[dnsNameKey(ep.DNSName)] {
+				held.Create = append(held.Create, ep)
+			} else {
+				apply.Create = append(apply.Create, ep)
+			}
+		}
+		held.Delete = changes.Delete
+	default:
+		apply, held = &plan.Changes{}, changes
+	}
+	return apply, held
+}
+
+// reportPendingChanges exposes the changes held until the next change window or until they are approved.
+func (c *Controller) reportPendingChanges(held *plan.Changes, now time.Time) {
+	pendingChanges.WithLabelValues("create").Set(float64(len(held.Create)))
+	pendingChanges.WithLabelValues("update").Set(float64(len(held.UpdateNew)))
+	pendingChanges.WithLabelValues("delete").Set(float64(len(held.Delete)))
+
+	pending := len(held.Create) + len(held.UpdateNew) + len(held.Delete)
+	var next time.Time
+	if pending > 0 {
+		if c.ChangeWindows.Open(now) {
+			log.Infof("Holding %d changes until they are approved", pending)
+		} else {
+			next = c.ChangeWindows.Next(now)
+			log.Infof("Holding %d changes until the next change window at %s", pending, next.Format(time.RFC3339))
+		}
+		for _, ep := range held.Create {
+			log.Infof("Pending create: %s", ep)
+		}
+		for _, ep := range held.UpdateNew {
+			log.Infof("Pending update: %s", ep)
+		}
+		for _, ep := range held.Delete {
+			log.Infof("Pending delete: %s", ep)
+		}
+	}
+	c.status.setPending(pending, next)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/changewindow"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+func newWindowChanges() *plan.Changes {
+	return &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("new.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+			endpoint.NewEndpoint("retyped.used.tld", endpoint.RecordTypeCNAME, "other.used.tld"),
+		},
+		UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpoint("updated.used.tld", endpoint.RecordTypeA, "2.2.2.2")},
+		UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpoint("updated.used.tld", endpoint.RecordTypeA, "3.3.3.3")},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("old.used.tld", endpoint.RecordTypeA, "4.4.4.4"),
+			endpoint.NewEndpoint("retyped.used.tld", endpoint.RecordTypeA, "5.5.5.5"),
+		},
+	}
+}
+
+func TestHoldChanges(t *testing.T) {
+	windows, err := changewindow.ParseWindows([]string{"0 22 * * 6 8h"})
+	require.NoError(t, err)
+	open := time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)
+	closed := time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+
+	ctrl := &Controller{}
+	apply, held := ctrl.holdChanges(newWindowChanges(), closed)
+	assert.True(t, apply.HasChanges())
+	assert.False(t, held.HasChanges())
+
+	ctrl = &Controller{ChangeWindows: windows}
+	apply, held = ctrl.holdChanges(newWindowChanges(), open)
+	assert.Len(t, apply.Create, 2)
+	assert.False(t, held.HasChanges())
+	ctrl.reportPendingChanges(held, open)
+	assert.Zero(t, ctrl.status.get().PendingChanges)
+
+	apply, held = ctrl.holdChanges(newWindowChanges(), closed)
+	assert.False(t, apply.HasChanges())
+	assert.Len(t, held.Create, 2)
+	assert.Len(t, held.UpdateNew, 1)
+	assert.Len(t, held.Delete, 2)
+	ctrl.reportPendingChanges(held, closed)
+	status := ctrl.status.get()
+	assert.Equal(t, 5, status.PendingChanges)
+	require.NotNil(t, status.NextChangeWindow)
+	assert.Equal(t, time.Date(2024, 6, 8, 22, 0, 0, 0, time.UTC), *status.NextChangeWindow)
+
+	ctrl = &Controller{ChangeWindows: windows, ChangeWindowDeletionsOnly: true}
+	apply, held = ctrl.holdChanges(newWindowChanges(), closed)
+	require.Len(t, apply.Create, 1)
+	assert.Equal(t, "new.used.tld", apply.Create[0].DNSName)
+	assert.Len(t, apply.UpdateNew, 1)
+	assert.Empty(t, apply.Delete)
+	require.Len(t, held.Create, 1)
+	assert.Equal(t, "retyped.used.tld", held.Create[0].DNSName)
+	assert.Len(t, held.Delete, 2)
+}
+
+func TestRunOnceHoldsChangesOutsideOfChangeWindows(t *testing.T) {
+	p := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(p)
+	require.NoError(t, err)
+	// a window which is only open for a minute per year
+	windows, err := changewindow.ParseWindows([]string{"0 0 1 1 * 1m"})
+	require.NoError(t, err)
+	if windows.Open(time.Now()) {
+		t.Skip("the change window is open")
+	}
+	ctrl := &Controller{
+		Source: &staticSource{endpoints: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.used.tld", endpoint.RecordTypeA, "1.1.1.1"),
+		}},
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		ChangeWindows:      windows,
+	}
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Empty(t, p.ApplyChangesCalls)
+	assert.Equal(t, 1, ctrl.status.get().PendingChanges)
+}
@@ -23,6 +23,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type MatchAllDomainFilters []*DomainFilter
@@ -49,6 +51,9 @@ type DomainFilter struct {
 	regex *regexp.Regexp
 	// regexExclusion defines a regular expression to exclude the domains matched
 	regexExclusion *regexp.Regexp
+	// filterIndex and excludeIndex index Filters and exclude when created by the constructors
+	filterIndex  *domainIndex
+	excludeIndex *domainIndex
 }
 
 // domainFilterSerde is a helper type for serializing and deserializing DomainFilter.
@@ -64,6 +69,11 @@ func prepareFilters(filters []string) []string {
 	var fs []string
 	for _, filter := range filters {
 		if domain := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(filter), ".")); domain != "" {
+			if ascii, err := NormalizeDNSName(domain); err == nil {
+				domain = ascii
+			} else {
+				log.Warnf("Using domain filter %s as is: %v", domain, err)
+			}
 			fs = append(fs, domain)
 		}
 	}
@@ -72,12 +82,14 @@ func prepareFilters(filters []string) []string {
 
 // NewDomainFilterWithExclusions returns a new DomainFilter, given a list of matches and exclusions
 func NewDomainFilterWithExclusions(domainFilters []string, excludeDomains []string) DomainFilter {
-	return DomainFilter{Filters: prepareFilters(domainFilters), exclude: prepareFilters(excludeDomains)}
+	filters, exclude := prepareFilters(domainFilters), prepareFilters(excludeDomains)
+	return DomainFilter{Filters: filters, exclude: exclude, filterIndex: newDomainIndex(filters), excludeIndex: newDomainIndex(exclude)}
 }
 
 // NewDomainFilter returns a new DomainFilter given a comma separated list of domains
 func NewDomainFilter(domainFilters []string) DomainFilter {
-	return DomainFilter{Filters: prepareFilters(domainFilters)}
+	filters := prepareFilters(domainFilters)
+	return DomainFilter{Filters: filters, filterIndex: newDomainIndex(filters)}
 }
 
 // NewRegexDomainFilter returns a new DomainFilter given a regular expression
@@ -92,7 +104,69 @@ func (df DomainFilter) Match(domain string) bool {
 		return matchRegex(df.regex, df.regexExclusion, domain)
 	}
 
-	return matchFilter(df.Filters, domain, true) && !matchFilter(df.exclude, domain, false)
+	return df.matchFilters(domain) && !df.matchExclusions(domain)
+}
+
+func (df DomainFilter) matchFilters(domain string) bool {
+	if df.filterIndex != nil && len(df.Filters) > 0 {
+		return df.filterIndex.match(domain)
+	}
+	return matchFilter(df.Filters, domain, true)
+}
+
+func (df DomainFilter) matchExclusions(domain string) bool {
+	if df.excludeIndex != nil && len(df.exclude) > 0 {
+		return df.excludeIndex.match(domain)
+	}
+	return matchFilter(df.exclude, domain, false)
+}
+
+// domainIndex matches domains against a list of filters with one lookup per label of the
+// domain instead of one comparison per filter, which matters with hundreds of filters and
+// hundreds of thousands of records. It matches the same domains as matchFilter.
+type domainIndex struct {
+	// names holds the filters matching the name and its subdomains, e.g. "example.org"
+	names map[string]struct{}
+	// subdomains holds the filters only matching subdomains, e.g. ".example.org", without the dot
+	subdomains map[string]struct{}
+}
+
+func newDomainIndex(filters []string) *domainIndex {
+	if len(filters) == 0 {
+		return nil
+	}
+	idx := &domainIndex{names: map[string]struct{}{}, subdomains: map[string]struct{}{}}
+	for _, filter := range filters {
+		if filter == "" {
+			continue
+		}
+		if strings.HasPrefix(filter, ".") {
+			idx.subdomains[filter[1:]] = struct{}{}
+		} else {
+			idx.names[filter] = struct{}{}
+		}
+	}
+	return idx
+}
+
+func (idx *domainIndex) match(domain string) bool {
+	strippedDomain := strings.ToLower(strings.TrimSuffix(domain, "."))
+	if _, ok := idx.names[strippedDomain]; ok {
+		return true
+	}
+	for i := 0; i < len(strippedDomain); i++ {
+		if strippedDomain[i] != '.' {
+			continue
+		}
+		suffix := strippedDomain[i+1:]
+		if _, ok := idx.names[suffix]; ok {
+			return true
+		}
+		if _, ok := idx.subdomains[suffix]; ok {
+			return true
+		}
+	}
+	return false
 }
 
 // matchFilter determines if any `filters` match `domain`.
@@ -668,3 +668,22 @@ func deserialize[T any](t *testing.T, serialized map[string]T) DomainFilter {
 
 	return deserialized
 }
+
+func TestDomainFilterIndexMatchesLikeFilters(t *testing.T) {
+	filters := []string{"example.org", ".sub.example.com", "a.b.example.net", "EXAMPLE.io.", "."}
+	exclusions := []string{"excluded.example.org", ".internal.example.com"}
+	domainFilter := NewDomainFilterWithExclusions(filters, exclusions)
+	require.NotNil(t, domainFilter.filterIndex)
+	require.NotNil(t, domainFilter.excludeIndex)
+
+	for _, domain := range []string{
+		"example.org", "example.org.", "Foo.Example.org", "fooexample.org", "org",
+		"excluded.example.org", "a.excluded.example.org", "notexcluded.example.org",
+		"sub.example.com", "a.sub.example.com", "a.internal.example.com", ".sub.example.com",
+		"a.b.example.net", "x.a.b.example.net", "b.example.net",
+		"example.io", "www.example.io", "example.io.evil.com", "", ".", "..",
+	} {
+		expected := matchFilter(domainFilter.Filters, domain, true) && !matchFilter(domainFilter.exclude, domain, false)
+		assert.Equal(t, expected, domainFilter.Match(domain), domain)
+	}
+}
@@ -44,8 +44,22 @@ const (
 	RecordTypePTR = "PTR"
 	// RecordTypeMX is a RecordType enum value
 	RecordTypeMX = "MX"
+	// RecordTypeSSHFP is a RecordType enum value
+	RecordTypeSSHFP = "SSHFP"
 )
 
+// ProviderSpecificCanaryWeight is the percentage of the traffic of a DNS name requested by a canary record.
+// The plan turns it into the weights of the weighted records of the DNS name.
+const ProviderSpecificCanaryWeight = "canary-weight"
+
+// ProviderSpecificZoneID is the ID of the zone a record is pinned to, for DNS names matching several zones,
+// e.g. a public and a private zone of the same domain. Providers supporting it only write the record to that zone.
+const ProviderSpecificZoneID = "zone-id"
+
+// ProviderSpecificSourceWeight is the weight of an endpoint when several sources return conflicting endpoints
+// for the same record. It is removed once the conflicts are resolved.
+const ProviderSpecificSourceWeight = "source-weight"
+
 // TTL is a structure defining the TTL of a DNS record
 type TTL int64
 
@@ -159,6 +173,22 @@ type ProviderSpecificProperty struct {
 	Value string `json:"value,omitempty"`
 }
 
+// TargetReference references a Kubernetes object whose addresses are used as targets
+type TargetReference struct {
+	// Kind of the object, Service or Gateway
+	Kind string `json:"kind"`
+	// Namespace of the object, defaults to the namespace of the resource holding the reference
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the object
+	Name string `json:"name"`
+}
+
+// String returns the reference as kind/namespace/name.
+func (r TargetReference) String() string {
+	return strings.ToLower(r.Kind) + "/" + r.Namespace + "/" + r.Name
+}
+
 // ProviderSpecific holds configuration which is specific to individual DNS providers
 type ProviderSpecific []ProviderSpecificProperty
 
@@ -175,6 +205,10 @@ type Endpoint struct {
 	DNSName string `json:"dnsName,omitempty"`
 	// The targets the DNS record points to
 	Targets Targets `json:"targets,omitempty"`
+	// TargetsFrom references Kubernetes objects whose load balancer addresses are added to the targets.
+	// It is resolved by the crd source.
+	// +optional
+	TargetsFrom []TargetReference `json:"targetsFrom,omitempty"`
 	// RecordType type of record, e.g. CNAME, A, AAAA, SRV, TXT etc
 	RecordType string `json:"recordType,omitempty"`
 	// Identifier to distinguish multiple records with the same name and type (e.g. Route53 records with routing policies other than 'simple')
@@ -201,6 +235,12 @@ func NewEndpointWithTTL(dnsName, recordType string, ttl TTL, targets ...string)
 		cleanTargets[idx] = strings.TrimSuffix(target, ".")
 	}
 
+	dnsName, err := NormalizeDNSName(dnsName)
+	if err != nil {
+		log.Errorf("%v. Cannot create endpoint", err)
+		return nil
+	}
+
 	for _, label := range strings.Split(dnsName, ".") {
 		if len(label) > 63 {
 			log.Errorf("label %s in %s is longer than 63 characters. Cannot create endpoint", label, dnsName)
@@ -307,11 +347,39 @@ type DNSEndpointSpec struct {
 	Endpoints []*Endpoint `json:"endpoints,omitempty"`
 }
 
+// DNSEndpointConditionReady is the type of the condition reporting whether the records of a DNSEndpoint are synced.
+const DNSEndpointConditionReady = "Ready"
+
 // DNSEndpointStatus defines the observed state of DNSEndpoint
 type DNSEndpointStatus struct {
 	// The generation observed by the external-dns controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe whether the records of the endpoints are synced by the external-dns controller.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The last time records of the endpoints were applied by the external-dns controller.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// The sync state of the record of every endpoint.
+	// +optional
+	Records []DNSEndpointRecordStatus `json:"records,omitempty"`
+}
+
+// DNSEndpointRecordStatus defines the sync state of the record of an endpoint of a DNSEndpoint.
+type DNSEndpointRecordStatus struct {
+	// The hostname of the DNS record
+	DNSName string `json:"dnsName"`
+	// RecordType type of record, e.g. CNAME, A, AAAA, SRV, TXT etc
+	RecordType string `json:"recordType,omitempty"`
+	// Identifier to distinguish multiple records with the same name and type (e.g. Route53 records with routing policies other than 'simple')
+	// +optional
+	SetIdentifier string `json:"setIdentifier,omitempty"`
+	// Whether the record is in the DNS provider as desired.
+	Synced bool `json:"synced"`
+	// Why the record is not synced.
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // +genclient
@@ -324,6 +392,7 @@ type DNSEndpointStatus struct {
 // +kubebuilder:resource:path=dnsendpoints
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +versionName=v1alpha1
 
 type DNSEndpoint struct {
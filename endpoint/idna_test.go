@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeDNSName(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{"example.org", "example.org"},
+		{"Example.ORG", "Example.ORG"},
+		{"_acme-challenge.example.org", "_acme-challenge.example.org"},
+		{"bücher.example.org", "xn--bcher-kva.example.org"},
+		{"Bücher.example.org", "xn--bcher-kva.example.org"},
+		{"*.bücher.example.org", "*.xn--bcher-kva.example.org"},
+		{"παράδειγμα.δοκιμή", "xn--hxajbheg2az3al.xn--jxalpdlp"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, err := NormalizeDNSName(tc.name)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, normalized)
+		})
+	}
+
+	_, err := NormalizeDNSName("bücher‍.example.org")
+	assert.Error(t, err)
+}
+
+func TestDisplayDNSName(t *testing.T) {
+	assert.Equal(t, "example.org", DisplayDNSName("example.org"))
+	assert.Equal(t, "bücher.example.org", DisplayDNSName("xn--bcher-kva.example.org"))
+	assert.Equal(t, "*.bücher.example.org", DisplayDNSName("*.xn--bcher-kva.example.org"))
+	assert.Equal(t, "xn--zz.example.org", DisplayDNSName("xn--zz.example.org"))
+}
+
+func TestNewEndpointInternationalized(t *testing.T) {
+	ep := NewEndpoint("bücher.example.org.", RecordTypeA, "1.2.3.4")
+	require.NotNil(t, ep)
+	assert.Equal(t, "xn--bcher-kva.example.org", ep.DNSName)
+
+	assert.Nil(t, NewEndpoint("bücher‍.example.org", RecordTypeA, "1.2.3.4"))
+}
+
+func TestDomainFilterInternationalized(t *testing.T) {
+	filter := NewDomainFilter([]string{"Bücher.example.org"})
+	assert.Equal(t, []string{"xn--bcher-kva.example.org"}, filter.Filters)
+	assert.True(t, filter.Match("shop.xn--bcher-kva.example.org"))
+	assert.False(t, filter.Match("shop.example.org"))
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -44,8 +45,24 @@ const (
 	// DualstackLabelKey is the name of the label that identifies dualstack endpoints
 	DualstackLabelKey = "dualstack"
 
+	// ClusterLabelKey is the name of the label that identifies the cluster the record was created from
+	ClusterLabelKey = "cluster"
+	// CommitLabelKey is the name of the label that stores the git commit of the k8s resource which wants to acquire the DNS name
+	CommitLabelKey = "commit"
+
+	// TombstoneLabelKey is the name of the label that stores when the record was first found to be no longer desired
+	TombstoneLabelKey = "tombstone"
+
+	// ActiveTargetLabelKey is the name of the label that stores whether the blue or the green targets of the k8s resource are active
+	ActiveTargetLabelKey = "active-target"
+	// PreviousTargetsLabelKey is the name of the label that stores the targets of a blue/green record before its last swap, separated by semicolons
+	PreviousTargetsLabelKey = "previous-targets"
+
 	// txtEncryptionNonce label for keep same nonce for same txt records, for prevent different result of encryption for same txt record, it can cause issues for some providers
 	txtEncryptionNonce = "txt-encryption-nonce"
+
+	// txtEncryptionKeyIndex label for keep the index of the key which decrypted the txt record, when it is not the first (encryption) key
+	txtEncryptionKeyIndex = "txt-encryption-key-index"
 )
 
 // Labels store metadata related to the endpoint
@@ -91,14 +108,29 @@ func NewLabelsFromStringPlain(labelText string) (Labels, error) {
 }
 
 func NewLabelsFromString(labelText string, aesKey []byte) (Labels, error) {
-	if len(aesKey) != 0 {
+	return NewLabelsFromStringWithKeys(labelText, [][]byte{aesKey})
+}
+
+// NewLabelsFromStringWithKeys constructs endpoints labels from a provided format string
+// trying to decrypt it with each of the supplied AES keys in order.
+// The first key is the one used for encryption, the others are only used for decryption
+// so that keys can be rotated. If a key other than the first one decrypted the labels,
+// its index is kept in the labels and returned by EncryptionKeyIndex.
+func NewLabelsFromStringWithKeys(labelText string, aesKeys [][]byte) (Labels, error) {
+	for i, aesKey := range aesKeys {
+		if len(aesKey) == 0 {
+			continue
+		}
 		decryptedText, encryptionNonce, err := DecryptText(strings.Trim(labelText, "\""), aesKey)
-		//in case if we have decryption error, just try process original text
+		//in case if we have decryption error, just try the next key and finally process original text
 		//decryption errors should be ignored here, because we can already have plain-text labels in registry
 		if err == nil {
 			labels, err := NewLabelsFromStringPlain(decryptedText)
 			if err == nil {
 				labels[txtEncryptionNonce] = encryptionNonce
+				if i > 0 {
+					labels[txtEncryptionKeyIndex] = strconv.Itoa(i)
+				}
 			}
 
 			return labels, err
@@ -107,6 +139,29 @@ func NewLabelsFromString(labelText string, aesKey []byte) (Labels, error) {
 	return NewLabelsFromStringPlain(labelText)
 }
 
+// EncryptionKeyIndex returns the index of the key which decrypted the labels.
+// It is 0 for labels decrypted with the encryption key and for plain-text labels.
+func (l Labels) EncryptionKeyIndex() int {
+	index, err := strconv.Atoi(l[txtEncryptionKeyIndex])
+	if err != nil {
+		return 0
+	}
+	return index
+}
+
+// Resource returns the kind, namespace and name of the k8s resource stored in the resource label.
+// The namespace is empty for cluster-scoped resources.
+func (l Labels) Resource() (kind, namespace, name string) {
+	parts := strings.SplitN(l[ResourceLabelKey], "/", 3)
+	switch len(parts) {
+	case 3:
+		return parts[0], parts[1], parts[2]
+	case 2:
+		return parts[0], "", parts[1]
+	}
+	return "", "", ""
+}
+
 // SerializePlain transforms endpoints labels into a external-dns recognizable format string
 // withQuotes adds additional quotes
 func (l Labels) SerializePlain(withQuotes bool) string {
@@ -119,7 +174,7 @@ func (l Labels) SerializePlain(withQuotes bool) string {
 	sort.Strings(keys) // sort for consistency
 
 	for _, key := range keys {
-		if key == txtEncryptionNonce {
+		if key == txtEncryptionNonce || key == txtEncryptionKeyIndex {
 			continue
 		}
 		tokens = append(tokens, fmt.Sprintf("%s/%s=%s", heritage, key, l[key]))
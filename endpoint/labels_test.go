@@ -121,6 +121,45 @@ func (suite *LabelsSuite) TestDeserialize() {
 	suite.Nil(multipleHeritage, "if error should return nil")
 }
 
+func (suite *LabelsSuite) TestDeserializeWithKeys() {
+	newKey := []byte("s8d5dPNZ2Al1Kp3BbSu7lN/g7aHlUAe6")
+
+	foo, err := NewLabelsFromStringWithKeys(suite.fooAsTextEncrypted, [][]byte{newKey, suite.aesKey})
+	suite.NoError(err, "should succeed with a decryption-only key")
+	for key, val := range suite.foo {
+		suite.Equal(val, foo[key], "should contains all keys from original label map")
+	}
+	suite.Equal(1, foo.EncryptionKeyIndex(), "should remember the key used for decryption")
+	suite.NotContains(foo.SerializePlain(false), txtEncryptionKeyIndex, "should not serialize the key index")
+
+	rotated, err := NewLabelsFromStringWithKeys(foo.Serialize(false, true, newKey), [][]byte{newKey, suite.aesKey})
+	suite.NoError(err, "should succeed with the encryption key")
+	suite.Equal(0, rotated.EncryptionKeyIndex(), "should decrypt with the encryption key")
+
+	_, err = NewLabelsFromStringWithKeys(suite.fooAsTextEncrypted, [][]byte{newKey})
+	suite.Equal(ErrInvalidHeritage, err, "should fail without the original key")
+
+	foo, err = NewLabelsFromStringWithKeys(suite.fooAsText, [][]byte{nil, suite.aesKey})
+	suite.NoError(err, "should succeed for plain label text")
+	suite.Equal(suite.foo, foo, "should reconstruct original label map")
+}
+
+func (suite *LabelsSuite) TestResource() {
+	for _, tc := range []struct {
+		resource              string
+		kind, namespace, name string
+	}{
+		{"ingress/default/foo", "ingress", "default", "foo"},
+		{"node/worker-1", "node", "", "worker-1"},
+		{"", "", "", ""},
+	} {
+		kind, namespace, name := Labels{ResourceLabelKey: tc.resource}.Resource()
+		suite.Equal(tc.kind, kind, tc.resource)
+		suite.Equal(tc.namespace, namespace, tc.resource)
+		suite.Equal(tc.name, name, tc.resource)
+	}
+}
+
 func TestLabels(t *testing.T) {
 	suite.Run(t, new(LabelsSuite))
 }
@@ -22,6 +22,7 @@ limitations under the License.
 package endpoint
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -31,7 +32,7 @@ func (in *DNSEndpoint) DeepCopyInto(out *DNSEndpoint) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -86,6 +87,22 @@ func (in *DNSEndpointList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSEndpointRecordStatus) DeepCopyInto(out *DNSEndpointRecordStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSEndpointRecordStatus.
+func (in *DNSEndpointRecordStatus) DeepCopy() *DNSEndpointRecordStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSEndpointRecordStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSEndpointSpec) DeepCopyInto(out *DNSEndpointSpec) {
 	*out = *in
@@ -117,6 +134,22 @@ func (in *DNSEndpointSpec) DeepCopy() *DNSEndpointSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSEndpointStatus) DeepCopyInto(out *DNSEndpointStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Records != nil {
+		in, out := &in.Records, &out.Records
+		*out = make([]DNSEndpointRecordStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -138,6 +171,11 @@ func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 		*out = make(Targets, len(*in))
 		copy(*out, *in)
 	}
+	if in.TargetsFrom != nil {
+		in, out := &in.TargetsFrom, &out.TargetsFrom
+		*out = make([]TargetReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(Labels, len(*in))
@@ -209,6 +247,22 @@ func (in ProviderSpecific) DeepCopy() ProviderSpecific {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetReference) DeepCopyInto(out *TargetReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetReference.
+func (in *TargetReference) DeepCopy() *TargetReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Targets) DeepCopyInto(out *Targets) {
 	{
@@ -18,10 +18,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
@@ -41,6 +44,19 @@ import (
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns/validation"
+	"sigs.k8s.io/external-dns/pkg/approval"
+	"sigs.k8s.io/external-dns/pkg/audit"
+	"sigs.k8s.io/external-dns/pkg/changewindow"
+	"sigs.k8s.io/external-dns/pkg/dnspolicy"
+	"sigs.k8s.io/external-dns/pkg/dnssec"
+	"sigs.k8s.io/external-dns/pkg/dnszone"
+	"sigs.k8s.io/external-dns/pkg/export"
+	"sigs.k8s.io/external-dns/pkg/metricsserver"
+	"sigs.k8s.io/external-dns/pkg/notify"
+	"sigs.k8s.io/external-dns/pkg/readiness"
+	"sigs.k8s.io/external-dns/pkg/reload"
+	"sigs.k8s.io/external-dns/pkg/tailscale"
+	"sigs.k8s.io/external-dns/pkg/verify"
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 	"sigs.k8s.io/external-dns/provider/akamai"
@@ -96,6 +112,14 @@ func main() {
 	}
 	log.Infof("config: %s", cfg)
 
+	if cfg.TriggerSync != "" {
+		if err := metricsserver.TriggerSync(context.Background(), cfg.TriggerSync, cfg.MetricsBearerTokenFile, cfg.TriggerSyncCAFile); err != nil {
+			log.Fatal(err)
+		}
+		log.Infof("Triggered the synchronization of %s", cfg.TriggerSync)
+		os.Exit(0)
+	}
+
 	if err := validation.ValidateConfig(cfg); err != nil {
 		log.Fatalf("config validation failed: %v", err)
 	}
@@ -117,49 +141,19 @@ func main() {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	go serveMetrics(cfg.MetricsAddress)
+	readinessChecks := &readiness.Checks{}
+	go serveMetrics(metricsserver.Config{
+		Address:         cfg.MetricsAddress,
+		TLSCertFile:     cfg.MetricsTLSCertFile,
+		TLSKeyFile:      cfg.MetricsTLSKeyFile,
+		TLSClientCAFile: cfg.MetricsTLSClientCAFile,
+		BearerTokenFile: cfg.MetricsBearerTokenFile,
+		PublicPaths:     []string{"/healthz", "/readyz"},
+	}, readinessChecks)
 	go handleSigterm(cancel)
 
-	// error is explicitly ignored because the filter is already validated in validation.ValidateConfig
-	labelSelector, _ := labels.Parse(cfg.LabelFilter)
-
-	// Create a source.Config from the flags passed by the user.
-	sourceCfg := &source.Config{
-		Namespace:                      cfg.Namespace,
-		AnnotationFilter:               cfg.AnnotationFilter,
-		LabelFilter:                    labelSelector,
-		IngressClassNames:              cfg.IngressClassNames,
-		FQDNTemplate:                   cfg.FQDNTemplate,
-		CombineFQDNAndAnnotation:       cfg.CombineFQDNAndAnnotation,
-		IgnoreHostnameAnnotation:       cfg.IgnoreHostnameAnnotation,
-		IgnoreIngressTLSSpec:           cfg.IgnoreIngressTLSSpec,
-		IgnoreIngressRulesSpec:         cfg.IgnoreIngressRulesSpec,
-		GatewayNamespace:               cfg.GatewayNamespace,
-		GatewayLabelFilter:             cfg.GatewayLabelFilter,
-		Compatibility:                  cfg.Compatibility,
-		PublishInternal:                cfg.PublishInternal,
-		PublishHostIP:                  cfg.PublishHostIP,
-		AlwaysPublishNotReadyAddresses: cfg.AlwaysPublishNotReadyAddresses,
-		ConnectorServer:                cfg.ConnectorSourceServer,
-		CRDSourceAPIVersion:            cfg.CRDSourceAPIVersion,
-		CRDSourceKind:                  cfg.CRDSourceKind,
-		KubeConfig:                     cfg.KubeConfig,
-		APIServerURL:                   cfg.APIServerURL,
-		ServiceTypeFilter:              cfg.ServiceTypeFilter,
-		CFAPIEndpoint:                  cfg.CFAPIEndpoint,
-		CFUsername:                     cfg.CFUsername,
-		CFPassword:                     cfg.CFPassword,
-		GlooNamespaces:                 cfg.GlooNamespaces,
-		SkipperRouteGroupVersion:       cfg.SkipperRouteGroupVersion,
-		RequestTimeout:                 cfg.RequestTimeout,
-		DefaultTargets:                 cfg.DefaultTargets,
-		OCPRouterName:                  cfg.OCPRouterName,
-		UpdateEvents:                   cfg.UpdateEvents,
-		ResolveLoadBalancerHostname:    cfg.ResolveServiceLoadBalancerHostname,
-	}
-
-	// Lookup all the selected sources by names and pass them the desired configuration.
-	sources, err := source.ByNames(ctx, &source.SingletonClientGenerator{
+	// Create the clients of the sources, shared with the sources rebuilt when the config file is reloaded.
+	clientGenerator := &source.SingletonClientGenerator{
 		KubeConfig:   cfg.KubeConfig,
 		APIServerURL: cfg.APIServerURL,
 		// If update events are enabled, disable timeout.
@@ -169,32 +163,23 @@ func main() {
 			}
 			return cfg.RequestTimeout
 		}(),
-	}, cfg.Sources, sourceCfg)
+	}
+
+	// The sources get their own context, canceled when they are rebuilt once the config file is reloaded.
+	sourcesCtx, cancelSources := context.WithCancel(ctx)
+	endpointsSource, syncReporters, err := newEndpointsSource(sourcesCtx, cfg, clientGenerator)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// Filter targets
-	targetFilter := endpoint.NewTargetNetFilterWithExclusions(cfg.TargetNetFilter, cfg.ExcludeTargetNets)
-
-	// Combine multiple sources into a single, deduplicated source.
-	endpointsSource := source.NewDedupSource(source.NewMultiSource(sources, sourceCfg.DefaultTargets))
-	endpointsSource = source.NewTargetFilterSource(endpointsSource, targetFilter)
-
-	// RegexDomainFilter overrides DomainFilter
-	var domainFilter endpoint.DomainFilter
-	if cfg.RegexDomainFilter.String() != "" {
-		domainFilter = endpoint.NewRegexDomainFilter(cfg.RegexDomainFilter, cfg.RegexDomainExclusion)
-	} else {
-		domainFilter = endpoint.NewDomainFilterWithExclusions(cfg.DomainFilter, cfg.ExcludeDomains)
-	}
+	domainFilter := newDomainFilter(cfg)
 	zoneNameFilter := endpoint.NewDomainFilter(cfg.ZoneNameFilter)
 	zoneIDFilter := provider.NewZoneIDFilter(cfg.ZoneIDFilter)
 	zoneTypeFilter := provider.NewZoneTypeFilter(cfg.AWSZoneType)
-	zoneTagFilter := provider.NewZoneTagFilter(cfg.AWSZoneTagFilter)
+	zoneTagFilter := provider.NewZoneTagFilter(append(cfg.ZoneTagFilter, cfg.AWSZoneTagFilter...))
 
 	var awsSession *session.Session
-	if cfg.Provider == "aws" || cfg.Provider == "aws-sd" || cfg.Registry == "dynamodb" {
+	if cfg.Provider == "aws" || cfg.Provider == "aws-sd" || cfg.Registry == "dynamodb" || cfg.SecondaryRegistry == "dynamodb" {
 		awsSession, err = aws.NewSession(
 			aws.AWSSessionConfig{
 				AssumeRole:           cfg.AWSAssumeRole,
@@ -233,9 +218,13 @@ func main() {
 				ZoneTagFilter:        zoneTagFilter,
 				BatchChangeSize:      cfg.AWSBatchChangeSize,
 				BatchChangeInterval:  cfg.AWSBatchChangeInterval,
+				ZoneWorkers:          cfg.AWSZoneWorkers,
+				ZoneChangeRate:       cfg.AWSZoneChangeRate,
 				EvaluateTargetHealth: cfg.AWSEvaluateTargetHealth,
+				HealthChecks:         cfg.AWSHealthChecks,
 				PreferCNAME:          cfg.AWSPreferCNAME,
 				DryRun:               cfg.DryRun,
+				ValidateDryRun:       cfg.ValidateDryRun,
 				ZoneCacheDuration:    cfg.AWSZoneCacheDuration,
 			},
 			route53.New(awsSession),
@@ -248,9 +237,9 @@ func main() {
 		}
 		p, err = awssd.NewAWSSDProvider(domainFilter, cfg.AWSZoneType, cfg.DryRun, cfg.AWSSDServiceCleanup, cfg.TXTOwnerID, sd.New(awsSession))
 	case "azure-dns", "azure":
-		p, err = azure.NewAzureProvider(cfg.AzureConfigFile, domainFilter, zoneNameFilter, zoneIDFilter, cfg.AzureResourceGroup, cfg.AzureUserAssignedIdentityClientID, cfg.DryRun)
+		p, err = azure.NewAzureProvider(cfg.AzureConfigFile, domainFilter, zoneNameFilter, zoneIDFilter, zoneTagFilter, cfg.AzureResourceGroup, cfg.AzureUserAssignedIdentityClientID, cfg.DryRun)
 	case "azure-private-dns":
-		p, err = azure.NewAzurePrivateDNSProvider(cfg.AzureConfigFile, domainFilter, zoneIDFilter, cfg.AzureResourceGroup, cfg.AzureUserAssignedIdentityClientID, cfg.DryRun)
+		p, err = azure.NewAzurePrivateDNSProvider(cfg.AzureConfigFile, domainFilter, zoneIDFilter, zoneTagFilter, cfg.AzureResourceGroup, cfg.AzureUserAssignedIdentityClientID, cfg.DryRun)
 	case "bluecat":
 		p, err = bluecat.NewBluecatProvider(cfg.BluecatConfigFile, cfg.BluecatDNSConfiguration, cfg.BluecatDNSServerName, cfg.BluecatDNSDeployType, cfg.BluecatDNSView, cfg.BluecatGatewayHost, cfg.BluecatRootZone, cfg.TXTPrefix, cfg.TXTSuffix, domainFilter, zoneIDFilter, cfg.DryRun, cfg.BluecatSkipTLSVerify)
 	case "vinyldns":
@@ -262,11 +251,18 @@ func main() {
 	case "civo":
 		p, err = civo.NewCivoProvider(domainFilter, cfg.DryRun)
 	case "cloudflare":
-		p, err = cloudflare.NewCloudFlareProvider(domainFilter, zoneIDFilter, cfg.CloudflareProxied, cfg.DryRun, cfg.CloudflareDNSRecordsPerPage)
+		tunnelID := cfg.CloudflareTunnelID
+		if tunnelID == "" && cfg.CloudflareTunnelCredentialsFile != "" {
+			tunnelID, err = cloudflare.ReadTunnelID(cfg.CloudflareTunnelCredentialsFile)
+			if err != nil {
+				break
+			}
+		}
+		p, err = cloudflare.NewCloudFlareProvider(domainFilter, zoneIDFilter, zoneTagFilter, cfg.CloudflareProxied, tunnelID, cfg.DryRun, cfg.CloudflareDNSRecordsPerPage)
 	case "rcodezero":
 		p, err = rcode0.NewRcodeZeroProvider(domainFilter, cfg.DryRun, cfg.RcodezeroTXTEncrypt)
 	case "google":
-		p, err = google.NewGoogleProvider(ctx, cfg.GoogleProject, domainFilter, zoneIDFilter, cfg.GoogleBatchChangeSize, cfg.GoogleBatchChangeInterval, cfg.GoogleZoneVisibility, cfg.DryRun)
+		p, err = google.NewGoogleProvider(ctx, cfg.GoogleProject, domainFilter, zoneIDFilter, zoneTagFilter, cfg.GoogleBatchChangeSize, cfg.GoogleBatchChangeInterval, cfg.GoogleZoneVisibility, cfg.GoogleZoneWorkers, cfg.DryRun)
 	case "digitalocean":
 		p, err = digitalocean.NewDigitalOceanProvider(ctx, domainFilter, cfg.DryRun, cfg.DigitalOceanAPIPageSize)
 	case "ovh":
@@ -418,42 +414,231 @@ func main() {
 		os.Exit(0)
 	}
 
-	var r registry.Registry
-	switch cfg.Registry {
-	case "dynamodb":
-		config := awsSDK.NewConfig()
-		if cfg.AWSDynamoDBRegion != "" {
-			config = config.WithRegion(cfg.AWSDynamoDBRegion)
+	if cfg.Preflight {
+		preflighter, ok := p.(provider.Preflighter)
+		switch {
+		case cfg.DryRun:
+			log.Info("Skipping the preflight in dry-run mode")
+		case !ok:
+			log.Warnf("The %s provider does not support --preflight", cfg.Provider)
+		default:
+			readinessChecks.Set("preflight", errors.New("not completed yet"))
+			if cfg.PreflightCredentialsFile != "" {
+				go watchCredentialsFile(ctx, cfg.PreflightCredentialsFile, preflighter, cfg.Interval, readinessChecks)
+			} else {
+				go runPreflight(ctx, preflighter, cfg.Interval, readinessChecks)
+			}
+		}
+	}
+
+	if cfg.TailscaleTailnet != "" {
+		if cfg.DryRun {
+			log.Info("Skipping the tailscale split DNS configuration in dry-run mode")
+		} else {
+			tailnet := &tailscale.Client{Tailnet: cfg.TailscaleTailnet, APIKey: cfg.TailscaleAPIKey}
+			readinessChecks.Set("tailscale", errors.New("not completed yet"))
+			go runTailscaleSplitDNS(ctx, tailnet, cfg.DomainFilter, cfg.TailscaleNameservers, cfg.Interval, readinessChecks)
+		}
+	}
+
+	if cfg.ManageZones {
+		zoneManager, ok := p.(provider.ZoneManager)
+		switch {
+		case cfg.DryRun:
+			log.Info("Skipping the management of DNSZones in dry-run mode")
+		case !ok:
+			log.Warnf("The %s provider does not support --manage-zones", cfg.Provider)
+		default:
+			client, err := clientGenerator.DynamicKubernetesClient()
+			if err != nil {
+				log.Fatal(err)
+			}
+			reconciler := dnszone.NewReconciler(client, zoneManager, cfg.ManageZonesNamespace, domainFilter)
+			go runZoneReconciler(ctx, reconciler, cfg.Interval)
+		}
+	}
+
+	if cfg.DNSSECExportFile != "" {
+		if signerProvider, ok := p.(provider.DelegationSignerProvider); ok {
+			go runDNSSECExport(ctx, dnssec.NewFileExporter(signerProvider, cfg.DNSSECExportFile), cfg.Interval)
+		} else {
+			log.Warnf("The %s provider does not support --dnssec-export-file", cfg.Provider)
 		}
-		r, err = registry.NewDynamoDBRegistry(p, cfg.TXTOwnerID, dynamodb.New(awsSession, config), cfg.AWSDynamoDBTable, cfg.TXTPrefix, cfg.TXTSuffix, cfg.TXTWildcardReplacement, cfg.ManagedDNSRecordTypes, cfg.ExcludeDNSRecordTypes, []byte(cfg.TXTEncryptAESKey), cfg.TXTCacheInterval)
-	case "noop":
-		r, err = registry.NewNoopRegistry(p)
-	case "txt":
-		r, err = registry.NewTXTRegistry(p, cfg.TXTPrefix, cfg.TXTSuffix, cfg.TXTOwnerID, cfg.TXTCacheInterval, cfg.TXTWildcardReplacement, cfg.ManagedDNSRecordTypes, cfg.ExcludeDNSRecordTypes, cfg.TXTEncryptEnabled, []byte(cfg.TXTEncryptAESKey))
-	case "aws-sd":
-		r, err = registry.NewAWSSDRegistry(p.(*awssd.AWSSDProvider), cfg.TXTOwnerID)
-	default:
-		log.Fatalf("unknown registry: %s", cfg.Registry)
 	}
 
+	if len(cfg.DefaultTTLs) > 0 {
+		// error is explicitly ignored because the TTLs are already validated in validation.ValidateConfig
+		defaultTTLs, _ := externaldns.ParseDefaultTTLs(cfg.DefaultTTLs)
+		ttls := make(map[string]endpoint.TTL, len(defaultTTLs))
+		for recordType, ttl := range defaultTTLs {
+			ttls[recordType] = endpoint.TTL(ttl.Seconds())
+		}
+		p = provider.NewDefaultTTLProvider(p, ttls)
+	}
+
+	r, err := newRegistry(cfg.Registry, p, cfg, awsSession)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if cfg.TXTOwnerIDTemplate != "" {
+		kubeClient, err := clientGenerator.KubeClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		owners, err := registry.NewNamespaceOwners(ctx, kubeClient, cfg.TXTOwnerIDTemplate)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.(*registry.TXTRegistry).SetNamespaceOwners(owners)
+	}
+
+	if cfg.SecondaryRegistry != "" {
+		// The secondary registry only records ownership, DNS records are changed through the primary registry.
+		secondary, err := newRegistry(cfg.SecondaryRegistry, registry.NewOwnershipOnlyProvider(p), cfg, awsSession)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r, err = registry.NewDualRegistry(r, secondary)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var cachedRegistry *registry.CachedRegistry
+	var snapshotStore registry.SnapshotStore
+	if cfg.RegistryCacheInterval > 0 {
+		cachedRegistry = registry.NewCachedRegistry(r, cfg.RegistryCacheInterval)
+		r = cachedRegistry
+		snapshotStore, err = newSnapshotStore(cfg, clientGenerator)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if snapshotStore != nil {
+			if err := cachedRegistry.Restore(ctx, snapshotStore); err != nil {
+				log.Warnf("Failed to restore the registry records from the snapshot: %v", err)
+			}
+		}
+	}
+
 	policy, exists := plan.Policies[cfg.Policy]
 	if !exists {
 		log.Fatalf("unknown policy: %s", cfg.Policy)
 	}
 
+	targetNormalizations := make([]plan.TargetNormalization, 0, len(cfg.TargetNormalizations))
+	for _, name := range cfg.TargetNormalizations {
+		targetNormalizations = append(targetNormalizations, plan.TargetNormalizations[name])
+	}
+
+	changeWindows, err := changewindow.ParseWindows(cfg.ChangeWindows)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	ctrl := controller.Controller{
-		Source:               endpointsSource,
-		Registry:             r,
-		Policy:               policy,
-		Interval:             cfg.Interval,
-		DomainFilter:         domainFilter,
-		ManagedRecordTypes:   cfg.ManagedDNSRecordTypes,
-		ExcludeRecordTypes:   cfg.ExcludeDNSRecordTypes,
-		MinEventSyncInterval: cfg.MinEventSyncInterval,
+		Source:                    endpointsSource,
+		Registry:                  r,
+		Policy:                    policy,
+		Interval:                  cfg.Interval,
+		DomainFilter:              domainFilter,
+		ManagedRecordTypes:        cfg.ManagedDNSRecordTypes,
+		ExcludeRecordTypes:        cfg.ExcludeDNSRecordTypes,
+		MinEventSyncInterval:      cfg.MinEventSyncInterval,
+		FullReconcileInterval:     cfg.FullReconcileInterval,
+		TargetNormalizations:      targetNormalizations,
+		MetadataSensitive:         cfg.MetadataSensitiveDiff,
+		DryRun:                    cfg.DryRun,
+		IgnoreTTL:                 cfg.IgnoreTTLDifferences,
+		MinTTL:                    endpoint.TTL(cfg.MinTTL.Seconds()),
+		DeletionGracePeriod:       cfg.DeletionGracePeriod,
+		WildcardPolicy:            plan.WildcardPolicy(cfg.WildcardPolicy),
+		FQDNPolicy:                plan.FQDNPolicy(cfg.FQDNPolicy),
+		WeightProperty:            weightProperty(cfg.Provider),
+		DrainTimeout:              cfg.DrainTimeout,
+		PendingChangesFile:        cfg.PendingChangesFile,
+		ChangeWindows:             changeWindows,
+		ChangeWindowDeletionsOnly: cfg.ChangeWindowScope == "deletions",
+		SyncReporters:             syncReporters,
+		DebugStatusEndpoint:       cfg.DebugStatusEndpoint,
+		DebugRecordsEndpoint:      cfg.DebugRecordsEndpoint,
+	}
+
+	if cfg.DebugStatusEndpoint {
+		http.Handle("/debug/status", controller.NewStatusHandler(&ctrl))
+	}
+	if cfg.DebugRecordsEndpoint {
+		http.Handle("/debug/records", registry.NewRecordsHandler(ctrl.SyncedRecords))
+	}
+
+	if cfg.PlanApproval {
+		client, err := clientGenerator.DynamicKubernetesClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctrl.ApprovalGate = approval.NewCRDGate(client, cfg.PlanApprovalNamespace, cfg.TXTOwnerID, cfg.PlanApprovalExpiry)
+	}
+
+	if cfg.DNSPolicies {
+		client, err := clientGenerator.DynamicKubernetesClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		kubeClient, err := clientGenerator.KubeClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctrl.DNSPolicies = dnspolicy.NewCRDEnforcer(client, kubeClient)
+	}
+
+	if cfg.GitExportRepository != "" {
+		ctrl.StateExporter, err = export.NewGitExporter(export.GitConfig{
+			Repository: cfg.GitExportRepository,
+			Branch:     cfg.GitExportBranch,
+			Path:       cfg.GitExportPath,
+			Format:     cfg.GitExportFormat,
+			Author:     cfg.GitExportAuthor,
+			Dir:        cfg.GitExportDir,
+			OwnerID:    cfg.TXTOwnerID,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if cfg.NotifyWebhookURL != "" {
+		notifier, err := notify.NewWebhookNotifier(cfg.NotifyWebhookURL, cfg.NotifyFormat)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctrl.Notifier = &notify.FilteredNotifier{
+			Notifier:   notifier,
+			Zones:      endpoint.NewDomainFilter(cfg.NotifyZones),
+			MinChanges: cfg.NotifyMinChanges,
+		}
+	}
+
+	if len(cfg.VerifyResolvers) > 0 {
+		ctrl.Verifier, err = verify.NewDNSVerifier(cfg.VerifyResolvers, cfg.VerifyTimeout, cfg.VerifyRetries, cfg.VerifyInterval)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if len(cfg.AuditSinks) > 0 {
+		ctrl.AuditSink, err = newAuditSink(cfg, clientGenerator)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if slices.Contains(cfg.AuditSinks, "events") {
+		// the records changed outside ExternalDNS are reported as Events of their resources as well
+		client, err := clientGenerator.KubeClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctrl.DriftRecorder = audit.NewEventSink(client)
 	}
 
 	if cfg.Once {
@@ -472,8 +657,402 @@ func main() {
 		ctrl.Source.AddEventHandler(ctx, func() { ctrl.ScheduleRunOnce(time.Now()) })
 	}
 
+	if cfg.SyncEndpoint {
+		http.Handle("/sync", ctrl.SyncHandler())
+	}
+
+	if cfg.ConfigFile != "" {
+		go watchConfigFile(ctx, cfg, &ctrl, clientGenerator, cancelSources)
+	}
+
+	if cfg.ReadinessMaxSyncIntervals > 0 {
+		maxAge := time.Duration(cfg.ReadinessMaxSyncIntervals) * cfg.Interval
+		readinessChecks.AddFunc("sync", ctrl.SyncAgeCheck(maxAge, time.Now()))
+	}
+
 	ctrl.ScheduleRunOnce(time.Now())
 	ctrl.Run(ctx)
+
+	if snapshotStore != nil {
+		// the context is canceled by now, give saving the snapshot its own deadline
+		saveCtx, cancelSave := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancelSave()
+		if err := cachedRegistry.Persist(saveCtx, snapshotStore); err != nil {
+			log.Errorf("Failed to save the registry records to the snapshot: %v", err)
+		}
+	}
+}
+
+// newEndpointsSource creates the source of the desired endpoints selected by the configuration, along with the
+// sources reporting the outcome of the synchronizations. The informers of the sources stop with the context.
+func newEndpointsSource(ctx context.Context, cfg *externaldns.Config, clientGenerator *source.SingletonClientGenerator) (source.Source, []source.SyncReporter, error) {
+	// error is explicitly ignored because the filter is already validated in validation.ValidateConfig
+	labelSelector, _ := labels.Parse(cfg.LabelFilter)
+	nodeLabelSelector, _ := labels.Parse(cfg.NodeLabelFilter)
+	podLabelSelector, _ := labels.Parse(cfg.PodLabelSelector)
+	crdLabelSelector, _ := labels.Parse(cfg.CRDSourceLabelFilter)
+
+	// Create a source.Config from the flags passed by the user.
+	sourceCfg := &source.Config{
+		Namespace:                      cfg.Namespace,
+		AnnotationFilter:               cfg.AnnotationFilter,
+		LabelFilter:                    labelSelector,
+		IngressClassNames:              cfg.IngressClassNames,
+		FQDNTemplate:                   cfg.FQDNTemplate,
+		CombineFQDNAndAnnotation:       cfg.CombineFQDNAndAnnotation,
+		IgnoreHostnameAnnotation:       cfg.IgnoreHostnameAnnotation,
+		IgnoreIngressTLSSpec:           cfg.IgnoreIngressTLSSpec,
+		IgnoreIngressRulesSpec:         cfg.IgnoreIngressRulesSpec,
+		GatewayNamespace:               cfg.GatewayNamespace,
+		GatewayLabelFilter:             cfg.GatewayLabelFilter,
+		GatewayEnvoyServices:           cfg.GatewayEnvoyServices,
+		Compatibility:                  cfg.Compatibility,
+		PublishInternal:                cfg.PublishInternal,
+		PublishHostIP:                  cfg.PublishHostIP,
+		AlwaysPublishNotReadyAddresses: cfg.AlwaysPublishNotReadyAddresses,
+		NodeSSHFP:                      cfg.NodeSSHFP,
+		NodeSSHFPSecret:                cfg.NodeSSHFPSecret,
+		NodeLabelFilter:                nodeLabelSelector,
+		NodeExcludeNotReady:            cfg.NodeExcludeNotReady,
+		NodeExcludeUnschedulable:       cfg.NodeExcludeUnschedulable,
+		NodeIPv4AddressType:            cfg.NodeIPv4AddressType,
+		NodeIPv6AddressType:            cfg.NodeIPv6AddressType,
+		PodLabelSelector:               podLabelSelector,
+		PodPublishPodIP:                cfg.PodPublishPodIP,
+		ConnectorServer:                cfg.ConnectorSourceServer,
+		ConnectorTLS:                   cfg.ConnectorSourceTLS,
+		ConnectorTLSCA:                 cfg.ConnectorSourceTLSCA,
+		ConnectorTLSCert:               cfg.ConnectorSourceTLSCert,
+		ConnectorTLSKey:                cfg.ConnectorSourceTLSKey,
+		ConnectorTokenFile:             cfg.ConnectorSourceTokenFile,
+		CRDSourceAPIVersion:            cfg.CRDSourceAPIVersion,
+		CRDSourceKind:                  cfg.CRDSourceKind,
+		CRDSourceNamespaces:            cfg.CRDSourceNamespaces,
+		CRDSourceLabelFilter:           crdLabelSelector,
+		KubeConfig:                     cfg.KubeConfig,
+		APIServerURL:                   cfg.APIServerURL,
+		ServiceTypeFilter:              cfg.ServiceTypeFilter,
+		CFAPIEndpoint:                  cfg.CFAPIEndpoint,
+		CFUsername:                     cfg.CFUsername,
+		CFPassword:                     cfg.CFPassword,
+		GlooNamespaces:                 cfg.GlooNamespaces,
+		SkipperRouteGroupVersion:       cfg.SkipperRouteGroupVersion,
+		IstioPublishMeshOnlyHosts:      cfg.IstioPublishMeshOnlyHosts,
+		IstioGatewayAnyNamespace:       cfg.IstioGatewayAnyNamespace,
+		CiliumLoadBalancerMode:         cfg.CiliumLoadBalancerMode,
+		CiliumNamespace:                cfg.CiliumNamespace,
+		TraefikDisableLegacy:           cfg.TraefikDisableLegacy,
+		TraefikDisableTCP:              cfg.TraefikDisableTCP,
+		TraefikDisableUDP:              cfg.TraefikDisableUDP,
+		TraefikService:                 cfg.TraefikService,
+		KongProxyService:               cfg.KongProxyService,
+		AmbassadorService:              cfg.AmbassadorService,
+		FileSourcePath:                 cfg.FileSourcePath,
+		FileSourceConfigMap:            cfg.FileSourceConfigMap,
+		HTTPSourceURL:                  cfg.HTTPSourceURL,
+		HTTPSourceBearerTokenFile:      cfg.HTTPSourceBearerTokenFile,
+		KnativeRoutes:                  cfg.KnativeRoutes,
+		KnativeIngressService:          cfg.KnativeIngressService,
+		RequestTimeout:                 cfg.RequestTimeout,
+		DefaultTargets:                 cfg.DefaultTargets,
+		OCPRouterName:                  cfg.OCPRouterName,
+		UpdateEvents:                   cfg.UpdateEvents,
+		ResolveLoadBalancerHostname:    cfg.ResolveServiceLoadBalancerHostname,
+		ServiceTopologyHostnames:       cfg.ServiceTopologyHostnames,
+		ServiceImportNaming:            cfg.ServiceImportNaming,
+		ClusterSetDomain:               cfg.ClusterSetDomain,
+		InformerFactories:              source.NewInformerFactories(),
+	}
+
+	// Lookup all the selected sources by names and pass them the desired configuration.
+	sources, err := source.ByNames(ctx, clientGenerator, cfg.Sources, sourceCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The sources reporting the outcome of the synchronizations, e.g. to the status of DNSEndpoints, are notified
+	// unless nothing is actually applied.
+	var syncReporters []source.SyncReporter
+	if !cfg.DryRun {
+		for _, s := range sources {
+			if reporter, ok := s.(source.SyncReporter); ok {
+				syncReporters = append(syncReporters, reporter)
+			}
+		}
+	}
+
+	// error is explicitly ignored because the intervals are already validated in validation.ValidateConfig
+	sourceIntervals, _ := externaldns.ParseSourceIntervals(cfg.SourceIntervals)
+	for i, name := range cfg.Sources {
+		if interval, ok := sourceIntervals[name]; ok {
+			sources[i] = source.NewIntervalSource(sources[i], interval)
+		}
+		if cfg.SourceMaxStaleness > 0 {
+			sources[i] = source.NewTolerantSource(sources[i], name, cfg.SourceMaxStaleness)
+		}
+	}
+
+	// Read the resources of the member clusters with the same sources.
+	if len(cfg.MemberClusters) > 0 {
+		// error is explicitly ignored because the member clusters are already validated in validation.ValidateConfig
+		members, _ := source.ParseMemberClusters(cfg.MemberClusters)
+		memberSources, err := source.NewMemberClusterSources(ctx, members, cfg.Sources, sourceCfg, clientGenerator.RequestTimeout)
+		if err != nil {
+			return nil, nil, err
+		}
+		sources = append(sources, memberSources...)
+	}
+
+	// Filter targets
+	targetFilter := endpoint.NewTargetNetFilterWithExclusions(cfg.TargetNetFilter, cfg.ExcludeTargetNets)
+
+	// Combine multiple sources into a single, deduplicated source.
+	endpointsSource := source.NewDedupSource(source.NewMultiSource(sources, sourceCfg.DefaultTargets, cfg.SourceConflictPolicy))
+	if cfg.FlattenCNAMEs {
+		endpointsSource = source.NewFlattenSource(endpointsSource)
+	}
+	if cfg.DNSRewrites {
+		client, err := clientGenerator.DynamicKubernetesClient()
+		if err != nil {
+			return nil, nil, err
+		}
+		endpointsSource, err = source.NewRewriteSource(ctx, client, endpointsSource)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.TransformationsFile != "" {
+		rules, err := source.LoadTransformationRules(cfg.TransformationsFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		endpointsSource, err = source.NewTransformSource(endpointsSource, rules)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.EndpointFilter != "" {
+		endpointsSource, err = source.NewExpressionFilterSource(endpointsSource, cfg.EndpointFilter)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(cfg.NAT64Networks) > 0 || cfg.NAT64SuppressA {
+		// error is explicitly ignored because the prefixes are already validated in validation.ValidateConfig
+		nat64Prefixes, _ := source.ParseNAT64Prefixes(cfg.NAT64Networks)
+		endpointsSource = source.NewNAT64Source(endpointsSource, nat64Prefixes, cfg.NAT64SuppressA)
+	}
+	endpointsSource = source.NewTargetFilterSource(endpointsSource, targetFilter)
+	if cfg.ClusterID != "" {
+		endpointsSource = source.NewClusterSource(endpointsSource, cfg.ClusterID)
+	}
+
+	return endpointsSource, syncReporters, nil
+}
+
+// newDomainFilter creates the domain filter of the configuration; RegexDomainFilter overrides DomainFilter.
+func newDomainFilter(cfg *externaldns.Config) endpoint.DomainFilter {
+	if cfg.RegexDomainFilter.String() != "" {
+		return endpoint.NewRegexDomainFilter(cfg.RegexDomainFilter, cfg.RegexDomainExclusion)
+	}
+	return endpoint.NewDomainFilterWithExclusions(cfg.DomainFilter, cfg.ExcludeDomains)
+}
+
+// configReloadInterval is how often the config file is compared with the one loaded last.
+const configReloadInterval = 10 * time.Second
+
+// watchConfigFile rebuilds the sources and the domain filter of the controller when the config file changes or on
+// SIGHUP, keeping the provider and the registry. The current ones are kept when the configuration is invalid.
+func watchConfigFile(ctx context.Context, cfg *externaldns.Config, ctrl *controller.Controller, clientGenerator *source.SingletonClientGenerator, cancelSources context.CancelFunc) {
+	watcher := &reload.Watcher{File: cfg.ConfigFile, Interval: configReloadInterval, Reload: func() {
+		newCfg := externaldns.NewConfig()
+		if err := newCfg.ParseFlags(os.Args[1:]); err != nil {
+			log.Errorf("Failed to reload the configuration, keeping the current one: %v", err)
+			return
+		}
+		if err := validation.ValidateConfig(newCfg); err != nil {
+			log.Errorf("Failed to reload the configuration, keeping the current one: %v", err)
+			return
+		}
+		sourcesCtx, cancel := context.WithCancel(ctx)
+		endpointsSource, syncReporters, err := newEndpointsSource(sourcesCtx, newCfg, clientGenerator)
+		if err != nil {
+			cancel()
+			log.Errorf("Failed to rebuild the sources, keeping the current ones: %v", err)
+			return
+		}
+		ctrl.Reconfigure(endpointsSource, newDomainFilter(newCfg), syncReporters)
+		cancelSources()
+		cancelSources = cancel
+		if newCfg.UpdateEvents {
+			endpointsSource.AddEventHandler(sourcesCtx, func() { ctrl.ScheduleRunOnce(time.Now()) })
+		}
+		ctrl.TriggerRunNow(time.Now())
+		log.Infof("Reloaded config: %s", newCfg)
+	}}
+	watcher.Run(ctx)
+}
+
+// newSnapshotStore creates the store of the registry cache snapshot selected by the configuration, if any.
+func newSnapshotStore(cfg *externaldns.Config, clientGenerator source.ClientGenerator) (registry.SnapshotStore, error) {
+	switch {
+	case cfg.RegistryCacheSnapshotFile != "":
+		return registry.NewFileSnapshotStore(cfg.RegistryCacheSnapshotFile), nil
+	case cfg.RegistryCacheSnapshotConfigMap != "":
+		client, err := clientGenerator.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		// the format is already validated in validation.ValidateConfig
+		namespace, name, _ := strings.Cut(cfg.RegistryCacheSnapshotConfigMap, "/")
+		return registry.NewConfigMapSnapshotStore(client, namespace, name), nil
+	default:
+		return nil, nil
+	}
+}
+
+// newAuditSink creates the audit sinks selected by the configuration.
+func newAuditSink(cfg *externaldns.Config, clientGenerator source.ClientGenerator) (audit.Sink, error) {
+	sinks := make(audit.MultiSink, 0, len(cfg.AuditSinks))
+	for _, name := range cfg.AuditSinks {
+		switch name {
+		case "file":
+			sink, err := audit.NewFileSink(cfg.AuditFile)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "webhook":
+			sinks = append(sinks, audit.NewWebhookSink(cfg.AuditWebhookURL))
+		case "events":
+			client, err := clientGenerator.KubeClient()
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, audit.NewEventSink(client))
+		default:
+			return nil, fmt.Errorf("unknown audit sink: %s", name)
+		}
+	}
+	return sinks, nil
+}
+
+// weightProperty returns the provider specific property holding the weights of weighted records,
+// if the provider supports them.
+func weightProperty(providerName string) string {
+	switch providerName {
+	case "aws":
+		return "aws/weight"
+	default:
+		return ""
+	}
+}
+
+// newRegistry creates the registry with the given name on top of the provider.
+func newRegistry(name string, p provider.Provider, cfg *externaldns.Config, awsSession *session.Session) (registry.Registry, error) {
+	switch name {
+	case "dynamodb":
+		config := awsSDK.NewConfig()
+		if cfg.AWSDynamoDBRegion != "" {
+			config = config.WithRegion(cfg.AWSDynamoDBRegion)
+		}
+		return registry.NewDynamoDBRegistry(p, cfg.TXTOwnerID, dynamodb.New(awsSession, config), cfg.AWSDynamoDBTable, cfg.TXTPrefix, cfg.TXTSuffix, cfg.TXTWildcardReplacement, cfg.ManagedDNSRecordTypes, cfg.ExcludeDNSRecordTypes, []byte(cfg.TXTEncryptAESKey), cfg.TXTCacheInterval, cfg.AWSDynamoDBGCGracePeriod)
+	case "noop":
+		return registry.NewNoopRegistry(p)
+	case "txt":
+		decryptAESKeys := make([][]byte, 0, len(cfg.TXTDecryptAESKeys))
+		for _, key := range cfg.TXTDecryptAESKeys {
+			decryptAESKeys = append(decryptAESKeys, []byte(key))
+		}
+		return registry.NewTXTRegistry(p, cfg.TXTPrefix, cfg.TXTSuffix, cfg.TXTOwnerID, cfg.TXTCacheInterval, cfg.TXTWildcardReplacement, cfg.ManagedDNSRecordTypes, cfg.ExcludeDNSRecordTypes, cfg.TXTEncryptEnabled, []byte(cfg.TXTEncryptAESKey), decryptAESKeys...)
+	case "aws-sd":
+		return registry.NewAWSSDRegistry(p.(*awssd.AWSSDProvider), cfg.TXTOwnerID)
+	}
+	return nil, fmt.Errorf("unknown registry: %s", name)
+}
+
+// runPreflight verifies that the provider can change the records of its zones, retrying at the
+// given interval until it succeeds. The readiness endpoint fails until then.
+func runPreflight(ctx context.Context, p provider.Preflighter, interval time.Duration, checks *readiness.Checks) {
+	for {
+		err := p.Preflight(ctx)
+		if ctx.Err() != nil {
+			// the preflight was superseded, e.g. by the one of reloaded credentials
+			return
+		}
+		checks.Set("preflight", err)
+		if err == nil {
+			log.Info("Preflight succeeded, the records of all zones can be changed")
+			return
+		}
+		log.Errorf("Preflight failed, retrying in %s: %v", interval, err)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// watchCredentialsFile runs the preflight, and runs it again when the credentials file changes or on SIGHUP,
+// so that credentials losing their permissions fail readiness before the next change does.
+func watchCredentialsFile(ctx context.Context, file string, p provider.Preflighter, interval time.Duration, checks *readiness.Checks) {
+	preflightCtx, cancel := context.WithCancel(ctx)
+	go runPreflight(preflightCtx, p, interval, checks)
+	watcher := &reload.Watcher{File: file, Interval: configReloadInterval, Reload: func() {
+		cancel()
+		preflightCtx, cancel = context.WithCancel(ctx)
+		log.Info("The credentials changed, running the preflight again")
+		checks.Set("preflight", errors.New("not completed yet"))
+		go runPreflight(preflightCtx, p, interval, checks)
+	}}
+	watcher.Run(ctx)
+	cancel()
+}
+
+// runZoneReconciler ensures the hosted zones of the DNSZones at the given interval.
+func runZoneReconciler(ctx context.Context, r *dnszone.Reconciler, interval time.Duration) {
+	for {
+		if err := r.Reconcile(ctx); err != nil {
+			log.Errorf("Failed to manage DNSZones, retrying in %s: %v", interval, err)
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runDNSSECExport exports the DNSSEC delegation signers of the zones of the provider at the given interval.
+func runDNSSECExport(ctx context.Context, e *dnssec.FileExporter, interval time.Duration) {
+	for {
+		if err := e.Export(ctx); err != nil {
+			log.Errorf("Failed to export the DNSSEC delegation signers, retrying in %s: %v", interval, err)
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runTailscaleSplitDNS routes the managed domains to the name servers within the tailnet and
+// keeps reverting changes made to their split DNS configuration at the given interval.
+func runTailscaleSplitDNS(ctx context.Context, c *tailscale.Client, domains, nameservers []string, interval time.Duration, checks *readiness.Checks) {
+	for {
+		err := tailscale.EnsureSplitDNS(ctx, c, domains, nameservers)
+		checks.Set("tailscale", err)
+		if err != nil {
+			log.Errorf("Failed to configure the tailscale split DNS, retrying in %s: %v", interval, err)
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
 func handleSigterm(cancel func()) {
@@ -484,13 +1063,15 @@ func handleSigterm(cancel func()) {
 	cancel()
 }
 
-func serveMetrics(address string) {
+func serveMetrics(metricsCfg metricsserver.Config, readinessChecks *readiness.Checks) {
 	http.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
 
+	http.Handle("/readyz", readinessChecks)
+
 	http.Handle("/metrics", promhttp.Handler())
 
-	log.Fatal(http.ListenAndServe(address, nil))
+	log.Fatal(metricsserver.ListenAndServe(metricsCfg, http.DefaultServeMux))
 }
@@ -18,6 +18,7 @@ package externaldns
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -45,11 +46,31 @@ var Version = "unknown"
 type Config struct {
 	APIServerURL                       string
 	KubeConfig                         string
+	MemberClusters                     []string
 	RequestTimeout                     time.Duration
 	DefaultTargets                     []string
 	GlooNamespaces                     []string
 	SkipperRouteGroupVersion           string
+	IstioPublishMeshOnlyHosts          bool
+	IstioGatewayAnyNamespace           bool
+	CiliumLoadBalancerMode             string
+	CiliumNamespace                    string
+	TraefikDisableLegacy               bool
+	TraefikDisableTCP                  bool
+	TraefikDisableUDP                  bool
+	TraefikService                     string
+	KongProxyService                   string
+	AmbassadorService                  string
+	FileSourcePath                     string
+	FileSourceConfigMap                string
+	HTTPSourceURL                      string
+	HTTPSourceBearerTokenFile          string
+	KnativeRoutes                      bool
+	KnativeIngressService              string
 	Sources                            []string
+	SourceIntervals                    []string
+	SourceMaxStaleness                 time.Duration
+	SourceConflictPolicy               string
 	Namespace                          string
 	AnnotationFilter                   string
 	LabelFilter                        string
@@ -61,24 +82,48 @@ type Config struct {
 	IgnoreIngressRulesSpec             bool
 	GatewayNamespace                   string
 	GatewayLabelFilter                 string
+	GatewayEnvoyServices               bool
 	Compatibility                      string
 	PublishInternal                    bool
 	PublishHostIP                      bool
 	AlwaysPublishNotReadyAddresses     bool
+	NodeSSHFP                          bool
+	NodeSSHFPSecret                    string
+	NodeLabelFilter                    string
+	NodeExcludeNotReady                bool
+	NodeExcludeUnschedulable           bool
+	NodeIPv4AddressType                string
+	NodeIPv6AddressType                string
+	PodLabelSelector                   string
+	PodPublishPodIP                    bool
 	ConnectorSourceServer              string
+	ConnectorSourceTLS                 bool
+	ConnectorSourceTLSCA               string
+	ConnectorSourceTLSCert             string
+	ConnectorSourceTLSKey              string
+	ConnectorSourceTokenFile           string
 	Provider                           string
 	GoogleProject                      string
 	GoogleBatchChangeSize              int
 	GoogleBatchChangeInterval          time.Duration
 	GoogleZoneVisibility               string
+	GoogleZoneWorkers                  int
 	DomainFilter                       []string
 	ExcludeDomains                     []string
 	RegexDomainFilter                  *regexp.Regexp
 	RegexDomainExclusion               *regexp.Regexp
 	ZoneNameFilter                     []string
 	ZoneIDFilter                       []string
+	ZoneTagFilter                      []string
 	TargetNetFilter                    []string
 	ExcludeTargetNets                  []string
+	DNSRewrites                        bool
+	TransformationsFile                string
+	ConfigFile                         string
+	EndpointFilter                     string
+	NAT64Networks                      []string
+	NAT64SuppressA                     bool
+	FlattenCNAMEs                      bool
 	AlibabaCloudConfigFile             string
 	AlibabaCloudZoneType               string
 	AWSZoneType                        string
@@ -87,13 +132,17 @@ type Config struct {
 	AWSAssumeRoleExternalID            string
 	AWSBatchChangeSize                 int
 	AWSBatchChangeInterval             time.Duration
+	AWSZoneWorkers                     int
+	AWSZoneChangeRate                  float64
 	AWSEvaluateTargetHealth            bool
+	AWSHealthChecks                    bool
 	AWSAPIRetries                      int
 	AWSPreferCNAME                     bool
 	AWSZoneCacheDuration               time.Duration
 	AWSSDServiceCleanup                bool
 	AWSDynamoDBRegion                  string
 	AWSDynamoDBTable                   string
+	AWSDynamoDBGCGracePeriod           time.Duration
 	AzureConfigFile                    string
 	AzureResourceGroup                 string
 	AzureSubscriptionID                string
@@ -108,6 +157,8 @@ type Config struct {
 	BluecatSkipTLSVerify               bool
 	CloudflareProxied                  bool
 	CloudflareDNSRecordsPerPage        int
+	CloudflareTunnelID                 string
+	CloudflareTunnelCredentialsFile    string
 	CoreDNSPrefix                      string
 	RcodezeroTXTEncrypt                bool
 	AkamaiServiceConsumerDomain        string
@@ -147,21 +198,78 @@ type Config struct {
 	TLSClientCert                      string
 	TLSClientCertKey                   string
 	Policy                             string
+	TargetNormalizations               []string
+	MetadataSensitiveDiff              bool
+	IgnoreTTLDifferences               bool
+	MinTTL                             time.Duration
+	DefaultTTLs                        []string
+	DeletionGracePeriod                time.Duration
+	WildcardPolicy                     string
+	ChangeWindows                      []string
+	ChangeWindowScope                  string
+	PlanApproval                       bool
+	PlanApprovalNamespace              string
+	PlanApprovalExpiry                 time.Duration
+	DNSPolicies                        bool
+	GitExportRepository                string
+	GitExportBranch                    string
+	GitExportPath                      string
+	GitExportFormat                    string
+	GitExportAuthor                    string
+	GitExportDir                       string
+	NotifyWebhookURL                   string
+	NotifyFormat                       string
+	NotifyZones                        []string
+	NotifyMinChanges                   int
+	VerifyResolvers                    []string
+	VerifyTimeout                      time.Duration
+	VerifyRetries                      int
+	VerifyInterval                     time.Duration
+	ManageZones                        bool
+	ManageZonesNamespace               string
+	DNSSECExportFile                   string
+	FQDNPolicy                         string
+	AuditSinks                         []string
+	AuditFile                          string
+	AuditWebhookURL                    string
 	Registry                           string
+	SecondaryRegistry                  string
 	TXTOwnerID                         string
+	TXTOwnerIDTemplate                 string
+	ClusterID                          string
 	TXTPrefix                          string
 	TXTSuffix                          string
 	TXTEncryptEnabled                  bool
-	TXTEncryptAESKey                   string `secure:"yes"`
+	TXTEncryptAESKey                   string   `secure:"yes"`
+	TXTDecryptAESKeys                  []string `secure:"yes"`
 	Interval                           time.Duration
 	MinEventSyncInterval               time.Duration
+	FullReconcileInterval              time.Duration
+	DrainTimeout                       time.Duration
+	PendingChangesFile                 string
 	Once                               bool
 	DryRun                             bool
+	ValidateDryRun                     bool
+	Preflight                          bool
+	PreflightCredentialsFile           string
 	UpdateEvents                       bool
 	LogFormat                          string
 	MetricsAddress                     string
+	MetricsTLSCertFile                 string
+	MetricsTLSKeyFile                  string
+	MetricsTLSClientCAFile             string
+	MetricsBearerTokenFile             string
+	SyncEndpoint                       bool
+	DebugRecordsEndpoint               bool
+	DebugStatusEndpoint                bool
+	TriggerSync                        string
+	TriggerSyncCAFile                  string
+	ReadinessMaxSyncIntervals          int
 	LogLevel                           string
 	TXTCacheInterval                   time.Duration
+	RegistryCacheInterval              time.Duration
+	RegistryCacheSnapshotFile          string
+	RegistryCacheSnapshotConfigMap     string
 	TXTWildcardReplacement             string
 	ExoscaleEndpoint                   string
 	ExoscaleAPIKey                     string `secure:"yes"`
@@ -170,11 +278,16 @@ type Config struct {
 	ExoscaleAPIZone                    string
 	CRDSourceAPIVersion                string
 	CRDSourceKind                      string
+	CRDSourceNamespaces                []string
+	CRDSourceLabelFilter               string
 	ServiceTypeFilter                  []string
+	ServiceImportNaming                string
+	ClusterSetDomain                   string
 	CFAPIEndpoint                      string
 	CFUsername                         string
 	CFPassword                         string
 	ResolveServiceLoadBalancerHostname bool
+	ServiceTopologyHostnames           bool
 	RFC2136Host                        string
 	RFC2136Port                        int
 	RFC2136Zone                        []string
@@ -215,16 +328,39 @@ type Config struct {
 	WebhookProviderReadTimeout         time.Duration
 	WebhookProviderWriteTimeout        time.Duration
 	WebhookServer                      bool
+	TailscaleTailnet                   string
+	TailscaleAPIKey                    string `secure:"yes"`
+	TailscaleNameservers               []string
 }
 
 var defaultConfig = &Config{
 	APIServerURL:                "",
 	KubeConfig:                  "",
+	MemberClusters:              []string{},
 	RequestTimeout:              time.Second * 30,
 	DefaultTargets:              []string{},
 	GlooNamespaces:              []string{"gloo-system"},
 	SkipperRouteGroupVersion:    "zalando.org/v1",
+	IstioPublishMeshOnlyHosts:   false,
+	IstioGatewayAnyNamespace:    false,
+	CiliumLoadBalancerMode:      "",
+	CiliumNamespace:             "kube-system",
+	TraefikDisableLegacy:        false,
+	TraefikDisableTCP:           false,
+	TraefikDisableUDP:           false,
+	TraefikService:              "",
+	KongProxyService:            "",
+	AmbassadorService:           "",
+	FileSourcePath:              "",
+	FileSourceConfigMap:         "",
+	HTTPSourceURL:               "",
+	HTTPSourceBearerTokenFile:   "",
+	KnativeRoutes:               false,
+	KnativeIngressService:       "",
 	Sources:                     nil,
+	SourceIntervals:             []string{},
+	SourceMaxStaleness:          0,
+	SourceConflictPolicy:        source.ConflictPolicyNone,
 	Namespace:                   "",
 	AnnotationFilter:            "",
 	LabelFilter:                 labels.Everything().String(),
@@ -236,22 +372,46 @@ var defaultConfig = &Config{
 	IgnoreIngressRulesSpec:      false,
 	GatewayNamespace:            "",
 	GatewayLabelFilter:          "",
+	GatewayEnvoyServices:        false,
 	Compatibility:               "",
 	PublishInternal:             false,
 	PublishHostIP:               false,
+	NodeSSHFP:                   false,
+	NodeSSHFPSecret:             "",
+	NodeLabelFilter:             "",
+	NodeExcludeNotReady:         false,
+	ServiceTopologyHostnames:    false,
+	NodeExcludeUnschedulable:    false,
+	NodeIPv4AddressType:         "",
+	NodeIPv6AddressType:         "",
+	PodLabelSelector:            "",
+	PodPublishPodIP:             false,
 	ConnectorSourceServer:       "localhost:8080",
+	ConnectorSourceTLS:          false,
+	ConnectorSourceTLSCA:        "",
+	ConnectorSourceTLSCert:      "",
+	ConnectorSourceTLSKey:       "",
+	ConnectorSourceTokenFile:    "",
 	Provider:                    "",
 	GoogleProject:               "",
 	GoogleBatchChangeSize:       1000,
 	GoogleBatchChangeInterval:   time.Second,
 	GoogleZoneVisibility:        "",
+	GoogleZoneWorkers:           1,
 	DomainFilter:                []string{},
 	ZoneIDFilter:                []string{},
+	ZoneTagFilter:               []string{},
 	ExcludeDomains:              []string{},
 	RegexDomainFilter:           regexp.MustCompile(""),
 	RegexDomainExclusion:        regexp.MustCompile(""),
 	TargetNetFilter:             []string{},
 	ExcludeTargetNets:           []string{},
+	DNSRewrites:                 false,
+	TransformationsFile:         "",
+	ConfigFile:                  "",
+	EndpointFilter:              "",
+	NAT64Networks:               []string{},
+	NAT64SuppressA:              false,
 	AlibabaCloudConfigFile:      "/etc/kubernetes/alibaba-cloud.json",
 	AWSZoneType:                 "",
 	AWSZoneTagFilter:            []string{},
@@ -259,13 +419,17 @@ var defaultConfig = &Config{
 	AWSAssumeRoleExternalID:     "",
 	AWSBatchChangeSize:          1000,
 	AWSBatchChangeInterval:      time.Second,
+	AWSZoneWorkers:              1,
+	AWSZoneChangeRate:           0,
 	AWSEvaluateTargetHealth:     true,
+	AWSHealthChecks:             false,
 	AWSAPIRetries:               3,
 	AWSPreferCNAME:              false,
 	AWSZoneCacheDuration:        0 * time.Second,
 	AWSSDServiceCleanup:         false,
 	AWSDynamoDBRegion:           "",
 	AWSDynamoDBTable:            "external-dns",
+	AWSDynamoDBGCGracePeriod:    0,
 	AzureConfigFile:             "/etc/kubernetes/azure.json",
 	AzureResourceGroup:          "",
 	AzureSubscriptionID:         "",
@@ -273,6 +437,7 @@ var defaultConfig = &Config{
 	BluecatDNSDeployType:        "no-deploy",
 	CloudflareProxied:           false,
 	CloudflareDNSRecordsPerPage: 100,
+	CloudflareTunnelID:          "",
 	CoreDNSPrefix:               "/skydns/",
 	RcodezeroTXTEncrypt:         false,
 	AkamaiServiceConsumerDomain: "",
@@ -305,18 +470,61 @@ var defaultConfig = &Config{
 	TLSClientCert:               "",
 	TLSClientCertKey:            "",
 	Policy:                      "sync",
+	TargetNormalizations:        []string{"case"},
+	MetadataSensitiveDiff:       false,
+	IgnoreTTLDifferences:        false,
+	MinTTL:                      0,
+	DefaultTTLs:                 []string{},
+	DeletionGracePeriod:         0,
+	WildcardPolicy:              "allow",
+	ChangeWindows:               []string{},
+	ChangeWindowScope:           "all",
+	PlanApproval:                false,
+	PlanApprovalNamespace:       "default",
+	PlanApprovalExpiry:          time.Hour,
+	DNSPolicies:                 false,
+	GitExportRepository:         "",
+	GitExportBranch:             "main",
+	GitExportPath:               "records.yaml",
+	GitExportFormat:             "yaml",
+	GitExportAuthor:             "ExternalDNS <external-dns@localhost>",
+	GitExportDir:                "",
+	NotifyWebhookURL:            "",
+	NotifyFormat:                "generic",
+	NotifyZones:                 []string{},
+	NotifyMinChanges:            1,
+	VerifyResolvers:             []string{},
+	VerifyTimeout:               2 * time.Second,
+	VerifyRetries:               5,
+	VerifyInterval:              5 * time.Second,
+	ManageZones:                 false,
+	ManageZonesNamespace:        "",
+	DNSSECExportFile:            "",
+	FQDNPolicy:                  "preserve",
+	AuditSinks:                  []string{},
+	AuditFile:                   "",
+	AuditWebhookURL:             "",
 	Registry:                    "txt",
+	SecondaryRegistry:           "",
 	TXTOwnerID:                  "default",
+	TXTOwnerIDTemplate:          "",
 	TXTPrefix:                   "",
 	TXTSuffix:                   "",
 	TXTCacheInterval:            0,
+	RegistryCacheInterval:       0,
 	TXTWildcardReplacement:      "",
 	MinEventSyncInterval:        5 * time.Second,
+	FullReconcileInterval:       0,
+	DrainTimeout:                20 * time.Second,
+	PendingChangesFile:          "",
 	TXTEncryptEnabled:           false,
 	TXTEncryptAESKey:            "",
+	TXTDecryptAESKeys:           []string{},
 	Interval:                    time.Minute,
 	Once:                        false,
 	DryRun:                      false,
+	Preflight:                   false,
+	PreflightCredentialsFile:    "",
 	UpdateEvents:                false,
 	LogFormat:                   "text",
 	MetricsAddress:              ":7979",
@@ -327,7 +535,11 @@ var defaultConfig = &Config{
 	ExoscaleAPISecret:           "",
 	CRDSourceAPIVersion:         "externaldns.k8s.io/v1alpha1",
 	CRDSourceKind:               "DNSEndpoint",
+	CRDSourceNamespaces:         nil,
+	CRDSourceLabelFilter:        "",
 	ServiceTypeFilter:           []string{},
+	ServiceImportNaming:         "clusterset",
+	ClusterSetDomain:            "clusterset.local",
 	CFAPIEndpoint:               "",
 	CFUsername:                  "",
 	CFPassword:                  "",
@@ -384,12 +596,18 @@ func (cfg *Config) String() string {
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
 		if val, ok := f.Tag.Lookup("secure"); ok && val == "yes" {
-			if f.Type.Kind() != reflect.String {
-				continue
-			}
 			v := reflect.ValueOf(&temp).Elem().Field(i)
-			if v.String() != "" {
-				v.SetString(passwordMask)
+			switch {
+			case f.Type.Kind() == reflect.String:
+				if v.String() != "" {
+					v.SetString(passwordMask)
+				}
+			case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.String:
+				masked := make([]string, v.Len())
+				for j := range masked {
+					masked[j] = passwordMask
+				}
+				v.Set(reflect.ValueOf(masked))
 			}
 		}
 	}
@@ -397,6 +615,46 @@ func (cfg *Config) String() string {
 	return fmt.Sprintf("%+v", temp)
 }
 
+// ParseSourceIntervals parses the source intervals given as "<source>=<duration>", e.g. "node=10m".
+func ParseSourceIntervals(values []string) (map[string]time.Duration, error) {
+	intervals := make(map[string]time.Duration, len(values))
+	for _, value := range values {
+		name, duration, found := strings.Cut(value, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid source interval %q, expected <source>=<duration>", value)
+		}
+		interval, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source interval %q: %w", value, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid source interval %q: must be positive", value)
+		}
+		intervals[name] = interval
+	}
+	return intervals, nil
+}
+
+// ParseDefaultTTLs parses values in the form <record type>=<duration> into a map of default TTLs by record type.
+func ParseDefaultTTLs(values []string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration, len(values))
+	for _, value := range values {
+		recordType, duration, found := strings.Cut(value, "=")
+		if !found || recordType == "" {
+			return nil, fmt.Errorf("invalid default TTL %q, expected <record type>=<duration>", value)
+		}
+		ttl, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default TTL %q: %w", value, err)
+		}
+		if ttl < time.Second {
+			return nil, fmt.Errorf("invalid default TTL %q: must be at least 1s", value)
+		}
+		ttls[strings.ToUpper(recordType)] = ttl
+	}
+	return ttls, nil
+}
+
 // allLogLevelsAsStrings returns all logrus levels as a list of strings
 func allLogLevelsAsStrings() []string {
 	var levels []string
@@ -406,8 +664,89 @@ func allLogLevelsAsStrings() []string {
 	return levels
 }
 
-// ParseFlags adds and parses flags from command line
+// ParseFlags adds and parses flags from command line, followed by the flags of the config file if one is given.
+// The flags given on the command line take precedence over the same flags in the config file.
 func (cfg *Config) ParseFlags(args []string) error {
+	fileArgs, err := configFileArgs(args)
+	if err != nil {
+		return err
+	}
+	return cfg.parseFlags(append(args[:len(args):len(args)], fileArgs...))
+}
+
+// configFileArgs returns the flags of the config file given on the command line, if any, leaving out the flags which
+// are also given on the command line, so that a flag is taken either from the command line or from the config file.
+func configFileArgs(args []string) ([]string, error) {
+	app := (&Config{}).newApp()
+	cliContext, err := app.ParseContext(args)
+	if err != nil {
+		// reported when parsing the command line
+		return nil, nil
+	}
+	configFile := os.Getenv(app.GetFlag("config-file").Model().Envar)
+	cliFlags := map[string]bool{}
+	for _, element := range cliContext.Elements {
+		if flag, ok := element.Clause.(*kingpin.FlagClause); ok {
+			cliFlags[flag.Model().Name] = true
+			if flag.Model().Name == "config-file" {
+				configFile = *element.Value
+			}
+		}
+	}
+	if configFile == "" {
+		return nil, nil
+	}
+	fileArgs, err := ReadConfigFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	fileContext, err := app.ParseContext(fileArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the config file: %w", err)
+	}
+	var merged []string
+	for _, element := range fileContext.Elements {
+		flag, ok := element.Clause.(*kingpin.FlagClause)
+		if !ok || cliFlags[flag.Model().Name] {
+			continue
+		}
+		switch {
+		case !flag.Model().IsBoolFlag():
+			merged = append(merged, "--"+flag.Model().Name+"="+*element.Value)
+		case *element.Value == "false":
+			merged = append(merged, "--no-"+flag.Model().Name)
+		default:
+			merged = append(merged, "--"+flag.Model().Name)
+		}
+	}
+	return merged, nil
+}
+
+// ReadConfigFile reads the flags of a config file, one per line, e.g. --domain-filter=example.org;
+// blank lines and lines starting with # are skipped.
+func ReadConfigFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %w", err)
+	}
+	var args []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		args = append(args, line)
+	}
+	return args, nil
+}
+
+func (cfg *Config) parseFlags(args []string) error {
+	_, err := cfg.newApp().Parse(args)
+	return err
+}
+
+// newApp creates the application with all the flags bound to the fields of the config.
+func (cfg *Config) newApp() *kingpin.Application {
 	app := kingpin.New("external-dns", "ExternalDNS synchronizes exposed Kubernetes Services and Ingresses with DNS providers.\n\nNote that all flags may be replaced with env vars - `--flag` -> `EXTERNAL_DNS_FLAG=1` or `--flag value` -> `EXTERNAL_DNS_FLAG=value`")
 	app.Version(Version)
 	app.DefaultEnvars()
@@ -415,8 +754,10 @@ func (cfg *Config) ParseFlags(args []string) error {
 	// Flags related to Kubernetes
 	app.Flag("server", "The Kubernetes API server to connect to (default: auto-detect)").Default(defaultConfig.APIServerURL).StringVar(&cfg.APIServerURL)
 	app.Flag("kubeconfig", "Retrieve target cluster configuration from a Kubernetes configuration file (default: auto-detect)").Default(defaultConfig.KubeConfig).StringVar(&cfg.KubeConfig)
+	app.Flag("member-cluster", "Also read the resources of the sources from a member cluster, in the form name=<name>,kubeconfig=<path>[,context=<context>]; the endpoints get the name as cluster label; specify multiple times for multiple clusters (optional)").StringsVar(&cfg.MemberClusters)
 	app.Flag("request-timeout", "Request timeout when calling Kubernetes APIs. 0s means no timeout").Default(defaultConfig.RequestTimeout.String()).DurationVar(&cfg.RequestTimeout)
 	app.Flag("resolve-service-load-balancer-hostname", "Resolve the hostname of LoadBalancer-type Service object to IP addresses in order to create DNS A/AAAA records instead of CNAMEs").BoolVar(&cfg.ResolveServiceLoadBalancerHostname)
+	app.Flag("service-topology-hostnames", "When enabled, the service source also publishes zone-scoped hostnames, e.g. svc.eu-west-1a.example.com for svc.example.com, pointing to the targets of the nodes and endpoints of each topology.kubernetes.io/zone (default: disabled)").BoolVar(&cfg.ServiceTopologyHostnames)
 
 	// Flags related to cloud foundry
 	app.Flag("cf-api-endpoint", "The fully-qualified domain name of the cloud foundry instance you are targeting").Default(defaultConfig.CFAPIEndpoint).StringVar(&cfg.CFAPIEndpoint)
@@ -426,15 +767,41 @@ func (cfg *Config) ParseFlags(args []string) error {
 	// Flags related to Gloo
 	app.Flag("gloo-namespace", "The Gloo Proxy namespace; specify multiple times for multiple namespaces. (default: gloo-system)").Default("gloo-system").StringsVar(&cfg.GlooNamespaces)
 
+	// Flags related to Istio
+	app.Flag("istio-publish-mesh-only-hosts", "Publish the hosts of the Istio VirtualServices bound only to the mesh gateway, or not exported to the namespace of any of their gateways (default: disabled)").BoolVar(&cfg.IstioPublishMeshOnlyHosts)
+	app.Flag("istio-gateway-any-namespace", "Look up the Services implementing Istio Gateways, which match their selectors, in all namespaces, not only in the namespace of --namespace (default: disabled)").BoolVar(&cfg.IstioGatewayAnyNamespace)
+
+	// Flags related to Cilium
+	app.Flag("cilium-loadbalancer-mode", "Resolve the targets of the Ingresses of the cilium class from the load balancer Services of Cilium, in this mode unless an Ingress sets another with the ingress.cilium.io/loadbalancer-mode annotation (optional, options: dedicated, shared)").Default(defaultConfig.CiliumLoadBalancerMode).EnumVar(&cfg.CiliumLoadBalancerMode, "", source.CiliumLoadBalancerModeDedicated, source.CiliumLoadBalancerModeShared)
+	app.Flag("cilium-namespace", "The namespace of Cilium, where the Service shared by the Ingresses is").Default(defaultConfig.CiliumNamespace).StringVar(&cfg.CiliumNamespace)
+
 	// Flags related to Skipper RouteGroup
 	app.Flag("skipper-routegroup-groupversion", "The resource version for skipper routegroup").Default(source.DefaultRoutegroupVersion).StringVar(&cfg.SkipperRouteGroupVersion)
 
+	// Flags related to Traefik
+	app.Flag("traefik-disable-legacy", "Don't watch the IngressRoutes of the legacy traefik.containo.us API group of Traefik v2, only those of the traefik.io API group (default: disabled)").BoolVar(&cfg.TraefikDisableLegacy)
+	app.Flag("traefik-disable-tcp", "Don't watch the IngressRouteTCPs, so that no access to them is needed (default: disabled)").BoolVar(&cfg.TraefikDisableTCP)
+	app.Flag("traefik-disable-udp", "Don't watch the IngressRouteUDPs, so that no access to them is needed (default: disabled)").BoolVar(&cfg.TraefikDisableUDP)
+	app.Flag("traefik-service", "The Service of Traefik whose load balancer addresses are the targets of the routes without a target annotation, in the form <namespace>/<name>; the traefik-service annotation overrides it per route (optional)").Default(defaultConfig.TraefikService).StringVar(&cfg.TraefikService)
+	app.Flag("kong-proxy-service", "The Kong proxy Service whose load balancer addresses are the targets of the TCPIngresses and UDPIngresses without a target annotation, in the form <namespace>/<name>; the kong-proxy-service annotation overrides it per resource (optional)").Default(defaultConfig.KongProxyService).StringVar(&cfg.KongProxyService)
+	app.Flag("ambassador-service", "The Emissary/Ambassador Service whose load balancer addresses are the targets of the Hosts without external-dns.ambassador-service annotation, in the form <namespace>/<name>; the annotation overrides it per Host (optional)").Default(defaultConfig.AmbassadorService).StringVar(&cfg.AmbassadorService)
+
+	// Flags related to the file source
+	app.Flag("file-source-path", "When using the file source, a YAML or JSON file, e.g. mounted from a ConfigMap, holding a list of endpoints or a DNSEndpoint spec").Default(defaultConfig.FileSourcePath).StringVar(&cfg.FileSourcePath)
+	app.Flag("file-source-configmap", "When using the file source, a ConfigMap, as <namespace>/<name>, whose keys hold lists of endpoints or DNSEndpoint specs, instead of --file-source-path").Default(defaultConfig.FileSourceConfigMap).StringVar(&cfg.FileSourceConfigMap)
+
+	// Flags related to the http source
+	app.Flag("http-source-url", "When using the http source, the HTTP(S) URL returning a JSON list of endpoints or a DNSEndpoint spec").Default(defaultConfig.HTTPSourceURL).StringVar(&cfg.HTTPSourceURL)
+	app.Flag("http-source-bearer-token-file", "When using the http source, a file holding the bearer token authenticating the requests to --http-source-url, read for every request (optional)").Default(defaultConfig.HTTPSourceBearerTokenFile).StringVar(&cfg.HTTPSourceBearerTokenFile)
+	app.Flag("knative-routes", "Also publish the URLs of the Knative Routes with the knative-domainmapping source (default: disabled)").BoolVar(&cfg.KnativeRoutes)
+	app.Flag("knative-ingress-service", "The Knative ingress Service whose load balancer addresses are the targets of the DomainMappings and Routes without a target annotation, in the form <namespace>/<name>; the knative-ingress-service annotation overrides it per resource (optional)").Default(defaultConfig.KnativeIngressService).StringVar(&cfg.KnativeIngressService)
+
 	// Flags related to processing source
-	app.Flag("source", "The resource types that are queried for endpoints; specify multiple times for multiple sources (required, options: service, ingress, node, pod, fake, connector, gateway-httproute, gateway-grpcroute, gateway-tlsroute, gateway-tcproute, gateway-udproute, istio-gateway, istio-virtualservice, cloudfoundry, contour-httpproxy, gloo-proxy, crd, empty, skipper-routegroup, openshift-route, ambassador-host, kong-tcpingress, f5-virtualserver, traefik-proxy)").Required().PlaceHolder("source").EnumsVar(&cfg.Sources, "service", "ingress", "node", "pod", "gateway-httproute", "gateway-grpcroute", "gateway-tlsroute", "gateway-tcproute", "gateway-udproute", "istio-gateway", "istio-virtualservice", "cloudfoundry", "contour-httpproxy", "gloo-proxy", "fake", "connector", "crd", "empty", "skipper-routegroup", "openshift-route", "ambassador-host", "kong-tcpingress", "f5-virtualserver", "traefik-proxy")
-	app.Flag("openshift-router-name", "if source is openshift-route then you can pass the ingress controller name. Based on this name external-dns will select the respective router from the route status and map that routerCanonicalHostname to the route host while creating a CNAME record.").StringVar(&cfg.OCPRouterName)
+	app.Flag("source", "The resource types that are queried for endpoints; specify multiple times for multiple sources (required, options: service, ingress, node, pod, fake, connector, gateway-httproute, gateway-grpcroute, gateway-tlsroute, gateway-tcproute, gateway-udproute, istio-gateway, istio-virtualservice, cloudfoundry, contour-httpproxy, gloo-proxy, crd, empty, skipper-routegroup, openshift-route, ambassador-host, kong-tcpingress, kong-udpingress, knative-domainmapping, argo-rollout, f5-virtualserver, f5-transportserver, traefik-proxy, service-import, file, http)").Required().PlaceHolder("source").EnumsVar(&cfg.Sources, "service", "ingress", "node", "pod", "gateway-httproute", "gateway-grpcroute", "gateway-tlsroute", "gateway-tcproute", "gateway-udproute", "istio-gateway", "istio-virtualservice", "cloudfoundry", "contour-httpproxy", "gloo-proxy", "fake", "connector", "crd", "empty", "skipper-routegroup", "openshift-route", "ambassador-host", "kong-tcpingress", "kong-udpingress", "knative-domainmapping", "argo-rollout", "f5-virtualserver", "f5-transportserver", "traefik-proxy", "service-import", "file", "http")
+	app.Flag("openshift-router-name", "if source is openshift-route then you can pass the ingress controller name. Based on this name external-dns will select the respective router from the route status and map that routerCanonicalHostname to the route host while creating a CNAME record. The routes not admitted by this router are skipped.").StringVar(&cfg.OCPRouterName)
 	app.Flag("namespace", "Limit resources queried for endpoints to a specific namespace (default: all namespaces)").Default(defaultConfig.Namespace).StringVar(&cfg.Namespace)
 	app.Flag("annotation-filter", "Filter resources queried for endpoints by annotation, using label selector semantics").Default(defaultConfig.AnnotationFilter).StringVar(&cfg.AnnotationFilter)
-	app.Flag("label-filter", "Filter resources queried for endpoints by label selector; currently supported by source types crd, gateway-httproute, gateway-grpcroute, gateway-tlsroute, gateway-tcproute, gateway-udproute, ingress, node, openshift-route, and service").Default(defaultConfig.LabelFilter).StringVar(&cfg.LabelFilter)
+	app.Flag("label-filter", "Filter resources queried for endpoints by label selector; currently supported by source types crd, gateway-httproute, gateway-grpcroute, gateway-tlsroute, gateway-tcproute, gateway-udproute, gloo-proxy, ingress, node, openshift-route, and service").Default(defaultConfig.LabelFilter).StringVar(&cfg.LabelFilter)
 	app.Flag("ingress-class", "Require an Ingress to have this class name (defaults to any class; specify multiple times to allow more than one class)").StringsVar(&cfg.IngressClassNames)
 	app.Flag("fqdn-template", "A templated string that's used to generate DNS names from sources that don't define a hostname themselves, or to add a hostname suffix when paired with the fake source (optional). Accepts comma separated list for multiple global FQDN.").Default(defaultConfig.FQDNTemplate).StringVar(&cfg.FQDNTemplate)
 	app.Flag("combine-fqdn-annotation", "Combine FQDN template and Annotations instead of overwriting").BoolVar(&cfg.CombineFQDNAndAnnotation)
@@ -442,20 +809,46 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("ignore-ingress-tls-spec", "Ignore the spec.tls section in Ingress resources (default: false)").BoolVar(&cfg.IgnoreIngressTLSSpec)
 	app.Flag("gateway-namespace", "Limit Gateways of Route endpoints to a specific namespace (default: all namespaces)").StringVar(&cfg.GatewayNamespace)
 	app.Flag("gateway-label-filter", "Filter Gateways of Route endpoints via label selector (default: all gateways)").StringVar(&cfg.GatewayLabelFilter)
+	app.Flag("gateway-envoy-services", "Use the addresses of the Services Envoy Gateway generates for the Gateways as the targets of their Route endpoints (default: disabled)").BoolVar(&cfg.GatewayEnvoyServices)
 	app.Flag("compatibility", "Process annotation semantics from legacy implementations (optional, options: mate, molecule, kops-dns-controller)").Default(defaultConfig.Compatibility).EnumVar(&cfg.Compatibility, "", "mate", "molecule", "kops-dns-controller")
 	app.Flag("ignore-ingress-rules-spec", "Ignore the spec.rules section in Ingress resources (default: false)").BoolVar(&cfg.IgnoreIngressRulesSpec)
 	app.Flag("publish-internal-services", "Allow external-dns to publish DNS records for ClusterIP services (optional)").BoolVar(&cfg.PublishInternal)
 	app.Flag("publish-host-ip", "Allow external-dns to publish host-ip for headless services (optional)").BoolVar(&cfg.PublishHostIP)
 	app.Flag("always-publish-not-ready-addresses", "Always publish also not ready addresses for headless services (optional)").BoolVar(&cfg.AlwaysPublishNotReadyAddresses)
+	app.Flag("node-sshfp", "When enabled, the node source publishes SSHFP records of the SSH host keys of the nodes, read from their ssh-host-keys annotation; requires SSHFP in --managed-record-types (default: disabled)").BoolVar(&cfg.NodeSSHFP)
+	app.Flag("node-sshfp-secret", "A secret, as <namespace>/<name>, holding the SSH host keys of the nodes by node name, for the nodes without ssh-host-keys annotation, valid only with --node-sshfp (optional)").Default(defaultConfig.NodeSSHFPSecret).StringVar(&cfg.NodeSSHFPSecret)
+	app.Flag("node-label-filter", "Filter the nodes of the node source by label selector, in addition to --label-filter (default: all nodes)").Default(defaultConfig.NodeLabelFilter).StringVar(&cfg.NodeLabelFilter)
+	app.Flag("node-exclude-not-ready", "When enabled, the node source skips the nodes whose Ready condition is not True (default: disabled)").BoolVar(&cfg.NodeExcludeNotReady)
+	app.Flag("node-exclude-unschedulable", "When enabled, the node source skips the cordoned nodes (default: disabled)").BoolVar(&cfg.NodeExcludeUnschedulable)
+	app.Flag("node-ipv4-address-type", "The type of the node addresses the node source prefers for A records, falling back to the other type when a node has none (optional, options: ExternalIP, InternalIP)").Default(defaultConfig.NodeIPv4AddressType).EnumVar(&cfg.NodeIPv4AddressType, "", "ExternalIP", "InternalIP")
+	app.Flag("node-ipv6-address-type", "The type of the node addresses the node source prefers for AAAA records, falling back to the other type when a node has none (default: addresses of both types, options: ExternalIP, InternalIP)").Default(defaultConfig.NodeIPv6AddressType).EnumVar(&cfg.NodeIPv6AddressType, "", "ExternalIP", "InternalIP")
+	app.Flag("pod-label-selector", "Filter the pods of the pod source by label selector (default: all pods)").Default(defaultConfig.PodLabelSelector).StringVar(&cfg.PodLabelSelector)
+	app.Flag("pod-publish-pod-ip", "When enabled, the pod source also publishes the pods not using the host network, with their hostname annotations pointing to their pod IPs (default: disabled)").BoolVar(&cfg.PodPublishPodIP)
 	app.Flag("connector-source-server", "The server to connect for connector source, valid only when using connector source").Default(defaultConfig.ConnectorSourceServer).StringVar(&cfg.ConnectorSourceServer)
+	app.Flag("connector-source-tls", "When using the connector source, connect to --connector-source-server with TLS (default: disabled)").BoolVar(&cfg.ConnectorSourceTLS)
+	app.Flag("connector-source-tls-ca", "When using the connector source with TLS, the CA certificate file verifying the server, instead of the system roots (optional)").Default(defaultConfig.ConnectorSourceTLSCA).StringVar(&cfg.ConnectorSourceTLSCA)
+	app.Flag("connector-source-tls-cert", "When using the connector source with TLS, the client certificate file presented to servers verifying client certificates (optional)").Default(defaultConfig.ConnectorSourceTLSCert).StringVar(&cfg.ConnectorSourceTLSCert)
+	app.Flag("connector-source-tls-key", "When using the connector source with TLS, the key file of --connector-source-tls-cert (optional)").Default(defaultConfig.ConnectorSourceTLSKey).StringVar(&cfg.ConnectorSourceTLSKey)
+	app.Flag("connector-source-token-file", "When using the connector source, a file holding the token sent to the server before receiving the endpoints, read for every connection (optional)").Default(defaultConfig.ConnectorSourceTokenFile).StringVar(&cfg.ConnectorSourceTokenFile)
 	app.Flag("crd-source-apiversion", "API version of the CRD for crd source, e.g. `externaldns.k8s.io/v1alpha1`, valid only when using crd source").Default(defaultConfig.CRDSourceAPIVersion).StringVar(&cfg.CRDSourceAPIVersion)
 	app.Flag("crd-source-kind", "Kind of the CRD for the crd source in API group and version specified by crd-source-apiversion").Default(defaultConfig.CRDSourceKind).StringVar(&cfg.CRDSourceKind)
+	app.Flag("crd-source-namespace", "Limit the crd source to the DNSEndpoints of this namespace; specify multiple times for multiple namespaces (default: --namespace)").StringsVar(&cfg.CRDSourceNamespaces)
+	app.Flag("crd-source-label-filter", "Filter the DNSEndpoints of the crd source by label selector, in addition to --label-filter (default: all DNSEndpoints)").Default(defaultConfig.CRDSourceLabelFilter).StringVar(&cfg.CRDSourceLabelFilter)
 	app.Flag("service-type-filter", "The service types to take care about (default: all, expected: ClusterIP, NodePort, LoadBalancer or ExternalName)").StringsVar(&cfg.ServiceTypeFilter)
+	app.Flag("service-import-naming", "How the records of the ServiceImports of the service-import source are named: clusterset names them <service>.<namespace>.svc.<clusterset-domain> following the Multi-Cluster DNS specification, in addition to their hostname annotations, annotation only uses their hostname annotations (default: clusterset, options: clusterset, annotation)").Default(defaultConfig.ServiceImportNaming).EnumVar(&cfg.ServiceImportNaming, "clusterset", "annotation")
+	app.Flag("clusterset-domain", "The domain of the records named after the Multi-Cluster DNS specification by the service-import source").Default(defaultConfig.ClusterSetDomain).StringVar(&cfg.ClusterSetDomain)
 	app.Flag("managed-record-types", "Record types to manage; specify multiple times to include many; (default: A, AAAA, CNAME) (supported records: A, AAAA, CNAME, NS, SRV, TXT)").Default("A", "AAAA", "CNAME").StringsVar(&cfg.ManagedDNSRecordTypes)
 	app.Flag("exclude-record-types", "Record types to exclude from management; specify multiple times to exclude many; (optional)").Default().StringsVar(&cfg.ExcludeDNSRecordTypes)
 	app.Flag("default-targets", "Set globally default host/IP that will apply as a target instead of source addresses. Specify multiple times for multiple targets (optional)").StringsVar(&cfg.DefaultTargets)
 	app.Flag("target-net-filter", "Limit possible targets by a net filter; specify multiple times for multiple possible nets (optional)").StringsVar(&cfg.TargetNetFilter)
 	app.Flag("exclude-target-net", "Exclude target nets (optional)").StringsVar(&cfg.ExcludeTargetNets)
+	app.Flag("dns-rewrites", "When enabled, the DNS names of the endpoints of the sources are rewritten by the DNSRewrite resources of the cluster (default: disabled)").BoolVar(&cfg.DNSRewrites)
+	app.Flag("transformations-file", "A YAML file holding a list of rules transforming the endpoints of the sources before planning, in order; conditions and values are Go templates rendered with the endpoint (optional)").Default(defaultConfig.TransformationsFile).StringVar(&cfg.TransformationsFile)
+	app.Flag("config-file", "A file of flags, one per line, e.g. mounted from a ConfigMap, parsed after the command line; the sources and the domain filters are rebuilt when it changes or on SIGHUP, without restarting (optional)").Default(defaultConfig.ConfigFile).StringVar(&cfg.ConfigFile)
+	app.Flag("endpoint-filter", "A Go template rendered with every endpoint of the sources and each of its targets, available as .Target, rendering true for the targets to keep; endpoints without targets left are skipped (optional)").Default(defaultConfig.EndpointFilter).StringVar(&cfg.EndpointFilter)
+	app.Flag("nat64-networks", "In IPv6-only clusters behind NAT64, a /96 NAT64 prefix, e.g. 64:ff9b::/96; A records holding the embedded IPv4 addresses are created for the AAAA targets within the prefix; specify multiple times for multiple prefixes (optional)").StringsVar(&cfg.NAT64Networks)
+	app.Flag("nat64-suppress-a", "In IPv6-only clusters, skip all A records of the sources, e.g. when clients resolve through DNS64; mutually exclusive with --nat64-networks (default: disabled)").BoolVar(&cfg.NAT64SuppressA)
+	app.Flag("flatten-cnames", "When enabled, CNAME records pointing at other records managed by ExternalDNS, e.g. chains of ExternalName services, are published as the A and AAAA records they resolve to; useful where CNAME records are forbidden, e.g. at a zone apex (default: disabled)").BoolVar(&cfg.FlattenCNAMEs)
 
 	// Flags related to providers
 	providers := []string{"akamai", "alibabacloud", "aws", "aws-sd", "azure", "azure-dns", "azure-private-dns", "bluecat", "civo", "cloudflare", "coredns", "designate", "digitalocean", "dnsimple", "dyn", "exoscale", "gandi", "godaddy", "google", "ibmcloud", "infoblox", "inmemory", "linode", "ns1", "oci", "ovh", "pdns", "pihole", "plural", "rcodezero", "rdns", "rfc2136", "safedns", "scaleway", "skydns", "tencentcloud", "transip", "ultradns", "vinyldns", "vultr", "webhook"}
@@ -466,10 +859,12 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("regex-domain-exclusion", "Regex filter that excludes domains and target zones matched by regex-domain-filter (optional)").Default(defaultConfig.RegexDomainExclusion.String()).RegexpVar(&cfg.RegexDomainExclusion)
 	app.Flag("zone-name-filter", "Filter target zones by zone domain (For now, only AzureDNS provider is using this flag); specify multiple times for multiple zones (optional)").Default("").StringsVar(&cfg.ZoneNameFilter)
 	app.Flag("zone-id-filter", "Filter target zones by hosted zone id; specify multiple times for multiple zones (optional)").Default("").StringsVar(&cfg.ZoneIDFilter)
+	app.Flag("zone-tags", "Filter target zones by the tags or labels of the zones, in the form key or key=value (supported by AWS, AzureDNS, Azure Private DNS, Google and Cloudflare); specify multiple times for zones with all of these tags (optional)").Default("").StringsVar(&cfg.ZoneTagFilter)
 	app.Flag("google-project", "When using the Google provider, current project is auto-detected, when running on GCP. Specify other project with this. Must be specified when running outside GCP.").Default(defaultConfig.GoogleProject).StringVar(&cfg.GoogleProject)
 	app.Flag("google-batch-change-size", "When using the Google provider, set the maximum number of changes that will be applied in each batch.").Default(strconv.Itoa(defaultConfig.GoogleBatchChangeSize)).IntVar(&cfg.GoogleBatchChangeSize)
 	app.Flag("google-batch-change-interval", "When using the Google provider, set the interval between batch changes.").Default(defaultConfig.GoogleBatchChangeInterval.String()).DurationVar(&cfg.GoogleBatchChangeInterval)
 	app.Flag("google-zone-visibility", "When using the Google provider, filter for zones with this visibility (optional, options: public, private)").Default(defaultConfig.GoogleZoneVisibility).EnumVar(&cfg.GoogleZoneVisibility, "", "public", "private")
+	app.Flag("google-zone-workers", "When using the Google provider, set the number of zones changed concurrently; the changes of each zone are retried on transient errors (default: 1, one zone after the other)").Default(strconv.Itoa(defaultConfig.GoogleZoneWorkers)).IntVar(&cfg.GoogleZoneWorkers)
 	app.Flag("alibaba-cloud-config-file", "When using the Alibaba Cloud provider, specify the Alibaba Cloud configuration file (required when --provider=alibabacloud)").Default(defaultConfig.AlibabaCloudConfigFile).StringVar(&cfg.AlibabaCloudConfigFile)
 	app.Flag("alibaba-cloud-zone-type", "When using the Alibaba Cloud provider, filter for zones of this type (optional, options: public, private)").Default(defaultConfig.AlibabaCloudZoneType).EnumVar(&cfg.AlibabaCloudZoneType, "", "public", "private")
 	app.Flag("aws-zone-type", "When using the AWS provider, filter for zones of this type (optional, options: public, private)").Default(defaultConfig.AWSZoneType).EnumVar(&cfg.AWSZoneType, "", "public", "private")
@@ -478,7 +873,10 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("aws-assume-role-external-id", "When using the AWS API and assuming a role then specify this external ID` (optional)").Default(defaultConfig.AWSAssumeRoleExternalID).StringVar(&cfg.AWSAssumeRoleExternalID)
 	app.Flag("aws-batch-change-size", "When using the AWS provider, set the maximum number of changes that will be applied in each batch.").Default(strconv.Itoa(defaultConfig.AWSBatchChangeSize)).IntVar(&cfg.AWSBatchChangeSize)
 	app.Flag("aws-batch-change-interval", "When using the AWS provider, set the interval between batch changes.").Default(defaultConfig.AWSBatchChangeInterval.String()).DurationVar(&cfg.AWSBatchChangeInterval)
+	app.Flag("aws-zone-workers", "When using the AWS provider, set the number of hosted zones listed and changed concurrently; --aws-batch-change-interval applies within each zone (default: 1, one zone after the other)").Default(strconv.Itoa(defaultConfig.AWSZoneWorkers)).IntVar(&cfg.AWSZoneWorkers)
+	app.Flag("aws-zone-change-rate", "When using the AWS provider, set the maximum number of change batches submitted per second by all the zone workers together, including the changes resubmitted one by one after a batch failed (default: 0, unlimited)").Default(strconv.FormatFloat(defaultConfig.AWSZoneChangeRate, 'f', -1, 64)).Float64Var(&cfg.AWSZoneChangeRate)
 	app.Flag("aws-evaluate-target-health", "When using the AWS provider, set whether to evaluate the health of a DNS target (default: enabled, disable with --no-aws-evaluate-target-health)").Default(strconv.FormatBool(defaultConfig.AWSEvaluateTargetHealth)).BoolVar(&cfg.AWSEvaluateTargetHealth)
+	app.Flag("aws-health-checks", "When using the AWS provider, create and delete the health checks requested by the aws-health-check annotation of records, e.g. to fail over between clusters (default: disabled)").BoolVar(&cfg.AWSHealthChecks)
 	app.Flag("aws-api-retries", "When using the AWS API, set the maximum number of retries before giving up.").Default(strconv.Itoa(defaultConfig.AWSAPIRetries)).IntVar(&cfg.AWSAPIRetries)
 	app.Flag("aws-prefer-cname", "When using the AWS provider, prefer using CNAME instead of ALIAS (default: disabled)").BoolVar(&cfg.AWSPreferCNAME)
 	app.Flag("aws-zones-cache-duration", "When using the AWS provider, set the zones list cache TTL (0s to disable).").Default(defaultConfig.AWSZoneCacheDuration.String()).DurationVar(&cfg.AWSZoneCacheDuration)
@@ -502,6 +900,8 @@ func (cfg *Config) ParseFlags(args []string) error {
 
 	app.Flag("cloudflare-proxied", "When using the Cloudflare provider, specify if the proxy mode must be enabled (default: disabled)").BoolVar(&cfg.CloudflareProxied)
 	app.Flag("cloudflare-dns-records-per-page", "When using the Cloudflare provider, specify how many DNS records listed per page, max possible 5,000 (default: 100)").Default(strconv.Itoa(defaultConfig.CloudflareDNSRecordsPerPage)).IntVar(&cfg.CloudflareDNSRecordsPerPage)
+	app.Flag("cloudflare-tunnel-id", "When using the Cloudflare provider, route records through this Cloudflare Tunnel by default by publishing proxied CNAMEs to <tunnel-id>.cfargotunnel.com (optional)").Default(defaultConfig.CloudflareTunnelID).StringVar(&cfg.CloudflareTunnelID)
+	app.Flag("cloudflare-tunnel-credentials-file", "When using the Cloudflare provider, read the default tunnel ID from this cloudflared credentials file; ignored when --cloudflare-tunnel-id is set (optional)").Default(defaultConfig.CloudflareTunnelCredentialsFile).StringVar(&cfg.CloudflareTunnelCredentialsFile)
 	app.Flag("coredns-prefix", "When using the CoreDNS provider, specify the prefix name").Default(defaultConfig.CoreDNSPrefix).StringVar(&cfg.CoreDNSPrefix)
 	app.Flag("akamai-serviceconsumerdomain", "When using the Akamai provider, specify the base URL (required when --provider=akamai and edgerc-path not specified)").Default(defaultConfig.AkamaiServiceConsumerDomain).StringVar(&cfg.AkamaiServiceConsumerDomain)
 	app.Flag("akamai-client-token", "When using the Akamai provider, specify the client token (required when --provider=akamai and edgerc-path not specified)").Default(defaultConfig.AkamaiClientToken).StringVar(&cfg.AkamaiClientToken)
@@ -590,32 +990,104 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("plural-provider", "When using the plural provider, specify the provider name you're running with").Default(defaultConfig.PluralProvider).StringVar(&cfg.PluralProvider)
 
 	// Flags related to policies
-	app.Flag("policy", "Modify how DNS records are synchronized between sources and providers (default: sync, options: sync, upsert-only, create-only)").Default(defaultConfig.Policy).EnumVar(&cfg.Policy, "sync", "upsert-only", "create-only")
+	app.Flag("policy", "Modify how DNS records are synchronized between sources and providers (default: sync, options: sync, upsert-only, create-only, delete-only)").Default(defaultConfig.Policy).EnumVar(&cfg.Policy, "sync", "upsert-only", "create-only", "delete-only")
+
+	app.Flag("target-normalization", "Normalization applied to targets before comparing existing and desired records, to avoid updates of records only differing in their textual form; specify multiple times for multiple normalizations (default: case, options: case, trailing-dot, ipv6)").Default(defaultConfig.TargetNormalizations...).EnumsVar(&cfg.TargetNormalizations, "case", "trailing-dot", "ipv6")
+
+	app.Flag("metadata-sensitive-diff", "When enabled, owned records are also updated when only the labels stored in the registry, such as the resource they belong to, change; provider-specific properties are always compared (default: disabled)").BoolVar(&cfg.MetadataSensitiveDiff)
+
+	app.Flag("ignore-ttl-differences", "When enabled, records are not updated when only their TTL differs from the desired one (default: disabled)").BoolVar(&cfg.IgnoreTTLDifferences)
+	app.Flag("min-ttl", "The minimum TTL enforced by the provider in duration format; desired TTLs below it are compared as if they were the minimum, to avoid updating records on every synchronization (default: disabled)").Default(defaultConfig.MinTTL.String()).DurationVar(&cfg.MinTTL)
+	app.Flag("default-ttl", "The TTL of the records of a type for which neither the source nor the registry specify one, in the form <record type>=<duration>, e.g. TXT=1h; also applies to the TXT records of the registry; specify multiple times for multiple record types (default: the default TTL of the provider)").StringsVar(&cfg.DefaultTTLs)
+
+	app.Flag("deletion-grace-period", "When set, owned records which are no longer desired are tombstoned and only deleted once they stayed undesired for this long, e.g. to survive brief source outages (default: disabled)").Default(defaultConfig.DeletionGracePeriod.String()).DurationVar(&cfg.DeletionGracePeriod)
+	app.Flag("change-window", "Only apply changes during this recurring window, given as a cron expression in UTC followed by a duration, e.g. \"0 22 * * 6 8h\" from Saturday 22:00 to Sunday 06:00, optionally prefixed with a time zone such as CRON_TZ=Europe/Berlin; outside of the windows, changes stay pending; specify multiple times for multiple windows (default: changes are applied at any time)").StringsVar(&cfg.ChangeWindows)
+	app.Flag("change-window-scope", "Which changes are restricted to the change windows: all of them, or only the deletions of records (default: all, options: all, deletions)").Default(defaultConfig.ChangeWindowScope).EnumVar(&cfg.ChangeWindowScope, "all", "deletions")
+	app.Flag("plan-approval", "When enabled, the planned changes are written as a DNSChangeRequest and only applied once its Approved condition is set to True (default: disabled)").BoolVar(&cfg.PlanApproval)
+	app.Flag("plan-approval-namespace", "The namespace of the DNSChangeRequests created with --plan-approval").Default(defaultConfig.PlanApprovalNamespace).StringVar(&cfg.PlanApprovalNamespace)
+	app.Flag("plan-approval-expiry", "How long an approval of a DNSChangeRequest stays valid, counting from when its Approved condition was set; 0 for no expiry").Default(defaultConfig.PlanApprovalExpiry.String()).DurationVar(&cfg.PlanApprovalExpiry)
+	app.Flag("dns-policies", "When enabled, the records of resources in namespaces selected by DNSPolicies are only managed when one of these policies allows their domain, record type and TTL; violations are recorded as Events (default: disabled)").BoolVar(&cfg.DNSPolicies)
+	app.Flag("git-export-repository", "When set, the managed records are committed to this git repository after every successful synchronization in which they changed, e.g. https://token@git.example.org/dns.git; requires the git command (default: disabled)").Default(defaultConfig.GitExportRepository).StringVar(&cfg.GitExportRepository)
+	app.Flag("git-export-branch", "The branch the managed records are committed to, which is created if needed").Default(defaultConfig.GitExportBranch).StringVar(&cfg.GitExportBranch)
+	app.Flag("git-export-path", "The path of the file holding the managed records within the git repository").Default(defaultConfig.GitExportPath).StringVar(&cfg.GitExportPath)
+	app.Flag("git-export-format", "The format of the file holding the managed records (default: yaml, options: yaml, zone)").Default(defaultConfig.GitExportFormat).EnumVar(&cfg.GitExportFormat, "yaml", "zone")
+	app.Flag("git-export-author", "The author of the commits of the managed records, in the form \"Name <email>\"").Default(defaultConfig.GitExportAuthor).StringVar(&cfg.GitExportAuthor)
+	app.Flag("git-export-dir", "The directory the git repository is checked out to (default: a temporary directory)").Default(defaultConfig.GitExportDir).StringVar(&cfg.GitExportDir)
+	app.Flag("notify-webhook-url", "When set, a summary of the changes applied by every synchronization, and of the changes which failed to be applied, is posted to this URL, e.g. a Slack incoming webhook (default: disabled)").Default(defaultConfig.NotifyWebhookURL).StringVar(&cfg.NotifyWebhookURL)
+	app.Flag("notify-format", "The format of the notifications: generic posts the changes as JSON, slack posts a message compatible with Slack incoming webhooks (default: generic, options: generic, slack)").Default(defaultConfig.NotifyFormat).EnumVar(&cfg.NotifyFormat, "generic", "slack")
+	app.Flag("notify-zone", "Only notify of the changes of records within this domain; specify multiple times for multiple domains (default: all records)").StringsVar(&cfg.NotifyZones)
+	app.Flag("notify-min-changes", "Only notify of synchronizations applying at least this many changes; failures are always notified").Default(strconv.Itoa(defaultConfig.NotifyMinChanges)).IntVar(&cfg.NotifyMinChanges)
+	app.Flag("verify-resolver", "When set, the records applied by every synchronization are queried on this resolver, as host or host:port, until they resolve to their targets, and the synchronization is reported as degraded if they don't; specify multiple times for multiple resolvers (default: disabled)").StringsVar(&cfg.VerifyResolvers)
+	app.Flag("verify-timeout", "The timeout of the queries verifying the applied records").Default(defaultConfig.VerifyTimeout.String()).DurationVar(&cfg.VerifyTimeout)
+	app.Flag("verify-retries", "How many times the applied records which don't resolve are queried again before the synchronization is reported as degraded").Default(strconv.Itoa(defaultConfig.VerifyRetries)).IntVar(&cfg.VerifyRetries)
+	app.Flag("verify-interval", "The interval between the queries of the applied records which don't resolve yet").Default(defaultConfig.VerifyInterval.String()).DurationVar(&cfg.VerifyInterval)
+	app.Flag("manage-zones", "When enabled, the hosted zones declared by DNSZone resources are created and configured at the provider, and the information needed to delegate to them is written to their status; requires a provider supporting it (default: disabled)").BoolVar(&cfg.ManageZones)
+	app.Flag("manage-zones-namespace", "The namespace of the DNSZones managed with --manage-zones (default: all namespaces)").Default(defaultConfig.ManageZonesNamespace).StringVar(&cfg.ManageZonesNamespace)
+	app.Flag("dnssec-export-file", "When set, the DS and CDNSKEY data of the zones of the provider signed with DNSSEC are written to this YAML file at every interval, so that the delegations from their parent zones can be maintained; requires a provider supporting it (optional)").Default(defaultConfig.DNSSECExportFile).StringVar(&cfg.DNSSECExportFile)
+
+	app.Flag("wildcard-policy", "How to handle the creation of records below an owned wildcard record, which stop the wildcard from answering for their name: allow creates them, warn creates them and logs a warning, block does not create them (default: allow, options: allow, warn, block)").Default(defaultConfig.WildcardPolicy).EnumVar(&cfg.WildcardPolicy, "allow", "warn", "block")
+	app.Flag("fqdn-policy", "How DNS names and host names in targets, e.g. of CNAME, MX or SRV records, are canonicalized before comparing existing and desired records: preserve publishes them as produced by the sources, relative in lower case without trailing dot, absolute in lower case with a trailing dot for host names in targets; relative and absolute also ignore these differences in existing records (default: preserve, options: preserve, relative, absolute)").Default(defaultConfig.FQDNPolicy).EnumVar(&cfg.FQDNPolicy, "preserve", "relative", "absolute")
+
+	app.Flag("audit-sink", "Record every applied change to this sink; specify multiple times for multiple sinks (optional, options: file, webhook, events)").EnumsVar(&cfg.AuditSinks, "file", "webhook", "events")
+	app.Flag("audit-file", "When using the file audit sink, the file audit entries are appended to as JSON lines").Default(defaultConfig.AuditFile).StringVar(&cfg.AuditFile)
+	app.Flag("audit-webhook-url", "When using the webhook audit sink, the URL audit entries are posted to as a JSON array").Default(defaultConfig.AuditWebhookURL).StringVar(&cfg.AuditWebhookURL)
 
 	// Flags related to the registry
 	app.Flag("registry", "The registry implementation to use to keep track of DNS record ownership (default: txt, options: txt, noop, dynamodb, aws-sd)").Default(defaultConfig.Registry).EnumVar(&cfg.Registry, "txt", "noop", "dynamodb", "aws-sd")
+	app.Flag("secondary-registry", "When migrating between registries, a registry that keeps receiving ownership information in addition to --registry, which is preferred for reads (optional, options: txt, dynamodb)").Default(defaultConfig.SecondaryRegistry).EnumVar(&cfg.SecondaryRegistry, "", "txt", "dynamodb")
 	app.Flag("txt-owner-id", "When using the TXT or DynamoDB registry, a name that identifies this instance of ExternalDNS (default: default)").Default(defaultConfig.TXTOwnerID).StringVar(&cfg.TXTOwnerID)
+	app.Flag("txt-owner-id-template", "When using the TXT registry, a Go template rendered with the .Namespace, .Labels and .Annotations of the namespace of each resource, which derives the owner ID <txt-owner-id>/<rendered template> of its records; records of resources rendering an empty owner ID are owned by --txt-owner-id (optional)").Default(defaultConfig.TXTOwnerIDTemplate).StringVar(&cfg.TXTOwnerIDTemplate)
+	app.Flag("cluster-id", "A name that identifies the cluster this instance of ExternalDNS runs in, stored in the registry alongside the owner of each record (optional)").Default(defaultConfig.ClusterID).StringVar(&cfg.ClusterID)
 	app.Flag("txt-prefix", "When using the TXT registry, a custom string that's prefixed to each ownership DNS record (optional). Could contain record type template like '%{record_type}-prefix-'. Mutual exclusive with txt-suffix!").Default(defaultConfig.TXTPrefix).StringVar(&cfg.TXTPrefix)
 	app.Flag("txt-suffix", "When using the TXT registry, a custom string that's suffixed to the host portion of each ownership DNS record (optional). Could contain record type template like '-%{record_type}-suffix'. Mutual exclusive with txt-prefix!").Default(defaultConfig.TXTSuffix).StringVar(&cfg.TXTSuffix)
 	app.Flag("txt-wildcard-replacement", "When using the TXT registry, a custom string that's used instead of an asterisk for TXT records corresponding to wildcard DNS records (optional)").Default(defaultConfig.TXTWildcardReplacement).StringVar(&cfg.TXTWildcardReplacement)
 	app.Flag("txt-encrypt-enabled", "When using the TXT registry, set if TXT records should be encrypted before stored (default: disabled)").BoolVar(&cfg.TXTEncryptEnabled)
 	app.Flag("txt-encrypt-aes-key", "When using the TXT registry, set TXT record decryption and encryption 32 byte aes key (required when --txt-encrypt=true)").Default(defaultConfig.TXTEncryptAESKey).StringVar(&cfg.TXTEncryptAESKey)
+	app.Flag("txt-decrypt-aes-key", "When using the TXT registry, a 32 byte aes key only used to decrypt TXT records, which are re-encrypted with --txt-encrypt-aes-key; specify multiple times to rotate through several keys (optional)").StringsVar(&cfg.TXTDecryptAESKeys)
 	app.Flag("dynamodb-region", "When using the DynamoDB registry, the AWS region of the DynamoDB table (optional)").Default(cfg.AWSDynamoDBRegion).StringVar(&cfg.AWSDynamoDBRegion)
 	app.Flag("dynamodb-table", "When using the DynamoDB registry, the name of the DynamoDB table (default: \"external-dns\")").Default(defaultConfig.AWSDynamoDBTable).StringVar(&cfg.AWSDynamoDBTable)
+	app.Flag("dynamodb-gc-grace-period", "When using the DynamoDB registry, delete items owned by this instance whose DNS record no longer exists once they are orphaned for longer than this period, even without other changes (default: disabled, orphaned items are deleted with the next change)").Default(defaultConfig.AWSDynamoDBGCGracePeriod.String()).DurationVar(&cfg.AWSDynamoDBGCGracePeriod)
 
 	// Flags related to the main control loop
 	app.Flag("txt-cache-interval", "The interval between cache synchronizations in duration format (default: disabled)").Default(defaultConfig.TXTCacheInterval.String()).DurationVar(&cfg.TXTCacheInterval)
+	app.Flag("registry-cache-interval", "The interval between refreshes of the registry records, independent of --interval; the records are refreshed after every change (default: disabled)").Default(defaultConfig.RegistryCacheInterval.String()).DurationVar(&cfg.RegistryCacheInterval)
+	app.Flag("registry-cache-snapshot-file", "A file, e.g. on a persistent volume, the registry records cached with --registry-cache-interval are saved to when shutting down, and restored from when starting, unless older than the interval (optional)").StringVar(&cfg.RegistryCacheSnapshotFile)
+	app.Flag("registry-cache-snapshot-configmap", "A ConfigMap, as <namespace>/<name>, the registry records cached with --registry-cache-interval are saved to when shutting down, and restored from when starting, unless older than the interval; limited to 1 MiB of compressed records (optional)").StringVar(&cfg.RegistryCacheSnapshotConfigMap)
 	app.Flag("interval", "The interval between two consecutive synchronizations in duration format (default: 1m)").Default(defaultConfig.Interval.String()).DurationVar(&cfg.Interval)
+	app.Flag("source-interval", "The interval between two consecutive collections of endpoints from a source in the form <source>=<duration>, e.g. node=10m; endpoints collected last are reused in between; specify multiple times for multiple sources (default: every synchronization)").StringsVar(&cfg.SourceIntervals)
+	app.Flag("source-max-staleness", "When a source fails to collect its endpoints, e.g. because its CRD is not installed, synchronize the other sources with the endpoints it collected last if they are not older than this duration, or without deleting any records if it never succeeded (default: disabled, the failure of a source aborts the synchronization)").Default(defaultConfig.SourceMaxStaleness.String()).DurationVar(&cfg.SourceMaxStaleness)
+	app.Flag("source-conflict-policy", "How to resolve the conflicts between sources returning endpoints with different targets for the same record (default: none, keep the endpoints of all sources; options: none, error, prefer-source-order, merge-targets, prefer-annotation-weight)").Default(defaultConfig.SourceConflictPolicy).EnumVar(&cfg.SourceConflictPolicy, source.ConflictPolicies...)
 	app.Flag("min-event-sync-interval", "The minimum interval between two consecutive synchronizations triggered from kubernetes events in duration format (default: 5s)").Default(defaultConfig.MinEventSyncInterval.String()).DurationVar(&cfg.MinEventSyncInterval)
+	app.Flag("full-reconcile-interval", "When set, synchronizations only plan the DNS names whose desired endpoints changed since the previous one, and all DNS names are planned at this interval in duration format (default: disabled, every synchronization plans all DNS names)").Default(defaultConfig.FullReconcileInterval.String()).DurationVar(&cfg.FullReconcileInterval)
+	app.Flag("drain-timeout", "On SIGTERM, how long the synchronization in flight may keep applying its changes before being aborted, in duration format (default: 20s)").Default(defaultConfig.DrainTimeout.String()).DurationVar(&cfg.DrainTimeout)
+	app.Flag("pending-changes-file", "When set, the changes aborted by the drain timeout are persisted to this file and resumed after the next start (optional)").Default(defaultConfig.PendingChangesFile).StringVar(&cfg.PendingChangesFile)
 	app.Flag("once", "When enabled, exits the synchronization loop after the first iteration (default: disabled)").BoolVar(&cfg.Once)
 	app.Flag("dry-run", "When enabled, prints DNS record changes rather than actually performing them (default: disabled)").BoolVar(&cfg.DryRun)
+	app.Flag("validate-dry-run", "When enabled with --dry-run, the synchronization fails if the provider would reject the changes, e.g. creating an existing record or exceeding the size of a change batch; only supported by the aws provider (default: disabled)").BoolVar(&cfg.ValidateDryRun)
+	app.Flag("preflight", "When enabled, verifies at startup that the records of all zones can be changed by creating and deleting a canary TXT record in each of them, and fails readiness until it succeeds; only supported by the aws, google and inmemory providers (default: disabled)").BoolVar(&cfg.Preflight)
+	app.Flag("preflight-credentials-file", "When set with --preflight, the preflight runs again whenever the content of this file, e.g. the mounted credentials of the provider, changes or on SIGHUP (optional)").Default(defaultConfig.PreflightCredentialsFile).StringVar(&cfg.PreflightCredentialsFile)
 	app.Flag("events", "When enabled, in addition to running every interval, the reconciliation loop will get triggered when supported sources change (default: disabled)").BoolVar(&cfg.UpdateEvents)
 
 	// Miscellaneous flags
 	app.Flag("log-format", "The format in which log messages are printed (default: text, options: text, json)").Default(defaultConfig.LogFormat).EnumVar(&cfg.LogFormat, "text", "json")
 	app.Flag("metrics-address", "Specify where to serve the metrics and health check endpoint (default: :7979)").Default(defaultConfig.MetricsAddress).StringVar(&cfg.MetricsAddress)
+	app.Flag("metrics-tls-cert-file", "When set with --metrics-tls-key-file, serves the metrics and health check endpoint over TLS with this certificate, which is reloaded when it changes (optional)").Default(defaultConfig.MetricsTLSCertFile).StringVar(&cfg.MetricsTLSCertFile)
+	app.Flag("metrics-tls-key-file", "The key of the certificate given with --metrics-tls-cert-file (optional)").Default(defaultConfig.MetricsTLSKeyFile).StringVar(&cfg.MetricsTLSKeyFile)
+	app.Flag("metrics-tls-client-ca-file", "When set, /metrics is only served to clients presenting a certificate signed by these CAs, or the bearer token of --metrics-bearer-token-file; requires TLS; /healthz and /readyz stay public for the kubelet probes (optional)").Default(defaultConfig.MetricsTLSClientCAFile).StringVar(&cfg.MetricsTLSClientCAFile)
+	app.Flag("metrics-bearer-token-file", "When set, /metrics is only served to clients sending the bearer token contained in this file, which is read for every request, or a certificate verified by --metrics-tls-client-ca-file; /healthz and /readyz stay public for the kubelet probes (optional)").Default(defaultConfig.MetricsBearerTokenFile).StringVar(&cfg.MetricsBearerTokenFile)
+	app.Flag("sync-endpoint", "When enabled, a POST to /sync on --metrics-address triggers a synchronization immediately; requires --metrics-bearer-token-file or --metrics-tls-client-ca-file (default: disabled)").BoolVar(&cfg.SyncEndpoint)
+	app.Flag("debug-records-endpoint", "When enabled, /debug/records on --metrics-address lists the records of the last synchronization with their owner, cluster, resource and commit labels (default: disabled)").BoolVar(&cfg.DebugRecordsEndpoint)
+	app.Flag("debug-status-endpoint", "When enabled, /debug/status on --metrics-address reports the outcome of the last synchronization and its desired endpoints (default: disabled)").BoolVar(&cfg.DebugStatusEndpoint)
+	app.Flag("trigger-sync", "When set, triggers a synchronization of the ExternalDNS instance serving the sync endpoint at this URL, e.g. https://external-dns.external-dns:7979/sync, authenticating with the token of --metrics-bearer-token-file, and exits (optional)").Default(defaultConfig.TriggerSync).StringVar(&cfg.TriggerSync)
+	app.Flag("trigger-sync-ca-file", "The CAs verifying the certificate of the instance called by --trigger-sync (default: the system CAs)").Default(defaultConfig.TriggerSyncCAFile).StringVar(&cfg.TriggerSyncCAFile)
+	app.Flag("readiness-max-sync-intervals", "When set, the readiness probe fails when the last fully successful synchronization is older than this number of --interval (default: 0, disabled)").Default(strconv.Itoa(defaultConfig.ReadinessMaxSyncIntervals)).IntVar(&cfg.ReadinessMaxSyncIntervals)
 	app.Flag("log-level", "Set the level of logging. (default: info, options: panic, debug, info, warning, error, fatal)").Default(defaultConfig.LogLevel).EnumVar(&cfg.LogLevel, allLogLevelsAsStrings()...)
 
+	// Tailscale split DNS
+	app.Flag("tailscale-tailnet", "When set, routes the domains of --domain-filter to --tailscale-nameserver within this Tailscale tailnet using its split DNS configuration, \"-\" for the default tailnet of the API key (optional)").Default(defaultConfig.TailscaleTailnet).StringVar(&cfg.TailscaleTailnet)
+	app.Flag("tailscale-api-key", "The Tailscale API key or OAuth access token allowed to change the DNS configuration of the tailnet; required when --tailscale-tailnet is set").Default(defaultConfig.TailscaleAPIKey).StringVar(&cfg.TailscaleAPIKey)
+	app.Flag("tailscale-nameserver", "The tailnet address of a name server serving the records published by ExternalDNS; specify multiple times for multiple name servers").StringsVar(&cfg.TailscaleNameservers)
+
 	// Webhook provider
 	app.Flag("webhook-provider-url", "[EXPERIMENTAL] The URL of the remote endpoint to call for the webhook provider (default: http://localhost:8888)").Default(defaultConfig.WebhookProviderURL).StringVar(&cfg.WebhookProviderURL)
 	app.Flag("webhook-provider-read-timeout", "[EXPERIMENTAL] The read timeout for the webhook provider in duration format (default: 5s)").Default(defaultConfig.WebhookProviderReadTimeout.String()).DurationVar(&cfg.WebhookProviderReadTimeout)
@@ -623,10 +1095,5 @@ func (cfg *Config) ParseFlags(args []string) error {
 
 	app.Flag("webhook-server", "[EXPERIMENTAL] When enabled, runs as a webhook server instead of a controller. (default: false).").BoolVar(&cfg.WebhookServer)
 
-	_, err := app.Parse(args)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return app
 }
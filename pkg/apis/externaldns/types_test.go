@@ -18,6 +18,7 @@ package externaldns
 
 import (
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
@@ -37,6 +38,7 @@ var (
 		RequestTimeout:              time.Second * 30,
 		GlooNamespaces:              []string{"gloo-system"},
 		SkipperRouteGroupVersion:    "zalando.org/v1",
+		CiliumNamespace:             "kube-system",
 		Sources:                     []string{"service"},
 		Namespace:                   "",
 		FQDNTemplate:                "",
@@ -46,12 +48,14 @@ var (
 		GoogleBatchChangeSize:       1000,
 		GoogleBatchChangeInterval:   time.Second,
 		GoogleZoneVisibility:        "",
+		GoogleZoneWorkers:           1,
 		DomainFilter:                []string{""},
 		ExcludeDomains:              []string{""},
 		RegexDomainFilter:           regexp.MustCompile(""),
 		RegexDomainExclusion:        regexp.MustCompile(""),
 		ZoneNameFilter:              []string{""},
 		ZoneIDFilter:                []string{""},
+		ZoneTagFilter:               []string{""},
 		AlibabaCloudConfigFile:      "/etc/kubernetes/alibaba-cloud.json",
 		AWSZoneType:                 "",
 		AWSZoneTagFilter:            []string{""},
@@ -59,6 +63,8 @@ var (
 		AWSAssumeRoleExternalID:     "",
 		AWSBatchChangeSize:          1000,
 		AWSBatchChangeInterval:      time.Second,
+		AWSZoneWorkers:              1,
+		AWSZoneChangeRate:           0,
 		AWSEvaluateTargetHealth:     true,
 		AWSAPIRetries:               3,
 		AWSPreferCNAME:              false,
@@ -102,14 +108,34 @@ var (
 		PDNSServer:                  "http://localhost:8081",
 		PDNSAPIKey:                  "",
 		Policy:                      "sync",
+		WildcardPolicy:              "allow",
+		ChangeWindowScope:           "all",
+		PlanApprovalNamespace:       "default",
+		PlanApprovalExpiry:          time.Hour,
+		GitExportBranch:             "main",
+		GitExportPath:               "records.yaml",
+		GitExportFormat:             "yaml",
+		GitExportAuthor:             "ExternalDNS <external-dns@localhost>",
+		NotifyFormat:                "generic",
+		NotifyMinChanges:            1,
+		VerifyTimeout:               2 * time.Second,
+		VerifyRetries:               5,
+		VerifyInterval:              5 * time.Second,
+		ServiceImportNaming:         "clusterset",
+		ClusterSetDomain:            "clusterset.local",
+		FQDNPolicy:                  "preserve",
+		TargetNormalizations:        []string{"case"},
 		Registry:                    "txt",
 		TXTOwnerID:                  "default",
 		TXTPrefix:                   "",
 		TXTCacheInterval:            0,
 		Interval:                    time.Minute,
+		SourceConflictPolicy:        "none",
 		MinEventSyncInterval:        5 * time.Second,
+		DrainTimeout:                20 * time.Second,
 		Once:                        false,
 		DryRun:                      false,
+		Preflight:                   false,
 		UpdateEvents:                false,
 		LogFormat:                   "text",
 		MetricsAddress:              ":7979",
@@ -138,119 +164,230 @@ var (
 	}
 
 	overriddenConfig = &Config{
-		APIServerURL:                "http://127.0.0.1:8080",
-		KubeConfig:                  "/some/path",
-		RequestTimeout:              time.Second * 77,
-		GlooNamespaces:              []string{"gloo-not-system", "gloo-second-system"},
-		SkipperRouteGroupVersion:    "zalando.org/v2",
-		Sources:                     []string{"service", "ingress", "connector"},
-		Namespace:                   "namespace",
-		IgnoreHostnameAnnotation:    true,
-		IgnoreIngressTLSSpec:        true,
-		IgnoreIngressRulesSpec:      true,
-		FQDNTemplate:                "{{.Name}}.service.example.com",
-		Compatibility:               "mate",
-		Provider:                    "google",
-		GoogleProject:               "project",
-		GoogleBatchChangeSize:       100,
-		GoogleBatchChangeInterval:   time.Second * 2,
-		GoogleZoneVisibility:        "private",
-		DomainFilter:                []string{"example.org", "company.com"},
-		ExcludeDomains:              []string{"xapi.example.org", "xapi.company.com"},
-		RegexDomainFilter:           regexp.MustCompile("(example\\.org|company\\.com)$"),
-		RegexDomainExclusion:        regexp.MustCompile("xapi\\.(example\\.org|company\\.com)$"),
-		ZoneNameFilter:              []string{"yapi.example.org", "yapi.company.com"},
-		ZoneIDFilter:                []string{"/hostedzone/ZTST1", "/hostedzone/ZTST2"},
-		TargetNetFilter:             []string{"10.0.0.0/9", "10.1.0.0/9"},
-		ExcludeTargetNets:           []string{"1.0.0.0/9", "1.1.0.0/9"},
-		AlibabaCloudConfigFile:      "/etc/kubernetes/alibaba-cloud.json",
-		AWSZoneType:                 "private",
-		AWSZoneTagFilter:            []string{"tag=foo"},
-		AWSAssumeRole:               "some-other-role",
-		AWSAssumeRoleExternalID:     "pg2000",
-		AWSBatchChangeSize:          100,
-		AWSBatchChangeInterval:      time.Second * 2,
-		AWSEvaluateTargetHealth:     false,
-		AWSAPIRetries:               13,
-		AWSPreferCNAME:              true,
-		AWSZoneCacheDuration:        10 * time.Second,
-		AWSSDServiceCleanup:         true,
-		AWSDynamoDBTable:            "custom-table",
-		AzureConfigFile:             "azure.json",
-		AzureResourceGroup:          "arg",
-		AzureSubscriptionID:         "arg",
-		BluecatDNSConfiguration:     "arg",
-		BluecatDNSServerName:        "arg",
-		BluecatConfigFile:           "bluecat.json",
-		BluecatDNSView:              "arg",
-		BluecatGatewayHost:          "arg",
-		BluecatRootZone:             "arg",
-		BluecatDNSDeployType:        "full-deploy",
-		BluecatSkipTLSVerify:        true,
-		CloudflareProxied:           true,
-		CloudflareDNSRecordsPerPage: 5000,
-		CoreDNSPrefix:               "/coredns/",
-		AkamaiServiceConsumerDomain: "oooo-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net",
-		AkamaiClientToken:           "o184671d5307a388180fbf7f11dbdf46",
-		AkamaiClientSecret:          "o184671d5307a388180fbf7f11dbdf46",
-		AkamaiAccessToken:           "o184671d5307a388180fbf7f11dbdf46",
-		AkamaiEdgercPath:            "/home/test/.edgerc",
-		AkamaiEdgercSection:         "default",
-		InfobloxGridHost:            "127.0.0.1",
-		InfobloxWapiPort:            8443,
-		InfobloxWapiUsername:        "infoblox",
-		InfobloxWapiPassword:        "infoblox",
-		InfobloxWapiVersion:         "2.6.1",
-		InfobloxView:                "internal",
-		InfobloxSSLVerify:           false,
-		InfobloxMaxResults:          2000,
-		OCIConfigFile:               "oci.yaml",
-		OCIZoneScope:                "PRIVATE",
-		OCIZoneCacheDuration:        30 * time.Second,
-		InMemoryZones:               []string{"example.org", "company.com"},
-		OVHEndpoint:                 "ovh-ca",
-		OVHApiRateLimit:             42,
-		PDNSServer:                  "http://ns.example.com:8081",
-		PDNSAPIKey:                  "some-secret-key",
-		PDNSSkipTLSVerify:           true,
-		TLSCA:                       "/path/to/ca.crt",
-		TLSClientCert:               "/path/to/cert.pem",
-		TLSClientCertKey:            "/path/to/key.pem",
-		Policy:                      "upsert-only",
-		Registry:                    "noop",
-		TXTOwnerID:                  "owner-1",
-		TXTPrefix:                   "associated-txt-record",
-		TXTCacheInterval:            12 * time.Hour,
-		Interval:                    10 * time.Minute,
-		MinEventSyncInterval:        50 * time.Second,
-		Once:                        true,
-		DryRun:                      true,
-		UpdateEvents:                true,
-		LogFormat:                   "json",
-		MetricsAddress:              "127.0.0.1:9099",
-		LogLevel:                    logrus.DebugLevel.String(),
-		ConnectorSourceServer:       "localhost:8081",
-		ExoscaleAPIEnvironment:      "api1",
-		ExoscaleAPIZone:             "zone1",
-		ExoscaleAPIKey:              "1",
-		ExoscaleAPISecret:           "2",
-		CRDSourceAPIVersion:         "test.k8s.io/v1alpha1",
-		CRDSourceKind:               "Endpoint",
-		RcodezeroTXTEncrypt:         true,
-		NS1Endpoint:                 "https://api.example.com/v1",
-		NS1IgnoreSSL:                true,
-		TransIPAccountName:          "transip",
-		TransIPPrivateKeyFile:       "/path/to/transip.key",
-		DigitalOceanAPIPageSize:     100,
-		ManagedDNSRecordTypes:       []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeNS},
-		RFC2136BatchChangeSize:      100,
-		IBMCloudProxied:             true,
-		IBMCloudConfigFile:          "ibmcloud.json",
-		TencentCloudConfigFile:      "tencent-cloud.json",
-		TencentCloudZoneType:        "private",
-		WebhookProviderURL:          "http://localhost:8888",
-		WebhookProviderReadTimeout:  5 * time.Second,
-		WebhookProviderWriteTimeout: 10 * time.Second,
+		APIServerURL:                    "http://127.0.0.1:8080",
+		KubeConfig:                      "/some/path",
+		MemberClusters:                  []string{"name=eu,kubeconfig=/some/eu", "name=us,kubeconfig=/some/us,context=us-east"},
+		RequestTimeout:                  time.Second * 77,
+		GlooNamespaces:                  []string{"gloo-not-system", "gloo-second-system"},
+		SkipperRouteGroupVersion:        "zalando.org/v2",
+		IstioPublishMeshOnlyHosts:       true,
+		IstioGatewayAnyNamespace:        true,
+		CiliumLoadBalancerMode:          "shared",
+		CiliumNamespace:                 "cilium",
+		TraefikDisableLegacy:            true,
+		TraefikDisableTCP:               true,
+		GatewayEnvoyServices:            true,
+		TraefikDisableUDP:               true,
+		TraefikService:                  "traefik/traefik",
+		KongProxyService:                "kong/kong-proxy",
+		AmbassadorService:               "emissary/emissary-ingress",
+		FileSourcePath:                  "/etc/external-dns/endpoints.yaml",
+		FileSourceConfigMap:             "external-dns/endpoints",
+		HTTPSourceURL:                   "https://inventory.example.org/endpoints",
+		HTTPSourceBearerTokenFile:       "/var/run/secrets/inventory/token",
+		KnativeRoutes:                   true,
+		KnativeIngressService:           "kourier-system/kourier",
+		Sources:                         []string{"service", "ingress", "connector"},
+		Namespace:                       "namespace",
+		IgnoreHostnameAnnotation:        true,
+		IgnoreIngressTLSSpec:            true,
+		IgnoreIngressRulesSpec:          true,
+		FQDNTemplate:                    "{{.Name}}.service.example.com",
+		Compatibility:                   "mate",
+		NodeSSHFP:                       true,
+		NodeSSHFPSecret:                 "kube-system/ssh-host-keys",
+		NodeLabelFilter:                 "node-role.kubernetes.io/worker",
+		NodeExcludeNotReady:             true,
+		ServiceTopologyHostnames:        true,
+		NodeExcludeUnschedulable:        true,
+		NodeIPv4AddressType:             "InternalIP",
+		NodeIPv6AddressType:             "ExternalIP",
+		PodLabelSelector:                "app=worker",
+		PodPublishPodIP:                 true,
+		Provider:                        "google",
+		GoogleProject:                   "project",
+		GoogleBatchChangeSize:           100,
+		GoogleBatchChangeInterval:       time.Second * 2,
+		GoogleZoneVisibility:            "private",
+		GoogleZoneWorkers:               4,
+		DomainFilter:                    []string{"example.org", "company.com"},
+		ExcludeDomains:                  []string{"xapi.example.org", "xapi.company.com"},
+		RegexDomainFilter:               regexp.MustCompile("(example\\.org|company\\.com)$"),
+		RegexDomainExclusion:            regexp.MustCompile("xapi\\.(example\\.org|company\\.com)$"),
+		ZoneNameFilter:                  []string{"yapi.example.org", "yapi.company.com"},
+		ZoneIDFilter:                    []string{"/hostedzone/ZTST1", "/hostedzone/ZTST2"},
+		ZoneTagFilter:                   []string{"team=platform", "public"},
+		TargetNetFilter:                 []string{"10.0.0.0/9", "10.1.0.0/9"},
+		ExcludeTargetNets:               []string{"1.0.0.0/9", "1.1.0.0/9"},
+		DNSRewrites:                     true,
+		TransformationsFile:             "/etc/external-dns/transformations.yaml",
+		EndpointFilter:                  "{{ not (isPrivateIP .Target) }}",
+		NAT64Networks:                   []string{"64:ff9b::/96", "2001:db8:64::/96"},
+		NAT64SuppressA:                  true,
+		FlattenCNAMEs:                   true,
+		AlibabaCloudConfigFile:          "/etc/kubernetes/alibaba-cloud.json",
+		AWSZoneType:                     "private",
+		AWSZoneTagFilter:                []string{"tag=foo"},
+		AWSAssumeRole:                   "some-other-role",
+		AWSAssumeRoleExternalID:         "pg2000",
+		AWSBatchChangeSize:              100,
+		AWSBatchChangeInterval:          time.Second * 2,
+		AWSZoneWorkers:                  8,
+		AWSZoneChangeRate:               2.5,
+		AWSEvaluateTargetHealth:         false,
+		AWSHealthChecks:                 true,
+		AWSAPIRetries:                   13,
+		AWSPreferCNAME:                  true,
+		AWSZoneCacheDuration:            10 * time.Second,
+		AWSSDServiceCleanup:             true,
+		AWSDynamoDBTable:                "custom-table",
+		AWSDynamoDBGCGracePeriod:        time.Hour,
+		AzureConfigFile:                 "azure.json",
+		AzureResourceGroup:              "arg",
+		AzureSubscriptionID:             "arg",
+		BluecatDNSConfiguration:         "arg",
+		BluecatDNSServerName:            "arg",
+		BluecatConfigFile:               "bluecat.json",
+		BluecatDNSView:                  "arg",
+		BluecatGatewayHost:              "arg",
+		BluecatRootZone:                 "arg",
+		BluecatDNSDeployType:            "full-deploy",
+		BluecatSkipTLSVerify:            true,
+		CloudflareProxied:               true,
+		CloudflareDNSRecordsPerPage:     5000,
+		CloudflareTunnelID:              "c1f0b6d2-3f5e-4a8b-9c7d-2e1f0a9b8c7d",
+		CloudflareTunnelCredentialsFile: "/etc/cloudflared/credentials.json",
+		TailscaleTailnet:                "example.com",
+		TailscaleAPIKey:                 "tskey-api",
+		TailscaleNameservers:            []string{"100.100.1.1", "100.100.1.2"},
+		CoreDNSPrefix:                   "/coredns/",
+		AkamaiServiceConsumerDomain:     "oooo-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net",
+		AkamaiClientToken:               "o184671d5307a388180fbf7f11dbdf46",
+		AkamaiClientSecret:              "o184671d5307a388180fbf7f11dbdf46",
+		AkamaiAccessToken:               "o184671d5307a388180fbf7f11dbdf46",
+		AkamaiEdgercPath:                "/home/test/.edgerc",
+		AkamaiEdgercSection:             "default",
+		InfobloxGridHost:                "127.0.0.1",
+		InfobloxWapiPort:                8443,
+		InfobloxWapiUsername:            "infoblox",
+		InfobloxWapiPassword:            "infoblox",
+		InfobloxWapiVersion:             "2.6.1",
+		InfobloxView:                    "internal",
+		InfobloxSSLVerify:               false,
+		InfobloxMaxResults:              2000,
+		OCIConfigFile:                   "oci.yaml",
+		OCIZoneScope:                    "PRIVATE",
+		OCIZoneCacheDuration:            30 * time.Second,
+		InMemoryZones:                   []string{"example.org", "company.com"},
+		OVHEndpoint:                     "ovh-ca",
+		OVHApiRateLimit:                 42,
+		PDNSServer:                      "http://ns.example.com:8081",
+		PDNSAPIKey:                      "some-secret-key",
+		PDNSSkipTLSVerify:               true,
+		TLSCA:                           "/path/to/ca.crt",
+		TLSClientCert:                   "/path/to/cert.pem",
+		TLSClientCertKey:                "/path/to/key.pem",
+		Policy:                          "upsert-only",
+		TargetNormalizations:            []string{"case", "trailing-dot"},
+		MetadataSensitiveDiff:           true,
+		IgnoreTTLDifferences:            true,
+		MinTTL:                          time.Minute,
+		DefaultTTLs:                     []string{"A=1m", "TXT=1h"},
+		DeletionGracePeriod:             time.Hour,
+		WildcardPolicy:                  "block",
+		ChangeWindows:                   []string{"0 22 * * 6 8h"},
+		ChangeWindowScope:               "deletions",
+		PlanApproval:                    true,
+		PlanApprovalNamespace:           "dns-review",
+		PlanApprovalExpiry:              30 * time.Minute,
+		DNSPolicies:                     true,
+		GitExportRepository:             "https://git.example.org/dns.git",
+		GitExportBranch:                 "records",
+		GitExportPath:                   "clusters/cluster-1.zone",
+		GitExportFormat:                 "zone",
+		GitExportAuthor:                 "DNS Bot <dns@example.org>",
+		GitExportDir:                    "/var/lib/external-dns/git",
+		NotifyWebhookURL:                "https://hooks.slack.com/services/T0/B0/X",
+		NotifyFormat:                    "slack",
+		NotifyZones:                     []string{"example.org", "company.com"},
+		NotifyMinChanges:                5,
+		VerifyResolvers:                 []string{"1.1.1.1", "8.8.8.8:53"},
+		VerifyTimeout:                   time.Second,
+		VerifyRetries:                   10,
+		VerifyInterval:                  30 * time.Second,
+		ManageZones:                     true,
+		ManageZonesNamespace:            "tenants",
+		DNSSECExportFile:                "/var/run/external-dns/ds.yaml",
+		ServiceImportNaming:             "annotation",
+		ClusterSetDomain:                "clusterset.example.org",
+		FQDNPolicy:                      "absolute",
+		AuditSinks:                      []string{"file", "events"},
+		AuditFile:                       "/var/log/external-dns-audit.log",
+		AuditWebhookURL:                 "http://localhost:8080/audit",
+		Registry:                        "noop",
+		TXTOwnerID:                      "owner-1",
+		TXTOwnerIDTemplate:              "{{ .Namespace }}",
+		ClusterID:                       "cluster-1",
+		TXTPrefix:                       "associated-txt-record",
+		TXTCacheInterval:                12 * time.Hour,
+		RegistryCacheInterval:           5 * time.Minute,
+		RegistryCacheSnapshotFile:       "/var/lib/external-dns/registry.json.gz",
+		Interval:                        10 * time.Minute,
+		SourceIntervals:                 []string{"node=1h"},
+		SourceMaxStaleness:              15 * time.Minute,
+		SourceConflictPolicy:            "merge-targets",
+		MinEventSyncInterval:            50 * time.Second,
+		FullReconcileInterval:           time.Hour,
+		DrainTimeout:                    time.Minute,
+		PendingChangesFile:              "/var/lib/external-dns/pending.json",
+		Once:                            true,
+		DryRun:                          true,
+		ValidateDryRun:                  true,
+		Preflight:                       true,
+		PreflightCredentialsFile:        "/var/run/secrets/provider/credentials",
+		UpdateEvents:                    true,
+		LogFormat:                       "json",
+		MetricsAddress:                  "127.0.0.1:9099",
+		MetricsTLSCertFile:              "/etc/metrics/tls.crt",
+		MetricsTLSKeyFile:               "/etc/metrics/tls.key",
+		MetricsTLSClientCAFile:          "/etc/metrics/ca.crt",
+		MetricsBearerTokenFile:          "/etc/metrics/token",
+		SyncEndpoint:                    true,
+		DebugRecordsEndpoint:            true,
+		DebugStatusEndpoint:             true,
+		ReadinessMaxSyncIntervals:       3,
+		TriggerSync:                     "https://external-dns:7979/sync",
+		TriggerSyncCAFile:               "/etc/metrics/ca.crt",
+		LogLevel:                        logrus.DebugLevel.String(),
+		ConnectorSourceServer:           "localhost:8081",
+		ConnectorSourceTLS:              true,
+		ConnectorSourceTLSCA:            "/etc/external-dns/connector/ca.crt",
+		ConnectorSourceTLSCert:          "/etc/external-dns/connector/tls.crt",
+		ConnectorSourceTLSKey:           "/etc/external-dns/connector/tls.key",
+		ConnectorSourceTokenFile:        "/var/run/secrets/connector/token",
+		ExoscaleAPIEnvironment:          "api1",
+		ExoscaleAPIZone:                 "zone1",
+		ExoscaleAPIKey:                  "1",
+		ExoscaleAPISecret:               "2",
+		CRDSourceAPIVersion:             "test.k8s.io/v1alpha1",
+		CRDSourceKind:                   "Endpoint",
+		CRDSourceNamespaces:             []string{"team-a", "team-b"},
+		CRDSourceLabelFilter:            "external-dns.io/instance=public",
+		RcodezeroTXTEncrypt:             true,
+		NS1Endpoint:                     "https://api.example.com/v1",
+		NS1IgnoreSSL:                    true,
+		TransIPAccountName:              "transip",
+		TransIPPrivateKeyFile:           "/path/to/transip.key",
+		DigitalOceanAPIPageSize:         100,
+		ManagedDNSRecordTypes:           []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeNS},
+		RFC2136BatchChangeSize:          100,
+		IBMCloudProxied:                 true,
+		IBMCloudConfigFile:              "ibmcloud.json",
+		TencentCloudConfigFile:          "tencent-cloud.json",
+		TencentCloudZoneType:            "private",
+		WebhookProviderURL:              "http://localhost:8888",
+		WebhookProviderReadTimeout:      5 * time.Second,
+		WebhookProviderWriteTimeout:     10 * time.Second,
 	}
 )
 
@@ -276,10 +413,29 @@ func TestParseFlags(t *testing.T) {
 			args: []string{
 				"--server=http://127.0.0.1:8080",
 				"--kubeconfig=/some/path",
+				"--member-cluster=name=eu,kubeconfig=/some/eu",
+				"--member-cluster=name=us,kubeconfig=/some/us,context=us-east",
 				"--request-timeout=77s",
 				"--gloo-namespace=gloo-not-system",
 				"--gloo-namespace=gloo-second-system",
 				"--skipper-routegroup-groupversion=zalando.org/v2",
+				"--istio-publish-mesh-only-hosts",
+				"--istio-gateway-any-namespace",
+				"--cilium-loadbalancer-mode=shared",
+				"--cilium-namespace=cilium",
+				"--traefik-disable-legacy",
+				"--traefik-disable-tcp",
+				"--gateway-envoy-services",
+				"--traefik-disable-udp",
+				"--traefik-service=traefik/traefik",
+				"--kong-proxy-service=kong/kong-proxy",
+				"--ambassador-service=emissary/emissary-ingress",
+				"--file-source-path=/etc/external-dns/endpoints.yaml",
+				"--file-source-configmap=external-dns/endpoints",
+				"--http-source-url=https://inventory.example.org/endpoints",
+				"--http-source-bearer-token-file=/var/run/secrets/inventory/token",
+				"--knative-routes",
+				"--knative-ingress-service=kourier-system/kourier",
 				"--source=service",
 				"--source=ingress",
 				"--source=connector",
@@ -289,9 +445,20 @@ func TestParseFlags(t *testing.T) {
 				"--ignore-ingress-tls-spec",
 				"--ignore-ingress-rules-spec",
 				"--compatibility=mate",
+				"--node-sshfp",
+				"--node-sshfp-secret=kube-system/ssh-host-keys",
+				"--node-label-filter=node-role.kubernetes.io/worker",
+				"--node-exclude-not-ready",
+				"--service-topology-hostnames",
+				"--node-exclude-unschedulable",
+				"--node-ipv4-address-type=InternalIP",
+				"--node-ipv6-address-type=ExternalIP",
+				"--pod-label-selector=app=worker",
+				"--pod-publish-pod-ip",
 				"--provider=google",
 				"--google-project=project",
 				"--google-batch-change-size=100",
+				"--google-zone-workers=4",
 				"--google-batch-change-interval=2s",
 				"--google-zone-visibility=private",
 				"--azure-config-file=azure.json",
@@ -307,6 +474,12 @@ func TestParseFlags(t *testing.T) {
 				"--bluecat-skip-tls-verify",
 				"--cloudflare-proxied",
 				"--cloudflare-dns-records-per-page=5000",
+				"--cloudflare-tunnel-id=c1f0b6d2-3f5e-4a8b-9c7d-2e1f0a9b8c7d",
+				"--cloudflare-tunnel-credentials-file=/etc/cloudflared/credentials.json",
+				"--tailscale-tailnet=example.com",
+				"--tailscale-api-key=tskey-api",
+				"--tailscale-nameserver=100.100.1.1",
+				"--tailscale-nameserver=100.100.1.2",
 				"--coredns-prefix=/coredns/",
 				"--akamai-serviceconsumerdomain=oooo-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net",
 				"--akamai-client-token=o184671d5307a388180fbf7f11dbdf46",
@@ -345,42 +518,127 @@ func TestParseFlags(t *testing.T) {
 				"--zone-name-filter=yapi.company.com",
 				"--zone-id-filter=/hostedzone/ZTST1",
 				"--zone-id-filter=/hostedzone/ZTST2",
+				"--zone-tags=team=platform",
+				"--zone-tags=public",
 				"--target-net-filter=10.0.0.0/9",
 				"--target-net-filter=10.1.0.0/9",
 				"--exclude-target-net=1.0.0.0/9",
 				"--exclude-target-net=1.1.0.0/9",
+				"--dns-rewrites",
+				"--transformations-file=/etc/external-dns/transformations.yaml",
+				"--endpoint-filter={{ not (isPrivateIP .Target) }}",
+				"--nat64-networks=64:ff9b::/96",
+				"--nat64-networks=2001:db8:64::/96",
+				"--nat64-suppress-a",
+				"--flatten-cnames",
 				"--aws-zone-type=private",
 				"--aws-zone-tags=tag=foo",
 				"--aws-assume-role=some-other-role",
 				"--aws-assume-role-external-id=pg2000",
 				"--aws-batch-change-size=100",
 				"--aws-batch-change-interval=2s",
+				"--aws-zone-workers=8",
+				"--aws-zone-change-rate=2.5",
 				"--aws-api-retries=13",
 				"--aws-prefer-cname",
 				"--aws-zones-cache-duration=10s",
 				"--aws-sd-service-cleanup",
 				"--no-aws-evaluate-target-health",
+				"--aws-health-checks",
 				"--policy=upsert-only",
+				"--target-normalization=case",
+				"--target-normalization=trailing-dot",
+				"--metadata-sensitive-diff",
+				"--ignore-ttl-differences",
+				"--min-ttl=1m",
+				"--default-ttl=A=1m",
+				"--default-ttl=TXT=1h",
+				"--deletion-grace-period=1h",
+				"--wildcard-policy=block",
+				"--change-window=0 22 * * 6 8h",
+				"--change-window-scope=deletions",
+				"--plan-approval",
+				"--plan-approval-namespace=dns-review",
+				"--plan-approval-expiry=30m",
+				"--dns-policies",
+				"--git-export-repository=https://git.example.org/dns.git",
+				"--git-export-branch=records",
+				"--git-export-path=clusters/cluster-1.zone",
+				"--git-export-format=zone",
+				"--git-export-author=DNS Bot <dns@example.org>",
+				"--git-export-dir=/var/lib/external-dns/git",
+				"--notify-webhook-url=https://hooks.slack.com/services/T0/B0/X",
+				"--notify-format=slack",
+				"--notify-zone=example.org",
+				"--notify-zone=company.com",
+				"--notify-min-changes=5",
+				"--verify-resolver=1.1.1.1",
+				"--verify-resolver=8.8.8.8:53",
+				"--verify-timeout=1s",
+				"--verify-retries=10",
+				"--verify-interval=30s",
+				"--manage-zones",
+				"--manage-zones-namespace=tenants",
+				"--dnssec-export-file=/var/run/external-dns/ds.yaml",
+				"--service-import-naming=annotation",
+				"--clusterset-domain=clusterset.example.org",
+				"--fqdn-policy=absolute",
+				"--audit-sink=file",
+				"--audit-sink=events",
+				"--audit-file=/var/log/external-dns-audit.log",
+				"--audit-webhook-url=http://localhost:8080/audit",
 				"--registry=noop",
 				"--txt-owner-id=owner-1",
+				"--txt-owner-id-template={{ .Namespace }}",
+				"--cluster-id=cluster-1",
 				"--txt-prefix=associated-txt-record",
 				"--txt-cache-interval=12h",
+				"--registry-cache-interval=5m",
+				"--registry-cache-snapshot-file=/var/lib/external-dns/registry.json.gz",
 				"--dynamodb-table=custom-table",
+				"--dynamodb-gc-grace-period=1h",
 				"--interval=10m",
+				"--source-interval=node=1h",
+				"--source-max-staleness=15m",
+				"--source-conflict-policy=merge-targets",
 				"--min-event-sync-interval=50s",
+				"--full-reconcile-interval=1h",
+				"--drain-timeout=1m",
+				"--pending-changes-file=/var/lib/external-dns/pending.json",
 				"--once",
 				"--dry-run",
+				"--validate-dry-run",
+				"--preflight",
+				"--preflight-credentials-file=/var/run/secrets/provider/credentials",
 				"--events",
 				"--log-format=json",
 				"--metrics-address=127.0.0.1:9099",
+				"--metrics-tls-cert-file=/etc/metrics/tls.crt",
+				"--metrics-tls-key-file=/etc/metrics/tls.key",
+				"--metrics-tls-client-ca-file=/etc/metrics/ca.crt",
+				"--metrics-bearer-token-file=/etc/metrics/token",
+				"--sync-endpoint",
+				"--debug-records-endpoint",
+				"--debug-status-endpoint",
+				"--readiness-max-sync-intervals=3",
+				"--trigger-sync=https://external-dns:7979/sync",
+				"--trigger-sync-ca-file=/etc/metrics/ca.crt",
 				"--log-level=debug",
 				"--connector-source-server=localhost:8081",
+				"--connector-source-tls",
+				"--connector-source-tls-ca=/etc/external-dns/connector/ca.crt",
+				"--connector-source-tls-cert=/etc/external-dns/connector/tls.crt",
+				"--connector-source-tls-key=/etc/external-dns/connector/tls.key",
+				"--connector-source-token-file=/var/run/secrets/connector/token",
 				"--exoscale-apienv=api1",
 				"--exoscale-apizone=zone1",
 				"--exoscale-apikey=1",
 				"--exoscale-apisecret=2",
 				"--crd-source-apiversion=test.k8s.io/v1alpha1",
 				"--crd-source-kind=Endpoint",
+				"--crd-source-namespace=team-a",
+				"--crd-source-namespace=team-b",
+				"--crd-source-label-filter=external-dns.io/instance=public",
 				"--rcodezero-txt-encrypt",
 				"--ns1-endpoint=https://api.example.com/v1",
 				"--ns1-ignoressl",
@@ -404,117 +662,228 @@ func TestParseFlags(t *testing.T) {
 			title: "override everything via environment variables",
 			args:  []string{},
 			envVars: map[string]string{
-				"EXTERNAL_DNS_SERVER":                          "http://127.0.0.1:8080",
-				"EXTERNAL_DNS_KUBECONFIG":                      "/some/path",
-				"EXTERNAL_DNS_REQUEST_TIMEOUT":                 "77s",
-				"EXTERNAL_DNS_CONTOUR_LOAD_BALANCER":           "heptio-contour-other/contour-other",
-				"EXTERNAL_DNS_GLOO_NAMESPACE":                  "gloo-not-system\ngloo-second-system",
-				"EXTERNAL_DNS_SKIPPER_ROUTEGROUP_GROUPVERSION": "zalando.org/v2",
-				"EXTERNAL_DNS_SOURCE":                          "service\ningress\nconnector",
-				"EXTERNAL_DNS_NAMESPACE":                       "namespace",
-				"EXTERNAL_DNS_FQDN_TEMPLATE":                   "{{.Name}}.service.example.com",
-				"EXTERNAL_DNS_IGNORE_HOSTNAME_ANNOTATION":      "1",
-				"EXTERNAL_DNS_IGNORE_INGRESS_TLS_SPEC":         "1",
-				"EXTERNAL_DNS_IGNORE_INGRESS_RULES_SPEC":       "1",
-				"EXTERNAL_DNS_COMPATIBILITY":                   "mate",
-				"EXTERNAL_DNS_PROVIDER":                        "google",
-				"EXTERNAL_DNS_GOOGLE_PROJECT":                  "project",
-				"EXTERNAL_DNS_GOOGLE_BATCH_CHANGE_SIZE":        "100",
-				"EXTERNAL_DNS_GOOGLE_BATCH_CHANGE_INTERVAL":    "2s",
-				"EXTERNAL_DNS_GOOGLE_ZONE_VISIBILITY":          "private",
-				"EXTERNAL_DNS_AZURE_CONFIG_FILE":               "azure.json",
-				"EXTERNAL_DNS_AZURE_RESOURCE_GROUP":            "arg",
-				"EXTERNAL_DNS_AZURE_SUBSCRIPTION_ID":           "arg",
-				"EXTERNAL_DNS_BLUECAT_DNS_CONFIGURATION":       "arg",
-				"EXTERNAL_DNS_BLUECAT_DNS_SERVER_NAME":         "arg",
-				"EXTERNAL_DNS_BLUECAT_DNS_DEPLOY_TYPE":         "full-deploy",
-				"EXTERNAL_DNS_BLUECAT_CONFIG_FILE":             "bluecat.json",
-				"EXTERNAL_DNS_BLUECAT_DNS_VIEW":                "arg",
-				"EXTERNAL_DNS_BLUECAT_GATEWAY_HOST":            "arg",
-				"EXTERNAL_DNS_BLUECAT_ROOT_ZONE":               "arg",
-				"EXTERNAL_DNS_BLUECAT_SKIP_TLS_VERIFY":         "1",
-				"EXTERNAL_DNS_CLOUDFLARE_PROXIED":              "1",
-				"EXTERNAL_DNS_CLOUDFLARE_DNS_RECORDS_PER_PAGE": "5000",
-				"EXTERNAL_DNS_COREDNS_PREFIX":                  "/coredns/",
-				"EXTERNAL_DNS_AKAMAI_SERVICECONSUMERDOMAIN":    "oooo-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net",
-				"EXTERNAL_DNS_AKAMAI_CLIENT_TOKEN":             "o184671d5307a388180fbf7f11dbdf46",
-				"EXTERNAL_DNS_AKAMAI_CLIENT_SECRET":            "o184671d5307a388180fbf7f11dbdf46",
-				"EXTERNAL_DNS_AKAMAI_ACCESS_TOKEN":             "o184671d5307a388180fbf7f11dbdf46",
-				"EXTERNAL_DNS_AKAMAI_EDGERC_PATH":              "/home/test/.edgerc",
-				"EXTERNAL_DNS_AKAMAI_EDGERC_SECTION":           "default",
-				"EXTERNAL_DNS_INFOBLOX_GRID_HOST":              "127.0.0.1",
-				"EXTERNAL_DNS_INFOBLOX_WAPI_PORT":              "8443",
-				"EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME":          "infoblox",
-				"EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD":          "infoblox",
-				"EXTERNAL_DNS_INFOBLOX_WAPI_VERSION":           "2.6.1",
-				"EXTERNAL_DNS_INFOBLOX_VIEW":                   "internal",
-				"EXTERNAL_DNS_INFOBLOX_SSL_VERIFY":             "0",
-				"EXTERNAL_DNS_INFOBLOX_MAX_RESULTS":            "2000",
-				"EXTERNAL_DNS_OCI_CONFIG_FILE":                 "oci.yaml",
-				"EXTERNAL_DNS_OCI_ZONE_SCOPE":                  "PRIVATE",
-				"EXTERNAL_DNS_OCI_ZONES_CACHE_DURATION":        "30s",
-				"EXTERNAL_DNS_INMEMORY_ZONE":                   "example.org\ncompany.com",
-				"EXTERNAL_DNS_OVH_ENDPOINT":                    "ovh-ca",
-				"EXTERNAL_DNS_OVH_API_RATE_LIMIT":              "42",
-				"EXTERNAL_DNS_DOMAIN_FILTER":                   "example.org\ncompany.com",
-				"EXTERNAL_DNS_EXCLUDE_DOMAINS":                 "xapi.example.org\nxapi.company.com",
-				"EXTERNAL_DNS_REGEX_DOMAIN_FILTER":             "(example\\.org|company\\.com)$",
-				"EXTERNAL_DNS_REGEX_DOMAIN_EXCLUSION":          "xapi\\.(example\\.org|company\\.com)$",
-				"EXTERNAL_DNS_TARGET_NET_FILTER":               "10.0.0.0/9\n10.1.0.0/9",
-				"EXTERNAL_DNS_EXCLUDE_TARGET_NET":              "1.0.0.0/9\n1.1.0.0/9",
-				"EXTERNAL_DNS_PDNS_SERVER":                     "http://ns.example.com:8081",
-				"EXTERNAL_DNS_PDNS_API_KEY":                    "some-secret-key",
-				"EXTERNAL_DNS_PDNS_SKIP_TLS_VERIFY":            "1",
-				"EXTERNAL_DNS_RDNS_ROOT_DOMAIN":                "lb.rancher.cloud",
-				"EXTERNAL_DNS_TLS_CA":                          "/path/to/ca.crt",
-				"EXTERNAL_DNS_TLS_CLIENT_CERT":                 "/path/to/cert.pem",
-				"EXTERNAL_DNS_TLS_CLIENT_CERT_KEY":             "/path/to/key.pem",
-				"EXTERNAL_DNS_ZONE_NAME_FILTER":                "yapi.example.org\nyapi.company.com",
-				"EXTERNAL_DNS_ZONE_ID_FILTER":                  "/hostedzone/ZTST1\n/hostedzone/ZTST2",
-				"EXTERNAL_DNS_AWS_ZONE_TYPE":                   "private",
-				"EXTERNAL_DNS_AWS_ZONE_TAGS":                   "tag=foo",
-				"EXTERNAL_DNS_AWS_ASSUME_ROLE":                 "some-other-role",
-				"EXTERNAL_DNS_AWS_ASSUME_ROLE_EXTERNAL_ID":     "pg2000",
-				"EXTERNAL_DNS_AWS_BATCH_CHANGE_SIZE":           "100",
-				"EXTERNAL_DNS_AWS_BATCH_CHANGE_INTERVAL":       "2s",
-				"EXTERNAL_DNS_AWS_EVALUATE_TARGET_HEALTH":      "0",
-				"EXTERNAL_DNS_AWS_API_RETRIES":                 "13",
-				"EXTERNAL_DNS_AWS_PREFER_CNAME":                "true",
-				"EXTERNAL_DNS_AWS_ZONES_CACHE_DURATION":        "10s",
-				"EXTERNAL_DNS_AWS_SD_SERVICE_CLEANUP":          "true",
-				"EXTERNAL_DNS_DYNAMODB_TABLE":                  "custom-table",
-				"EXTERNAL_DNS_POLICY":                          "upsert-only",
-				"EXTERNAL_DNS_REGISTRY":                        "noop",
-				"EXTERNAL_DNS_TXT_OWNER_ID":                    "owner-1",
-				"EXTERNAL_DNS_TXT_PREFIX":                      "associated-txt-record",
-				"EXTERNAL_DNS_TXT_CACHE_INTERVAL":              "12h",
-				"EXTERNAL_DNS_INTERVAL":                        "10m",
-				"EXTERNAL_DNS_MIN_EVENT_SYNC_INTERVAL":         "50s",
-				"EXTERNAL_DNS_ONCE":                            "1",
-				"EXTERNAL_DNS_DRY_RUN":                         "1",
-				"EXTERNAL_DNS_EVENTS":                          "1",
-				"EXTERNAL_DNS_LOG_FORMAT":                      "json",
-				"EXTERNAL_DNS_METRICS_ADDRESS":                 "127.0.0.1:9099",
-				"EXTERNAL_DNS_LOG_LEVEL":                       "debug",
-				"EXTERNAL_DNS_CONNECTOR_SOURCE_SERVER":         "localhost:8081",
-				"EXTERNAL_DNS_EXOSCALE_APIENV":                 "api1",
-				"EXTERNAL_DNS_EXOSCALE_APIZONE":                "zone1",
-				"EXTERNAL_DNS_EXOSCALE_APIKEY":                 "1",
-				"EXTERNAL_DNS_EXOSCALE_APISECRET":              "2",
-				"EXTERNAL_DNS_CRD_SOURCE_APIVERSION":           "test.k8s.io/v1alpha1",
-				"EXTERNAL_DNS_CRD_SOURCE_KIND":                 "Endpoint",
-				"EXTERNAL_DNS_RCODEZERO_TXT_ENCRYPT":           "1",
-				"EXTERNAL_DNS_NS1_ENDPOINT":                    "https://api.example.com/v1",
-				"EXTERNAL_DNS_NS1_IGNORESSL":                   "1",
-				"EXTERNAL_DNS_TRANSIP_ACCOUNT":                 "transip",
-				"EXTERNAL_DNS_TRANSIP_KEYFILE":                 "/path/to/transip.key",
-				"EXTERNAL_DNS_DIGITALOCEAN_API_PAGE_SIZE":      "100",
-				"EXTERNAL_DNS_MANAGED_RECORD_TYPES":            "A\nAAAA\nCNAME\nNS",
-				"EXTERNAL_DNS_RFC2136_BATCH_CHANGE_SIZE":       "100",
-				"EXTERNAL_DNS_IBMCLOUD_PROXIED":                "1",
-				"EXTERNAL_DNS_IBMCLOUD_CONFIG_FILE":            "ibmcloud.json",
-				"EXTERNAL_DNS_TENCENT_CLOUD_CONFIG_FILE":       "tencent-cloud.json",
-				"EXTERNAL_DNS_TENCENT_CLOUD_ZONE_TYPE":         "private",
+				"EXTERNAL_DNS_SERVER":                             "http://127.0.0.1:8080",
+				"EXTERNAL_DNS_KUBECONFIG":                         "/some/path",
+				"EXTERNAL_DNS_MEMBER_CLUSTER":                     "name=eu,kubeconfig=/some/eu\nname=us,kubeconfig=/some/us,context=us-east",
+				"EXTERNAL_DNS_REQUEST_TIMEOUT":                    "77s",
+				"EXTERNAL_DNS_CONTOUR_LOAD_BALANCER":              "heptio-contour-other/contour-other",
+				"EXTERNAL_DNS_GLOO_NAMESPACE":                     "gloo-not-system\ngloo-second-system",
+				"EXTERNAL_DNS_SKIPPER_ROUTEGROUP_GROUPVERSION":    "zalando.org/v2",
+				"EXTERNAL_DNS_ISTIO_PUBLISH_MESH_ONLY_HOSTS":      "1",
+				"EXTERNAL_DNS_ISTIO_GATEWAY_ANY_NAMESPACE":        "1",
+				"EXTERNAL_DNS_CILIUM_LOADBALANCER_MODE":           "shared",
+				"EXTERNAL_DNS_CILIUM_NAMESPACE":                   "cilium",
+				"EXTERNAL_DNS_TRAEFIK_DISABLE_LEGACY":             "1",
+				"EXTERNAL_DNS_TRAEFIK_DISABLE_TCP":                "1",
+				"EXTERNAL_DNS_GATEWAY_ENVOY_SERVICES":             "1",
+				"EXTERNAL_DNS_TRAEFIK_DISABLE_UDP":                "1",
+				"EXTERNAL_DNS_TRAEFIK_SERVICE":                    "traefik/traefik",
+				"EXTERNAL_DNS_KONG_PROXY_SERVICE":                 "kong/kong-proxy",
+				"EXTERNAL_DNS_AMBASSADOR_SERVICE":                 "emissary/emissary-ingress",
+				"EXTERNAL_DNS_FILE_SOURCE_PATH":                   "/etc/external-dns/endpoints.yaml",
+				"EXTERNAL_DNS_FILE_SOURCE_CONFIGMAP":              "external-dns/endpoints",
+				"EXTERNAL_DNS_HTTP_SOURCE_URL":                    "https://inventory.example.org/endpoints",
+				"EXTERNAL_DNS_HTTP_SOURCE_BEARER_TOKEN_FILE":      "/var/run/secrets/inventory/token",
+				"EXTERNAL_DNS_KNATIVE_ROUTES":                     "1",
+				"EXTERNAL_DNS_KNATIVE_INGRESS_SERVICE":            "kourier-system/kourier",
+				"EXTERNAL_DNS_SOURCE":                             "service\ningress\nconnector",
+				"EXTERNAL_DNS_NAMESPACE":                          "namespace",
+				"EXTERNAL_DNS_FQDN_TEMPLATE":                      "{{.Name}}.service.example.com",
+				"EXTERNAL_DNS_IGNORE_HOSTNAME_ANNOTATION":         "1",
+				"EXTERNAL_DNS_IGNORE_INGRESS_TLS_SPEC":            "1",
+				"EXTERNAL_DNS_IGNORE_INGRESS_RULES_SPEC":          "1",
+				"EXTERNAL_DNS_COMPATIBILITY":                      "mate",
+				"EXTERNAL_DNS_NODE_SSHFP":                         "1",
+				"EXTERNAL_DNS_NODE_SSHFP_SECRET":                  "kube-system/ssh-host-keys",
+				"EXTERNAL_DNS_NODE_LABEL_FILTER":                  "node-role.kubernetes.io/worker",
+				"EXTERNAL_DNS_NODE_EXCLUDE_NOT_READY":             "1",
+				"EXTERNAL_DNS_SERVICE_TOPOLOGY_HOSTNAMES":         "1",
+				"EXTERNAL_DNS_NODE_EXCLUDE_UNSCHEDULABLE":         "1",
+				"EXTERNAL_DNS_NODE_IPV4_ADDRESS_TYPE":             "InternalIP",
+				"EXTERNAL_DNS_NODE_IPV6_ADDRESS_TYPE":             "ExternalIP",
+				"EXTERNAL_DNS_POD_LABEL_SELECTOR":                 "app=worker",
+				"EXTERNAL_DNS_POD_PUBLISH_POD_IP":                 "1",
+				"EXTERNAL_DNS_PROVIDER":                           "google",
+				"EXTERNAL_DNS_GOOGLE_PROJECT":                     "project",
+				"EXTERNAL_DNS_GOOGLE_BATCH_CHANGE_SIZE":           "100",
+				"EXTERNAL_DNS_GOOGLE_ZONE_WORKERS":                "4",
+				"EXTERNAL_DNS_GOOGLE_BATCH_CHANGE_INTERVAL":       "2s",
+				"EXTERNAL_DNS_GOOGLE_ZONE_VISIBILITY":             "private",
+				"EXTERNAL_DNS_AZURE_CONFIG_FILE":                  "azure.json",
+				"EXTERNAL_DNS_AZURE_RESOURCE_GROUP":               "arg",
+				"EXTERNAL_DNS_AZURE_SUBSCRIPTION_ID":              "arg",
+				"EXTERNAL_DNS_BLUECAT_DNS_CONFIGURATION":          "arg",
+				"EXTERNAL_DNS_BLUECAT_DNS_SERVER_NAME":            "arg",
+				"EXTERNAL_DNS_BLUECAT_DNS_DEPLOY_TYPE":            "full-deploy",
+				"EXTERNAL_DNS_BLUECAT_CONFIG_FILE":                "bluecat.json",
+				"EXTERNAL_DNS_BLUECAT_DNS_VIEW":                   "arg",
+				"EXTERNAL_DNS_BLUECAT_GATEWAY_HOST":               "arg",
+				"EXTERNAL_DNS_BLUECAT_ROOT_ZONE":                  "arg",
+				"EXTERNAL_DNS_BLUECAT_SKIP_TLS_VERIFY":            "1",
+				"EXTERNAL_DNS_CLOUDFLARE_PROXIED":                 "1",
+				"EXTERNAL_DNS_CLOUDFLARE_DNS_RECORDS_PER_PAGE":    "5000",
+				"EXTERNAL_DNS_CLOUDFLARE_TUNNEL_ID":               "c1f0b6d2-3f5e-4a8b-9c7d-2e1f0a9b8c7d",
+				"EXTERNAL_DNS_CLOUDFLARE_TUNNEL_CREDENTIALS_FILE": "/etc/cloudflared/credentials.json",
+				"EXTERNAL_DNS_TAILSCALE_TAILNET":                  "example.com",
+				"EXTERNAL_DNS_TAILSCALE_API_KEY":                  "tskey-api",
+				"EXTERNAL_DNS_TAILSCALE_NAMESERVER":               "100.100.1.1\n100.100.1.2",
+				"EXTERNAL_DNS_COREDNS_PREFIX":                     "/coredns/",
+				"EXTERNAL_DNS_AKAMAI_SERVICECONSUMERDOMAIN":       "oooo-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net",
+				"EXTERNAL_DNS_AKAMAI_CLIENT_TOKEN":                "o184671d5307a388180fbf7f11dbdf46",
+				"EXTERNAL_DNS_AKAMAI_CLIENT_SECRET":               "o184671d5307a388180fbf7f11dbdf46",
+				"EXTERNAL_DNS_AKAMAI_ACCESS_TOKEN":                "o184671d5307a388180fbf7f11dbdf46",
+				"EXTERNAL_DNS_AKAMAI_EDGERC_PATH":                 "/home/test/.edgerc",
+				"EXTERNAL_DNS_AKAMAI_EDGERC_SECTION":              "default",
+				"EXTERNAL_DNS_INFOBLOX_GRID_HOST":                 "127.0.0.1",
+				"EXTERNAL_DNS_INFOBLOX_WAPI_PORT":                 "8443",
+				"EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME":             "infoblox",
+				"EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD":             "infoblox",
+				"EXTERNAL_DNS_INFOBLOX_WAPI_VERSION":              "2.6.1",
+				"EXTERNAL_DNS_INFOBLOX_VIEW":                      "internal",
+				"EXTERNAL_DNS_INFOBLOX_SSL_VERIFY":                "0",
+				"EXTERNAL_DNS_INFOBLOX_MAX_RESULTS":               "2000",
+				"EXTERNAL_DNS_OCI_CONFIG_FILE":                    "oci.yaml",
+				"EXTERNAL_DNS_OCI_ZONE_SCOPE":                     "PRIVATE",
+				"EXTERNAL_DNS_OCI_ZONES_CACHE_DURATION":           "30s",
+				"EXTERNAL_DNS_INMEMORY_ZONE":                      "example.org\ncompany.com",
+				"EXTERNAL_DNS_OVH_ENDPOINT":                       "ovh-ca",
+				"EXTERNAL_DNS_OVH_API_RATE_LIMIT":                 "42",
+				"EXTERNAL_DNS_DOMAIN_FILTER":                      "example.org\ncompany.com",
+				"EXTERNAL_DNS_EXCLUDE_DOMAINS":                    "xapi.example.org\nxapi.company.com",
+				"EXTERNAL_DNS_REGEX_DOMAIN_FILTER":                "(example\\.org|company\\.com)$",
+				"EXTERNAL_DNS_REGEX_DOMAIN_EXCLUSION":             "xapi\\.(example\\.org|company\\.com)$",
+				"EXTERNAL_DNS_TARGET_NET_FILTER":                  "10.0.0.0/9\n10.1.0.0/9",
+				"EXTERNAL_DNS_EXCLUDE_TARGET_NET":                 "1.0.0.0/9\n1.1.0.0/9",
+				"EXTERNAL_DNS_DNS_REWRITES":                       "1",
+				"EXTERNAL_DNS_TRANSFORMATIONS_FILE":               "/etc/external-dns/transformations.yaml",
+				"EXTERNAL_DNS_ENDPOINT_FILTER":                    "{{ not (isPrivateIP .Target) }}",
+				"EXTERNAL_DNS_NAT64_NETWORKS":                     "64:ff9b::/96\n2001:db8:64::/96",
+				"EXTERNAL_DNS_NAT64_SUPPRESS_A":                   "1",
+				"EXTERNAL_DNS_FLATTEN_CNAMES":                     "1",
+				"EXTERNAL_DNS_PDNS_SERVER":                        "http://ns.example.com:8081",
+				"EXTERNAL_DNS_PDNS_API_KEY":                       "some-secret-key",
+				"EXTERNAL_DNS_PDNS_SKIP_TLS_VERIFY":               "1",
+				"EXTERNAL_DNS_RDNS_ROOT_DOMAIN":                   "lb.rancher.cloud",
+				"EXTERNAL_DNS_TLS_CA":                             "/path/to/ca.crt",
+				"EXTERNAL_DNS_TLS_CLIENT_CERT":                    "/path/to/cert.pem",
+				"EXTERNAL_DNS_TLS_CLIENT_CERT_KEY":                "/path/to/key.pem",
+				"EXTERNAL_DNS_ZONE_NAME_FILTER":                   "yapi.example.org\nyapi.company.com",
+				"EXTERNAL_DNS_ZONE_ID_FILTER":                     "/hostedzone/ZTST1\n/hostedzone/ZTST2",
+				"EXTERNAL_DNS_ZONE_TAGS":                          "team=platform\npublic",
+				"EXTERNAL_DNS_AWS_ZONE_TYPE":                      "private",
+				"EXTERNAL_DNS_AWS_ZONE_TAGS":                      "tag=foo",
+				"EXTERNAL_DNS_AWS_ASSUME_ROLE":                    "some-other-role",
+				"EXTERNAL_DNS_AWS_ASSUME_ROLE_EXTERNAL_ID":        "pg2000",
+				"EXTERNAL_DNS_AWS_BATCH_CHANGE_SIZE":              "100",
+				"EXTERNAL_DNS_AWS_BATCH_CHANGE_INTERVAL":          "2s",
+				"EXTERNAL_DNS_AWS_ZONE_WORKERS":                   "8",
+				"EXTERNAL_DNS_AWS_ZONE_CHANGE_RATE":               "2.5",
+				"EXTERNAL_DNS_AWS_EVALUATE_TARGET_HEALTH":         "0",
+				"EXTERNAL_DNS_AWS_HEALTH_CHECKS":                  "1",
+				"EXTERNAL_DNS_AWS_API_RETRIES":                    "13",
+				"EXTERNAL_DNS_AWS_PREFER_CNAME":                   "true",
+				"EXTERNAL_DNS_AWS_ZONES_CACHE_DURATION":           "10s",
+				"EXTERNAL_DNS_AWS_SD_SERVICE_CLEANUP":             "true",
+				"EXTERNAL_DNS_DYNAMODB_TABLE":                     "custom-table",
+				"EXTERNAL_DNS_DYNAMODB_GC_GRACE_PERIOD":           "1h",
+				"EXTERNAL_DNS_POLICY":                             "upsert-only",
+				"EXTERNAL_DNS_TARGET_NORMALIZATION":               "case\ntrailing-dot",
+				"EXTERNAL_DNS_METADATA_SENSITIVE_DIFF":            "1",
+				"EXTERNAL_DNS_IGNORE_TTL_DIFFERENCES":             "1",
+				"EXTERNAL_DNS_MIN_TTL":                            "1m",
+				"EXTERNAL_DNS_DEFAULT_TTL":                        "A=1m\nTXT=1h",
+				"EXTERNAL_DNS_DELETION_GRACE_PERIOD":              "1h",
+				"EXTERNAL_DNS_WILDCARD_POLICY":                    "block",
+				"EXTERNAL_DNS_CHANGE_WINDOW":                      "0 22 * * 6 8h",
+				"EXTERNAL_DNS_CHANGE_WINDOW_SCOPE":                "deletions",
+				"EXTERNAL_DNS_PLAN_APPROVAL":                      "1",
+				"EXTERNAL_DNS_PLAN_APPROVAL_NAMESPACE":            "dns-review",
+				"EXTERNAL_DNS_PLAN_APPROVAL_EXPIRY":               "30m",
+				"EXTERNAL_DNS_DNS_POLICIES":                       "1",
+				"EXTERNAL_DNS_GIT_EXPORT_REPOSITORY":              "https://git.example.org/dns.git",
+				"EXTERNAL_DNS_GIT_EXPORT_BRANCH":                  "records",
+				"EXTERNAL_DNS_GIT_EXPORT_PATH":                    "clusters/cluster-1.zone",
+				"EXTERNAL_DNS_GIT_EXPORT_FORMAT":                  "zone",
+				"EXTERNAL_DNS_GIT_EXPORT_AUTHOR":                  "DNS Bot <dns@example.org>",
+				"EXTERNAL_DNS_GIT_EXPORT_DIR":                     "/var/lib/external-dns/git",
+				"EXTERNAL_DNS_NOTIFY_WEBHOOK_URL":                 "https://hooks.slack.com/services/T0/B0/X",
+				"EXTERNAL_DNS_NOTIFY_FORMAT":                      "slack",
+				"EXTERNAL_DNS_NOTIFY_ZONE":                        "example.org\ncompany.com",
+				"EXTERNAL_DNS_NOTIFY_MIN_CHANGES":                 "5",
+				"EXTERNAL_DNS_VERIFY_RESOLVER":                    "1.1.1.1\n8.8.8.8:53",
+				"EXTERNAL_DNS_VERIFY_TIMEOUT":                     "1s",
+				"EXTERNAL_DNS_VERIFY_RETRIES":                     "10",
+				"EXTERNAL_DNS_VERIFY_INTERVAL":                    "30s",
+				"EXTERNAL_DNS_MANAGE_ZONES":                       "1",
+				"EXTERNAL_DNS_MANAGE_ZONES_NAMESPACE":             "tenants",
+				"EXTERNAL_DNS_DNSSEC_EXPORT_FILE":                 "/var/run/external-dns/ds.yaml",
+				"EXTERNAL_DNS_SERVICE_IMPORT_NAMING":              "annotation",
+				"EXTERNAL_DNS_CLUSTERSET_DOMAIN":                  "clusterset.example.org",
+				"EXTERNAL_DNS_FQDN_POLICY":                        "absolute",
+				"EXTERNAL_DNS_AUDIT_SINK":                         "file\nevents",
+				"EXTERNAL_DNS_AUDIT_FILE":                         "/var/log/external-dns-audit.log",
+				"EXTERNAL_DNS_AUDIT_WEBHOOK_URL":                  "http://localhost:8080/audit",
+				"EXTERNAL_DNS_REGISTRY":                           "noop",
+				"EXTERNAL_DNS_TXT_OWNER_ID":                       "owner-1",
+				"EXTERNAL_DNS_TXT_OWNER_ID_TEMPLATE":              "{{ .Namespace }}",
+				"EXTERNAL_DNS_CLUSTER_ID":                         "cluster-1",
+				"EXTERNAL_DNS_TXT_PREFIX":                         "associated-txt-record",
+				"EXTERNAL_DNS_TXT_CACHE_INTERVAL":                 "12h",
+				"EXTERNAL_DNS_REGISTRY_CACHE_INTERVAL":            "5m",
+				"EXTERNAL_DNS_REGISTRY_CACHE_SNAPSHOT_FILE":       "/var/lib/external-dns/registry.json.gz",
+				"EXTERNAL_DNS_INTERVAL":                           "10m",
+				"EXTERNAL_DNS_SOURCE_INTERVAL":                    "node=1h",
+				"EXTERNAL_DNS_SOURCE_MAX_STALENESS":               "15m",
+				"EXTERNAL_DNS_SOURCE_CONFLICT_POLICY":             "merge-targets",
+				"EXTERNAL_DNS_MIN_EVENT_SYNC_INTERVAL":            "50s",
+				"EXTERNAL_DNS_FULL_RECONCILE_INTERVAL":            "1h",
+				"EXTERNAL_DNS_DRAIN_TIMEOUT":                      "1m",
+				"EXTERNAL_DNS_PENDING_CHANGES_FILE":               "/var/lib/external-dns/pending.json",
+				"EXTERNAL_DNS_ONCE":                               "1",
+				"EXTERNAL_DNS_DRY_RUN":                            "1",
+				"EXTERNAL_DNS_VALIDATE_DRY_RUN":                   "1",
+				"EXTERNAL_DNS_PREFLIGHT":                          "1",
+				"EXTERNAL_DNS_PREFLIGHT_CREDENTIALS_FILE":         "/var/run/secrets/provider/credentials",
+				"EXTERNAL_DNS_EVENTS":                             "1",
+				"EXTERNAL_DNS_LOG_FORMAT":                         "json",
+				"EXTERNAL_DNS_METRICS_ADDRESS":                    "127.0.0.1:9099",
+				"EXTERNAL_DNS_METRICS_TLS_CERT_FILE":              "/etc/metrics/tls.crt",
+				"EXTERNAL_DNS_METRICS_TLS_KEY_FILE":               "/etc/metrics/tls.key",
+				"EXTERNAL_DNS_METRICS_TLS_CLIENT_CA_FILE":         "/etc/metrics/ca.crt",
+				"EXTERNAL_DNS_METRICS_BEARER_TOKEN_FILE":          "/etc/metrics/token",
+				"EXTERNAL_DNS_SYNC_ENDPOINT":                      "1",
+				"EXTERNAL_DNS_DEBUG_RECORDS_ENDPOINT":             "1",
+				"EXTERNAL_DNS_DEBUG_STATUS_ENDPOINT":              "1",
+				"EXTERNAL_DNS_READINESS_MAX_SYNC_INTERVALS":       "3",
+				"EXTERNAL_DNS_TRIGGER_SYNC":                       "https://external-dns:7979/sync",
+				"EXTERNAL_DNS_TRIGGER_SYNC_CA_FILE":               "/etc/metrics/ca.crt",
+				"EXTERNAL_DNS_LOG_LEVEL":                          "debug",
+				"EXTERNAL_DNS_CONNECTOR_SOURCE_SERVER":            "localhost:8081",
+				"EXTERNAL_DNS_CONNECTOR_SOURCE_TLS":               "1",
+				"EXTERNAL_DNS_CONNECTOR_SOURCE_TLS_CA":            "/etc/external-dns/connector/ca.crt",
+				"EXTERNAL_DNS_CONNECTOR_SOURCE_TLS_CERT":          "/etc/external-dns/connector/tls.crt",
+				"EXTERNAL_DNS_CONNECTOR_SOURCE_TLS_KEY":           "/etc/external-dns/connector/tls.key",
+				"EXTERNAL_DNS_CONNECTOR_SOURCE_TOKEN_FILE":        "/var/run/secrets/connector/token",
+				"EXTERNAL_DNS_EXOSCALE_APIENV":                    "api1",
+				"EXTERNAL_DNS_EXOSCALE_APIZONE":                   "zone1",
+				"EXTERNAL_DNS_EXOSCALE_APIKEY":                    "1",
+				"EXTERNAL_DNS_EXOSCALE_APISECRET":                 "2",
+				"EXTERNAL_DNS_CRD_SOURCE_APIVERSION":              "test.k8s.io/v1alpha1",
+				"EXTERNAL_DNS_CRD_SOURCE_KIND":                    "Endpoint",
+				"EXTERNAL_DNS_CRD_SOURCE_NAMESPACE":               "team-a\nteam-b",
+				"EXTERNAL_DNS_CRD_SOURCE_LABEL_FILTER":            "external-dns.io/instance=public",
+				"EXTERNAL_DNS_RCODEZERO_TXT_ENCRYPT":              "1",
+				"EXTERNAL_DNS_NS1_ENDPOINT":                       "https://api.example.com/v1",
+				"EXTERNAL_DNS_NS1_IGNORESSL":                      "1",
+				"EXTERNAL_DNS_TRANSIP_ACCOUNT":                    "transip",
+				"EXTERNAL_DNS_TRANSIP_KEYFILE":                    "/path/to/transip.key",
+				"EXTERNAL_DNS_DIGITALOCEAN_API_PAGE_SIZE":         "100",
+				"EXTERNAL_DNS_MANAGED_RECORD_TYPES":               "A\nAAAA\nCNAME\nNS",
+				"EXTERNAL_DNS_RFC2136_BATCH_CHANGE_SIZE":          "100",
+				"EXTERNAL_DNS_IBMCLOUD_PROXIED":                   "1",
+				"EXTERNAL_DNS_IBMCLOUD_CONFIG_FILE":               "ibmcloud.json",
+				"EXTERNAL_DNS_TENCENT_CLOUD_CONFIG_FILE":          "tencent-cloud.json",
+				"EXTERNAL_DNS_TENCENT_CLOUD_ZONE_TYPE":            "private",
 			},
 			expected: overriddenConfig,
 		},
@@ -549,12 +918,54 @@ func restoreEnv(t *testing.T, originalEnv map[string]string) {
 	}
 }
 
+func TestParseFlagsConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags")
+	require.NoError(t, os.WriteFile(path, []byte("# reloaded without restarting\n--source=ingress\n\n  --domain-filter=example.org  \n--dry-run\n"), 0o600))
+
+	cfg := NewConfig()
+	require.NoError(t, cfg.ParseFlags([]string{"--provider=aws", "--source=service", "--config-file=" + path}))
+	assert.Equal(t, []string{"service"}, cfg.Sources)
+	assert.Equal(t, []string{"example.org"}, cfg.DomainFilter)
+	assert.True(t, cfg.DryRun)
+	assert.Equal(t, path, cfg.ConfigFile)
+
+	assert.ErrorContains(t, NewConfig().ParseFlags([]string{"--provider=aws", "--source=service", "--config-file=" + path + ".missing"}), "failed to read the config file")
+}
+
+func TestParseFlagsConfigFilePrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags")
+	require.NoError(t, os.WriteFile(path, []byte("--provider=google\n--source=ingress\n--source=node\n--interval=5m\n--no-dry-run\n"), 0o600))
+
+	cfg := NewConfig()
+	require.NoError(t, cfg.ParseFlags([]string{"--provider=aws", "--source=service", "--source=pod", "--dry-run", "--config-file=" + path}))
+	assert.Equal(t, "aws", cfg.Provider)
+	assert.Equal(t, []string{"service", "pod"}, cfg.Sources)
+	assert.True(t, cfg.DryRun)
+	assert.Equal(t, 5*time.Minute, cfg.Interval)
+
+	cfg = NewConfig()
+	require.NoError(t, cfg.ParseFlags([]string{"--provider=aws", "--config-file=" + path}))
+	assert.Equal(t, []string{"ingress", "node"}, cfg.Sources)
+	assert.False(t, cfg.DryRun)
+
+	t.Setenv("EXTERNAL_DNS_CONFIG_FILE", path)
+	cfg = NewConfig()
+	require.NoError(t, cfg.ParseFlags([]string{"--provider=aws"}))
+	assert.Equal(t, "aws", cfg.Provider)
+	assert.Equal(t, []string{"ingress", "node"}, cfg.Sources)
+	assert.Equal(t, path, cfg.ConfigFile)
+
+	require.NoError(t, os.WriteFile(path, []byte("--unknown-flag\n"), 0o600))
+	assert.ErrorContains(t, NewConfig().ParseFlags([]string{"--provider=aws", "--source=service", "--config-file=" + path}), "failed to parse the config file")
+}
+
 func TestPasswordsNotLogged(t *testing.T) {
 	cfg := Config{
 		DynPassword:          "dyn-pass",
 		InfobloxWapiPassword: "infoblox-pass",
 		PDNSAPIKey:           "pdns-api-key",
 		RFC2136TSIGSecret:    "tsig-secret",
+		TXTDecryptAESKeys:    []string{"txt-decrypt-key"},
 	}
 
 	s := cfg.String()
@@ -563,4 +974,6 @@ func TestPasswordsNotLogged(t *testing.T) {
 	assert.False(t, strings.Contains(s, "infoblox-pass"))
 	assert.False(t, strings.Contains(s, "pdns-api-key"))
 	assert.False(t, strings.Contains(s, "tsig-secret"))
+	assert.False(t, strings.Contains(s, "txt-decrypt-key"))
+	assert.Equal(t, []string{"txt-decrypt-key"}, cfg.TXTDecryptAESKeys)
 }
@@ -0,0 +1,29 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 contains the v1alpha1 version of the DNSChangeRequest,
+// DNSZone, DNSPolicy and DNSRewrite APIs.
+//
+// A DNSChangeRequest holds the changes planned by ExternalDNS when changes
+// have to be approved before they are applied. A DNSZone describes a hosted
+// zone which ExternalDNS creates at the DNS provider. A DNSPolicy entitles the
+// resources of some namespaces to records, and a DNSRewrite rewrites the DNS
+// names of records. The v1alpha1 DNSEndpoint types are defined in the endpoint
+// package.
+//
+// +kubebuilder:object:generate=true
+// +groupName=externaldns.k8s.io
+package v1alpha1
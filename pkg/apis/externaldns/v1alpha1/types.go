@@ -0,0 +1,283 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// SchemeGroupVersion is the group version of the v1alpha1 DNSChangeRequest, DNSZone, DNSPolicy and DNSRewrite APIs.
+var SchemeGroupVersion = schema.GroupVersion{Group: "externaldns.k8s.io", Version: "v1alpha1"}
+
+// DNSChangeRequestResource is the resource of DNSChangeRequests.
+var DNSChangeRequestResource = SchemeGroupVersion.WithResource("dnschangerequests")
+
+// DNSZoneResource is the resource of DNSZones.
+var DNSZoneResource = SchemeGroupVersion.WithResource("dnszones")
+
+// DNSPolicyResource is the resource of DNSPolicies.
+var DNSPolicyResource = SchemeGroupVersion.WithResource("dnspolicies")
+
+// DNSRewriteResource is the resource of DNSRewrites.
+var DNSRewriteResource = SchemeGroupVersion.WithResource("dnsrewrites")
+
+var (
+	// SchemeBuilder registers the v1alpha1 DNSChangeRequest, DNSZone, DNSPolicy and DNSRewrite types.
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the v1alpha1 DNSChangeRequest, DNSZone, DNSPolicy and DNSRewrite types to a scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion, &DNSChangeRequest{}, &DNSChangeRequestList{}, &DNSZone{}, &DNSZoneList{}, &DNSPolicy{}, &DNSPolicyList{}, &DNSRewrite{}, &DNSRewriteList{})
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+// Condition types of DNSChangeRequests.
+const (
+	// ConditionApproved is set by the reviewer, to True to approve the changes or to False to reject them
+	ConditionApproved = "Approved"
+	// ConditionApplied is set by ExternalDNS once it applied the approved changes, to False when applying them failed
+	ConditionApplied = "Applied"
+)
+
+// DNSChangeRequestSpec defines the changes waiting for approval
+type DNSChangeRequestSpec struct {
+	// OwnerID is the owner ID of the ExternalDNS instance which planned the changes
+	OwnerID string `json:"ownerID"`
+	// Records that need to be created
+	// +optional
+	Create []*endpoint.Endpoint `json:"create,omitempty"`
+	// Records that need to be updated (current data)
+	// +optional
+	UpdateOld []*endpoint.Endpoint `json:"updateOld,omitempty"`
+	// Records that need to be updated (desired data)
+	// +optional
+	UpdateNew []*endpoint.Endpoint `json:"updateNew,omitempty"`
+	// Records that need to be deleted
+	// +optional
+	Delete []*endpoint.Endpoint `json:"delete,omitempty"`
+}
+
+// DNSChangeRequestStatus defines the review state of DNSChangeRequest
+type DNSChangeRequestStatus struct {
+	// Conditions are the Approved condition set by the reviewer and the Applied condition set by ExternalDNS
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// DNSChangeRequest describes changes planned by external-dns which are only applied once approved.
+// +kubebuilder:resource:path=dnschangerequests,shortName=dnscr
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Owner",type=string,JSONPath=`.spec.ownerID`
+// +kubebuilder:printcolumn:name="Approved",type=string,JSONPath=`.status.conditions[?(@.type=="Approved")].status`
+// +kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type DNSChangeRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSChangeRequestSpec   `json:"spec,omitempty"`
+	Status DNSChangeRequestStatus `json:"status,omitempty"`
+}
+
+// DNSChangeRequestList is a list of DNSChangeRequest objects
+// +kubebuilder:object:root=true
+type DNSChangeRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSChangeRequest `json:"items"`
+}
+
+// Condition types of DNSZones.
+const (
+	// ConditionReady is set by ExternalDNS to True once the hosted zone exists and is configured,
+	// or to False with the error preventing it
+	ConditionReady = "Ready"
+)
+
+// Visibilities of DNSZones.
+const (
+	// VisibilityPublic zones are resolvable from the internet
+	VisibilityPublic = "Public"
+	// VisibilityPrivate zones are only resolvable from the network given in the spec
+	VisibilityPrivate = "Private"
+)
+
+// DNSZoneSpec defines the hosted zone to create at the DNS provider
+type DNSZoneSpec struct {
+	// DomainName is the domain name of the zone, e.g. team-a.example.org
+	DomainName string `json:"domainName"`
+	// Visibility is Public, the default, or Private. It cannot be changed once the zone was created.
+	// +kubebuilder:validation:Enum=Public;Private
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+	// Network is the network a private zone is visible from, in the format of the provider,
+	// e.g. <region>:<VPC ID> for AWS
+	// +optional
+	Network string `json:"network,omitempty"`
+	// Tags are set on the zone, in addition to the tags it already has
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+	// Comment describes the zone when it is created
+	// +optional
+	Comment string `json:"comment,omitempty"`
+}
+
+// DNSZoneStatus defines the delegation information of the hosted zone
+type DNSZoneStatus struct {
+	// ZoneID is the ID of the hosted zone at the provider
+	// +optional
+	ZoneID string `json:"zoneID,omitempty"`
+	// NameServers are the name servers the zone has to be delegated to by NS records in the parent zone
+	// +optional
+	NameServers []string `json:"nameServers,omitempty"`
+	// DSRecords are the DS records to add to the parent zone when the zone is signed with DNSSEC
+	// +optional
+	DSRecords []string `json:"dsRecords,omitempty"`
+	// ObservedGeneration is the generation of the spec the status was set for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions are the Ready condition set by ExternalDNS
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// DNSZone describes a hosted zone which ExternalDNS creates and configures at the DNS provider.
+// +kubebuilder:resource:path=dnszones,shortName=dnsz
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Domain",type=string,JSONPath=`.spec.domainName`
+// +kubebuilder:printcolumn:name="Zone",type=string,JSONPath=`.status.zoneID`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type DNSZone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSZoneSpec   `json:"spec,omitempty"`
+	Status DNSZoneStatus `json:"status,omitempty"`
+}
+
+// DNSZoneList is a list of DNSZone objects
+// +kubebuilder:object:root=true
+type DNSZoneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSZone `json:"items"`
+}
+
+// DNSPolicySpec defines the records the resources of some namespaces are entitled to
+type DNSPolicySpec struct {
+	// Namespaces are the names of the namespaces the policy applies to
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceSelector selects the namespaces the policy applies to by their labels, in addition to Namespaces
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Domains are the domains the records may be named after, along with their subdomains.
+	// A policy without domains allows no records.
+	// +optional
+	Domains []string `json:"domains,omitempty"`
+	// RecordTypes are the types the records may have, any type if empty
+	// +optional
+	RecordTypes []string `json:"recordTypes,omitempty"`
+	// MinTTL is the minimum TTL of the records in seconds, no minimum if 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinTTL int64 `json:"minTTL,omitempty"`
+	// MaxTTL is the maximum TTL of the records in seconds, no maximum if 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxTTL int64 `json:"maxTTL,omitempty"`
+}
+
+// DNSPolicy entitles the resources of some namespaces to records of some domains, record types and TTLs.
+// The records of resources in namespaces selected by DNSPolicies are only managed when one of them allows them.
+// +kubebuilder:resource:path=dnspolicies,scope=Cluster,shortName=dnsp
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Domains",type=string,JSONPath=`.spec.domains`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type DNSPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DNSPolicySpec `json:"spec,omitempty"`
+}
+
+// DNSPolicyList is a list of DNSPolicy objects
+// +kubebuilder:object:root=true
+type DNSPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSPolicy `json:"items"`
+}
+
+// DNSRewriteSpec defines how DNS names are rewritten, either with a regular expression or by swapping their suffix
+type DNSRewriteSpec struct {
+	// Namespaces restricts the rewrite to the records of resources in these namespaces, all records if empty
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Match is a regular expression matching the whole DNS names to rewrite, which may contain capture groups
+	// +optional
+	Match string `json:"match,omitempty"`
+	// Replacement is the new DNS name of the names matching Match, which may reference capture groups as $1 or ${name}
+	// +optional
+	Replacement string `json:"replacement,omitempty"`
+	// FromSuffix is the domain whose subdomains are rewritten to subdomains of ToSuffix, when Match is empty
+	// +optional
+	FromSuffix string `json:"fromSuffix,omitempty"`
+	// ToSuffix is the domain replacing FromSuffix
+	// +optional
+	ToSuffix string `json:"toSuffix,omitempty"`
+	// KeepOriginal keeps the records of the original DNS names, in addition to the records of the rewritten names
+	// +optional
+	KeepOriginal bool `json:"keepOriginal,omitempty"`
+}
+
+// DNSRewrite rewrites the DNS names of the records of resources, e.g. to expose generated names under vanity names.
+// The DNS name of a record is rewritten by the first DNSRewrite matching it, in the order of their names.
+// +kubebuilder:resource:path=dnsrewrites,scope=Cluster,shortName=dnsrw
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Match",type=string,JSONPath=`.spec.match`
+// +kubebuilder:printcolumn:name="From",type=string,JSONPath=`.spec.fromSuffix`
+// +kubebuilder:printcolumn:name="To",type=string,JSONPath=`.spec.toSuffix`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type DNSRewrite struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DNSRewriteSpec `json:"spec,omitempty"`
+}
+
+// DNSRewriteList is a list of DNSRewrite objects
+// +kubebuilder:object:root=true
+type DNSRewriteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSRewrite `json:"items"`
+}
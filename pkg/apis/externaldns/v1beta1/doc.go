@@ -0,0 +1,26 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 contains the v1beta1 version of the DNSEndpoint API.
+//
+// It has the same fields as v1alpha1, whose types are defined in the endpoint
+// package, so that both versions are served by the same CRD without a
+// conversion webhook. Unlike v1alpha1, v1beta1 validates DNS names, record
+// types, TTLs and targets when a DNSEndpoint is applied, and defaults the TTL.
+//
+// +kubebuilder:object:generate=true
+// +groupName=externaldns.k8s.io
+package v1beta1
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultTTL is the TTL of endpoints which don't specify one.
+const DefaultTTL = 300
+
+// SchemeGroupVersion is the group version of the v1beta1 DNSEndpoint API.
+var SchemeGroupVersion = schema.GroupVersion{Group: "externaldns.k8s.io", Version: "v1beta1"}
+
+var (
+	// SchemeBuilder registers the v1beta1 types.
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the v1beta1 types to a scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion, &DNSEndpoint{}, &DNSEndpointList{})
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+// Endpoint is a high-level way of a connection between a service and an IP
+// +kubebuilder:validation:XValidation:rule="(has(self.targets) && size(self.targets) > 0) || (has(self.targetsFrom) && size(self.targetsFrom) > 0)",message="targets or targetsFrom must be set"
+// +kubebuilder:validation:XValidation:rule="self.recordType != 'A' || !has(self.targets) || self.targets.all(t, t.matches('^[0-9]{1,3}(\\\\.[0-9]{1,3}){3}$'))",message="targets of A records must be IPv4 addresses"
+// +kubebuilder:validation:XValidation:rule="self.recordType != 'AAAA' || !has(self.targets) || self.targets.all(t, t.contains(':') && t.matches('^[0-9a-fA-F:.]+$'))",message="targets of AAAA records must be IPv6 addresses"
+// +kubebuilder:validation:XValidation:rule="self.recordType != 'CNAME' || !has(self.targets) || size(self.targets) == 1",message="CNAME records must have exactly one target"
+type Endpoint struct {
+	// The hostname of the DNS record
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^(\*\.)?([a-zA-Z0-9_]([-a-zA-Z0-9_]{0,61}[a-zA-Z0-9_])?\.)*[a-zA-Z0-9_]([-a-zA-Z0-9_]{0,61}[a-zA-Z0-9_])?\.?$`
+	DNSName string `json:"dnsName"`
+	// The targets the DNS record points to
+	// +optional
+	Targets []string `json:"targets,omitempty"`
+	// TargetsFrom references Kubernetes objects whose load balancer addresses are added to the targets
+	// +optional
+	TargetsFrom []TargetReference `json:"targetsFrom,omitempty"`
+	// RecordType type of record, e.g. CNAME, A, SRV, TXT etc
+	// +kubebuilder:validation:Enum=A;AAAA;CNAME;TXT;SRV;NS;PTR;MX;SSHFP
+	RecordType string `json:"recordType"`
+	// Identifier to distinguish multiple records with the same name and type (e.g. Route53 records with routing policies other than 'simple')
+	// +optional
+	SetIdentifier string `json:"setIdentifier,omitempty"`
+	// TTL for the record in seconds
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	// +kubebuilder:default=300
+	// +optional
+	RecordTTL int64 `json:"recordTTL,omitempty"`
+	// Labels stores labels defined for the Endpoint
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// ProviderSpecific stores provider specific config
+	// +optional
+	ProviderSpecific []ProviderSpecificProperty `json:"providerSpecific,omitempty"`
+}
+
+// TargetReference references a Kubernetes object whose addresses are used as targets
+type TargetReference struct {
+	// Kind of the object
+	// +kubebuilder:validation:Enum=Service;Gateway
+	Kind string `json:"kind"`
+	// Namespace of the object, defaults to the namespace of the DNSEndpoint
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the object
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ProviderSpecificProperty holds the name and value of a configuration which is specific to individual DNS providers
+type ProviderSpecificProperty struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// DNSEndpointSpec defines the desired state of DNSEndpoint
+type DNSEndpointSpec struct {
+	// +optional
+	Endpoints []*Endpoint `json:"endpoints,omitempty"`
+}
+
+// DNSEndpointStatus defines the observed state of DNSEndpoint
+type DNSEndpointStatus struct {
+	// The generation observed by the external-dns controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe whether the records of the endpoints are synced by the external-dns controller.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The last time records of the endpoints were applied by the external-dns controller.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// The sync state of the record of every endpoint.
+	// +optional
+	Records []DNSEndpointRecordStatus `json:"records,omitempty"`
+}
+
+// DNSEndpointRecordStatus defines the sync state of the record of an endpoint of a DNSEndpoint.
+type DNSEndpointRecordStatus struct {
+	// The hostname of the DNS record.
+	DNSName string `json:"dnsName"`
+	// The type of the DNS record.
+	RecordType string `json:"recordType,omitempty"`
+	// Identifier to distinguish multiple records with the same name and type.
+	// +optional
+	SetIdentifier string `json:"setIdentifier,omitempty"`
+	// Whether the record is in the DNS provider as desired.
+	Synced bool `json:"synced"`
+	// Why the record is not synced.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// DNSEndpoint describes DNS records which external-dns creates for the records of the crd source.
+// +kubebuilder:resource:path=dnsendpoints
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+type DNSEndpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSEndpointSpec   `json:"spec,omitempty"`
+	Status DNSEndpointStatus `json:"status,omitempty"`
+}
+
+// DNSEndpointList is a list of DNSEndpoint objects
+// +kubebuilder:object:root=true
+type DNSEndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSEndpoint `json:"items"`
+}
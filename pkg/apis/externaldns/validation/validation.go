@@ -19,10 +19,15 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
+	"sigs.k8s.io/external-dns/pkg/changewindow"
+	"sigs.k8s.io/external-dns/source"
 )
 
 // ValidateConfig performs validation on the Config object
@@ -37,6 +42,91 @@ func ValidateConfig(cfg *externaldns.Config) error {
 	if cfg.Provider == "" {
 		return errors.New("no provider specified")
 	}
+	sourceIntervals, err := externaldns.ParseSourceIntervals(cfg.SourceIntervals)
+	if err != nil {
+		return err
+	}
+	for name := range sourceIntervals {
+		if !slices.Contains(cfg.Sources, name) {
+			return fmt.Errorf("source interval given for source %q which is not enabled", name)
+		}
+	}
+	if cfg.SourceMaxStaleness < 0 {
+		return errors.New("--source-max-staleness must not be negative")
+	}
+
+	if len(cfg.DefaultTTLs) > 0 {
+		if _, err := externaldns.ParseDefaultTTLs(cfg.DefaultTTLs); err != nil {
+			return err
+		}
+		if cfg.Registry == "aws-sd" {
+			return errors.New("--default-ttl cannot be used with the aws-sd registry")
+		}
+	}
+
+	if _, err := changewindow.ParseWindows(cfg.ChangeWindows); err != nil {
+		return err
+	}
+	if cfg.PlanApproval && cfg.PlanApprovalNamespace == "" {
+		return errors.New("--plan-approval requires --plan-approval-namespace")
+	}
+	if cfg.PlanApprovalExpiry < 0 {
+		return errors.New("--plan-approval-expiry must not be negative")
+	}
+	if cfg.GitExportRepository != "" && cfg.DryRun {
+		return errors.New("--git-export-repository cannot be used with --dry-run, which does not change the records")
+	}
+	if cfg.NotifyMinChanges < 0 {
+		return errors.New("--notify-min-changes must not be negative")
+	}
+	if len(cfg.VerifyResolvers) > 0 {
+		if cfg.VerifyTimeout <= 0 {
+			return errors.New("--verify-timeout must be positive")
+		}
+		if cfg.VerifyRetries < 0 {
+			return errors.New("--verify-retries must not be negative")
+		}
+		if cfg.VerifyInterval < 0 {
+			return errors.New("--verify-interval must not be negative")
+		}
+	}
+
+	if (cfg.MetricsTLSCertFile == "") != (cfg.MetricsTLSKeyFile == "") {
+		return errors.New("--metrics-tls-cert-file and --metrics-tls-key-file must be given together")
+	}
+	if cfg.MetricsTLSClientCAFile != "" && cfg.MetricsTLSCertFile == "" {
+		return errors.New("--metrics-tls-client-ca-file requires --metrics-tls-cert-file")
+	}
+
+	if cfg.PreflightCredentialsFile != "" && !cfg.Preflight {
+		return errors.New("--preflight-credentials-file requires --preflight")
+	}
+	if cfg.SyncEndpoint && cfg.MetricsBearerTokenFile == "" && cfg.MetricsTLSClientCAFile == "" {
+		return errors.New("--sync-endpoint requires --metrics-bearer-token-file or --metrics-tls-client-ca-file")
+	}
+	if cfg.ReadinessMaxSyncIntervals < 0 {
+		return errors.New("--readiness-max-sync-intervals must not be negative")
+	}
+
+	if cfg.AWSZoneChangeRate < 0 {
+		return errors.New("--aws-zone-change-rate must not be negative")
+	}
+
+	if cfg.ValidateDryRun && !cfg.DryRun {
+		return errors.New("--validate-dry-run requires --dry-run")
+	}
+
+	if cfg.TailscaleTailnet != "" {
+		if cfg.TailscaleAPIKey == "" {
+			return errors.New("--tailscale-api-key is required with --tailscale-tailnet")
+		}
+		if len(cfg.TailscaleNameservers) == 0 {
+			return errors.New("--tailscale-nameserver is required with --tailscale-tailnet")
+		}
+		if len(cfg.DomainFilter) == 0 {
+			return errors.New("--domain-filter is required with --tailscale-tailnet")
+		}
+	}
 
 	// Azure provider specific validations
 	if cfg.Provider == "azure" {
@@ -112,9 +202,128 @@ func ValidateConfig(cfg *externaldns.Config) error {
 		return errors.New("txt-prefix and txt-suffix are mutual exclusive")
 	}
 
-	_, err := labels.Parse(cfg.LabelFilter)
+	if cfg.SecondaryRegistry != "" {
+		if cfg.SecondaryRegistry == cfg.Registry {
+			return errors.New("--secondary-registry must differ from --registry")
+		}
+		if cfg.Registry != "txt" && cfg.Registry != "dynamodb" {
+			return errors.New("--secondary-registry can only be used with the txt or dynamodb registry")
+		}
+	}
+
+	if slices.Contains(cfg.AuditSinks, "file") && cfg.AuditFile == "" {
+		return errors.New("--audit-file is required by the file audit sink")
+	}
+	if slices.Contains(cfg.AuditSinks, "webhook") && cfg.AuditWebhookURL == "" {
+		return errors.New("--audit-webhook-url is required by the webhook audit sink")
+	}
+
+	if len(cfg.NAT64Networks) > 0 {
+		if cfg.NAT64SuppressA {
+			return errors.New("--nat64-networks and --nat64-suppress-a are mutually exclusive")
+		}
+		if _, err := source.ParseNAT64Prefixes(cfg.NAT64Networks); err != nil {
+			return err
+		}
+	}
+
+	if _, err := source.ParseMemberClusters(cfg.MemberClusters); err != nil {
+		return err
+	}
+
+	if cfg.TraefikService != "" {
+		if namespace, name, found := strings.Cut(cfg.TraefikService, "/"); !found || namespace == "" || name == "" {
+			return fmt.Errorf("invalid --traefik-service %q, expected <namespace>/<name>", cfg.TraefikService)
+		}
+	}
+
+	if cfg.KongProxyService != "" {
+		if namespace, name, found := strings.Cut(cfg.KongProxyService, "/"); !found || namespace == "" || name == "" {
+			return fmt.Errorf("invalid --kong-proxy-service %q, expected <namespace>/<name>", cfg.KongProxyService)
+		}
+	}
+
+	if cfg.AmbassadorService != "" {
+		if namespace, name, found := strings.Cut(cfg.AmbassadorService, "/"); !found || namespace == "" || name == "" {
+			return fmt.Errorf("invalid --ambassador-service %q, expected <namespace>/<name>", cfg.AmbassadorService)
+		}
+	}
+
+	if slices.Contains(cfg.Sources, "file") && (cfg.FileSourcePath == "") == (cfg.FileSourceConfigMap == "") {
+		return errors.New("the file source requires either --file-source-path or --file-source-configmap")
+	}
+	if cfg.FileSourceConfigMap != "" {
+		if namespace, name, found := strings.Cut(cfg.FileSourceConfigMap, "/"); !found || namespace == "" || name == "" {
+			return fmt.Errorf("invalid --file-source-configmap %q, expected <namespace>/<name>", cfg.FileSourceConfigMap)
+		}
+	}
+
+	if slices.Contains(cfg.Sources, "http") {
+		if u, err := url.Parse(cfg.HTTPSourceURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("the http source requires an HTTP(S) --http-source-url, got %q", cfg.HTTPSourceURL)
+		}
+	}
+
+	if !cfg.ConnectorSourceTLS && (cfg.ConnectorSourceTLSCA != "" || cfg.ConnectorSourceTLSCert != "" || cfg.ConnectorSourceTLSKey != "") {
+		return errors.New("--connector-source-tls-ca, --connector-source-tls-cert and --connector-source-tls-key require --connector-source-tls")
+	}
+	if (cfg.ConnectorSourceTLSCert == "") != (cfg.ConnectorSourceTLSKey == "") {
+		return errors.New("--connector-source-tls-cert and --connector-source-tls-key must be given together")
+	}
+
+	if cfg.KnativeIngressService != "" {
+		if namespace, name, found := strings.Cut(cfg.KnativeIngressService, "/"); !found || namespace == "" || name == "" {
+			return fmt.Errorf("invalid --knative-ingress-service %q, expected <namespace>/<name>", cfg.KnativeIngressService)
+		}
+	}
+
+	if cfg.NodeSSHFPSecret != "" {
+		if !cfg.NodeSSHFP {
+			return errors.New("--node-sshfp-secret can only be used with --node-sshfp")
+		}
+		if namespace, name, found := strings.Cut(cfg.NodeSSHFPSecret, "/"); !found || namespace == "" || name == "" {
+			return fmt.Errorf("invalid --node-sshfp-secret %q, expected <namespace>/<name>", cfg.NodeSSHFPSecret)
+		}
+	}
+
+	if cfg.RegistryCacheSnapshotFile != "" || cfg.RegistryCacheSnapshotConfigMap != "" {
+		if cfg.RegistryCacheSnapshotFile != "" && cfg.RegistryCacheSnapshotConfigMap != "" {
+			return errors.New("--registry-cache-snapshot-file and --registry-cache-snapshot-configmap are mutually exclusive")
+		}
+		if cfg.RegistryCacheInterval <= 0 {
+			return errors.New("the registry cache snapshot requires --registry-cache-interval")
+		}
+		// the owner IDs derived from namespaces are not part of the cached records
+		if cfg.TXTOwnerIDTemplate != "" {
+			return errors.New("the registry cache snapshot cannot be used with --txt-owner-id-template")
+		}
+		if cfg.RegistryCacheSnapshotConfigMap != "" {
+			if namespace, name, found := strings.Cut(cfg.RegistryCacheSnapshotConfigMap, "/"); !found || namespace == "" || name == "" {
+				return fmt.Errorf("invalid --registry-cache-snapshot-configmap %q, expected <namespace>/<name>", cfg.RegistryCacheSnapshotConfigMap)
+			}
+		}
+	}
+
+	if cfg.TXTOwnerIDTemplate != "" && cfg.Registry != "txt" {
+		return errors.New("--txt-owner-id-template can only be used with the txt registry")
+	}
+
+	if cfg.Policy == "delete-only" && cfg.Registry == "noop" {
+		return errors.New("--policy=delete-only requires a registry tracking ownership")
+	}
+
+	_, err = labels.Parse(cfg.LabelFilter)
 	if err != nil {
 		return errors.New("--label-filter does not specify a valid label selector")
 	}
+	if _, err := labels.Parse(cfg.NodeLabelFilter); err != nil {
+		return errors.New("--node-label-filter does not specify a valid label selector")
+	}
+	if _, err := labels.Parse(cfg.PodLabelSelector); err != nil {
+		return errors.New("--pod-label-selector does not specify a valid label selector")
+	}
+	if _, err := labels.Parse(cfg.CRDSourceLabelFilter); err != nil {
+		return errors.New("--crd-source-label-filter does not specify a valid label selector")
+	}
 	return nil
 }
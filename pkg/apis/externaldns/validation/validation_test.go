@@ -18,6 +18,7 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	"sigs.k8s.io/external-dns/pkg/apis/externaldns"
 
@@ -279,3 +280,384 @@ func TestValidateGoodRfc2136GssTsigConfig(t *testing.T) {
 		assert.Nil(t, err)
 	}
 }
+
+func TestValidateSecondaryRegistry(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.Registry = "dynamodb"
+	cfg.SecondaryRegistry = "txt"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.SecondaryRegistry = "dynamodb"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.Registry = "noop"
+	cfg.SecondaryRegistry = "txt"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateSourceIntervals(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.Sources = []string{"ingress", "node"}
+	cfg.SourceIntervals = []string{"node=10m"}
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.SourceIntervals = []string{"crd=10m"}
+	assert.Error(t, ValidateConfig(cfg))
+
+	for _, interval := range []string{"node", "=10m", "node=soon", "node=0s"} {
+		cfg.SourceIntervals = []string{interval}
+		assert.Error(t, ValidateConfig(cfg), interval)
+	}
+}
+
+func TestValidateSourceMaxStaleness(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.SourceMaxStaleness = 15 * time.Minute
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.SourceMaxStaleness = -time.Minute
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateChangeWindows(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.ChangeWindows = []string{"0 22 * * 6 8h", "CRON_TZ=Europe/Berlin 0 3 * * 3 1h"}
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.ChangeWindows = []string{"0 22 * * 6"}
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidatePlanApproval(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.PlanApproval = true
+	cfg.PlanApprovalNamespace = "dns-review"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.PlanApprovalNamespace = ""
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg = newValidConfig(t)
+	cfg.PlanApprovalExpiry = -time.Minute
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateGitExport(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.GitExportRepository = "https://git.example.org/dns.git"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.DryRun = true
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateNotifyMinChanges(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.NotifyMinChanges = 10
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.NotifyMinChanges = -1
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateVerify(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.VerifyResolvers = []string{"1.1.1.1"}
+	cfg.VerifyTimeout = time.Second
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.VerifyTimeout = 0
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.VerifyTimeout = time.Second
+	cfg.VerifyRetries = -1
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateDefaultTTLs(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.DefaultTTLs = []string{"A=5m", "txt=1h"}
+	assert.NoError(t, ValidateConfig(cfg))
+
+	for _, ttl := range []string{"A", "=5m", "A=soon", "A=100ms"} {
+		cfg.DefaultTTLs = []string{ttl}
+		assert.Error(t, ValidateConfig(cfg), ttl)
+	}
+
+	cfg.DefaultTTLs = []string{"A=5m"}
+	cfg.Registry = "aws-sd"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateDeleteOnlyPolicy(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.Policy = "delete-only"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.Registry = "noop"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateNAT64(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.NAT64Networks = []string{"64:ff9b::/96"}
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.NAT64SuppressA = true
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.NAT64SuppressA = false
+	cfg.NAT64Networks = []string{"64:ff9b::/64"}
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateMemberClusters(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.MemberClusters = []string{"name=eu,kubeconfig=/etc/kubeconfigs/eu", "name=us,kubeconfig=/etc/kubeconfigs/fleet,context=us"}
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.MemberClusters = []string{"name=eu"}
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.MemberClusters = []string{"name=eu,kubeconfig=/etc/kubeconfigs/eu", "name=eu,kubeconfig=/etc/kubeconfigs/fleet"}
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateTraefikService(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.TraefikService = "traefik/traefik"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.TraefikService = "traefik"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateKongProxyService(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.KongProxyService = "kong/kong-proxy"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.KongProxyService = "kong-proxy"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateKnativeIngressService(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.KnativeIngressService = "kourier-system/kourier"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.KnativeIngressService = "kourier"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateAmbassadorService(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.AmbassadorService = "emissary/emissary-ingress"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.AmbassadorService = "emissary-ingress.emissary"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateFileSource(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.Sources = []string{"file"}
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.FileSourcePath = "/etc/external-dns/endpoints.yaml"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.FileSourceConfigMap = "external-dns/endpoints"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.FileSourcePath = ""
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.FileSourceConfigMap = "endpoints"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateHTTPSource(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.Sources = []string{"http"}
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.HTTPSourceURL = "https://inventory.example.org/endpoints"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.HTTPSourceURL = "inventory.example.org/endpoints"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateConnectorSourceTLS(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.ConnectorSourceTLSCA = "/etc/external-dns/connector/ca.crt"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.ConnectorSourceTLS = true
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.ConnectorSourceTLSCert = "/etc/external-dns/connector/tls.crt"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.ConnectorSourceTLSKey = "/etc/external-dns/connector/tls.key"
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateNodeSSHFPSecret(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.NodeSSHFP = true
+	cfg.NodeSSHFPSecret = "kube-system/ssh-host-keys"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.NodeSSHFPSecret = "ssh-host-keys"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.NodeSSHFP = false
+	cfg.NodeSSHFPSecret = "kube-system/ssh-host-keys"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateNodeLabelFilter(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.NodeLabelFilter = "node-role.kubernetes.io/worker,!node.kubernetes.io/exclude-from-external-load-balancers"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.NodeLabelFilter = "node-role.kubernetes.io/worker in (true"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidatePodLabelSelector(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.PodLabelSelector = "app=worker"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.PodLabelSelector = "app in (worker"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateCRDSourceLabelFilter(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.CRDSourceLabelFilter = "external-dns.io/instance=public"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.CRDSourceLabelFilter = "external-dns.io/instance in (public"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateRegistryCacheSnapshot(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.RegistryCacheSnapshotConfigMap = "external-dns/registry-snapshot"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.RegistryCacheInterval = time.Hour
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.RegistryCacheSnapshotFile = "/var/lib/external-dns/registry.json.gz"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.RegistryCacheSnapshotFile = ""
+	cfg.RegistryCacheSnapshotConfigMap = "registry-snapshot"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.RegistryCacheSnapshotConfigMap = ""
+	cfg.RegistryCacheSnapshotFile = "/var/lib/external-dns/registry.json.gz"
+	cfg.TXTOwnerIDTemplate = "{{ .Namespace }}"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateTXTOwnerIDTemplate(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.Registry = "txt"
+	cfg.TXTOwnerIDTemplate = "{{ .Namespace }}"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.Registry = "dynamodb"
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidateAuditSinks(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.AuditSinks = []string{"file", "webhook", "events"}
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.AuditFile = "/var/log/audit.log"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.AuditWebhookURL = "http://localhost:8080/audit"
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateTailscale(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.TailscaleTailnet = "-"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.TailscaleAPIKey = "tskey-api"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.TailscaleNameservers = []string{"100.100.1.1"}
+	cfg.DomainFilter = nil
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.DomainFilter = []string{"cluster.example.org"}
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateDryRunValidation(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.ValidateDryRun = true
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.DryRun = true
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateMetricsTLS(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.MetricsTLSCertFile = "/etc/metrics/tls.crt"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.MetricsTLSKeyFile = "/etc/metrics/tls.key"
+	cfg.MetricsTLSClientCAFile = "/etc/metrics/ca.crt"
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.MetricsTLSCertFile, cfg.MetricsTLSKeyFile = "", ""
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestValidatePreflightCredentialsFile(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.PreflightCredentialsFile = "/var/run/secrets/provider/credentials"
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.Preflight = true
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateSyncEndpoint(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.SyncEndpoint = true
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.MetricsBearerTokenFile = "/etc/metrics-token/token"
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateReadinessMaxSyncIntervals(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.ReadinessMaxSyncIntervals = -1
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.ReadinessMaxSyncIntervals = 3
+	assert.NoError(t, ValidateConfig(cfg))
+}
+
+func TestValidateAWSZoneChangeRate(t *testing.T) {
+	cfg := newValidConfig(t)
+	cfg.AWSZoneChangeRate = -1
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.AWSZoneChangeRate = 2.5
+	assert.NoError(t, ValidateConfig(cfg))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package approval gates the changes planned by ExternalDNS behind a review,
+// so that they are only applied once a human or an automation approved them.
+package approval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// Gate decides whether planned changes may be applied.
+type Gate interface {
+	// Approved returns true when exactly these changes were approved,
+	// otherwise it requests their approval.
+	Approved(ctx context.Context, changes *plan.Changes) (bool, error)
+	// Applied records the result of applying approved changes.
+	Applied(ctx context.Context, changes *plan.Changes, err error) error
+}
+
+// Hash returns a hash identifying the changes planned by owner, which doesn't depend on the order of the changes.
+func Hash(owner string, changes *plan.Changes) (string, error) {
+	data, err := json.Marshal(struct {
+		Owner     string               `json:"owner"`
+		Create    []*endpoint.Endpoint `json:"create"`
+		UpdateOld []*endpoint.Endpoint `json:"updateOld"`
+		UpdateNew []*endpoint.Endpoint `json:"updateNew"`
+		Delete    []*endpoint.Endpoint `json:"delete"`
+	}{
+		Owner:     owner,
+		Create:    sorted(changes.Create),
+		UpdateOld: sorted(changes.UpdateOld),
+		UpdateNew: sorted(changes.UpdateNew),
+		Delete:    sorted(changes.Delete),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// sorted returns a copy of the endpoints sorted by name, set identifier, type and targets.
+func sorted(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	result := make([]*endpoint.Endpoint, len(endpoints))
+	copy(result, endpoints)
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.DNSName != b.DNSName {
+			return a.DNSName < b.DNSName
+		}
+		if a.SetIdentifier != b.SetIdentifier {
+			return a.SetIdentifier < b.SetIdentifier
+		}
+		if a.RecordType != b.RecordType {
+			return a.RecordType < b.RecordType
+		}
+		return a.Targets.String() < b.Targets.String()
+	})
+	return result
+}
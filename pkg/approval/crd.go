@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns/v1alpha1"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// requestNamePrefix prefixes the names of the DNSChangeRequests created by ExternalDNS.
+const requestNamePrefix = "external-dns-"
+
+// CRDGate requests the approval of changes by creating a DNSChangeRequest holding them. The changes are applied
+// once the Approved condition of the DNSChangeRequest is True, unless the approval expired. A DNSChangeRequest
+// which is still pending when different changes are planned is replaced by a DNSChangeRequest of the new changes.
+type CRDGate struct {
+	client    dynamic.Interface
+	namespace string
+	ownerID   string
+	expiry    time.Duration
+	now       func() time.Time
+}
+
+// NewCRDGate returns a CRDGate creating the DNSChangeRequests of owner in namespace. Approvals older than
+// expiry are ignored, unless expiry is 0.
+func NewCRDGate(client dynamic.Interface, namespace, ownerID string, expiry time.Duration) *CRDGate {
+	return &CRDGate{
+		client:    client,
+		namespace: namespace,
+		ownerID:   ownerID,
+		expiry:    expiry,
+		now:       time.Now,
+	}
+}
+
+// requestName returns the name of the DNSChangeRequest of the changes.
+func (g *CRDGate) requestName(changes *plan.Changes) (string, error) {
+	hash, err := Hash(g.ownerID, changes)
+	if err != nil {
+		return "", err
+	}
+	return requestNamePrefix + hash[:16], nil
+}
+
+// Approved returns true when the DNSChangeRequest of the changes was approved, otherwise it creates it.
+func (g *CRDGate) Approved(ctx context.Context, changes *plan.Changes) (bool, error) {
+	name, err := g.requestName(changes)
+	if err != nil {
+		return false, err
+	}
+	request, err := g.get(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	if request != nil && meta.IsStatusConditionTrue(request.Status.Conditions, v1alpha1.ConditionApplied) {
+		// the same changes are planned again after they were applied, which needs another approval
+		log.Infof("Changes of DNSChangeRequest %s/%s were already applied, requesting their approval again", g.namespace, name)
+		if err := g.resource().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("deleting DNSChangeRequest %s/%s: %w", g.namespace, name, err)
+		}
+		request = nil
+	}
+	if request == nil {
+		if err := g.create(ctx, name, changes); err != nil {
+			return false, err
+		}
+		log.Infof("Waiting for the approval of DNSChangeRequest %s/%s", g.namespace, name)
+		return false, g.deleteSuperseded(ctx, name)
+	}
+
+	approved := meta.FindStatusCondition(request.Status.Conditions, v1alpha1.ConditionApproved)
+	switch {
+	case approved == nil:
+		log.Infof("Waiting for the approval of DNSChangeRequest %s/%s", g.namespace, name)
+		return false, nil
+	case approved.Status != metav1.ConditionTrue:
+		log.Infof("DNSChangeRequest %s/%s was not approved: %s", g.namespace, name, approved.Message)
+		return false, nil
+	case g.expiry > 0 && g.now().After(approved.LastTransitionTime.Add(g.expiry)):
+		log.Infof("Approval of DNSChangeRequest %s/%s expired at %s", g.namespace, name, approved.LastTransitionTime.Add(g.expiry).Format(time.RFC3339))
+		return false, nil
+	}
+	log.Infof("Applying the changes of approved DNSChangeRequest %s/%s", g.namespace, name)
+	return true, nil
+}
+
+// Applied sets the Applied condition of the DNSChangeRequest of the changes.
+func (g *CRDGate) Applied(ctx context.Context, changes *plan.Changes, applyErr error) error {
+	name, err := g.requestName(changes)
+	if err != nil {
+		return err
+	}
+	request, err := g.get(ctx, name)
+	if err != nil || request == nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionApplied,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: request.Generation,
+		Reason:             "Applied",
+		Message:            "The changes were applied",
+	}
+	if applyErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Failed"
+		condition.Message = applyErr.Error()
+	}
+	meta.SetStatusCondition(&request.Status.Conditions, condition)
+
+	obj, err := toUnstructured(request)
+	if err != nil {
+		return err
+	}
+	if _, err := g.resource().UpdateStatus(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("updating status of DNSChangeRequest %s/%s: %w", g.namespace, name, err)
+	}
+	return nil
+}
+
+func (g *CRDGate) resource() dynamic.ResourceInterface {
+	return g.client.Resource(v1alpha1.DNSChangeRequestResource).Namespace(g.namespace)
+}
+
+// get returns the DNSChangeRequest with the given name, or nil when there is none.
+func (g *CRDGate) get(ctx context.Context, name string) (*v1alpha1.DNSChangeRequest, error) {
+	obj, err := g.resource().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting DNSChangeRequest %s/%s: %w", g.namespace, name, err)
+	}
+	request := &v1alpha1.DNSChangeRequest{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), request); err != nil {
+		return nil, fmt.Errorf("decoding DNSChangeRequest %s/%s: %w", g.namespace, name, err)
+	}
+	return request, nil
+}
+
+func (g *CRDGate) create(ctx context.Context, name string, changes *plan.Changes) error {
+	request := &v1alpha1.DNSChangeRequest{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "DNSChangeRequest",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: g.namespace,
+		},
+		Spec: v1alpha1.DNSChangeRequestSpec{
+			OwnerID:   g.ownerID,
+			Create:    changes.Create,
+			UpdateOld: changes.UpdateOld,
+			UpdateNew: changes.UpdateNew,
+			Delete:    changes.Delete,
+		},
+	}
+	obj, err := toUnstructured(request)
+	if err != nil {
+		return err
+	}
+	if _, err := g.resource().Create(ctx, obj, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("creating DNSChangeRequest %s/%s: %w", g.namespace, name, err)
+	}
+	return nil
+}
+
+// deleteSuperseded deletes the pending DNSChangeRequests of the owner other than the current one,
+// whose changes aren't planned anymore.
+func (g *CRDGate) deleteSuperseded(ctx context.Context, current string) error {
+	list, err := g.resource().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing DNSChangeRequests in %s: %w", g.namespace, err)
+	}
+	for _, item := range list.Items {
+		request := &v1alpha1.DNSChangeRequest{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.UnstructuredContent(), request); err != nil {
+			log.Warnf("Failed to decode DNSChangeRequest %s/%s: %v", item.GetNamespace(), item.GetName(), err)
+			continue
+		}
+		if request.Name == current || request.Spec.OwnerID != g.ownerID ||
+			meta.FindStatusCondition(request.Status.Conditions, v1alpha1.ConditionApplied) != nil {
+			continue
+		}
+		log.Infof("Deleting DNSChangeRequest %s/%s superseded by %s", g.namespace, request.Name, current)
+		if err := g.resource().Delete(ctx, request.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("deleting DNSChangeRequest %s/%s: %w", g.namespace, request.Name, err)
+		}
+	}
+	return nil
+}
+
+func toUnstructured(request *v1alpha1.DNSChangeRequest) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(request)
+	if err != nil {
+		return nil, fmt.Errorf("encoding DNSChangeRequest %s/%s: %w", request.Namespace, request.Name, err)
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns/v1alpha1"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+func newTestGate(t *testing.T, expiry time.Duration) *CRDGate {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	client := fakeDynamic.NewSimpleDynamicClient(scheme)
+	return NewCRDGate(client, "external-dns", "owner", expiry)
+}
+
+func testChanges(target string) *plan.Changes {
+	return &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("new.example.org", endpoint.RecordTypeA, target)},
+		Delete: []*endpoint.Endpoint{endpoint.NewEndpoint("old.example.org", endpoint.RecordTypeA, "4.4.4.4")},
+	}
+}
+
+func listRequests(t *testing.T, g *CRDGate) []v1alpha1.DNSChangeRequest {
+	list, err := g.resource().List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	requests := make([]v1alpha1.DNSChangeRequest, 0, len(list.Items))
+	for _, item := range list.Items {
+		request := v1alpha1.DNSChangeRequest{}
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(item.UnstructuredContent(), &request))
+		requests = append(requests, request)
+	}
+	return requests
+}
+
+// setApproved sets the Approved condition of the only DNSChangeRequest as a reviewer would.
+func setApproved(t *testing.T, g *CRDGate, status metav1.ConditionStatus, at time.Time) {
+	requests := listRequests(t, g)
+	require.Len(t, requests, 1)
+	request := &requests[0]
+	// SetStatusCondition keeps the transition time when the status doesn't change
+	meta.RemoveStatusCondition(&request.Status.Conditions, v1alpha1.ConditionApproved)
+	meta.SetStatusCondition(&request.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionApproved,
+		Status:             status,
+		Reason:             "Reviewed",
+		LastTransitionTime: metav1.NewTime(at),
+	})
+	obj, err := toUnstructured(request)
+	require.NoError(t, err)
+	_, err = g.resource().UpdateStatus(context.Background(), obj, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func TestHash(t *testing.T) {
+	a, err := Hash("owner", &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+	}})
+	require.NoError(t, err)
+	b, err := Hash("owner", &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	other, err := Hash("other", &plan.Changes{Create: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+	}})
+	require.NoError(t, err)
+	assert.NotEqual(t, a, other)
+}
+
+func TestCRDGateApproval(t *testing.T) {
+	ctx := context.Background()
+	g := newTestGate(t, time.Hour)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+	changes := testChanges("1.1.1.1")
+
+	approved, err := g.Approved(ctx, changes)
+	require.NoError(t, err)
+	assert.False(t, approved)
+	requests := listRequests(t, g)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "owner", requests[0].Spec.OwnerID)
+	require.Len(t, requests[0].Spec.Create, 1)
+	assert.Equal(t, "new.example.org", requests[0].Spec.Create[0].DNSName)
+	require.Len(t, requests[0].Spec.Delete, 1)
+
+	// still pending
+	approved, err = g.Approved(ctx, changes)
+	require.NoError(t, err)
+	assert.False(t, approved)
+
+	setApproved(t, g, metav1.ConditionFalse, now)
+	approved, err = g.Approved(ctx, changes)
+	require.NoError(t, err)
+	assert.False(t, approved)
+
+	setApproved(t, g, metav1.ConditionTrue, now.Add(-2*time.Hour))
+	approved, err = g.Approved(ctx, changes)
+	require.NoError(t, err)
+	assert.False(t, approved, "the approval expired")
+
+	setApproved(t, g, metav1.ConditionTrue, now.Add(-time.Minute))
+	approved, err = g.Approved(ctx, changes)
+	require.NoError(t, err)
+	assert.True(t, approved)
+
+	require.NoError(t, g.Applied(ctx, changes, errors.New("throttled")))
+	applied := meta.FindStatusCondition(listRequests(t, g)[0].Status.Conditions, v1alpha1.ConditionApplied)
+	require.NotNil(t, applied)
+	assert.Equal(t, metav1.ConditionFalse, applied.Status)
+	assert.Equal(t, "throttled", applied.Message)
+
+	// failed changes are retried with the same approval
+	approved, err = g.Approved(ctx, changes)
+	require.NoError(t, err)
+	assert.True(t, approved)
+
+	require.NoError(t, g.Applied(ctx, changes, nil))
+	assert.True(t, meta.IsStatusConditionTrue(listRequests(t, g)[0].Status.Conditions, v1alpha1.ConditionApplied))
+
+	// the same changes planned again need another approval
+	approved, err = g.Approved(ctx, changes)
+	require.NoError(t, err)
+	assert.False(t, approved)
+	requests = listRequests(t, g)
+	require.Len(t, requests, 1)
+	assert.Empty(t, requests[0].Status.Conditions)
+}
+
+func TestCRDGateWithoutExpiry(t *testing.T) {
+	ctx := context.Background()
+	g := newTestGate(t, 0)
+	changes := testChanges("1.1.1.1")
+
+	_, err := g.Approved(ctx, changes)
+	require.NoError(t, err)
+	setApproved(t, g, metav1.ConditionTrue, time.Now().Add(-24*365*time.Hour))
+	approved, err := g.Approved(ctx, changes)
+	require.NoError(t, err)
+	assert.True(t, approved)
+}
+
+func TestCRDGateSupersedesPendingRequests(t *testing.T) {
+	ctx := context.Background()
+	g := newTestGate(t, time.Hour)
+	other := NewCRDGate(g.client, "external-dns", "other", time.Hour)
+
+	_, err := other.Approved(ctx, testChanges("1.1.1.1"))
+	require.NoError(t, err)
+	_, err = g.Approved(ctx, testChanges("1.1.1.1"))
+	require.NoError(t, err)
+	require.NoError(t, g.Applied(ctx, testChanges("1.1.1.1"), nil))
+	_, err = g.Approved(ctx, testChanges("2.2.2.2"))
+	require.NoError(t, err)
+	_, err = g.Approved(ctx, testChanges("3.3.3.3"))
+	require.NoError(t, err)
+
+	current, err := g.requestName(testChanges("3.3.3.3"))
+	require.NoError(t, err)
+	applied, err := g.requestName(testChanges("1.1.1.1"))
+	require.NoError(t, err)
+	otherName, err := other.requestName(testChanges("1.1.1.1"))
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, request := range listRequests(t, g) {
+		names = append(names, request.Name)
+	}
+	assert.ElementsMatch(t, []string{current, applied, otherName}, names)
+}
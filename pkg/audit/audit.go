@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the changes applied to DNS records, so that every
+// change made by ExternalDNS can be traced.
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// Actions of audit entries.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Entry describes a change applied to a DNS record.
+type Entry struct {
+	Time          time.Time        `json:"time"`
+	Action        string           `json:"action"`
+	Owner         string           `json:"owner,omitempty"`
+	DNSName       string           `json:"dnsName"`
+	RecordType    string           `json:"recordType"`
+	SetIdentifier string           `json:"setIdentifier,omitempty"`
+	OldTargets    endpoint.Targets `json:"oldTargets,omitempty"`
+	NewTargets    endpoint.Targets `json:"newTargets,omitempty"`
+	OldTTL        endpoint.TTL     `json:"oldTTL,omitempty"`
+	NewTTL        endpoint.TTL     `json:"newTTL,omitempty"`
+	// Resource is the Kubernetes resource the record originates from, e.g. ingress/default/foo
+	Resource string `json:"resource,omitempty"`
+}
+
+// Sink stores audit entries.
+type Sink interface {
+	Write(ctx context.Context, entries []Entry) error
+}
+
+// NewEntries returns the audit entries of the given changes, applied by owner at time t.
+func NewEntries(changes *plan.Changes, owner string, t time.Time) []Entry {
+	entries := make([]Entry, 0, len(changes.Create)+len(changes.UpdateNew)+len(changes.Delete))
+	for _, e := range changes.Create {
+		entry := newEntry(ActionCreate, e, owner, t)
+		entry.NewTargets = e.Targets
+		entry.NewTTL = e.RecordTTL
+		entries = append(entries, entry)
+	}
+
+	old := make(map[endpoint.EndpointKey]*endpoint.Endpoint, len(changes.UpdateOld))
+	for _, e := range changes.UpdateOld {
+		old[e.Key()] = e
+	}
+	for _, e := range changes.UpdateNew {
+		entry := newEntry(ActionUpdate, e, owner, t)
+		if o, ok := old[e.Key()]; ok {
+			entry.OldTargets = o.Targets
+			entry.OldTTL = o.RecordTTL
+		}
+		entry.NewTargets = e.Targets
+		entry.NewTTL = e.RecordTTL
+		entries = append(entries, entry)
+	}
+
+	for _, e := range changes.Delete {
+		entry := newEntry(ActionDelete, e, owner, t)
+		entry.OldTargets = e.Targets
+		entry.OldTTL = e.RecordTTL
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func newEntry(action string, e *endpoint.Endpoint, owner string, t time.Time) Entry {
+	return Entry{
+		Time:          t.UTC(),
+		Action:        action,
+		Owner:         owner,
+		DNSName:       e.DNSName,
+		RecordType:    e.RecordType,
+		SetIdentifier: e.SetIdentifier,
+		Resource:      e.Labels[endpoint.ResourceLabelKey],
+	}
+}
+
+// MultiSink writes audit entries to several sinks.
+type MultiSink []Sink
+
+// Write writes the entries to all sinks, even if some of them fail.
+func (m MultiSink) Write(ctx context.Context, entries []Entry) error {
+	var errs []error
+	for _, s := range m {
+		if err := s.Write(ctx, entries); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+func testChanges() *plan.Changes {
+	created := endpoint.NewEndpoint("new.example.org", endpoint.RecordTypeA, "1.1.1.1")
+	created.Labels[endpoint.ResourceLabelKey] = "ingress/default/new"
+	return &plan.Changes{
+		Create:    []*endpoint.Endpoint{created},
+		UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("changed.example.org", endpoint.RecordTypeCNAME, 300, "old.example.org")},
+		UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("changed.example.org", endpoint.RecordTypeCNAME, 60, "new.example.org")},
+		Delete:    []*endpoint.Endpoint{endpoint.NewEndpoint("gone.example.org", endpoint.RecordTypeAAAA, "::1")},
+	}
+}
+
+func TestNewEntries(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := NewEntries(testChanges(), "owner", now)
+
+	assert.Equal(t, []Entry{
+		{
+			Time:       now,
+			Action:     ActionCreate,
+			Owner:      "owner",
+			DNSName:    "new.example.org",
+			RecordType: endpoint.RecordTypeA,
+			NewTargets: endpoint.Targets{"1.1.1.1"},
+			Resource:   "ingress/default/new",
+		},
+		{
+			Time:       now,
+			Action:     ActionUpdate,
+			Owner:      "owner",
+			DNSName:    "changed.example.org",
+			RecordType: endpoint.RecordTypeCNAME,
+			OldTargets: endpoint.Targets{"old.example.org"},
+			NewTargets: endpoint.Targets{"new.example.org"},
+			OldTTL:     300,
+			NewTTL:     60,
+		},
+		{
+			Time:       now,
+			Action:     ActionDelete,
+			Owner:      "owner",
+			DNSName:    "gone.example.org",
+			RecordType: endpoint.RecordTypeAAAA,
+			OldTargets: endpoint.Targets{"::1"},
+		},
+	}, entries)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+
+	entries := NewEntries(testChanges(), "owner", time.Now())
+	require.NoError(t, sink.Write(context.Background(), entries[:1]))
+	require.NoError(t, sink.Write(context.Background(), entries[1:]))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var actions []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{ActionCreate, ActionUpdate, ActionDelete}, actions)
+
+	_, err = NewFileSink(filepath.Join(t.TempDir(), "missing", "audit.log"))
+	assert.Error(t, err)
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received []Entry
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL)
+	require.NoError(t, sink.Write(context.Background(), NewEntries(testChanges(), "owner", time.Now())))
+	assert.Len(t, received, 3)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, sink.Write(context.Background(), NewEntries(testChanges(), "owner", time.Now())))
+}
+
+func TestEventSink(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := NewEventSink(client)
+
+	require.NoError(t, sink.Write(context.Background(), NewEntries(testChanges(), "owner", time.Now())))
+
+	events, err := client.CoreV1().Events("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1, "only entries with a resource should be recorded")
+	event := events.Items[0]
+	assert.Equal(t, "Ingress", event.InvolvedObject.Kind)
+	assert.Equal(t, "new", event.InvolvedObject.Name)
+	assert.Equal(t, "DNSRecordCreated", event.Reason)
+}
+
+func TestEventSinkRecordDrift(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := NewEventSink(client)
+
+	applied := endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4")
+	applied.Labels[endpoint.ResourceLabelKey] = "service/prod/foo"
+	observed := endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "6.6.6.6")
+	require.NoError(t, sink.RecordDrift(context.Background(), applied, observed))
+	require.NoError(t, sink.RecordDrift(context.Background(), endpoint.NewEndpoint("bar.example.org", endpoint.RecordTypeA, "1.2.3.4"), observed))
+
+	events, err := client.CoreV1().Events("prod").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1, "only records with a resource should be recorded")
+	event := events.Items[0]
+	assert.Equal(t, "Service", event.InvolvedObject.Kind)
+	assert.Equal(t, "foo", event.InvolvedObject.Name)
+	assert.Equal(t, corev1.EventTypeWarning, event.Type)
+	assert.Equal(t, "DNSRecordDrifted", event.Reason)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+const eventComponent = "external-dns"
+
+// kinds maps the kinds used in resource labels to Kubernetes kinds.
+var kinds = map[string]string{
+	"ingress": "Ingress",
+	"service": "Service",
+	"node":    "Node",
+	"pod":     "Pod",
+	"crd":     "DNSEndpoint",
+}
+
+// EventSink records audit entries as Kubernetes Events of the resources the
+// records originate from. Entries without resource are skipped.
+type EventSink struct {
+	client kubernetes.Interface
+}
+
+// NewEventSink returns an EventSink using the given client.
+func NewEventSink(client kubernetes.Interface) *EventSink {
+	return &EventSink{client: client}
+}
+
+// Write creates one Event per entry.
+func (s *EventSink) Write(ctx context.Context, entries []Entry) error {
+	var errs []error
+	for _, entry := range entries {
+		event := NewResourceEvent(entry.Resource, corev1.EventTypeNormal, eventReason(entry.Action), eventMessage(entry), entry.Time)
+		if event == nil {
+			log.Debugf("Not recording an event for %s %s without resource", entry.DNSName, entry.RecordType)
+			continue
+		}
+		if _, err := s.client.CoreV1().Events(event.Namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("creating event for %s %s: %w", entry.DNSName, entry.RecordType, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RecordDrift creates a Warning Event of the resource of a record found changed outside ExternalDNS.
+// Records without resource are skipped.
+func (s *EventSink) RecordDrift(ctx context.Context, applied, observed *endpoint.Endpoint) error {
+	message := fmt.Sprintf("%s record %s was changed outside ExternalDNS: applied targets %v, found targets %v",
+		observed.RecordType, observed.DNSName, applied.Targets, observed.Targets)
+	event := NewResourceEvent(applied.Labels[endpoint.ResourceLabelKey], corev1.EventTypeWarning, "DNSRecordDrifted", message, time.Now())
+	if event == nil {
+		log.Debugf("Not recording the drift of %s %s without resource", observed.DNSName, observed.RecordType)
+		return nil
+	}
+	if _, err := s.client.CoreV1().Events(event.Namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("creating event for %s %s: %w", observed.DNSName, observed.RecordType, err)
+	}
+	return nil
+}
+
+// NewResourceEvent returns an Event of the resource, given in the form of the resource label of records,
+// or nil if there is no resource. Events of cluster-scoped resources are created in the default namespace.
+func NewResourceEvent(resource, eventType, reason, message string, t time.Time) *corev1.Event {
+	kind, namespace, name := endpoint.Labels{endpoint.ResourceLabelKey: resource}.Resource()
+	if name == "" {
+		return nil
+	}
+	if k, ok := kinds[kind]; ok {
+		kind = k
+	}
+	eventNamespace := namespace
+	if eventNamespace == "" {
+		eventNamespace = metav1.NamespaceDefault
+	}
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", name, time.Now().UnixNano()),
+			Namespace: eventNamespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: eventComponent},
+		FirstTimestamp: metav1.NewTime(t),
+		LastTimestamp:  metav1.NewTime(t),
+		Count:          1,
+	}
+}
+
+func eventReason(action string) string {
+	switch action {
+	case ActionCreate:
+		return "DNSRecordCreated"
+	case ActionUpdate:
+		return "DNSRecordUpdated"
+	default:
+		return "DNSRecordDeleted"
+	}
+}
+
+func eventMessage(entry Entry) string {
+	switch entry.Action {
+	case ActionCreate:
+		return fmt.Sprintf("Created %s record %s with targets %v", entry.RecordType, entry.DNSName, entry.NewTargets)
+	case ActionUpdate:
+		return fmt.Sprintf("Updated %s record %s from targets %v to %v", entry.RecordType, entry.DNSName, entry.OldTargets, entry.NewTargets)
+	default:
+		return fmt.Sprintf("Deleted %s record %s with targets %v", entry.RecordType, entry.DNSName, entry.OldTargets)
+	}
+}
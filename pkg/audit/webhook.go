@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/external-dns/pkg/webhookclient"
+)
+
+// WebhookSink posts audit entries to an HTTP endpoint as a JSON array.
+type WebhookSink struct {
+	client *webhookclient.Client
+}
+
+// NewWebhookSink returns a WebhookSink posting to url.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{client: webhookclient.New(url)}
+}
+
+// Write posts the entries in a single request.
+func (s *WebhookSink) Write(ctx context.Context, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := s.client.PostJSON(ctx, entries); err != nil {
+		return fmt.Errorf("posting audit entries: %w", err)
+	}
+	return nil
+}
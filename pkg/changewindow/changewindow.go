@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package changewindow implements recurring time windows, e.g. the maintenance windows
+// during which DNS records may be changed.
+package changewindow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLookahead bounds the search for the next opening of a window.
+const maxLookahead = 366 * 24 * time.Hour
+
+// field is the set of values a field of a cron expression matches.
+type field uint64
+
+func (f field) matches(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+// fieldRange is the range of the values of a field of a cron expression.
+type fieldRange struct {
+	name     string
+	min, max int
+}
+
+var fieldRanges = []fieldRange{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// Window is a recurring time window, opening when a cron expression matches and staying open for a duration,
+// e.g. "0 22 * * 6 8h" opens every Saturday at 22:00 until Sunday 06:00. The expression is evaluated in UTC,
+// unless it's prefixed with a time zone such as "CRON_TZ=Europe/Berlin".
+type Window struct {
+	spec     string
+	location *time.Location
+	minute   field
+	hour     field
+	dom      field
+	month    field
+	dow      field
+	// anyDay is true when either the day of month or the day of week isn't restricted,
+	// in which case both have to match, as for cron
+	anyDay   bool
+	duration time.Duration
+}
+
+// Parse parses a window given as "[CRON_TZ=<zone>] <minute> <hour> <day of month> <month> <day of week> <duration>".
+// The fields of the cron expression are "*", values, ranges and steps, e.g. "1-5", "*/15" or "0,30".
+func Parse(spec string) (*Window, error) {
+	fields := strings.Fields(spec)
+	w := &Window{spec: spec, location: time.UTC}
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "CRON_TZ=") {
+		location, err := time.LoadLocation(strings.TrimPrefix(fields[0], "CRON_TZ="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone in change window %q: %w", spec, err)
+		}
+		w.location = location
+		fields = fields[1:]
+	}
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("invalid change window %q, expected a cron expression and a duration", spec)
+	}
+
+	parsed := make([]field, len(fieldRanges))
+	for i, r := range fieldRanges {
+		f, err := parseField(fields[i], r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid change window %q: %w", spec, err)
+		}
+		parsed[i] = f
+	}
+	w.minute, w.hour, w.dom, w.month, w.dow = parsed[0], parsed[1], parsed[2], parsed[3], parsed[4]
+	// Sunday is both 0 and 7
+	if w.dow.matches(7) {
+		w.dow |= 1
+	}
+	w.anyDay = fields[2] == "*" || fields[4] == "*"
+
+	duration, err := time.ParseDuration(fields[5])
+	if err != nil || duration < time.Minute {
+		return nil, fmt.Errorf("invalid duration in change window %q, expected at least 1m", spec)
+	}
+	w.duration = duration
+	return w, nil
+}
+
+// parseField parses a comma separated list of values, ranges and steps of a field of a cron expression.
+func parseField(value string, r fieldRange) (field, error) {
+	var f field
+	for _, part := range strings.Split(value, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rng = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in %s %q", r.name, value)
+			}
+		}
+
+		low, high := r.min, r.max
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if low, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid %s %q", r.name, value)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("invalid %s %q", r.name, value)
+				}
+			}
+		}
+		if low < r.min || high > r.max || low > high {
+			return 0, fmt.Errorf("%s %q out of range %d-%d", r.name, value, r.min, r.max)
+		}
+		for v := low; v <= high; v += step {
+			f |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+// String returns the window as it was parsed.
+func (w *Window) String() string {
+	return w.spec
+}
+
+// opensAt returns true when the window opens at the minute t.
+func (w *Window) opensAt(t time.Time) bool {
+	if !w.minute.matches(t.Minute()) || !w.hour.matches(t.Hour()) || !w.month.matches(int(t.Month())) {
+		return false
+	}
+	dom, dow := w.dom.matches(t.Day()), w.dow.matches(int(t.Weekday()))
+	if w.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Contains returns true when the window is open at t.
+func (w *Window) Contains(t time.Time) bool {
+	t = t.In(w.location)
+	for start := t.Truncate(time.Minute); t.Sub(start) < w.duration; start = start.Add(-time.Minute) {
+		if w.opensAt(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// Windows are the windows during which something is allowed, at any time when there are none.
+type Windows []*Window
+
+// ParseWindows parses the windows with Parse.
+func ParseWindows(specs []string) (Windows, error) {
+	windows := make(Windows, 0, len(specs))
+	for _, spec := range specs {
+		w, err := Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// Open returns true when one of the windows is open at t, or when there are no windows.
+func (ws Windows) Open(t time.Time) bool {
+	if len(ws) == 0 {
+		return true
+	}
+	for _, w := range ws {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns when one of the windows opens next after t, or the zero time when none opens within a year.
+func (ws Windows) Next(t time.Time) time.Time {
+	if len(ws) == 0 {
+		return t
+	}
+	end := t.Add(maxLookahead)
+	for next := t.Truncate(time.Minute).Add(time.Minute); next.Before(end); next = next.Add(time.Minute) {
+		for _, w := range ws {
+			if w.opensAt(next.In(w.location)) {
+				return next
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changewindow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"0 22 * * 6",
+		"0 22 * * 6 8h extra",
+		"60 22 * * 6 8h",
+		"0 24 * * 6 8h",
+		"0 22 0 * * 8h",
+		"0 22 * 13 * 8h",
+		"0 22 * * 8 8h",
+		"0 22-20 * * * 8h",
+		"*/0 22 * * * 8h",
+		"a 22 * * * 8h",
+		"0 22 * * 6 30s",
+		"0 22 * * 6 forever",
+		"CRON_TZ=Nowhere/Nothing 0 22 * * 6 8h",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := Parse(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	// Saturday 22:00 until Sunday 06:00
+	w, err := Parse("0 22 * * 6 8h")
+	require.NoError(t, err)
+	assert.Equal(t, "0 22 * * 6 8h", w.String())
+
+	for _, tt := range []struct {
+		time     string
+		expected bool
+	}{
+		{time: "2024-06-01T21:59:59Z", expected: false},
+		{time: "2024-06-01T22:00:00Z", expected: true},
+		{time: "2024-06-02T05:59:59Z", expected: true},
+		{time: "2024-06-02T06:00:00Z", expected: false},
+		{time: "2024-06-03T22:30:00Z", expected: false},
+		// in another time zone
+		{time: "2024-06-02T00:30:00+02:00", expected: true},
+	} {
+		t.Run(tt.time, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.time)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, w.Contains(at))
+		})
+	}
+}
+
+func TestWindowFields(t *testing.T) {
+	for _, tt := range []struct {
+		spec     string
+		time     string
+		expected bool
+	}{
+		// weekdays, every 15 minutes for 5 minutes
+		{spec: "*/15 9-17 * * 1-5 5m", time: "2024-06-03T09:47:00Z", expected: true},
+		{spec: "*/15 9-17 * * 1-5 5m", time: "2024-06-03T09:50:00Z", expected: false},
+		{spec: "*/15 9-17 * * 1-5 5m", time: "2024-06-01T09:47:00Z", expected: false},
+		// Sunday is 0 and 7
+		{spec: "0 0 * * 7 1h", time: "2024-06-02T00:30:00Z", expected: true},
+		// lists
+		{spec: "0,30 12 * * * 10m", time: "2024-06-05T12:35:00Z", expected: true},
+		{spec: "0,30 12 * * * 10m", time: "2024-06-05T12:15:00Z", expected: false},
+		// either the day of month or the day of week when both are restricted
+		{spec: "0 0 1 * 1 24h", time: "2024-06-01T12:00:00Z", expected: true},
+		{spec: "0 0 1 * 1 24h", time: "2024-06-03T12:00:00Z", expected: true},
+		{spec: "0 0 1 * 1 24h", time: "2024-06-04T12:00:00Z", expected: false},
+		// months
+		{spec: "0 0 * 12 * 24h", time: "2024-12-24T12:00:00Z", expected: true},
+		{spec: "0 0 * 12 * 24h", time: "2024-11-24T12:00:00Z", expected: false},
+	} {
+		t.Run(tt.spec+" "+tt.time, func(t *testing.T) {
+			w, err := Parse(tt.spec)
+			require.NoError(t, err)
+			at, err := time.Parse(time.RFC3339, tt.time)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, w.Contains(at))
+		})
+	}
+}
+
+func TestWindowTimeZone(t *testing.T) {
+	w, err := Parse("CRON_TZ=UTC 0 22 * * 6 8h")
+	require.NoError(t, err)
+	assert.True(t, w.Contains(time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)))
+}
+
+func TestWindows(t *testing.T) {
+	none, err := ParseWindows(nil)
+	require.NoError(t, err)
+	now := time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+	assert.True(t, none.Open(now))
+
+	_, err = ParseWindows([]string{"0 22 * * 6 8h", "invalid"})
+	assert.Error(t, err)
+
+	windows, err := ParseWindows([]string{"0 22 * * 6 8h", "0 3 * * 3 1h"})
+	require.NoError(t, err)
+	assert.False(t, windows.Open(now))
+	assert.True(t, windows.Open(time.Date(2024, 6, 5, 3, 30, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2024, 6, 8, 22, 0, 0, 0, time.UTC), windows.Next(now))
+	assert.Equal(t, time.Date(2024, 6, 12, 3, 0, 0, 0, time.UTC), windows.Next(time.Date(2024, 6, 9, 3, 0, 0, 0, time.UTC)))
+
+	// February 30th never happens
+	never, err := ParseWindows([]string{"0 0 30 2 * 1h"})
+	require.NoError(t, err)
+	assert.True(t, never.Next(now).IsZero())
+}
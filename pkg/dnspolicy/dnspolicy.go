@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dnspolicy restricts the records of the resources of tenants to the
+// domains, record types and TTLs they are entitled to by DNSPolicies.
+package dnspolicy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns/v1alpha1"
+	"sigs.k8s.io/external-dns/pkg/audit"
+)
+
+// violationReason is the reason of the Events of records which are not allowed.
+const violationReason = "DNSPolicyViolation"
+
+// Enforcer decides which desired endpoints may be managed.
+type Enforcer interface {
+	// Enforce returns the endpoints which are allowed, dropping the others.
+	Enforce(ctx context.Context, endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error)
+}
+
+// policy is a DNSPolicy with its namespace selector, which is nil if it has none.
+type policy struct {
+	name     string
+	spec     v1alpha1.DNSPolicySpec
+	selector labels.Selector
+}
+
+// CRDEnforcer enforces the DNSPolicies of the cluster. The endpoints of resources in namespaces selected by
+// DNSPolicies are only allowed when one of these policies allows them. Endpoints of cluster-scoped resources,
+// of resources in namespaces no policy selects, and without resource, are always allowed.
+type CRDEnforcer struct {
+	client     dynamic.Interface
+	kubeClient kubernetes.Interface
+	// reported are the violations Events were recorded for, which are not recorded again while they last
+	reported map[string]bool
+	now      func() time.Time
+}
+
+// NewCRDEnforcer returns a CRDEnforcer reading the DNSPolicies with client, and the namespaces and Events with
+// kubeClient.
+func NewCRDEnforcer(client dynamic.Interface, kubeClient kubernetes.Interface) *CRDEnforcer {
+	return &CRDEnforcer{
+		client:     client,
+		kubeClient: kubeClient,
+		reported:   map[string]bool{},
+		now:        time.Now,
+	}
+}
+
+// Enforce returns the endpoints allowed by the DNSPolicies, and records a Warning Event on the resources of the
+// endpoints which are not.
+func (e *CRDEnforcer) Enforce(ctx context.Context, endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	policies, err := e.policies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		e.reported = map[string]bool{}
+		return endpoints, nil
+	}
+
+	namespaceLabels := map[string]labels.Set{}
+	allowed := make([]*endpoint.Endpoint, 0, len(endpoints))
+	reported := map[string]bool{}
+	for _, ep := range endpoints {
+		_, namespace, _ := ep.Labels.Resource()
+		if namespace == "" {
+			allowed = append(allowed, ep)
+			continue
+		}
+		applicable, err := e.applicable(ctx, policies, namespace, namespaceLabels)
+		if err != nil {
+			return nil, err
+		}
+		if len(applicable) == 0 || allowedByAny(applicable, ep) {
+			allowed = append(allowed, ep)
+			continue
+		}
+
+		names := make([]string, 0, len(applicable))
+		for _, p := range applicable {
+			names = append(names, p.name)
+		}
+		message := fmt.Sprintf("%s record %s is not allowed by the DNSPolicies %s of namespace %s", ep.RecordType, ep.DNSName, strings.Join(names, ", "), namespace)
+		log.Warnf("Skipping endpoint %s: %s", ep, message)
+		resource := ep.Labels[endpoint.ResourceLabelKey]
+		key := fmt.Sprintf("%s %s %s %s", resource, ep.DNSName, ep.RecordType, ep.SetIdentifier)
+		reported[key] = true
+		if !e.reported[key] {
+			e.recordViolation(ctx, resource, message)
+		}
+	}
+	e.reported = reported
+	return allowed, nil
+}
+
+// policies returns the DNSPolicies, sorted by name. Policies with an invalid namespace selector are ignored.
+func (e *CRDEnforcer) policies(ctx context.Context) ([]policy, error) {
+	list, err := e.client.Resource(v1alpha1.DNSPolicyResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing DNSPolicies: %w", err)
+	}
+	policies := make([]policy, 0, len(list.Items))
+	for _, item := range list.Items {
+		p := &v1alpha1.DNSPolicy{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.UnstructuredContent(), p); err != nil {
+			log.Warnf("Failed to decode DNSPolicy %s: %v", item.GetName(), err)
+			continue
+		}
+		var selector labels.Selector
+		if p.Spec.NamespaceSelector != nil {
+			if selector, err = metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector); err != nil {
+				log.Warnf("Ignoring DNSPolicy %s with an invalid namespace selector: %v", p.Name, err)
+				continue
+			}
+		}
+		policies = append(policies, policy{name: p.Name, spec: p.Spec, selector: selector})
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].name < policies[j].name })
+	return policies, nil
+}
+
+// applicable returns the policies selecting the namespace. The labels of the namespaces are looked up once
+// and kept in namespaceLabels.
+func (e *CRDEnforcer) applicable(ctx context.Context, policies []policy, namespace string, namespaceLabels map[string]labels.Set) ([]policy, error) {
+	var applicable []policy
+	for _, p := range policies {
+		selected, err := e.selects(ctx, p, namespace, namespaceLabels)
+		if err != nil {
+			return nil, err
+		}
+		if selected {
+			applicable = append(applicable, p)
+		}
+	}
+	return applicable, nil
+}
+
+// selects returns true when the policy names the namespace, or selects it by its labels.
+func (e *CRDEnforcer) selects(ctx context.Context, p policy, namespace string, namespaceLabels map[string]labels.Set) (bool, error) {
+	for _, n := range p.spec.Namespaces {
+		if n == namespace {
+			return true, nil
+		}
+	}
+	if p.selector == nil {
+		return false, nil
+	}
+	set, ok := namespaceLabels[namespace]
+	if !ok {
+		ns, err := e.kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return false, fmt.Errorf("getting namespace %s: %w", namespace, err)
+		default:
+			set = ns.Labels
+		}
+		namespaceLabels[namespace] = set
+	}
+	return p.selector.Matches(set), nil
+}
+
+// allowedByAny returns true when one of the policies allows the endpoint.
+func allowedByAny(policies []policy, ep *endpoint.Endpoint) bool {
+	for _, p := range policies {
+		if allows(p.spec, ep) {
+			return true
+		}
+	}
+	return false
+}
+
+// allows returns true when the endpoint has one of the domains, one of the record types, if any, and a TTL
+// within the range of the policy. Endpoints without TTL have the TTL of the provider and are not restricted.
+func allows(spec v1alpha1.DNSPolicySpec, ep *endpoint.Endpoint) bool {
+	if !matchDomain(ep.DNSName, spec.Domains) {
+		return false
+	}
+	if len(spec.RecordTypes) > 0 {
+		found := false
+		for _, t := range spec.RecordTypes {
+			if strings.EqualFold(t, ep.RecordType) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if ep.RecordTTL.IsConfigured() {
+		ttl := int64(ep.RecordTTL)
+		if spec.MinTTL > 0 && ttl < spec.MinTTL || spec.MaxTTL > 0 && ttl > spec.MaxTTL {
+			return false
+		}
+	}
+	return true
+}
+
+// matchDomain returns true when the name is one of the domains or one of their subdomains.
+func matchDomain(name string, domains []string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.Trim(domain, "."))
+		if domain != "" && (name == domain || strings.HasSuffix(name, "."+domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordViolation records a Warning Event on the resource. Failures are only logged, so that they don't
+// prevent the synchronization of the allowed records.
+func (e *CRDEnforcer) recordViolation(ctx context.Context, resource, message string) {
+	event := audit.NewResourceEvent(resource, corev1.EventTypeWarning, violationReason, message, e.now())
+	if event == nil {
+		return
+	}
+	if _, err := e.kubeClient.CoreV1().Events(event.Namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		log.Errorf("Failed to record the DNSPolicy violation of %s: %v", resource, err)
+	}
+}
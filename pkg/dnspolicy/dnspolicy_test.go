@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnspolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns/v1alpha1"
+)
+
+func newDNSPolicy(name string, spec v1alpha1.DNSPolicySpec) *v1alpha1.DNSPolicy {
+	return &v1alpha1.DNSPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "DNSPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       spec,
+	}
+}
+
+func newTestEnforcer(t *testing.T, policies ...*v1alpha1.DNSPolicy) (*CRDEnforcer, *fake.Clientset) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	objects := make([]runtime.Object, 0, len(policies))
+	for _, p := range policies {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(p)
+		require.NoError(t, err)
+		objects = append(objects, &unstructured.Unstructured{Object: content})
+	}
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tenant": "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "system"}},
+	)
+	return NewCRDEnforcer(fakeDynamic.NewSimpleDynamicClient(scheme, objects...), kubeClient), kubeClient
+}
+
+func newEndpoint(dnsName, recordType string, ttl endpoint.TTL, resource string) *endpoint.Endpoint {
+	ep := endpoint.NewEndpointWithTTL(dnsName, recordType, ttl, "1.2.3.4")
+	if resource != "" {
+		ep.Labels[endpoint.ResourceLabelKey] = resource
+	}
+	return ep
+}
+
+func dnsNames(endpoints []*endpoint.Endpoint) []string {
+	names := make([]string, 0, len(endpoints))
+	for _, ep := range endpoints {
+		names = append(names, ep.DNSName)
+	}
+	return names
+}
+
+func TestEnforce(t *testing.T) {
+	enforcer, kubeClient := newTestEnforcer(t,
+		newDNSPolicy("team-a", v1alpha1.DNSPolicySpec{
+			Namespaces:  []string{"team-a"},
+			Domains:     []string{"team-a.example.org"},
+			RecordTypes: []string{"A", "CNAME"},
+			MinTTL:      60,
+			MaxTTL:      3600,
+		}),
+		newDNSPolicy("tenants", v1alpha1.DNSPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+			Domains:           []string{"tenants.example.org."},
+		}),
+	)
+
+	allowed, err := enforcer.Enforce(context.Background(), []*endpoint.Endpoint{
+		newEndpoint("team-a.example.org", endpoint.RecordTypeA, 0, "ingress/team-a/apex"),
+		newEndpoint("www.team-a.example.org", endpoint.RecordTypeA, 300, "ingress/team-a/www"),
+		newEndpoint("txt.team-a.example.org", endpoint.RecordTypeTXT, 0, "ingress/team-a/txt"),
+		newEndpoint("short.team-a.example.org", endpoint.RecordTypeA, 30, "ingress/team-a/short"),
+		newEndpoint("www.team-b.example.org", endpoint.RecordTypeA, 0, "ingress/team-a/other"),
+		newEndpoint("eviltenants.example.org", endpoint.RecordTypeA, 0, "service/team-b/evil"),
+		newEndpoint("api.tenants.example.org", endpoint.RecordTypeAAAA, 0, "service/team-b/api"),
+		newEndpoint("anything.example.org", endpoint.RecordTypeA, 0, "ingress/system/anything"),
+		newEndpoint("node.example.org", endpoint.RecordTypeA, 0, "node/node-1"),
+		newEndpoint("static.example.org", endpoint.RecordTypeA, 0, ""),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"team-a.example.org",
+		"www.team-a.example.org",
+		"api.tenants.example.org",
+		"anything.example.org",
+		"node.example.org",
+		"static.example.org",
+	}, dnsNames(allowed))
+
+	events, err := kubeClient.CoreV1().Events("").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 4)
+	for _, event := range events.Items {
+		assert.Equal(t, corev1.EventTypeWarning, event.Type)
+		assert.Equal(t, violationReason, event.Reason)
+	}
+}
+
+func TestEnforceReportsViolationsOnce(t *testing.T) {
+	enforcer, kubeClient := newTestEnforcer(t,
+		newDNSPolicy("deny-all", v1alpha1.DNSPolicySpec{NamespaceSelector: &metav1.LabelSelector{}}),
+	)
+	endpoints := []*endpoint.Endpoint{newEndpoint("www.example.org", endpoint.RecordTypeA, 0, "ingress/team-a/www")}
+	countEvents := func() int {
+		events, err := kubeClient.CoreV1().Events("team-a").List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		return len(events.Items)
+	}
+
+	for i := 0; i < 2; i++ {
+		allowed, err := enforcer.Enforce(context.Background(), endpoints)
+		require.NoError(t, err)
+		assert.Empty(t, allowed)
+	}
+	assert.Equal(t, 1, countEvents())
+
+	// a violation which ended is reported again when it reoccurs
+	_, err := enforcer.Enforce(context.Background(), nil)
+	require.NoError(t, err)
+	_, err = enforcer.Enforce(context.Background(), endpoints)
+	require.NoError(t, err)
+	assert.Equal(t, 2, countEvents())
+}
+
+func TestEnforceWithoutPolicies(t *testing.T) {
+	enforcer, _ := newTestEnforcer(t)
+	endpoints := []*endpoint.Endpoint{newEndpoint("www.example.org", endpoint.RecordTypeA, 0, "ingress/team-a/www")}
+	allowed, err := enforcer.Enforce(context.Background(), endpoints)
+	require.NoError(t, err)
+	assert.Equal(t, endpoints, allowed)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dnssec exports the DNSSEC key material of the zones of the DNS provider,
+// so that the delegations to them from their parent zones can be maintained.
+package dnssec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// zone is a signed zone as exported in YAML.
+type zone struct {
+	Zone    string   `yaml:"zone"`
+	ID      string   `yaml:"id"`
+	DS      []string `yaml:"ds"`
+	CDNSKEY []string `yaml:"cdnskey,omitempty"`
+}
+
+// Marshal returns the delegation signers in YAML.
+func Marshal(signers []provider.DelegationSigner) ([]byte, error) {
+	zones := make([]zone, 0, len(signers))
+	for _, signer := range signers {
+		zones = append(zones, zone{
+			Zone:    signer.Zone,
+			ID:      signer.ID,
+			DS:      signer.DSRecords,
+			CDNSKEY: signer.DNSKEYRecords,
+		})
+	}
+	return yaml.Marshal(zones)
+}
+
+// FileExporter writes the delegation signers of the signed zones of a provider to a YAML file.
+type FileExporter struct {
+	provider provider.DelegationSignerProvider
+	path     string
+	// last is the content last written to the file
+	last []byte
+}
+
+// NewFileExporter returns a FileExporter writing the delegation signers of the provider to the file at path.
+func NewFileExporter(p provider.DelegationSignerProvider, path string) *FileExporter {
+	return &FileExporter{provider: p, path: path}
+}
+
+// Export writes the delegation signers of the provider to the file, unless they are unchanged since the last export.
+// The file is replaced atomically, so that readers never see a partially written file.
+func (e *FileExporter) Export(ctx context.Context) error {
+	signers, err := e.provider.DelegationSigners(ctx)
+	if err != nil {
+		return err
+	}
+	content, err := Marshal(signers)
+	if err != nil {
+		return err
+	}
+	if e.last != nil && bytes.Equal(content, e.last) {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(e.path), "."+filepath.Base(e.path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to export the DNSSEC delegation signers: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to export the DNSSEC delegation signers: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to export the DNSSEC delegation signers: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to export the DNSSEC delegation signers: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), e.path); err != nil {
+		return fmt.Errorf("failed to export the DNSSEC delegation signers: %w", err)
+	}
+	log.Infof("Exported the DNSSEC delegation signers of %d zones to %s", len(signers), e.path)
+	e.last = content
+	return nil
+}
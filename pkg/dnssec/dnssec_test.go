@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnssec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/provider"
+)
+
+type fakeProvider struct {
+	signers []provider.DelegationSigner
+	err     error
+}
+
+func (p *fakeProvider) DelegationSigners(ctx context.Context) ([]provider.DelegationSigner, error) {
+	return p.signers, p.err
+}
+
+func TestFileExporter(t *testing.T) {
+	p := &fakeProvider{signers: []provider.DelegationSigner{{
+		Zone:          "team-a.example.org",
+		ID:            "/hostedzone/Z1",
+		DSRecords:     []string{"12345 13 2 ABCDEF"},
+		DNSKEYRecords: []string{"257 3 13 AAAA"},
+	}}}
+	path := filepath.Join(t.TempDir(), "ds.yaml")
+	e := NewFileExporter(p, path)
+
+	require.NoError(t, e.Export(context.Background()))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `- zone: team-a.example.org
+  id: /hostedzone/Z1
+  ds:
+  - 12345 13 2 ABCDEF
+  cdnskey:
+  - 257 3 13 AAAA
+`, string(content))
+
+	// unchanged delegation signers are not written again
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	require.NoError(t, e.Export(context.Background()))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(old))
+
+	p.signers = nil
+	require.NoError(t, e.Export(context.Background()))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(content))
+
+	// the file is kept when the provider fails
+	p.err = errors.New("failed")
+	assert.Error(t, e.Export(context.Background()))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(content))
+}
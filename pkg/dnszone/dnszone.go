@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnszone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns/v1alpha1"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// Reasons of the Ready condition of DNSZones.
+const (
+	reasonReady          = "Ready"
+	reasonFailed         = "Failed"
+	reasonNotOwned       = "NotOwned"
+	reasonDomainFiltered = "DomainFiltered"
+	reasonInvalid        = "Invalid"
+)
+
+// Reconciler creates and configures the hosted zones declared by DNSZones, and reports the information needed to
+// delegate to them in their status. Hosted zones are never deleted, deleting a DNSZone leaves its zone at the
+// provider.
+type Reconciler struct {
+	client       dynamic.Interface
+	manager      provider.ZoneManager
+	namespace    string
+	domainFilter endpoint.DomainFilter
+}
+
+// NewReconciler returns a Reconciler for the DNSZones in namespace, or in all namespaces if it is empty.
+// Only the DNSZones whose domain matches domainFilter are created.
+func NewReconciler(client dynamic.Interface, manager provider.ZoneManager, namespace string, domainFilter endpoint.DomainFilter) *Reconciler {
+	return &Reconciler{
+		client:       client,
+		manager:      manager,
+		namespace:    namespace,
+		domainFilter: domainFilter,
+	}
+}
+
+// Reconcile ensures the hosted zones of all DNSZones and updates their status. It returns an error when the
+// DNSZones could not be listed, or when some of their zones could not be ensured.
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	list, err := r.client.Resource(v1alpha1.DNSZoneResource).Namespace(r.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing DNSZones: %w", err)
+	}
+	var failed []string
+	for _, item := range list.Items {
+		zone := &v1alpha1.DNSZone{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.UnstructuredContent(), zone); err != nil {
+			log.Warnf("Failed to decode DNSZone %s/%s: %v", item.GetNamespace(), item.GetName(), err)
+			continue
+		}
+		if err := r.reconcile(ctx, zone); err != nil {
+			log.Errorf("Failed to ensure the hosted zone of DNSZone %s/%s: %v", zone.Namespace, zone.Name, err)
+			failed = append(failed, zone.Namespace+"/"+zone.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to ensure the hosted zones of DNSZones %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// reconcile ensures the hosted zone of a DNSZone and updates its status.
+func (r *Reconciler) reconcile(ctx context.Context, zone *v1alpha1.DNSZone) error {
+	status := zone.Status.DeepCopy()
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: zone.Generation,
+	}
+
+	var ensureErr error
+	spec, err := zoneSpec(zone)
+	switch {
+	case err != nil:
+		condition.Reason = reasonInvalid
+		condition.Message = err.Error()
+	case !r.domainFilter.Match(spec.DomainName):
+		condition.Reason = reasonDomainFiltered
+		condition.Message = fmt.Sprintf("The domain %s is not managed by this ExternalDNS instance", spec.DomainName)
+	default:
+		var zoneStatus provider.ZoneStatus
+		zoneStatus, ensureErr = r.manager.EnsureZone(ctx, spec)
+		switch {
+		case errors.Is(ensureErr, provider.ErrZoneNotOwned):
+			condition.Reason = reasonNotOwned
+			condition.Message = ensureErr.Error()
+		case ensureErr != nil:
+			condition.Reason = reasonFailed
+			condition.Message = ensureErr.Error()
+		default:
+			status.ZoneID = zoneStatus.ID
+			status.NameServers = zoneStatus.NameServers
+			status.DSRecords = zoneStatus.DSRecords
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = reasonReady
+			condition.Message = "The hosted zone exists and is configured"
+		}
+	}
+	status.ObservedGeneration = zone.Generation
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	if statusEqual(&zone.Status, status) {
+		return ensureErr
+	}
+	zone.Status = *status
+	if err := r.updateStatus(ctx, zone); err != nil {
+		return errors.Join(ensureErr, err)
+	}
+	if condition.Status == metav1.ConditionTrue {
+		log.Infof("Hosted zone %s of DNSZone %s/%s is ready", status.ZoneID, zone.Namespace, zone.Name)
+	}
+	return ensureErr
+}
+
+// zoneSpec returns the spec of the hosted zone of a DNSZone.
+func zoneSpec(zone *v1alpha1.DNSZone) (provider.ZoneSpec, error) {
+	spec := provider.ZoneSpec{
+		DomainName: strings.ToLower(strings.TrimSuffix(zone.Spec.DomainName, ".")),
+		Network:    zone.Spec.Network,
+		Tags:       zone.Spec.Tags,
+		Comment:    zone.Spec.Comment,
+		Reference:  string(zone.UID),
+	}
+	if spec.DomainName == "" {
+		return spec, errors.New("the domain name must not be empty")
+	}
+	switch zone.Spec.Visibility {
+	case "", v1alpha1.VisibilityPublic:
+	case v1alpha1.VisibilityPrivate:
+		spec.Private = true
+		if spec.Network == "" {
+			return spec, errors.New("the network of private zones must not be empty")
+		}
+	default:
+		return spec, fmt.Errorf("unknown visibility %q", zone.Spec.Visibility)
+	}
+	return spec, nil
+}
+
+// statusEqual returns true when two statuses are equal, ignoring the transition times of their conditions.
+func statusEqual(a, b *v1alpha1.DNSZoneStatus) bool {
+	if a.ZoneID != b.ZoneID || a.ObservedGeneration != b.ObservedGeneration ||
+		!slices.Equal(a.NameServers, b.NameServers) || !slices.Equal(a.DSRecords, b.DSRecords) ||
+		len(a.Conditions) != len(b.Conditions) {
+		return false
+	}
+	for _, c := range a.Conditions {
+		other := meta.FindStatusCondition(b.Conditions, c.Type)
+		if other == nil || other.Status != c.Status || other.Reason != c.Reason || other.Message != c.Message ||
+			other.ObservedGeneration != c.ObservedGeneration {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, zone *v1alpha1.DNSZone) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(zone)
+	if err != nil {
+		return fmt.Errorf("encoding DNSZone %s/%s: %w", zone.Namespace, zone.Name, err)
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	if _, err := r.client.Resource(v1alpha1.DNSZoneResource).Namespace(zone.Namespace).UpdateStatus(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("updating status of DNSZone %s/%s: %w", zone.Namespace, zone.Name, err)
+	}
+	return nil
+}
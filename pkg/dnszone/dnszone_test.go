@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnszone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns/v1alpha1"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+type fakeZoneManager struct {
+	specs []provider.ZoneSpec
+	err   error
+}
+
+func (m *fakeZoneManager) EnsureZone(ctx context.Context, spec provider.ZoneSpec) (provider.ZoneStatus, error) {
+	m.specs = append(m.specs, spec)
+	if m.err != nil {
+		return provider.ZoneStatus{}, m.err
+	}
+	return provider.ZoneStatus{
+		ID:          "/hostedzone/" + spec.DomainName,
+		NameServers: []string{"ns1.example.net", "ns2.example.net"},
+		DSRecords:   []string{"12345 13 2 ABCDEF"},
+	}, nil
+}
+
+func newTestReconciler(t *testing.T, manager provider.ZoneManager, zones ...*v1alpha1.DNSZone) *Reconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	objects := make([]runtime.Object, 0, len(zones))
+	for _, zone := range zones {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(zone)
+		require.NoError(t, err)
+		objects = append(objects, &unstructured.Unstructured{Object: content})
+	}
+	client := fakeDynamic.NewSimpleDynamicClient(scheme, objects...)
+	return NewReconciler(client, manager, "", endpoint.NewDomainFilter([]string{"example.org"}))
+}
+
+func newDNSZone(name, domain string) *v1alpha1.DNSZone {
+	return &v1alpha1.DNSZone{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "DNSZone",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "tenants",
+			UID:        types.UID("uid-" + name),
+			Generation: 1,
+		},
+		Spec: v1alpha1.DNSZoneSpec{DomainName: domain},
+	}
+}
+
+func getDNSZone(t *testing.T, r *Reconciler, name string) *v1alpha1.DNSZone {
+	obj, err := r.client.Resource(v1alpha1.DNSZoneResource).Namespace("tenants").Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	zone := &v1alpha1.DNSZone{}
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), zone))
+	return zone
+}
+
+func TestReconcile(t *testing.T) {
+	manager := &fakeZoneManager{}
+	zone := newDNSZone("team-a", "Team-A.example.org.")
+	zone.Spec.Tags = map[string]string{"team": "a"}
+	r := newTestReconciler(t, manager, zone)
+
+	require.NoError(t, r.Reconcile(context.Background()))
+	assert.Equal(t, []provider.ZoneSpec{{
+		DomainName: "team-a.example.org",
+		Tags:       map[string]string{"team": "a"},
+		Reference:  "uid-team-a",
+	}}, manager.specs)
+
+	zone = getDNSZone(t, r, "team-a")
+	assert.Equal(t, "/hostedzone/team-a.example.org", zone.Status.ZoneID)
+	assert.Equal(t, []string{"ns1.example.net", "ns2.example.net"}, zone.Status.NameServers)
+	assert.Equal(t, []string{"12345 13 2 ABCDEF"}, zone.Status.DSRecords)
+	assert.Equal(t, int64(1), zone.Status.ObservedGeneration)
+	assert.True(t, meta.IsStatusConditionTrue(zone.Status.Conditions, v1alpha1.ConditionReady))
+}
+
+func TestReconcileNotReady(t *testing.T) {
+	private := newDNSZone("private", "private.example.org")
+	private.Spec.Visibility = v1alpha1.VisibilityPrivate
+	for _, tt := range []struct {
+		name   string
+		zone   *v1alpha1.DNSZone
+		err    error
+		reason string
+	}{
+		{name: "filtered", zone: newDNSZone("filtered", "example.com"), reason: reasonDomainFiltered},
+		{name: "invalid", zone: private, reason: reasonInvalid},
+		{name: "not owned", zone: newDNSZone("team-a", "team-a.example.org"), err: fmt.Errorf("%w: Z123", provider.ErrZoneNotOwned), reason: reasonNotOwned},
+		{name: "failed", zone: newDNSZone("team-a", "team-a.example.org"), err: errors.New("access denied"), reason: reasonFailed},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &fakeZoneManager{err: tt.err}
+			r := newTestReconciler(t, manager, tt.zone)
+
+			err := r.Reconcile(context.Background())
+			if tt.err != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Empty(t, manager.specs)
+			}
+
+			zone := getDNSZone(t, r, tt.zone.Name)
+			assert.Empty(t, zone.Status.ZoneID)
+			condition := meta.FindStatusCondition(zone.Status.Conditions, v1alpha1.ConditionReady)
+			require.NotNil(t, condition)
+			assert.Equal(t, metav1.ConditionFalse, condition.Status)
+			assert.Equal(t, tt.reason, condition.Reason)
+		})
+	}
+}
+
+func TestStatusEqual(t *testing.T) {
+	a := &v1alpha1.DNSZoneStatus{ZoneID: "Z1", NameServers: []string{"ns1"}}
+	meta.SetStatusCondition(&a.Conditions, metav1.Condition{Type: v1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: reasonReady})
+	b := a.DeepCopy()
+	b.Conditions[0].LastTransitionTime = metav1.Now()
+	assert.True(t, statusEqual(a, b))
+
+	b.NameServers = []string{"ns2"}
+	assert.False(t, statusEqual(a, b))
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export stores the records managed by ExternalDNS outside of the DNS
+// provider, e.g. in a git repository, so that their history can be reviewed.
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// Formats of exported records.
+const (
+	FormatYAML = "yaml"
+	FormatZone = "zone"
+)
+
+// Exporter stores the records managed by ExternalDNS.
+type Exporter interface {
+	Export(ctx context.Context, records []*endpoint.Endpoint) error
+}
+
+// record is a record as exported in YAML.
+type record struct {
+	DNSName          string                              `yaml:"dnsName"`
+	RecordType       string                              `yaml:"recordType"`
+	SetIdentifier    string                              `yaml:"setIdentifier,omitempty"`
+	TTL              int64                               `yaml:"ttl,omitempty"`
+	Targets          []string                            `yaml:"targets"`
+	ProviderSpecific []endpoint.ProviderSpecificProperty `yaml:"providerSpecific,omitempty"`
+}
+
+// Marshal returns the records in the given format, sorted so that unchanged records result in the same output.
+func Marshal(records []*endpoint.Endpoint, format string) ([]byte, error) {
+	sorted := make([]*endpoint.Endpoint, len(records))
+	copy(sorted, records)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.DNSName != b.DNSName {
+			return a.DNSName < b.DNSName
+		}
+		if a.RecordType != b.RecordType {
+			return a.RecordType < b.RecordType
+		}
+		return a.SetIdentifier < b.SetIdentifier
+	})
+
+	switch format {
+	case FormatYAML:
+		return marshalYAML(sorted)
+	case FormatZone:
+		return marshalZone(sorted), nil
+	default:
+		return nil, fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+func marshalYAML(records []*endpoint.Endpoint) ([]byte, error) {
+	exported := make([]record, 0, len(records))
+	for _, r := range records {
+		targets := make([]string, len(r.Targets))
+		copy(targets, r.Targets)
+		sort.Strings(targets)
+		exported = append(exported, record{
+			DNSName:          r.DNSName,
+			RecordType:       r.RecordType,
+			SetIdentifier:    r.SetIdentifier,
+			TTL:              int64(r.RecordTTL),
+			Targets:          targets,
+			ProviderSpecific: r.ProviderSpecific,
+		})
+	}
+	return yaml.Marshal(exported)
+}
+
+// marshalZone returns the records as a zone file, with one line per target. The set identifiers and the
+// provider specific properties, which zone files can't express, are added as comments.
+func marshalZone(records []*endpoint.Endpoint) []byte {
+	var b bytes.Buffer
+	for _, r := range records {
+		if r.SetIdentifier != "" || len(r.ProviderSpecific) > 0 {
+			fmt.Fprintf(&b, "; %s %s", absolute(r.DNSName), r.RecordType)
+			if r.SetIdentifier != "" {
+				fmt.Fprintf(&b, " set-identifier=%s", r.SetIdentifier)
+			}
+			for _, p := range r.ProviderSpecific {
+				fmt.Fprintf(&b, " %s=%s", p.Name, p.Value)
+			}
+			b.WriteString("\n")
+		}
+		targets := make([]string, len(r.Targets))
+		copy(targets, r.Targets)
+		sort.Strings(targets)
+		for _, target := range targets {
+			b.WriteString(absolute(r.DNSName))
+			if r.RecordTTL.IsConfigured() {
+				fmt.Fprintf(&b, "\t%d", r.RecordTTL)
+			}
+			fmt.Fprintf(&b, "\tIN\t%s\t%s\n", r.RecordType, zoneTarget(r.RecordType, target))
+		}
+	}
+	return b.Bytes()
+}
+
+// zoneTarget returns the target as written in a zone file.
+func zoneTarget(recordType, target string) string {
+	switch recordType {
+	case endpoint.RecordTypeCNAME, endpoint.RecordTypeNS, endpoint.RecordTypePTR:
+		return absolute(target)
+	case endpoint.RecordTypeTXT:
+		if strings.HasPrefix(target, `"`) {
+			return target
+		}
+		return fmt.Sprintf("%q", target)
+	default:
+		return target
+	}
+}
+
+func absolute(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func testRecords() []*endpoint.Endpoint {
+	return []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("www.example.org", endpoint.RecordTypeCNAME, 300, "lb.example.org"),
+		endpoint.NewEndpoint("api.example.org", endpoint.RecordTypeA, "2.2.2.2", "1.1.1.1"),
+		endpoint.NewEndpoint("api.example.org", endpoint.RecordTypeTXT, "hello world"),
+		endpoint.NewEndpointWithTTL("eu.example.org", endpoint.RecordTypeA, 60, "3.3.3.3").
+			WithSetIdentifier("eu").
+			WithProviderSpecific("aws/weight", "10"),
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	data, err := Marshal(testRecords(), FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, `- dnsName: api.example.org
+  recordType: A
+  targets:
+  - 1.1.1.1
+  - 2.2.2.2
+- dnsName: api.example.org
+  recordType: TXT
+  targets:
+  - hello world
+- dnsName: eu.example.org
+  recordType: A
+  setIdentifier: eu
+  ttl: 60
+  targets:
+  - 3.3.3.3
+  providerSpecific:
+  - name: aws/weight
+    value: "10"
+- dnsName: www.example.org
+  recordType: CNAME
+  ttl: 300
+  targets:
+  - lb.example.org
+`, string(data))
+}
+
+func TestMarshalZone(t *testing.T) {
+	data, err := Marshal(testRecords(), FormatZone)
+	require.NoError(t, err)
+	assert.Equal(t, `api.example.org.	IN	A	1.1.1.1
+api.example.org.	IN	A	2.2.2.2
+api.example.org.	IN	TXT	"hello world"
+; eu.example.org. A set-identifier=eu aws/weight=10
+eu.example.org.	60	IN	A	3.3.3.3
+www.example.org.	300	IN	CNAME	lb.example.org.
+`, string(data))
+}
+
+func TestMarshalUnknownFormat(t *testing.T) {
+	_, err := Marshal(testRecords(), "json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// GitConfig configures a GitExporter.
+type GitConfig struct {
+	// Repository is the URL of the repository, which may contain credentials
+	Repository string
+	// Branch is the branch the records are committed to, which is created if needed
+	Branch string
+	// Path is the path of the file holding the records within the repository
+	Path string
+	// Format is the format of the file, FormatYAML or FormatZone
+	Format string
+	// Author is the author of the commits, in the form "Name <email>"
+	Author string
+	// Dir is the directory the repository is checked out to
+	Dir string
+	// OwnerID is the owner ID of the exported records, mentioned in the commit messages
+	OwnerID string
+}
+
+// GitExporter commits the records to a file in a git repository and pushes it, using the git command.
+// Every export fetches the branch first, so that commits pushed by others are kept.
+type GitExporter struct {
+	mutex       sync.Mutex
+	config      GitConfig
+	authorName  string
+	authorEmail string
+	initialized bool
+	// exported is the hash of the content pushed last, which isn't exported again
+	exported [sha256.Size]byte
+}
+
+// NewGitExporter returns a GitExporter with the given configuration.
+func NewGitExporter(config GitConfig) (*GitExporter, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("exporting records to git requires the git command: %w", err)
+	}
+	if _, err := Marshal(nil, config.Format); err != nil {
+		return nil, err
+	}
+	author, err := mail.ParseAddress(config.Author)
+	if err != nil {
+		return nil, fmt.Errorf("invalid git author %q: %w", config.Author, err)
+	}
+	if filepath.IsAbs(config.Path) || strings.HasPrefix(filepath.Clean(config.Path), "..") {
+		return nil, fmt.Errorf("the export path %q must be relative to the repository", config.Path)
+	}
+	if config.Dir == "" {
+		if config.Dir, err = os.MkdirTemp("", "external-dns-export-"); err != nil {
+			return nil, err
+		}
+	}
+	return &GitExporter{config: config, authorName: author.Name, authorEmail: author.Address}, nil
+}
+
+// Export commits the records if they changed since the last export, and pushes the commit.
+func (e *GitExporter) Export(ctx context.Context, records []*endpoint.Endpoint) error {
+	content, err := Marshal(records, e.config.Format)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(content)
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.initialized && hash == e.exported {
+		return nil
+	}
+	if err := e.export(ctx, content); err != nil {
+		return err
+	}
+	e.exported = hash
+	return nil
+}
+
+func (e *GitExporter) export(ctx context.Context, content []byte) error {
+	if !e.initialized {
+		if err := e.git(ctx, "init", "-q", "."); err != nil {
+			return err
+		}
+		// the repository may be reused from a previous run
+		_ = e.git(ctx, "remote", "remove", "origin")
+		if err := e.git(ctx, "remote", "add", "origin", e.config.Repository); err != nil {
+			return err
+		}
+		e.initialized = true
+	}
+
+	exists, err := e.branchExists(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := e.git(ctx, "fetch", "-q", "--depth", "1", "origin", e.config.Branch); err != nil {
+			return err
+		}
+		if err := e.git(ctx, "checkout", "-q", "-f", "-B", e.config.Branch, "FETCH_HEAD"); err != nil {
+			return err
+		}
+	} else if err := e.git(ctx, "checkout", "-q", "-f", "--orphan", e.config.Branch); err != nil {
+		return err
+	}
+
+	path := filepath.Join(e.config.Dir, e.config.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return err
+	}
+	if err := e.git(ctx, "add", "--", e.config.Path); err != nil {
+		return err
+	}
+	if exists && e.git(ctx, "diff", "--cached", "--quiet") == nil {
+		log.Debugf("Exported records are unchanged in %s", e.config.Path)
+		return nil
+	}
+
+	message := fmt.Sprintf("Update DNS records of %s", e.config.OwnerID)
+	if err := e.git(ctx, "-c", "user.name="+e.authorName, "-c", "user.email="+e.authorEmail, "commit", "-q", "-m", message); err != nil {
+		return err
+	}
+	if err := e.git(ctx, "push", "-q", "origin", "HEAD:refs/heads/"+e.config.Branch); err != nil {
+		return err
+	}
+	log.Infof("Exported records to %s of branch %s of %s", e.config.Path, e.config.Branch, redact(e.config.Repository))
+	return nil
+}
+
+// branchExists returns true when the branch exists in the repository.
+func (e *GitExporter) branchExists(ctx context.Context) (bool, error) {
+	err := e.git(ctx, "ls-remote", "--exit-code", "--heads", "origin", e.config.Branch)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// git runs a git command in the directory of the repository.
+func (e *GitExporter) git(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = e.config.Dir
+	// never prompt for credentials
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return &gitError{
+			command: args[0],
+			output:  strings.ReplaceAll(strings.TrimSpace(stderr.String()), e.config.Repository, redact(e.config.Repository)),
+			err:     err,
+		}
+	}
+	return nil
+}
+
+// gitError is the error of a failed git command.
+type gitError struct {
+	command string
+	output  string
+	err     error
+}
+
+func (e *gitError) Error() string {
+	if e.output == "" {
+		return fmt.Sprintf("git %s: %v", e.command, e.err)
+	}
+	return fmt.Sprintf("git %s: %v: %s", e.command, e.err, e.output)
+}
+
+func (e *gitError) Unwrap() error {
+	return e.err
+}
+
+// redact removes the credentials from the URL of a repository.
+func redact(repository string) string {
+	u, err := url.Parse(repository)
+	if err != nil || u.User == nil {
+		return repository
+	}
+	return u.Redacted()
+}
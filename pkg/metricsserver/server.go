@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metricsserver serves the metrics and health check endpoints, optionally over TLS
+// and requiring a client certificate or a bearer token, and calls the sync endpoint it serves.
+package metricsserver
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config configures the listener of the metrics and health check endpoints.
+type Config struct {
+	// Address is the address to listen on, e.g. ":7979".
+	Address string
+	// TLSCertFile and TLSKeyFile enable TLS when set.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCAFile, when set, authenticates clients presenting a certificate signed by these CAs.
+	TLSClientCAFile string
+	// BearerTokenFile, when set, authenticates clients sending the token it contains.
+	BearerTokenFile string
+	// PublicPaths are served without authentication, e.g. the endpoints probed by the kubelet.
+	PublicPaths []string
+}
+
+// authRequired returns true when clients have to authenticate.
+func (c *Config) authRequired() bool {
+	return c.TLSClientCAFile != "" || c.BearerTokenFile != ""
+}
+
+// NewServer returns the server of the handler configured by the config.
+func NewServer(cfg Config, handler http.Handler) (*http.Server, error) {
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return nil, errors.New("either both the TLS certificate and key or none must be provided")
+	}
+	if cfg.TLSClientCAFile != "" && cfg.TLSCertFile == "" {
+		return nil, errors.New("client certificates can only be verified when serving TLS")
+	}
+	server := &http.Server{
+		Addr:              cfg.Address,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if cfg.authRequired() {
+		server.Handler = &authenticator{cfg: cfg, next: handler}
+	}
+	if cfg.TLSCertFile == "" {
+		return server, nil
+	}
+
+	certs := &certificateLoader{certFile: cfg.TLSCertFile, keyFile: cfg.TLSKeyFile}
+	if _, err := certs.GetCertificate(nil); err != nil {
+		return nil, err
+	}
+	server.TLSConfig = &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.GetCertificate,
+	}
+	if cfg.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in the client CA %s", cfg.TLSClientCAFile)
+		}
+		server.TLSConfig.ClientCAs = pool
+		// unauthenticated clients are rejected by the handler, so that public paths stay reachable
+		server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return server, nil
+}
+
+// ListenAndServe serves the handler as configured by the config.
+func ListenAndServe(cfg Config, handler http.Handler) error {
+	server, err := NewServer(cfg, handler)
+	if err != nil {
+		return err
+	}
+	if server.TLSConfig != nil {
+		// the certificate is provided by the TLS config
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
+
+// authenticator rejects the requests to non-public paths without a verified client certificate
+// or the bearer token.
+type authenticator struct {
+	cfg  Config
+	next http.Handler
+}
+
+func (a *authenticator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a.public(r.URL.Path) || a.verifiedClient(r) || a.validToken(r) {
+		a.next.ServeHTTP(w, r)
+		return
+	}
+	if a.cfg.BearerTokenFile != "" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="external-dns"`)
+	}
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
+
+func (a *authenticator) public(path string) bool {
+	for _, p := range a.cfg.PublicPaths {
+		if path == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *authenticator) verifiedClient(r *http.Request) bool {
+	return a.cfg.TLSClientCAFile != "" && r.TLS != nil && len(r.TLS.VerifiedChains) > 0
+}
+
+// validToken compares the bearer token of the request with the token file, which is read
+// for every request so that rotated tokens are picked up.
+func (a *authenticator) validToken(r *http.Request) bool {
+	if a.cfg.BearerTokenFile == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	expected, err := os.ReadFile(a.cfg.BearerTokenFile)
+	if err != nil {
+		return false
+	}
+	want := strings.TrimSpace(string(expected))
+	if want == "" {
+		return false
+	}
+	// compare digests so that the comparison does not leak the length of the token
+	got, exp := sha256.Sum256([]byte(token)), sha256.Sum256([]byte(want))
+	return subtle.ConstantTimeCompare(got[:], exp[:]) == 1
+}
+
+// certificateLoader reloads the certificate when its files change, so that rotated
+// certificates are served without restarting.
+type certificateLoader struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func (l *certificateLoader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	modTime, err := latestModTime(l.certFile, l.keyFile)
+	if err != nil {
+		if l.cert != nil {
+			return l.cert, nil
+		}
+		return nil, err
+	}
+	if l.cert != nil && !modTime.After(l.modTime) {
+		return l.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		if l.cert != nil {
+			// keep serving the previous certificate while the files are being rotated
+			return l.cert, nil
+		}
+		return nil, fmt.Errorf("could not load TLS cert: %w", err)
+	}
+	l.cert, l.modTime = &cert, modTime
+	return l.cert, nil
+}
+
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA issues certificates for the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func writeCertificate(t *testing.T, dir string, cert tls.Certificate) (string, string) {
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	require.NoError(t, err)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+// startServer serves a handler answering 200 on every path with the server configured by cfg
+// and returns its URL.
+func startServer(t *testing.T, cfg Config) string {
+	server, err := NewServer(cfg, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	require.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	scheme := "http"
+	if server.TLSConfig != nil {
+		scheme = "https"
+		go server.ServeTLS(listener, "", "")
+	} else {
+		go server.Serve(listener)
+	}
+	t.Cleanup(func() { server.Close() })
+	return scheme + "://" + listener.Addr().String()
+}
+
+func get(t *testing.T, client *http.Client, url, token string) int {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestBearerToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0o600))
+	url := startServer(t, Config{BearerTokenFile: tokenFile, PublicPaths: []string{"/healthz"}})
+	client := http.DefaultClient
+
+	assert.Equal(t, http.StatusUnauthorized, get(t, client, url+"/metrics", ""))
+	assert.Equal(t, http.StatusUnauthorized, get(t, client, url+"/metrics", "wrong"))
+	assert.Equal(t, http.StatusOK, get(t, client, url+"/metrics", "s3cr3t"))
+	assert.Equal(t, http.StatusOK, get(t, client, url+"/healthz", ""))
+
+	// rotated tokens are picked up
+	require.NoError(t, os.WriteFile(tokenFile, []byte("rotated"), 0o600))
+	assert.Equal(t, http.StatusUnauthorized, get(t, client, url+"/metrics", "s3cr3t"))
+	assert.Equal(t, http.StatusOK, get(t, client, url+"/metrics", "rotated"))
+}
+
+func TestClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certFile, keyFile := writeCertificate(t, dir, ca.issue(t, 2, x509.ExtKeyUsageServerAuth))
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, ca.pem, 0o600))
+
+	url := startServer(t, Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: caFile, PublicPaths: []string{"/healthz"}})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+	assert.Equal(t, http.StatusUnauthorized, get(t, anonymous, url+"/metrics", ""))
+	assert.Equal(t, http.StatusOK, get(t, anonymous, url+"/healthz", ""))
+
+	clientCert := ca.issue(t, 3, x509.ExtKeyUsageClientAuth)
+	authenticated := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{clientCert}}}}
+	assert.Equal(t, http.StatusOK, get(t, authenticated, url+"/metrics", ""))
+
+	// certificates from another CA are rejected during the handshake
+	other := newTestCA(t).issue(t, 4, x509.ExtKeyUsageClientAuth)
+	untrusted := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{other}}}}
+	_, err := untrusted.Get(url + "/metrics")
+	assert.Error(t, err)
+}
+
+func TestCertificateReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certFile, keyFile := writeCertificate(t, dir, ca.issue(t, 2, x509.ExtKeyUsageServerAuth))
+
+	l := &certificateLoader{certFile: certFile, keyFile: keyFile}
+	first, err := l.GetCertificate(nil)
+	require.NoError(t, err)
+	same, err := l.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Same(t, first, same)
+
+	writeCertificate(t, dir, ca.issue(t, 5, x509.ExtKeyUsageServerAuth))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	reloaded, err := l.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Certificate[0], reloaded.Certificate[0])
+
+	// the previous certificate is served while the files are invalid
+	require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0o600))
+	future = future.Add(time.Minute)
+	require.NoError(t, os.Chtimes(keyFile, future, future))
+	current, err := l.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Same(t, reloaded, current)
+}
+
+func TestNewServerValidation(t *testing.T) {
+	_, err := NewServer(Config{TLSCertFile: "tls.crt"}, http.NotFoundHandler())
+	assert.Error(t, err)
+	_, err = NewServer(Config{TLSClientCAFile: "ca.crt"}, http.NotFoundHandler())
+	assert.Error(t, err)
+
+	server, err := NewServer(Config{Address: ":7979"}, http.NotFoundHandler())
+	require.NoError(t, err)
+	assert.Nil(t, server.TLSConfig)
+}
+
+func TestTriggerSync(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s3cr3t"), 0o600))
+	ca := newTestCA(t)
+	certFile, keyFile := writeCertificate(t, dir, ca.issue(t, 2, x509.ExtKeyUsageServerAuth))
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, ca.pem, 0o600))
+
+	triggered := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sync", func(w http.ResponseWriter, r *http.Request) {
+		triggered++
+		w.WriteHeader(http.StatusAccepted)
+	})
+	server, err := NewServer(Config{TLSCertFile: certFile, TLSKeyFile: keyFile, BearerTokenFile: tokenFile}, mux)
+	require.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.ServeTLS(listener, "", "")
+	t.Cleanup(func() { server.Close() })
+	url := "https://" + listener.Addr().String() + "/sync"
+
+	require.NoError(t, TriggerSync(context.Background(), url, tokenFile, caFile))
+	assert.Equal(t, 1, triggered)
+
+	wrongToken := filepath.Join(dir, "wrong")
+	require.NoError(t, os.WriteFile(wrongToken, []byte("wrong"), 0o600))
+	assert.ErrorContains(t, TriggerSync(context.Background(), url, wrongToken, caFile), "401 Unauthorized")
+	assert.Error(t, TriggerSync(context.Background(), url, tokenFile, ""))
+	assert.Equal(t, 1, triggered)
+}
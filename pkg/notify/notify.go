@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notify sends a summary of the changes applied by a synchronization,
+// e.g. to a chat channel, so that teams learn about DNS changes as they happen.
+package notify
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/audit"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// Notification summarizes the changes applied, or failed to be applied, by a synchronization.
+type Notification struct {
+	Time    time.Time `json:"time"`
+	Owner   string    `json:"owner,omitempty"`
+	Creates int       `json:"creates"`
+	Updates int       `json:"updates"`
+	Deletes int       `json:"deletes"`
+	// Changes describe the individual changes
+	Changes []audit.Entry `json:"changes"`
+	// Error is the error applying the changes, if they failed
+	Error string `json:"error,omitempty"`
+}
+
+// NewNotification returns the notification of the changes applied by owner at time t, which failed with err if not nil.
+func NewNotification(changes *plan.Changes, owner string, t time.Time, err error) Notification {
+	n := Notification{
+		Time:    t,
+		Owner:   owner,
+		Changes: audit.NewEntries(changes, owner, t),
+	}
+	if err != nil {
+		n.Error = err.Error()
+	}
+	n.count()
+	return n
+}
+
+// count sets the number of changes per action.
+func (n *Notification) count() {
+	n.Creates, n.Updates, n.Deletes = 0, 0, 0
+	for _, c := range n.Changes {
+		switch c.Action {
+		case audit.ActionCreate:
+			n.Creates++
+		case audit.ActionUpdate:
+			n.Updates++
+		case audit.ActionDelete:
+			n.Deletes++
+		}
+	}
+}
+
+// Failed returns true when applying the changes failed.
+func (n Notification) Failed() bool {
+	return n.Error != ""
+}
+
+// Notifier sends notifications.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// FilteredNotifier only forwards the changes of records within the zones, and only when there are at least
+// MinChanges of them. Failures are forwarded regardless of the number of changes.
+type FilteredNotifier struct {
+	Notifier   Notifier
+	Zones      endpoint.DomainFilter
+	MinChanges int
+}
+
+// Notify forwards the notification if it passes the filter.
+func (f *FilteredNotifier) Notify(ctx context.Context, n Notification) error {
+	if f.Zones.IsConfigured() {
+		changes := make([]audit.Entry, 0, len(n.Changes))
+		for _, c := range n.Changes {
+			if f.Zones.Match(c.DNSName) {
+				changes = append(changes, c)
+			}
+		}
+		n.Changes = changes
+		n.count()
+	}
+	if len(n.Changes) == 0 || (!n.Failed() && len(n.Changes) < f.MinChanges) {
+		return nil
+	}
+	return f.Notifier.Notify(ctx, n)
+}
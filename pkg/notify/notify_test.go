@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+func testChanges() *plan.Changes {
+	return &plan.Changes{
+		Create:    []*endpoint.Endpoint{endpoint.NewEndpoint("new.example.org", endpoint.RecordTypeA, "1.1.1.1")},
+		UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpoint("app.example.com", endpoint.RecordTypeCNAME, "lb-1.example.com")},
+		UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpoint("app.example.com", endpoint.RecordTypeCNAME, "lb-2.example.com")},
+		Delete:    []*endpoint.Endpoint{endpoint.NewEndpoint("old.example.org", endpoint.RecordTypeA, "2.2.2.2").WithSetIdentifier("eu")},
+	}
+}
+
+// recordingNotifier records the notifications.
+type recordingNotifier struct {
+	notifications []Notification
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n Notification) error {
+	r.notifications = append(r.notifications, n)
+	return nil
+}
+
+func TestNewNotification(t *testing.T) {
+	n := NewNotification(testChanges(), "owner", time.Now(), nil)
+	assert.Equal(t, 1, n.Creates)
+	assert.Equal(t, 1, n.Updates)
+	assert.Equal(t, 1, n.Deletes)
+	assert.Len(t, n.Changes, 3)
+	assert.False(t, n.Failed())
+
+	n = NewNotification(testChanges(), "owner", time.Now(), errors.New("throttled"))
+	assert.True(t, n.Failed())
+	assert.Equal(t, "throttled", n.Error)
+}
+
+func TestFilteredNotifier(t *testing.T) {
+	ctx := context.Background()
+	recorder := &recordingNotifier{}
+	f := &FilteredNotifier{Notifier: recorder, Zones: endpoint.NewDomainFilter([]string{"example.org"})}
+
+	require.NoError(t, f.Notify(ctx, NewNotification(testChanges(), "owner", time.Now(), nil)))
+	require.Len(t, recorder.notifications, 1)
+	assert.Len(t, recorder.notifications[0].Changes, 2)
+	assert.Equal(t, 1, recorder.notifications[0].Creates)
+	assert.Equal(t, 0, recorder.notifications[0].Updates)
+	assert.Equal(t, 1, recorder.notifications[0].Deletes)
+
+	// no changes within the zones
+	f.Zones = endpoint.NewDomainFilter([]string{"example.net"})
+	require.NoError(t, f.Notify(ctx, NewNotification(testChanges(), "owner", time.Now(), nil)))
+	assert.Len(t, recorder.notifications, 1)
+
+	// below the threshold, unless failed
+	f = &FilteredNotifier{Notifier: recorder, MinChanges: 4}
+	require.NoError(t, f.Notify(ctx, NewNotification(testChanges(), "owner", time.Now(), nil)))
+	assert.Len(t, recorder.notifications, 1)
+	require.NoError(t, f.Notify(ctx, NewNotification(testChanges(), "owner", time.Now(), errors.New("throttled"))))
+	assert.Len(t, recorder.notifications, 2)
+
+	f.MinChanges = 3
+	require.NoError(t, f.Notify(ctx, NewNotification(testChanges(), "owner", time.Now(), nil)))
+	assert.Len(t, recorder.notifications, 3)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received map[string]interface{}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	_, err := NewWebhookNotifier(server.URL, "teams")
+	assert.Error(t, err)
+
+	generic, err := NewWebhookNotifier(server.URL, FormatGeneric)
+	require.NoError(t, err)
+	require.NoError(t, generic.Notify(ctx, NewNotification(testChanges(), "owner", time.Now(), nil)))
+	assert.Equal(t, "owner", received["owner"])
+	assert.Equal(t, float64(1), received["creates"])
+	assert.Len(t, received["changes"], 3)
+
+	slack, err := NewWebhookNotifier(server.URL, FormatSlack)
+	require.NoError(t, err)
+	require.NoError(t, slack.Notify(ctx, NewNotification(testChanges(), "owner", time.Now(), nil)))
+	assert.Equal(t, ":white_check_mark: ExternalDNS `owner` applied 1 creates, 1 updates and 1 deletes\n"+
+		"• create A new.example.org → 1.1.1.1\n"+
+		"• update CNAME app.example.com: lb-1.example.com → lb-2.example.com\n"+
+		"• delete A old.example.org (eu) (2.2.2.2)", received["text"])
+
+	require.NoError(t, slack.Notify(ctx, NewNotification(testChanges(), "", time.Now(), errors.New("throttled"))))
+	assert.Contains(t, received["text"], ":x: ExternalDNS failed to apply 1 creates, 1 updates and 1 deletes: throttled\n")
+
+	status = http.StatusInternalServerError
+	assert.Error(t, slack.Notify(ctx, NewNotification(testChanges(), "owner", time.Now(), nil)))
+}
+
+func TestSlackMessageIsBounded(t *testing.T) {
+	changes := &plan.Changes{}
+	for i := 0; i < maxMessageChanges+5; i++ {
+		changes.Create = append(changes.Create, endpoint.NewEndpoint(fmt.Sprintf("%d.example.org", i), endpoint.RecordTypeA, "1.1.1.1"))
+	}
+	assert.Contains(t, slackMessage(NewNotification(changes, "owner", time.Now(), nil)), "\n… and 5 more")
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/external-dns/pkg/audit"
+	"sigs.k8s.io/external-dns/pkg/webhookclient"
+)
+
+// Formats of the webhook payloads.
+const (
+	// FormatGeneric posts the Notification as JSON
+	FormatGeneric = "generic"
+	// FormatSlack posts a message in the format of Slack incoming webhooks
+	FormatSlack = "slack"
+)
+
+// maxMessageChanges bounds the number of changes listed in a Slack message
+const maxMessageChanges = 20
+
+// WebhookNotifier posts notifications to an HTTP endpoint.
+type WebhookNotifier struct {
+	format string
+	client *webhookclient.Client
+}
+
+// NewWebhookNotifier returns a WebhookNotifier posting to url in the given format.
+func NewWebhookNotifier(url, format string) (*WebhookNotifier, error) {
+	if format != FormatGeneric && format != FormatSlack {
+		return nil, fmt.Errorf("unknown notification format: %s", format)
+	}
+	return &WebhookNotifier{
+		format: format,
+		client: webhookclient.New(url),
+	}, nil
+}
+
+// Notify posts the notification.
+func (w *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	var payload interface{} = n
+	if w.format == FormatSlack {
+		payload = struct {
+			Text string `json:"text"`
+		}{Text: slackMessage(n)}
+	}
+	if err := w.client.PostJSON(ctx, payload); err != nil {
+		return fmt.Errorf("posting notification: %w", err)
+	}
+	return nil
+}
+
+// slackMessage returns the notification as the text of a Slack message.
+func slackMessage(n Notification) string {
+	var b strings.Builder
+	owner := ""
+	if n.Owner != "" {
+		owner = fmt.Sprintf(" `%s`", n.Owner)
+	}
+	if n.Failed() {
+		fmt.Fprintf(&b, ":x: ExternalDNS%s failed to apply %d creates, %d updates and %d deletes: %s", owner, n.Creates, n.Updates, n.Deletes, n.Error)
+	} else {
+		fmt.Fprintf(&b, ":white_check_mark: ExternalDNS%s applied %d creates, %d updates and %d deletes", owner, n.Creates, n.Updates, n.Deletes)
+	}
+	for i, c := range n.Changes {
+		if i == maxMessageChanges {
+			fmt.Fprintf(&b, "\n… and %d more", len(n.Changes)-maxMessageChanges)
+			break
+		}
+		fmt.Fprintf(&b, "\n• %s", changeLine(c))
+	}
+	return b.String()
+}
+
+func changeLine(c audit.Entry) string {
+	name := c.DNSName
+	if c.SetIdentifier != "" {
+		name = fmt.Sprintf("%s (%s)", name, c.SetIdentifier)
+	}
+	switch c.Action {
+	case audit.ActionCreate:
+		return fmt.Sprintf("create %s %s → %s", c.RecordType, name, strings.Join(c.NewTargets, ", "))
+	case audit.ActionUpdate:
+		return fmt.Sprintf("update %s %s: %s → %s", c.RecordType, name, strings.Join(c.OldTargets, ", "), strings.Join(c.NewTargets, ", "))
+	default:
+		return fmt.Sprintf("delete %s %s (%s)", c.RecordType, name, strings.Join(c.OldTargets, ", "))
+	}
+}
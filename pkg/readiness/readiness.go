@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readiness reports whether ExternalDNS is ready to synchronize DNS records.
+package readiness
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Checks holds the outcome of named readiness checks. It serves the readiness
+// endpoint, which fails as long as any of the checks failed.
+type Checks struct {
+	mu       sync.Mutex
+	failures map[string]error
+	funcs    map[string]func() error
+}
+
+// AddFunc adds a named check evaluated whenever the readiness is requested; it fails when
+// the function returns an error.
+func (c *Checks) AddFunc(name string, check func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.funcs == nil {
+		c.funcs = map[string]func() error{}
+	}
+	c.funcs[name] = check
+}
+
+// Set records the outcome of the named check; a nil error marks it as passed.
+func (c *Checks) Set(name string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures == nil {
+		c.failures = map[string]error{}
+	}
+	if err == nil {
+		delete(c.failures, name)
+		return
+	}
+	c.failures[name] = err
+}
+
+// Err returns the failed checks, or nil when all of them passed.
+func (c *Checks) Err() error {
+	c.mu.Lock()
+	failures := make(map[string]error, len(c.failures)+len(c.funcs))
+	for name, err := range c.failures {
+		failures[name] = err
+	}
+	funcs := make(map[string]func() error, len(c.funcs))
+	for name, check := range c.funcs {
+		funcs[name] = check
+	}
+	c.mu.Unlock()
+
+	// the functions are called without holding the lock, so that they may take their time
+	for name, check := range funcs {
+		if err := check(); err != nil {
+			failures[name] = err
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	failed := make([]string, 0, len(failures))
+	for name, err := range failures {
+		failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+	}
+	sort.Strings(failed)
+	return errors.New(strings.Join(failed, "\n"))
+}
+
+// ServeHTTP responds with 200 when all checks passed and 503 with the failed checks otherwise.
+func (c *Checks) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	if err := c.Err(); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(c *Checks) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	return rec
+}
+
+func TestChecks(t *testing.T) {
+	c := &Checks{}
+	assert.Equal(t, http.StatusOK, serve(c).Code)
+
+	c.Set("preflight", errors.New("zone example.org is not writable"))
+	c.Set("sync", errors.New("no synchronization yet"))
+	rec := serve(c)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "preflight: zone example.org is not writable\nsync: no synchronization yet\n", rec.Body.String())
+
+	c.Set("preflight", nil)
+	c.Set("sync", nil)
+	assert.NoError(t, c.Err())
+	assert.Equal(t, http.StatusOK, serve(c).Code)
+}
+
+func TestChecksFunc(t *testing.T) {
+	c := &Checks{}
+	var err error
+	c.AddFunc("sync", func() error { return err })
+	assert.Equal(t, http.StatusOK, serve(c).Code)
+
+	err = errors.New("last successful synchronization 10m0s ago")
+	c.Set("preflight", errors.New("not completed yet"))
+	rec := serve(c)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "preflight: not completed yet\nsync: last successful synchronization 10m0s ago\n", rec.Body.String())
+
+	err = nil
+	c.Set("preflight", nil)
+	assert.NoError(t, c.Err())
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reload watches the configuration of ExternalDNS to apply its changes without restarting.
+package reload
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Watcher calls Reload when the content of the config file changes or when SIGHUP is received.
+type Watcher struct {
+	// File is the config file, e.g. mounted from a ConfigMap
+	File string
+	// Interval is how often the content of the file is compared
+	Interval time.Duration
+	// Reload is called for every change, one call at a time
+	Reload func()
+}
+
+// Run watches the config file until the context is canceled.
+func (w *Watcher) Run(ctx context.Context) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	// the content is compared rather than the modification time, since the files of mounted ConfigMaps are
+	// replaced by swapping symbolic links
+	content, err := os.ReadFile(w.File)
+	if err != nil {
+		log.Warnf("Failed to read the config file %s: %v", w.File, err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangups:
+			log.Infof("Received SIGHUP. Reloading the config file %s", w.File)
+			content, _ = os.ReadFile(w.File)
+			w.Reload()
+		case <-ticker.C:
+			current, err := os.ReadFile(w.File)
+			if err != nil {
+				log.Warnf("Failed to read the config file %s: %v", w.File, err)
+				continue
+			}
+			if bytes.Equal(current, content) {
+				continue
+			}
+			log.Infof("The config file %s changed. Reloading it", w.File)
+			content = current
+			w.Reload()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags")
+	require.NoError(t, os.WriteFile(path, []byte("--source=service\n"), 0o600))
+
+	reloads := make(chan struct{}, 10)
+	w := &Watcher{File: path, Interval: 10 * time.Millisecond, Reload: func() { reloads <- struct{}{} }}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// an unchanged file is not reloaded
+	select {
+	case <-reloads:
+		t.Fatal("reloaded an unchanged config file")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte("--source=ingress\n"), 0o600))
+	select {
+	case <-reloads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the changed config file was not reloaded")
+	}
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	select {
+	case <-reloads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the config file was not reloaded on SIGHUP")
+	}
+}
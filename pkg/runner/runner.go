@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package runner is the supported API for embedding ExternalDNS into
+// other programs.
+//
+// It wires a source of desired endpoints, a registry and a DNS provider
+// together the same way the external-dns binary does, so that operators can
+// synchronize endpoints to a provider without depending on the layout of the
+// controller, plan and registry packages:
+//
+//	src := runner.NewStaticSource(endpoints...)
+//	s, err := runner.New(runner.Config{
+//		Source:   src,
+//		Provider: p,
+//		OwnerID:  "my-operator",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	return s.Run(ctx)
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/external-dns/controller"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/registry"
+	"sigs.k8s.io/external-dns/source"
+)
+
+const (
+	// DefaultInterval is the interval between synchronizations used when Config.Interval is unset.
+	DefaultInterval = time.Minute
+	// DefaultPolicy is the policy used when Config.Policy is unset.
+	DefaultPolicy = "sync"
+)
+
+// Config describes how endpoints are synchronized to a provider.
+type Config struct {
+	// Source returns the desired endpoints. Required.
+	Source source.Source
+	// Provider is the DNS provider the endpoints are synchronized to. Required.
+	Provider provider.Provider
+	// Registry tracks ownership of records. When unset, a TXT registry owned
+	// by OwnerID is used, or a noop registry if OwnerID is empty.
+	Registry registry.Registry
+	// OwnerID identifies the records managed by this instance.
+	OwnerID string
+	// Policy is one of "sync", "upsert-only" or "create-only". Defaults to "sync".
+	Policy string
+	// DomainFilter limits the DNS names that are managed.
+	DomainFilter endpoint.DomainFilter
+	// ManagedRecordTypes are the record types that will be considered for management.
+	// Defaults to A, AAAA and CNAME.
+	ManagedRecordTypes []string
+	// ExcludeRecordTypes are record types that will be excluded from management.
+	ExcludeRecordTypes []string
+	// Interval is the interval between synchronizations. Defaults to DefaultInterval.
+	Interval time.Duration
+	// MinEventSyncInterval is the minimum interval between two synchronizations
+	// triggered by Trigger or source events.
+	MinEventSyncInterval time.Duration
+}
+
+// Syncer synchronizes the endpoints of a source to a provider.
+type Syncer struct {
+	ctrl *controller.Controller
+}
+
+// New validates cfg and returns a Syncer.
+func New(cfg Config) (*Syncer, error) {
+	if cfg.Source == nil {
+		return nil, errors.New("runner: a source is required")
+	}
+	if cfg.Provider == nil && cfg.Registry == nil {
+		return nil, errors.New("runner: a provider or registry is required")
+	}
+	if cfg.Policy == "" {
+		cfg.Policy = DefaultPolicy
+	}
+	policy, ok := plan.Policies[cfg.Policy]
+	if !ok {
+		return nil, fmt.Errorf("runner: unknown policy %q", cfg.Policy)
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if len(cfg.ManagedRecordTypes) == 0 {
+		cfg.ManagedRecordTypes = []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME}
+	}
+
+	r := cfg.Registry
+	if r == nil {
+		var err error
+		if cfg.OwnerID == "" {
+			r, err = registry.NewNoopRegistry(cfg.Provider)
+		} else {
+			r, err = registry.NewTXTRegistry(cfg.Provider, "", "", cfg.OwnerID, 0, "", cfg.ManagedRecordTypes, cfg.ExcludeRecordTypes, false, nil)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("runner: creating registry: %w", err)
+		}
+	}
+
+	return &Syncer{
+		ctrl: &controller.Controller{
+			Source:               cfg.Source,
+			Registry:             r,
+			Policy:               policy,
+			Interval:             cfg.Interval,
+			DomainFilter:         cfg.DomainFilter,
+			ManagedRecordTypes:   cfg.ManagedRecordTypes,
+			ExcludeRecordTypes:   cfg.ExcludeRecordTypes,
+			MinEventSyncInterval: cfg.MinEventSyncInterval,
+		},
+	}, nil
+}
+
+// SyncOnce performs a single synchronization.
+func (s *Syncer) SyncOnce(ctx context.Context) error {
+	return s.ctrl.RunOnce(ctx)
+}
+
+// Trigger schedules a synchronization as soon as MinEventSyncInterval allows.
+func (s *Syncer) Trigger() {
+	s.ctrl.ScheduleRunOnce(time.Now())
+}
+
+// Run synchronizes periodically and on source events until ctx is canceled.
+// Unlike the external-dns binary it does not exit the process on failure:
+// the first synchronization error is returned to the caller.
+func (s *Syncer) Run(ctx context.Context) error {
+	s.ctrl.Source.AddEventHandler(ctx, s.Trigger)
+	s.Trigger()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if s.ctrl.ShouldRunOnce(time.Now()) {
+			if err := s.ctrl.RunOnce(ctx); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
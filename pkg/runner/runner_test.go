@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/internal/testutils"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+)
+
+func TestNewValidation(t *testing.T) {
+	p := inmemory.NewInMemoryProvider()
+
+	_, err := New(Config{Provider: p})
+	assert.Error(t, err)
+
+	_, err = New(Config{Source: NewStaticSource()})
+	assert.Error(t, err)
+
+	_, err = New(Config{Source: NewStaticSource(), Provider: p, Policy: "unknown"})
+	assert.Error(t, err)
+
+	_, err = New(Config{Source: NewStaticSource(), Provider: p})
+	assert.NoError(t, err)
+}
+
+func TestSyncOnce(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"}))
+	src := NewStaticSource(
+		endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "192.0.2.1"),
+		endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeCNAME, "foo.example.com"),
+	)
+
+	s, err := New(Config{Source: src, Provider: p, OwnerID: "embedded"})
+	require.NoError(t, err)
+	require.NoError(t, s.SyncOnce(ctx))
+
+	records, err := s.ctrl.Registry.Records(ctx)
+	require.NoError(t, err)
+	expected := []*endpoint.Endpoint{
+		ownedEndpoint("foo.example.com", endpoint.RecordTypeA, "192.0.2.1"),
+		ownedEndpoint("bar.example.com", endpoint.RecordTypeCNAME, "foo.example.com"),
+	}
+	assert.True(t, testutils.SameEndpoints(expected, records), "expected %v, got %v", expected, records)
+
+	src.SetEndpoints(endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "192.0.2.2"))
+	require.NoError(t, s.SyncOnce(ctx))
+
+	records, err = s.ctrl.Registry.Records(ctx)
+	require.NoError(t, err)
+	expected = []*endpoint.Endpoint{
+		ownedEndpoint("foo.example.com", endpoint.RecordTypeA, "192.0.2.2"),
+	}
+	assert.True(t, testutils.SameEndpoints(expected, records), "expected %v, got %v", expected, records)
+}
+
+func TestStaticSourceEventHandler(t *testing.T) {
+	src := NewStaticSource()
+	calls := 0
+	src.AddEventHandler(context.Background(), func() { calls++ })
+	src.SetEndpoints(endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "192.0.2.1"))
+	assert.Equal(t, 1, calls)
+
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, endpoints, 1)
+}
+
+func ownedEndpoint(dnsName, recordType, target string) *endpoint.Endpoint {
+	ep := endpoint.NewEndpoint(dnsName, recordType, target)
+	ep.Labels[endpoint.OwnerLabelKey] = "embedded"
+	return ep
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"sync"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// StaticSource is a source whose endpoints are set programmatically.
+// It is safe for concurrent use.
+type StaticSource struct {
+	mu        sync.RWMutex
+	endpoints []*endpoint.Endpoint
+	handlers  []func()
+}
+
+// NewStaticSource returns a StaticSource serving the given endpoints.
+func NewStaticSource(endpoints ...*endpoint.Endpoint) *StaticSource {
+	return &StaticSource{endpoints: copyEndpoints(endpoints)}
+}
+
+// SetEndpoints replaces the served endpoints and notifies registered event handlers.
+func (s *StaticSource) SetEndpoints(endpoints ...*endpoint.Endpoint) {
+	s.mu.Lock()
+	s.endpoints = copyEndpoints(endpoints)
+	handlers := append([]func(){}, s.handlers...)
+	s.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
+// Endpoints returns a copy of the served endpoints.
+func (s *StaticSource) Endpoints(_ context.Context) ([]*endpoint.Endpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyEndpoints(s.endpoints), nil
+}
+
+// AddEventHandler registers a handler called whenever the endpoints change.
+func (s *StaticSource) AddEventHandler(_ context.Context, handler func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+func copyEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		result = append(result, ep.DeepCopy())
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tailscale routes the DNS names managed by ExternalDNS to the cluster's name servers
+// within a Tailscale tailnet, using the split DNS configuration of the tailnet.
+//
+// The Tailscale API cannot host arbitrary records, so the records themselves are still published
+// by the configured provider into a zone served by those name servers (e.g. CoreDNS or RFC2136).
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultBaseURL is the address of the Tailscale API.
+const DefaultBaseURL = "https://api.tailscale.com"
+
+// Client manages the split DNS configuration of a tailnet.
+type Client struct {
+	// BaseURL is the address of the Tailscale API, DefaultBaseURL when empty.
+	BaseURL string
+	// Tailnet is the name of the tailnet, "-" for the default tailnet of the API key.
+	Tailnet string
+	// APIKey authenticates the requests, either an API key or an OAuth access token.
+	APIKey string
+	// HTTPClient performs the requests, http.DefaultClient when nil.
+	HTTPClient *http.Client
+}
+
+// SplitDNS maps domains to the name servers resolving them within the tailnet.
+type SplitDNS map[string][]string
+
+// SplitDNS returns the current split DNS configuration of the tailnet.
+func (c *Client) SplitDNS(ctx context.Context) (SplitDNS, error) {
+	config := SplitDNS{}
+	if err := c.do(ctx, http.MethodGet, nil, &config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// UpdateSplitDNS sets the name servers of the given domains, leaving the other domains untouched.
+func (c *Client) UpdateSplitDNS(ctx context.Context, config SplitDNS) error {
+	return c.do(ctx, http.MethodPatch, config, nil)
+}
+
+func (c *Client) do(ctx context.Context, method string, in, out interface{}) error {
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	u := fmt.Sprintf("%s/api/v2/tailnet/%s/dns/split-dns", strings.TrimSuffix(baseURL, "/"), url.PathEscape(c.Tailnet))
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call the tailscale API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("tailscale API %s %s returned %s: %s", method, u, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// EnsureSplitDNS routes the given domains to the name servers within the tailnet.
+// Only the domains whose name servers differ are updated.
+func EnsureSplitDNS(ctx context.Context, c *Client, domains, nameservers []string) error {
+	current, err := c.SplitDNS(ctx)
+	if err != nil {
+		return err
+	}
+	want := slices.Clone(nameservers)
+	sort.Strings(want)
+
+	update := SplitDNS{}
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.TrimPrefix(domain, "."), ".")
+		if domain == "" {
+			continue
+		}
+		have := slices.Clone(current[domain])
+		sort.Strings(have)
+		if !slices.Equal(have, want) {
+			update[domain] = want
+		}
+	}
+	if len(update) == 0 {
+		log.Debug("The split DNS configuration of the tailnet is up to date")
+		return nil
+	}
+	for domain := range update {
+		log.Infof("Routing %s to %s within the tailnet", domain, strings.Join(want, ", "))
+	}
+	return c.UpdateSplitDNS(ctx, update)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTailnet serves the split DNS API of a single tailnet.
+type fakeTailnet struct {
+	config  SplitDNS
+	patches []SplitDNS
+}
+
+func (f *fakeTailnet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/v2/tailnet/example.com/dns/split-dns" || r.Header.Get("Authorization") != "Bearer tskey-api" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPatch:
+		patch := SplitDNS{}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.patches = append(f.patches, patch)
+		for domain, nameservers := range patch {
+			f.config[domain] = nameservers
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(f.config)
+}
+
+func TestEnsureSplitDNS(t *testing.T) {
+	tailnet := &fakeTailnet{config: SplitDNS{
+		"up-to-date.example.org": {"10.0.0.2", "10.0.0.1"},
+		"outdated.example.org":   {"10.0.0.9"},
+		"unrelated.example.org":  {"10.0.0.9"},
+	}}
+	server := httptest.NewServer(tailnet)
+	defer server.Close()
+	c := &Client{BaseURL: server.URL, Tailnet: "example.com", APIKey: "tskey-api"}
+
+	err := EnsureSplitDNS(context.Background(), c,
+		[]string{"up-to-date.example.org", "outdated.example.org.", ".new.example.org", ""},
+		[]string{"10.0.0.1", "10.0.0.2"})
+	require.NoError(t, err)
+	require.Len(t, tailnet.patches, 1)
+	assert.Equal(t, SplitDNS{
+		"outdated.example.org": {"10.0.0.1", "10.0.0.2"},
+		"new.example.org":      {"10.0.0.1", "10.0.0.2"},
+	}, tailnet.patches[0])
+	assert.Equal(t, []string{"10.0.0.9"}, tailnet.config["unrelated.example.org"])
+
+	// nothing is updated once the configuration is converged
+	err = EnsureSplitDNS(context.Background(), c, []string{"outdated.example.org", "new.example.org"}, []string{"10.0.0.2", "10.0.0.1"})
+	require.NoError(t, err)
+	assert.Len(t, tailnet.patches, 1)
+}
+
+func TestEnsureSplitDNSError(t *testing.T) {
+	server := httptest.NewServer(&fakeTailnet{config: SplitDNS{}})
+	defer server.Close()
+	c := &Client{BaseURL: server.URL, Tailnet: "example.com", APIKey: "wrong"}
+
+	err := EnsureSplitDNS(context.Background(), c, []string{"example.org"}, []string{"10.0.0.1"})
+	assert.ErrorContains(t, err, "404 Not Found")
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// Result is the outcome of the verification of a record.
+type Result struct {
+	Endpoint *endpoint.Endpoint
+	// Resolved is when the record was found on all resolvers, zero if it was not
+	Resolved time.Time
+	// Err is why the record was not found, if it was not
+	Err error
+}
+
+// Verifier verifies that records resolve to their targets.
+type Verifier interface {
+	Verify(ctx context.Context, records []*endpoint.Endpoint) []Result
+}
+
+// queryTypes are the record types which can be verified.
+var queryTypes = map[string]uint16{
+	endpoint.RecordTypeA:     dns.TypeA,
+	endpoint.RecordTypeAAAA:  dns.TypeAAAA,
+	endpoint.RecordTypeCNAME: dns.TypeCNAME,
+	endpoint.RecordTypeTXT:   dns.TypeTXT,
+	endpoint.RecordTypeMX:    dns.TypeMX,
+	endpoint.RecordTypeNS:    dns.TypeNS,
+	endpoint.RecordTypeSRV:   dns.TypeSRV,
+	endpoint.RecordTypeSSHFP: dns.TypeSSHFP,
+}
+
+// Supported returns true when the record can be verified. Records with a set identifier aren't, as the answers
+// of their DNS name depend on the routing policy, nor are alias records, which resolve to the targets of their
+// target.
+func Supported(ep *endpoint.Endpoint) bool {
+	if _, ok := queryTypes[ep.RecordType]; !ok || ep.SetIdentifier != "" || len(ep.Targets) == 0 {
+		return false
+	}
+	if alias, ok := ep.GetProviderSpecificProperty("alias"); ok && alias == "true" {
+		return false
+	}
+	return true
+}
+
+// exchanger sends DNS queries, like dns.Client.
+type exchanger interface {
+	ExchangeContext(ctx context.Context, m *dns.Msg, address string) (*dns.Msg, time.Duration, error)
+}
+
+// DNSVerifier verifies records by querying them on a set of resolvers, until all resolvers answer with the
+// targets of the records.
+type DNSVerifier struct {
+	resolvers []string
+	retries   int
+	interval  time.Duration
+	udp       exchanger
+	tcp       exchanger
+	now       func() time.Time
+}
+
+// NewDNSVerifier returns a DNSVerifier querying resolvers, given as host or host:port, with the given timeout
+// per query. The records which don't resolve are queried again up to retries times, after interval.
+func NewDNSVerifier(resolvers []string, timeout time.Duration, retries int, interval time.Duration) (*DNSVerifier, error) {
+	if len(resolvers) == 0 {
+		return nil, errors.New("no resolvers to verify records with")
+	}
+	addresses := make([]string, 0, len(resolvers))
+	for _, r := range resolvers {
+		address, err := resolverAddress(r)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return &DNSVerifier{
+		resolvers: addresses,
+		retries:   retries,
+		interval:  interval,
+		udp:       &dns.Client{Net: "udp", Timeout: timeout},
+		tcp:       &dns.Client{Net: "tcp", Timeout: timeout},
+		now:       time.Now,
+	}, nil
+}
+
+// resolverAddress returns the address of a resolver, with the default DNS port if it has none.
+func resolverAddress(resolver string) (string, error) {
+	if _, port, err := net.SplitHostPort(resolver); err == nil {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return "", fmt.Errorf("invalid port of resolver %q", resolver)
+		}
+		return resolver, nil
+	}
+	host := strings.Trim(resolver, "[]")
+	if host == "" || strings.ContainsAny(host, "/ ") {
+		return "", fmt.Errorf("invalid resolver %q", resolver)
+	}
+	return net.JoinHostPort(host, "53"), nil
+}
+
+// Verify queries the records until they resolve or the retries are exhausted, and returns a Result per record.
+func (v *DNSVerifier) Verify(ctx context.Context, records []*endpoint.Endpoint) []Result {
+	results := make([]Result, len(records))
+	pending := make([]int, 0, len(records))
+	for i, ep := range records {
+		results[i].Endpoint = ep
+		if !Supported(ep) {
+			results[i].Err = fmt.Errorf("records of type %s with a set identifier or an alias cannot be verified", ep.RecordType)
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for attempt := 0; len(pending) > 0; attempt++ {
+		remaining := pending[:0]
+		for _, i := range pending {
+			if err := v.resolves(ctx, records[i]); err != nil {
+				results[i].Err = err
+				remaining = append(remaining, i)
+				continue
+			}
+			results[i].Resolved = v.now()
+			results[i].Err = nil
+		}
+		pending = remaining
+		if len(pending) == 0 || attempt >= v.retries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			for _, i := range pending {
+				results[i].Err = ctx.Err()
+			}
+			return results
+		case <-time.After(v.interval):
+		}
+	}
+	return results
+}
+
+// resolves returns an error unless all resolvers answer with the targets of the record.
+func (v *DNSVerifier) resolves(ctx context.Context, ep *endpoint.Endpoint) error {
+	want := normalizedTargets(ep.RecordType, ep.Targets)
+	for _, resolver := range v.resolvers {
+		got, err := v.query(ctx, resolver, ep.DNSName, queryTypes[ep.RecordType])
+		if err != nil {
+			return fmt.Errorf("querying %s %s on %s: %w", ep.RecordType, ep.DNSName, resolver, err)
+		}
+		if !slices.Equal(got, want) {
+			return fmt.Errorf("%s %s resolves to %v on %s, expected %v", ep.RecordType, ep.DNSName, got, resolver, want)
+		}
+	}
+	return nil
+}
+
+// query returns the normalized answers of a resolver to a query.
+func (v *DNSVerifier) query(ctx context.Context, resolver, name string, qtype uint16) ([]string, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), qtype)
+	in, _, err := v.udp.ExchangeContext(ctx, m, resolver)
+	if err == nil && in.Truncated {
+		in, _, err = v.tcp.ExchangeContext(ctx, m, resolver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if in.Rcode != dns.RcodeSuccess {
+		return nil, errors.New(dns.RcodeToString[in.Rcode])
+	}
+	answers := []string{}
+	for _, rr := range in.Answer {
+		if rr.Header().Rrtype == qtype {
+			answers = append(answers, rrValue(rr))
+		}
+	}
+	slices.Sort(answers)
+	return answers, nil
+}
+
+// rrValue returns the value of a resource record in the form of the targets of an endpoint.
+func rrValue(rr dns.RR) string {
+	switch rr := rr.(type) {
+	case *dns.A:
+		return rr.A.String()
+	case *dns.AAAA:
+		return rr.AAAA.String()
+	case *dns.CNAME:
+		return normalizeName(rr.Target)
+	case *dns.TXT:
+		return strings.Join(rr.Txt, "")
+	case *dns.MX:
+		return fmt.Sprintf("%d %s", rr.Preference, normalizeName(rr.Mx))
+	case *dns.NS:
+		return normalizeName(rr.Ns)
+	case *dns.SRV:
+		return fmt.Sprintf("%d %d %d %s", rr.Priority, rr.Weight, rr.Port, normalizeName(rr.Target))
+	case *dns.SSHFP:
+		return fmt.Sprintf("%d %d %s", rr.Algorithm, rr.Type, strings.ToLower(rr.FingerPrint))
+	}
+	return rr.String()
+}
+
+// normalizedTargets returns the targets in the form of the values returned by rrValue, sorted.
+func normalizedTargets(recordType string, targets endpoint.Targets) []string {
+	normalized := make([]string, 0, len(targets))
+	for _, t := range targets {
+		switch recordType {
+		case endpoint.RecordTypeA, endpoint.RecordTypeAAAA:
+			if ip := net.ParseIP(t); ip != nil {
+				t = ip.String()
+			}
+		case endpoint.RecordTypeTXT:
+			t = strings.TrimSuffix(strings.TrimPrefix(t, `"`), `"`)
+		default:
+			// names are the last field of MX and SRV targets, fingerprints the last field of SSHFP targets
+			fields := strings.Fields(t)
+			if len(fields) > 0 {
+				fields[len(fields)-1] = normalizeName(fields[len(fields)-1])
+			}
+			t = strings.Join(fields, " ")
+		}
+		normalized = append(normalized, t)
+	}
+	slices.Sort(normalized)
+	return normalized
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
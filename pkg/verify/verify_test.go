@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// fakeResolver answers queries with the records of its zone, given in the presentation format.
+type fakeResolver struct {
+	zone      map[string][]string
+	queries   int
+	truncated bool
+}
+
+func (r *fakeResolver) ExchangeContext(_ context.Context, m *dns.Msg, _ string) (*dns.Msg, time.Duration, error) {
+	r.queries++
+	in := new(dns.Msg)
+	in.SetReply(m)
+	q := m.Question[0]
+	records, ok := r.zone[q.Name]
+	if !ok {
+		in.Rcode = dns.RcodeNameError
+		return in, 0, nil
+	}
+	for _, record := range records {
+		rr, err := dns.NewRR(q.Name + " 300 IN " + record)
+		if err != nil {
+			return nil, 0, err
+		}
+		if rr.Header().Rrtype == q.Qtype {
+			in.Answer = append(in.Answer, rr)
+		}
+	}
+	in.Truncated = r.truncated
+	return in, 0, nil
+}
+
+func newTestVerifier(resolver exchanger, retries int) *DNSVerifier {
+	return &DNSVerifier{
+		resolvers: []string{"127.0.0.1:53"},
+		retries:   retries,
+		udp:       resolver,
+		tcp:       resolver,
+		now:       time.Now,
+	}
+}
+
+func TestResolverAddress(t *testing.T) {
+	for resolver, expected := range map[string]string{
+		"1.1.1.1":         "1.1.1.1:53",
+		"1.1.1.1:5353":    "1.1.1.1:5353",
+		"dns.example.org": "dns.example.org:53",
+		"2001:db8::1":     "[2001:db8::1]:53",
+		"[2001:db8::1]":   "[2001:db8::1]:53",
+		"[2001:db8::1]:5": "[2001:db8::1]:5",
+	} {
+		address, err := resolverAddress(resolver)
+		require.NoError(t, err, resolver)
+		assert.Equal(t, expected, address, resolver)
+	}
+	for _, resolver := range []string{"", "1.1.1.1:dns", "https://dns.example.org"} {
+		_, err := resolverAddress(resolver)
+		assert.Error(t, err, resolver)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	assert.True(t, Supported(endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1")))
+	assert.False(t, Supported(endpoint.NewEndpoint("a.example.org", endpoint.RecordTypePTR, "b.example.org")))
+	assert.False(t, Supported(endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1").WithSetIdentifier("eu")))
+	assert.False(t, Supported(endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeCNAME, "lb.example.net").WithProviderSpecific("alias", "true")))
+}
+
+func TestVerify(t *testing.T) {
+	resolver := &fakeResolver{zone: map[string][]string{
+		"a.example.org.":    {"A 1.1.1.1", "A 2.2.2.2"},
+		"v6.example.org.":   {"AAAA 2001:db8:0:0::1"},
+		"www.example.org.":  {"CNAME LB.example.net."},
+		"txt.example.org.":  {`TXT "heritage=external-dns"`},
+		"mail.example.org.": {"MX 10 mx.example.org."},
+		"_sip._tcp.example.org.": {
+			"SRV 10 5 5060 sip.example.org.",
+		},
+		"node.example.org.": {"SSHFP 4 2 ABCDEF0123"},
+	}}
+	records := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "2.2.2.2", "1.1.1.1"),
+		endpoint.NewEndpoint("v6.example.org", endpoint.RecordTypeAAAA, "2001:db8::1"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "lb.example.net"),
+		endpoint.NewEndpoint("txt.example.org", endpoint.RecordTypeTXT, `"heritage=external-dns"`),
+		endpoint.NewEndpoint("mail.example.org", endpoint.RecordTypeMX, "10 mx.example.org"),
+		endpoint.NewEndpoint("_sip._tcp.example.org", endpoint.RecordTypeSRV, "10 5 5060 sip.example.org."),
+		endpoint.NewEndpoint("node.example.org", endpoint.RecordTypeSSHFP, "4 2 abcdef0123"),
+	}
+
+	for _, result := range newTestVerifier(resolver, 0).Verify(context.Background(), records) {
+		assert.NoError(t, result.Err, result.Endpoint.DNSName)
+		assert.False(t, result.Resolved.IsZero(), result.Endpoint.DNSName)
+	}
+
+	resolver.truncated = true
+	results := newTestVerifier(resolver, 0).Verify(context.Background(), records[:1])
+	assert.NoError(t, results[0].Err)
+}
+
+func TestVerifyFailures(t *testing.T) {
+	resolver := &fakeResolver{zone: map[string][]string{
+		"a.example.org.": {"A 1.1.1.1"},
+	}}
+	records := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1", "2.2.2.2"),
+		endpoint.NewEndpoint("missing.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("weighted.example.org", endpoint.RecordTypeA, "1.1.1.1").WithSetIdentifier("eu"),
+	}
+
+	results := newTestVerifier(resolver, 2).Verify(context.Background(), records)
+	require.Len(t, results, 3)
+	for _, result := range results {
+		assert.True(t, result.Resolved.IsZero(), result.Endpoint.DNSName)
+	}
+	assert.ErrorContains(t, results[0].Err, "resolves to [1.1.1.1]")
+	assert.ErrorContains(t, results[1].Err, "NXDOMAIN")
+	assert.ErrorContains(t, results[2].Err, "cannot be verified")
+	// both records were queried three times, the unsupported one never
+	assert.Equal(t, 6, resolver.queries)
+}
+
+func TestVerifyRetries(t *testing.T) {
+	resolver := &fakeResolver{zone: map[string][]string{}}
+	v := newTestVerifier(resolver, 3)
+	v.interval = time.Millisecond
+	// the record is created after the first query
+	v.udp = exchangerFunc(func(ctx context.Context, m *dns.Msg, address string) (*dns.Msg, time.Duration, error) {
+		in, rtt, err := resolver.ExchangeContext(ctx, m, address)
+		resolver.zone["a.example.org."] = []string{"A 1.1.1.1"}
+		return in, rtt, err
+	})
+
+	results := v.Verify(context.Background(), []*endpoint.Endpoint{endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1")})
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, 2, resolver.queries)
+}
+
+type exchangerFunc func(ctx context.Context, m *dns.Msg, address string) (*dns.Msg, time.Duration, error)
+
+func (f exchangerFunc) ExchangeContext(ctx context.Context, m *dns.Msg, address string) (*dns.Msg, time.Duration, error) {
+	return f(ctx, m, address)
+}
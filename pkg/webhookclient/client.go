@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhookclient posts JSON payloads to the webhooks ExternalDNS reports to,
+// e.g. the audit entries and the notifications of the applied changes.
+package webhookclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const timeout = 10 * time.Second
+
+// Client posts JSON payloads to a webhook URL.
+type Client struct {
+	url    string
+	client *http.Client
+}
+
+// New returns a Client posting to url, each request being given 10 seconds.
+func New(url string) *Client {
+	return &Client{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// PostJSON posts the payload encoded as JSON and fails unless the webhook answers with a 2xx status.
+func (c *Client) PostJSON(ctx context.Context, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// maxCanaryWeight is the sum of the weights of the weighted records of a DNS name with canary records,
+// so that the weights are percentages of the traffic.
+const maxCanaryWeight = 100
+
+// weightCanaries returns the desired records with the weights of the DNS names with canary records,
+// i.e. records requesting a percentage of the traffic of a DNS name with the canary-weight provider
+// specific property. The canary records get their percentage and the other records of the DNS name
+// and record type share the rest evenly, e.g. a canary-weight of 10 results in the weights 10 and 90,
+// and changing it shifts the traffic progressively. The weights are set in the weightProperty provider
+// specific property, canary weights are ignored when it's empty.
+func weightCanaries(desired []*endpoint.Endpoint, weightProperty string) []*endpoint.Endpoint {
+	groups := map[endpoint.EndpointKey][]int{}
+	canaries := map[endpoint.EndpointKey]bool{}
+	for i, ep := range desired {
+		key := endpoint.EndpointKey{DNSName: ep.DNSName, RecordType: ep.RecordType}
+		groups[key] = append(groups[key], i)
+		if _, ok := ep.GetProviderSpecificProperty(endpoint.ProviderSpecificCanaryWeight); ok {
+			canaries[key] = true
+		}
+	}
+	if len(canaries) == 0 {
+		return desired
+	}
+
+	result := make([]*endpoint.Endpoint, len(desired))
+	copy(result, desired)
+	for key := range canaries {
+		records := make([]*endpoint.Endpoint, 0, len(groups[key]))
+		for _, idx := range groups[key] {
+			records = append(records, desired[idx])
+		}
+		for i, ep := range canaryWeights(key, records, weightProperty) {
+			result[groups[key][i]] = ep
+		}
+	}
+	return result
+}
+
+// canaryWeights returns copies of the records of a DNS name and record type with canary records,
+// weighted unless the canary weights can't be used.
+func canaryWeights(key endpoint.EndpointKey, records []*endpoint.Endpoint, weightProperty string) []*endpoint.Endpoint {
+	weighted := make([]*endpoint.Endpoint, len(records))
+	canaries := map[int]int64{}
+	var total int64
+	for i, ep := range records {
+		weighted[i] = ep.DeepCopy()
+		weighted[i].DeleteProviderSpecificProperty(endpoint.ProviderSpecificCanaryWeight)
+
+		value, ok := ep.GetProviderSpecificProperty(endpoint.ProviderSpecificCanaryWeight)
+		if !ok {
+			continue
+		}
+		weight, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || weight < 0 || weight > maxCanaryWeight {
+			log.Warnf("Ignoring canary weight %q of %s %s with set identifier %q, expected a percentage", value, key.DNSName, key.RecordType, ep.SetIdentifier)
+			continue
+		}
+		canaries[i] = weight
+		total += weight
+	}
+
+	switch {
+	case len(canaries) == 0:
+		return weighted
+	case weightProperty == "":
+		log.Warnf("Ignoring the canary weights of %s %s, the provider doesn't support weighted records", key.DNSName, key.RecordType)
+		return weighted
+	case total > maxCanaryWeight:
+		log.Warnf("Ignoring the canary weights of %s %s, which add up to more than %d", key.DNSName, key.RecordType, maxCanaryWeight)
+		return weighted
+	}
+	for _, ep := range records {
+		if ep.SetIdentifier == "" {
+			log.Warnf("Ignoring the canary weights of %s %s, all its records need a set identifier", key.DNSName, key.RecordType)
+			return weighted
+		}
+	}
+
+	// the records without canary weight share the rest of the traffic, the remainder of the division
+	// going to the first ones by set identifier so that the weights don't change between synchronizations
+	var stable []*endpoint.Endpoint
+	for i, ep := range weighted {
+		if weight, ok := canaries[i]; ok {
+			ep.SetProviderSpecificProperty(weightProperty, strconv.FormatInt(weight, 10))
+		} else {
+			stable = append(stable, ep)
+		}
+	}
+	sort.Slice(stable, func(i, j int) bool {
+		return stable[i].SetIdentifier < stable[j].SetIdentifier
+	})
+	for i, ep := range stable {
+		weight := (maxCanaryWeight - total) / int64(len(stable))
+		if int64(i) < (maxCanaryWeight-total)%int64(len(stable)) {
+			weight++
+		}
+		ep.SetProviderSpecificProperty(weightProperty, strconv.FormatInt(weight, 10))
+	}
+	log.Debugf("Weighted the records of %s %s with canary records", key.DNSName, key.RecordType)
+	return weighted
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+const testWeightProperty = "aws/weight"
+
+func canaryEndpoint(setIdentifier, target, canaryWeight string) *endpoint.Endpoint {
+	ep := endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeCNAME, target).WithSetIdentifier(setIdentifier)
+	if canaryWeight != "" {
+		ep.WithProviderSpecific(endpoint.ProviderSpecificCanaryWeight, canaryWeight)
+	}
+	return ep
+}
+
+func weightsBySetIdentifier(endpoints []*endpoint.Endpoint) map[string]string {
+	weights := map[string]string{}
+	for _, ep := range endpoints {
+		_, canary := ep.GetProviderSpecificProperty(endpoint.ProviderSpecificCanaryWeight)
+		if canary {
+			weights[ep.SetIdentifier] = "canary"
+			continue
+		}
+		weights[ep.SetIdentifier], _ = ep.GetProviderSpecificProperty(testWeightProperty)
+	}
+	return weights
+}
+
+func TestWeightCanaries(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		desired        []*endpoint.Endpoint
+		weightProperty string
+		expected       map[string]string
+	}{
+		{
+			name: "canary and stable",
+			desired: []*endpoint.Endpoint{
+				canaryEndpoint("stable", "stable.example.org", ""),
+				canaryEndpoint("canary", "canary.example.org", "10"),
+			},
+			weightProperty: testWeightProperty,
+			expected:       map[string]string{"stable": "90", "canary": "10"},
+		},
+		{
+			name: "rest shared by stable records",
+			desired: []*endpoint.Endpoint{
+				canaryEndpoint("stable-b", "b.example.org", ""),
+				canaryEndpoint("stable-a", "a.example.org", ""),
+				canaryEndpoint("canary", "canary.example.org", "25"),
+			},
+			weightProperty: testWeightProperty,
+			expected:       map[string]string{"stable-a": "38", "stable-b": "37", "canary": "25"},
+		},
+		{
+			name: "whole traffic shifted",
+			desired: []*endpoint.Endpoint{
+				canaryEndpoint("stable", "stable.example.org", ""),
+				canaryEndpoint("canary", "canary.example.org", "100"),
+			},
+			weightProperty: testWeightProperty,
+			expected:       map[string]string{"stable": "0", "canary": "100"},
+		},
+		{
+			name: "canaries only",
+			desired: []*endpoint.Endpoint{
+				canaryEndpoint("canary-1", "canary-1.example.org", "20"),
+				canaryEndpoint("canary-2", "canary-2.example.org", "30"),
+			},
+			weightProperty: testWeightProperty,
+			expected:       map[string]string{"canary-1": "20", "canary-2": "30"},
+		},
+		{
+			name: "invalid canary weight is a stable record",
+			desired: []*endpoint.Endpoint{
+				canaryEndpoint("stable", "stable.example.org", ""),
+				canaryEndpoint("invalid", "invalid.example.org", "half"),
+				canaryEndpoint("canary", "canary.example.org", "10"),
+			},
+			weightProperty: testWeightProperty,
+			expected:       map[string]string{"stable": "45", "invalid": "45", "canary": "10"},
+		},
+		{
+			name: "canary weights over 100",
+			desired: []*endpoint.Endpoint{
+				canaryEndpoint("canary-1", "canary-1.example.org", "60"),
+				canaryEndpoint("canary-2", "canary-2.example.org", "60"),
+			},
+			weightProperty: testWeightProperty,
+			expected:       map[string]string{"canary-1": "", "canary-2": ""},
+		},
+		{
+			name: "record without set identifier",
+			desired: []*endpoint.Endpoint{
+				canaryEndpoint("", "stable.example.org", ""),
+				canaryEndpoint("canary", "canary.example.org", "10"),
+			},
+			weightProperty: testWeightProperty,
+			expected:       map[string]string{"": "", "canary": ""},
+		},
+		{
+			name: "provider without weighted records",
+			desired: []*endpoint.Endpoint{
+				canaryEndpoint("stable", "stable.example.org", ""),
+				canaryEndpoint("canary", "canary.example.org", "10"),
+			},
+			expected: map[string]string{"stable": "", "canary": ""},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			weighted := weightCanaries(tt.desired, tt.weightProperty)
+			require.Len(t, weighted, len(tt.desired))
+			assert.Equal(t, tt.expected, weightsBySetIdentifier(weighted))
+		})
+	}
+}
+
+func TestWeightCanariesKeepsDesired(t *testing.T) {
+	desired := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("other.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		canaryEndpoint("stable", "stable.example.org", ""),
+		canaryEndpoint("canary", "canary.example.org", "10"),
+	}
+
+	weighted := weightCanaries(desired, testWeightProperty)
+	assert.Same(t, desired[0], weighted[0])
+	assert.Equal(t, "canary", weighted[2].SetIdentifier)
+	// the desired records are copied as they may be shared with the source
+	_, ok := desired[2].GetProviderSpecificProperty(endpoint.ProviderSpecificCanaryWeight)
+	assert.True(t, ok)
+	_, ok = desired[1].GetProviderSpecificProperty(testWeightProperty)
+	assert.False(t, ok)
+}
+
+func TestCalculateCanaryWeightShift(t *testing.T) {
+	current := []*endpoint.Endpoint{
+		canaryEndpoint("stable", "stable.example.org", "").WithProviderSpecific(testWeightProperty, "90"),
+		canaryEndpoint("canary", "canary.example.org", "").WithProviderSpecific(testWeightProperty, "10"),
+	}
+
+	// unchanged canary weight
+	p := (&Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        []*endpoint.Endpoint{canaryEndpoint("stable", "stable.example.org", ""), canaryEndpoint("canary", "canary.example.org", "10")},
+		ManagedRecords: []string{endpoint.RecordTypeCNAME},
+		WeightProperty: testWeightProperty,
+	}).Calculate()
+	assert.False(t, p.Changes.HasChanges())
+
+	// the traffic shifted to the canary
+	p = (&Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        []*endpoint.Endpoint{canaryEndpoint("stable", "stable.example.org", ""), canaryEndpoint("canary", "canary.example.org", "50")},
+		ManagedRecords: []string{endpoint.RecordTypeCNAME},
+		WeightProperty: testWeightProperty,
+	}).Calculate()
+	assert.Equal(t, map[string]string{"stable": "50", "canary": "50"}, weightsBySetIdentifier(p.Changes.UpdateNew))
+	assert.Equal(t, map[string]string{"stable": "90", "canary": "10"}, weightsBySetIdentifier(p.Changes.UpdateOld))
+}
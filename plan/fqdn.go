@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// FQDNPolicy controls the canonical form of DNS names and of the host names in targets,
+// e.g. the target of a CNAME record or the exchange of an MX record.
+type FQDNPolicy string
+
+const (
+	// FQDNPolicyPreserve publishes names as produced by the sources.
+	FQDNPolicyPreserve FQDNPolicy = "preserve"
+	// FQDNPolicyRelative publishes names in lower case and without trailing dot.
+	FQDNPolicyRelative FQDNPolicy = "relative"
+	// FQDNPolicyAbsolute publishes DNS names in lower case and without trailing dot, and
+	// host names in targets in lower case and with a trailing dot.
+	FQDNPolicyAbsolute FQDNPolicy = "absolute"
+)
+
+// FQDNPolicies lists the supported FQDN policies.
+var FQDNPolicies = []string{string(FQDNPolicyPreserve), string(FQDNPolicyRelative), string(FQDNPolicyAbsolute)}
+
+// Canonicalize rewrites the DNS name and host name targets of the endpoints according to the policy.
+func (p FQDNPolicy) Canonicalize(endpoints []*endpoint.Endpoint) {
+	if p == "" || p == FQDNPolicyPreserve {
+		return
+	}
+	for _, e := range endpoints {
+		e.DNSName = strings.TrimSuffix(strings.ToLower(e.DNSName), ".")
+		if !hasHostnameTargets(e.RecordType) {
+			continue
+		}
+		for i, target := range e.Targets {
+			e.Targets[i] = p.canonicalTarget(e.RecordType, target)
+		}
+	}
+}
+
+// Normalizations returns the normalizations comparing targets in their canonical form, so that
+// records observed in another form than the one published are not considered changed.
+func (p FQDNPolicy) Normalizations(normalizations []TargetNormalization) []TargetNormalization {
+	if p == "" || p == FQDNPolicyPreserve {
+		return normalizations
+	}
+	if len(normalizations) == 0 {
+		normalizations = DefaultTargetNormalizations
+	}
+	return append(append([]TargetNormalization{}, normalizations...), p)
+}
+
+// Normalize returns the host name targets in the canonical form of the policy.
+func (p FQDNPolicy) Normalize(recordType, target string) string {
+	if !hasHostnameTargets(recordType) {
+		return target
+	}
+	return p.canonicalTarget(recordType, target)
+}
+
+func (p FQDNPolicy) canonicalTarget(recordType, target string) string {
+	// the host name is the last field of MX and SRV targets, e.g. "10 mail.example.org"
+	prefix, host := "", target
+	if recordType == endpoint.RecordTypeMX || recordType == endpoint.RecordTypeSRV {
+		if i := strings.LastIndexByte(target, ' '); i >= 0 {
+			prefix, host = target[:i+1], target[i+1:]
+		}
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if p == FQDNPolicyAbsolute && host != "" {
+		host += "."
+	}
+	return prefix + host
+}
+
+func hasHostnameTargets(recordType string) bool {
+	switch recordType {
+	case endpoint.RecordTypeCNAME, endpoint.RecordTypeNS, endpoint.RecordTypePTR, endpoint.RecordTypeMX, endpoint.RecordTypeSRV:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// withTargets sets the targets as is, NewEndpoint strips their trailing dots.
+func withTargets(e *endpoint.Endpoint, targets ...string) *endpoint.Endpoint {
+	e.Targets = targets
+	return e
+}
+
+func newFQDNTestEndpoints() []*endpoint.Endpoint {
+	return []*endpoint.Endpoint{
+		withTargets(endpoint.NewEndpoint("WWW.Example.org.", endpoint.RecordTypeCNAME), "LB.example.com."),
+		withTargets(endpoint.NewEndpoint("example.org", endpoint.RecordTypeMX), "10 Mail.example.org"),
+		withTargets(endpoint.NewEndpoint("_sip._tcp.example.org", endpoint.RecordTypeSRV), "0 50 5060 sip.example.org."),
+		withTargets(endpoint.NewEndpoint("example.org", endpoint.RecordTypeTXT), "Text."),
+	}
+}
+
+func TestFQDNPolicyCanonicalize(t *testing.T) {
+	for _, tc := range []struct {
+		policy   FQDNPolicy
+		expected []*endpoint.Endpoint
+	}{
+		{
+			policy:   FQDNPolicyPreserve,
+			expected: newFQDNTestEndpoints(),
+		},
+		{
+			policy: FQDNPolicyRelative,
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "lb.example.com"),
+				endpoint.NewEndpoint("example.org", endpoint.RecordTypeMX, "10 mail.example.org"),
+				endpoint.NewEndpoint("_sip._tcp.example.org", endpoint.RecordTypeSRV, "0 50 5060 sip.example.org"),
+				withTargets(endpoint.NewEndpoint("example.org", endpoint.RecordTypeTXT), "Text."),
+			},
+		},
+		{
+			policy: FQDNPolicyAbsolute,
+			expected: []*endpoint.Endpoint{
+				withTargets(endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME), "lb.example.com."),
+				withTargets(endpoint.NewEndpoint("example.org", endpoint.RecordTypeMX), "10 mail.example.org."),
+				withTargets(endpoint.NewEndpoint("_sip._tcp.example.org", endpoint.RecordTypeSRV), "0 50 5060 sip.example.org."),
+				withTargets(endpoint.NewEndpoint("example.org", endpoint.RecordTypeTXT), "Text."),
+			},
+		},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			endpoints := newFQDNTestEndpoints()
+			tc.policy.Canonicalize(endpoints)
+			assert.Equal(t, tc.expected, endpoints)
+		})
+	}
+}
+
+func TestFQDNPolicyNormalizations(t *testing.T) {
+	assert.Nil(t, FQDNPolicyPreserve.Normalizations(nil))
+
+	// records observed in another form than the one published are unchanged
+	current := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "lb.example.com"),
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeMX, "10 mail.example.org"),
+	}
+	desired := newFQDNTestEndpoints()[:2]
+	FQDNPolicyAbsolute.Canonicalize(desired)
+
+	p := &Plan{
+		Policies:             []Policy{&SyncPolicy{}},
+		Current:              current,
+		Desired:              desired,
+		ManagedRecords:       []string{endpoint.RecordTypeCNAME, endpoint.RecordTypeMX},
+		TargetNormalizations: FQDNPolicyAbsolute.Normalizations(nil),
+	}
+	changes := p.Calculate().Changes
+	assert.False(t, changes.HasChanges())
+
+	p.TargetNormalizations = nil
+	p.Changes = nil
+	changes = p.Calculate().Changes
+	assert.Len(t, changes.UpdateNew, 2)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"net/netip"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// TargetNormalization rewrites a target into a canonical form before the
+// targets of current and desired records are compared, so that targets
+// differing only in their textual form are not considered changed.
+type TargetNormalization interface {
+	Normalize(recordType, target string) string
+}
+
+// TargetNormalizations is a registry of available target normalizations.
+var TargetNormalizations = map[string]TargetNormalization{
+	"case":         &CaseFoldingNormalization{},
+	"trailing-dot": &TrailingDotNormalization{},
+	"ipv6":         &IPv6Normalization{},
+}
+
+// DefaultTargetNormalizations are used when a Plan doesn't specify any.
+var DefaultTargetNormalizations = []TargetNormalization{&CaseFoldingNormalization{}}
+
+// CaseFoldingNormalization compares targets case-insensitively.
+type CaseFoldingNormalization struct{}
+
+// Normalize returns the target in lower case.
+func (n *CaseFoldingNormalization) Normalize(_, target string) string {
+	return strings.ToLower(target)
+}
+
+// TrailingDotNormalization ignores the trailing dot of fully qualified targets.
+// TXT targets are left untouched.
+type TrailingDotNormalization struct{}
+
+// Normalize returns the target without trailing dot.
+func (n *TrailingDotNormalization) Normalize(recordType, target string) string {
+	if recordType == endpoint.RecordTypeTXT {
+		return target
+	}
+	return strings.TrimSuffix(target, ".")
+}
+
+// IPv6Normalization compares IPv6 addresses in their canonical textual form (RFC 5952),
+// e.g. "2001:db8:0:0::1" and "2001:DB8::1" are the same target.
+type IPv6Normalization struct{}
+
+// Normalize returns the canonical form of IPv6 addresses.
+func (n *IPv6Normalization) Normalize(recordType, target string) string {
+	if recordType != endpoint.RecordTypeAAAA {
+		return target
+	}
+	addr, err := netip.ParseAddr(target)
+	if err != nil || !addr.Is6() {
+		return target
+	}
+	return addr.String()
+}
+
+// NormalizeTargets returns the sorted targets after applying the normalizations in order.
+func NormalizeTargets(normalizations []TargetNormalization, recordType string, targets endpoint.Targets) []string {
+	normalized := make([]string, 0, len(targets))
+	for _, target := range targets {
+		for _, n := range normalizations {
+			target = n.Normalize(recordType, target)
+		}
+		normalized = append(normalized, target)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
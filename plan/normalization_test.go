@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestTargetNormalizations(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		normalizations []TargetNormalization
+		recordType     string
+		desired        endpoint.Targets
+		current        endpoint.Targets
+		changed        bool
+	}{
+		{
+			name:       "case is folded by default",
+			recordType: endpoint.RecordTypeCNAME,
+			desired:    endpoint.Targets{"LB.example.com"},
+			current:    endpoint.Targets{"lb.example.com"},
+		},
+		{
+			name:       "trailing dot is significant by default",
+			recordType: endpoint.RecordTypeCNAME,
+			desired:    endpoint.Targets{"lb.example.com"},
+			current:    endpoint.Targets{"lb.example.com."},
+			changed:    true,
+		},
+		{
+			name:           "trailing dot normalization",
+			normalizations: []TargetNormalization{TargetNormalizations["trailing-dot"]},
+			recordType:     endpoint.RecordTypeCNAME,
+			desired:        endpoint.Targets{"lb.example.com"},
+			current:        endpoint.Targets{"lb.example.com."},
+		},
+		{
+			name:           "case is significant without case folding",
+			normalizations: []TargetNormalization{TargetNormalizations["trailing-dot"]},
+			recordType:     endpoint.RecordTypeCNAME,
+			desired:        endpoint.Targets{"LB.example.com"},
+			current:        endpoint.Targets{"lb.example.com."},
+			changed:        true,
+		},
+		{
+			name:           "trailing dot of TXT targets is kept",
+			normalizations: []TargetNormalization{TargetNormalizations["trailing-dot"]},
+			recordType:     endpoint.RecordTypeTXT,
+			desired:        endpoint.Targets{"text"},
+			current:        endpoint.Targets{"text."},
+			changed:        true,
+		},
+		{
+			name:           "ipv6 normalization",
+			normalizations: []TargetNormalization{TargetNormalizations["ipv6"]},
+			recordType:     endpoint.RecordTypeAAAA,
+			desired:        endpoint.Targets{"2001:db8::1", "2001:db8::2"},
+			current:        endpoint.Targets{"2001:0db8:0:0:0:0:0:2", "2001:DB8::1"},
+		},
+		{
+			name:       "ipv6 textual form is significant by default",
+			recordType: endpoint.RecordTypeAAAA,
+			desired:    endpoint.Targets{"2001:db8::1"},
+			current:    endpoint.Targets{"2001:0db8:0:0:0:0:0:1"},
+			changed:    true,
+		},
+		{
+			name:           "different targets",
+			normalizations: []TargetNormalization{TargetNormalizations["case"], TargetNormalizations["trailing-dot"], TargetNormalizations["ipv6"]},
+			recordType:     endpoint.RecordTypeA,
+			desired:        endpoint.Targets{"1.2.3.4"},
+			current:        endpoint.Targets{"1.2.3.5"},
+			changed:        true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Plan{TargetNormalizations: tc.normalizations}
+			desired := &endpoint.Endpoint{DNSName: "foo.example.com", RecordType: tc.recordType, Targets: tc.desired}
+			current := &endpoint.Endpoint{DNSName: "foo.example.com", RecordType: tc.recordType, Targets: tc.current}
+			assert.Equal(t, tc.changed, p.targetChanged(desired, current))
+		})
+	}
+}
@@ -18,8 +18,10 @@ package plan
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	log "github.com/sirupsen/logrus"
@@ -35,6 +37,9 @@ type PropertyComparator func(name string, previous string, current string) bool
 type Plan struct {
 	// List of current records
 	Current []*endpoint.Endpoint
+	// Existing holds all the current records when Current only holds the ones being planned, e.g. in
+	// incremental synchronizations. Current is used instead when empty.
+	Existing []*endpoint.Endpoint
 	// List of desired records
 	Desired []*endpoint.Endpoint
 	// Policies under which the desired changes are calculated
@@ -50,6 +55,27 @@ type Plan struct {
 	ExcludeRecords []string
 	// OwnerID of records to manage
 	OwnerID string
+	// IgnoreTTL makes differences in TTL alone not trigger updates
+	IgnoreTTL bool
+	// MinTTL is the minimum TTL enforced by the provider. Desired TTLs below it
+	// are compared as if they were MinTTL.
+	MinTTL endpoint.TTL
+	// MetadataSensitive makes changes of the labels persisted by the registry,
+	// such as the resource label, trigger updates of owned records.
+	MetadataSensitive bool
+	// TargetNormalizations are applied to the targets of current and desired records before
+	// comparing them. DefaultTargetNormalizations are used when empty.
+	TargetNormalizations []TargetNormalization
+	// DeletionGracePeriod delays the deletion of owned records. Instead of being deleted, records
+	// which are no longer desired are tombstoned and only deleted once they stay undesired for
+	// this long.
+	DeletionGracePeriod time.Duration
+	// WildcardPolicy controls the creation of records shadowing wildcard records of the owner.
+	// Records are created silently when empty.
+	WildcardPolicy WildcardPolicy
+	// WeightProperty is the provider specific property holding the weights of weighted records, set
+	// for the DNS names with canary records. Canary weights are ignored when empty.
+	WeightProperty string
 }
 
 // Changes holds lists of actions to be executed by dns providers
@@ -125,34 +151,40 @@ func (t planTableRow) String() string {
 }
 
 func (t planTable) addCurrent(e *endpoint.Endpoint) {
-	key := t.newPlanKey(e)
-	t.rows[key].current = append(t.rows[key].current, e)
-	t.rows[key].records[e.RecordType].current = e
+	row, records := t.rowOf(e)
+	row.current = append(row.current, e)
+	records.current = e
 }
 
 func (t planTable) addCandidate(e *endpoint.Endpoint) {
-	key := t.newPlanKey(e)
-	t.rows[key].candidates = append(t.rows[key].candidates, e)
-	t.rows[key].records[e.RecordType].candidates = append(t.rows[key].records[e.RecordType].candidates, e)
+	row, records := t.rowOf(e)
+	row.candidates = append(row.candidates, e)
+	records.candidates = append(records.candidates, e)
 }
 
-func (t *planTable) newPlanKey(e *endpoint.Endpoint) planKey {
+// rowOf returns the row of the endpoint and its records of the endpoint type, creating them
+// if needed, with a single lookup of each.
+func (t planTable) rowOf(e *endpoint.Endpoint) (*planTableRow, *domainEndpoints) {
 	key := planKey{
 		dnsName:       normalizeDNSName(e.DNSName),
 		setIdentifier: e.SetIdentifier,
 	}
 
-	if _, ok := t.rows[key]; !ok {
-		t.rows[key] = &planTableRow{
+	row, ok := t.rows[key]
+	if !ok {
+		row = &planTableRow{
 			records: make(map[string]*domainEndpoints),
 		}
+		t.rows[key] = row
 	}
 
-	if _, ok := t.rows[key].records[e.RecordType]; !ok {
-		t.rows[key].records[e.RecordType] = &domainEndpoints{}
+	records, ok := row.records[e.RecordType]
+	if !ok {
+		records = &domainEndpoints{}
+		row.records[e.RecordType] = records
 	}
 
-	return key
+	return row, records
 }
 
 func (c *Changes) HasChanges() bool {
@@ -175,7 +207,12 @@ func (p *Plan) Calculate() *Plan {
 	for _, current := range filterRecordsForPlan(p.Current, p.DomainFilter, p.ManagedRecords, p.ExcludeRecords) {
 		t.addCurrent(current)
 	}
-	for _, desired := range filterRecordsForPlan(p.Desired, p.DomainFilter, p.ManagedRecords, p.ExcludeRecords) {
+	desiredRecords := p.Desired
+	if p.deleteOnly() {
+		desiredRecords = nil
+	}
+	desiredRecords = weightCanaries(desiredRecords, p.WeightProperty)
+	for _, desired := range filterRecordsForPlan(desiredRecords, p.DomainFilter, p.ManagedRecords, p.ExcludeRecords) {
 		t.addCandidate(desired)
 	}
 
@@ -222,8 +259,9 @@ func (p *Plan) Calculate() *Plan {
 				if records.current != nil && len(records.candidates) > 0 {
 					update := t.resolver.ResolveUpdate(records.current, records.candidates)
 
-					if shouldUpdateTTL(update, records.current) || targetChanged(update, records.current) || p.shouldUpdateProviderSpecific(update, records.current) {
+					if isTombstoned(records.current) || p.shouldUpdateTTL(update, records.current) || p.targetChanged(update, records.current) || p.shouldUpdateProviderSpecific(update, records.current) || shouldUpdateProvenance(update, records.current) || p.shouldUpdateMetadata(update, records.current) {
 						inheritOwner(records.current, update)
+						recordPreviousTargets(records.current, update, p.targetChanged(update, records.current))
 						changes.UpdateNew = append(changes.UpdateNew, update)
 						changes.UpdateOld = append(changes.UpdateOld, records.current)
 					}
@@ -257,6 +295,12 @@ func (p *Plan) Calculate() *Plan {
 		changes.UpdateNew = endpoint.FilterEndpointsByOwnerID(p.OwnerID, changes.UpdateNew)
 	}
 
+	changes.Create = p.applyWildcardPolicy(changes.Create)
+
+	if p.DeletionGracePeriod > 0 && !p.deleteOnly() {
+		changes = p.tombstoneDeletes(changes, time.Now())
+	}
+
 	plan := &Plan{
 		Current:        p.Current,
 		Desired:        p.Desired,
@@ -267,6 +311,16 @@ func (p *Plan) Calculate() *Plan {
 	return plan
 }
 
+// deleteOnly returns true when the plan decommissions the records of the owner.
+func (p *Plan) deleteOnly() bool {
+	for _, pol := range p.Policies {
+		if _, ok := pol.(*DeleteOnlyPolicy); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func inheritOwner(from, to *endpoint.Endpoint) {
 	if to.Labels == nil {
 		to.Labels = map[string]string{}
@@ -277,24 +331,147 @@ func inheritOwner(from, to *endpoint.Endpoint) {
 	to.Labels[endpoint.OwnerLabelKey] = from.Labels[endpoint.OwnerLabelKey]
 }
 
-func targetChanged(desired, current *endpoint.Endpoint) bool {
-	return !desired.Targets.Same(current.Targets)
+// recordPreviousTargets keeps the targets of a blue/green record before its last swap in its labels, so that
+// the registry records them for rolling back. The targets are swapped by a single update of the record.
+func recordPreviousTargets(current, update *endpoint.Endpoint, targetsChanged bool) {
+	if update.Labels[endpoint.ActiveTargetLabelKey] == "" {
+		return
+	}
+	previous := current.Labels[endpoint.PreviousTargetsLabelKey]
+	if targetsChanged {
+		previous = strings.Join(current.Targets, ";")
+	}
+	if previous != "" {
+		update.Labels[endpoint.PreviousTargetsLabelKey] = previous
+	}
+}
+
+// tombstoneDeletes replaces the deletion of owned records by an update adding a tombstone label,
+// and only keeps the deletions of records tombstoned for longer than the deletion grace period.
+// Records without owner are deleted right away, since their labels are not persisted by the registry.
+func (p *Plan) tombstoneDeletes(changes *Changes, now time.Time) *Changes {
+	deletes := make([]*endpoint.Endpoint, 0, len(changes.Delete))
+	for _, current := range changes.Delete {
+		if current.Labels[endpoint.OwnerLabelKey] == "" {
+			deletes = append(deletes, current)
+			continue
+		}
+		tombstoned, ok := tombstoneTime(current)
+		if !ok {
+			log.Infof("Tombstoning record %s %s, it will be deleted in %s if it stays undesired", current.DNSName, current.RecordType, p.DeletionGracePeriod)
+			tombstone := current.DeepCopy()
+			tombstone.Labels[endpoint.TombstoneLabelKey] = strconv.FormatInt(now.Unix(), 10)
+			changes.UpdateOld = append(changes.UpdateOld, current)
+			changes.UpdateNew = append(changes.UpdateNew, tombstone)
+			continue
+		}
+		if now.Sub(tombstoned) >= p.DeletionGracePeriod {
+			deletes = append(deletes, current)
+		} else {
+			log.Debugf("Keeping tombstoned record %s %s until %s", current.DNSName, current.RecordType, tombstoned.Add(p.DeletionGracePeriod))
+		}
+	}
+	changes.Delete = deletes
+	return changes
+}
+
+// isTombstoned returns true when the record is marked for deletion. Such records are updated
+// when they are desired again, in order to remove the mark.
+func isTombstoned(e *endpoint.Endpoint) bool {
+	_, ok := e.Labels[endpoint.TombstoneLabelKey]
+	return ok
+}
+
+// tombstoneTime returns the time the record was tombstoned at. An unparsable tombstone label
+// is treated as an expired one.
+func tombstoneTime(e *endpoint.Endpoint) (time.Time, bool) {
+	value, ok := e.Labels[endpoint.TombstoneLabelKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Warnf("Invalid tombstone label %q on record %s %s", value, e.DNSName, e.RecordType)
+		return time.Time{}, true
+	}
+	return time.Unix(seconds, 0), true
+}
+
+func (p *Plan) targetChanged(desired, current *endpoint.Endpoint) bool {
+	if len(desired.Targets) != len(current.Targets) {
+		return true
+	}
+	normalizations := p.TargetNormalizations
+	if len(normalizations) == 0 {
+		normalizations = DefaultTargetNormalizations
+	}
+	return !slices.Equal(
+		NormalizeTargets(normalizations, desired.RecordType, desired.Targets),
+		NormalizeTargets(normalizations, current.RecordType, current.Targets),
+	)
+}
+
+func (p *Plan) shouldUpdateTTL(desired, current *endpoint.Endpoint) bool {
+	if p.IgnoreTTL || !desired.RecordTTL.IsConfigured() {
+		return false
+	}
+	return ClampTTL(desired.RecordTTL, p.MinTTL) != current.RecordTTL
+}
+
+// ClampTTL returns the TTL a provider enforcing minTTL sets for a record with the given TTL.
+func ClampTTL(ttl, minTTL endpoint.TTL) endpoint.TTL {
+	if ttl < minTTL {
+		return minTTL
+	}
+	return ttl
+}
+
+// shouldUpdateProvenance returns true when the cluster or commit label of the desired endpoint
+// differs from the one stored in the registry. Records without owner are not checked, since
+// their labels are not persisted by the registry.
+func shouldUpdateProvenance(desired, current *endpoint.Endpoint) bool {
+	if current.Labels[endpoint.OwnerLabelKey] == "" {
+		return false
+	}
+	for _, key := range []string{endpoint.ClusterLabelKey, endpoint.CommitLabelKey} {
+		if value := desired.Labels[key]; value != "" && value != current.Labels[key] {
+			return true
+		}
+	}
+	return false
 }
 
-func shouldUpdateTTL(desired, current *endpoint.Endpoint) bool {
-	if !desired.RecordTTL.IsConfigured() {
+// shouldUpdateMetadata returns true in metadata-sensitive mode when a label of the desired endpoint
+// differs from the one stored in the registry. As for provenance labels, only owned records are checked.
+func (p *Plan) shouldUpdateMetadata(desired, current *endpoint.Endpoint) bool {
+	if !p.MetadataSensitive || current.Labels[endpoint.OwnerLabelKey] == "" {
 		return false
 	}
-	return desired.RecordTTL != current.RecordTTL
+	for key, value := range desired.Labels {
+		if key == endpoint.OwnerLabelKey {
+			continue
+		}
+		if current.Labels[key] != value {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *Plan) shouldUpdateProviderSpecific(desired, current *endpoint.Endpoint) bool {
 	desiredProperties := map[string]endpoint.ProviderSpecificProperty{}
 
 	for _, d := range desired.ProviderSpecific {
+		// the zone a record is pinned to only selects where it is written, records read from providers don't have it
+		if d.Name == endpoint.ProviderSpecificZoneID {
+			continue
+		}
 		desiredProperties[d.Name] = d
 	}
 	for _, c := range current.ProviderSpecific {
+		if c.Name == endpoint.ProviderSpecificZoneID {
+			continue
+		}
 		if d, ok := desiredProperties[c.Name]; ok {
 			if c.Value != d.Value {
 				return true
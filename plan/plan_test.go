@@ -17,7 +17,10 @@ limitations under the License.
 package plan
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -333,6 +336,114 @@ func (suite *PlanTestSuite) TestSyncSecondRoundWithTTLChange() {
 	validateEntries(suite.T(), changes.Delete, expectedDelete)
 }
 
+func (suite *PlanTestSuite) TestSyncSecondRoundWithIgnoredTTLChange() {
+	current := []*endpoint.Endpoint{suite.bar127A}
+	desired := []*endpoint.Endpoint{suite.bar127AWithTTL}
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+		IgnoreTTL:      true,
+	}
+
+	suite.False(p.Calculate().Changes.HasChanges())
+}
+
+func (suite *PlanTestSuite) TestSyncSecondRoundWithTTLBelowMinimum() {
+	current := []*endpoint.Endpoint{{
+		DNSName:    "bar",
+		Targets:    endpoint.Targets{"127.0.0.1"},
+		RecordType: "A",
+		RecordTTL:  600,
+	}}
+	desired := []*endpoint.Endpoint{suite.bar127AWithTTL}
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+		MinTTL:         600,
+	}
+	suite.False(p.Calculate().Changes.HasChanges(), "TTL raised to the provider minimum")
+
+	p.MinTTL = 60
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.UpdateOld, current)
+	validateEntries(suite.T(), changes.UpdateNew, desired)
+}
+
+func (suite *PlanTestSuite) TestDeleteOnly() {
+	current := []*endpoint.Endpoint{suite.fooV1Cname, suite.bar127A}
+	desired := []*endpoint.Endpoint{suite.fooV2Cname, suite.bar127A, suite.bar192A}
+	expectedDelete := []*endpoint.Endpoint{suite.fooV1Cname, suite.bar127A}
+
+	p := &Plan{
+		Policies:       []Policy{&DeleteOnlyPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.UpdateNew, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.UpdateOld, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.Delete, expectedDelete)
+}
+
+func (suite *PlanTestSuite) TestSyncDeletionGracePeriod() {
+	owned := func(labels map[string]string) *endpoint.Endpoint {
+		e := &endpoint.Endpoint{
+			DNSName:    "foo",
+			Targets:    endpoint.Targets{"1.2.3.4"},
+			RecordType: endpoint.RecordTypeA,
+			Labels:     endpoint.Labels{endpoint.OwnerLabelKey: "pwner"},
+		}
+		for k, v := range labels {
+			e.Labels[k] = v
+		}
+		return e
+	}
+	unowned := &endpoint.Endpoint{
+		DNSName:    "bar",
+		Targets:    endpoint.Targets{"1.2.3.4"},
+		RecordType: endpoint.RecordTypeA,
+	}
+
+	p := &Plan{
+		Policies:            []Policy{&SyncPolicy{}},
+		Current:             []*endpoint.Endpoint{owned(nil), unowned},
+		ManagedRecords:      []string{endpoint.RecordTypeA},
+		DeletionGracePeriod: time.Hour,
+	}
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Delete, []*endpoint.Endpoint{unowned})
+	validateEntries(suite.T(), changes.UpdateOld, []*endpoint.Endpoint{owned(nil)})
+	suite.Require().Len(changes.UpdateNew, 1)
+	suite.Contains(changes.UpdateNew[0].Labels, endpoint.TombstoneLabelKey, "record should be tombstoned")
+	suite.NotContains(p.Current[0].Labels, endpoint.TombstoneLabelKey, "current record should not be modified")
+
+	recent := owned(map[string]string{endpoint.TombstoneLabelKey: strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)})
+	p.Current = []*endpoint.Endpoint{recent}
+	suite.False(p.Calculate().Changes.HasChanges(), "recently tombstoned record should be kept")
+
+	expired := owned(map[string]string{endpoint.TombstoneLabelKey: strconv.FormatInt(time.Now().Add(-2*time.Hour).Unix(), 10)})
+	p.Current = []*endpoint.Endpoint{expired}
+	changes = p.Calculate().Changes
+	validateEntries(suite.T(), changes.Delete, []*endpoint.Endpoint{expired})
+	suite.Empty(changes.UpdateNew)
+
+	p.Current = []*endpoint.Endpoint{recent}
+	p.Desired = []*endpoint.Endpoint{owned(nil)}
+	changes = p.Calculate().Changes
+	validateEntries(suite.T(), changes.UpdateOld, []*endpoint.Endpoint{recent})
+	suite.Require().Len(changes.UpdateNew, 1)
+	suite.NotContains(changes.UpdateNew[0].Labels, endpoint.TombstoneLabelKey, "tombstone should be removed when desired again")
+}
+
 func (suite *PlanTestSuite) TestSyncSecondRoundWithProviderSpecificChange() {
 	current := []*endpoint.Endpoint{suite.bar127AWithProviderSpecificTrue}
 	desired := []*endpoint.Endpoint{suite.bar127AWithProviderSpecificFalse}
@@ -377,6 +488,23 @@ func (suite *PlanTestSuite) TestSyncSecondRoundWithProviderSpecificRemoval() {
 	validateEntries(suite.T(), changes.Delete, expectedDelete)
 }
 
+func (suite *PlanTestSuite) TestSyncSecondRoundWithPinnedZone() {
+	current := []*endpoint.Endpoint{suite.bar127AWithProviderSpecificTrue}
+	pinned := suite.bar127AWithProviderSpecificTrue.DeepCopy()
+	pinned.SetProviderSpecificProperty(endpoint.ProviderSpecificZoneID, "Z0123456789")
+	desired := []*endpoint.Endpoint{pinned}
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+	}
+
+	changes := p.Calculate().Changes
+	suite.False(changes.HasChanges())
+}
+
 func (suite *PlanTestSuite) TestSyncSecondRoundWithProviderSpecificAddition() {
 	current := []*endpoint.Endpoint{suite.bar127AWithProviderSpecificUnset}
 	desired := []*endpoint.Endpoint{suite.bar127AWithProviderSpecificTrue}
@@ -431,6 +559,106 @@ func (suite *PlanTestSuite) TestSyncSecondRoundWithOwnerInherited() {
 	validateEntries(suite.T(), changes.Delete, expectedDelete)
 }
 
+func (suite *PlanTestSuite) TestSyncSecondRoundWithCommitChange() {
+	current := []*endpoint.Endpoint{{
+		DNSName:    "foo",
+		Targets:    endpoint.Targets{"v1"},
+		RecordType: "CNAME",
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey: "ingress/default/foo-v1",
+			endpoint.OwnerLabelKey:    "pwner",
+			endpoint.CommitLabelKey:   "1f3a2b4",
+		},
+	}}
+	desired := []*endpoint.Endpoint{{
+		DNSName:    "foo",
+		Targets:    endpoint.Targets{"v1"},
+		RecordType: "CNAME",
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey: "ingress/default/foo-v1",
+			endpoint.CommitLabelKey:   "9c8d7e6",
+		},
+	}}
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+	}
+
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.Create, []*endpoint.Endpoint{})
+	validateEntries(suite.T(), changes.UpdateOld, current)
+	validateEntries(suite.T(), changes.UpdateNew, desired)
+	validateEntries(suite.T(), changes.Delete, []*endpoint.Endpoint{})
+	suite.Equal("pwner", changes.UpdateNew[0].Labels[endpoint.OwnerLabelKey])
+}
+
+func (suite *PlanTestSuite) TestProvenanceIgnoredWithoutOwner() {
+	current := []*endpoint.Endpoint{{
+		DNSName:    "foo",
+		Targets:    endpoint.Targets{"v1"},
+		RecordType: "CNAME",
+	}}
+	desired := []*endpoint.Endpoint{{
+		DNSName:    "foo",
+		Targets:    endpoint.Targets{"v1"},
+		RecordType: "CNAME",
+		Labels: map[string]string{
+			endpoint.ClusterLabelKey: "prod-eu-1",
+		},
+	}}
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+	}
+
+	changes := p.Calculate().Changes
+	suite.Empty(changes.UpdateNew)
+	suite.Empty(changes.UpdateOld)
+}
+
+func (suite *PlanTestSuite) TestSyncSecondRoundMetadataSensitive() {
+	current := []*endpoint.Endpoint{{
+		DNSName:    "foo",
+		Targets:    endpoint.Targets{"v1"},
+		RecordType: "CNAME",
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey: "ingress/default/foo-v1",
+			endpoint.OwnerLabelKey:    "pwner",
+		},
+	}}
+	desired := []*endpoint.Endpoint{{
+		DNSName:    "foo",
+		Targets:    endpoint.Targets{"v1"},
+		RecordType: "CNAME",
+		Labels: map[string]string{
+			endpoint.ResourceLabelKey: "ingress/other/foo-v1",
+		},
+	}}
+
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+	}
+	suite.False(p.Calculate().Changes.HasChanges(), "labels are ignored by default")
+
+	p.MetadataSensitive = true
+	changes := p.Calculate().Changes
+	validateEntries(suite.T(), changes.UpdateOld, current)
+	validateEntries(suite.T(), changes.UpdateNew, desired)
+	suite.Equal("ingress/other/foo-v1", changes.UpdateNew[0].Labels[endpoint.ResourceLabelKey])
+
+	current[0].Labels = map[string]string{endpoint.ResourceLabelKey: "ingress/default/foo-v1"}
+	suite.False(p.Calculate().Changes.HasChanges(), "labels of unowned records are ignored")
+}
+
 func (suite *PlanTestSuite) TestIdempotency() {
 	current := []*endpoint.Endpoint{suite.fooV1Cname, suite.fooV2Cname}
 	desired := []*endpoint.Endpoint{suite.fooV1Cname, suite.fooV2Cname}
@@ -1085,3 +1313,85 @@ func TestShouldUpdateProviderSpecific(tt *testing.T) {
 		})
 	}
 }
+
+// BenchmarkCalculate plans 100k owned records spread over 200 zones selected by domain filters,
+// a tenth of which change their target.
+func TestCalculateRecordsPreviousTargets(t *testing.T) {
+	blueGreen := func(active string, labels endpoint.Labels, targets ...string) *endpoint.Endpoint {
+		ep := endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, targets...)
+		ep.Labels = labels
+		ep.Labels[endpoint.OwnerLabelKey] = "owner"
+		ep.Labels[endpoint.ActiveTargetLabelKey] = active
+		return ep
+	}
+	calculate := func(current, desired *endpoint.Endpoint) *Changes {
+		return (&Plan{
+			Current:        []*endpoint.Endpoint{current},
+			Desired:        []*endpoint.Endpoint{desired},
+			Policies:       []Policy{&SyncPolicy{}},
+			ManagedRecords: []string{endpoint.RecordTypeA},
+			OwnerID:        "owner",
+		}).Calculate().Changes
+	}
+
+	// the targets are swapped by a single update recording the previous targets
+	changes := calculate(blueGreen("blue", endpoint.Labels{}, "192.0.2.1", "192.0.2.2"), blueGreen("green", endpoint.Labels{}, "198.51.100.1"))
+	assert.Empty(t, changes.Create)
+	assert.Empty(t, changes.Delete)
+	assert.Len(t, changes.UpdateNew, 1)
+	assert.Equal(t, "192.0.2.1;192.0.2.2", changes.UpdateNew[0].Labels[endpoint.PreviousTargetsLabelKey])
+
+	// the previous targets are kept by the updates which don't swap the targets
+	current := blueGreen("green", endpoint.Labels{endpoint.PreviousTargetsLabelKey: "192.0.2.1;192.0.2.2"}, "198.51.100.1")
+	desired := blueGreen("green", endpoint.Labels{}, "198.51.100.1")
+	desired.RecordTTL = 60
+	changes = calculate(current, desired)
+	assert.Len(t, changes.UpdateNew, 1)
+	assert.Equal(t, "192.0.2.1;192.0.2.2", changes.UpdateNew[0].Labels[endpoint.PreviousTargetsLabelKey])
+
+	// records which are not blue/green don't record their previous targets
+	current = endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "192.0.2.1")
+	current.Labels[endpoint.OwnerLabelKey] = "owner"
+	changes = calculate(current, endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "198.51.100.1"))
+	assert.Len(t, changes.UpdateNew, 1)
+	assert.NotContains(t, changes.UpdateNew[0].Labels, endpoint.PreviousTargetsLabelKey)
+}
+
+func BenchmarkCalculate(b *testing.B) {
+	const records, zones = 100000, 200
+	filters := make([]string, 0, zones)
+	for i := 0; i < zones; i++ {
+		filters = append(filters, fmt.Sprintf("zone-%d.example.org", i))
+	}
+	domainFilter := endpoint.NewDomainFilter(filters)
+
+	current := make([]*endpoint.Endpoint, 0, records)
+	desired := make([]*endpoint.Endpoint, 0, records)
+	for i := 0; i < records; i++ {
+		name := fmt.Sprintf("host-%d.zone-%d.example.org", i, i%zones)
+		target := fmt.Sprintf("10.%d.%d.%d", i>>16&255, i>>8&255, i&255)
+		owned := endpoint.NewEndpoint(name, endpoint.RecordTypeA, target)
+		owned.Labels = endpoint.Labels{endpoint.OwnerLabelKey: "owner"}
+		current = append(current, owned)
+		if i%10 == 0 {
+			target = "192.0.2.1"
+		}
+		desired = append(desired, endpoint.NewEndpoint(name, endpoint.RecordTypeA, target))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := &Plan{
+			Current:        current,
+			Desired:        desired,
+			Policies:       []Policy{&SyncPolicy{}},
+			DomainFilter:   endpoint.MatchAllDomainFilters{&domainFilter},
+			ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME},
+			OwnerID:        "owner",
+		}
+		changes := p.Calculate().Changes
+		if len(changes.UpdateNew) != records/10 {
+			b.Fatalf("expected %d updates, got %d", records/10, len(changes.UpdateNew))
+		}
+	}
+}
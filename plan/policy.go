@@ -26,6 +26,7 @@ var Policies = map[string]Policy{
 	"sync":        &SyncPolicy{},
 	"upsert-only": &UpsertOnlyPolicy{},
 	"create-only": &CreateOnlyPolicy{},
+	"delete-only": &DeleteOnlyPolicy{},
 }
 
 // SyncPolicy allows for full synchronization of DNS records.
@@ -57,3 +58,15 @@ func (p *CreateOnlyPolicy) Apply(changes *Changes) *Changes {
 		Create: changes.Create,
 	}
 }
+
+// DeleteOnlyPolicy allows only deleting DNS records. It is meant to decommission
+// a deployment: the plan ignores the desired records under this policy, so that
+// all the records owned by the owner ID are deleted.
+type DeleteOnlyPolicy struct{}
+
+// Apply applies the delete-only policy which strips out creations and updates.
+func (p *DeleteOnlyPolicy) Apply(changes *Changes) *Changes {
+	return &Changes{
+		Delete: changes.Delete,
+	}
+}
@@ -58,6 +58,12 @@ func TestApply(t *testing.T) {
 			&Changes{Create: baz, UpdateOld: fooV1, UpdateNew: fooV2, Delete: bar},
 			&Changes{Create: baz, UpdateOld: empty, UpdateNew: empty, Delete: empty},
 		},
+		{
+			// DeleteOnlyPolicy clears the list of creations and updates.
+			&DeleteOnlyPolicy{},
+			&Changes{Create: baz, UpdateOld: fooV1, UpdateNew: fooV2, Delete: bar},
+			&Changes{Create: empty, UpdateOld: empty, UpdateNew: empty, Delete: bar},
+		},
 	} {
 		// apply policy
 		changes := tc.policy.Apply(tc.changes)
@@ -75,6 +81,7 @@ func TestPolicies(t *testing.T) {
 	validatePolicy(t, Policies["sync"], &SyncPolicy{})
 	validatePolicy(t, Policies["upsert-only"], &UpsertOnlyPolicy{})
 	validatePolicy(t, Policies["create-only"], &CreateOnlyPolicy{})
+	validatePolicy(t, Policies["delete-only"], &DeleteOnlyPolicy{})
 }
 
 // validatePolicy validates that a given policy is of the given type.
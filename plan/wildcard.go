@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// WildcardPolicy controls the creation of records which shadow a wildcard record of the owner.
+// A wildcard record, e.g. *.example.org, answers for the names below it which have no records
+// of their own. Once any record is created for such a name, e.g. a.example.org, the wildcard
+// no longer answers for it, whatever the record type.
+type WildcardPolicy string
+
+const (
+	// WildcardPolicyAllow creates records shadowing wildcards silently.
+	WildcardPolicyAllow WildcardPolicy = "allow"
+	// WildcardPolicyWarn creates records shadowing wildcards, logging a warning.
+	WildcardPolicyWarn WildcardPolicy = "warn"
+	// WildcardPolicyBlock does not create records shadowing wildcards.
+	WildcardPolicyBlock WildcardPolicy = "block"
+)
+
+// WildcardPolicies lists the supported wildcard policies.
+var WildcardPolicies = []string{string(WildcardPolicyAllow), string(WildcardPolicyWarn), string(WildcardPolicyBlock)}
+
+// applyWildcardPolicy warns about or drops the creations of records which would shadow an owned wildcard record.
+func (p *Plan) applyWildcardPolicy(creates []*endpoint.Endpoint) []*endpoint.Endpoint {
+	if p.WildcardPolicy == "" || p.WildcardPolicy == WildcardPolicyAllow {
+		return creates
+	}
+
+	// nodes holds the names which exist in the zone, including the ones only having records below them
+	nodes := map[string]bool{}
+	wildcards := map[string]bool{}
+	addNodes := func(name string) {
+		for ; name != ""; name = parentName(name) {
+			nodes[name] = true
+		}
+	}
+	existing := p.Existing
+	if existing == nil {
+		existing = p.Current
+	}
+	for _, r := range existing {
+		name := normalizeDNSName(r.DNSName)
+		addNodes(name)
+		if strings.HasPrefix(name, "*.") && (p.OwnerID == "" || r.IsOwnedBy(p.OwnerID)) {
+			wildcards[parentName(name)] = true
+		}
+	}
+	for _, r := range creates {
+		name := normalizeDNSName(r.DNSName)
+		if strings.HasPrefix(name, "*.") {
+			addNodes(name)
+			wildcards[parentName(name)] = true
+		}
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(creates))
+	for _, r := range creates {
+		wildcard := shadowedWildcard(normalizeDNSName(r.DNSName), nodes, wildcards)
+		if wildcard == "" {
+			result = append(result, r)
+			continue
+		}
+		if p.WildcardPolicy == WildcardPolicyBlock {
+			log.Warnf("Not creating %s %s, which would shadow the wildcard record %s", r.DNSName, r.RecordType, wildcard)
+			continue
+		}
+		log.Warnf("Creating %s %s, which shadows the wildcard record %s", r.DNSName, r.RecordType, wildcard)
+		result = append(result, r)
+	}
+	return result
+}
+
+// shadowedWildcard returns the wildcard record answering for name, which would no longer do so
+// once name has records, or an empty string. As in RFC 4592, the wildcard answering for a name is
+// the one below its closest existing ancestor.
+func shadowedWildcard(name string, nodes, wildcards map[string]bool) string {
+	if strings.HasPrefix(name, "*.") || nodes[name] {
+		return ""
+	}
+	for ancestor := parentName(name); ancestor != ""; ancestor = parentName(ancestor) {
+		if nodes[ancestor] {
+			if wildcards[ancestor] {
+				return "*." + strings.TrimSuffix(ancestor, ".")
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+func parentName(name string) string {
+	_, parent, _ := strings.Cut(name, ".")
+	return parent
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func ownedEndpoint(dnsName, recordType, owner string, targets ...string) *endpoint.Endpoint {
+	ep := endpoint.NewEndpoint(dnsName, recordType, targets...)
+	ep.Labels[endpoint.OwnerLabelKey] = owner
+	return ep
+}
+
+func createdNames(p *Plan) []string {
+	names := []string{}
+	for _, ep := range p.Changes.Create {
+		names = append(names, ep.DNSName)
+	}
+	return names
+}
+
+func TestWildcardPolicy(t *testing.T) {
+	current := []*endpoint.Endpoint{
+		ownedEndpoint("*.apps.example.org", endpoint.RecordTypeA, "owner", "1.1.1.1"),
+		ownedEndpoint("*.other.example.org", endpoint.RecordTypeA, "other", "2.2.2.2"),
+		ownedEndpoint("sub.apps.example.org", endpoint.RecordTypeA, "owner", "3.3.3.3"),
+	}
+	desired := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("*.apps.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("sub.apps.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+		// shadows *.apps.example.org
+		endpoint.NewEndpoint("app.apps.example.org", endpoint.RecordTypeA, "4.4.4.4"),
+		// answered by *.apps.example.org, shadowed by the name below sub.apps.example.org
+		endpoint.NewEndpoint("deep.sub.apps.example.org", endpoint.RecordTypeA, "5.5.5.5"),
+		// shadows the wildcard of another owner
+		endpoint.NewEndpoint("app.other.example.org", endpoint.RecordTypeA, "6.6.6.6"),
+		// shadows a wildcard created at the same time
+		endpoint.NewEndpoint("*.new.example.org", endpoint.RecordTypeA, "7.7.7.7"),
+		endpoint.NewEndpoint("app.new.example.org", endpoint.RecordTypeA, "8.8.8.8"),
+		// not below a wildcard
+		endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "9.9.9.9"),
+	}
+
+	for _, tc := range []struct {
+		policy   WildcardPolicy
+		expected []string
+	}{
+		{"", []string{"app.apps.example.org", "deep.sub.apps.example.org", "app.other.example.org", "*.new.example.org", "app.new.example.org", "app.example.org"}},
+		{WildcardPolicyAllow, []string{"app.apps.example.org", "deep.sub.apps.example.org", "app.other.example.org", "*.new.example.org", "app.new.example.org", "app.example.org"}},
+		{WildcardPolicyWarn, []string{"app.apps.example.org", "deep.sub.apps.example.org", "app.other.example.org", "*.new.example.org", "app.new.example.org", "app.example.org"}},
+		{WildcardPolicyBlock, []string{"deep.sub.apps.example.org", "app.other.example.org", "*.new.example.org", "app.example.org"}},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			p := &Plan{
+				Policies:       []Policy{&SyncPolicy{}},
+				Current:        current,
+				Desired:        desired,
+				ManagedRecords: []string{endpoint.RecordTypeA},
+				OwnerID:        "owner",
+				WildcardPolicy: tc.policy,
+			}
+			assert.ElementsMatch(t, tc.expected, createdNames(p.Calculate()))
+		})
+	}
+}
+
+func TestWildcardPolicyExisting(t *testing.T) {
+	// the wildcard record is not planned, e.g. in an incremental synchronization
+	existing := []*endpoint.Endpoint{
+		ownedEndpoint("*.apps.example.org", endpoint.RecordTypeA, "owner", "1.1.1.1"),
+	}
+	p := &Plan{
+		Policies:       []Policy{&SyncPolicy{}},
+		Existing:       existing,
+		Desired:        []*endpoint.Endpoint{endpoint.NewEndpoint("app.apps.example.org", endpoint.RecordTypeA, "2.2.2.2")},
+		ManagedRecords: []string{endpoint.RecordTypeA},
+		OwnerID:        "owner",
+		WildcardPolicy: WildcardPolicyBlock,
+	}
+	assert.Empty(t, createdNames(p.Calculate()))
+}
@@ -19,9 +19,11 @@ package aws
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -29,6 +31,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/plan"
@@ -200,12 +203,20 @@ type Route53API interface {
 	CreateHostedZoneWithContext(ctx context.Context, input *route53.CreateHostedZoneInput, opts ...request.Option) (*route53.CreateHostedZoneOutput, error)
 	ListHostedZonesPagesWithContext(ctx context.Context, input *route53.ListHostedZonesInput, fn func(resp *route53.ListHostedZonesOutput, lastPage bool) (shouldContinue bool), opts ...request.Option) error
 	ListTagsForResourceWithContext(ctx context.Context, input *route53.ListTagsForResourceInput, opts ...request.Option) (*route53.ListTagsForResourceOutput, error)
+	ChangeTagsForResourceWithContext(ctx context.Context, input *route53.ChangeTagsForResourceInput, opts ...request.Option) (*route53.ChangeTagsForResourceOutput, error)
+	GetHostedZoneWithContext(ctx context.Context, input *route53.GetHostedZoneInput, opts ...request.Option) (*route53.GetHostedZoneOutput, error)
+	GetDNSSECWithContext(ctx context.Context, input *route53.GetDNSSECInput, opts ...request.Option) (*route53.GetDNSSECOutput, error)
+	ListHealthChecksPagesWithContext(ctx context.Context, input *route53.ListHealthChecksInput, fn func(resp *route53.ListHealthChecksOutput, lastPage bool) (shouldContinue bool), opts ...request.Option) error
+	CreateHealthCheckWithContext(ctx context.Context, input *route53.CreateHealthCheckInput, opts ...request.Option) (*route53.CreateHealthCheckOutput, error)
+	DeleteHealthCheckWithContext(ctx context.Context, input *route53.DeleteHealthCheckInput, opts ...request.Option) (*route53.DeleteHealthCheckOutput, error)
 }
 
 // wrapper to handle ownership relation throughout the provider implementation
 type Route53Change struct {
 	route53.Change
 	OwnedRecord string
+	// ZoneID is the ID of the zone the record is pinned to, if any
+	ZoneID string
 }
 
 type Route53Changes []*Route53Change
@@ -229,6 +240,7 @@ type AWSProvider struct {
 	provider.BaseProvider
 	client               Route53API
 	dryRun               bool
+	validateDryRun       bool
 	batchChangeSize      int
 	batchChangeInterval  time.Duration
 	evaluateTargetHealth bool
@@ -244,6 +256,13 @@ type AWSProvider struct {
 	zonesCache    *zonesListCache
 	// queue for collecting changes to submit them in the next iteration, but after all other changes
 	failedChangesQueue map[string]Route53Changes
+	failedChangesMu    sync.Mutex
+	// number of hosted zones listed and changed concurrently
+	zoneWorkers int
+	// limits the change batches submitted by all the zone workers, nil if unlimited
+	changeLimiter *rate.Limiter
+	// health checks created by ExternalDNS, nil unless enabled
+	healthChecks *healthChecks
 }
 
 // AWSConfig contains configuration to create a new AWS provider.
@@ -257,7 +276,11 @@ type AWSConfig struct {
 	EvaluateTargetHealth bool
 	PreferCNAME          bool
 	DryRun               bool
+	ValidateDryRun       bool
 	ZoneCacheDuration    time.Duration
+	ZoneWorkers          int
+	ZoneChangeRate       float64
+	HealthChecks         bool
 }
 
 // NewAWSProvider initializes a new AWS Route53 based Provider.
@@ -273,8 +296,14 @@ func NewAWSProvider(awsConfig AWSConfig, client Route53API) (*AWSProvider, error
 		evaluateTargetHealth: awsConfig.EvaluateTargetHealth,
 		preferCNAME:          awsConfig.PreferCNAME,
 		dryRun:               awsConfig.DryRun,
+		validateDryRun:       awsConfig.ValidateDryRun,
 		zonesCache:           &zonesListCache{duration: awsConfig.ZoneCacheDuration},
 		failedChangesQueue:   make(map[string]Route53Changes),
+		zoneWorkers:          awsConfig.ZoneWorkers,
+		changeLimiter:        provider.NewZoneChangeLimiter(awsConfig.ZoneChangeRate),
+	}
+	if awsConfig.HealthChecks {
+		provider.healthChecks = newHealthChecks()
 	}
 
 	return provider, nil
@@ -343,6 +372,25 @@ func (p *AWSProvider) Zones(ctx context.Context) (map[string]*route53.HostedZone
 	return zones, nil
 }
 
+// Preflight verifies that the records of all hosted zones can be changed.
+func (p *AWSProvider) Preflight(ctx context.Context) error {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list zones")
+	}
+	// private and public zones may share their name, changes are applied to all of them
+	seen := make(map[string]bool, len(zones))
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		name := aws.StringValue(zone.Name)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return provider.VerifyZonesWritable(ctx, p, names)
+}
+
 // wildcardUnescape converts \\052.abc back to *.abc
 // Route53 stores wildcards escaped: http://docs.aws.amazon.com/Route53/latest/DeveloperGuide/DomainNameFormat.html?shortFooter=true#domain-name-format-asterisk
 func wildcardUnescape(s string) string {
@@ -360,7 +408,94 @@ func (p *AWSProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoi
 }
 
 func (p *AWSProvider) records(ctx context.Context, zones map[string]*route53.HostedZone) ([]*endpoint.Endpoint, error) {
+	if p.healthChecks != nil {
+		if err := p.healthChecks.refresh(ctx, p.client); err != nil {
+			return nil, err
+		}
+	}
+
+	var mu sync.Mutex
+	endpointsByZone := make(map[string][]*endpoint.Endpoint, len(zones))
+	err := provider.ForEachZone(ctx, sortedZoneIDs(zones), p.zoneWorkers, func(ctx context.Context, id string) error {
+		endpoints, err := p.zoneRecords(ctx, zones[id])
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		endpointsByZone[id] = endpoints
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]*endpoint.Endpoint, 0)
+	for _, id := range sortedZoneIDs(zones) {
+		endpoints = append(endpoints, endpointsByZone[id]...)
+	}
+	if p.healthChecks != nil {
+		p.healthChecks.collect(ctx, p.client, zones)
+	}
+	return endpoints, nil
+}
+
+// RecordsIter calls fn for the records of the hosted zones one page of records after the other.
+// Up to zoneWorkers zones are listed concurrently, fn being called for the records of one zone at a time.
+func (p *AWSProvider) RecordsIter(ctx context.Context, fn func(*endpoint.Endpoint) error) error {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return errors.Wrap(err, "records retrieval failed")
+	}
+	if p.healthChecks != nil {
+		if err := p.healthChecks.refresh(ctx, p.client); err != nil {
+			return err
+		}
+	}
+
+	var (
+		mu    sync.Mutex
+		fnErr error
+	)
+	err = provider.ForEachZone(ctx, sortedZoneIDs(zones), p.zoneWorkers, func(ctx context.Context, id string) error {
+		return p.iterZoneRecords(ctx, zones[id], func(ep *endpoint.Endpoint) error {
+			mu.Lock()
+			defer mu.Unlock()
+			// the zones listed concurrently stop at the first error of fn
+			if fnErr == nil {
+				fnErr = fn(ep)
+			}
+			return fnErr
+		})
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return err
+	}
+	if p.healthChecks != nil {
+		p.healthChecks.collect(ctx, p.client, zones)
+	}
+	return nil
+}
+
+// zoneRecords lists the records of a hosted zone.
+func (p *AWSProvider) zoneRecords(ctx context.Context, z *route53.HostedZone) ([]*endpoint.Endpoint, error) {
 	endpoints := make([]*endpoint.Endpoint, 0)
+	err := p.iterZoneRecords(ctx, z, func(ep *endpoint.Endpoint) error {
+		endpoints = append(endpoints, ep)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// iterZoneRecords calls fn for the records of a hosted zone while they are listed.
+func (p *AWSProvider) iterZoneRecords(ctx context.Context, z *route53.HostedZone, fn func(*endpoint.Endpoint) error) error {
+	var fnErr error
 	f := func(resp *route53.ListResourceRecordSetsOutput, lastPage bool) (shouldContinue bool) {
 		for _, r := range resp.ResourceRecordSets {
 			newEndpoints := make([]*endpoint.Endpoint, 0)
@@ -428,28 +563,42 @@ func (p *AWSProvider) records(ctx context.Context, zones map[string]*route53.Hos
 				}
 
 				if r.HealthCheckId != nil {
-					ep.WithProviderSpecific(providerSpecificHealthCheckID, aws.StringValue(r.HealthCheckId))
+					if spec, ok := p.managedHealthCheck(aws.StringValue(r.HealthCheckId)); ok {
+						ep.WithProviderSpecific(providerSpecificHealthCheck, spec)
+					} else {
+						ep.WithProviderSpecific(providerSpecificHealthCheckID, aws.StringValue(r.HealthCheckId))
+					}
 				}
 
-				endpoints = append(endpoints, ep)
+				if fnErr = fn(ep); fnErr != nil {
+					return false
+				}
 			}
 		}
 
 		return true
 	}
 
-	for _, z := range zones {
-		params := &route53.ListResourceRecordSetsInput{
-			HostedZoneId: z.Id,
-			MaxItems:     aws.String(route53PageSize),
-		}
+	params := &route53.ListResourceRecordSetsInput{
+		HostedZoneId: z.Id,
+		MaxItems:     aws.String(route53PageSize),
+	}
 
-		if err := p.client.ListResourceRecordSetsPagesWithContext(ctx, params, f); err != nil {
-			return nil, errors.Wrapf(err, "failed to list resource records sets for zone %s", *z.Id)
-		}
+	if err := p.client.ListResourceRecordSetsPagesWithContext(ctx, params, f); err != nil {
+		return errors.Wrapf(err, "failed to list resource records sets for zone %s", *z.Id)
 	}
 
-	return endpoints, nil
+	return fnErr
+}
+
+// sortedZoneIDs returns the IDs of the hosted zones in a stable order.
+func sortedZoneIDs(zones map[string]*route53.HostedZone) []string {
+	ids := make([]string, 0, len(zones))
+	for id := range zones {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
 }
 
 // Identify if old and new endpoints require DELETE/CREATE instead of UPDATE.
@@ -533,6 +682,12 @@ func (p *AWSProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) e
 		return errors.Wrap(err, "failed to list zones, not applying changes")
 	}
 
+	if p.healthChecks != nil {
+		if changes, err = p.withHealthChecks(ctx, zones, changes); err != nil {
+			return err
+		}
+	}
+
 	updateChanges := p.createUpdateChanges(changes.UpdateNew, changes.UpdateOld)
 
 	combinedChanges := make(Route53Changes, 0, len(changes.Delete)+len(changes.Create)+len(updateChanges))
@@ -540,6 +695,12 @@ func (p *AWSProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) e
 	combinedChanges = append(combinedChanges, p.newChanges(route53.ChangeActionDelete, changes.Delete)...)
 	combinedChanges = append(combinedChanges, updateChanges...)
 
+	if p.dryRun && p.validateDryRun {
+		if err := p.validateChanges(ctx, changes, combinedChanges, zones); err != nil {
+			return errors.Wrap(err, "the changes would be rejected by Route53")
+		}
+	}
+
 	return p.submitChanges(ctx, combinedChanges, zones)
 }
 
@@ -557,85 +718,116 @@ func (p *AWSProvider) submitChanges(ctx context.Context, changes Route53Changes,
 		log.Info("All records are already up to date, there are no changes for the matching hosted zones")
 	}
 
-	var failedZones []string
-	for z, cs := range changesByZone {
-		var failedUpdate bool
+	var (
+		mu          sync.Mutex
+		failedZones []string
+	)
+	ids := make([]string, 0, len(changesByZone))
+	for z := range changesByZone {
+		ids = append(ids, z)
+	}
+	sort.Strings(ids)
+	_ = provider.ForEachZone(ctx, ids, p.zoneWorkers, func(ctx context.Context, z string) error {
+		if !p.submitZoneChanges(ctx, z, changesByZone[z], zones) {
+			mu.Lock()
+			failedZones = append(failedZones, z)
+			mu.Unlock()
+		}
+		return nil
+	})
 
-		// group changes into new changes and into changes that failed in a previous iteration and are retried
-		retriedChanges, newChanges := findChangesInQueue(cs, p.failedChangesQueue[z])
-		p.failedChangesQueue[z] = nil
+	if len(failedZones) > 0 {
+		sort.Strings(failedZones)
+		return errors.Errorf("failed to submit all changes for the following zones: %v", failedZones)
+	}
 
-		batchCs := append(batchChangeSet(newChanges, p.batchChangeSize), batchChangeSet(retriedChanges, p.batchChangeSize)...)
-		for i, b := range batchCs {
-			if len(b) == 0 {
-				continue
-			}
+	return nil
+}
 
-			for _, c := range b {
-				log.Infof("Desired change: %s %s %s [Id: %s]", *c.Action, *c.ResourceRecordSet.Name, *c.ResourceRecordSet.Type, z)
-			}
+// submitZoneChanges submits the changes of a hosted zone in batches and returns whether all
+// of them were applied. The changes which failed are queued to be retried in the next iteration.
+func (p *AWSProvider) submitZoneChanges(ctx context.Context, z string, cs Route53Changes, zones map[string]*route53.HostedZone) bool {
+	var failedUpdate bool
 
-			if !p.dryRun {
-				params := &route53.ChangeResourceRecordSetsInput{
-					HostedZoneId: aws.String(z),
-					ChangeBatch: &route53.ChangeBatch{
-						Changes: b.Route53Changes(),
-					},
-				}
+	// group changes into new changes and into changes that failed in a previous iteration and are retried
+	p.failedChangesMu.Lock()
+	retriedChanges, newChanges := findChangesInQueue(cs, p.failedChangesQueue[z])
+	p.failedChangesQueue[z] = nil
+	p.failedChangesMu.Unlock()
 
-				successfulChanges := 0
+	batchCs := append(batchChangeSet(newChanges, p.batchChangeSize), batchChangeSet(retriedChanges, p.batchChangeSize)...)
+	for i, b := range batchCs {
+		if len(b) == 0 {
+			continue
+		}
 
-				if _, err := p.client.ChangeResourceRecordSetsWithContext(ctx, params); err != nil {
-					log.Errorf("Failure in zone %s [Id: %s] when submitting change batch: %v", aws.StringValue(zones[z].Name), z, err)
+		for _, c := range b {
+			log.Infof("Desired change: %s %s %s [Id: %s]", *c.Action, *c.ResourceRecordSet.Name, *c.ResourceRecordSet.Type, z)
+		}
 
-					changesByOwnership := groupChangesByNameAndOwnershipRelation(b)
+		if !p.dryRun {
+			params := &route53.ChangeResourceRecordSetsInput{
+				HostedZoneId: aws.String(z),
+				ChangeBatch: &route53.ChangeBatch{
+					Changes: b.Route53Changes(),
+				},
+			}
 
-					if len(changesByOwnership) > 1 {
-						log.Debug("Trying to submit change sets one-by-one instead")
+			successfulChanges := 0
 
-						for _, changes := range changesByOwnership {
-							for _, c := range changes {
-								log.Debugf("Desired change: %s %s %s [Id: %s]", *c.Action, *c.ResourceRecordSet.Name, *c.ResourceRecordSet.Type, z)
-							}
-							params.ChangeBatch = &route53.ChangeBatch{
-								Changes: changes.Route53Changes(),
-							}
-							if _, err := p.client.ChangeResourceRecordSetsWithContext(ctx, params); err != nil {
-								failedUpdate = true
-								log.Errorf("Failed submitting change (error: %v), it will be retried in a separate change batch in the next iteration", err)
-								p.failedChangesQueue[z] = append(p.failedChangesQueue[z], changes...)
-							} else {
-								successfulChanges = successfulChanges + len(changes)
-							}
+			if err := p.changeResourceRecordSets(ctx, params); err != nil {
+				log.Errorf("Failure in zone %s [Id: %s] when submitting change batch: %v", aws.StringValue(zones[z].Name), z, err)
+
+				changesByOwnership := groupChangesByNameAndOwnershipRelation(b)
+
+				if len(changesByOwnership) > 1 {
+					log.Debug("Trying to submit change sets one-by-one instead")
+
+					for _, changes := range changesByOwnership {
+						for _, c := range changes {
+							log.Debugf("Desired change: %s %s %s [Id: %s]", *c.Action, *c.ResourceRecordSet.Name, *c.ResourceRecordSet.Type, z)
+						}
+						params.ChangeBatch = &route53.ChangeBatch{
+							Changes: changes.Route53Changes(),
+						}
+						if err := p.changeResourceRecordSets(ctx, params); err != nil {
+							failedUpdate = true
+							log.Errorf("Failed submitting change (error: %v), it will be retried in a separate change batch in the next iteration", err)
+							p.failedChangesMu.Lock()
+							p.failedChangesQueue[z] = append(p.failedChangesQueue[z], changes...)
+							p.failedChangesMu.Unlock()
+						} else {
+							successfulChanges = successfulChanges + len(changes)
 						}
-					} else {
-						failedUpdate = true
 					}
 				} else {
-					successfulChanges = len(b)
-				}
-
-				if successfulChanges > 0 {
-					// z is the R53 Hosted Zone ID already as aws.StringValue
-					log.Infof("%d record(s) in zone %s [Id: %s] were successfully updated", successfulChanges, aws.StringValue(zones[z].Name), z)
+					failedUpdate = true
 				}
+			} else {
+				successfulChanges = len(b)
+			}
 
-				if i != len(batchCs)-1 {
-					time.Sleep(p.batchChangeInterval)
-				}
+			if successfulChanges > 0 {
+				// z is the R53 Hosted Zone ID already as aws.StringValue
+				log.Infof("%d record(s) in zone %s [Id: %s] were successfully updated", successfulChanges, aws.StringValue(zones[z].Name), z)
 			}
-		}
 
-		if failedUpdate {
-			failedZones = append(failedZones, z)
+			if i != len(batchCs)-1 {
+				time.Sleep(p.batchChangeInterval)
+			}
 		}
 	}
 
-	if len(failedZones) > 0 {
-		return errors.Errorf("failed to submit all changes for the following zones: %v", failedZones)
-	}
+	return !failedUpdate
+}
 
-	return nil
+// changeResourceRecordSets submits a change batch once the change rate shared by the zone workers allows it.
+func (p *AWSProvider) changeResourceRecordSets(ctx context.Context, params *route53.ChangeResourceRecordSetsInput) error {
+	if err := provider.WaitZoneChange(ctx, p.changeLimiter); err != nil {
+		return err
+	}
+	_, err := p.client.ChangeResourceRecordSetsWithContext(ctx, params)
+	return err
 }
 
 // newChanges returns a collection of Changes based on the given records and action.
@@ -648,7 +840,7 @@ func (p *AWSProvider) newChanges(action string, endpoints []*endpoint.Endpoint)
 		if dualstack {
 			// make a copy of change, modify RRS type to AAAA, then add new change
 			rrs := *change.ResourceRecordSet
-			change2 := &Route53Change{Change: route53.Change{Action: change.Action, ResourceRecordSet: &rrs}}
+			change2 := &Route53Change{Change: route53.Change{Action: change.Action, ResourceRecordSet: &rrs}, ZoneID: change.ZoneID}
 			change2.ResourceRecordSet.Type = aws.String(route53.RRTypeAaaa)
 			changes = append(changes, change2)
 		}
@@ -663,7 +855,10 @@ func (p *AWSProvider) newChanges(action string, endpoints []*endpoint.Endpoint)
 // Example: CNAME endpoints pointing to ELBs will have a `alias` provider-specific property
 // added to match the endpoints generated from existing alias records in Route53.
 func (p *AWSProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	adjusted := make([]*endpoint.Endpoint, 0, len(endpoints))
 	for _, ep := range endpoints {
+		p.adjustHealthCheck(ep)
+
 		alias := false
 
 		if aliasString, ok := ep.GetProviderSpecificProperty(providerSpecificAlias); ok {
@@ -703,8 +898,50 @@ func (p *AWSProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoi
 		} else {
 			ep.DeleteProviderSpecificProperty(providerSpecificEvaluateTargetHealth)
 		}
+
+		if alias && len(ep.Targets) > 1 {
+			adjusted = append(adjusted, splitAliasTargets(ep)...)
+			continue
+		}
+		adjusted = append(adjusted, ep)
 	}
-	return endpoints, nil
+	return adjusted, nil
+}
+
+// splitAliasTargets returns one alias record per target of an alias record with multiple targets,
+// e.g. a zone apex pointing at several load balancers, as an alias record has a single target.
+// The alias records have equal weights, so that Route53 answers with each of the targets evenly.
+// Alias records with a set identifier already use a routing policy and can't be split, only their
+// first target is used.
+func splitAliasTargets(ep *endpoint.Endpoint) []*endpoint.Endpoint {
+	if ep.SetIdentifier != "" {
+		log.Warnf("Alias record %s with set identifier %s has multiple targets, only %s is used", ep.DNSName, ep.SetIdentifier, ep.Targets[0])
+		return []*endpoint.Endpoint{ep}
+	}
+
+	log.Debugf("Splitting alias record %s into weighted alias records for its targets %v", ep.DNSName, ep.Targets)
+	endpoints := make([]*endpoint.Endpoint, 0, len(ep.Targets))
+	for _, target := range ep.Targets {
+		split := ep.DeepCopy()
+		split.Targets = endpoint.Targets{target}
+		split.SetIdentifier = aliasSetIdentifier(target)
+		split.SetProviderSpecificProperty(providerSpecificWeight, "1")
+		endpoints = append(endpoints, split)
+	}
+	return endpoints
+}
+
+// aliasSetIdentifier returns the set identifier of the alias record of a target split from an
+// alias record with multiple targets, within the limit of 128 characters of Route53.
+func aliasSetIdentifier(target string) string {
+	const maxLength = 128
+	if len(target) <= maxLength {
+		return target
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(target))
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+	return target[:maxLength-len(suffix)] + suffix
 }
 
 // newChange returns a route53 Change and a boolean indicating if there should also be a change to a AAAA record
@@ -800,6 +1037,10 @@ func (p *AWSProvider) newChange(action string, ep *endpoint.Endpoint) (*Route53C
 		change.OwnedRecord = ownedRecord
 	}
 
+	if zoneID, ok := ep.GetProviderSpecificProperty(endpoint.ProviderSpecificZoneID); ok {
+		change.ZoneID = zoneID
+	}
+
 	return change, dualstack
 }
 
@@ -918,17 +1159,27 @@ func sortChangesByActionNameType(cs Route53Changes) Route53Changes {
 }
 
 // changesByZone separates a multi-zone change into a single change per zone.
-func changesByZone(zones map[string]*route53.HostedZone, changeSet Route53Changes) map[string]Route53Changes {
+func changesByZone(allZones map[string]*route53.HostedZone, changeSet Route53Changes) map[string]Route53Changes {
 	changes := make(map[string]Route53Changes)
 
-	for _, z := range zones {
+	for _, z := range allZones {
 		changes[aws.StringValue(z.Id)] = Route53Changes{}
 	}
 
 	for _, c := range changeSet {
 		hostname := provider.EnsureTrailingDot(aws.StringValue(c.ResourceRecordSet.Name))
 
-		zones := suitableZones(hostname, zones)
+		var zones []*route53.HostedZone
+		if c.ZoneID != "" {
+			zone, err := pinnedZone(hostname, c.ZoneID, allZones)
+			if err != nil {
+				log.Warnf("Skipping record %s: %v", c.String(), err)
+				continue
+			}
+			zones = []*route53.HostedZone{zone}
+		} else {
+			zones = suitableZones(hostname, allZones)
+		}
 		if len(zones) == 0 {
 			log.Debugf("Skipping record %s because no hosted zone matching record DNS Name was detected", c.String())
 			continue
@@ -946,6 +1197,7 @@ func changesByZone(zones map[string]*route53.HostedZone, changeSet Route53Change
 						Action:            c.Action,
 						ResourceRecordSet: &rrset,
 					},
+					ZoneID: c.ZoneID,
 				}
 			}
 			changes[aws.StringValue(z.Id)] = append(changes[aws.StringValue(z.Id)], c)
@@ -963,6 +1215,21 @@ func changesByZone(zones map[string]*route53.HostedZone, changeSet Route53Change
 	return changes
 }
 
+// pinnedZone returns the zone a record is pinned to, which has to be one of the managed zones and contain the
+// record, so that records can't be written to zones outside of the configured zone filters.
+func pinnedZone(hostname, zoneID string, zones map[string]*route53.HostedZone) (*route53.HostedZone, error) {
+	for _, z := range zones {
+		if cleanZoneID(aws.StringValue(z.Id)) != cleanZoneID(zoneID) {
+			continue
+		}
+		if aws.StringValue(z.Name) != hostname && !strings.HasSuffix(hostname, "."+aws.StringValue(z.Name)) {
+			return nil, fmt.Errorf("the record is pinned to hosted zone %s, which does not contain it", zoneID)
+		}
+		return z, nil
+	}
+	return nil, fmt.Errorf("the record is pinned to hosted zone %s, which is not managed", zoneID)
+}
+
 // suitableZones returns all suitable private zones and the most suitable public zone
 //
 //	for a given hostname and a set of zones.
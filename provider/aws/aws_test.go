@@ -18,10 +18,12 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -55,8 +57,16 @@ type Route53APIStub struct {
 	zones      map[string]*route53.HostedZone
 	recordSets map[string]map[string][]*route53.ResourceRecordSet
 	zoneTags   map[string][]*route53.Tag
-	m          dynamicMock
-	t          *testing.T
+	// healthChecks are the health checks by id
+	healthChecks map[string]*route53.HealthCheck
+	// healthCheckSeq numbers the ids of the created health checks
+	healthCheckSeq int
+	// keySigningKeys are the DNSSEC key signing keys by zone id
+	keySigningKeys map[string][]*route53.KeySigningKey
+	m              dynamicMock
+	t              *testing.T
+	// mu guards recordSets, which are listed and changed concurrently for different zones
+	mu sync.Mutex
 }
 
 // MockMethod starts a description of an expectation of the specified method
@@ -70,14 +80,17 @@ func (r *Route53APIStub) MockMethod(method string, args ...interface{}) *mock.Ca
 // NewRoute53APIStub returns an initialized Route53APIStub
 func NewRoute53APIStub(t *testing.T) *Route53APIStub {
 	return &Route53APIStub{
-		zones:      make(map[string]*route53.HostedZone),
-		recordSets: make(map[string]map[string][]*route53.ResourceRecordSet),
-		zoneTags:   make(map[string][]*route53.Tag),
-		t:          t,
+		zones:        make(map[string]*route53.HostedZone),
+		recordSets:   make(map[string]map[string][]*route53.ResourceRecordSet),
+		zoneTags:     make(map[string][]*route53.Tag),
+		healthChecks: make(map[string]*route53.HealthCheck),
+		t:            t,
 	}
 }
 
 func (r *Route53APIStub) ListResourceRecordSetsPagesWithContext(ctx context.Context, input *route53.ListResourceRecordSetsInput, fn func(p *route53.ListResourceRecordSetsOutput, lastPage bool) (shouldContinue bool), opts ...request.Option) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	output := route53.ListResourceRecordSetsOutput{} // TODO: Support optional input args.
 	require.NotNil(r.t, input.MaxItems)
 	assert.EqualValues(r.t, route53PageSize, *input.MaxItems)
@@ -132,6 +145,36 @@ func (c *Route53APICounter) ListTagsForResourceWithContext(ctx context.Context,
 	return c.wrapped.ListTagsForResourceWithContext(ctx, input)
 }
 
+func (c *Route53APICounter) ChangeTagsForResourceWithContext(ctx context.Context, input *route53.ChangeTagsForResourceInput, opts ...request.Option) (*route53.ChangeTagsForResourceOutput, error) {
+	c.calls["ChangeTagsForResource"]++
+	return c.wrapped.ChangeTagsForResourceWithContext(ctx, input)
+}
+
+func (c *Route53APICounter) GetHostedZoneWithContext(ctx context.Context, input *route53.GetHostedZoneInput, opts ...request.Option) (*route53.GetHostedZoneOutput, error) {
+	c.calls["GetHostedZone"]++
+	return c.wrapped.GetHostedZoneWithContext(ctx, input)
+}
+
+func (c *Route53APICounter) GetDNSSECWithContext(ctx context.Context, input *route53.GetDNSSECInput, opts ...request.Option) (*route53.GetDNSSECOutput, error) {
+	c.calls["GetDNSSEC"]++
+	return c.wrapped.GetDNSSECWithContext(ctx, input)
+}
+
+func (c *Route53APICounter) ListHealthChecksPagesWithContext(ctx context.Context, input *route53.ListHealthChecksInput, fn func(resp *route53.ListHealthChecksOutput, lastPage bool) (shouldContinue bool), opts ...request.Option) error {
+	c.calls["ListHealthChecksPages"]++
+	return c.wrapped.ListHealthChecksPagesWithContext(ctx, input, fn)
+}
+
+func (c *Route53APICounter) CreateHealthCheckWithContext(ctx context.Context, input *route53.CreateHealthCheckInput, opts ...request.Option) (*route53.CreateHealthCheckOutput, error) {
+	c.calls["CreateHealthCheck"]++
+	return c.wrapped.CreateHealthCheckWithContext(ctx, input)
+}
+
+func (c *Route53APICounter) DeleteHealthCheckWithContext(ctx context.Context, input *route53.DeleteHealthCheckInput, opts ...request.Option) (*route53.DeleteHealthCheckOutput, error) {
+	c.calls["DeleteHealthCheck"]++
+	return c.wrapped.DeleteHealthCheckWithContext(ctx, input)
+}
+
 // Route53 stores wildcards escaped: http://docs.aws.amazon.com/Route53/latest/DeveloperGuide/DomainNameFormat.html?shortFooter=true#domain-name-format-asterisk
 func wildcardEscape(s string) string {
 	if strings.Contains(s, "*") {
@@ -154,11 +197,48 @@ func (r *Route53APIStub) ListTagsForResourceWithContext(ctx context.Context, inp
 	return &route53.ListTagsForResourceOutput{}, nil
 }
 
+func (r *Route53APIStub) ChangeTagsForResourceWithContext(ctx context.Context, input *route53.ChangeTagsForResourceInput, opts ...request.Option) (*route53.ChangeTagsForResourceOutput, error) {
+	id := aws.StringValue(input.ResourceId)
+	for _, tag := range input.AddTags {
+		found := false
+		for _, existing := range r.zoneTags[id] {
+			if aws.StringValue(existing.Key) == aws.StringValue(tag.Key) {
+				existing.Value = tag.Value
+				found = true
+			}
+		}
+		if !found {
+			r.zoneTags[id] = append(r.zoneTags[id], tag)
+		}
+	}
+	return &route53.ChangeTagsForResourceOutput{}, nil
+}
+
+func (r *Route53APIStub) GetHostedZoneWithContext(ctx context.Context, input *route53.GetHostedZoneInput, opts ...request.Option) (*route53.GetHostedZoneOutput, error) {
+	zone, ok := r.zones[aws.StringValue(input.Id)]
+	if !ok {
+		return nil, fmt.Errorf("Hosted zone doesn't exist: %s", aws.StringValue(input.Id))
+	}
+	output := &route53.GetHostedZoneOutput{HostedZone: zone}
+	if zone.Config == nil || !aws.BoolValue(zone.Config.PrivateZone) {
+		output.DelegationSet = &route53.DelegationSet{
+			NameServers: aws.StringSlice([]string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.com"}),
+		}
+	}
+	return output, nil
+}
+
+func (r *Route53APIStub) GetDNSSECWithContext(ctx context.Context, input *route53.GetDNSSECInput, opts ...request.Option) (*route53.GetDNSSECOutput, error) {
+	return &route53.GetDNSSECOutput{KeySigningKeys: r.keySigningKeys[aws.StringValue(input.HostedZoneId)]}, nil
+}
+
 func (r *Route53APIStub) ChangeResourceRecordSetsWithContext(ctx context.Context, input *route53.ChangeResourceRecordSetsInput, opts ...request.Option) (*route53.ChangeResourceRecordSetsOutput, error) {
 	if r.m.isMocked("ChangeResourceRecordSets", input) {
 		return r.m.ChangeResourceRecordSets(input)
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	_, ok := r.zones[aws.StringValue(input.HostedZoneId)]
 	if !ok {
 		return nil, fmt.Errorf("Hosted zone doesn't exist: %s", aws.StringValue(input.HostedZoneId))
@@ -230,13 +310,55 @@ func (r *Route53APIStub) CreateHostedZoneWithContext(ctx context.Context, input
 		return nil, fmt.Errorf("Error creating hosted DNS zone: %s already exists", id)
 	}
 	r.zones[id] = &route53.HostedZone{
-		Id:     aws.String(id),
-		Name:   aws.String(name),
-		Config: input.HostedZoneConfig,
+		Id:              aws.String(id),
+		Name:            aws.String(name),
+		Config:          input.HostedZoneConfig,
+		CallerReference: input.CallerReference,
 	}
 	return &route53.CreateHostedZoneOutput{HostedZone: r.zones[id]}, nil
 }
 
+func (r *Route53APIStub) ListHealthChecksPagesWithContext(ctx context.Context, input *route53.ListHealthChecksInput, fn func(p *route53.ListHealthChecksOutput, lastPage bool) (shouldContinue bool), opts ...request.Option) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	output := &route53.ListHealthChecksOutput{}
+	for _, hc := range r.healthChecks {
+		output.HealthChecks = append(output.HealthChecks, hc)
+	}
+	lastPage := true
+	fn(output, lastPage)
+	return nil
+}
+
+func (r *Route53APIStub) CreateHealthCheckWithContext(ctx context.Context, input *route53.CreateHealthCheckInput, opts ...request.Option) (*route53.CreateHealthCheckOutput, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, hc := range r.healthChecks {
+		if aws.StringValue(hc.CallerReference) == aws.StringValue(input.CallerReference) {
+			return nil, fmt.Errorf("Health check with caller reference %s already exists", aws.StringValue(input.CallerReference))
+		}
+	}
+	r.healthCheckSeq++
+	id := fmt.Sprintf("hc-%d", r.healthCheckSeq)
+	r.healthChecks[id] = &route53.HealthCheck{
+		Id:                aws.String(id),
+		CallerReference:   input.CallerReference,
+		HealthCheckConfig: input.HealthCheckConfig,
+	}
+	return &route53.CreateHealthCheckOutput{HealthCheck: r.healthChecks[id]}, nil
+}
+
+func (r *Route53APIStub) DeleteHealthCheckWithContext(ctx context.Context, input *route53.DeleteHealthCheckInput, opts ...request.Option) (*route53.DeleteHealthCheckOutput, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := aws.StringValue(input.HealthCheckId)
+	if _, ok := r.healthChecks[id]; !ok {
+		return nil, fmt.Errorf("Health check doesn't exist: %s", id)
+	}
+	delete(r.healthChecks, id)
+	return &route53.DeleteHealthCheckOutput{}, nil
+}
+
 type dynamicMock struct {
 	mock.Mock
 }
@@ -517,6 +639,52 @@ func TestAWSRecords(t *testing.T) {
 	})
 }
 
+func TestAWSRecordsIter(t *testing.T) {
+	provider, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), false, false, []*route53.ResourceRecordSet{
+		{
+			Name:            aws.String("list-test.zone-1.ext-dns-test-2.teapot.zalan.do."),
+			Type:            aws.String(route53.RRTypeA),
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.4")}},
+		},
+		{
+			Name:            aws.String("list-test.zone-2.ext-dns-test-2.teapot.zalan.do."),
+			Type:            aws.String(route53.RRTypeA),
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("8.8.8.8")}},
+		},
+		{
+			Name:            aws.String("list-test.zone-3.ext-dns-test-2.teapot.zalan.do."),
+			Type:            aws.String(route53.RRTypeCname),
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("example.com")}},
+		},
+	})
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+
+	for _, workers := range []int{1, 3} {
+		provider.zoneWorkers = workers
+
+		var streamed []*endpoint.Endpoint
+		require.NoError(t, provider.RecordsIter(context.Background(), func(ep *endpoint.Endpoint) error {
+			streamed = append(streamed, ep)
+			return nil
+		}))
+		assert.ElementsMatch(t, records, streamed)
+
+		errStop := errors.New("stop")
+		calls := 0
+		err = provider.RecordsIter(context.Background(), func(*endpoint.Endpoint) error {
+			calls++
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, calls)
+	}
+}
+
 func TestAWSAdjustEndpoints(t *testing.T) {
 	provider, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
 
@@ -542,6 +710,43 @@ func TestAWSAdjustEndpoints(t *testing.T) {
 	})
 }
 
+func TestAWSAdjustEndpointsAliasMultipleTargets(t *testing.T) {
+	provider, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
+
+	records, err := provider.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeCNAME, "foo.eu-central-1.elb.amazonaws.com", "bar.eu-central-1.elb.amazonaws.com"),
+		endpoint.NewEndpoint("weighted.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeCNAME, "foo.eu-central-1.elb.amazonaws.com", "bar.eu-central-1.elb.amazonaws.com").WithSetIdentifier("blue").WithProviderSpecific(providerSpecificWeight, "10"),
+	})
+	require.NoError(t, err)
+
+	expected := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "foo.eu-central-1.elb.amazonaws.com").WithSetIdentifier("foo.eu-central-1.elb.amazonaws.com").WithProviderSpecific(providerSpecificAlias, "true").WithProviderSpecific(providerSpecificEvaluateTargetHealth, "true").WithProviderSpecific(providerSpecificWeight, "1"),
+		endpoint.NewEndpoint("zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "bar.eu-central-1.elb.amazonaws.com").WithSetIdentifier("bar.eu-central-1.elb.amazonaws.com").WithProviderSpecific(providerSpecificAlias, "true").WithProviderSpecific(providerSpecificEvaluateTargetHealth, "true").WithProviderSpecific(providerSpecificWeight, "1"),
+		endpoint.NewEndpoint("weighted.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "foo.eu-central-1.elb.amazonaws.com", "bar.eu-central-1.elb.amazonaws.com").WithSetIdentifier("blue").WithProviderSpecific(providerSpecificAlias, "true").WithProviderSpecific(providerSpecificEvaluateTargetHealth, "true").WithProviderSpecific(providerSpecificWeight, "10"),
+	}
+	split := []*endpoint.Endpoint{records[0], records[1]}
+	validateEndpoints(t, provider, records, expected)
+
+	// the split records are read back as they are desired, so that they are not updated again
+	require.NoError(t, provider.ApplyChanges(context.Background(), &plan.Changes{Create: split}))
+	current, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, provider, current, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, recordTTL, "foo.eu-central-1.elb.amazonaws.com").WithSetIdentifier("foo.eu-central-1.elb.amazonaws.com").WithProviderSpecific(providerSpecificAlias, "true").WithProviderSpecific(providerSpecificEvaluateTargetHealth, "true").WithProviderSpecific(providerSpecificWeight, "1"),
+		endpoint.NewEndpointWithTTL("zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, recordTTL, "bar.eu-central-1.elb.amazonaws.com").WithSetIdentifier("bar.eu-central-1.elb.amazonaws.com").WithProviderSpecific(providerSpecificAlias, "true").WithProviderSpecific(providerSpecificEvaluateTargetHealth, "true").WithProviderSpecific(providerSpecificWeight, "1"),
+	})
+}
+
+func TestAWSAliasSetIdentifier(t *testing.T) {
+	assert.Equal(t, "foo.eu-central-1.elb.amazonaws.com", aliasSetIdentifier("foo.eu-central-1.elb.amazonaws.com"))
+
+	long := strings.Repeat("a", 120) + ".eu-central-1.elb.amazonaws.com"
+	id := aliasSetIdentifier(long)
+	assert.Len(t, id, 128)
+	assert.Equal(t, id, aliasSetIdentifier(long))
+	assert.NotEqual(t, id, aliasSetIdentifier(strings.Repeat("a", 120)+".eu-west-1.elb.amazonaws.com"))
+}
+
 func TestAWSCreateRecords(t *testing.T) {
 	customTTL := endpoint.TTL(60)
 	provider, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
@@ -1145,6 +1350,64 @@ func TestAWSApplyChanges(t *testing.T) {
 	}
 }
 
+func TestAWSApplyChangesZoneWorkers(t *testing.T) {
+	provider, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, []*route53.ResourceRecordSet{
+		{
+			Name:            aws.String("delete-test.zone-2.ext-dns-test-2.teapot.zalan.do."),
+			Type:            aws.String(route53.RRTypeA),
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("8.8.4.4")}},
+		},
+	})
+	provider.zoneWorkers = 3
+	provider.batchChangeInterval = 10 * time.Millisecond
+	provider.batchChangeSize = 1
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.1.1.1"),
+			endpoint.NewEndpoint("b.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.1.1.2"),
+			endpoint.NewEndpoint("a.zone-2.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "2.2.2.2"),
+			endpoint.NewEndpoint("a.zone-3.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "3.3.3.3"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("delete-test.zone-2.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "8.8.4.4"),
+		},
+	}
+	require.NoError(t, provider.ApplyChanges(context.Background(), changes))
+
+	records, err := provider.Records(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, provider, records, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("a.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, endpoint.TTL(recordTTL), "1.1.1.1"),
+		endpoint.NewEndpointWithTTL("b.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, endpoint.TTL(recordTTL), "1.1.1.2"),
+		endpoint.NewEndpointWithTTL("a.zone-2.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, endpoint.TTL(recordTTL), "2.2.2.2"),
+		endpoint.NewEndpointWithTTL("a.zone-3.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, endpoint.TTL(recordTTL), "3.3.3.3"),
+	})
+}
+
+func TestAWSApplyChangesZoneChangeRate(t *testing.T) {
+	limiter := provider.NewZoneChangeLimiter(50)
+	provider, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
+	provider.zoneWorkers = 3
+	provider.changeLimiter = limiter
+	provider.batchChangeInterval = 0
+	provider.batchChangeSize = 1
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.1.1.1"),
+			endpoint.NewEndpoint("b.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.1.1.2"),
+			endpoint.NewEndpoint("a.zone-2.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "2.2.2.2"),
+			endpoint.NewEndpoint("a.zone-3.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "3.3.3.3"),
+		},
+	}
+	start := time.Now()
+	require.NoError(t, provider.ApplyChanges(context.Background(), changes))
+	// the four batches of the three zones share 50 batches per second, the first one being immediate
+	assert.GreaterOrEqual(t, time.Since(start), 3*20*time.Millisecond)
+}
+
 func TestAWSApplyChangesDryRun(t *testing.T) {
 	originalRecords := []*route53.ResourceRecordSet{
 		{
@@ -1401,6 +1664,44 @@ func TestAWSChangesByZones(t *testing.T) {
 	})
 }
 
+func TestAWSChangesByZonesPinned(t *testing.T) {
+	zones := map[string]*route53.HostedZone{
+		"/hostedzone/bar-example-org": {
+			Id:   aws.String("/hostedzone/bar-example-org"),
+			Name: aws.String("bar.example.org."),
+		},
+		"/hostedzone/bar-example-org-private": {
+			Id:     aws.String("/hostedzone/bar-example-org-private"),
+			Name:   aws.String("bar.example.org."),
+			Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(true)},
+		},
+		"/hostedzone/baz-example-org": {
+			Id:   aws.String("/hostedzone/baz-example-org"),
+			Name: aws.String("baz.example.org."),
+		},
+	}
+	newPinnedChange := func(name, zoneID string) *Route53Change {
+		return &Route53Change{
+			Change: route53.Change{
+				Action:            aws.String(route53.ChangeActionCreate),
+				ResourceRecordSet: &route53.ResourceRecordSet{Name: aws.String(name)},
+			},
+			ZoneID: zoneID,
+		}
+	}
+	changes := Route53Changes{
+		newPinnedChange("qux.bar.example.org", "bar-example-org-private"),
+		// not contained by the zone it is pinned to
+		newPinnedChange("quux.bar.example.org", "baz-example-org"),
+		// not a managed zone
+		newPinnedChange("corge.bar.example.org", "other"),
+	}
+
+	changesByZone := changesByZone(zones, changes)
+	require.Len(t, changesByZone, 1)
+	validateAWSChangeRecords(t, changesByZone["/hostedzone/bar-example-org-private"], Route53Changes{changes[0]})
+}
+
 func TestAWSsubmitChanges(t *testing.T) {
 	provider, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
 	const subnets = 16
@@ -2054,3 +2355,75 @@ func TestRequiresDeleteCreate(t *testing.T) {
 	assert.False(t, provider.requiresDeleteCreate(oldSetIdentifier, oldSetIdentifier), "actual and expected endpoints don't match. %+v:%+v", oldSetIdentifier, oldSetIdentifier)
 	assert.True(t, provider.requiresDeleteCreate(oldSetIdentifier, newSetIdentifier), "actual and expected endpoints don't match. %+v:%+v", oldSetIdentifier, newSetIdentifier)
 }
+
+func TestAWSPreflight(t *testing.T) {
+	p, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
+
+	require.NoError(t, p.Preflight(context.Background()))
+
+	records, err := p.Records(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	p, clientStub := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
+	clientStub.MockMethod("ChangeResourceRecordSets", mock.Anything).Return(nil, fmt.Errorf("AccessDenied"))
+
+	assert.ErrorContains(t, p.Preflight(context.Background()), "records of zone zone-1.ext-dns-test-2.teapot.zalan.do. cannot be created")
+}
+
+func TestAWSValidateDryRun(t *testing.T) {
+	p, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, true, []*route53.ResourceRecordSet{
+		{
+			Name:            aws.String("existing.zone-1.ext-dns-test-2.teapot.zalan.do."),
+			Type:            aws.String(route53.RRTypeA),
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.4")}},
+		},
+	})
+	p.validateDryRun = true
+
+	valid := &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("new.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4")},
+		Delete: []*endpoint.Endpoint{endpoint.NewEndpoint("existing.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4")},
+	}
+	require.NoError(t, p.ApplyChanges(context.Background(), valid))
+
+	err := p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("existing.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "5.6.7.8"),
+			endpoint.NewEndpoint("zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeCNAME, "example.org"),
+		},
+	})
+	assert.ErrorContains(t, err, "cannot create A existing.zone-1.ext-dns-test-2.teapot.zalan.do: the record already exists")
+	assert.ErrorContains(t, err, "CNAME zone-1.ext-dns-test-2.teapot.zalan.do is not permitted at the apex of the zone")
+
+	// the changes are not validated when disabled
+	p.validateDryRun = false
+	assert.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("existing.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "5.6.7.8")},
+	}))
+}
+
+func TestAWSValidateBatch(t *testing.T) {
+	newChange := func(action string, values ...string) *Route53Change {
+		rrs := make([]*route53.ResourceRecord, 0, len(values))
+		for _, v := range values {
+			rrs = append(rrs, &route53.ResourceRecord{Value: aws.String(v)})
+		}
+		return &Route53Change{Change: route53.Change{
+			Action:            aws.String(action),
+			ResourceRecordSet: &route53.ResourceRecordSet{Name: aws.String("a.example.org"), Type: aws.String(route53.RRTypeTxt), ResourceRecords: rrs},
+		}}
+	}
+	value := strings.Repeat("x", 20000)
+
+	assert.NoError(t, validateBatch(Route53Changes{newChange(route53.ChangeActionCreate, value)}))
+	assert.ErrorContains(t, validateBatch(Route53Changes{newChange(route53.ChangeActionUpsert, value)}), "40000 characters")
+
+	values := make([]string, 501)
+	for i := range values {
+		values[i] = fmt.Sprintf("%d", i)
+	}
+	assert.NoError(t, validateBatch(Route53Changes{newChange(route53.ChangeActionCreate, values...)}))
+	assert.ErrorContains(t, validateBatch(Route53Changes{newChange(route53.ChangeActionUpsert, values...)}), "1002 resource records")
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+const (
+	// providerSpecificHealthCheck requests a health check of the target of a record, managed by ExternalDNS,
+	// in the form <type>:<port>[<path>], e.g. HTTPS:443/healthz or TCP:5432.
+	providerSpecificHealthCheck = "aws/health-check"
+	// healthCheckCallerReferencePrefix identifies the health checks created by ExternalDNS, whose caller
+	// references are <prefix><hosted zone id>/<creation time>.
+	healthCheckCallerReferencePrefix = "external-dns/"
+	// healthCheckGracePeriod is how long a health check created by ExternalDNS is kept without being
+	// referenced by a record, so that a health check is not deleted before its record is created.
+	healthCheckGracePeriod = 10 * time.Minute
+)
+
+// healthCheckSpec is the configuration of a health check created by ExternalDNS.
+type healthCheckSpec struct {
+	checkType string
+	port      int64
+	path      string
+}
+
+// parseHealthCheckSpec parses a health check given as <type>:<port>[<path>].
+func parseHealthCheckSpec(value string) (healthCheckSpec, error) {
+	checkType, rest, found := strings.Cut(value, ":")
+	if !found {
+		return healthCheckSpec{}, fmt.Errorf("invalid health check %q, expected <type>:<port>[<path>]", value)
+	}
+	spec := healthCheckSpec{checkType: strings.ToUpper(checkType)}
+
+	port := rest
+	if i := strings.Index(rest, "/"); i >= 0 {
+		port, spec.path = rest[:i], rest[i:]
+	}
+	var err error
+	if spec.port, err = strconv.ParseInt(port, 10, 64); err != nil || spec.port < 1 || spec.port > 65535 {
+		return healthCheckSpec{}, fmt.Errorf("invalid port in health check %q", value)
+	}
+
+	switch spec.checkType {
+	case route53.HealthCheckTypeHttp, route53.HealthCheckTypeHttps:
+	case route53.HealthCheckTypeTcp:
+		if spec.path != "" {
+			return healthCheckSpec{}, fmt.Errorf("invalid health check %q, TCP health checks have no path", value)
+		}
+	default:
+		return healthCheckSpec{}, fmt.Errorf("invalid type in health check %q, expected HTTP, HTTPS or TCP", value)
+	}
+	return spec, nil
+}
+
+func (s healthCheckSpec) String() string {
+	return fmt.Sprintf("%s:%d%s", s.checkType, s.port, s.path)
+}
+
+// healthCheckKey identifies the health check of a target created for the records of a hosted zone.
+type healthCheckKey struct {
+	zoneID string
+	spec   string
+	target string
+}
+
+// healthCheck is a health check created by ExternalDNS.
+type healthCheck struct {
+	key     healthCheckKey
+	created time.Time
+}
+
+// healthChecks keeps track of the health checks created by ExternalDNS, which are attached to the
+// records with the aws/health-check provider specific property, e.g. to fail over between clusters
+// publishing the same hostname with different set identifiers, and deleted once no record uses them.
+type healthChecks struct {
+	mu   sync.Mutex
+	byID map[string]healthCheck
+	ids  map[healthCheckKey]string
+	// referenced are the ids of the health checks used by the records listed last
+	referenced map[string]bool
+}
+
+func newHealthChecks() *healthChecks {
+	return &healthChecks{
+		byID:       map[string]healthCheck{},
+		ids:        map[healthCheckKey]string{},
+		referenced: map[string]bool{},
+	}
+}
+
+// refresh lists the health checks created by ExternalDNS, before the records are listed.
+func (h *healthChecks) refresh(ctx context.Context, client Route53API) error {
+	byID := map[string]healthCheck{}
+	ids := map[healthCheckKey]string{}
+	f := func(resp *route53.ListHealthChecksOutput, lastPage bool) bool {
+		for _, hc := range resp.HealthChecks {
+			check, ok := parseHealthCheck(hc)
+			if !ok {
+				continue
+			}
+			byID[aws.StringValue(hc.Id)] = check
+			ids[check.key] = aws.StringValue(hc.Id)
+		}
+		return true
+	}
+	if err := client.ListHealthChecksPagesWithContext(ctx, &route53.ListHealthChecksInput{}, f); err != nil {
+		return errors.Wrap(err, "failed to list health checks")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.byID = byID
+	h.ids = ids
+	h.referenced = map[string]bool{}
+	return nil
+}
+
+// parseHealthCheck returns the health check created by ExternalDNS described by a Route53 health check.
+func parseHealthCheck(hc *route53.HealthCheck) (healthCheck, bool) {
+	ref := aws.StringValue(hc.CallerReference)
+	if !strings.HasPrefix(ref, healthCheckCallerReferencePrefix) || hc.HealthCheckConfig == nil {
+		return healthCheck{}, false
+	}
+	zoneID, created, found := strings.Cut(strings.TrimPrefix(ref, healthCheckCallerReferencePrefix), "/")
+	if !found {
+		return healthCheck{}, false
+	}
+	nanos, err := strconv.ParseInt(created, 36, 64)
+	if err != nil {
+		return healthCheck{}, false
+	}
+
+	config := hc.HealthCheckConfig
+	spec := healthCheckSpec{
+		checkType: aws.StringValue(config.Type),
+		port:      aws.Int64Value(config.Port),
+		path:      aws.StringValue(config.ResourcePath),
+	}
+	target := aws.StringValue(config.FullyQualifiedDomainName)
+	if config.IPAddress != nil {
+		target = aws.StringValue(config.IPAddress)
+	}
+	return healthCheck{
+		key:     healthCheckKey{zoneID: zoneID, spec: spec.String(), target: target},
+		created: time.Unix(0, nanos),
+	}, true
+}
+
+// spec returns the health check to report for a record using the health check id, if created by ExternalDNS.
+func (h *healthChecks) spec(id string) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.referenced[id] = true
+	check, ok := h.byID[id]
+	return check.key.spec, ok
+}
+
+// ensure returns the id of the health check of the key, creating it unless create is false.
+func (h *healthChecks) ensure(ctx context.Context, client Route53API, key healthCheckKey, create bool) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if id, ok := h.ids[key]; ok {
+		h.referenced[id] = true
+		return id, nil
+	}
+	if !create {
+		return "", nil
+	}
+
+	spec, err := parseHealthCheckSpec(key.spec)
+	if err != nil {
+		return "", err
+	}
+	config := &route53.HealthCheckConfig{
+		Type: aws.String(spec.checkType),
+		Port: aws.Int64(spec.port),
+	}
+	if spec.path != "" {
+		config.ResourcePath = aws.String(spec.path)
+	}
+	if net.ParseIP(key.target) != nil {
+		config.IPAddress = aws.String(key.target)
+	} else {
+		config.FullyQualifiedDomainName = aws.String(key.target)
+		config.EnableSNI = aws.Bool(spec.checkType == route53.HealthCheckTypeHttps)
+	}
+
+	created := time.Now()
+	out, err := client.CreateHealthCheckWithContext(ctx, &route53.CreateHealthCheckInput{
+		CallerReference:   aws.String(healthCheckCallerReferencePrefix + key.zoneID + "/" + strconv.FormatInt(created.UnixNano(), 36)),
+		HealthCheckConfig: config,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create health check %s of %s", key.spec, key.target)
+	}
+	id := aws.StringValue(out.HealthCheck.Id)
+	log.Infof("Created health check %s of %s [Id: %s]", key.spec, key.target, id)
+	h.byID[id] = healthCheck{key: key, created: created}
+	h.ids[key] = id
+	h.referenced[id] = true
+	return id, nil
+}
+
+// collect deletes the health checks created by ExternalDNS for the hosted zones whose records were
+// listed last, which none of these records use since the grace period.
+func (h *healthChecks) collect(ctx context.Context, client Route53API, zones map[string]*route53.HostedZone) {
+	h.mu.Lock()
+	var unused []string
+	for id, check := range h.byID {
+		_, listed := zones["/hostedzone/"+check.key.zoneID]
+		if listed && !h.referenced[id] && time.Since(check.created) > healthCheckGracePeriod {
+			unused = append(unused, id)
+		}
+	}
+	h.mu.Unlock()
+	sort.Strings(unused)
+
+	for _, id := range unused {
+		if _, err := client.DeleteHealthCheckWithContext(ctx, &route53.DeleteHealthCheckInput{HealthCheckId: aws.String(id)}); err != nil {
+			log.Warnf("Failed to delete unused health check %s: %v", id, err)
+			continue
+		}
+		log.Infof("Deleted unused health check %s", id)
+		h.mu.Lock()
+		delete(h.ids, h.byID[id].key)
+		delete(h.byID, id)
+		h.mu.Unlock()
+	}
+}
+
+// adjustHealthCheck normalizes the health check requested by the endpoint, so that it matches the
+// health check reported for the records, and removes it when it can't be used.
+func (p *AWSProvider) adjustHealthCheck(ep *endpoint.Endpoint) {
+	value, ok := ep.GetProviderSpecificProperty(providerSpecificHealthCheck)
+	if !ok {
+		return
+	}
+	if p.healthChecks == nil {
+		log.Debugf("Ignoring health check %s of %s, health checks are disabled", value, ep.DNSName)
+		ep.DeleteProviderSpecificProperty(providerSpecificHealthCheck)
+		return
+	}
+	if _, ok := ep.GetProviderSpecificProperty(providerSpecificHealthCheckID); ok {
+		log.Warnf("Ignoring health check %s of %s, which uses the health check %s", value, ep.DNSName, providerSpecificHealthCheckID)
+		ep.DeleteProviderSpecificProperty(providerSpecificHealthCheck)
+		return
+	}
+	spec, err := parseHealthCheckSpec(value)
+	if err != nil {
+		log.Warnf("Ignoring health check of %s: %v", ep.DNSName, err)
+		ep.DeleteProviderSpecificProperty(providerSpecificHealthCheck)
+		return
+	}
+	ep.SetProviderSpecificProperty(providerSpecificHealthCheck, spec.String())
+}
+
+// managedHealthCheck returns the health check to report for a record using the health check id,
+// if the health check was created by ExternalDNS.
+func (p *AWSProvider) managedHealthCheck(id string) (string, bool) {
+	if p.healthChecks == nil {
+		return "", false
+	}
+	return p.healthChecks.spec(id)
+}
+
+// withHealthChecks returns the changes with the ids of the health checks requested by their records,
+// creating the health checks of the records to create or update.
+func (p *AWSProvider) withHealthChecks(ctx context.Context, zones map[string]*route53.HostedZone, changes *plan.Changes) (*plan.Changes, error) {
+	result := &plan.Changes{}
+	var err error
+	if result.Create, err = p.withHealthCheckIDs(ctx, zones, changes.Create, true); err != nil {
+		return nil, err
+	}
+	if result.UpdateNew, err = p.withHealthCheckIDs(ctx, zones, changes.UpdateNew, true); err != nil {
+		return nil, err
+	}
+	if result.UpdateOld, err = p.withHealthCheckIDs(ctx, zones, changes.UpdateOld, false); err != nil {
+		return nil, err
+	}
+	if result.Delete, err = p.withHealthCheckIDs(ctx, zones, changes.Delete, false); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// withHealthCheckIDs returns the endpoints with the ids of the health checks they request, creating the
+// missing health checks when create is true. The endpoints are copied, as their provider specific
+// properties may be shared with their TXT records.
+func (p *AWSProvider) withHealthCheckIDs(ctx context.Context, zones map[string]*route53.HostedZone, endpoints []*endpoint.Endpoint, create bool) ([]*endpoint.Endpoint, error) {
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		key, ok := healthCheckKeyOf(ep, zones)
+		if !ok {
+			result = append(result, ep)
+			continue
+		}
+		id, err := p.healthChecks.ensure(ctx, p.client, key, create && !p.dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if id == "" && create && p.dryRun {
+			log.Infof("Would create health check %s of %s", key.spec, key.target)
+		}
+		ep = ep.DeepCopy()
+		ep.DeleteProviderSpecificProperty(providerSpecificHealthCheck)
+		if id != "" {
+			ep.SetProviderSpecificProperty(providerSpecificHealthCheckID, id)
+		}
+		result = append(result, ep)
+	}
+	return result, nil
+}
+
+// healthCheckKeyOf returns the health check requested by the endpoint, of the first of its targets,
+// created for the first of the hosted zones of the endpoint.
+func healthCheckKeyOf(ep *endpoint.Endpoint, zones map[string]*route53.HostedZone) (healthCheckKey, bool) {
+	spec, ok := ep.GetProviderSpecificProperty(providerSpecificHealthCheck)
+	if !ok || ep.RecordType == endpoint.RecordTypeTXT || len(ep.Targets) == 0 {
+		return healthCheckKey{}, false
+	}
+	matching := suitableZones(provider.EnsureTrailingDot(ep.DNSName), zones)
+	if len(matching) == 0 {
+		return healthCheckKey{}, false
+	}
+	zoneIDs := make([]string, 0, len(matching))
+	for _, z := range matching {
+		zoneIDs = append(zoneIDs, cleanZoneID(aws.StringValue(z.Id)))
+	}
+	sort.Strings(zoneIDs)
+	return healthCheckKey{zoneID: zoneIDs[0], spec: spec, target: ep.Targets[0]}, true
+}
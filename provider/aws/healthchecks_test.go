@@ -0,0 +1,286 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+func TestParseHealthCheckSpec(t *testing.T) {
+	for _, tt := range []struct {
+		value    string
+		expected string
+		err      bool
+	}{
+		{value: "HTTPS:443/healthz", expected: "HTTPS:443/healthz"},
+		{value: "http:80/", expected: "HTTP:80/"},
+		{value: "HTTP:8080", expected: "HTTP:8080"},
+		{value: "tcp:5432", expected: "TCP:5432"},
+		{value: "TCP:5432/healthz", err: true},
+		{value: "HTTPS", err: true},
+		{value: "HTTPS:0", err: true},
+		{value: "HTTPS:65536", err: true},
+		{value: "HTTPS:port/healthz", err: true},
+		{value: "ICMP:80", err: true},
+	} {
+		t.Run(tt.value, func(t *testing.T) {
+			spec, err := parseHealthCheckSpec(tt.value)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, spec.String())
+		})
+	}
+}
+
+func TestAWSAdjustEndpointsHealthCheck(t *testing.T) {
+	provider, _ := newAWSProviderWithHealthChecks(t)
+
+	records, err := provider.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").WithSetIdentifier("a").WithProviderSpecific(providerSpecificFailover, "PRIMARY").WithProviderSpecific(providerSpecificHealthCheck, "https:443/healthz"),
+		endpoint.NewEndpoint("b.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").WithSetIdentifier("b").WithProviderSpecific(providerSpecificFailover, "PRIMARY").WithProviderSpecific(providerSpecificHealthCheck, "ICMP:0"),
+		endpoint.NewEndpoint("c.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").WithSetIdentifier("c").WithProviderSpecific(providerSpecificFailover, "PRIMARY").WithProviderSpecific(providerSpecificHealthCheck, "TCP:443").WithProviderSpecific(providerSpecificHealthCheckID, "abc"),
+	})
+	require.NoError(t, err)
+
+	validateEndpoints(t, provider, records, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").WithSetIdentifier("a").WithProviderSpecific(providerSpecificFailover, "PRIMARY").WithProviderSpecific(providerSpecificHealthCheck, "HTTPS:443/healthz"),
+		endpoint.NewEndpoint("b.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").WithSetIdentifier("b").WithProviderSpecific(providerSpecificFailover, "PRIMARY"),
+		endpoint.NewEndpoint("c.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").WithSetIdentifier("c").WithProviderSpecific(providerSpecificFailover, "PRIMARY").WithProviderSpecific(providerSpecificHealthCheckID, "abc"),
+	})
+}
+
+func TestAWSAdjustEndpointsHealthCheckDisabled(t *testing.T) {
+	provider, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
+
+	records, err := provider.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").WithSetIdentifier("a").WithProviderSpecific(providerSpecificFailover, "PRIMARY").WithProviderSpecific(providerSpecificHealthCheck, "HTTPS:443/healthz"),
+	})
+	require.NoError(t, err)
+
+	validateEndpoints(t, provider, records, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").WithSetIdentifier("a").WithProviderSpecific(providerSpecificFailover, "PRIMARY"),
+	})
+}
+
+func TestAWSHealthChecks(t *testing.T) {
+	provider, client := newAWSProviderWithHealthChecks(t)
+	ctx := context.Background()
+
+	// records of two clusters failing over to each other
+	primary := endpoint.NewEndpoint("failover.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").
+		WithSetIdentifier("cluster-1").
+		WithProviderSpecific(providerSpecificFailover, "PRIMARY").
+		WithProviderSpecific(providerSpecificHealthCheck, "HTTPS:443/healthz")
+	secondary := endpoint.NewEndpoint("failover.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeCNAME, "lb.cluster-2.example.com").
+		WithSetIdentifier("cluster-2").
+		WithProviderSpecific(providerSpecificFailover, "SECONDARY").
+		WithProviderSpecific(providerSpecificHealthCheck, "HTTP:80/")
+	desired, err := provider.AdjustEndpoints([]*endpoint.Endpoint{primary, secondary})
+	require.NoError(t, err)
+
+	_, err = provider.Records(ctx)
+	require.NoError(t, err)
+	require.NoError(t, provider.ApplyChanges(ctx, &plan.Changes{Create: desired}))
+
+	require.Len(t, client.healthChecks, 2)
+	var ipCheck, fqdnCheck *route53.HealthCheck
+	for _, hc := range client.healthChecks {
+		if hc.HealthCheckConfig.IPAddress != nil {
+			ipCheck = hc
+		} else {
+			fqdnCheck = hc
+		}
+	}
+	require.NotNil(t, ipCheck)
+	require.NotNil(t, fqdnCheck)
+	assert.Equal(t, &route53.HealthCheckConfig{
+		Type:         aws.String(route53.HealthCheckTypeHttps),
+		Port:         aws.Int64(443),
+		ResourcePath: aws.String("/healthz"),
+		IPAddress:    aws.String("1.2.3.4"),
+	}, ipCheck.HealthCheckConfig)
+	assert.Equal(t, &route53.HealthCheckConfig{
+		Type:                     aws.String(route53.HealthCheckTypeHttp),
+		Port:                     aws.Int64(80),
+		ResourcePath:             aws.String("/"),
+		FullyQualifiedDomainName: aws.String("lb.cluster-2.example.com"),
+		EnableSNI:                aws.Bool(false),
+	}, fqdnCheck.HealthCheckConfig)
+
+	validateRecords(t, listAWSRecords(t, provider.client, "/hostedzone/zone-1.ext-dns-test-2.teapot.zalan.do."), []*route53.ResourceRecordSet{
+		{
+			Name:            aws.String("failover.zone-1.ext-dns-test-2.teapot.zalan.do."),
+			Type:            aws.String(route53.RRTypeA),
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.4")}},
+			SetIdentifier:   aws.String("cluster-1"),
+			Failover:        aws.String("PRIMARY"),
+			HealthCheckId:   ipCheck.Id,
+		},
+		{
+			Name:            aws.String("failover.zone-1.ext-dns-test-2.teapot.zalan.do."),
+			Type:            aws.String(route53.RRTypeCname),
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("lb.cluster-2.example.com")}},
+			SetIdentifier:   aws.String("cluster-2"),
+			Failover:        aws.String("SECONDARY"),
+			HealthCheckId:   fqdnCheck.Id,
+		},
+	})
+
+	// the health checks are read back as they are desired, so that the records are not updated again
+	current, err := provider.Records(ctx)
+	require.NoError(t, err)
+	validateEndpoints(t, provider, current, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("failover.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, recordTTL, "1.2.3.4").
+			WithSetIdentifier("cluster-1").
+			WithProviderSpecific(providerSpecificFailover, "PRIMARY").
+			WithProviderSpecific(providerSpecificHealthCheck, "HTTPS:443/healthz"),
+		endpoint.NewEndpointWithTTL("failover.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeCNAME, recordTTL, "lb.cluster-2.example.com").
+			WithSetIdentifier("cluster-2").
+			WithProviderSpecific(providerSpecificAlias, "false").
+			WithProviderSpecific(providerSpecificFailover, "SECONDARY").
+			WithProviderSpecific(providerSpecificHealthCheck, "HTTP:80/"),
+	})
+
+	// the health checks are reused when the records are deleted, and kept during the grace period
+	require.NoError(t, provider.ApplyChanges(ctx, &plan.Changes{Delete: current}))
+	assert.Empty(t, listAWSRecords(t, provider.client, "/hostedzone/zone-1.ext-dns-test-2.teapot.zalan.do."))
+	_, err = provider.Records(ctx)
+	require.NoError(t, err)
+	assert.Len(t, client.healthChecks, 2)
+
+	// and deleted once the grace period has passed
+	for _, hc := range client.healthChecks {
+		hc.CallerReference = aws.String(healthCheckCallerReferencePrefix + "zone-1.ext-dns-test-2.teapot.zalan.do./" + strconv.FormatInt(time.Now().Add(-healthCheckGracePeriod-time.Minute).UnixNano(), 36))
+	}
+	_, err = provider.Records(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, client.healthChecks)
+}
+
+func TestAWSHealthChecksCollect(t *testing.T) {
+	provider, client := newAWSProviderWithHealthChecks(t)
+	ctx := context.Background()
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).UnixNano(), 36)
+
+	setAWSRecords(t, provider, []*route53.ResourceRecordSet{
+		{
+			Name:            aws.String("used.zone-1.ext-dns-test-2.teapot.zalan.do."),
+			Type:            aws.String(route53.RRTypeA),
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("1.2.3.4")}},
+			SetIdentifier:   aws.String("used"),
+			Failover:        aws.String("PRIMARY"),
+			HealthCheckId:   aws.String("used"),
+		},
+		{
+			Name:            aws.String("manual.zone-1.ext-dns-test-2.teapot.zalan.do."),
+			Type:            aws.String(route53.RRTypeA),
+			TTL:             aws.Int64(recordTTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String("5.6.7.8")}},
+			SetIdentifier:   aws.String("manual"),
+			Failover:        aws.String("PRIMARY"),
+			HealthCheckId:   aws.String("manual"),
+		},
+	})
+
+	client.healthChecks = map[string]*route53.HealthCheck{
+		// created by ExternalDNS and used by a record
+		"used": {
+			Id:                aws.String("used"),
+			CallerReference:   aws.String(healthCheckCallerReferencePrefix + "zone-1.ext-dns-test-2.teapot.zalan.do./" + old),
+			HealthCheckConfig: &route53.HealthCheckConfig{Type: aws.String(route53.HealthCheckTypeTcp), Port: aws.Int64(443), IPAddress: aws.String("1.2.3.4")},
+		},
+		// created by ExternalDNS and not used
+		"unused": {
+			Id:                aws.String("unused"),
+			CallerReference:   aws.String(healthCheckCallerReferencePrefix + "zone-1.ext-dns-test-2.teapot.zalan.do./" + old),
+			HealthCheckConfig: &route53.HealthCheckConfig{Type: aws.String(route53.HealthCheckTypeTcp), Port: aws.Int64(443), IPAddress: aws.String("5.6.7.8")},
+		},
+		// created by ExternalDNS for a hosted zone which isn't managed
+		"other-zone": {
+			Id:                aws.String("other-zone"),
+			CallerReference:   aws.String(healthCheckCallerReferencePrefix + "zone-4.ext-dns-test-3.teapot.zalan.do./" + old),
+			HealthCheckConfig: &route53.HealthCheckConfig{Type: aws.String(route53.HealthCheckTypeTcp), Port: aws.Int64(443), IPAddress: aws.String("5.6.7.8")},
+		},
+		// not created by ExternalDNS
+		"manual": {
+			Id:                aws.String("manual"),
+			CallerReference:   aws.String("manual"),
+			HealthCheckConfig: &route53.HealthCheckConfig{Type: aws.String(route53.HealthCheckTypeTcp), Port: aws.Int64(443), IPAddress: aws.String("5.6.7.8")},
+		},
+	}
+	records, err := provider.Records(ctx)
+	require.NoError(t, err)
+	validateEndpoints(t, provider, records, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("used.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, recordTTL, "1.2.3.4").
+			WithSetIdentifier("used").
+			WithProviderSpecific(providerSpecificFailover, "PRIMARY").
+			WithProviderSpecific(providerSpecificHealthCheck, "TCP:443"),
+		endpoint.NewEndpointWithTTL("manual.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, recordTTL, "5.6.7.8").
+			WithSetIdentifier("manual").
+			WithProviderSpecific(providerSpecificFailover, "PRIMARY").
+			WithProviderSpecific(providerSpecificHealthCheckID, "manual"),
+	})
+
+	ids := make([]string, 0, len(client.healthChecks))
+	for id := range client.healthChecks {
+		ids = append(ids, id)
+	}
+	assert.ElementsMatch(t, []string{"used", "other-zone", "manual"}, ids)
+}
+
+func TestAWSHealthChecksDryRun(t *testing.T) {
+	provider, client := newAWSProviderWithHealthChecks(t)
+	provider.dryRun = true
+	ctx := context.Background()
+
+	desired, err := provider.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("failover.zone-1.ext-dns-test-2.teapot.zalan.do", endpoint.RecordTypeA, "1.2.3.4").
+			WithSetIdentifier("cluster-1").
+			WithProviderSpecific(providerSpecificFailover, "PRIMARY").
+			WithProviderSpecific(providerSpecificHealthCheck, "TCP:443"),
+	})
+	require.NoError(t, err)
+
+	_, err = provider.Records(ctx)
+	require.NoError(t, err)
+	require.NoError(t, provider.ApplyChanges(ctx, &plan.Changes{Create: desired}))
+	assert.Empty(t, client.healthChecks)
+}
+
+func newAWSProviderWithHealthChecks(t *testing.T) (*AWSProvider, *Route53APIStub) {
+	provider, client := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), defaultEvaluateTargetHealth, false, nil)
+	provider.healthChecks = newHealthChecks()
+	return provider, client
+}
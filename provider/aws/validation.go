@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+const (
+	// route53MaxBatchRecords is the maximum number of resource records in a change batch
+	route53MaxBatchRecords = 1000
+	// route53MaxBatchValueChars is the maximum number of characters of the record values in a change batch
+	route53MaxBatchValueChars = 32000
+)
+
+// validateChanges reports the changes Route53 would reject, without submitting them.
+// Route53 has no endpoint validating a change batch without applying it, so the rules it
+// enforces on change batches are checked against the records of the zones.
+func (p *AWSProvider) validateChanges(ctx context.Context, changes *plan.Changes, combinedChanges Route53Changes, zones map[string]*route53.HostedZone) error {
+	records, err := p.records(ctx, zones)
+	if err != nil {
+		return fmt.Errorf("failed to list records to validate the changes: %w", err)
+	}
+	zoneNames := make([]string, 0, len(zones))
+	for _, z := range zones {
+		zoneNames = append(zoneNames, aws.StringValue(z.Name))
+	}
+	errs := []error{provider.ValidateChanges(changes, records, zoneNames)}
+
+	for z, cs := range changesByZone(zones, combinedChanges) {
+		for _, b := range batchChangeSet(cs, p.batchChangeSize) {
+			if err := validateBatch(b); err != nil {
+				errs = append(errs, fmt.Errorf("zone %s [Id: %s]: %w", aws.StringValue(zones[z].Name), z, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateBatch checks the size limits of a change batch, UPSERT changes count twice.
+func validateBatch(b Route53Changes) error {
+	records, chars := 0, 0
+	for _, c := range b {
+		weight := 1
+		if aws.StringValue(c.Action) == route53.ChangeActionUpsert {
+			weight = 2
+		}
+		for _, rr := range c.ResourceRecordSet.ResourceRecords {
+			records += weight
+			chars += weight * len(aws.StringValue(rr.Value))
+		}
+	}
+	if records > route53MaxBatchRecords {
+		return fmt.Errorf("change batch has %d resource records, more than the maximum of %d", records, route53MaxBatchRecords)
+	}
+	if chars > route53MaxBatchValueChars {
+		return fmt.Errorf("change batch has %d characters of record values, more than the maximum of %d", chars, route53MaxBatchValueChars)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// EnsureZone creates the hosted zone unless it exists, adds the tags to it and returns its name servers
+// and the DS records of its active key signing keys. Zones are created with the reference as caller
+// reference, which identifies the zones created for it.
+func (p *AWSProvider) EnsureZone(ctx context.Context, spec provider.ZoneSpec) (provider.ZoneStatus, error) {
+	zone, err := p.findZone(ctx, spec)
+	if err != nil {
+		return provider.ZoneStatus{}, err
+	}
+	if zone == nil {
+		if p.dryRun {
+			log.Infof("Would create hosted zone %s", spec.DomainName)
+			return provider.ZoneStatus{}, nil
+		}
+		if zone, err = p.createZone(ctx, spec); err != nil {
+			return provider.ZoneStatus{}, err
+		}
+	}
+	zoneID := aws.StringValue(zone.Id)
+
+	if err := p.tagZone(ctx, zoneID, spec.Tags); err != nil {
+		return provider.ZoneStatus{}, err
+	}
+
+	status := provider.ZoneStatus{ID: zoneID}
+	output, err := p.client.GetHostedZoneWithContext(ctx, &route53.GetHostedZoneInput{Id: zone.Id})
+	if err != nil {
+		return status, errors.Wrapf(err, "failed to get hosted zone %s", zoneID)
+	}
+	if output.DelegationSet != nil {
+		status.NameServers = aws.StringValueSlice(output.DelegationSet.NameServers)
+	}
+	if spec.Private {
+		// private zones cannot be signed
+		return status, nil
+	}
+	signer, err := p.delegationSigner(ctx, zone)
+	if err != nil {
+		return status, err
+	}
+	status.DSRecords = signer.DSRecords
+	return status, nil
+}
+
+// DelegationSigners returns the DS and DNSKEY records of the active key signing keys of the public hosted zones
+// signed with DNSSEC.
+func (p *AWSProvider) DelegationSigners(ctx context.Context) ([]provider.DelegationSigner, error) {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var signers []provider.DelegationSigner
+	for _, zone := range zones {
+		if isPrivateZone(zone) {
+			// private zones cannot be signed
+			continue
+		}
+		signer, err := p.delegationSigner(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+		if len(signer.DSRecords) > 0 {
+			signers = append(signers, signer)
+		}
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return signers[i].ID < signers[j].ID
+	})
+	return signers, nil
+}
+
+// delegationSigner returns the DS and DNSKEY records of the active key signing keys of the zone.
+func (p *AWSProvider) delegationSigner(ctx context.Context, zone *route53.HostedZone) (provider.DelegationSigner, error) {
+	signer := provider.DelegationSigner{
+		Zone: strings.TrimSuffix(aws.StringValue(zone.Name), "."),
+		ID:   aws.StringValue(zone.Id),
+	}
+	dnssec, err := p.client.GetDNSSECWithContext(ctx, &route53.GetDNSSECInput{HostedZoneId: zone.Id})
+	if err != nil {
+		return signer, errors.Wrapf(err, "failed to get DNSSEC of hosted zone %s", signer.ID)
+	}
+	for _, key := range dnssec.KeySigningKeys {
+		if aws.StringValue(key.Status) == "ACTIVE" && aws.StringValue(key.DSRecord) != "" {
+			signer.DSRecords = append(signer.DSRecords, aws.StringValue(key.DSRecord))
+			if aws.StringValue(key.DNSKEYRecord) != "" {
+				signer.DNSKEYRecords = append(signer.DNSKEYRecords, aws.StringValue(key.DNSKEYRecord))
+			}
+		}
+	}
+	return signer, nil
+}
+
+// findZone returns the hosted zone of the spec, or nil if there is none. Hosted zones of the same name and
+// visibility created for other references are not returned, but reported with provider.ErrZoneNotOwned.
+func (p *AWSProvider) findZone(ctx context.Context, spec provider.ZoneSpec) (*route53.HostedZone, error) {
+	name := provider.EnsureTrailingDot(strings.ToLower(spec.DomainName))
+	var found, other *route53.HostedZone
+	err := p.client.ListHostedZonesPagesWithContext(ctx, &route53.ListHostedZonesInput{}, func(resp *route53.ListHostedZonesOutput, lastPage bool) bool {
+		for _, zone := range resp.HostedZones {
+			if !strings.EqualFold(aws.StringValue(zone.Name), name) || isPrivateZone(zone) != spec.Private {
+				continue
+			}
+			if aws.StringValue(zone.CallerReference) == spec.Reference {
+				found = zone
+				return false
+			}
+			other = zone
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list hosted zones")
+	}
+	if found == nil && other != nil {
+		return nil, fmt.Errorf("%w: %s", provider.ErrZoneNotOwned, aws.StringValue(other.Id))
+	}
+	return found, nil
+}
+
+func isPrivateZone(zone *route53.HostedZone) bool {
+	return zone.Config != nil && aws.BoolValue(zone.Config.PrivateZone)
+}
+
+func (p *AWSProvider) createZone(ctx context.Context, spec provider.ZoneSpec) (*route53.HostedZone, error) {
+	input := &route53.CreateHostedZoneInput{
+		Name:            aws.String(provider.EnsureTrailingDot(strings.ToLower(spec.DomainName))),
+		CallerReference: aws.String(spec.Reference),
+		HostedZoneConfig: &route53.HostedZoneConfig{
+			Comment:     aws.String(spec.Comment),
+			PrivateZone: aws.Bool(spec.Private),
+		},
+	}
+	if spec.Private {
+		region, vpcID, ok := strings.Cut(spec.Network, ":")
+		if !ok || region == "" || vpcID == "" {
+			return nil, fmt.Errorf("the network of private hosted zone %s must be given as <region>:<VPC ID>, got %q", spec.DomainName, spec.Network)
+		}
+		input.VPC = &route53.VPC{VPCRegion: aws.String(region), VPCId: aws.String(vpcID)}
+	}
+	output, err := p.client.CreateHostedZoneWithContext(ctx, input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create hosted zone %s", spec.DomainName)
+	}
+	log.Infof("Created hosted zone %s (%s)", spec.DomainName, aws.StringValue(output.HostedZone.Id))
+	// the new zone has to be listed by the next synchronization
+	p.zonesCache.zones = nil
+	return output.HostedZone, nil
+}
+
+// tagZone adds the tags the zone doesn't have yet or with another value.
+func (p *AWSProvider) tagZone(ctx context.Context, zoneID string, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	current, err := p.tagsForZone(ctx, zoneID)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(tags))
+	for key, value := range tags {
+		if current[key] != value {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	add := make([]*route53.Tag, 0, len(keys))
+	for _, key := range keys {
+		add = append(add, &route53.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	if p.dryRun {
+		log.Infof("Would tag hosted zone %s with %v", zoneID, keys)
+		return nil
+	}
+	_, err = p.client.ChangeTagsForResourceWithContext(ctx, &route53.ChangeTagsForResourceInput{
+		ResourceType: aws.String(route53.TagResourceTypeHostedzone),
+		ResourceId:   aws.String(zoneID),
+		AddTags:      add,
+	})
+	return errors.Wrapf(err, "failed to tag hosted zone %s", zoneID)
+}
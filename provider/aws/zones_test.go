@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+func TestAWSEnsureZone(t *testing.T) {
+	p, client := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), false, false, nil)
+	counter := NewRoute53APICounter(client)
+	p.client = counter
+	spec := provider.ZoneSpec{
+		DomainName: "tenant.ext-dns-test-2.teapot.zalan.do",
+		Tags:       map[string]string{"tenant": "a"},
+		Comment:    "tenant a",
+		Reference:  "uid-1",
+	}
+
+	status, err := p.EnsureZone(context.Background(), spec)
+	require.NoError(t, err)
+	assert.Equal(t, provider.ZoneStatus{
+		ID:          "/hostedzone/tenant.ext-dns-test-2.teapot.zalan.do.",
+		NameServers: []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.com"},
+	}, status)
+	zone := client.zones[status.ID]
+	require.NotNil(t, zone)
+	assert.Equal(t, "uid-1", aws.StringValue(zone.CallerReference))
+	assert.Equal(t, "tenant a", aws.StringValue(zone.Config.Comment))
+	assert.Equal(t, []*route53.Tag{{Key: aws.String("tenant"), Value: aws.String("a")}}, client.zoneTags[status.ID])
+
+	// the zone is not created again, and the tags are only changed when they differ
+	client.keySigningKeys = map[string][]*route53.KeySigningKey{status.ID: {
+		{Status: aws.String("ACTIVE"), DSRecord: aws.String("12345 13 2 ABCDEF")},
+		{Status: aws.String("INACTIVE"), DSRecord: aws.String("54321 13 2 FEDCBA")},
+	}}
+	status, err = p.EnsureZone(context.Background(), spec)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12345 13 2 ABCDEF"}, status.DSRecords)
+	assert.Equal(t, 1, counter.calls["CreateHostedZone"])
+	assert.Equal(t, 1, counter.calls["ChangeTagsForResource"])
+
+	spec.Tags["tenant"] = "b"
+	_, err = p.EnsureZone(context.Background(), spec)
+	require.NoError(t, err)
+	assert.Equal(t, []*route53.Tag{{Key: aws.String("tenant"), Value: aws.String("b")}}, client.zoneTags[status.ID])
+	assert.Equal(t, 2, counter.calls["ChangeTagsForResource"])
+}
+
+func TestAWSEnsureZoneNotOwned(t *testing.T) {
+	p, _ := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), false, false, nil)
+
+	_, err := p.EnsureZone(context.Background(), provider.ZoneSpec{DomainName: "zone-1.ext-dns-test-2.teapot.zalan.do", Reference: "uid-1"})
+	assert.ErrorIs(t, err, provider.ErrZoneNotOwned)
+}
+
+func TestAWSEnsureZonePrivate(t *testing.T) {
+	p, client := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), false, false, nil)
+	spec := provider.ZoneSpec{
+		DomainName: "internal.ext-dns-test-2.teapot.zalan.do",
+		Private:    true,
+		Reference:  "uid-1",
+	}
+
+	_, err := p.EnsureZone(context.Background(), spec)
+	assert.Error(t, err, "the network of private zones is required")
+
+	spec.Network = "eu-central-1:vpc-1"
+	status, err := p.EnsureZone(context.Background(), spec)
+	require.NoError(t, err)
+	assert.Empty(t, status.NameServers)
+	assert.True(t, aws.BoolValue(client.zones[status.ID].Config.PrivateZone))
+}
+
+func TestAWSEnsureZoneDryRun(t *testing.T) {
+	p, client := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), false, true, nil)
+
+	status, err := p.EnsureZone(context.Background(), provider.ZoneSpec{DomainName: "tenant.ext-dns-test-2.teapot.zalan.do", Reference: "uid-1"})
+	require.NoError(t, err)
+	assert.Empty(t, status.ID)
+	assert.Len(t, client.zones, 4)
+}
+
+func TestAWSDelegationSigners(t *testing.T) {
+	p, client := newAWSProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.teapot.zalan.do."}), provider.NewZoneIDFilter([]string{}), provider.NewZoneTypeFilter(""), false, false, nil)
+	client.keySigningKeys = map[string][]*route53.KeySigningKey{
+		"/hostedzone/zone-1.ext-dns-test-2.teapot.zalan.do.": {
+			{Status: aws.String("ACTIVE"), DSRecord: aws.String("12345 13 2 ABCDEF"), DNSKEYRecord: aws.String("257 3 13 AAAA")},
+			{Status: aws.String("INACTIVE"), DSRecord: aws.String("54321 13 2 FEDCBA"), DNSKEYRecord: aws.String("257 3 13 BBBB")},
+		},
+		"/hostedzone/zone-2.ext-dns-test-2.teapot.zalan.do.": {
+			{Status: aws.String("INACTIVE"), DSRecord: aws.String("54321 13 2 FEDCBA"), DNSKEYRecord: aws.String("257 3 13 BBBB")},
+		},
+	}
+
+	signers, err := p.DelegationSigners(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []provider.DelegationSigner{{
+		Zone:          "zone-1.ext-dns-test-2.teapot.zalan.do",
+		ID:            "/hostedzone/zone-1.ext-dns-test-2.teapot.zalan.do.",
+		DSRecords:     []string{"12345 13 2 ABCDEF"},
+		DNSKEYRecords: []string{"257 3 13 AAAA"},
+	}}, signers)
+}
@@ -55,6 +55,7 @@ type AzureProvider struct {
 	domainFilter                 endpoint.DomainFilter
 	zoneNameFilter               endpoint.DomainFilter
 	zoneIDFilter                 provider.ZoneIDFilter
+	zoneTagFilter                provider.ZoneTagFilter
 	dryRun                       bool
 	resourceGroup                string
 	userAssignedIdentityClientID string
@@ -65,7 +66,7 @@ type AzureProvider struct {
 // NewAzureProvider creates a new Azure provider.
 //
 // Returns the provider or an error if a provider could not be created.
-func NewAzureProvider(configFile string, domainFilter endpoint.DomainFilter, zoneNameFilter endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, resourceGroup string, userAssignedIdentityClientID string, dryRun bool) (*AzureProvider, error) {
+func NewAzureProvider(configFile string, domainFilter endpoint.DomainFilter, zoneNameFilter endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, zoneTagFilter provider.ZoneTagFilter, resourceGroup string, userAssignedIdentityClientID string, dryRun bool) (*AzureProvider, error) {
 	cfg, err := getConfig(configFile, resourceGroup, userAssignedIdentityClientID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read Azure config file '%s': %v", configFile, err)
@@ -87,6 +88,7 @@ func NewAzureProvider(configFile string, domainFilter endpoint.DomainFilter, zon
 		domainFilter:                 domainFilter,
 		zoneNameFilter:               zoneNameFilter,
 		zoneIDFilter:                 zoneIDFilter,
+		zoneTagFilter:                zoneTagFilter,
 		dryRun:                       dryRun,
 		resourceGroup:                cfg.ResourceGroup,
 		userAssignedIdentityClientID: cfg.UserAssignedIdentityID,
@@ -173,6 +175,9 @@ func (p *AzureProvider) zones(ctx context.Context) ([]dns.Zone, error) {
 			return nil, err
 		}
 		for _, zone := range nextResult.Value {
+			if !p.zoneTagFilter.Match(azureTags(zone.Tags)) {
+				continue
+			}
 			if zone.Name != nil && p.domainFilter.Match(*zone.Name) && p.zoneIDFilter.Match(*zone.ID) {
 				zones = append(zones, *zone)
 			} else if zone.Name != nil && len(p.zoneNameFilter.Filters) > 0 && p.zoneNameFilter.Match(*zone.Name) {
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	dns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/internal/testutils"
@@ -245,6 +246,25 @@ func validateAzureEndpoints(t *testing.T, endpoints []*endpoint.Endpoint, expect
 	assert.True(t, testutils.SameEndpoints(endpoints, expected), "expected and actual endpoints don't match. %s:%s", endpoints, expected)
 }
 
+func TestAzureZonesTagFilter(t *testing.T) {
+	zoneTagFilter := provider.NewZoneTagFilter([]string{"team=platform", "public"})
+	provider, err := newMockedAzureProvider(endpoint.NewDomainFilter([]string{""}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), true, "k8s", "",
+		[]*dns.Zone{
+			{ID: to.Ptr("/dnszones/example.com"), Name: to.Ptr("example.com"), Tags: map[string]*string{"team": to.Ptr("platform"), "public": nil}},
+			{ID: to.Ptr("/dnszones/example.org"), Name: to.Ptr("example.org"), Tags: map[string]*string{"team": to.Ptr("platform")}},
+			{ID: to.Ptr("/dnszones/example.net"), Name: to.Ptr("example.net"), Tags: map[string]*string{"team": to.Ptr("other"), "public": to.Ptr("true")}},
+			createMockZone("example.io", "/dnszones/example.io"),
+		},
+		[]*dns.RecordSet{})
+	require.NoError(t, err)
+	provider.zoneTagFilter = zoneTagFilter
+
+	zones, err := provider.zones(context.Background())
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	assert.Equal(t, "example.com", *zones[0].Name)
+}
+
 func TestAzureRecord(t *testing.T) {
 	provider, err := newMockedAzureProvider(endpoint.NewDomainFilter([]string{"example.com"}), endpoint.NewDomainFilter([]string{}), provider.NewZoneIDFilter([]string{""}), true, "k8s", "",
 		[]*dns.Zone{
@@ -45,3 +45,16 @@ func parseMxTarget[T dns.MxRecord | privatedns.MxRecord](mxTarget string) (T, er
 		Exchange:   to.Ptr(exchange),
 	}, nil
 }
+
+// azureTags returns the tags of an Azure resource, so that they can be matched against the zone tag filter.
+func azureTags(tags map[string]*string) map[string]string {
+	result := make(map[string]string, len(tags))
+	for key, value := range tags {
+		if value != nil {
+			result[key] = *value
+		} else {
+			result[key] = ""
+		}
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// ValidateChanges checks the changes against the rules DNS servers enforce when changing the
+// records of a zone, so that a dry run reports the changes the provider would reject:
+// creating an existing record, deleting or updating a missing one, a CNAME record at a zone
+// apex or next to other records of the same name.
+// The current records are the records of the zones before the changes are applied.
+func ValidateChanges(changes *plan.Changes, current []*endpoint.Endpoint, zones []string) error {
+	records := make(map[endpoint.EndpointKey]*endpoint.Endpoint, len(current))
+	for _, r := range current {
+		records[validationKey(r)] = r
+	}
+
+	var errs []error
+	for _, e := range changes.Delete {
+		key := validationKey(e)
+		if _, ok := records[key]; !ok {
+			errs = append(errs, fmt.Errorf("cannot delete %s %s: the record does not exist", e.RecordType, e.DNSName))
+			continue
+		}
+		delete(records, key)
+	}
+	for _, e := range changes.UpdateOld {
+		key := validationKey(e)
+		if _, ok := records[key]; !ok {
+			errs = append(errs, fmt.Errorf("cannot update %s %s: the record does not exist", e.RecordType, e.DNSName))
+			continue
+		}
+		delete(records, key)
+	}
+	for _, e := range changes.UpdateNew {
+		records[validationKey(e)] = e
+	}
+	for _, e := range changes.Create {
+		key := validationKey(e)
+		if _, ok := records[key]; ok {
+			errs = append(errs, fmt.Errorf("cannot create %s %s: the record already exists", e.RecordType, e.DNSName))
+			continue
+		}
+		records[key] = e
+	}
+
+	apexes := make(map[string]bool, len(zones))
+	for _, z := range zones {
+		apexes[validationName(z)] = true
+	}
+	typesByName := map[string]map[string]bool{}
+	for key := range records {
+		if typesByName[key.DNSName] == nil {
+			typesByName[key.DNSName] = map[string]bool{}
+		}
+		typesByName[key.DNSName][key.RecordType] = true
+	}
+	names := make([]string, 0, len(typesByName))
+	for name, types := range typesByName {
+		if types[endpoint.RecordTypeCNAME] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if apexes[name] {
+			errs = append(errs, fmt.Errorf("CNAME %s is not permitted at the apex of the zone", name))
+		}
+		if len(typesByName[name]) > 1 {
+			errs = append(errs, fmt.Errorf("CNAME %s is not permitted next to other records of the same name", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validationKey(e *endpoint.Endpoint) endpoint.EndpointKey {
+	return endpoint.EndpointKey{
+		DNSName:       validationName(e.DNSName),
+		RecordType:    e.RecordType,
+		SetIdentifier: e.SetIdentifier,
+	}
+}
+
+func validationName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+func TestValidateChanges(t *testing.T) {
+	current := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeTXT, "text"),
+		endpoint.NewEndpoint("weighted.example.org", endpoint.RecordTypeCNAME, "lb-1.example.com").WithSetIdentifier("1"),
+	}
+	zones := []string{"example.org."}
+
+	for _, tc := range []struct {
+		title   string
+		changes *plan.Changes
+		errs    []string
+	}{
+		{
+			title: "valid changes",
+			changes: &plan.Changes{
+				Create: []*endpoint.Endpoint{
+					endpoint.NewEndpoint("c.example.org", endpoint.RecordTypeCNAME, "lb.example.com"),
+					endpoint.NewEndpoint("weighted.example.org", endpoint.RecordTypeCNAME, "lb-2.example.com").WithSetIdentifier("2"),
+				},
+				UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1")},
+				UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.2")},
+			},
+		},
+		{
+			title: "record replaced by a CNAME in the same changes",
+			changes: &plan.Changes{
+				Create: []*endpoint.Endpoint{endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeCNAME, "lb.example.com")},
+				Delete: []*endpoint.Endpoint{
+					endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+					endpoint.NewEndpoint("B.example.org.", endpoint.RecordTypeTXT, "text"),
+				},
+			},
+		},
+		{
+			title: "missing and existing records",
+			changes: &plan.Changes{
+				Create:    []*endpoint.Endpoint{endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1")},
+				UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpoint("missing.example.org", endpoint.RecordTypeA, "1.1.1.1")},
+				UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpoint("missing.example.org", endpoint.RecordTypeA, "1.1.1.2")},
+				Delete:    []*endpoint.Endpoint{endpoint.NewEndpoint("gone.example.org", endpoint.RecordTypeA, "1.1.1.1")},
+			},
+			errs: []string{
+				"cannot create A a.example.org: the record already exists",
+				"cannot update A missing.example.org: the record does not exist",
+				"cannot delete A gone.example.org: the record does not exist",
+			},
+		},
+		{
+			title: "CNAME conflicts",
+			changes: &plan.Changes{
+				Create: []*endpoint.Endpoint{
+					endpoint.NewEndpoint("example.org", endpoint.RecordTypeCNAME, "lb.example.com"),
+					endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeCNAME, "lb.example.com"),
+				},
+			},
+			errs: []string{
+				"CNAME example.org is not permitted at the apex of the zone",
+				"CNAME b.example.org is not permitted next to other records of the same name",
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			err := ValidateChanges(tc.changes, current, zones)
+			if len(tc.errs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, msg := range tc.errs {
+				assert.ErrorContains(t, err, msg)
+			}
+		})
+	}
+}
@@ -122,7 +122,9 @@ type CloudFlareProvider struct {
 	// only consider hosted zones managing domains ending in this suffix
 	domainFilter      endpoint.DomainFilter
 	zoneIDFilter      provider.ZoneIDFilter
+	zoneTagFilter     provider.ZoneTagFilter
 	proxiedByDefault  bool
+	tunnelIDByDefault string
 	DryRun            bool
 	DNSRecordsPerPage int
 }
@@ -161,7 +163,7 @@ func getCreateDNSRecordParam(cfc cloudFlareChange) cloudflare.CreateDNSRecordPar
 }
 
 // NewCloudFlareProvider initializes a new CloudFlare DNS based Provider.
-func NewCloudFlareProvider(domainFilter endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, proxiedByDefault bool, dryRun bool, dnsRecordsPerPage int) (*CloudFlareProvider, error) {
+func NewCloudFlareProvider(domainFilter endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, zoneTagFilter provider.ZoneTagFilter, proxiedByDefault bool, tunnelIDByDefault string, dryRun bool, dnsRecordsPerPage int) (*CloudFlareProvider, error) {
 	// initialize via chosen auth method and returns new API object
 	var (
 		config *cloudflare.API
@@ -183,12 +185,19 @@ func NewCloudFlareProvider(domainFilter endpoint.DomainFilter, zoneIDFilter prov
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize cloudflare provider: %v", err)
 	}
+	if tunnelIDByDefault != "" {
+		if _, err := tunnelTarget(tunnelIDByDefault); err != nil {
+			return nil, err
+		}
+	}
 	provider := &CloudFlareProvider{
 		// Client: config,
 		Client:            zoneService{config},
 		domainFilter:      domainFilter,
 		zoneIDFilter:      zoneIDFilter,
+		zoneTagFilter:     zoneTagFilter,
 		proxiedByDefault:  proxiedByDefault,
+		tunnelIDByDefault: tunnelIDByDefault,
 		DryRun:            dryRun,
 		DNSRecordsPerPage: dnsRecordsPerPage,
 	}
@@ -210,6 +219,10 @@ func (p *CloudFlareProvider) Zones(ctx context.Context) ([]cloudflare.Zone, erro
 				log.Errorf("zone %s lookup failed, %v", zoneID, err)
 				return result, err
 			}
+			if !p.zoneTagFilter.Match(zoneTags(detailResponse)) {
+				log.Debugf("zone %s not in zone tag filter", detailResponse.Name)
+				continue
+			}
 			log.WithFields(log.Fields{
 				"zoneName": detailResponse.Name,
 				"zoneID":   detailResponse.ID,
@@ -231,12 +244,32 @@ func (p *CloudFlareProvider) Zones(ctx context.Context) ([]cloudflare.Zone, erro
 			log.Debugf("zone %s not in domain filter", zone.Name)
 			continue
 		}
+		if !p.zoneTagFilter.Match(zoneTags(zone)) {
+			log.Debugf("zone %s not in zone tag filter", zone.Name)
+			continue
+		}
 		result = append(result, zone)
 	}
 
 	return result, nil
 }
 
+// zoneTags returns the tags the zone tag filter matches the zone against. Cloudflare zones can't
+// be tagged, so their account and type are used instead.
+func zoneTags(zone cloudflare.Zone) map[string]string {
+	tags := map[string]string{}
+	for key, value := range map[string]string{
+		"account-id":   zone.Account.ID,
+		"account-name": zone.Account.Name,
+		"type":         zone.Type,
+	} {
+		if value != "" {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
 // Records returns the list of records.
 func (p *CloudFlareProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	zones, err := p.Zones(ctx)
@@ -370,7 +403,16 @@ func (p *CloudFlareProvider) submitChanges(ctx context.Context, changes []*cloud
 // AdjustEndpoints modifies the endpoints as needed by the specific provider
 func (p *CloudFlareProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
 	adjustedEndpoints := []*endpoint.Endpoint{}
+	tunneled := map[endpoint.EndpointKey]bool{}
 	for _, e := range endpoints {
+		if p.routeThroughTunnel(e) {
+			// A and AAAA records of the same name collapse into a single tunnel CNAME
+			key := e.Key()
+			if tunneled[key] {
+				continue
+			}
+			tunneled[key] = true
+		}
 		proxied := shouldBeProxied(e, p.proxiedByDefault)
 		if proxied {
 			e.RecordTTL = 0
@@ -638,6 +638,32 @@ func TestCloudFlareZonesWithIDFilter(t *testing.T) {
 	assert.Equal(t, "bar.com", zones[0].Name)
 }
 
+func TestCloudFlareZonesWithTagFilter(t *testing.T) {
+	provider := &CloudFlareProvider{
+		Client:        NewMockCloudFlareClient(),
+		domainFilter:  endpoint.NewDomainFilter([]string{"bar.com", "foo.com"}),
+		zoneIDFilter:  provider.NewZoneIDFilter([]string{""}),
+		zoneTagFilter: provider.NewZoneTagFilter([]string{"type=full"}),
+	}
+
+	zones, err := provider.Zones(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the zones of the mock have no type
+	assert.Empty(t, zones)
+
+	tags := zoneTags(cloudflare.Zone{
+		Name:    "bar.com",
+		Type:    "full",
+		Account: cloudflare.Account{ID: "42", Name: "platform"},
+	})
+	assert.Equal(t, map[string]string{"account-id": "42", "account-name": "platform", "type": "full"}, tags)
+	assert.True(t, provider.zoneTagFilter.Match(tags))
+	assert.False(t, provider.zoneTagFilter.Match(zoneTags(cloudflare.Zone{Name: "foo.com", Type: "partial"})))
+}
+
 func TestCloudflareRecords(t *testing.T) {
 	client := NewMockCloudFlareClientWithRecords(map[string][]cloudflare.DNSRecord{
 		"001": ExampleDomain,
@@ -674,7 +700,9 @@ func TestCloudflareProvider(t *testing.T) {
 	_, err := NewCloudFlareProvider(
 		endpoint.NewDomainFilter([]string{"bar.com"}),
 		provider.NewZoneIDFilter([]string{""}),
+		provider.NewZoneTagFilter([]string{""}),
 		false,
+		"",
 		true,
 		5000)
 	if err != nil {
@@ -690,7 +718,9 @@ func TestCloudflareProvider(t *testing.T) {
 	_, err = NewCloudFlareProvider(
 		endpoint.NewDomainFilter([]string{"bar.com"}),
 		provider.NewZoneIDFilter([]string{""}),
+		provider.NewZoneTagFilter([]string{""}),
 		false,
+		"",
 		true,
 		5000)
 	if err != nil {
@@ -703,7 +733,9 @@ func TestCloudflareProvider(t *testing.T) {
 	_, err = NewCloudFlareProvider(
 		endpoint.NewDomainFilter([]string{"bar.com"}),
 		provider.NewZoneIDFilter([]string{""}),
+		provider.NewZoneTagFilter([]string{""}),
 		false,
+		"",
 		true,
 		5000)
 	if err != nil {
@@ -715,7 +747,9 @@ func TestCloudflareProvider(t *testing.T) {
 	_, err = NewCloudFlareProvider(
 		endpoint.NewDomainFilter([]string{"bar.com"}),
 		provider.NewZoneIDFilter([]string{""}),
+		provider.NewZoneTagFilter([]string{""}),
 		false,
+		"",
 		true,
 		5000)
 	if err == nil {
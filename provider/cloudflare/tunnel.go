@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/source"
+)
+
+// cloudFlareTunnelDomain is the domain under which Cloudflare publishes the CNAME target of every tunnel.
+const cloudFlareTunnelDomain = "cfargotunnel.com"
+
+// tunnelCredentials is the subset of the cloudflared credentials file needed to address the tunnel.
+type tunnelCredentials struct {
+	TunnelID string `json:"TunnelID"`
+}
+
+// ReadTunnelID returns the tunnel ID stored in a cloudflared credentials file.
+func ReadTunnelID(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cloudflare tunnel credentials: %w", err)
+	}
+	var credentials tunnelCredentials
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return "", fmt.Errorf("failed to parse cloudflare tunnel credentials %s: %w", path, err)
+	}
+	if credentials.TunnelID == "" {
+		return "", fmt.Errorf("cloudflare tunnel credentials %s do not contain a TunnelID", path)
+	}
+	return credentials.TunnelID, nil
+}
+
+// tunnelTarget returns the CNAME target routing traffic to the given tunnel.
+func tunnelTarget(tunnelID string) (string, error) {
+	id, err := uuid.Parse(tunnelID)
+	if err != nil {
+		return "", fmt.Errorf("invalid cloudflare tunnel ID %q: %w", tunnelID, err)
+	}
+	return fmt.Sprintf("%s.%s", id.String(), cloudFlareTunnelDomain), nil
+}
+
+// tunnelID returns the tunnel the endpoint should be routed through, if any.
+// The annotation takes precedence over the tunnel configured for the provider.
+func (p *CloudFlareProvider) tunnelID(e *endpoint.Endpoint) string {
+	if v, ok := e.GetProviderSpecificProperty(source.CloudflareTunnelIDKey); ok {
+		return v
+	}
+	return p.tunnelIDByDefault
+}
+
+// routeThroughTunnel rewrites address records to a proxied CNAME pointing at the endpoint's tunnel.
+// It returns false when the endpoint is not routed through a tunnel.
+func (p *CloudFlareProvider) routeThroughTunnel(e *endpoint.Endpoint) bool {
+	tunnelID := p.tunnelID(e)
+	if tunnelID == "" {
+		return false
+	}
+	switch e.RecordType {
+	case endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME:
+	default:
+		return false
+	}
+	target, err := tunnelTarget(tunnelID)
+	if err != nil {
+		log.Errorf("Not routing %s through a tunnel: %v", e.DNSName, err)
+		return false
+	}
+	e.RecordType = endpoint.RecordTypeCNAME
+	e.Targets = endpoint.Targets{target}
+	e.DeleteProviderSpecificProperty(source.CloudflareTunnelIDKey)
+	e.SetProviderSpecificProperty(source.CloudflareProxiedKey, "true")
+	return true
+}
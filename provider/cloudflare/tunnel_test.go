@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudflare
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/source"
+)
+
+const testTunnelID = "c1f0b6d2-3f5e-4a8b-9c7d-2e1f0a9b8c7d"
+
+func TestReadTunnelID(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"AccountTag":"abc","TunnelSecret":"c2VjcmV0","TunnelID":"`+testTunnelID+`"}`), 0o600))
+	id, err := ReadTunnelID(valid)
+	require.NoError(t, err)
+	assert.Equal(t, testTunnelID, id)
+
+	missingID := filepath.Join(dir, "missing-id.json")
+	require.NoError(t, os.WriteFile(missingID, []byte(`{"AccountTag":"abc"}`), 0o600))
+	_, err = ReadTunnelID(missingID)
+	assert.Error(t, err)
+
+	_, err = ReadTunnelID(filepath.Join(dir, "absent.json"))
+	assert.Error(t, err)
+}
+
+func TestCloudflareAdjustEndpointsTunnel(t *testing.T) {
+	p := &CloudFlareProvider{tunnelIDByDefault: testTunnelID}
+	other := "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d"
+
+	endpoints, err := p.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("a.bar.com", endpoint.RecordTypeA, 300, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("a.bar.com", endpoint.RecordTypeAAAA, 300, "2001:db8::1"),
+		endpoint.NewEndpoint("b.bar.com", endpoint.RecordTypeA, "1.2.3.4").
+			WithProviderSpecific(source.CloudflareTunnelIDKey, other).
+			WithProviderSpecific(source.CloudflareProxiedKey, "false"),
+		endpoint.NewEndpoint("c.bar.com", endpoint.RecordTypeA, "1.2.3.4").
+			WithProviderSpecific(source.CloudflareTunnelIDKey, "not-a-uuid"),
+		endpoint.NewEndpoint("d.bar.com", endpoint.RecordTypeTXT, "text"),
+	})
+	require.NoError(t, err)
+	require.Len(t, endpoints, 4)
+
+	assert.Equal(t, endpoint.RecordTypeCNAME, endpoints[0].RecordType)
+	assert.Equal(t, endpoint.Targets{testTunnelID + ".cfargotunnel.com"}, endpoints[0].Targets)
+	assert.Equal(t, endpoint.TTL(0), endpoints[0].RecordTTL)
+	proxied, _ := endpoints[0].GetProviderSpecificProperty(source.CloudflareProxiedKey)
+	assert.Equal(t, "true", proxied)
+
+	assert.Equal(t, endpoint.Targets{other + ".cfargotunnel.com"}, endpoints[1].Targets)
+	proxied, _ = endpoints[1].GetProviderSpecificProperty(source.CloudflareProxiedKey)
+	assert.Equal(t, "true", proxied)
+	_, ok := endpoints[1].GetProviderSpecificProperty(source.CloudflareTunnelIDKey)
+	assert.False(t, ok)
+
+	// an invalid tunnel ID leaves the record untouched
+	assert.Equal(t, endpoint.RecordTypeA, endpoints[2].RecordType)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, endpoints[2].Targets)
+
+	assert.Equal(t, endpoint.RecordTypeTXT, endpoints[3].RecordType)
+}
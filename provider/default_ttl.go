@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// DefaultTTLProvider sets the TTL of the records without one to the default TTL of their record type.
+// Desired endpoints get it before being compared with the current records, so that existing records
+// are updated when a default TTL changes, and records generated by the registry get it when applied.
+type DefaultTTLProvider struct {
+	Provider
+	ttls map[string]endpoint.TTL
+}
+
+// NewDefaultTTLProvider wraps the provider to apply the default TTLs by record type.
+func NewDefaultTTLProvider(p Provider, ttls map[string]endpoint.TTL) *DefaultTTLProvider {
+	return &DefaultTTLProvider{Provider: p, ttls: ttls}
+}
+
+// AdjustEndpoints sets the default TTLs before letting the wrapped provider adjust the endpoints.
+func (p *DefaultTTLProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	p.setDefaults(endpoints)
+	return p.Provider.AdjustEndpoints(endpoints)
+}
+
+// ApplyChanges sets the default TTLs of the created and updated records before applying them.
+func (p *DefaultTTLProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	p.setDefaults(changes.Create)
+	p.setDefaults(changes.UpdateNew)
+	return p.Provider.ApplyChanges(ctx, changes)
+}
+
+// RecordsIter streams the records of the wrapped provider, see RecordsIterator.
+func (p *DefaultTTLProvider) RecordsIter(ctx context.Context, fn func(*endpoint.Endpoint) error) error {
+	return IterRecords(ctx, p.Provider, fn)
+}
+
+func (p *DefaultTTLProvider) setDefaults(endpoints []*endpoint.Endpoint) {
+	for _, ep := range endpoints {
+		if ep.RecordTTL.IsConfigured() {
+			continue
+		}
+		if ttl, ok := p.ttls[ep.RecordType]; ok {
+			ep.RecordTTL = ttl
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+func TestDefaultTTLProvider(t *testing.T) {
+	wrapped := &forbiddenZoneProvider{}
+	p := NewDefaultTTLProvider(wrapped, map[string]endpoint.TTL{
+		endpoint.RecordTypeA:   60,
+		endpoint.RecordTypeTXT: 3600,
+	})
+
+	endpoints, err := p.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("b.example.org", endpoint.RecordTypeA, 300, "1.2.3.4"),
+		endpoint.NewEndpoint("c.example.org", endpoint.RecordTypeCNAME, "a.example.org"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, endpoint.TTL(60), endpoints[0].RecordTTL)
+	assert.Equal(t, endpoint.TTL(300), endpoints[1].RecordTTL)
+	assert.False(t, endpoints[2].RecordTTL.IsConfigured())
+
+	require.NoError(t, p.ApplyChanges(context.Background(), &plan.Changes{
+		Create:    []*endpoint.Endpoint{endpoint.NewEndpoint("a-a.example.org", endpoint.RecordTypeTXT, "heritage=external-dns")},
+		UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.2.3.4")},
+		UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.2.3.5")},
+	}))
+	require.Len(t, wrapped.changes, 1)
+	assert.Equal(t, endpoint.TTL(3600), wrapped.changes[0].Create[0].RecordTTL)
+	assert.Equal(t, endpoint.TTL(60), wrapped.changes[0].UpdateNew[0].RecordTTL)
+	assert.False(t, wrapped.changes[0].UpdateOld[0].RecordTTL.IsConfigured())
+}
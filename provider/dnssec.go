@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import "context"
+
+// DelegationSigner holds the DNSSEC key material of a signed zone, which has to be published in its parent zone
+// for the chain of trust to reach the zone.
+type DelegationSigner struct {
+	// Zone is the domain name of the zone
+	Zone string
+	// ID is the ID of the zone at the provider
+	ID string
+	// DSRecords are the DS records of the active key signing keys of the zone,
+	// in the format "<key tag> <algorithm> <digest type> <digest>"
+	DSRecords []string
+	// DNSKEYRecords are the DNSKEY records of the active key signing keys of the zone, the data of CDNSKEY records,
+	// in the format "<flags> <protocol> <algorithm> <public key>"
+	DNSKEYRecords []string
+}
+
+// DelegationSignerProvider is implemented by providers which expose the DNSSEC key material of their zones.
+type DelegationSignerProvider interface {
+	// DelegationSigners returns the delegation signers of the zones of the provider signed with DNSSEC.
+	// Zones which are not signed are omitted.
+	DelegationSigners(ctx context.Context) ([]DelegationSigner, error)
+}
@@ -18,7 +18,9 @@ package google
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
 	"time"
@@ -38,6 +40,8 @@ import (
 
 const (
 	googleRecordTTL = 300
+	// googleChangeAttempts is the number of times a change of a zone failing transiently is submitted
+	googleChangeAttempts = 3
 )
 
 type managedZonesCreateCallInterface interface {
@@ -69,6 +73,14 @@ type changesServiceInterface interface {
 	Create(project string, managedZone string, change *dns.Change) changesCreateCallInterface
 }
 
+type dnsKeysListCallInterface interface {
+	Pages(ctx context.Context, f func(*dns.DnsKeysListResponse) error) error
+}
+
+type dnsKeysServiceInterface interface {
+	List(project string, managedZone string) dnsKeysListCallInterface
+}
+
 type resourceRecordSetsService struct {
 	service *dns.ResourceRecordSetsService
 }
@@ -89,6 +101,14 @@ func (m managedZonesService) List(project string) managedZonesListCallInterface
 	return m.service.List(project)
 }
 
+type dnsKeysService struct {
+	service *dns.DnsKeysService
+}
+
+func (d dnsKeysService) List(project string, managedZone string) dnsKeysListCallInterface {
+	return d.service.List(project, managedZone)
+}
+
 type changesService struct {
 	service *dns.ChangesService
 }
@@ -114,18 +134,24 @@ type GoogleProvider struct {
 	zoneTypeFilter provider.ZoneTypeFilter
 	// only consider hosted zones ending with this zone id
 	zoneIDFilter provider.ZoneIDFilter
+	// only consider hosted zones with these labels
+	zoneTagFilter provider.ZoneTagFilter
+	// number of zones changed concurrently
+	zoneWorkers int
 	// A client for managing resource record sets
 	resourceRecordSetsClient resourceRecordSetsClientInterface
 	// A client for managing hosted zones
 	managedZonesClient managedZonesServiceInterface
 	// A client for managing change sets
 	changesClient changesServiceInterface
+	// A client for reading the DNSSEC keys of hosted zones
+	dnsKeysClient dnsKeysServiceInterface
 	// The context parameter to be passed for gcloud API calls.
 	ctx context.Context
 }
 
 // NewGoogleProvider initializes a new Google CloudDNS based Provider.
-func NewGoogleProvider(ctx context.Context, project string, domainFilter endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, batchChangeSize int, batchChangeInterval time.Duration, zoneVisibility string, dryRun bool) (*GoogleProvider, error) {
+func NewGoogleProvider(ctx context.Context, project string, domainFilter endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, zoneTagFilter provider.ZoneTagFilter, batchChangeSize int, batchChangeInterval time.Duration, zoneVisibility string, zoneWorkers int, dryRun bool) (*GoogleProvider, error) {
 	gcloud, err := google.DefaultClient(ctx, dns.NdevClouddnsReadwriteScope)
 	if err != nil {
 		return nil, err
@@ -162,9 +188,12 @@ func NewGoogleProvider(ctx context.Context, project string, domainFilter endpoin
 		domainFilter:             domainFilter,
 		zoneTypeFilter:           zoneTypeFilter,
 		zoneIDFilter:             zoneIDFilter,
+		zoneTagFilter:            zoneTagFilter,
+		zoneWorkers:              zoneWorkers,
 		resourceRecordSetsClient: resourceRecordSetsService{dnsClient.ResourceRecordSets},
 		managedZonesClient:       managedZonesService{dnsClient.ManagedZones},
 		changesClient:            changesService{dnsClient.Changes},
+		dnsKeysClient:            dnsKeysService{dnsClient.DnsKeys},
 		ctx:                      ctx,
 	}
 
@@ -178,7 +207,7 @@ func (p *GoogleProvider) Zones(ctx context.Context) (map[string]*dns.ManagedZone
 	f := func(resp *dns.ManagedZonesListResponse) error {
 		for _, zone := range resp.ManagedZones {
 			if zone.PeeringConfig == nil {
-				if p.domainFilter.Match(zone.DnsName) && p.zoneTypeFilter.Match(zone.Visibility) && (p.zoneIDFilter.Match(fmt.Sprintf("%v", zone.Id)) || p.zoneIDFilter.Match(fmt.Sprintf("%v", zone.Name))) {
+				if p.domainFilter.Match(zone.DnsName) && p.zoneTypeFilter.Match(zone.Visibility) && (p.zoneIDFilter.Match(fmt.Sprintf("%v", zone.Id)) || p.zoneIDFilter.Match(fmt.Sprintf("%v", zone.Name))) && p.zoneTagFilter.Match(zone.Labels) {
 					zones[zone.Name] = zone
 					log.Debugf("Matched %s (zone: %s) (visibility: %s)", zone.DnsName, zone.Name, zone.Visibility)
 				} else {
@@ -208,6 +237,80 @@ func (p *GoogleProvider) Zones(ctx context.Context) (map[string]*dns.ManagedZone
 	return zones, nil
 }
 
+// dnssecAlgorithms are the DNSSEC algorithm numbers of the algorithms of Cloud DNS, see RFC 8624.
+var dnssecAlgorithms = map[string]int{
+	"rsasha1":         5,
+	"rsasha256":       8,
+	"rsasha512":       10,
+	"ecdsap256sha256": 13,
+	"ecdsap384sha384": 14,
+}
+
+// dsDigestTypes are the DS digest type numbers of the digests of Cloud DNS, see RFC 4034 and 6605.
+var dsDigestTypes = map[string]int{
+	"sha1":   1,
+	"sha256": 2,
+	"sha384": 4,
+}
+
+// DelegationSigners returns the DS and DNSKEY records of the active key signing keys of the zones signed with DNSSEC.
+func (p *GoogleProvider) DelegationSigners(ctx context.Context) ([]provider.DelegationSigner, error) {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var signers []provider.DelegationSigner
+	for _, zone := range zones {
+		if zone.DnssecConfig == nil || zone.DnssecConfig.State != "on" {
+			continue
+		}
+		signer := provider.DelegationSigner{Zone: strings.TrimSuffix(zone.DnsName, "."), ID: zone.Name}
+		err := p.dnsKeysClient.List(p.project, zone.Name).Pages(ctx, func(resp *dns.DnsKeysListResponse) error {
+			for _, key := range resp.DnsKeys {
+				algorithm, ok := dnssecAlgorithms[key.Algorithm]
+				if key.Type != "keySigning" || !key.IsActive || !ok {
+					continue
+				}
+				for _, digest := range key.Digests {
+					if digestType, ok := dsDigestTypes[digest.Type]; ok {
+						signer.DSRecords = append(signer.DSRecords, fmt.Sprintf("%d %d %d %s", key.KeyTag, algorithm, digestType, strings.ToUpper(digest.Digest)))
+					}
+				}
+				// key signing keys have the zone key and secure entry point flags, 257
+				signer.DNSKEYRecords = append(signer.DNSKEYRecords, fmt.Sprintf("257 3 %d %s", algorithm, key.PublicKey))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the DNSSEC keys of zone %s: %w", zone.Name, err)
+		}
+		if len(signer.DSRecords) > 0 {
+			signers = append(signers, signer)
+		}
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return signers[i].ID < signers[j].ID
+	})
+	return signers, nil
+}
+
+// Preflight verifies that the records of all managed zones can be changed.
+func (p *GoogleProvider) Preflight(ctx context.Context) error {
+	zones, err := p.Zones(ctx)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(zones))
+	names := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		if !seen[zone.DnsName] {
+			seen[zone.DnsName] = true
+			names = append(names, zone.DnsName)
+		}
+	}
+	return provider.VerifyZonesWritable(ctx, p, names)
+}
+
 // Records returns the list of records in all relevant zones.
 func (p *GoogleProvider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, _ error) {
 	zones, err := p.Zones(ctx)
@@ -315,31 +418,56 @@ func (p *GoogleProvider) submitChange(ctx context.Context, change *dns.Change) e
 	// separate into per-zone change sets to be passed to the API.
 	changes := separateChange(zones, change)
 
-	for zone, change := range changes {
-		for batch, c := range batchChange(change, p.batchChangeSize) {
-			log.Infof("Change zone: %v batch #%d", zone, batch)
-			for _, del := range c.Deletions {
-				log.Infof("Del records: %s %s %s %d", del.Name, del.Type, del.Rrdatas, del.Ttl)
-			}
-			for _, add := range c.Additions {
-				log.Infof("Add records: %s %s %s %d", add.Name, add.Type, add.Rrdatas, add.Ttl)
-			}
+	zoneNames := make([]string, 0, len(changes))
+	for zone := range changes {
+		zoneNames = append(zoneNames, zone)
+	}
+	sort.Strings(zoneNames)
 
-			if p.dryRun {
-				continue
-			}
+	// the changes of a zone are atomic, so that the zones are changed independently of each other
+	return provider.ForEachZone(ctx, zoneNames, p.zoneWorkers, func(ctx context.Context, zone string) error {
+		return p.submitZoneChange(ctx, zone, changes[zone])
+	})
+}
 
-			if _, err := p.changesClient.Create(p.project, zone, c).Do(); err != nil {
-				return err
-			}
+// submitZoneChange sends the change of a zone to Google in batches, retrying the batches failing transiently.
+func (p *GoogleProvider) submitZoneChange(ctx context.Context, zone string, change *dns.Change) error {
+	for batch, c := range batchChange(change, p.batchChangeSize) {
+		log.Infof("Change zone: %v batch #%d", zone, batch)
+		for _, del := range c.Deletions {
+			log.Infof("Del records: %s %s %s %d", del.Name, del.Type, del.Rrdatas, del.Ttl)
+		}
+		for _, add := range c.Additions {
+			log.Infof("Add records: %s %s %s %d", add.Name, add.Type, add.Rrdatas, add.Ttl)
+		}
 
-			time.Sleep(p.batchChangeInterval)
+		if p.dryRun {
+			continue
 		}
+
+		err := provider.RetryZoneChange(ctx, googleChangeAttempts, p.batchChangeInterval, nil, isTransientError, func() error {
+			_, err := p.changesClient.Create(p.project, zone, c).Do()
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to change zone %s: %w", zone, err)
+		}
+
+		time.Sleep(p.batchChangeInterval)
 	}
 
 	return nil
 }
 
+// isTransientError returns true for the errors of the requests which may succeed when repeated.
+func isTransientError(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= http.StatusInternalServerError
+	}
+	return false
+}
+
 // batchChange separates a zone in multiple transaction.
 func batchChange(change *dns.Change, batchSize int) []*dns.Change {
 	changes := []*dns.Change{}
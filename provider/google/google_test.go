@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -84,6 +85,21 @@ func (m *mockManagedZonesClient) List(project string) managedZonesListCallInterf
 	return &mockManagedZonesListCall{project: project}
 }
 
+type mockDNSKeysListCall struct {
+	keys []*dns.DnsKey
+}
+
+func (m *mockDNSKeysListCall) Pages(ctx context.Context, f func(*dns.DnsKeysListResponse) error) error {
+	return f(&dns.DnsKeysListResponse{DnsKeys: m.keys})
+}
+
+// mockDNSKeysClient holds the DNSSEC keys by zone name.
+type mockDNSKeysClient map[string][]*dns.DnsKey
+
+func (m mockDNSKeysClient) List(project string, managedZone string) dnsKeysListCallInterface {
+	return &mockDNSKeysListCall{keys: m[managedZone]}
+}
+
 type mockResourceRecordSetsListCall struct {
 	project     string
 	managedZone string
@@ -118,6 +134,9 @@ type mockChangesCreateCall struct {
 }
 
 func (m *mockChangesCreateCall) Do(opts ...googleapi.CallOption) (*dns.Change, error) {
+	testChangesMu.Lock()
+	defer testChangesMu.Unlock()
+
 	zoneKey := zoneKey(m.project, m.managedZone)
 
 	if _, ok := testZones[zoneKey]; !ok {
@@ -150,8 +169,37 @@ func (m *mockChangesCreateCall) Do(opts ...googleapi.CallOption) (*dns.Change, e
 	return m.change, nil
 }
 
+// testChangesMu serializes the changes of the zones changed concurrently.
+var testChangesMu sync.Mutex
+
 type mockChangesClient struct{}
 
+// flakyChangesClient fails the first changes of each zone with a transient error.
+type flakyChangesClient struct {
+	mockChangesClient
+	mu       sync.Mutex
+	failures int
+	calls    map[string]int
+}
+
+func (m *flakyChangesClient) Create(project string, managedZone string, change *dns.Change) changesCreateCallInterface {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[managedZone]++
+	if m.calls[managedZone] <= m.failures {
+		return &failingChangesCreateCall{err: &googleapi.Error{Code: http.StatusServiceUnavailable}}
+	}
+	return m.mockChangesClient.Create(project, managedZone, change)
+}
+
+type failingChangesCreateCall struct {
+	err error
+}
+
+func (m *failingChangesCreateCall) Do(opts ...googleapi.CallOption) (*dns.Change, error) {
+	return nil, m.err
+}
+
 func (m *mockChangesClient) Create(project string, managedZone string, change *dns.Change) changesCreateCallInterface {
 	return &mockChangesCreateCall{project: project, managedZone: managedZone, change: change}
 }
@@ -204,6 +252,40 @@ func TestGoogleZonesIDFilter(t *testing.T) {
 	})
 }
 
+func TestGoogleDelegationSigners(t *testing.T) {
+	p := &GoogleProvider{
+		project:            "zalando-external-dns-dnssec-test",
+		domainFilter:       endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}),
+		managedZonesClient: &mockManagedZonesClient{},
+		dnsKeysClient: mockDNSKeysClient{
+			"zone-1-ext-dns-test-2-gcp-zalan-do": {
+				{Type: "keySigning", IsActive: true, KeyTag: 12345, Algorithm: "ecdsap256sha256", PublicKey: "AAAA", Digests: []*dns.DnsKeyDigest{{Type: "sha256", Digest: "abcdef"}}},
+				{Type: "keySigning", IsActive: false, KeyTag: 54321, Algorithm: "ecdsap256sha256", PublicKey: "BBBB", Digests: []*dns.DnsKeyDigest{{Type: "sha256", Digest: "fedcba"}}},
+				{Type: "zoneSigning", IsActive: true, KeyTag: 23456, Algorithm: "ecdsap256sha256", PublicKey: "CCCC"},
+			},
+			"zone-2-ext-dns-test-2-gcp-zalan-do": {
+				{Type: "keySigning", IsActive: true, KeyTag: 34567, Algorithm: "rsasha256", PublicKey: "DDDD", Digests: []*dns.DnsKeyDigest{{Type: "sha256", Digest: "012345"}}},
+			},
+		},
+	}
+	for _, zone := range []*dns.ManagedZone{
+		{Name: "zone-1-ext-dns-test-2-gcp-zalan-do", DnsName: "zone-1.ext-dns-test-2.gcp.zalan.do.", DnssecConfig: &dns.ManagedZoneDnsSecConfig{State: "on"}},
+		{Name: "zone-2-ext-dns-test-2-gcp-zalan-do", DnsName: "zone-2.ext-dns-test-2.gcp.zalan.do.", DnssecConfig: &dns.ManagedZoneDnsSecConfig{State: "off"}},
+	} {
+		_, err := p.managedZonesClient.Create(p.project, zone).Do()
+		require.NoError(t, err)
+	}
+
+	signers, err := p.DelegationSigners(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []provider.DelegationSigner{{
+		Zone:          "zone-1.ext-dns-test-2.gcp.zalan.do",
+		ID:            "zone-1-ext-dns-test-2-gcp-zalan-do",
+		DSRecords:     []string{"12345 13 2 ABCDEF"},
+		DNSKEYRecords: []string{"257 3 13 AAAA"},
+	}}, signers)
+}
+
 func TestGoogleZonesNameFilter(t *testing.T) {
 	provider := newGoogleProviderZoneOverlap(t, endpoint.NewDomainFilter([]string{"cluster.local."}), provider.NewZoneIDFilter([]string{"internal-2"}), provider.NewZoneTypeFilter(""), false, []*endpoint.Endpoint{})
 
@@ -237,6 +319,34 @@ func TestGoogleZonesVisibilityFilterPrivate(t *testing.T) {
 	})
 }
 
+func TestGoogleZonesTagFilter(t *testing.T) {
+	zoneTagFilter := provider.NewZoneTagFilter([]string{"team=platform"})
+	provider := newGoogleProviderZoneOverlap(t, endpoint.NewDomainFilter([]string{"labels.local."}), provider.NewZoneIDFilter([]string{""}), provider.NewZoneTypeFilter(""), false, []*endpoint.Endpoint{})
+	provider.zoneTagFilter = zoneTagFilter
+
+	createZone(t, provider, &dns.ManagedZone{
+		Name:       "labels-platform",
+		DnsName:    "labels.local.",
+		Id:         10010,
+		Visibility: "private",
+		Labels:     map[string]string{"team": "platform"},
+	})
+	createZone(t, provider, &dns.ManagedZone{
+		Name:       "labels-other",
+		DnsName:    "labels.local.",
+		Id:         10011,
+		Visibility: "private",
+		Labels:     map[string]string{"team": "other"},
+	})
+
+	zones, err := provider.Zones(context.Background())
+	require.NoError(t, err)
+
+	validateZones(t, zones, map[string]*dns.ManagedZone{
+		"labels-platform": {Name: "labels-platform", DnsName: "labels.local.", Id: 10010, Visibility: "private"},
+	})
+}
+
 func TestGoogleZonesVisibilityFilterPrivatePeering(t *testing.T) {
 	provider := newGoogleProviderZoneOverlap(t, endpoint.NewDomainFilter([]string{"svc.local."}), provider.NewZoneIDFilter([]string{""}), provider.NewZoneTypeFilter("private"), false, []*endpoint.Endpoint{})
 
@@ -458,6 +568,44 @@ func TestGoogleApplyChanges(t *testing.T) {
 	})
 }
 
+func TestGoogleApplyChangesZoneWorkers(t *testing.T) {
+	provider := newGoogleProvider(t, endpoint.NewDomainFilter([]string{"ext-dns-test-2.gcp.zalan.do."}), provider.NewZoneIDFilter([]string{""}), false, []*endpoint.Endpoint{})
+	provider.zoneWorkers = 3
+
+	for _, tc := range []struct {
+		name     string
+		failures int
+		err      bool
+	}{
+		{name: "transient errors retried", failures: googleChangeAttempts - 1},
+		{name: "retries exhausted", failures: googleChangeAttempts, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &flakyChangesClient{failures: tc.failures, calls: map[string]int{}}
+			provider.changesClient = client
+
+			suffix := strings.ReplaceAll(tc.name, " ", "-")
+			err := provider.ApplyChanges(context.Background(), &plan.Changes{
+				Create: []*endpoint.Endpoint{
+					endpoint.NewEndpoint(suffix+".zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "8.8.8.8"),
+					endpoint.NewEndpoint(suffix+".zone-2.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, "8.8.4.4"),
+				},
+			})
+			if tc.err {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "zone-1-ext-dns-test-2-gcp-zalan-do")
+				assert.Contains(t, err.Error(), "zone-2-ext-dns-test-2-gcp-zalan-do")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, map[string]int{
+				"zone-1-ext-dns-test-2-gcp-zalan-do": googleChangeAttempts,
+				"zone-2-ext-dns-test-2-gcp-zalan-do": googleChangeAttempts,
+			}, client.calls)
+		})
+	}
+}
+
 func TestGoogleApplyChangesDryRun(t *testing.T) {
 	originalEndpoints := []*endpoint.Endpoint{
 		endpoint.NewEndpointWithTTL("update-test.zone-1.ext-dns-test-2.gcp.zalan.do", endpoint.RecordTypeA, googleRecordTTL, "8.8.8.8"),
@@ -342,3 +342,12 @@ func (c *inMemoryClient) validateChangeBatch(zone string, changes *plan.Changes)
 	}
 	return nil
 }
+
+// Preflight verifies that records can be changed in all zones
+func (im *InMemoryProvider) Preflight(ctx context.Context) error {
+	zones := make([]string, 0, len(im.Zones()))
+	for _, name := range im.Zones() {
+		zones = append(zones, name)
+	}
+	return provider.VerifyZonesWritable(ctx, im, zones)
+}
@@ -36,6 +36,7 @@ func TestInMemoryProvider(t *testing.T) {
 	t.Run("ApplyChanges", testInMemoryApplyChanges)
 	t.Run("NewInMemoryProvider", testNewInMemoryProvider)
 	t.Run("CreateZone", testInMemoryCreateZone)
+	t.Run("Preflight", testInMemoryPreflight)
 }
 
 func testInMemoryRecords(t *testing.T) {
@@ -586,3 +587,17 @@ func makeZone(s ...string) map[endpoint.EndpointKey]*endpoint.Endpoint {
 
 	return output
 }
+
+func testInMemoryPreflight(t *testing.T) {
+	var changes []*plan.Changes
+	im := NewInMemoryProvider(InMemoryInitZones([]string{"example.org", "example.com"}))
+	im.OnApplyChanges = func(ctx context.Context, c *plan.Changes) {
+		changes = append(changes, c)
+	}
+
+	require.NoError(t, im.Preflight(context.Background()))
+	assert.Len(t, changes, 4)
+	records, err := im.Records(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
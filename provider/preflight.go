@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// PreflightRecordName is the name, relative to each zone, of the TXT record created and deleted
+// to verify that the records of the zone can be changed.
+const PreflightRecordName = "external-dns-preflight"
+
+// Preflighter is implemented by providers which can verify, before the first synchronization,
+// that they are allowed to change the records of the zones they manage.
+type Preflighter interface {
+	Preflight(ctx context.Context) error
+}
+
+// VerifyZonesWritable verifies that the records of the zones can be changed by creating and
+// deleting a canary TXT record in each of them through the provider. The canary left behind by
+// an interrupted verification is deleted first.
+func VerifyZonesWritable(ctx context.Context, p Provider, zones []string) error {
+	records, err := p.Records(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the records: %w", err)
+	}
+	leftovers := map[string]*endpoint.Endpoint{}
+	for _, r := range records {
+		if r.RecordType == endpoint.RecordTypeTXT && strings.HasPrefix(r.DNSName, PreflightRecordName+".") {
+			leftovers[strings.TrimSuffix(r.DNSName, ".")] = r
+		}
+	}
+
+	sort.Strings(zones)
+	for _, zone := range zones {
+		canary := endpoint.NewEndpoint(PreflightRecordName+"."+strings.TrimSuffix(zone, "."), endpoint.RecordTypeTXT, "\"external-dns preflight\"")
+		if leftover, ok := leftovers[canary.DNSName]; ok {
+			if err := p.ApplyChanges(ctx, &plan.Changes{Delete: []*endpoint.Endpoint{leftover}}); err != nil {
+				return fmt.Errorf("records of zone %s cannot be deleted, %s has to be removed manually: %w", zone, canary.DNSName, err)
+			}
+		}
+		if err := p.ApplyChanges(ctx, &plan.Changes{Create: []*endpoint.Endpoint{canary}}); err != nil {
+			return fmt.Errorf("records of zone %s cannot be created: %w", zone, err)
+		}
+		// the canary is deleted even when the verification is canceled in the meantime
+		if err := p.ApplyChanges(context.WithoutCancel(ctx), &plan.Changes{Delete: []*endpoint.Endpoint{canary}}); err != nil {
+			return fmt.Errorf("records of zone %s cannot be deleted, %s has to be removed manually: %w", zone, canary.DNSName, err)
+		}
+	}
+	return nil
+}
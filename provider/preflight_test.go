@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// forbiddenZoneProvider records the changes and rejects the ones of records in the forbidden zone, if set.
+type forbiddenZoneProvider struct {
+	BaseProvider
+	forbidden string
+	records   []*endpoint.Endpoint
+	changes   []*plan.Changes
+	canceled  []bool
+}
+
+func (p *forbiddenZoneProvider) Records(context.Context) ([]*endpoint.Endpoint, error) {
+	return p.records, nil
+}
+
+func (p *forbiddenZoneProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	p.canceled = append(p.canceled, ctx.Err() != nil)
+	for _, e := range append(changes.Create, changes.Delete...) {
+		if p.forbidden != "" && strings.HasSuffix(e.DNSName, p.forbidden) {
+			return errors.New("403 forbidden")
+		}
+	}
+	p.changes = append(p.changes, changes)
+	return nil
+}
+
+func TestVerifyZonesWritable(t *testing.T) {
+	p := &forbiddenZoneProvider{forbidden: "forbidden.org"}
+
+	require.NoError(t, VerifyZonesWritable(context.Background(), p, []string{"example.org.", "example.com"}))
+	require.Len(t, p.changes, 4)
+	assert.Equal(t, "external-dns-preflight.example.com", p.changes[0].Create[0].DNSName)
+	assert.Equal(t, endpoint.RecordTypeTXT, p.changes[0].Create[0].RecordType)
+	assert.Equal(t, "external-dns-preflight.example.com", p.changes[1].Delete[0].DNSName)
+	assert.Equal(t, "external-dns-preflight.example.org", p.changes[2].Create[0].DNSName)
+	assert.Equal(t, "external-dns-preflight.example.org", p.changes[3].Delete[0].DNSName)
+
+	err := VerifyZonesWritable(context.Background(), p, []string{"forbidden.org"})
+	assert.EqualError(t, err, "records of zone forbidden.org cannot be created: 403 forbidden")
+}
+
+func TestVerifyZonesWritableDeletesLeftoverCanary(t *testing.T) {
+	leftover := endpoint.NewEndpoint("external-dns-preflight.example.org", endpoint.RecordTypeTXT, "\"external-dns preflight\"")
+	p := &forbiddenZoneProvider{records: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		leftover,
+	}}
+
+	require.NoError(t, VerifyZonesWritable(context.Background(), p, []string{"example.org"}))
+	require.Len(t, p.changes, 3)
+	assert.Equal(t, []*endpoint.Endpoint{leftover}, p.changes[0].Delete)
+	assert.Equal(t, "external-dns-preflight.example.org", p.changes[1].Create[0].DNSName)
+	assert.Equal(t, "external-dns-preflight.example.org", p.changes[2].Delete[0].DNSName)
+}
+
+// cancelingProvider cancels the verification once the canary is created.
+type cancelingProvider struct {
+	forbiddenZoneProvider
+	cancel context.CancelFunc
+}
+
+func (p *cancelingProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	err := p.forbiddenZoneProvider.ApplyChanges(ctx, changes)
+	p.cancel()
+	return err
+}
+
+func TestVerifyZonesWritableDeletesCanaryWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &cancelingProvider{cancel: cancel}
+
+	require.NoError(t, VerifyZonesWritable(ctx, p, []string{"example.org"}))
+	require.Len(t, p.changes, 2)
+	assert.Equal(t, "external-dns-preflight.example.org", p.changes[1].Delete[0].DNSName)
+	assert.Equal(t, []bool{false, false}, p.canceled)
+}
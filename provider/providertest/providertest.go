@@ -0,0 +1,405 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package providertest provides a behavioral conformance suite for
+// implementations of provider.Provider.
+//
+// The suite only relies on the public Provider interface and is meant to be
+// imported from the tests of in-tree, out-of-tree and webhook providers:
+//
+//	func TestConformance(t *testing.T) {
+//		providertest.Run(t, providertest.Config{
+//			Zone: "example.com",
+//			NewProvider: func(t *testing.T) provider.Provider {
+//				return newTestProvider(t)
+//			},
+//		})
+//	}
+package providertest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+const defaultOwnerID = "providertest"
+
+// Config configures the conformance suite.
+type Config struct {
+	// Zone is the DNS zone the provider under test is authoritative for.
+	// All records created by the suite are placed below this zone.
+	Zone string
+	// NewProvider returns a provider with an empty Zone. It is called once
+	// per test case so that test cases do not observe each other's records.
+	NewProvider func(t *testing.T) provider.Provider
+	// RecordTypes are the record types exercised by the suite.
+	// Defaults to A, CNAME and TXT.
+	RecordTypes []string
+	// Skip holds names of test cases which should not be run, e.g. because
+	// the provider is known not to support the behavior yet.
+	Skip []string
+}
+
+// testCase is a single conformance check.
+type testCase struct {
+	name string
+	run  func(t *testing.T, cfg Config, p provider.Provider)
+}
+
+var testCases = []testCase{
+	{"RecordsEmptyZone", testRecordsEmptyZone},
+	{"ApplyChangesEmpty", testApplyChangesEmpty},
+	{"Create", testCreate},
+	{"Update", testUpdate},
+	{"Delete", testDelete},
+	{"MixedBatch", testMixedBatch},
+	{"Idempotency", testIdempotency},
+	{"AdjustEndpointsIdempotent", testAdjustEndpointsIdempotent},
+	{"OwnershipInterplay", testOwnershipInterplay},
+}
+
+// Run executes all conformance test cases against the provider returned by
+// cfg.NewProvider.
+func Run(t *testing.T, cfg Config) {
+	t.Helper()
+	if cfg.NewProvider == nil {
+		t.Fatal("providertest: Config.NewProvider must be set")
+	}
+	if cfg.Zone == "" {
+		t.Fatal("providertest: Config.Zone must be set")
+	}
+	if len(cfg.RecordTypes) == 0 {
+		cfg.RecordTypes = []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME, endpoint.RecordTypeTXT}
+	}
+
+	skip := make(map[string]struct{}, len(cfg.Skip))
+	for _, name := range cfg.Skip {
+		skip[name] = struct{}{}
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok := skip[tc.name]; ok {
+				t.Skipf("%s skipped by configuration", tc.name)
+			}
+			tc.run(t, cfg, cfg.NewProvider(t))
+		})
+	}
+}
+
+func testRecordsEmptyZone(t *testing.T, cfg Config, p provider.Provider) {
+	records := mustRecords(t, p)
+	if managed := filterManaged(records, cfg); len(managed) != 0 {
+		t.Errorf("expected no records in a fresh zone, got %v", managed)
+	}
+}
+
+func testApplyChangesEmpty(t *testing.T, cfg Config, p provider.Provider) {
+	before := mustRecords(t, p)
+	if err := p.ApplyChanges(context.Background(), &plan.Changes{}); err != nil {
+		t.Fatalf("applying empty changes must not fail: %v", err)
+	}
+	after := mustRecords(t, p)
+	if !sameEndpoints(before, after) {
+		t.Errorf("applying empty changes modified the zone: before %v, after %v", before, after)
+	}
+}
+
+func testCreate(t *testing.T, cfg Config, p provider.Provider) {
+	desired := sampleEndpoints(cfg, "create")
+	mustApply(t, p, &plan.Changes{Create: desired})
+	expectManaged(t, p, cfg, desired)
+}
+
+func testUpdate(t *testing.T, cfg Config, p provider.Provider) {
+	current := sampleEndpoints(cfg, "update")
+	mustApply(t, p, &plan.Changes{Create: current})
+
+	desired := make([]*endpoint.Endpoint, 0, len(current))
+	for _, ep := range current {
+		desired = append(desired, endpoint.NewEndpointWithTTL(ep.DNSName, ep.RecordType, ep.RecordTTL, alternateTarget(cfg, ep.RecordType)))
+	}
+	mustApply(t, p, &plan.Changes{UpdateOld: current, UpdateNew: desired})
+	expectManaged(t, p, cfg, desired)
+}
+
+func testDelete(t *testing.T, cfg Config, p provider.Provider) {
+	current := sampleEndpoints(cfg, "delete")
+	mustApply(t, p, &plan.Changes{Create: current})
+	mustApply(t, p, &plan.Changes{Delete: current})
+	expectManaged(t, p, cfg, nil)
+}
+
+func testMixedBatch(t *testing.T, cfg Config, p provider.Provider) {
+	toUpdate := sampleEndpoints(cfg, "batch-update")
+	toDelete := sampleEndpoints(cfg, "batch-delete")
+	mustApply(t, p, &plan.Changes{Create: append(append([]*endpoint.Endpoint{}, toUpdate...), toDelete...)})
+
+	toCreate := sampleEndpoints(cfg, "batch-create")
+	updated := make([]*endpoint.Endpoint, 0, len(toUpdate))
+	for _, ep := range toUpdate {
+		updated = append(updated, endpoint.NewEndpointWithTTL(ep.DNSName, ep.RecordType, ep.RecordTTL, alternateTarget(cfg, ep.RecordType)))
+	}
+	mustApply(t, p, &plan.Changes{
+		Create:    toCreate,
+		UpdateOld: toUpdate,
+		UpdateNew: updated,
+		Delete:    toDelete,
+	})
+	expectManaged(t, p, cfg, append(append([]*endpoint.Endpoint{}, toCreate...), updated...))
+}
+
+func testIdempotency(t *testing.T, cfg Config, p provider.Provider) {
+	desired := sampleEndpoints(cfg, "idempotent")
+	mustApply(t, p, &plan.Changes{Create: desired})
+
+	first := mustRecords(t, p)
+	second := mustRecords(t, p)
+	if !sameEndpoints(first, second) {
+		t.Fatalf("consecutive Records calls returned different results: %v, %v", first, second)
+	}
+
+	adjusted, err := p.AdjustEndpoints(sampleEndpoints(cfg, "idempotent"))
+	if err != nil {
+		t.Fatalf("AdjustEndpoints failed: %v", err)
+	}
+	changes := calculate(cfg, first, adjusted, "")
+	if changes.HasChanges() {
+		t.Errorf("expected no changes after records were applied, got %v", changes)
+	}
+}
+
+func testAdjustEndpointsIdempotent(t *testing.T, cfg Config, p provider.Provider) {
+	once, err := p.AdjustEndpoints(sampleEndpoints(cfg, "adjust"))
+	if err != nil {
+		t.Fatalf("AdjustEndpoints failed: %v", err)
+	}
+	twice, err := p.AdjustEndpoints(copyEndpoints(once))
+	if err != nil {
+		t.Fatalf("AdjustEndpoints failed: %v", err)
+	}
+	if !sameEndpoints(once, twice) {
+		t.Errorf("AdjustEndpoints is not idempotent: %v, %v", once, twice)
+	}
+}
+
+func testOwnershipInterplay(t *testing.T, cfg Config, p provider.Provider) {
+	ctx := context.Background()
+	ours, err := registry.NewTXTRegistry(p, "", "", defaultOwnerID, 0, "", cfg.RecordTypes, nil, false, nil)
+	if err != nil {
+		t.Fatalf("failed to create TXT registry: %v", err)
+	}
+	theirs, err := registry.NewTXTRegistry(p, "", "", defaultOwnerID+"-other", 0, "", cfg.RecordTypes, nil, false, nil)
+	if err != nil {
+		t.Fatalf("failed to create TXT registry: %v", err)
+	}
+
+	foreign := endpoint.NewEndpoint(recordName(cfg, "foreign"), endpoint.RecordTypeA, "192.0.2.10")
+	if err := theirs.ApplyChanges(ctx, &plan.Changes{Create: []*endpoint.Endpoint{foreign}}); err != nil {
+		t.Fatalf("failed to create foreign record: %v", err)
+	}
+
+	desired := []*endpoint.Endpoint{endpoint.NewEndpoint(recordName(cfg, "owned"), endpoint.RecordTypeA, "192.0.2.20")}
+	records, err := ours.Records(ctx)
+	if err != nil {
+		t.Fatalf("registry Records failed: %v", err)
+	}
+	changes := calculate(cfg, records, desired, ours.OwnerID())
+	if len(changes.Delete) != 0 || len(changes.UpdateNew) != 0 {
+		t.Fatalf("records owned by another owner must not be touched, got %v", changes)
+	}
+	if err := ours.ApplyChanges(ctx, changes); err != nil {
+		t.Fatalf("registry ApplyChanges failed: %v", err)
+	}
+
+	records, err = ours.Records(ctx)
+	if err != nil {
+		t.Fatalf("registry Records failed: %v", err)
+	}
+	owners := map[string]string{}
+	for _, r := range records {
+		if r.RecordType == endpoint.RecordTypeA {
+			owners[r.DNSName] = r.Labels[endpoint.OwnerLabelKey]
+		}
+	}
+	if got := owners[foreign.DNSName]; got != theirs.OwnerID() {
+		t.Errorf("expected %s to be owned by %q, got %q", foreign.DNSName, theirs.OwnerID(), got)
+	}
+	if got := owners[desired[0].DNSName]; got != ours.OwnerID() {
+		t.Errorf("expected %s to be owned by %q, got %q", desired[0].DNSName, ours.OwnerID(), got)
+	}
+
+	if changes := calculate(cfg, records, desired, ours.OwnerID()); changes.HasChanges() {
+		t.Errorf("expected a converged plan, got %v", changes)
+	}
+}
+
+func calculate(cfg Config, current, desired []*endpoint.Endpoint, ownerID string) *plan.Changes {
+	domainFilter := endpoint.NewDomainFilter([]string{cfg.Zone})
+	p := &plan.Plan{
+		Policies:       []plan.Policy{&plan.SyncPolicy{}},
+		Current:        current,
+		Desired:        desired,
+		DomainFilter:   endpoint.MatchAllDomainFilters{&domainFilter},
+		ManagedRecords: cfg.RecordTypes,
+		OwnerID:        ownerID,
+	}
+	return p.Calculate().Changes
+}
+
+func recordName(cfg Config, name string) string {
+	return fmt.Sprintf("%s.%s", name, cfg.Zone)
+}
+
+// sampleEndpoints returns one endpoint per configured record type below
+// the given name.
+func sampleEndpoints(cfg Config, name string) []*endpoint.Endpoint {
+	endpoints := make([]*endpoint.Endpoint, 0, len(cfg.RecordTypes))
+	for _, recordType := range cfg.RecordTypes {
+		dnsName := recordName(cfg, fmt.Sprintf("%s-%s", name, strings.ToLower(recordType)))
+		endpoints = append(endpoints, endpoint.NewEndpointWithTTL(dnsName, recordType, 300, sampleTarget(cfg, recordType)))
+	}
+	return endpoints
+}
+
+func sampleTarget(cfg Config, recordType string) string {
+	switch recordType {
+	case endpoint.RecordTypeA:
+		return "192.0.2.1"
+	case endpoint.RecordTypeAAAA:
+		return "2001:db8::1"
+	case endpoint.RecordTypeCNAME:
+		return "target." + cfg.Zone
+	case endpoint.RecordTypeTXT:
+		return "\"providertest\""
+	case endpoint.RecordTypeMX:
+		return "10 mail." + cfg.Zone
+	case endpoint.RecordTypeSRV:
+		return "10 5 443 srv." + cfg.Zone
+	case endpoint.RecordTypeNS:
+		return "ns1." + cfg.Zone
+	case endpoint.RecordTypePTR:
+		return "ptr." + cfg.Zone
+	}
+	return "example-target"
+}
+
+func alternateTarget(cfg Config, recordType string) string {
+	switch recordType {
+	case endpoint.RecordTypeA:
+		return "192.0.2.2"
+	case endpoint.RecordTypeAAAA:
+		return "2001:db8::2"
+	case endpoint.RecordTypeCNAME:
+		return "other." + cfg.Zone
+	case endpoint.RecordTypeTXT:
+		return "\"providertest-updated\""
+	case endpoint.RecordTypeMX:
+		return "20 mail." + cfg.Zone
+	case endpoint.RecordTypeSRV:
+		return "20 5 443 srv." + cfg.Zone
+	case endpoint.RecordTypeNS:
+		return "ns2." + cfg.Zone
+	case endpoint.RecordTypePTR:
+		return "other-ptr." + cfg.Zone
+	}
+	return "example-target-updated"
+}
+
+func mustRecords(t *testing.T, p provider.Provider) []*endpoint.Endpoint {
+	t.Helper()
+	records, err := p.Records(context.Background())
+	if err != nil {
+		t.Fatalf("Records failed: %v", err)
+	}
+	return records
+}
+
+func mustApply(t *testing.T, p provider.Provider, changes *plan.Changes) {
+	t.Helper()
+	if err := p.ApplyChanges(context.Background(), changes); err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+}
+
+// expectManaged verifies that the records of the configured types in the
+// provider match expected by name, type and targets.
+func expectManaged(t *testing.T, p provider.Provider, cfg Config, expected []*endpoint.Endpoint) {
+	t.Helper()
+	got := filterManaged(mustRecords(t, p), cfg)
+	if !sameEndpoints(got, expected) {
+		t.Errorf("unexpected records: got %v, expected %v", got, expected)
+	}
+}
+
+func filterManaged(records []*endpoint.Endpoint, cfg Config) []*endpoint.Endpoint {
+	suffix := "." + cfg.Zone
+	var result []*endpoint.Endpoint
+	for _, r := range records {
+		if !slices.Contains(cfg.RecordTypes, r.RecordType) {
+			continue
+		}
+		if strings.HasSuffix(strings.TrimSuffix(r.DNSName, "."), suffix) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func sameEndpoints(a, b []*endpoint.Endpoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	index := make(map[string]int, len(a))
+	for _, ep := range a {
+		index[endpointKey(ep)]++
+	}
+	for _, ep := range b {
+		key := endpointKey(ep)
+		if index[key] == 0 {
+			return false
+		}
+		index[key]--
+	}
+	return true
+}
+
+func endpointKey(ep *endpoint.Endpoint) string {
+	targets := make([]string, 0, len(ep.Targets))
+	for _, target := range ep.Targets {
+		targets = append(targets, strings.TrimSuffix(target, "."))
+	}
+	sort.Strings(targets)
+	return fmt.Sprintf("%s/%s/%s/%s", strings.TrimSuffix(ep.DNSName, "."), ep.RecordType, ep.SetIdentifier, strings.Join(targets, ";"))
+}
+
+func copyEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		result = append(result, ep.DeepCopy())
+	}
+	return result
+}
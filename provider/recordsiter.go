@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// RecordsIterator is implemented by providers which can stream their records, e.g. page by
+// page, so that the records of huge zones can be filtered without holding all of them in memory.
+type RecordsIterator interface {
+	// RecordsIter calls fn for every record, one after the other, and stops at the first error
+	// returned by fn, which it returns.
+	RecordsIter(ctx context.Context, fn func(*endpoint.Endpoint) error) error
+}
+
+// IterRecords calls fn for every record of the provider, streaming them when the provider
+// implements RecordsIterator and iterating over the result of Records otherwise.
+func IterRecords(ctx context.Context, p Provider, fn func(*endpoint.Endpoint) error) error {
+	if it, ok := p.(RecordsIterator); ok {
+		return it.RecordsIter(ctx, fn)
+	}
+	records, err := p.Records(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
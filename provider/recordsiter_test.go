@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// sliceProvider returns its records through Records only.
+type sliceProvider struct {
+	BaseProvider
+	records []*endpoint.Endpoint
+	err     error
+}
+
+func (p *sliceProvider) Records(context.Context) ([]*endpoint.Endpoint, error) {
+	return p.records, p.err
+}
+
+func (p *sliceProvider) ApplyChanges(context.Context, *plan.Changes) error {
+	return nil
+}
+
+// streamingProvider streams its records and fails when they are requested as a slice.
+type streamingProvider struct {
+	sliceProvider
+}
+
+func (p *streamingProvider) Records(context.Context) ([]*endpoint.Endpoint, error) {
+	return nil, errors.New("records requested as a slice")
+}
+
+func (p *streamingProvider) RecordsIter(_ context.Context, fn func(*endpoint.Endpoint) error) error {
+	for _, r := range p.records {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return p.err
+}
+
+func TestIterRecords(t *testing.T) {
+	records := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+		endpoint.NewEndpoint("c.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+	}
+	errStop := errors.New("stop")
+
+	for _, tc := range []struct {
+		title    string
+		provider Provider
+	}{
+		{"slice", &sliceProvider{records: records}},
+		{"streaming", &streamingProvider{sliceProvider{records: records}}},
+		{"streaming behind default TTLs", NewDefaultTTLProvider(&streamingProvider{sliceProvider{records: records}}, nil)},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			var names []string
+			err := IterRecords(context.Background(), tc.provider, func(e *endpoint.Endpoint) error {
+				names = append(names, e.DNSName)
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"a.example.org", "b.example.org", "c.example.org"}, names)
+
+			names = nil
+			err = IterRecords(context.Background(), tc.provider, func(e *endpoint.Endpoint) error {
+				names = append(names, e.DNSName)
+				if len(names) == 2 {
+					return errStop
+				}
+				return nil
+			})
+			assert.ErrorIs(t, err, errStop)
+			assert.Equal(t, []string{"a.example.org", "b.example.org"}, names)
+		})
+	}
+
+	err := IterRecords(context.Background(), &sliceProvider{err: errors.New("unavailable")}, func(*endpoint.Endpoint) error {
+		t.Fatal("unexpected record")
+		return nil
+	})
+	assert.EqualError(t, err, "unavailable")
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/external-dns/plan"
+)
+
+type metrics struct {
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	changesTotal    *prometheus.CounterVec
+}
+
+func newMetrics(registerer prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		requestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "external_dns",
+				Subsystem: "webhook_server",
+				Name:      "requests_total",
+				Help:      "Number of requests served by the webhook server.",
+			},
+			[]string{"route", "method", "code"},
+		),
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "external_dns",
+				Subsystem: "webhook_server",
+				Name:      "request_duration_seconds",
+				Help:      "Duration of requests served by the webhook server.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"route", "method"},
+		),
+		changesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "external_dns",
+				Subsystem: "webhook_server",
+				Name:      "changes_total",
+				Help:      "Number of record changes received by the webhook server.",
+			},
+			[]string{"action"},
+		),
+	}
+
+	var err error
+	if m.requestsTotal, err = register(registerer, m.requestsTotal); err != nil {
+		return nil, err
+	}
+	if m.requestDuration, err = register(registerer, m.requestDuration); err != nil {
+		return nil, err
+	}
+	if m.changesTotal, err = register(registerer, m.changesTotal); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// register registers c, returning the already registered collector when
+// several servers share a registerer.
+func register[T prometheus.Collector](registerer prometheus.Registerer, c T) (T, error) {
+	if err := registerer.Register(c); err != nil {
+		are := prometheus.AlreadyRegisteredError{}
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+		return c, err
+	}
+	return c, nil
+}
+
+func (m *metrics) observeRequest(route, method, code string, duration time.Duration) {
+	m.requestsTotal.WithLabelValues(route, method, code).Inc()
+	m.requestDuration.WithLabelValues(route, method).Observe(duration.Seconds())
+}
+
+func (m *metrics) observeChanges(changes *plan.Changes) {
+	m.changesTotal.WithLabelValues("create").Add(float64(len(changes.Create)))
+	m.changesTotal.WithLabelValues("update").Add(float64(len(changes.UpdateNew)))
+	m.changesTotal.WithLabelValues("delete").Add(float64(len(changes.Delete)))
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+	"sigs.k8s.io/external-dns/provider/providertest"
+	"sigs.k8s.io/external-dns/provider/webhook"
+)
+
+func newTestServer(t *testing.T, p provider.Provider, opts ...Option) (*Server, *httptest.Server) {
+	t.Helper()
+	s, err := NewServer(p, append([]Option{WithRegistry(prometheus.NewRegistry())}, opts...)...)
+	require.NoError(t, err)
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func TestNegotiate(t *testing.T) {
+	_, ts := newTestServer(t, inmemory.NewInMemoryProvider())
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(AcceptHeader, MediaTypeFormatAndVersion)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, MediaTypeFormatAndVersion, resp.Header.Get(ContentTypeHeader))
+
+	df := endpoint.DomainFilter{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&df))
+
+	req.Header.Set(AcceptHeader, "application/external.dns.webhook+json;version=2")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+}
+
+func TestNegotiateDomainFilterOverride(t *testing.T) {
+	_, ts := newTestServer(t, inmemory.NewInMemoryProvider(), WithDomainFilter(endpoint.NewDomainFilter([]string{"example.org"})))
+
+	resp, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	df := endpoint.DomainFilter{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&df))
+	assert.True(t, df.Match("foo.example.org"))
+	assert.False(t, df.Match("foo.example.com"))
+}
+
+func TestApplyChangesValidation(t *testing.T) {
+	s, ts := newTestServer(t, inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"})))
+
+	for _, tc := range []struct {
+		name     string
+		changes  *plan.Changes
+		expected int
+	}{
+		{
+			name: "valid",
+			changes: &plan.Changes{
+				Create: []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "192.0.2.1")},
+			},
+			expected: http.StatusNoContent,
+		},
+		{
+			name: "missing record type",
+			changes: &plan.Changes{
+				Create: []*endpoint.Endpoint{{DNSName: "bar.example.com", Targets: endpoint.Targets{"192.0.2.1"}}},
+			},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name: "mismatched updates",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "192.0.2.1")},
+			},
+			expected: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.changes)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/records", bytes.NewReader(b))
+			require.NoError(t, err)
+			req.Header.Set(ContentTypeHeader, MediaTypeFormatAndVersion)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tc.expected, resp.StatusCode)
+		})
+	}
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.metrics.changesTotal.WithLabelValues("create")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.metrics.requestsTotal.WithLabelValues("records", http.MethodPost, "204")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(s.metrics.requestsTotal.WithLabelValues("records", http.MethodPost, "400")))
+}
+
+func TestValidateChanges(t *testing.T) {
+	ep := endpoint.NewEndpoint("foo.example.com", endpoint.RecordTypeA, "192.0.2.1")
+	assert.NoError(t, ValidateChanges(&plan.Changes{}))
+	assert.NoError(t, ValidateChanges(&plan.Changes{UpdateOld: []*endpoint.Endpoint{ep}, UpdateNew: []*endpoint.Endpoint{ep}}))
+	assert.Error(t, ValidateChanges(&plan.Changes{Create: []*endpoint.Endpoint{ep}, Delete: []*endpoint.Endpoint{ep}}))
+	assert.Error(t, ValidateChanges(&plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{ep},
+		UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpoint("bar.example.com", endpoint.RecordTypeA, "192.0.2.1")},
+	}))
+	assert.Error(t, ValidateChanges(&plan.Changes{Delete: []*endpoint.Endpoint{nil}}))
+}
+
+// TestWebhookConformance runs the provider conformance suite through the
+// webhook provider against a server built with this package.
+func TestWebhookConformance(t *testing.T) {
+	providertest.Run(t, providertest.Config{
+		Zone: "example.com",
+		NewProvider: func(t *testing.T) provider.Provider {
+			_, ts := newTestServer(t, inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.com"})))
+			p, err := webhook.NewWebhookProvider(ts.URL)
+			require.NoError(t, err)
+			return p
+		},
+	})
+}
+
+func TestServeShutdown(t *testing.T) {
+	s, err := NewServer(inmemory.NewInMemoryProvider(), WithRegistry(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.ListenAndServe(ctx, "127.0.0.1:0") }()
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sdk contains the building blocks for out-of-tree webhook providers.
+//
+// A plugin only has to implement provider.Provider and hand it to NewServer.
+// The server serves the handlers of the api package, used by the in-tree
+// webhook server, and adds media type negotiation, request validation and
+// Prometheus metrics on top, so that plugins stay in sync with the protocol
+// spoken by the webhook provider.
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	webhookapi "sigs.k8s.io/external-dns/provider/webhook/api"
+)
+
+const (
+	// MediaTypeFormatAndVersion is the media type spoken by the webhook provider.
+	MediaTypeFormatAndVersion = webhookapi.MediaTypeFormatAndVersion
+	// ContentTypeHeader is the name of the content type header.
+	ContentTypeHeader = webhookapi.ContentTypeHeader
+	// AcceptHeader is the name of the accept header.
+	AcceptHeader = "Accept"
+)
+
+// Server serves the webhook provider API for a provider.Provider.
+type Server struct {
+	provider     provider.Provider
+	domainFilter *endpoint.DomainFilter
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	registerer   prometheus.Registerer
+	gatherer     prometheus.Gatherer
+	metrics      *metrics
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithTimeouts sets the read and write timeouts of the HTTP server.
+func WithTimeouts(readTimeout, writeTimeout time.Duration) Option {
+	return func(s *Server) {
+		s.readTimeout = readTimeout
+		s.writeTimeout = writeTimeout
+	}
+}
+
+// WithDomainFilter overrides the domain filter returned during negotiation.
+// By default the domain filter of the provider is used.
+func WithDomainFilter(domainFilter endpoint.DomainFilter) Option {
+	return func(s *Server) {
+		s.domainFilter = &domainFilter
+	}
+}
+
+// WithRegistry registers the server metrics with the given registry and
+// serves them on /metrics. By default prometheus.DefaultRegisterer is used.
+func WithRegistry(registry *prometheus.Registry) Option {
+	return func(s *Server) {
+		s.registerer = registry
+		s.gatherer = registry
+	}
+}
+
+// NewServer returns a Server for the given provider.
+func NewServer(p provider.Provider, opts ...Option) (*Server, error) {
+	s := &Server{
+		provider:     p,
+		readTimeout:  5 * time.Second,
+		writeTimeout: 10 * time.Second,
+		registerer:   prometheus.DefaultRegisterer,
+		gatherer:     prometheus.DefaultGatherer,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	m, err := newMetrics(s.registerer)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics = m
+
+	return s, nil
+}
+
+// Handler returns the http.Handler serving the webhook provider API. The
+// routes are served by the handlers of the api package, guarded by media type
+// and payload validation:
+//   - / (GET): negotiates the media type and returns the domain filter
+//   - /records (GET): returns the current records
+//   - /records (POST): applies the changes
+//   - /adjustendpoints (POST): executes the AdjustEndpoints method
+//   - /healthz (GET): liveness probe
+//   - /metrics (GET): Prometheus metrics
+func (s *Server) Handler() http.Handler {
+	p := s.provider
+	if s.domainFilter != nil {
+		p = &domainFilterProvider{Provider: p, domainFilter: *s.domainFilter}
+	}
+	api := &webhookapi.WebhookServer{Provider: p}
+
+	m := http.NewServeMux()
+	m.Handle("/", s.instrument("negotiate", validateNegotiate(http.HandlerFunc(api.NegotiateHandler))))
+	m.Handle("/records", s.instrument("records", s.validateRecords(http.HandlerFunc(api.RecordsHandler))))
+	m.Handle("/adjustendpoints", s.instrument("adjustendpoints", validateAdjustEndpoints(http.HandlerFunc(api.AdjustEndpointsHandler))))
+	m.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+	m.Handle("/metrics", promhttp.HandlerFor(s.gatherer, promhttp.HandlerOpts{}))
+	return m
+}
+
+// ListenAndServe serves the webhook provider API on address until ctx is
+// canceled. Once ctx is done in-flight requests are given the write timeout
+// to complete.
+func (s *Server) ListenAndServe(ctx context.Context, address string) error {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, l)
+}
+
+// Serve serves the webhook provider API on the given listener until ctx is canceled.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{
+		Handler:      s.Handler(),
+		ReadTimeout:  s.readTimeout,
+		WriteTimeout: s.writeTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.writeTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("Failed to shutdown webhook server: %v", err)
+		}
+	}()
+
+	if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// domainFilterProvider overrides the domain filter returned by a provider.
+type domainFilterProvider struct {
+	provider.Provider
+	domainFilter endpoint.DomainFilter
+}
+
+func (p *domainFilterProvider) GetDomainFilter() endpoint.DomainFilter {
+	return p.domainFilter
+}
+
+func validateNegotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/" {
+			http.NotFound(w, req)
+			return
+		}
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !acceptsMediaType(req) {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (s *Server) validateRecords(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			if !acceptsMediaType(req) {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+		case http.MethodPost:
+			if !hasMediaType(req) {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+			var changes plan.Changes
+			if !decodeBody(w, req, &changes) {
+				return
+			}
+			if err := ValidateChanges(&changes); err != nil {
+				log.Errorf("Rejecting invalid changes: %v", err)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.metrics.observeChanges(&changes)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func validateAdjustEndpoints(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		endpoints := []*endpoint.Endpoint{}
+		if !decodeBody(w, req, &endpoints) {
+			return
+		}
+		if err := ValidateEndpoints(endpoints); err != nil {
+			log.Errorf("Rejecting invalid endpoints: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// decodeBody decodes the request body into v and rewinds it for the next
+// handler. It writes a bad request response and returns false on failure.
+func decodeBody(w http.ResponseWriter, req *http.Request, v interface{}) bool {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Errorf("Failed to read request body: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		log.Errorf("Failed to decode request body: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return true
+}
+
+func (s *Server) instrument(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, req)
+		s.metrics.observeRequest(route, req.Method, strconv.Itoa(rw.status), time.Since(start))
+	})
+}
+
+// acceptsMediaType reports whether the client accepts the webhook media type.
+// Clients not sending an Accept header are assumed to accept it.
+func acceptsMediaType(req *http.Request) bool {
+	accept := req.Header.Get(AcceptHeader)
+	return accept == "" || accept == "*/*" || accept == MediaTypeFormatAndVersion
+}
+
+// hasMediaType reports whether the request body is of the webhook media type.
+// Requests without a content type are accepted for backwards compatibility.
+func hasMediaType(req *http.Request) bool {
+	contentType := req.Header.Get(ContentTypeHeader)
+	return contentType == "" || contentType == MediaTypeFormatAndVersion
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
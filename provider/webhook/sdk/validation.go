@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// ValidateEndpoints checks that every endpoint carries a DNS name and a record type.
+func ValidateEndpoints(endpoints []*endpoint.Endpoint) error {
+	for i, ep := range endpoints {
+		if ep == nil {
+			return fmt.Errorf("endpoint %d is null", i)
+		}
+		if ep.DNSName == "" {
+			return fmt.Errorf("endpoint %d has an empty dnsName", i)
+		}
+		if ep.RecordType == "" {
+			return fmt.Errorf("endpoint %s has an empty recordType", ep.DNSName)
+		}
+	}
+	return nil
+}
+
+// ValidateChanges checks that changes are well formed: all endpoints are valid,
+// every UpdateOld entry has a matching UpdateNew entry and no endpoint is
+// created and deleted within the same batch.
+func ValidateChanges(changes *plan.Changes) error {
+	for name, endpoints := range map[string][]*endpoint.Endpoint{
+		"create":    changes.Create,
+		"updateOld": changes.UpdateOld,
+		"updateNew": changes.UpdateNew,
+		"delete":    changes.Delete,
+	} {
+		if err := ValidateEndpoints(endpoints); err != nil {
+			return fmt.Errorf("invalid %s changes: %w", name, err)
+		}
+	}
+
+	if len(changes.UpdateOld) != len(changes.UpdateNew) {
+		return fmt.Errorf("mismatched updates: %d old and %d new endpoints", len(changes.UpdateOld), len(changes.UpdateNew))
+	}
+	updates := make(map[endpoint.EndpointKey]struct{}, len(changes.UpdateOld))
+	for _, ep := range changes.UpdateOld {
+		updates[ep.Key()] = struct{}{}
+	}
+	for _, ep := range changes.UpdateNew {
+		if _, ok := updates[ep.Key()]; !ok {
+			return fmt.Errorf("update of %s has no matching old endpoint", ep)
+		}
+	}
+
+	created := make(map[endpoint.EndpointKey]struct{}, len(changes.Create))
+	for _, ep := range changes.Create {
+		created[ep.Key()] = struct{}{}
+	}
+	for _, ep := range changes.Delete {
+		if _, ok := created[ep.Key()]; ok {
+			return fmt.Errorf("endpoint %s is both created and deleted", ep)
+		}
+	}
+	return nil
+}
@@ -25,12 +25,15 @@ type ZoneTagFilter struct {
 	zoneTags []string
 }
 
-// NewZoneTagFilter returns a new ZoneTagFilter given a list of zone tags
+// NewZoneTagFilter returns a new ZoneTagFilter given a list of zone tags, ignoring empty ones
 func NewZoneTagFilter(tags []string) ZoneTagFilter {
-	if len(tags) == 1 && len(tags[0]) == 0 {
-		tags = []string{}
+	zoneTags := []string{}
+	for _, tag := range tags {
+		if tag != "" {
+			zoneTags = append(zoneTags, tag)
+		}
 	}
-	return ZoneTagFilter{zoneTags: tags}
+	return ZoneTagFilter{zoneTags: zoneTags}
 }
 
 // Match checks whether a zone's set of tags matches the provided tag values
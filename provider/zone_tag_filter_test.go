@@ -53,6 +53,9 @@ func TestZoneTagFilterMatch(t *testing.T) {
 		{
 			"multiple filter matches", []string{"tag1=value1", "tag2=value2"}, map[string]string{"tag2": "value2", "tag1": "value1", "tag3": "value3"}, true,
 		},
+		{
+			"empty filters ignored", []string{"", "tag1=value1", ""}, map[string]string{"tag1": "value1"}, true,
+		},
 	} {
 		zoneTagFilter := NewZoneTagFilter(tc.zoneTagFilter)
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrZoneNotOwned is returned by a ZoneManager when a zone with the requested name exists,
+// but was not created for the requested reference.
+var ErrZoneNotOwned = errors.New("the hosted zone exists but was not created by ExternalDNS for this zone")
+
+// ZoneSpec describes a hosted zone created and configured by a ZoneManager.
+type ZoneSpec struct {
+	// DomainName is the domain name of the zone
+	DomainName string
+	// Private zones are only resolvable from Network
+	Private bool
+	// Network is the network of a private zone, in the format of the provider
+	Network string
+	// Tags are added to the tags of the zone
+	Tags map[string]string
+	// Comment describes the zone when it is created
+	Comment string
+	// Reference uniquely identifies what the zone is created for, so that a zone is only
+	// configured by the reference it was created for
+	Reference string
+}
+
+// ZoneStatus holds the information needed to delegate to a hosted zone.
+type ZoneStatus struct {
+	// ID is the ID of the zone at the provider
+	ID string
+	// NameServers are the name servers of the zone
+	NameServers []string
+	// DSRecords are the DS records of the zone, if it is signed with DNSSEC
+	DSRecords []string
+}
+
+// ZoneManager is implemented by providers which can create and configure hosted zones.
+type ZoneManager interface {
+	// EnsureZone creates the zone unless it exists, configures it and returns its status.
+	// It returns ErrZoneNotOwned when the zone exists but was created for another reference.
+	EnsureZone(ctx context.Context, zone ZoneSpec) (ZoneStatus, error)
+}
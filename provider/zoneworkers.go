@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ForEachZone calls fn for every zone, running at most workers calls concurrently, so that
+// providers managing many zones list and change them in parallel instead of one after the other.
+// Every zone is processed even when some fail, the errors are returned in the order of the zones.
+// A workers value below 2 processes the zones sequentially.
+func ForEachZone(ctx context.Context, zones []string, workers int, fn func(ctx context.Context, zone string) error) error {
+	errs := make([]error, len(zones))
+	if workers < 2 {
+		for i, z := range zones {
+			errs[i] = fn(ctx, z)
+		}
+		return errors.Join(errs...)
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, z := range zones {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, z string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(ctx, z)
+		}(i, z)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// NewZoneChangeLimiter returns the limiter shared by the workers of ForEachZone, allowing perSecond
+// changes per second across all the zones, or nil when perSecond is not positive.
+func NewZoneChangeLimiter(perSecond float64) *rate.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), 1)
+}
+
+// WaitZoneChange blocks until the limiter allows another change to be submitted, so that the
+// workers of ForEachZone together stay within the request rate of the provider API.
+// A nil limiter never blocks.
+func WaitZoneChange(ctx context.Context, limiter *rate.Limiter) error {
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
+
+// RetryZoneChange calls change until it succeeds, fails with an error which is not retryable or
+// was called attempts times, waiting interval between the calls, so that the transient failures
+// of the changes of a zone are retried right away instead of at the next synchronization.
+// Every call, including the retries, first waits for the limiter, which may be nil.
+func RetryZoneChange(ctx context.Context, attempts int, interval time.Duration, limiter *rate.Limiter, retryable func(error) bool, change func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if werr := WaitZoneChange(ctx, limiter); werr != nil {
+			return errors.Join(err, werr)
+		}
+		if err = change(); err == nil || attempt >= attempts || !retryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
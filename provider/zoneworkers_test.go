@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachZone(t *testing.T) {
+	zones := []string{"a.example.org", "b.example.org", "c.example.org", "d.example.org", "e.example.org"}
+
+	for _, workers := range []int{0, 1, 2, 5} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			var (
+				mu        sync.Mutex
+				processed []string
+				running   int32
+				peak      int32
+			)
+			err := ForEachZone(context.Background(), zones, workers, func(_ context.Context, zone string) error {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				mu.Lock()
+				if n > peak {
+					peak = n
+				}
+				processed = append(processed, zone)
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				if zone == "b.example.org" || zone == "d.example.org" {
+					return fmt.Errorf("failed to change %s", zone)
+				}
+				return nil
+			})
+
+			assert.EqualError(t, err, "failed to change b.example.org\nfailed to change d.example.org")
+			assert.ElementsMatch(t, zones, processed)
+			limit := int32(workers)
+			if limit < 1 {
+				limit = 1
+			}
+			assert.LessOrEqual(t, peak, limit)
+		})
+	}
+}
+
+func TestRetryZoneChange(t *testing.T) {
+	errTransient := errors.New("transient")
+	errPermanent := errors.New("permanent")
+	retryable := func(err error) bool { return errors.Is(err, errTransient) }
+
+	for _, tc := range []struct {
+		name     string
+		errs     []error
+		attempts int
+		calls    int
+		err      error
+	}{
+		{name: "success", errs: []error{nil}, attempts: 3, calls: 1},
+		{name: "transient errors retried", errs: []error{errTransient, errTransient, nil}, attempts: 3, calls: 3},
+		{name: "attempts exhausted", errs: []error{errTransient, errTransient, errTransient}, attempts: 3, calls: 3, err: errTransient},
+		{name: "permanent error not retried", errs: []error{errPermanent, nil}, attempts: 3, calls: 1, err: errPermanent},
+		{name: "single attempt", errs: []error{errTransient, nil}, attempts: 1, calls: 1, err: errTransient},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			err := RetryZoneChange(context.Background(), tc.attempts, time.Millisecond, nil, retryable, func() error {
+				calls++
+				return tc.errs[calls-1]
+			})
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.calls, calls)
+		})
+	}
+}
+
+func TestRetryZoneChangeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errTransient := errors.New("transient")
+
+	calls := 0
+	err := RetryZoneChange(ctx, 3, time.Hour, nil, func(error) bool { return true }, func() error {
+		calls++
+		return errTransient
+	})
+	assert.ErrorIs(t, err, errTransient)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestZoneChangeLimiter(t *testing.T) {
+	assert.Nil(t, NewZoneChangeLimiter(0))
+	assert.NoError(t, WaitZoneChange(context.Background(), nil))
+
+	// the workers and the retries share 50 changes per second, the first one being immediate
+	limiter := NewZoneChangeLimiter(50)
+	zones := []string{"a.example.org", "b.example.org", "c.example.org"}
+	start := time.Now()
+	err := ForEachZone(context.Background(), zones, len(zones), func(ctx context.Context, _ string) error {
+		calls := 0
+		return RetryZoneChange(ctx, 2, 0, limiter, func(error) bool { return true }, func() error {
+			calls++
+			if calls == 1 {
+				return errors.New("transient")
+			}
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 5*20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, WaitZoneChange(ctx, limiter), context.Canceled)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// CachedRegistry caches the records of the wrapped registry for a refresh
+// interval independent of the synchronization interval. The cache is
+// invalidated after every call to ApplyChanges, whether it succeeded or not,
+// so that the records written, or those lost to another owner, are read back
+// from the provider during the next synchronization.
+type CachedRegistry struct {
+	registry        Registry
+	refreshInterval time.Duration
+
+	mutex       sync.Mutex
+	records     []*endpoint.Endpoint
+	refreshTime time.Time
+	// fromCache is set when the last call to Records was served from the cache
+	fromCache bool
+}
+
+// NewCachedRegistry returns a new CachedRegistry object.
+func NewCachedRegistry(registry Registry, refreshInterval time.Duration) *CachedRegistry {
+	return &CachedRegistry{
+		registry:        registry,
+		refreshInterval: refreshInterval,
+	}
+}
+
+func (im *CachedRegistry) GetDomainFilter() endpoint.DomainFilter {
+	return im.registry.GetDomainFilter()
+}
+
+func (im *CachedRegistry) OwnerID() string {
+	return im.registry.OwnerID()
+}
+
+// Records returns the cached records, refreshing them from the wrapped
+// registry when they are older than the refresh interval.
+func (im *CachedRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	im.mutex.Lock()
+	defer im.mutex.Unlock()
+
+	if im.records != nil && time.Since(im.refreshTime) < im.refreshInterval {
+		log.Debug("Using cached registry records.")
+		im.fromCache = true
+		return im.records, nil
+	}
+
+	records, err := im.registry.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	im.records = records
+	im.refreshTime = time.Now()
+	im.fromCache = false
+	return records, nil
+}
+
+// ApplyChanges propagates the changes to the wrapped registry and invalidates the cache.
+func (im *CachedRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	// the cached records may be outdated, don't let the provider rely on them
+	if im.servedFromCache() {
+		ctx = context.WithValue(ctx, provider.RecordsContextKey, nil)
+	}
+	defer im.Invalidate()
+	return im.registry.ApplyChanges(ctx, changes)
+}
+
+// AdjustEndpoints modifies the endpoints as needed by the specific provider.
+func (im *CachedRegistry) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	return im.registry.AdjustEndpoints(endpoints)
+}
+
+// CollectGarbage collects the garbage of the wrapped registry.
+func (im *CachedRegistry) CollectGarbage(ctx context.Context) error {
+	return CollectGarbage(ctx, im.registry)
+}
+
+// Restore fills the cache with the snapshot of the store, unless it is older than the refresh interval or was taken
+// from the registry of another owner, so that the first synchronization after a restart doesn't need to read all
+// records from the provider. The snapshot is consumed: it is removed from the store, so that a snapshot outdated by
+// later changes is never restored, e.g. after a crash.
+func (im *CachedRegistry) Restore(ctx context.Context, store SnapshotStore) error {
+	snapshot, err := store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if err := store.Clear(ctx); err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return nil
+	}
+	if snapshot.OwnerID != im.registry.OwnerID() {
+		log.Infof("Ignoring the registry snapshot of owner %q", snapshot.OwnerID)
+		return nil
+	}
+	if age := time.Since(snapshot.RefreshTime); age >= im.refreshInterval {
+		log.Infof("Ignoring the registry snapshot taken %s ago", age.Round(time.Second))
+		return nil
+	}
+
+	im.mutex.Lock()
+	defer im.mutex.Unlock()
+	if snapshot.Records == nil {
+		snapshot.Records = []*endpoint.Endpoint{}
+	}
+	im.records = snapshot.Records
+	im.refreshTime = snapshot.RefreshTime
+	log.Infof("Restored %d registry records from the snapshot taken at %s", len(im.records), im.refreshTime.Format(time.RFC3339))
+	return nil
+}
+
+// Persist saves the cached records to the store, e.g. before shutting down. Nothing is saved when the cache is
+// empty or expired.
+func (im *CachedRegistry) Persist(ctx context.Context, store SnapshotStore) error {
+	im.mutex.Lock()
+	if im.records == nil || time.Since(im.refreshTime) >= im.refreshInterval {
+		im.mutex.Unlock()
+		return nil
+	}
+	snapshot := &Snapshot{OwnerID: im.registry.OwnerID(), RefreshTime: im.refreshTime, Records: im.records}
+	im.mutex.Unlock()
+
+	if err := store.Save(ctx, snapshot); err != nil {
+		return err
+	}
+	log.Infof("Saved %d registry records to the snapshot", len(snapshot.Records))
+	return nil
+}
+
+// Invalidate drops the cached records, so that the next call to Records reads them from the wrapped registry.
+func (im *CachedRegistry) Invalidate() {
+	im.mutex.Lock()
+	defer im.mutex.Unlock()
+	im.records = nil
+}
+
+func (im *CachedRegistry) servedFromCache() bool {
+	im.mutex.Lock()
+	defer im.mutex.Unlock()
+	return im.fromCache
+}
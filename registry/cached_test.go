@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+)
+
+// countingRegistry counts the calls to Records and records the context passed to ApplyChanges.
+type countingRegistry struct {
+	Registry
+	records    int
+	applyErr   error
+	applyCtxOK bool
+}
+
+func (r *countingRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	r.records++
+	return r.Registry.Records(ctx)
+}
+
+func (r *countingRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	_, r.applyCtxOK = ctx.Value(provider.RecordsContextKey).([]*endpoint.Endpoint)
+	if r.applyErr != nil {
+		return r.applyErr
+	}
+	return r.Registry.ApplyChanges(ctx, changes)
+}
+
+func newCountingRegistry(t *testing.T) *countingRegistry {
+	p := inmemory.NewInMemoryProvider()
+	require.NoError(t, p.CreateZone(testZone))
+	noop, err := NewNoopRegistry(p)
+	require.NoError(t, err)
+	return &countingRegistry{Registry: noop}
+}
+
+func TestCachedRegistryRecords(t *testing.T) {
+	ctx := context.Background()
+	counting := newCountingRegistry(t)
+	r := NewCachedRegistry(counting, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		_, err := r.Records(ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, counting.records)
+
+	r.Invalidate()
+	_, err := r.Records(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, counting.records)
+}
+
+func TestCachedRegistryRecordsExpire(t *testing.T) {
+	ctx := context.Background()
+	counting := newCountingRegistry(t)
+	r := NewCachedRegistry(counting, time.Nanosecond)
+
+	_, err := r.Records(ctx)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = r.Records(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, counting.records)
+}
+
+func TestCachedRegistryApplyChangesInvalidates(t *testing.T) {
+	ctx := context.Background()
+	counting := newCountingRegistry(t)
+	r := NewCachedRegistry(counting, time.Hour)
+
+	records, err := r.Records(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	records, err = r.Records(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, provider.RecordsContextKey, records)
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("foo.test-zone.example.org", endpoint.RecordTypeA, "1.2.3.4")},
+	}))
+	assert.False(t, counting.applyCtxOK, "cached records must not be passed to the provider")
+
+	records, err = r.Records(ctx)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, 2, counting.records)
+
+	counting.applyErr = errors.New("owned by another instance")
+	assert.Error(t, r.ApplyChanges(ctx, &plan.Changes{}))
+	_, err = r.Records(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, counting.records, "failed changes invalidate the cache")
+}
+
+func TestCachedRegistryPassesFreshRecords(t *testing.T) {
+	ctx := context.Background()
+	counting := newCountingRegistry(t)
+	r := NewCachedRegistry(counting, time.Hour)
+
+	records, err := r.Records(ctx)
+	require.NoError(t, err)
+	ctx = context.WithValue(ctx, provider.RecordsContextKey, records)
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{}))
+	assert.True(t, counting.applyCtxOK, "records just read are passed to the provider")
+}
+
+func TestCachedRegistrySnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "snapshot.json.gz"))
+	records := []*endpoint.Endpoint{
+		newEndpointWithOwner("foo.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+	}
+
+	// nothing is saved before the records were read
+	r := NewCachedRegistry(newCountingRegistry(t), time.Hour)
+	require.NoError(t, r.Persist(ctx, store))
+	snapshot, err := store.Load(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	r.records = records
+	r.refreshTime = time.Now().Add(-time.Minute)
+	require.NoError(t, r.Persist(ctx, store))
+
+	counting := newCountingRegistry(t)
+	restored := NewCachedRegistry(counting, time.Hour)
+	require.NoError(t, restored.Restore(ctx, store))
+	got, err := restored.Records(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, records, got)
+	assert.Equal(t, 0, counting.records)
+
+	// the snapshot is consumed
+	snapshot, err = store.Load(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+}
+
+func TestCachedRegistrySnapshotIgnored(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "snapshot.json.gz"))
+
+	for _, snapshot := range []*Snapshot{
+		{OwnerID: "other", RefreshTime: time.Now()},
+		{RefreshTime: time.Now().Add(-2 * time.Hour)},
+	} {
+		require.NoError(t, store.Save(ctx, snapshot))
+		counting := newCountingRegistry(t)
+		r := NewCachedRegistry(counting, time.Hour)
+		require.NoError(t, r.Restore(ctx, store))
+		_, err := r.Records(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, counting.records)
+	}
+}
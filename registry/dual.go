@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// DualRegistry writes ownership information to two registries while reading
+// it preferably from the primary one. It is meant to be used during the
+// migration from one registry to another, so that records never appear
+// unowned while the new registry is being populated.
+//
+// Both registries must be backed by the same DNS provider. The secondary
+// registry has to be created on top of NewOwnershipOnlyProvider so that DNS
+// records are only changed once, by the primary registry.
+type DualRegistry struct {
+	primary   Registry
+	secondary Registry
+}
+
+// NewDualRegistry returns a new DualRegistry object.
+func NewDualRegistry(primary, secondary Registry) (*DualRegistry, error) {
+	if primary == nil || secondary == nil {
+		return nil, errors.New("both a primary and a secondary registry are required")
+	}
+	if primary.OwnerID() != secondary.OwnerID() {
+		return nil, fmt.Errorf("owner ids of the primary (%q) and secondary (%q) registries must match", primary.OwnerID(), secondary.OwnerID())
+	}
+	return &DualRegistry{
+		primary:   primary,
+		secondary: secondary,
+	}, nil
+}
+
+func (im *DualRegistry) GetDomainFilter() endpoint.DomainFilter {
+	return im.primary.GetDomainFilter()
+}
+
+func (im *DualRegistry) OwnerID() string {
+	return im.primary.OwnerID()
+}
+
+// Records returns the records of the primary registry. Ownership labels of
+// records unknown to the primary registry are taken from the secondary one.
+// Records only the primary registry exposes are bookkeeping records of the
+// secondary registry (e.g. TXT ownership records) and are omitted, so that
+// they are not removed while both registries are in use.
+func (im *DualRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	primaryRecords, err := im.primary.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secondaryRecords, err := im.secondary.Records(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading secondary registry: %w", err)
+	}
+
+	secondaryLabels := make(map[endpoint.EndpointKey]endpoint.Labels, len(secondaryRecords))
+	for _, r := range secondaryRecords {
+		secondaryLabels[r.Key()] = r.Labels
+	}
+
+	endpoints := make([]*endpoint.Endpoint, 0, len(primaryRecords))
+	for _, r := range primaryRecords {
+		labels, ok := secondaryLabels[r.Key()]
+		if !ok {
+			continue
+		}
+		if r.Labels[endpoint.OwnerLabelKey] == "" && labels[endpoint.OwnerLabelKey] != "" {
+			if r.Labels == nil {
+				r.Labels = endpoint.NewLabels()
+			}
+			for k, v := range labels {
+				r.Labels[k] = v
+			}
+		}
+		endpoints = append(endpoints, r)
+	}
+	return endpoints, nil
+}
+
+// ApplyChanges applies the changes through the primary registry and then
+// records the ownership in the secondary registry.
+func (im *DualRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	secondaryChanges := copyChanges(changes)
+	if err := im.primary.ApplyChanges(ctx, changes); err != nil {
+		return err
+	}
+	if err := im.secondary.ApplyChanges(ctx, secondaryChanges); err != nil {
+		return fmt.Errorf("updating secondary registry: %w", err)
+	}
+	return nil
+}
+
+// AdjustEndpoints modifies the endpoints as needed by the specific provider
+func (im *DualRegistry) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	return im.primary.AdjustEndpoints(endpoints)
+}
+
+// CollectGarbage collects the garbage of both registries.
+func (im *DualRegistry) CollectGarbage(ctx context.Context) error {
+	if err := CollectGarbage(ctx, im.primary); err != nil {
+		return err
+	}
+	if err := CollectGarbage(ctx, im.secondary); err != nil {
+		return fmt.Errorf("collecting the garbage of the secondary registry: %w", err)
+	}
+	return nil
+}
+
+func copyChanges(changes *plan.Changes) *plan.Changes {
+	copyEndpoints := func(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+		result := make([]*endpoint.Endpoint, 0, len(endpoints))
+		for _, ep := range endpoints {
+			result = append(result, ep.DeepCopy())
+		}
+		return result
+	}
+	return &plan.Changes{
+		Create:    copyEndpoints(changes.Create),
+		UpdateOld: copyEndpoints(changes.UpdateOld),
+		UpdateNew: copyEndpoints(changes.UpdateNew),
+		Delete:    copyEndpoints(changes.Delete),
+	}
+}
+
+// ownershipOnlyProvider only forwards changes of registry bookkeeping records
+// to the wrapped provider.
+type ownershipOnlyProvider struct {
+	provider.Provider
+}
+
+// NewOwnershipOnlyProvider wraps a provider so that ApplyChanges ignores all
+// records except registry ownership records. It is used to back the
+// secondary registry of a DualRegistry.
+func NewOwnershipOnlyProvider(p provider.Provider) provider.Provider {
+	return ownershipOnlyProvider{Provider: p}
+}
+
+// RecordsIter streams the records of the wrapped provider, see provider.RecordsIterator.
+func (p ownershipOnlyProvider) RecordsIter(ctx context.Context, fn func(*endpoint.Endpoint) error) error {
+	return provider.IterRecords(ctx, p.Provider, fn)
+}
+
+func (p ownershipOnlyProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	filtered := &plan.Changes{
+		Create:    filterOwnershipRecords(changes.Create),
+		UpdateOld: filterOwnershipRecords(changes.UpdateOld),
+		UpdateNew: filterOwnershipRecords(changes.UpdateNew),
+		Delete:    filterOwnershipRecords(changes.Delete),
+	}
+	if !filtered.HasChanges() {
+		return nil
+	}
+	return p.Provider.ApplyChanges(ctx, filtered)
+}
+
+func filterOwnershipRecords(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	var result []*endpoint.Endpoint
+	for _, ep := range endpoints {
+		if ep.Labels[endpoint.OwnedRecordLabelKey] != "" {
+			result = append(result, ep)
+		}
+	}
+	return result
+}
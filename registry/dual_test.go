@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+)
+
+var (
+	_ Registry         = &DualRegistry{}
+	_ GarbageCollector = &DualRegistry{}
+	_ GarbageCollector = &CachedRegistry{}
+	_ GarbageCollector = &DynamoDBRegistry{}
+
+	_ provider.RecordsIterator = ownershipOnlyProvider{}
+)
+
+func newDualTestRegistries(t *testing.T) (*inmemory.InMemoryProvider, *TXTRegistry, *TXTRegistry) {
+	t.Helper()
+	p := inmemory.NewInMemoryProvider()
+	require.NoError(t, p.CreateZone(testZone))
+	primary, err := NewTXTRegistry(p, "new-", "", "owner", 0, "", []string{endpoint.RecordTypeA}, nil, false, nil)
+	require.NoError(t, err)
+	secondary, err := NewTXTRegistry(NewOwnershipOnlyProvider(p), "", "", "owner", 0, "", []string{endpoint.RecordTypeA}, nil, false, nil)
+	require.NoError(t, err)
+	return p, primary, secondary
+}
+
+func TestNewDualRegistry(t *testing.T) {
+	_, primary, secondary := newDualTestRegistries(t)
+
+	_, err := NewDualRegistry(primary, nil)
+	assert.Error(t, err)
+
+	other, err := NewTXTRegistry(inmemory.NewInMemoryProvider(), "", "", "other", 0, "", nil, nil, false, nil)
+	require.NoError(t, err)
+	_, err = NewDualRegistry(primary, other)
+	assert.Error(t, err)
+
+	r, err := NewDualRegistry(primary, secondary)
+	require.NoError(t, err)
+	assert.Equal(t, "owner", r.OwnerID())
+}
+
+func TestDualRegistryApplyChanges(t *testing.T) {
+	ctx := context.Background()
+	p, primary, secondary := newDualTestRegistries(t)
+	r, err := NewDualRegistry(primary, secondary)
+	require.NoError(t, err)
+
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "")},
+	}))
+
+	records, err := p.Records(ctx)
+	require.NoError(t, err)
+	names := []string{}
+	for _, record := range records {
+		names = append(names, record.RecordType+" "+record.DNSName)
+	}
+	assert.ElementsMatch(t, []string{
+		"A foo.test-zone.example.org",
+		"TXT new-foo.test-zone.example.org",
+		"TXT new-a-foo.test-zone.example.org",
+		"TXT foo.test-zone.example.org",
+		"TXT a-foo.test-zone.example.org",
+	}, names)
+
+	owned, err := r.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, owned, 1)
+	assert.Equal(t, "owner", owned[0].Labels[endpoint.OwnerLabelKey])
+
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{Delete: owned}))
+	records, err = p.Records(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestDualRegistryRecordsFallsBackToSecondary(t *testing.T) {
+	ctx := context.Background()
+	p, primary, _ := newDualTestRegistries(t)
+
+	// records created before the migration are only known to the old registry
+	old, err := NewTXTRegistry(p, "", "", "owner", 0, "", []string{endpoint.RecordTypeA}, nil, false, nil)
+	require.NoError(t, err)
+	require.NoError(t, p.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			newEndpointWithOwner("a-foo.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, ""),
+		},
+	}))
+
+	primaryRecords, err := primary.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, primaryRecords, 1)
+	assert.Empty(t, primaryRecords[0].Labels[endpoint.OwnerLabelKey])
+
+	r, err := NewDualRegistry(primary, old)
+	require.NoError(t, err)
+	records, err := r.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "owner", records[0].Labels[endpoint.OwnerLabelKey])
+}
+
+func TestOwnershipOnlyProvider(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	require.NoError(t, p.CreateZone(testZone))
+
+	txt := newEndpointWithOwner("txt.test-zone.example.org", "\"heritage=external-dns,external-dns/owner=owner\"", endpoint.RecordTypeTXT, "")
+	txt.Labels[endpoint.OwnedRecordLabelKey] = "foo.test-zone.example.org"
+	require.NoError(t, NewOwnershipOnlyProvider(p).ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, ""),
+			txt,
+		},
+	}))
+
+	records, err := p.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, endpoint.RecordTypeTXT, records[0].RecordType)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/sets"
 
@@ -34,6 +35,30 @@ import (
 	"sigs.k8s.io/external-dns/provider"
 )
 
+var (
+	dynamodbOrphanedItems = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "registry",
+			Name:      "dynamodb_orphaned_items",
+			Help:      "Number of DynamoDB registry items owned by this instance without a corresponding DNS record.",
+		},
+	)
+	dynamodbOrphanedItemsDeletedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "registry",
+			Name:      "dynamodb_orphaned_items_deleted_total",
+			Help:      "Number of orphaned DynamoDB registry items deleted.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(dynamodbOrphanedItems)
+	prometheus.MustRegister(dynamodbOrphanedItemsDeletedTotal)
+}
+
 // DynamoDBAPI is the subset of the AWS Route53 API that we actually use.  Add methods as required. Signatures must match exactly.
 type DynamoDBAPI interface {
 	DescribeTableWithContext(ctx aws.Context, input *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error)
@@ -57,8 +82,11 @@ type DynamoDBRegistry struct {
 	txtEncryptAESKey    []byte
 
 	// cache the dynamodb records owned by us.
-	labels         map[endpoint.EndpointKey]endpoint.Labels
-	orphanedLabels sets.Set[endpoint.EndpointKey]
+	labels map[endpoint.EndpointKey]endpoint.Labels
+	// the dynamodb records owned by us without a DNS record, with the time they were first found orphaned.
+	orphanedLabels map[endpoint.EndpointKey]time.Time
+	// orphaned dynamodb records are garbage collected once they are orphaned for longer than this period.
+	gcGracePeriod time.Duration
 
 	// cache the records in memory and update on an interval instead.
 	recordsCache            []*endpoint.Endpoint
@@ -72,7 +100,9 @@ const dynamodbAttributeMigrate = "dynamodb/needs-migration"
 var dynamodbMaxBatchSize uint8 = 25
 
 // NewDynamoDBRegistry returns a new DynamoDBRegistry object.
-func NewDynamoDBRegistry(provider provider.Provider, ownerID string, dynamodbAPI DynamoDBAPI, table string, txtPrefix, txtSuffix, txtWildcardReplacement string, managedRecordTypes, excludeRecordTypes []string, txtEncryptAESKey []byte, cacheInterval time.Duration) (*DynamoDBRegistry, error) {
+// When gcGracePeriod is zero, orphaned dynamodb records are deleted together with the next changes.
+// Otherwise they are garbage collected after every synchronization, once orphaned for longer than gcGracePeriod.
+func NewDynamoDBRegistry(provider provider.Provider, ownerID string, dynamodbAPI DynamoDBAPI, table string, txtPrefix, txtSuffix, txtWildcardReplacement string, managedRecordTypes, excludeRecordTypes []string, txtEncryptAESKey []byte, cacheInterval time.Duration, gcGracePeriod time.Duration) (*DynamoDBRegistry, error) {
 	if ownerID == "" {
 		return nil, errors.New("owner id cannot be empty")
 	}
@@ -102,6 +132,7 @@ func NewDynamoDBRegistry(provider provider.Provider, ownerID string, dynamodbAPI
 		excludeRecordTypes:  excludeRecordTypes,
 		txtEncryptAESKey:    txtEncryptAESKey,
 		cacheInterval:       cacheInterval,
+		gcGracePeriod:       gcGracePeriod,
 	}, nil
 }
 
@@ -166,7 +197,7 @@ func (im *DynamoDBRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint,
 		endpoints = append(endpoints, record)
 	}
 
-	im.orphanedLabels = orphanedLabels
+	im.setOrphanedLabels(orphanedLabels)
 
 	// Migrate label data from TXT registry.
 	if len(labelMap) > 0 {
@@ -175,14 +206,8 @@ func (im *DynamoDBRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint,
 				continue
 			}
 
-			dnsNameSplit := strings.Split(ep.DNSName, ".")
-			// If specified, replace a leading asterisk in the generated txt record name with some other string
-			if im.wildcardReplacement != "" && dnsNameSplit[0] == "*" {
-				dnsNameSplit[0] = im.wildcardReplacement
-			}
-			dnsName := strings.Join(dnsNameSplit, ".")
 			key := endpoint.EndpointKey{
-				DNSName:       dnsName,
+				DNSName:       ownershipName(ep.DNSName, im.wildcardReplacement),
 				SetIdentifier: ep.SetIdentifier,
 			}
 			if ep.RecordType == endpoint.RecordTypeAAAA {
@@ -237,7 +262,7 @@ func (im *DynamoDBRegistry) ApplyChanges(ctx context.Context, changes *plan.Chan
 		if oldLabels == nil {
 			statements = im.appendInsert(statements, key, r.Labels)
 		} else {
-			im.orphanedLabels.Delete(key)
+			delete(im.orphanedLabels, key)
 			statements = im.appendUpdate(statements, key, oldLabels, r.Labels)
 		}
 
@@ -326,15 +351,81 @@ func (im *DynamoDBRegistry) ApplyChanges(ctx context.Context, changes *plan.Chan
 		return err
 	}
 
-	statements = make([]*dynamodb.BatchStatementRequest, 0, len(filteredChanges.Delete)+len(im.orphanedLabels))
+	orphans := im.expiredOrphanedLabels()
+	statements = make([]*dynamodb.BatchStatementRequest, 0, len(filteredChanges.Delete)+len(orphans))
 	for _, r := range filteredChanges.Delete {
 		statements = im.appendDelete(statements, r.Key())
 	}
-	for r := range im.orphanedLabels {
+	for _, r := range orphans {
+		statements = im.appendDelete(statements, r)
+	}
+	if err := im.executeDeletes(ctx, statements); err != nil {
+		return err
+	}
+	im.removeOrphanedLabels(orphans)
+	return nil
+}
+
+// setOrphanedLabels records the given keys as orphaned, keeping the time of
+// keys which were already orphaned before.
+func (im *DynamoDBRegistry) setOrphanedLabels(keys sets.Set[endpoint.EndpointKey]) {
+	now := time.Now()
+	orphanedLabels := make(map[endpoint.EndpointKey]time.Time, len(keys))
+	for key := range keys {
+		if since, ok := im.orphanedLabels[key]; ok {
+			orphanedLabels[key] = since
+		} else {
+			orphanedLabels[key] = now
+		}
+	}
+	im.orphanedLabels = orphanedLabels
+	dynamodbOrphanedItems.Set(float64(len(orphanedLabels)))
+}
+
+// expiredOrphanedLabels returns the keys orphaned for longer than the garbage collection grace period.
+func (im *DynamoDBRegistry) expiredOrphanedLabels() []endpoint.EndpointKey {
+	var keys []endpoint.EndpointKey
+	for key, since := range im.orphanedLabels {
+		if time.Since(since) >= im.gcGracePeriod {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (im *DynamoDBRegistry) removeOrphanedLabels(keys []endpoint.EndpointKey) {
+	for _, key := range keys {
+		delete(im.labels, key)
+		delete(im.orphanedLabels, key)
+	}
+	dynamodbOrphanedItems.Set(float64(len(im.orphanedLabels)))
+	dynamodbOrphanedItemsDeletedTotal.Add(float64(len(keys)))
+}
+
+// CollectGarbage deletes the dynamodb records which are orphaned for longer than the grace period, if one is set.
+// Otherwise they are deleted together with the next changes.
+func (im *DynamoDBRegistry) CollectGarbage(ctx context.Context) error {
+	if im.gcGracePeriod <= 0 {
+		return nil
+	}
+	orphans := im.expiredOrphanedLabels()
+	if len(orphans) == 0 {
+		return nil
+	}
+
+	statements := make([]*dynamodb.BatchStatementRequest, 0, len(orphans))
+	for _, r := range orphans {
+		log.Infof("Deleting dynamodb record %v orphaned since %s", r, im.orphanedLabels[r].Format(time.RFC3339))
 		statements = im.appendDelete(statements, r)
-		delete(im.labels, r)
 	}
-	im.orphanedLabels = nil
+	if err := im.executeDeletes(ctx, statements); err != nil {
+		return err
+	}
+	im.removeOrphanedLabels(orphans)
+	return nil
+}
+
+func (im *DynamoDBRegistry) executeDeletes(ctx context.Context, statements []*dynamodb.BatchStatementRequest) error {
 	return im.executeStatements(ctx, statements, func(request *dynamodb.BatchStatementRequest, response *dynamodb.BatchStatementResponse) error {
 		im.labels = nil
 		return fmt.Errorf("deleting dynamodb record %q: %s: %s", aws.StringValue(request.Parameters[0].S), aws.StringValue(response.Error.Code), aws.StringValue(response.Error.Message))
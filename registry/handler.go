@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// NewRecordsHandler returns an http.Handler listing the records returned by records, typically the
+// registry records of the last synchronization, together with their labels, such as owner, cluster,
+// resource and commit, as JSON. The provider is not called when serving a request.
+// The optional "name", "owner", "cluster" and "resource" query parameters restrict the records returned.
+func NewRecordsHandler(records func() []*endpoint.Endpoint) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := req.URL.Query()
+		name := strings.TrimSuffix(query.Get("name"), ".")
+		synced := records()
+		result := make([]*endpoint.Endpoint, 0, len(synced))
+		for _, record := range synced {
+			if name != "" && strings.TrimSuffix(record.DNSName, ".") != name {
+				continue
+			}
+			if owner := query.Get("owner"); owner != "" && record.Labels[endpoint.OwnerLabelKey] != owner {
+				continue
+			}
+			if cluster := query.Get("cluster"); cluster != "" && record.Labels[endpoint.ClusterLabelKey] != cluster {
+				continue
+			}
+			if resource := query.Get("resource"); resource != "" && record.Labels[endpoint.ResourceLabelKey] != resource {
+				continue
+			}
+			result = append(result, record)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			log.Errorf("Failed to encode records for the debug endpoint: %v", err)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+)
+
+func TestRecordsHandler(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	r, err := NewTXTRegistry(p, "", "", "owner", 0, "", []string{}, []string{}, false, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			{
+				DNSName:    "foo.test-zone.example.org",
+				Targets:    endpoint.Targets{"1.2.3.4"},
+				RecordType: endpoint.RecordTypeA,
+				Labels: endpoint.Labels{
+					endpoint.ResourceLabelKey: "ingress/default/foo",
+					endpoint.ClusterLabelKey:  "prod-eu-1",
+					endpoint.CommitLabelKey:   "1f3a2b4",
+				},
+			},
+			{
+				DNSName:    "bar.test-zone.example.org",
+				Targets:    endpoint.Targets{"1.2.3.5"},
+				RecordType: endpoint.RecordTypeA,
+				Labels: endpoint.Labels{
+					endpoint.ResourceLabelKey: "service/default/bar",
+					endpoint.ClusterLabelKey:  "prod-us-1",
+				},
+			},
+		},
+	}))
+
+	synced, err := r.Records(ctx)
+	require.NoError(t, err)
+	handler := NewRecordsHandler(func() []*endpoint.Endpoint { return synced })
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/records?name=foo.test-zone.example.org.", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var records []*endpoint.Endpoint
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, "owner", records[0].Labels[endpoint.OwnerLabelKey])
+	assert.Equal(t, "prod-eu-1", records[0].Labels[endpoint.ClusterLabelKey])
+	assert.Equal(t, "1f3a2b4", records[0].Labels[endpoint.CommitLabelKey])
+	kind, namespace, name := records[0].Labels.Resource()
+	assert.Equal(t, []string{"ingress", "default", "foo"}, []string{kind, namespace, name})
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/records?cluster=prod-us-1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, "bar.test-zone.example.org", records[0].DNSName)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/records?resource=ingress/default/foo", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, "foo.test-zone.example.org", records[0].DNSName)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/records", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
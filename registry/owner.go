@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// ownerIDSeparator separates the owner ID of the instance from the part of the owner ID derived from the namespace.
+const ownerIDSeparator = "/"
+
+// NamespaceOwnerFunc returns the part of the owner ID of the records of the resources in a namespace which
+// follows the owner ID of the instance, or an empty string for records owned by the instance itself.
+type NamespaceOwnerFunc func(namespace string) (string, error)
+
+// namespaceOwnerData is the data the owner ID template is rendered with.
+type namespaceOwnerData struct {
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// NewNamespaceOwners returns a NamespaceOwnerFunc rendering the given template with the name, labels and
+// annotations of the namespace, e.g. {{ .Namespace }} or {{ index .Annotations "example.org/tenant" }}.
+func NewNamespaceOwners(ctx context.Context, kubeClient kubernetes.Interface, text string) (NamespaceOwnerFunc, error) {
+	tmpl, err := template.New("owner-id").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the owner ID template: %w", err)
+	}
+
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	namespaces := factory.Core().V1().Namespaces()
+	namespaces.Informer()
+	factory.Start(ctx.Done())
+	syncCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), namespaces.Informer().HasSynced) {
+		return nil, fmt.Errorf("failed to sync the namespaces cache")
+	}
+	return newNamespaceOwnerFunc(tmpl, namespaces.Lister()), nil
+}
+
+func newNamespaceOwnerFunc(tmpl *template.Template, lister corev1listers.NamespaceLister) NamespaceOwnerFunc {
+	return func(namespace string) (string, error) {
+		ns, err := lister.Get(namespace)
+		if err != nil {
+			return "", err
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, namespaceOwnerData{Namespace: ns.Name, Labels: ns.Labels, Annotations: ns.Annotations}); err != nil {
+			return "", fmt.Errorf("rendering the owner ID of namespace %s: %w", namespace, err)
+		}
+		owner := strings.TrimSpace(b.String())
+		// the owner ID is stored in the comma-separated key=value labels of the TXT records
+		if strings.ContainsAny(owner, ",=\" \t\n") {
+			return "", fmt.Errorf("the owner ID %q of namespace %s must not contain commas, equal signs, quotes or spaces", owner, namespace)
+		}
+		return owner, nil
+	}
+}
+
+// SetNamespaceOwners derives the owner IDs of the records from the namespace of their resource: the records
+// of the resources in a namespace are owned by the owner ID of the instance followed by a slash and the part
+// returned by namespaceOwners. The registry reports them as owned by the instance.
+func (im *TXTRegistry) SetNamespaceOwners(namespaceOwners NamespaceOwnerFunc) {
+	im.namespaceOwners = namespaceOwners
+}
+
+// ownerFor returns the owner ID the records of ep are created or updated with.
+func (im *TXTRegistry) ownerFor(ep *endpoint.Endpoint) string {
+	if im.namespaceOwners == nil {
+		return im.ownerID
+	}
+	_, namespace, _ := ep.Labels.Resource()
+	if namespace == "" {
+		return im.ownerID
+	}
+	suffix, err := im.namespaceOwners(namespace)
+	if err != nil {
+		log.Warnf("Failed to derive the owner ID of %s from its namespace, keeping its owner: %v", ep.DNSName, err)
+		return im.currentOwner(ep)
+	}
+	if suffix == "" {
+		return im.ownerID
+	}
+	return im.ownerID + ownerIDSeparator + suffix
+}
+
+// currentOwner returns the owner ID the records of ep are stored with.
+func (im *TXTRegistry) currentOwner(ep *endpoint.Endpoint) string {
+	if owner, ok := im.namespaceOwnerIDs[ep.Key()]; ok {
+		return owner
+	}
+	return ep.Labels[endpoint.OwnerLabelKey]
+}
+
+// normalizeOwner reports the records owned by an owner ID derived from a namespace as owned by the instance,
+// remembering their actual owner ID, and updates the records whose owner ID should be another one.
+func (im *TXTRegistry) normalizeOwner(ep *endpoint.Endpoint) {
+	if im.namespaceOwners == nil {
+		return
+	}
+	owner := ep.Labels[endpoint.OwnerLabelKey]
+	if strings.HasPrefix(owner, im.ownerID+ownerIDSeparator) {
+		im.namespaceOwnerIDs[ep.Key()] = owner
+		ep.Labels[endpoint.OwnerLabelKey] = im.ownerID
+	} else if owner != im.ownerID {
+		return
+	}
+	if !plan.IsManagedRecord(ep.RecordType, im.managedRecordTypes, im.excludeRecordTypes) {
+		return
+	}
+	if _, ok := ep.GetProviderSpecificProperty(providerSpecificForceUpdate); !ok && im.ownerFor(ep) != im.currentOwner(ep) {
+		ep.WithProviderSpecific(providerSpecificForceUpdate, "true")
+	}
+}
+
+// setNamespaceOwnerID remembers the owner ID the records of ep are stored with.
+func (im *TXTRegistry) setNamespaceOwnerID(ep *endpoint.Endpoint, owner string) {
+	if owner == im.ownerID {
+		delete(im.namespaceOwnerIDs, ep.Key())
+		return
+	}
+	im.namespaceOwnerIDs[ep.Key()] = owner
+}
+
+// generateOwnedTXTRecord generates the TXT records of r stored with the given owner ID, leaving r owned by the
+// instance.
+func (im *TXTRegistry) generateOwnedTXTRecord(r *endpoint.Endpoint, owner string) []*endpoint.Endpoint {
+	if owner == im.ownerID {
+		return im.generateTXTRecord(r)
+	}
+	r.Labels[endpoint.OwnerLabelKey] = owner
+	records := im.generateTXTRecord(r)
+	r.Labels[endpoint.OwnerLabelKey] = im.ownerID
+	return records
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+)
+
+func TestNewNamespaceOwners(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Annotations: map[string]string{"example.org/tenant": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shared"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "invalid", Annotations: map[string]string{"example.org/tenant": "a,b"}}},
+	)
+	owners, err := NewNamespaceOwners(context.Background(), client, `{{ index .Annotations "example.org/tenant" }}`)
+	require.NoError(t, err)
+
+	owner, err := owners("team-a")
+	require.NoError(t, err)
+	assert.Equal(t, "a", owner)
+
+	owner, err = owners("shared")
+	require.NoError(t, err)
+	assert.Empty(t, owner)
+
+	_, err = owners("invalid")
+	assert.Error(t, err)
+
+	_, err = owners("missing")
+	assert.Error(t, err)
+
+	_, err = NewNamespaceOwners(context.Background(), client, "{{ .Namespace")
+	assert.Error(t, err)
+}
+
+// ownerLabels returns the owner labels of the ownership records by DNS name.
+func ownerLabels(t *testing.T, p *inmemory.InMemoryProvider) map[string]string {
+	records, err := p.Records(context.Background())
+	require.NoError(t, err)
+	owners := map[string]string{}
+	for _, r := range records {
+		if r.RecordType != endpoint.RecordTypeTXT {
+			continue
+		}
+		labels, err := endpoint.NewLabelsFromString(r.Targets[0], nil)
+		require.NoError(t, err)
+		owners[r.DNSName] = labels[endpoint.OwnerLabelKey]
+	}
+	return owners
+}
+
+func TestTXTRegistryNamespaceOwners(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	require.NoError(t, p.CreateZone(testZone))
+	r, err := NewTXTRegistry(p, "", "", "owner", 0, "", []string{endpoint.RecordTypeA}, []string{}, false, nil)
+	require.NoError(t, err)
+	tenants := map[string]string{"team-a": "a"}
+	r.SetNamespaceOwners(func(namespace string) (string, error) {
+		tenant, ok := tenants[namespace]
+		if !ok {
+			return "", fmt.Errorf("namespace %s not found", namespace)
+		}
+		return tenant, nil
+	})
+
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwnerResource("web.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "", "ingress/team-a/web"),
+			newEndpointWithOwnerResource("node.test-zone.example.org", "1.2.3.5", endpoint.RecordTypeA, "", "node/node-1"),
+		},
+	}))
+	assert.Equal(t, map[string]string{
+		"web.test-zone.example.org":    "owner/a",
+		"a-web.test-zone.example.org":  "owner/a",
+		"node.test-zone.example.org":   "owner",
+		"a-node.test-zone.example.org": "owner",
+	}, ownerLabels(t, p))
+
+	// the records of the namespace are reported as owned by the instance
+	records, err := r.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	for _, record := range records {
+		assert.True(t, record.IsOwnedBy("owner"), record.DNSName)
+		_, forceUpdate := record.GetProviderSpecificProperty(providerSpecificForceUpdate)
+		assert.False(t, forceUpdate, record.DNSName)
+	}
+
+	// records whose owner ID changed are updated to the new owner ID
+	tenants["team-a"] = "b"
+	records, err = r.Records(ctx)
+	require.NoError(t, err)
+	var current *endpoint.Endpoint
+	for _, record := range records {
+		_, forceUpdate := record.GetProviderSpecificProperty(providerSpecificForceUpdate)
+		assert.Equal(t, record.DNSName == "web.test-zone.example.org", forceUpdate, record.DNSName)
+		if forceUpdate {
+			current = record
+		}
+	}
+	require.NotNil(t, current)
+	desired := newEndpointWithOwnerResource("web.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner", "ingress/team-a/web")
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{current},
+		UpdateNew: []*endpoint.Endpoint{desired},
+	}))
+	assert.Equal(t, "owner/b", ownerLabels(t, p)["web.test-zone.example.org"])
+	assert.True(t, desired.IsOwnedBy("owner"))
+
+	// the records are deleted with their owner ID
+	records, err = r.Records(ctx)
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{Delete: records}))
+	assert.Empty(t, ownerLabels(t, p))
+}
+
+func TestTXTRegistryNamespaceOwnersCached(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	require.NoError(t, p.CreateZone(testZone))
+	r, err := NewTXTRegistry(p, "", "", "owner", time.Hour, "", []string{endpoint.RecordTypeA}, []string{}, false, nil)
+	require.NoError(t, err)
+	r.SetNamespaceOwners(func(namespace string) (string, error) { return namespace, nil })
+
+	_, err = r.Records(ctx)
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			newEndpointWithOwnerResource("web.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "", "ingress/team-a/web"),
+		},
+	}))
+
+	records, err := r.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.True(t, records[0].IsOwnedBy("owner"))
+	assert.Equal(t, "owner/team-a", r.currentOwner(records[0]))
+}
@@ -34,3 +34,17 @@ type Registry interface {
 	GetDomainFilter() endpoint.DomainFilter
 	OwnerID() string
 }
+
+// GarbageCollector is implemented by the registries which keep bookkeeping items apart from the DNS records, to
+// delete the items whose DNS record no longer exists. It is called after every synchronization, except in dry-run mode.
+type GarbageCollector interface {
+	CollectGarbage(ctx context.Context) error
+}
+
+// CollectGarbage collects the garbage of the registry, if it implements GarbageCollector.
+func CollectGarbage(ctx context.Context, registry Registry) error {
+	if gc, ok := registry.(GarbageCollector); ok {
+		return gc.CollectGarbage(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// snapshotConfigMapKey is the key of the compressed snapshot in the binary data of its ConfigMap.
+const snapshotConfigMapKey = "snapshot.json.gz"
+
+// Snapshot holds the records cached by a CachedRegistry, so that they survive restarts.
+type Snapshot struct {
+	// OwnerID is the owner ID of the registry the records were read from
+	OwnerID string `json:"ownerID"`
+	// RefreshTime is when the records were read from the registry
+	RefreshTime time.Time `json:"refreshTime"`
+	// Records are the records of the registry
+	Records []*endpoint.Endpoint `json:"records"`
+}
+
+// SnapshotStore persists a snapshot of the records of a CachedRegistry.
+type SnapshotStore interface {
+	// Load returns the stored snapshot, or nil if there is none.
+	Load(ctx context.Context) (*Snapshot, error)
+	// Save stores the snapshot, replacing the stored one.
+	Save(ctx context.Context, snapshot *Snapshot) error
+	// Clear removes the stored snapshot, if any.
+	Clear(ctx context.Context) error
+}
+
+func marshalSnapshot(snapshot *Snapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalSnapshot(data []byte) (*Snapshot, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry snapshot: %w", err)
+	}
+	defer r.Close()
+	snapshot := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return nil, fmt.Errorf("invalid registry snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// FileSnapshotStore stores the snapshot in a gzip compressed file, e.g. on a persistent volume.
+type FileSnapshotStore struct {
+	path string
+}
+
+// NewFileSnapshotStore returns a FileSnapshotStore storing the snapshot at path.
+func NewFileSnapshotStore(path string) *FileSnapshotStore {
+	return &FileSnapshotStore{path: path}
+}
+
+func (s *FileSnapshotStore) Load(ctx context.Context) (*Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalSnapshot(data)
+}
+
+func (s *FileSnapshotStore) Save(ctx context.Context, snapshot *Snapshot) error {
+	data, err := marshalSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+func (s *FileSnapshotStore) Clear(ctx context.Context) error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// ConfigMapSnapshotStore stores the snapshot gzip compressed in the binary data of a ConfigMap,
+// which is created when needed. ConfigMaps are limited to 1 MiB.
+type ConfigMapSnapshotStore struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewConfigMapSnapshotStore returns a ConfigMapSnapshotStore storing the snapshot in the given ConfigMap.
+func NewConfigMapSnapshotStore(client kubernetes.Interface, namespace, name string) *ConfigMapSnapshotStore {
+	return &ConfigMapSnapshotStore{client: client, namespace: namespace, name: name}
+}
+
+func (s *ConfigMapSnapshotStore) Load(ctx context.Context) (*Snapshot, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, ok := cm.BinaryData[snapshotConfigMapKey]
+	if !ok {
+		return nil, nil
+	}
+	return unmarshalSnapshot(data)
+}
+
+func (s *ConfigMapSnapshotStore) Save(ctx context.Context, snapshot *Snapshot) error {
+	data, err := marshalSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	configMaps := s.client.CoreV1().ConfigMaps(s.namespace)
+	cm, err := configMaps.Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: s.name},
+			BinaryData: map[string][]byte{snapshotConfigMapKey: data},
+		}
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.BinaryData == nil {
+		cm.BinaryData = map[string][]byte{}
+	}
+	cm.BinaryData[snapshotConfigMapKey] = data
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+func (s *ConfigMapSnapshotStore) Clear(ctx context.Context) error {
+	configMaps := s.client.CoreV1().ConfigMaps(s.namespace)
+	cm, err := configMaps.Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := cm.BinaryData[snapshotConfigMapKey]; !ok {
+		return nil
+	}
+	delete(cm.BinaryData, snapshotConfigMapKey)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestSnapshotStores(t *testing.T) {
+	for name, store := range map[string]SnapshotStore{
+		"file":      NewFileSnapshotStore(filepath.Join(t.TempDir(), "snapshot.json.gz")),
+		"configmap": NewConfigMapSnapshotStore(fake.NewSimpleClientset(), "external-dns", "registry-snapshot"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			snapshot, err := store.Load(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, snapshot)
+			require.NoError(t, store.Clear(ctx))
+
+			saved := &Snapshot{
+				OwnerID:     "owner",
+				RefreshTime: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+				Records: []*endpoint.Endpoint{
+					newEndpointWithOwner("foo.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+				},
+			}
+			for i := 0; i < 2; i++ {
+				require.NoError(t, store.Save(ctx, saved))
+				snapshot, err = store.Load(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, saved, snapshot)
+			}
+
+			require.NoError(t, store.Clear(ctx))
+			snapshot, err = store.Load(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, snapshot)
+		})
+	}
+}
+
+func TestConfigMapSnapshotStoreKeepsOtherData(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapSnapshotStore(client, "external-dns", "registry-snapshot")
+	require.NoError(t, store.Save(ctx, &Snapshot{OwnerID: "owner"}))
+
+	cm, err := client.CoreV1().ConfigMaps("external-dns").Get(ctx, "registry-snapshot", metav1.GetOptions{})
+	require.NoError(t, err)
+	cm.Data = map[string]string{"other": "value"}
+	_, err = client.CoreV1().ConfigMaps("external-dns").Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, store.Clear(ctx))
+	cm, err = client.CoreV1().ConfigMaps("external-dns").Get(ctx, "registry-snapshot", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"other": "value"}, cm.Data)
+	assert.Empty(t, cm.BinaryData)
+}
@@ -56,10 +56,18 @@ type TXTRegistry struct {
 	// encrypt text records
 	txtEncryptEnabled bool
 	txtEncryptAESKey  []byte
+	// keys only used to decrypt text records, e.g. while rotating the encryption key
+	txtDecryptAESKeys [][]byte
+
+	// derive the owner IDs of records from their namespace, see SetNamespaceOwners
+	namespaceOwners NamespaceOwnerFunc
+	// the owner IDs derived from a namespace of the records reported as owned by the instance
+	namespaceOwnerIDs map[endpoint.EndpointKey]string
 }
 
 // NewTXTRegistry returns new TXTRegistry object
-func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID string, cacheInterval time.Duration, txtWildcardReplacement string, managedRecordTypes, excludeRecordTypes []string, txtEncryptEnabled bool, txtEncryptAESKey []byte) (*TXTRegistry, error) {
+// txtDecryptAESKeys are only used to decrypt TXT records, records encrypted with them are re-encrypted with txtEncryptAESKey.
+func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID string, cacheInterval time.Duration, txtWildcardReplacement string, managedRecordTypes, excludeRecordTypes []string, txtEncryptEnabled bool, txtEncryptAESKey []byte, txtDecryptAESKeys ...[]byte) (*TXTRegistry, error) {
 	if ownerID == "" {
 		return nil, errors.New("owner id cannot be empty")
 	}
@@ -71,6 +79,11 @@ func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID st
 	if txtEncryptEnabled && txtEncryptAESKey == nil {
 		return nil, errors.New("the AES Encryption key must be set when TXT record encryption is enabled")
 	}
+	for _, key := range txtDecryptAESKeys {
+		if len(key) != 32 {
+			return nil, errors.New("the AES Decryption keys must have a length of 32 bytes")
+		}
+	}
 
 	if len(txtPrefix) > 0 && len(txtSuffix) > 0 {
 		return nil, errors.New("txt-prefix and txt-suffix are mutual exclusive")
@@ -88,6 +101,8 @@ func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID st
 		excludeRecordTypes:  excludeRecordTypes,
 		txtEncryptEnabled:   txtEncryptEnabled,
 		txtEncryptAESKey:    txtEncryptAESKey,
+		txtDecryptAESKeys:   txtDecryptAESKeys,
+		namespaceOwnerIDs:   map[endpoint.EndpointKey]string{},
 	}, nil
 }
 
@@ -111,35 +126,36 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 	// last given interval, then just use the cached results.
 	if im.recordsCache != nil && time.Since(im.recordsCacheRefreshTime) < im.cacheInterval {
 		log.Debug("Using cached records.")
+		for _, ep := range im.recordsCache {
+			im.normalizeOwner(ep)
+		}
 		return im.recordsCache, nil
 	}
 
-	records, err := im.provider.Records(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	endpoints := []*endpoint.Endpoint{}
 
-	labelMap := map[endpoint.EndpointKey]endpoint.Labels{}
-	txtRecordsMap := map[string]struct{}{}
+	owners := newOwnershipIndex()
+	aesKeys := im.aesKeys()
+	im.namespaceOwnerIDs = map[endpoint.EndpointKey]string{}
 
-	for _, record := range records {
+	// the records are streamed when the provider supports it, so that the ownership records,
+	// about half of the records, are never held in memory at once
+	err := provider.IterRecords(ctx, im.provider, func(record *endpoint.Endpoint) error {
 		if record.RecordType != endpoint.RecordTypeTXT {
 			endpoints = append(endpoints, record)
-			continue
+			return nil
 		}
 		// We simply assume that TXT records for the registry will always have only one target.
-		labels, err := endpoint.NewLabelsFromString(record.Targets[0], im.txtEncryptAESKey)
+		labels, err := endpoint.NewLabelsFromStringWithKeys(record.Targets[0], aesKeys)
 		if err == endpoint.ErrInvalidHeritage {
 			// if no heritage is found or it is invalid
 			// case when value of txt record cannot be identified
 			// record will not be removed as it will have empty owner
 			endpoints = append(endpoints, record)
-			continue
+			return nil
 		}
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		endpointName, recordType := im.mapper.toEndpointName(record.DNSName)
@@ -148,53 +164,43 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 			RecordType:    recordType,
 			SetIdentifier: record.SetIdentifier,
 		}
-		labelMap[key] = labels
-		txtRecordsMap[record.DNSName] = struct{}{}
+		owners.add(record.DNSName, key, labels)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for wildcard, other := range wildcardCollisions(endpoints, im.wildcardReplacement) {
+		log.Warnf("The wildcard record %s shares its ownership record with %s, use a TXT wildcard replacement which is not used as a label", wildcard, other)
 	}
 
 	for _, ep := range endpoints {
 		if ep.Labels == nil {
 			ep.Labels = endpoint.NewLabels()
 		}
-		dnsNameSplit := strings.Split(ep.DNSName, ".")
-		// If specified, replace a leading asterisk in the generated txt record name with some other string
-		if im.wildcardReplacement != "" && dnsNameSplit[0] == "*" {
-			dnsNameSplit[0] = im.wildcardReplacement
-		}
-		dnsName := strings.Join(dnsNameSplit, ".")
-		key := endpoint.EndpointKey{
-			DNSName:       dnsName,
-			RecordType:    ep.RecordType,
-			SetIdentifier: ep.SetIdentifier,
-		}
-
-		// AWS Alias records have "new" format encoded as type "cname"
-		if isAlias, found := ep.GetProviderSpecificProperty("alias"); found && isAlias == "true" && ep.RecordType == endpoint.RecordTypeA {
-			key.RecordType = endpoint.RecordTypeCNAME
-		}
-
-		// Handle both new and old registry format with the preference for the new one
-		labels, labelsExist := labelMap[key]
-		if !labelsExist && ep.RecordType != endpoint.RecordTypeAAAA {
-			key.RecordType = ""
-			labels, labelsExist = labelMap[key]
-		}
+		labels, labelsExist := owners.lookup(ep, im.wildcardReplacement)
 		if labelsExist {
 			for k, v := range labels {
 				ep.Labels[k] = v
 			}
 		}
+		im.normalizeOwner(ep)
+
+		// Re-encrypt TXT records owned by this instance which were encrypted with a decryption-only key.
+		if im.txtEncryptEnabled && labelsExist && labels.EncryptionKeyIndex() > 0 && ep.Labels[endpoint.OwnerLabelKey] == im.ownerID {
+			if plan.IsManagedRecord(ep.RecordType, im.managedRecordTypes, im.excludeRecordTypes) {
+				ep.WithProviderSpecific(providerSpecificForceUpdate, "true")
+			}
+		}
 
 		// Handle the migration of TXT records created before the new format (introduced in v0.12.0).
 		// The migration is done for the TXT records owned by this instance only.
-		if len(txtRecordsMap) > 0 && ep.Labels[endpoint.OwnerLabelKey] == im.ownerID {
+		if !owners.empty() && ep.Labels[endpoint.OwnerLabelKey] == im.ownerID {
 			if plan.IsManagedRecord(ep.RecordType, im.managedRecordTypes, im.excludeRecordTypes) {
-				// Get desired TXT records and detect the missing ones
-				desiredTXTs := im.generateTXTRecord(ep)
-				for _, desiredTXT := range desiredTXTs {
-					if _, exists := txtRecordsMap[desiredTXT.DNSName]; !exists {
-						ep.WithProviderSpecific(providerSpecificForceUpdate, "true")
-					}
+				// Detect the missing TXT records by name, without generating their content
+				if owners.missing(im.txtRecordNames(ep)) {
+					ep.WithProviderSpecific(providerSpecificForceUpdate, "true")
 				}
 			}
 		}
@@ -220,9 +226,15 @@ func (im *TXTRegistry) generateTXTRecord(r *endpoint.Endpoint) []*endpoint.Endpo
 
 	endpoints := make([]*endpoint.Endpoint, 0)
 
+	// Existing records are reconstructed with the key they were encrypted with.
+	aesKey := im.txtEncryptAESKey
+	if index := r.Labels.EncryptionKeyIndex(); index > 0 && index < len(im.aesKeys()) {
+		aesKey = im.aesKeys()[index]
+	}
+
 	if !im.txtEncryptEnabled && !im.mapper.recordTypeInAffix() && r.RecordType != endpoint.RecordTypeAAAA {
 		// old TXT record format
-		txt := endpoint.NewEndpoint(im.mapper.toTXTName(r.DNSName), endpoint.RecordTypeTXT, r.Labels.Serialize(true, im.txtEncryptEnabled, im.txtEncryptAESKey))
+		txt := endpoint.NewEndpoint(im.mapper.toTXTName(r.DNSName), endpoint.RecordTypeTXT, r.Labels.Serialize(true, im.txtEncryptEnabled, aesKey))
 		if txt != nil {
 			txt.WithSetIdentifier(r.SetIdentifier)
 			txt.Labels[endpoint.OwnedRecordLabelKey] = r.DNSName
@@ -236,7 +248,7 @@ func (im *TXTRegistry) generateTXTRecord(r *endpoint.Endpoint) []*endpoint.Endpo
 	if isAlias, found := r.GetProviderSpecificProperty("alias"); found && isAlias == "true" && recordType == endpoint.RecordTypeA {
 		recordType = endpoint.RecordTypeCNAME
 	}
-	txtNew := endpoint.NewEndpoint(im.mapper.toNewTXTName(r.DNSName, recordType), endpoint.RecordTypeTXT, r.Labels.Serialize(true, im.txtEncryptEnabled, im.txtEncryptAESKey))
+	txtNew := endpoint.NewEndpoint(im.mapper.toNewTXTName(r.DNSName, recordType), endpoint.RecordTypeTXT, r.Labels.Serialize(true, im.txtEncryptEnabled, aesKey))
 	if txtNew != nil {
 		txtNew.WithSetIdentifier(r.SetIdentifier)
 		txtNew.Labels[endpoint.OwnedRecordLabelKey] = r.DNSName
@@ -247,6 +259,11 @@ func (im *TXTRegistry) generateTXTRecord(r *endpoint.Endpoint) []*endpoint.Endpo
 	return endpoints
 }
 
+// aesKeys returns the encryption key followed by the decryption-only keys.
+func (im *TXTRegistry) aesKeys() [][]byte {
+	return append([][]byte{im.txtEncryptAESKey}, im.txtDecryptAESKeys...)
+}
+
 // ApplyChanges updates dns provider with the changes
 // for each created/deleted record it will also take into account TXT records for creation/deletion
 func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
@@ -262,38 +279,42 @@ func (im *TXTRegistry) ApplyChanges(ctx context.Context, changes *plan.Changes)
 		}
 		r.Labels[endpoint.OwnerLabelKey] = im.ownerID
 
-		filteredChanges.Create = append(filteredChanges.Create, im.generateTXTRecord(r)...)
+		owner := im.ownerFor(r)
+		im.setNamespaceOwnerID(r, owner)
+		filteredChanges.Create = append(filteredChanges.Create, im.generateOwnedTXTRecord(r, owner)...)
 
 		if im.cacheInterval > 0 {
 			im.addToCache(r)
 		}
 	}
 
+	var removed []*endpoint.Endpoint
 	for _, r := range filteredChanges.Delete {
 		// when we delete TXT records for which value has changed (due to new label) this would still work because
 		// !!! TXT record value is uniquely generated from the Labels of the endpoint. Hence old TXT record can be uniquely reconstructed
 		// !!! After migration to the new TXT registry format we can drop records in old format here!!!
-		filteredChanges.Delete = append(filteredChanges.Delete, im.generateTXTRecord(r)...)
-
-		if im.cacheInterval > 0 {
-			im.removeFromCache(r)
-		}
+		filteredChanges.Delete = append(filteredChanges.Delete, im.generateOwnedTXTRecord(r, im.currentOwner(r))...)
+		removed = append(removed, r)
 	}
 
 	// make sure TXT records are consistently updated as well
 	for _, r := range filteredChanges.UpdateOld {
 		// when we updateOld TXT records for which value has changed (due to new label) this would still work because
 		// !!! TXT record value is uniquely generated from the Labels of the endpoint. Hence old TXT record can be uniquely reconstructed
-		filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, im.generateTXTRecord(r)...)
-		// remove old version of record from cache
-		if im.cacheInterval > 0 {
-			im.removeFromCache(r)
-		}
+		filteredChanges.UpdateOld = append(filteredChanges.UpdateOld, im.generateOwnedTXTRecord(r, im.currentOwner(r))...)
+		removed = append(removed, r)
+	}
+
+	// remove the deleted records and the old versions of the updated records from the cache at once
+	if im.cacheInterval > 0 {
+		im.removeFromCache(removed...)
 	}
 
 	// make sure TXT records are consistently updated as well
 	for _, r := range filteredChanges.UpdateNew {
-		filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, im.generateTXTRecord(r)...)
+		owner := im.ownerFor(r)
+		im.setNamespaceOwnerID(r, owner)
+		filteredChanges.UpdateNew = append(filteredChanges.UpdateNew, im.generateOwnedTXTRecord(r, owner)...)
 		// add new version of record to cache
 		if im.cacheInterval > 0 {
 			im.addToCache(r)
@@ -479,17 +500,3 @@ func (im *TXTRegistry) addToCache(ep *endpoint.Endpoint) {
 		im.recordsCache = append(im.recordsCache, ep)
 	}
 }
-
-func (im *TXTRegistry) removeFromCache(ep *endpoint.Endpoint) {
-	if im.recordsCache == nil || ep == nil {
-		return
-	}
-
-	for i, e := range im.recordsCache {
-		if e.DNSName == ep.DNSName && e.RecordType == ep.RecordType && e.SetIdentifier == ep.SetIdentifier && e.Targets.Same(ep.Targets) {
-			// We found a match delete the endpoint from the cache.
-			im.recordsCache = append(im.recordsCache[:i], im.recordsCache[i+1:]...)
-			return
-		}
-	}
-}
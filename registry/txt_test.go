@@ -1568,6 +1568,52 @@ func TestTXTRegistryApplyChangesEncrypt(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTXTRegistryEncryptionKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	p := inmemory.NewInMemoryProvider()
+	p.CreateZone(testZone)
+	oldKey := []byte("12345678901234567890123456789012")
+	newKey := []byte("abcdefghijklmnopqrstuvwxyz123456")
+	managed := []string{endpoint.RecordTypeCNAME}
+
+	r, err := NewTXTRegistry(p, "txt.", "", "owner", 0, "", managed, nil, true, oldKey)
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyChanges(ctx, &plan.Changes{
+		Create: []*endpoint.Endpoint{newEndpointWithOwner("foobar.test-zone.example.org", "foobar.loadbalancer.com", endpoint.RecordTypeCNAME, "")},
+	}))
+
+	_, err = NewTXTRegistry(p, "txt.", "", "owner", 0, "", managed, nil, true, newKey, []byte("too-short"))
+	require.Error(t, err)
+
+	r, err = NewTXTRegistry(p, "txt.", "", "owner", 0, "", managed, nil, true, newKey, oldKey)
+	require.NoError(t, err)
+	records, err := r.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "owner", records[0].Labels[endpoint.OwnerLabelKey])
+	value, ok := records[0].GetProviderSpecificProperty(providerSpecificForceUpdate)
+	assert.True(t, ok && value == "true", "records encrypted with a decryption-only key should be re-encrypted")
+
+	pl := &plan.Plan{
+		Policies:       []plan.Policy{&plan.SyncPolicy{}},
+		Current:        records,
+		Desired:        []*endpoint.Endpoint{newEndpointWithOwner("foobar.test-zone.example.org", "foobar.loadbalancer.com", endpoint.RecordTypeCNAME, "")},
+		ManagedRecords: managed,
+		OwnerID:        "owner",
+	}
+	require.NoError(t, r.ApplyChanges(ctx, pl.Calculate().Changes))
+
+	// after the rotation the records can be read without the old key
+	r, err = NewTXTRegistry(p, "txt.", "", "owner", 0, "", managed, nil, true, newKey)
+	require.NoError(t, err)
+	records, err = r.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "owner", records[0].Labels[endpoint.OwnerLabelKey])
+	_, ok = records[0].GetProviderSpecificProperty(providerSpecificForceUpdate)
+	assert.False(t, ok)
+}
+
 // TestMultiClusterDifferentRecordTypeOwnership validates the registry handles environments where the same zone is managed by
 // external-dns in different clusters and the ingress record type is different. For example one uses A records and the other
 // uses CNAME. In this environment the first cluster that establishes the owner record should maintain ownership even
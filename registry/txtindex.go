@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// ownershipIndex indexes the ownership TXT records of the zones once per listing of the records,
+// so that the labels and the ownership records of every record are looked up instead of being
+// searched for or generated again.
+type ownershipIndex struct {
+	// labels are the labels of the ownership records by the key of the record they own;
+	// the record type is empty for the records in the format preceding v0.12.0
+	labels map[endpoint.EndpointKey]endpoint.Labels
+	// names are the names of the ownership records
+	names map[string]struct{}
+}
+
+func newOwnershipIndex() *ownershipIndex {
+	return &ownershipIndex{
+		labels: map[endpoint.EndpointKey]endpoint.Labels{},
+		names:  map[string]struct{}{},
+	}
+}
+
+// add indexes the ownership record txtName holding the labels of the record identified by key.
+func (idx *ownershipIndex) add(txtName string, key endpoint.EndpointKey, labels endpoint.Labels) {
+	idx.labels[key] = labels
+	idx.names[txtName] = struct{}{}
+}
+
+// lookup returns the labels of the ownership record of the record, preferring the current format.
+func (idx *ownershipIndex) lookup(ep *endpoint.Endpoint, wildcardReplacement string) (endpoint.Labels, bool) {
+	key := endpoint.EndpointKey{
+		DNSName:       ownershipName(ep.DNSName, wildcardReplacement),
+		RecordType:    ep.RecordType,
+		SetIdentifier: ep.SetIdentifier,
+	}
+
+	// AWS Alias records have "new" format encoded as type "cname"
+	if isAlias, found := ep.GetProviderSpecificProperty("alias"); found && isAlias == "true" && ep.RecordType == endpoint.RecordTypeA {
+		key.RecordType = endpoint.RecordTypeCNAME
+	}
+
+	// Handle both new and old registry format with the preference for the new one
+	labels, ok := idx.labels[key]
+	if !ok && ep.RecordType != endpoint.RecordTypeAAAA {
+		key.RecordType = ""
+		labels, ok = idx.labels[key]
+	}
+	return labels, ok
+}
+
+// empty returns true when no ownership record was indexed.
+func (idx *ownershipIndex) empty() bool {
+	return len(idx.names) == 0
+}
+
+// missing returns true when one of the named ownership records doesn't exist.
+func (idx *ownershipIndex) missing(txtNames []string) bool {
+	for _, name := range txtNames {
+		if _, ok := idx.names[name]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// txtRecordNames returns the names of the ownership records generateTXTRecord generates for the
+// record, without serializing and possibly encrypting their labels.
+func (im *TXTRegistry) txtRecordNames(r *endpoint.Endpoint) []string {
+	if r.RecordType == endpoint.RecordTypeTXT {
+		return nil
+	}
+
+	names := make([]string, 0, 2)
+	if !im.txtEncryptEnabled && !im.mapper.recordTypeInAffix() && r.RecordType != endpoint.RecordTypeAAAA {
+		if name, ok := txtRecordName(im.mapper.toTXTName(r.DNSName)); ok {
+			names = append(names, name)
+		}
+	}
+	recordType := r.RecordType
+	// AWS Alias records are encoded as type "cname"
+	if isAlias, found := r.GetProviderSpecificProperty("alias"); found && isAlias == "true" && recordType == endpoint.RecordTypeA {
+		recordType = endpoint.RecordTypeCNAME
+	}
+	if name, ok := txtRecordName(im.mapper.toNewTXTName(r.DNSName, recordType)); ok {
+		names = append(names, name)
+	}
+	return names
+}
+
+// txtRecordName returns the name endpoint.NewEndpoint gives to a record named name, if valid.
+func txtRecordName(name string) (string, bool) {
+	if ep := endpoint.NewEndpoint(name, endpoint.RecordTypeTXT); ep != nil {
+		return ep.DNSName, true
+	}
+	return "", false
+}
+
+// removeFromCache removes the records from the cache in a single pass over the cache.
+func (im *TXTRegistry) removeFromCache(eps ...*endpoint.Endpoint) {
+	if im.recordsCache == nil || len(eps) == 0 {
+		return
+	}
+
+	removed := make(map[endpoint.EndpointKey][]*endpoint.Endpoint, len(eps))
+	for _, ep := range eps {
+		if ep == nil {
+			continue
+		}
+		key := cacheKey(ep)
+		removed[key] = append(removed[key], ep)
+	}
+
+	cache := im.recordsCache[:0]
+	for _, e := range im.recordsCache {
+		if im.matchRemoved(removed, e) {
+			continue
+		}
+		cache = append(cache, e)
+	}
+	// clear the tail so that the removed records can be garbage collected
+	for i := len(cache); i < len(im.recordsCache); i++ {
+		im.recordsCache[i] = nil
+	}
+	im.recordsCache = cache
+}
+
+// matchRemoved returns true when the cached record is one of the removed records, each of
+// which removes a single cached record.
+func (im *TXTRegistry) matchRemoved(removed map[endpoint.EndpointKey][]*endpoint.Endpoint, e *endpoint.Endpoint) bool {
+	key := cacheKey(e)
+	candidates := removed[key]
+	for i, ep := range candidates {
+		if e.Targets.Same(ep.Targets) {
+			removed[key] = append(candidates[:i:i], candidates[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func cacheKey(e *endpoint.Endpoint) endpoint.EndpointKey {
+	return endpoint.EndpointKey{DNSName: e.DNSName, RecordType: e.RecordType, SetIdentifier: e.SetIdentifier}
+}
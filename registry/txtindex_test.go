@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/internal/testutils"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+)
+
+func TestTXTRecordNamesMatchGeneratedRecords(t *testing.T) {
+	aesKey := []byte(";k&l)nUC/33:{?d{3)54+,AD?]SX%yh^")
+	for _, tc := range []struct {
+		name    string
+		prefix  string
+		suffix  string
+		encrypt bool
+	}{
+		{name: "no affix"},
+		{name: "prefix", prefix: "txt."},
+		{name: "suffix", suffix: "-txt"},
+		{name: "record type prefix", prefix: "%{record_type}-"},
+		{name: "encrypted", prefix: "txt.", encrypt: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var key []byte
+			if tc.encrypt {
+				key = aesKey
+			}
+			r, err := NewTXTRegistry(inmemory.NewInMemoryProvider(), tc.prefix, tc.suffix, "owner", time.Hour, "wc", nil, nil, tc.encrypt, key)
+			require.NoError(t, err)
+
+			for _, ep := range []*endpoint.Endpoint{
+				newEndpointWithOwner("foo.test-zone.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+				newEndpointWithOwner("Foo.Test-Zone.example.org.", "2001:db8::1", endpoint.RecordTypeAAAA, "owner"),
+				newEndpointWithOwner("*.test-zone.example.org", "foo.example.org", endpoint.RecordTypeCNAME, "owner"),
+				newEndpointWithOwner("test-zone.example.org", "lb.example.org", endpoint.RecordTypeA, "owner").WithProviderSpecific("alias", "true"),
+				newEndpointWithOwner("bücher.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+				newEndpointWithOwner("foo.test-zone.example.org", "txt", endpoint.RecordTypeTXT, "owner"),
+			} {
+				var expected []string
+				for _, txt := range r.generateTXTRecord(ep) {
+					expected = append(expected, txt.DNSName)
+				}
+				assert.Equal(t, expected, r.txtRecordNames(ep), ep.String())
+			}
+		})
+	}
+}
+
+func TestOwnershipIndexLookup(t *testing.T) {
+	idx := newOwnershipIndex()
+	assert.True(t, idx.empty())
+
+	idx.add("a-foo.example.org", endpoint.EndpointKey{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeA}, endpoint.Labels{endpoint.OwnerLabelKey: "new"})
+	idx.add("foo.example.org", endpoint.EndpointKey{DNSName: "foo.example.org"}, endpoint.Labels{endpoint.OwnerLabelKey: "old"})
+	idx.add("cname-alias.example.org", endpoint.EndpointKey{DNSName: "alias.example.org", RecordType: endpoint.RecordTypeCNAME}, endpoint.Labels{endpoint.OwnerLabelKey: "alias"})
+	idx.add("bar.example.org", endpoint.EndpointKey{DNSName: "bar.example.org"}, endpoint.Labels{endpoint.OwnerLabelKey: "old"})
+	assert.False(t, idx.empty())
+
+	for _, tc := range []struct {
+		ep    *endpoint.Endpoint
+		owner string
+		found bool
+	}{
+		{ep: endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4"), owner: "new", found: true},
+		{ep: endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeCNAME, "lb.example.org"), owner: "old", found: true},
+		{ep: endpoint.NewEndpoint("alias.example.org", endpoint.RecordTypeA, "lb.example.org").WithProviderSpecific("alias", "true"), owner: "alias", found: true},
+		{ep: endpoint.NewEndpoint("bar.example.org", endpoint.RecordTypeA, "1.2.3.4"), owner: "old", found: true},
+		// the old format never owned AAAA records
+		{ep: endpoint.NewEndpoint("bar.example.org", endpoint.RecordTypeAAAA, "2001:db8::1")},
+		{ep: endpoint.NewEndpoint("baz.example.org", endpoint.RecordTypeA, "1.2.3.4")},
+	} {
+		labels, found := idx.lookup(tc.ep, "")
+		assert.Equal(t, tc.found, found, tc.ep.String())
+		assert.Equal(t, tc.owner, labels[endpoint.OwnerLabelKey], tc.ep.String())
+	}
+
+	assert.False(t, idx.missing([]string{"foo.example.org", "a-foo.example.org"}))
+	assert.True(t, idx.missing([]string{"foo.example.org", "aaaa-foo.example.org"}))
+}
+
+func TestRemoveFromCacheBatch(t *testing.T) {
+	r, err := NewTXTRegistry(inmemory.NewInMemoryProvider(), "", "", "owner", time.Hour, "", nil, nil, false, nil)
+	require.NoError(t, err)
+	r.recordsCache = []*endpoint.Endpoint{
+		newEndpointWithOwner("foo.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("foo.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("foo.example.org", "1.2.3.5", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("bar.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner").WithSetIdentifier("a"),
+		newEndpointWithOwner("bar.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner").WithSetIdentifier("b"),
+		newEndpointWithOwner("baz.example.org", "foo.example.org", endpoint.RecordTypeCNAME, "owner"),
+	}
+
+	r.removeFromCache(
+		// each removed record removes a single cached record
+		newEndpointWithOwner("foo.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("bar.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner").WithSetIdentifier("b"),
+		newEndpointWithOwner("baz.example.org", "foo.example.org", endpoint.RecordTypeCNAME, "owner"),
+		// records which are not cached are ignored
+		newEndpointWithOwner("qux.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		nil,
+	)
+
+	assert.True(t, testutils.SameEndpoints([]*endpoint.Endpoint{
+		newEndpointWithOwner("foo.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("foo.example.org", "1.2.3.5", endpoint.RecordTypeA, "owner"),
+		newEndpointWithOwner("bar.example.org", "1.2.3.4", endpoint.RecordTypeA, "owner").WithSetIdentifier("a"),
+	}, r.recordsCache), "%v", r.recordsCache)
+}
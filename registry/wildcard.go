@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// ownershipName returns the DNS name under which the TXT ownership record of a record is stored.
+// The leading asterisk of wildcard records is replaced by the wildcard replacement, if set, since
+// most providers reject TXT records whose name contains an asterisk in another position.
+func ownershipName(dnsName, wildcardReplacement string) string {
+	if wildcardReplacement == "" {
+		return dnsName
+	}
+	first, rest, found := strings.Cut(dnsName, ".")
+	if first != "*" {
+		return dnsName
+	}
+	if !found {
+		return wildcardReplacement
+	}
+	return wildcardReplacement + "." + rest
+}
+
+// wildcardCollisions returns the wildcard records whose ownership is stored under the same name as
+// the one of another record, mapped to that record. This happens when the wildcard replacement is
+// also used as a label, e.g. for *.example.org and wildcard.example.org with the replacement "wildcard",
+// and makes both records share their ownership.
+func wildcardCollisions(endpoints []*endpoint.Endpoint, wildcardReplacement string) map[string]string {
+	if wildcardReplacement == "" {
+		return nil
+	}
+	specific := map[endpoint.EndpointKey]string{}
+	for _, ep := range endpoints {
+		if !strings.HasPrefix(ep.DNSName, "*") {
+			specific[endpoint.EndpointKey{DNSName: strings.ToLower(ep.DNSName), RecordType: ep.RecordType, SetIdentifier: ep.SetIdentifier}] = ep.DNSName
+		}
+	}
+	collisions := map[string]string{}
+	for _, ep := range endpoints {
+		name := ownershipName(ep.DNSName, wildcardReplacement)
+		if name == ep.DNSName {
+			continue
+		}
+		if other, ok := specific[endpoint.EndpointKey{DNSName: strings.ToLower(name), RecordType: ep.RecordType, SetIdentifier: ep.SetIdentifier}]; ok {
+			collisions[ep.DNSName] = other
+		}
+	}
+	return collisions
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestOwnershipName(t *testing.T) {
+	assert.Equal(t, "*.example.org", ownershipName("*.example.org", ""))
+	assert.Equal(t, "wildcard.example.org", ownershipName("*.example.org", "wildcard"))
+	assert.Equal(t, "wildcard", ownershipName("*", "wildcard"))
+	assert.Equal(t, "a.*.example.org", ownershipName("a.*.example.org", "wildcard"))
+	assert.Equal(t, "app.example.org", ownershipName("app.example.org", "wildcard"))
+}
+
+func TestWildcardCollisions(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("*.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("wildcard.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+		endpoint.NewEndpoint("*.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("wildcard.example.com", endpoint.RecordTypeCNAME, "example.com"),
+	}
+
+	assert.Empty(t, wildcardCollisions(endpoints, ""))
+	assert.Empty(t, wildcardCollisions(endpoints, "any"))
+	assert.Equal(t, map[string]string{"*.example.org": "wildcard.example.org"}, wildcardCollisions(endpoints, "wildcard"))
+}
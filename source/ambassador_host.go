@@ -31,7 +31,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -59,20 +58,23 @@ type ambassadorHostSource struct {
 	namespace              string
 	ambassadorHostInformer informers.GenericInformer
 	unstructuredConverter  *unstructuredConverter
+	defaultService         string
 }
 
-// NewAmbassadorHostSource creates a new ambassadorHostSource with the given config.
+// NewAmbassadorHostSource creates a new ambassadorHostSource with the given config. The targets of the Hosts without
+// ambassador-service annotation are resolved from defaultService, given as <namespace>/<name>, if set.
 func NewAmbassadorHostSource(
 	ctx context.Context,
 	dynamicKubeClient dynamic.Interface,
 	kubeClient kubernetes.Interface,
 	namespace string,
+	defaultService string,
 ) (Source, error) {
 	var err error
 
 	// Use shared informer to listen for add/update/delete of Host in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed.
-	informerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicKubeClient, 0, namespace, nil)
+	informerFactory := sharedDynamicInformerFactory(ctx, dynamicKubeClient, namespace)
 	ambassadorHostInformer := informerFactory.ForResource(ambHostGVR)
 
 	// Add default resource event handlers to properly initialize informer.
@@ -100,6 +102,7 @@ func NewAmbassadorHostSource(
 		namespace:              namespace,
 		ambassadorHostInformer: ambassadorHostInformer,
 		unstructuredConverter:  uc,
+		defaultService:         defaultService,
 	}, nil
 }
 
@@ -126,11 +129,15 @@ func (sc *ambassadorHostSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 
 		fullname := fmt.Sprintf("%s/%s", host.Namespace, host.Name)
 
-		// look for the "exernal-dns.ambassador-service" annotation. If it is not there then just ignore this `Host`
+		// look for the "exernal-dns.ambassador-service" annotation. If it is not there and there is no default
+		// service then just ignore this `Host`
 		service, found := host.Annotations[ambHostAnnotation]
 		if !found {
-			log.Debugf("Host %s ignored: no annotation %q found", fullname, ambHostAnnotation)
-			continue
+			if sc.defaultService == "" {
+				log.Debugf("Host %s ignored: no annotation %q found", fullname, ambHostAnnotation)
+				continue
+			}
+			service = sc.defaultService
 		}
 
 		targets := getTargetsFromTargetAnnotation(host.Annotations)
@@ -153,6 +160,7 @@ func (sc *ambassadorHostSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 		}
 
 		log.Debugf("Endpoints generated from Host: %s: %v", fullname, hostEndpoints)
+		hostEndpoints = withRecordType(host.Annotations, hostEndpoints)
 		endpoints = append(endpoints, hostEndpoints...)
 	}
 
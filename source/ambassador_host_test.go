@@ -23,11 +23,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/net/context"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	fakeDynamic "k8s.io/client-go/dynamic/fake"
 	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
 )
 
 type AmbassadorSuite struct {
@@ -69,7 +72,7 @@ func TestAmbassadorHostSource(t *testing.T) {
 		}
 	}
 
-	ambassadorSource, err := NewAmbassadorHostSource(ctx, fakeDynamicClient, fakeKubernetesClient, namespace)
+	ambassadorSource, err := NewAmbassadorHostSource(ctx, fakeDynamicClient, fakeKubernetesClient, namespace, "")
 	if err != nil {
 		t.Fatalf("could not create ambassador source: %v", err)
 	}
@@ -82,6 +85,58 @@ func TestAmbassadorHostSource(t *testing.T) {
 	}
 }
 
+func TestAmbassadorHostSourceDefaultService(t *testing.T) {
+	ctx := context.Background()
+
+	fakeKubernetesClient := fakeKube.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "emissary-ingress",
+			Namespace: "emissary",
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			},
+		},
+	})
+
+	ambassadorScheme := runtime.NewScheme()
+	require.NoError(t, ambassador.AddToScheme(ambassadorScheme))
+	fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(ambassadorScheme)
+
+	for _, host := range []*ambassador.Host{
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "default", Namespace: "test"},
+			Spec:       &ambassador.HostSpec{Hostname: "default.example.org"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{
+				Name:        "annotated",
+				Namespace:   "test",
+				Annotations: map[string]string{ambHostAnnotation: "emissary/missing"},
+			},
+			Spec: &ambassador.HostSpec{Hostname: "annotated.example.org"},
+		},
+	} {
+		obj := &unstructured.Unstructured{}
+		uc, _ := newUnstructuredConverter()
+		require.NoError(t, uc.scheme.Convert(host, obj, nil))
+		_, err := fakeDynamicClient.Resource(ambHostGVR).Namespace("test").Create(ctx, obj, v1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	ambassadorSource, err := NewAmbassadorHostSource(ctx, fakeDynamicClient, fakeKubernetesClient, "test", "emissary/emissary-ingress")
+	require.NoError(t, err)
+
+	endpoints, err := ambassadorSource.Endpoints(ctx)
+	require.NoError(t, err)
+
+	// The annotation takes precedence over the default service, so the Host pointing to a missing service is skipped.
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		newTestEndpoint("default.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	})
+}
+
 func createAmbassadorHost(name, ambassadorService string) (*unstructured.Unstructured, error) {
 	host := &ambassador.Host{
 		ObjectMeta: v1.ObjectMeta{
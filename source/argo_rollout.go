@@ -0,0 +1,296 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var argoRolloutGVR = schema.GroupVersionResource{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "rollouts",
+}
+
+// argoRolloutSource is an implementation of Source for the blue-green Argo Rollouts. The hostnames of the hostname
+// annotation of a Rollout point at its active Service, and the ones of its preview-hostname annotation at its preview
+// Service while a new version awaits promotion.
+type argoRolloutSource struct {
+	annotationFilter      string
+	rolloutInformer       informers.GenericInformer
+	serviceInformer       coreinformers.ServiceInformer
+	namespace             string
+	unstructuredConverter *unstructuredConverter
+}
+
+// NewArgoRolloutSource creates a new argoRolloutSource with the given config.
+func NewArgoRolloutSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string) (Source, error) {
+	// Use shared informer to listen for add/update/delete of Rollouts in the specified namespace.
+	// Set resync period to 0, to prevent processing when nothing has changed.
+	informerFactory := sharedDynamicInformerFactory(ctx, dynamicKubeClient, namespace)
+	rolloutInformer := informerFactory.ForResource(argoRolloutGVR)
+	rolloutInformer.Informer() // Register with factory before starting.
+
+	kubeInformerFactory := sharedKubeInformerFactory(ctx, kubeClient, namespace)
+	serviceInformer := kubeInformerFactory.Core().V1().Services()
+	serviceInformer.Informer() // Register with factory before starting.
+
+	informerFactory.Start(ctx.Done())
+	kubeInformerFactory.Start(ctx.Done())
+
+	// wait for the local cache to be populated.
+	if err := waitForDynamicCacheSync(context.Background(), informerFactory); err != nil {
+		return nil, err
+	}
+	if err := waitForCacheSync(context.Background(), kubeInformerFactory); err != nil {
+		return nil, err
+	}
+
+	uc, err := newArgoRolloutUnstructuredConverter()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to setup Unstructured Converter")
+	}
+
+	return &argoRolloutSource{
+		annotationFilter:      annotationFilter,
+		rolloutInformer:       rolloutInformer,
+		serviceInformer:       serviceInformer,
+		namespace:             namespace,
+		unstructuredConverter: uc,
+	}, nil
+}
+
+// Endpoints returns endpoint objects for each host-target combination that should be processed.
+// Retrieves all Rollouts in the source's namespace(s).
+func (sc *argoRolloutSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	objs, err := sc.rolloutInformer.Lister().ByNamespace(sc.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	selector, err := getLabelSelector(sc.annotationFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []*endpoint.Endpoint
+	for _, obj := range objs {
+		unstructuredRollout, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, errors.New("could not convert")
+		}
+		rollout := &Rollout{}
+		if err := sc.unstructuredConverter.scheme.Convert(unstructuredRollout, rollout, nil); err != nil {
+			return nil, err
+		}
+		if !matchLabelSelector(selector, rollout.Annotations) {
+			continue
+		}
+		blueGreen := rollout.Spec.Strategy.BlueGreen
+		if blueGreen == nil {
+			log.Debugf("Skipping Rollout %s/%s, as it has no blue-green strategy", rollout.Namespace, rollout.Name)
+			continue
+		}
+
+		rolloutEndpoints, err := sc.endpointsFor(rollout, blueGreen.ActiveService, getHostnamesFromAnnotations(rollout.Annotations))
+		if err != nil {
+			return nil, err
+		}
+		// The preview Service selects the same pods as the active Service once the promotion completes,
+		// or when there is no new version.
+		status := rollout.Status.BlueGreen
+		if status.PreviewSelector != "" && status.PreviewSelector != status.ActiveSelector {
+			previewEndpoints, err := sc.endpointsFor(rollout, blueGreen.PreviewService, getPreviewHostnamesFromAnnotations(rollout.Annotations))
+			if err != nil {
+				return nil, err
+			}
+			rolloutEndpoints = append(rolloutEndpoints, previewEndpoints...)
+		}
+
+		log.Debugf("Endpoints generated from Rollout %s/%s: %v", rollout.Namespace, rollout.Name, rolloutEndpoints)
+		endpoints = append(endpoints, rolloutEndpoints...)
+	}
+
+	for _, ep := range endpoints {
+		sort.Sort(ep.Targets)
+	}
+
+	return endpoints, nil
+}
+
+// endpointsFor returns the endpoints of the hostnames pointing at the given Service of the Rollout. The targets are
+// the ones of the target annotation of the Service, or else its load balancer addresses.
+func (sc *argoRolloutSource) endpointsFor(rollout *Rollout, serviceName string, hostnames []string) ([]*endpoint.Endpoint, error) {
+	if serviceName == "" || len(hostnames) == 0 {
+		return nil, nil
+	}
+	svc, err := sc.serviceInformer.Lister().Services(rollout.Namespace).Get(serviceName)
+	if apierrors.IsNotFound(err) {
+		log.Debugf("Service %s/%s of Rollout %s not found", rollout.Namespace, serviceName, rollout.Name)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	targets := getTargetsFromTargetAnnotation(svc.Annotations)
+	if len(targets) == 0 {
+		targets = extractLoadBalancerTargets(svc, false)
+	}
+
+	resource := fmt.Sprintf("rollout/%s/%s", rollout.Namespace, rollout.Name)
+	ttl := getTTLFromAnnotations(rollout.Annotations, resource)
+	providerSpecific, setIdentifier := getProviderSpecificAnnotations(rollout.Annotations)
+
+	var endpoints []*endpoint.Endpoint
+	for _, hostname := range hostnames {
+		endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier, resource)...)
+	}
+	return endpoints, nil
+}
+
+func (sc *argoRolloutSource) AddEventHandler(ctx context.Context, handler func()) {
+	log.Debug("Adding event handler for Rollout")
+
+	// Right now there is no way to remove event handler from informer, see:
+	// https://github.com/kubernetes/kubernetes/issues/79610
+	sc.rolloutInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	sc.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+}
+
+// newArgoRolloutUnstructuredConverter returns a new unstructuredConverter initialized
+func newArgoRolloutUnstructuredConverter() (*unstructuredConverter, error) {
+	uc := &unstructuredConverter{
+		scheme: runtime.NewScheme(),
+	}
+
+	// Add the core types we need
+	uc.scheme.AddKnownTypes(argoRolloutGVR.GroupVersion(), &Rollout{}, &RolloutList{})
+	if err := scheme.AddToScheme(uc.scheme); err != nil {
+		return nil, err
+	}
+
+	return uc, nil
+}
+
+// Argo Rollouts types based on https://github.com/argoproj/argo-rollouts/blob/v1.6.0/pkg/apis/rollouts/v1alpha1/types.go,
+// reduced to the fields used here, see TCPIngress.
+type Rollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   rolloutSpec   `json:"spec,omitempty"`
+	Status rolloutStatus `json:"status,omitempty"`
+}
+
+type RolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Rollout `json:"items"`
+}
+
+type rolloutSpec struct {
+	Strategy rolloutStrategy `json:"strategy"`
+}
+
+type rolloutStrategy struct {
+	BlueGreen *blueGreenStrategy `json:"blueGreen,omitempty"`
+}
+
+type blueGreenStrategy struct {
+	ActiveService  string `json:"activeService"`
+	PreviewService string `json:"previewService,omitempty"`
+}
+
+type rolloutStatus struct {
+	BlueGreen blueGreenStatus `json:"blueGreen,omitempty"`
+}
+
+type blueGreenStatus struct {
+	PreviewSelector string `json:"previewSelector,omitempty"`
+	ActiveSelector  string `json:"activeSelector,omitempty"`
+}
+
+func (in *Rollout) DeepCopyInto(out *Rollout) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec.Strategy.BlueGreen != nil {
+		blueGreen := *in.Spec.Strategy.BlueGreen
+		out.Spec.Strategy.BlueGreen = &blueGreen
+	}
+}
+
+func (in *Rollout) DeepCopy() *Rollout {
+	if in == nil {
+		return nil
+	}
+	out := new(Rollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *Rollout) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (in *RolloutList) DeepCopyInto(out *RolloutList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]Rollout, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+func (in *RolloutList) DeepCopy() *RolloutList {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *RolloutList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// This is a compile-time validation that argoRolloutSource is a Source.
+var _ Source = &argoRolloutSource{}
+
+func TestArgoRolloutEndpoints(t *testing.T) {
+	t.Parallel()
+
+	blueGreen := &blueGreenStrategy{ActiveService: "app-active", PreviewService: "app-preview"}
+	hostnames := map[string]string{
+		hostnameAnnotationKey:        "app.example.com",
+		previewHostnameAnnotationKey: "preview.example.com",
+	}
+	rollout := func(annotations map[string]string, strategy *blueGreenStrategy, status blueGreenStatus) *Rollout {
+		return &Rollout{
+			TypeMeta:   metav1.TypeMeta{APIVersion: argoRolloutGVR.GroupVersion().String(), Kind: "Rollout"},
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: annotations},
+			Spec:       rolloutSpec{Strategy: rolloutStrategy{BlueGreen: strategy}},
+			Status:     rolloutStatus{BlueGreen: status},
+		}
+	}
+
+	for _, ti := range []struct {
+		title            string
+		rollout          *Rollout
+		annotationFilter string
+		expected         []*endpoint.Endpoint
+	}{
+		{
+			title:   "Rollout awaiting promotion",
+			rollout: rollout(hostnames, blueGreen, blueGreenStatus{ActiveSelector: "6b8f9c", PreviewSelector: "7d4c5b"}),
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("app.example.com", "A", "1.2.3.4"),
+				newTestEndpoint("preview.example.com", "CNAME", "preview-lb.example.com"),
+			},
+		},
+		{
+			title:   "Rollout promoted",
+			rollout: rollout(hostnames, blueGreen, blueGreenStatus{ActiveSelector: "7d4c5b", PreviewSelector: "7d4c5b"}),
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("app.example.com", "A", "1.2.3.4"),
+			},
+		},
+		{
+			title:   "Rollout without preview",
+			rollout: rollout(hostnames, blueGreen, blueGreenStatus{ActiveSelector: "6b8f9c"}),
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("app.example.com", "A", "1.2.3.4"),
+			},
+		},
+		{
+			title: "Rollout with target annotation on the preview Service",
+			rollout: rollout(hostnames, &blueGreenStrategy{ActiveService: "app-active", PreviewService: "app-preview-annotated"},
+				blueGreenStatus{ActiveSelector: "6b8f9c", PreviewSelector: "7d4c5b"}),
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("app.example.com", "A", "1.2.3.4"),
+				newTestEndpoint("preview.example.com", "A", "5.6.7.8"),
+			},
+		},
+		{
+			title: "Rollout with missing Service",
+			rollout: rollout(hostnames, &blueGreenStrategy{ActiveService: "app-active", PreviewService: "missing"},
+				blueGreenStatus{ActiveSelector: "6b8f9c", PreviewSelector: "7d4c5b"}),
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("app.example.com", "A", "1.2.3.4"),
+			},
+		},
+		{
+			title:    "Rollout without blue-green strategy",
+			rollout:  rollout(hostnames, nil, blueGreenStatus{}),
+			expected: nil,
+		},
+		{
+			title:            "Rollout not matching the annotation filter",
+			rollout:          rollout(hostnames, blueGreen, blueGreenStatus{ActiveSelector: "6b8f9c"}),
+			annotationFilter: "kubernetes.io/ingress.class=external",
+			expected:         nil,
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			kubeClient := fakeKube.NewSimpleClientset()
+			for _, svc := range []*corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "app-active", Namespace: "default"},
+					Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "app-preview", Namespace: "default"},
+					Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{Hostname: "preview-lb.example.com"}}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "app-preview-annotated", Namespace: "default", Annotations: map[string]string{targetAnnotationKey: "5.6.7.8"}},
+					Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{Hostname: "preview-lb.example.com"}}}},
+				},
+			} {
+				_, err := kubeClient.CoreV1().Services(svc.Namespace).Create(context.Background(), svc, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				argoRolloutGVR: "RolloutList",
+			})
+			data, err := json.Marshal(ti.rollout)
+			require.NoError(t, err)
+			u := &unstructured.Unstructured{}
+			require.NoError(t, u.UnmarshalJSON(data))
+			_, err = fakeDynamicClient.Resource(argoRolloutGVR).Namespace("default").Create(context.Background(), u, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			source, err := NewArgoRolloutSource(context.TODO(), fakeDynamicClient, kubeClient, "", ti.annotationFilter)
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, ti.expected)
+		})
+	}
+}
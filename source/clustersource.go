@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// clusterSource is a Source that labels the endpoints of its wrapped source with the cluster ID.
+type clusterSource struct {
+	source    Source
+	clusterID string
+}
+
+// NewClusterSource creates a new clusterSource wrapping the provided Source.
+func NewClusterSource(source Source, clusterID string) Source {
+	return &clusterSource{source: source, clusterID: clusterID}
+}
+
+// Endpoints collects endpoints from its wrapped source and sets the cluster label on them, unless a nested
+// clusterSource of a member cluster already did.
+func (cs *clusterSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := cs.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ep := range endpoints {
+		if ep.Labels == nil {
+			ep.Labels = endpoint.NewLabels()
+		}
+		if _, ok := ep.Labels[endpoint.ClusterLabelKey]; !ok {
+			ep.Labels[endpoint.ClusterLabelKey] = cs.clusterID
+		}
+	}
+
+	return endpoints, nil
+}
+
+func (cs *clusterSource) AddEventHandler(ctx context.Context, handler func()) {
+	cs.source.AddEventHandler(ctx, handler)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestClusterSource(t *testing.T) {
+	src := NewClusterSource(NewEchoSource([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		{
+			DNSName:    "bar.example.org",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.5"},
+			Labels:     endpoint.Labels{endpoint.ResourceLabelKey: "service/default/bar"},
+		},
+	}), "prod-eu-1")
+
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+
+	assert.Equal(t, "prod-eu-1", endpoints[0].Labels[endpoint.ClusterLabelKey])
+	assert.Equal(t, "prod-eu-1", endpoints[1].Labels[endpoint.ClusterLabelKey])
+	assert.Equal(t, "service/default/bar", endpoints[1].Labels[endpoint.ResourceLabelKey])
+}
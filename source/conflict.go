@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+const (
+	// ConflictPolicyNone keeps the conflicting endpoints of all sources.
+	ConflictPolicyNone = "none"
+	// ConflictPolicyError fails the collection of the endpoints on a conflict.
+	ConflictPolicyError = "error"
+	// ConflictPolicyPreferSourceOrder keeps the endpoints of the source given first.
+	ConflictPolicyPreferSourceOrder = "prefer-source-order"
+	// ConflictPolicyMergeTargets merges the targets of the conflicting endpoints into a single endpoint.
+	ConflictPolicyMergeTargets = "merge-targets"
+	// ConflictPolicyPreferAnnotationWeight keeps the endpoints with the highest source-weight annotation,
+	// falling back to the order of the sources.
+	ConflictPolicyPreferAnnotationWeight = "prefer-annotation-weight"
+)
+
+// ConflictPolicies are the supported policies resolving conflicts between the endpoints of several sources.
+var ConflictPolicies = []string{
+	ConflictPolicyNone,
+	ConflictPolicyError,
+	ConflictPolicyPreferSourceOrder,
+	ConflictPolicyMergeTargets,
+	ConflictPolicyPreferAnnotationWeight,
+}
+
+var sourceConflicts = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: "external_dns",
+		Subsystem: "source",
+		Name:      "conflicts",
+		Help:      "Number of records for which several sources returned endpoints with different targets in the last collection.",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(sourceConflicts)
+}
+
+// sourceEndpoint is an endpoint along with the index of the source that returned it.
+type sourceEndpoint struct {
+	endpoint *endpoint.Endpoint
+	source   int
+}
+
+// resolveConflicts resolves the conflicts between the endpoints of different sources according to the policy.
+// Endpoints conflict when they have the same key but different targets and are returned by different sources;
+// the endpoints of a single source never conflict with each other.
+func resolveConflicts(endpoints []sourceEndpoint, policy string) ([]*endpoint.Endpoint, error) {
+	groups := map[endpoint.EndpointKey][]int{}
+	for i, ep := range endpoints {
+		key := ep.endpoint.Key()
+		groups[key] = append(groups[key], i)
+	}
+
+	// replaced maps the index of an endpoint to the endpoint it is replaced with, nil to drop it.
+	replaced := map[int]*endpoint.Endpoint{}
+	conflicts := 0
+	for _, group := range groups {
+		if !isConflict(endpoints, group) {
+			continue
+		}
+		conflicts++
+		first := endpoints[group[0]].endpoint
+		switch policy {
+		case ConflictPolicyError:
+			sourceConflicts.Set(float64(conflicts))
+			return nil, fmt.Errorf("sources returned conflicting endpoints for %s %s: %s", first.RecordType, first.DNSName, conflictingTargets(endpoints, group))
+		case ConflictPolicyPreferSourceOrder:
+			keepSource(endpoints, group, endpoints[group[0]].source, replaced)
+		case ConflictPolicyMergeTargets:
+			merged := *first
+			merged.Targets = nil
+			for _, i := range group {
+				for _, target := range endpoints[i].endpoint.Targets {
+					if !containsTarget(merged.Targets, target) {
+						merged.Targets = append(merged.Targets, target)
+					}
+				}
+				replaced[i] = nil
+			}
+			replaced[group[0]] = &merged
+		case ConflictPolicyPreferAnnotationWeight:
+			winner := group[0]
+			for _, i := range group[1:] {
+				if sourceWeight(endpoints[i].endpoint) > sourceWeight(endpoints[winner].endpoint) {
+					winner = i
+				}
+			}
+			keepSource(endpoints, group, endpoints[winner].source, replaced)
+		default:
+			log.Debugf("Keeping the conflicting endpoints for %s %s: %s", first.RecordType, first.DNSName, conflictingTargets(endpoints, group))
+		}
+	}
+	sourceConflicts.Set(float64(conflicts))
+
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for i, ep := range endpoints {
+		if r, ok := replaced[i]; ok {
+			if r == nil {
+				continue
+			}
+			ep.endpoint = r
+		}
+		result = append(result, withoutSourceWeight(ep.endpoint))
+	}
+	return result, nil
+}
+
+// isConflict tells whether the endpoints of the group come from different sources and have different targets.
+func isConflict(endpoints []sourceEndpoint, group []int) bool {
+	first := endpoints[group[0]]
+	for _, i := range group[1:] {
+		if endpoints[i].source != first.source && !endpoints[i].endpoint.Targets.Same(first.endpoint.Targets) {
+			return true
+		}
+	}
+	return false
+}
+
+// keepSource drops the endpoints of the group not returned by the source.
+func keepSource(endpoints []sourceEndpoint, group []int, source int, replaced map[int]*endpoint.Endpoint) {
+	for _, i := range group {
+		if endpoints[i].source != source {
+			log.Debugf("Dropping conflicting endpoint %s", endpoints[i].endpoint)
+			replaced[i] = nil
+		}
+	}
+}
+
+// conflictingTargets describes the targets of the endpoints of the group.
+func conflictingTargets(endpoints []sourceEndpoint, group []int) string {
+	targets := make([]string, 0, len(group))
+	for _, i := range group {
+		targets = append(targets, endpoints[i].endpoint.Targets.String())
+	}
+	return strings.Join(targets, ", ")
+}
+
+func containsTarget(targets endpoint.Targets, target string) bool {
+	for _, t := range targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceWeight returns the weight set with the source-weight annotation, 0 if it is absent or invalid.
+func sourceWeight(ep *endpoint.Endpoint) int64 {
+	value, ok := ep.GetProviderSpecificProperty(endpoint.ProviderSpecificSourceWeight)
+	if !ok {
+		return 0
+	}
+	weight, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Warnf("Ignoring invalid source weight %q of endpoint %s", value, ep)
+		return 0
+	}
+	return weight
+}
+
+// withoutSourceWeight returns the endpoint without the source weight, which only matters to the resolution of
+// conflicts. The endpoint is copied rather than modified since sources may cache the endpoints they return.
+func withoutSourceWeight(ep *endpoint.Endpoint) *endpoint.Endpoint {
+	if _, ok := ep.GetProviderSpecificProperty(endpoint.ProviderSpecificSourceWeight); !ok {
+		return ep
+	}
+	c := *ep
+	c.ProviderSpecific = nil
+	for _, p := range ep.ProviderSpecific {
+		if p.Name != endpoint.ProviderSpecificSourceWeight {
+			c.ProviderSpecific = append(c.ProviderSpecific, p)
+		}
+	}
+	return &c
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestResolveConflicts(t *testing.T) {
+	newChildren := func() []Source {
+		return []Source{
+			NewEchoSource([]*endpoint.Endpoint{
+				endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+			}),
+			NewEchoSource([]*endpoint.Endpoint{
+				endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "2.2.2.2").WithProviderSpecific(endpoint.ProviderSpecificSourceWeight, "10"),
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+				endpoint.NewEndpoint("ing.example.org", endpoint.RecordTypeA, "4.4.4.4"),
+			}),
+		}
+	}
+
+	for _, tc := range []struct {
+		policy   string
+		expected []*endpoint.Endpoint
+	}{
+		{
+			policy: ConflictPolicyNone,
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+				endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+				endpoint.NewEndpoint("ing.example.org", endpoint.RecordTypeA, "4.4.4.4"),
+			},
+		},
+		{
+			policy: ConflictPolicyPreferSourceOrder,
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+				endpoint.NewEndpoint("ing.example.org", endpoint.RecordTypeA, "4.4.4.4"),
+			},
+		},
+		{
+			policy: ConflictPolicyMergeTargets,
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.1.1.1", "2.2.2.2"),
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+				endpoint.NewEndpoint("ing.example.org", endpoint.RecordTypeA, "4.4.4.4"),
+			},
+		},
+		{
+			policy: ConflictPolicyPreferAnnotationWeight,
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+				endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+				endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "3.3.3.3"),
+				endpoint.NewEndpoint("ing.example.org", endpoint.RecordTypeA, "4.4.4.4"),
+			},
+		},
+	} {
+		t.Run(tc.policy, func(t *testing.T) {
+			children := newChildren()
+			endpoints, err := NewMultiSource(children, nil, tc.policy).Endpoints(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, endpoints)
+			assert.Equal(t, 1.0, testutil.ToFloat64(sourceConflicts))
+
+			// The endpoints returned by the children keep their source weight.
+			weighted, err := children[1].Endpoints(context.Background())
+			require.NoError(t, err)
+			_, ok := weighted[0].GetProviderSpecificProperty(endpoint.ProviderSpecificSourceWeight)
+			assert.True(t, ok)
+		})
+	}
+
+	t.Run(ConflictPolicyError, func(t *testing.T) {
+		_, err := NewMultiSource(newChildren(), nil, ConflictPolicyError).Endpoints(context.Background())
+		assert.EqualError(t, err, "sources returned conflicting endpoints for A app.example.org: 1.1.1.1, 2.2.2.2")
+	})
+
+	t.Run("single source", func(t *testing.T) {
+		src := NewEchoSource([]*endpoint.Endpoint{
+			endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.1.1.1"),
+			endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "2.2.2.2"),
+		})
+		endpoints, err := NewMultiSource([]Source{src}, nil, ConflictPolicyError).Endpoints(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, endpoints, 2)
+		assert.Equal(t, 0.0, testutil.ToFloat64(sourceConflicts))
+	})
+}
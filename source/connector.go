@@ -18,10 +18,15 @@ package source
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/gob"
+	"fmt"
 	"net"
+	"os"
+	"strings"
 	"time"
 
+	backoff "github.com/cenkalti/backoff/v4"
 	log "github.com/sirupsen/logrus"
 
 	"sigs.k8s.io/external-dns/endpoint"
@@ -29,40 +34,88 @@ import (
 
 const (
 	dialTimeout = 30 * time.Second
+	// connectorMaxRetries is how many times the connection to the remote server is retried, with an exponential
+	// backoff, when it fails or is closed before all the endpoints are received.
+	connectorMaxRetries = 5
 )
 
 // connectorSource is an implementation of Source that provides endpoints by connecting
 // to a remote tcp server. The encoding/decoding is done using encoder/gob package.
+// When an auth token is set, it is sent gob encoded as a string before the endpoints are received.
 type connectorSource struct {
 	remoteServer string
+	// tlsConfig secures the connection with TLS when set, e.g. presenting a client certificate
+	tlsConfig  *tls.Config
+	tokenFile  string
+	newBackOff func() backoff.BackOff
 }
 
 // NewConnectorSource creates a new connectorSource with the given config.
-func NewConnectorSource(remoteServer string) (Source, error) {
+func NewConnectorSource(remoteServer string, tlsConfig *tls.Config, tokenFile string) (Source, error) {
 	return &connectorSource{
 		remoteServer: remoteServer,
+		tlsConfig:    tlsConfig,
+		tokenFile:    tokenFile,
+		newBackOff: func() backoff.BackOff {
+			return backoff.WithMaxRetries(backoff.NewExponentialBackOff(), connectorMaxRetries)
+		},
 	}, nil
 }
 
 // Endpoints returns endpoint objects.
 func (cs *connectorSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
-	endpoints := []*endpoint.Endpoint{}
+	var token string
+	if cs.tokenFile != "" {
+		content, err := os.ReadFile(cs.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the auth token of %s: %w", cs.remoteServer, err)
+		}
+		token = strings.TrimSpace(string(content))
+	}
 
-	conn, err := net.DialTimeout("tcp", cs.remoteServer, dialTimeout)
+	var endpoints []*endpoint.Endpoint
+	err := backoff.RetryNotify(func() error {
+		var err error
+		endpoints, err = cs.receiveEndpoints(ctx, token)
+		return err
+	}, backoff.WithContext(cs.newBackOff(), ctx), func(err error, next time.Duration) {
+		log.Warnf("Failed to receive the endpoints of %s, reconnecting in %s: %v", cs.remoteServer, next, err)
+	})
 	if err != nil {
 		log.Errorf("Connection error: %v", err)
 		return nil, err
 	}
-	defer conn.Close()
 
-	decoder := gob.NewDecoder(conn)
-	if err := decoder.Decode(&endpoints); err != nil {
-		log.Errorf("Decode error: %v", err)
+	log.Debugf("Received endpoints: %#v", endpoints)
+
+	return endpoints, nil
+}
+
+// receiveEndpoints connects to the remote server, sends it the auth token if any, and decodes the endpoints.
+func (cs *connectorSource) receiveEndpoints(ctx context.Context, token string) ([]*endpoint.Endpoint, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	var err error
+	if cs.tlsConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: cs.tlsConfig}).DialContext(ctx, "tcp", cs.remoteServer)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", cs.remoteServer)
+	}
+	if err != nil {
 		return nil, err
 	}
+	defer conn.Close()
 
-	log.Debugf("Received endpoints: %#v", endpoints)
+	if token != "" {
+		if err := gob.NewEncoder(conn).Encode(token); err != nil {
+			return nil, fmt.Errorf("failed to send the auth token: %w", err)
+		}
+	}
 
+	endpoints := []*endpoint.Endpoint{}
+	if err := gob.NewDecoder(conn).Decode(&endpoints); err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
 	return endpoints, nil
 }
 
@@ -18,11 +18,23 @@ package source
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/gob"
+	"math/big"
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	backoff "github.com/cenkalti/backoff/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"sigs.k8s.io/external-dns/endpoint"
@@ -55,6 +67,15 @@ func startServerToServeTargets(t *testing.T, endpoints []*endpoint.Endpoint) net
 	return ln
 }
 
+// newTestConnectorSource creates a connectorSource retrying twice without waiting.
+func newTestConnectorSource(addr string, tlsConfig *tls.Config, tokenFile string) *connectorSource {
+	cs, _ := NewConnectorSource(addr, tlsConfig, tokenFile)
+	cs.(*connectorSource).newBackOff = func() backoff.BackOff {
+		return backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 2)
+	}
+	return cs.(*connectorSource)
+}
+
 func TestConnectorSource(t *testing.T) {
 	t.Parallel()
 
@@ -129,7 +150,7 @@ func testConnectorSourceEndpoints(t *testing.T) {
 				defer ln.Close()
 				addr = ln.Addr().String()
 			}
-			cs, _ := NewConnectorSource(addr)
+			cs := newTestConnectorSource(addr, nil, "")
 
 			endpoints, err := cs.Endpoints(context.Background())
 			if ti.expectError {
@@ -143,3 +164,129 @@ func testConnectorSourceEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestConnectorSourceReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	expected := []*endpoint.Endpoint{endpoint.NewEndpoint("abc.example.org", endpoint.RecordTypeA, "1.2.3.4")}
+	go func() {
+		// the first connection is closed before the endpoints are sent
+		if conn, err := ln.Accept(); err == nil {
+			conn.Close()
+		}
+		if conn, err := ln.Accept(); err == nil {
+			gob.NewEncoder(conn).Encode(expected)
+			conn.Close()
+		}
+	}()
+
+	endpoints, err := newTestConnectorSource(ln.Addr().String(), nil, "").Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, expected)
+}
+
+func TestConnectorSourceAuthToken(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	expected := []*endpoint.Endpoint{endpoint.NewEndpoint("abc.example.org", endpoint.RecordTypeA, "1.2.3.4")}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			var token string
+			if err := gob.NewDecoder(conn).Decode(&token); err == nil && token == "secret" {
+				gob.NewEncoder(conn).Encode(expected)
+			}
+			conn.Close()
+		}
+	}()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+	endpoints, err := newTestConnectorSource(ln.Addr().String(), nil, tokenFile).Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, expected)
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("wrong"), 0o600))
+	_, err = newTestConnectorSource(ln.Addr().String(), nil, tokenFile).Endpoints(context.Background())
+	assert.Error(t, err)
+
+	_, err = newTestConnectorSource(ln.Addr().String(), nil, filepath.Join(t.TempDir(), "missing")).Endpoints(context.Background())
+	assert.Error(t, err)
+}
+
+func TestConnectorSourceTLS(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	issue := func(serial int64, usage x509.ExtKeyUsage) tls.Certificate {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "test"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{issue(2, x509.ExtKeyUsageServerAuth)},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    roots,
+	})
+	require.NoError(t, err)
+	defer ln.Close()
+	expected := []*endpoint.Endpoint{endpoint.NewEndpoint("abc.example.org", endpoint.RecordTypeA, "1.2.3.4")}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			gob.NewEncoder(conn).Encode(expected)
+			conn.Close()
+		}
+	}()
+
+	endpoints, err := newTestConnectorSource(ln.Addr().String(), &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{issue(3, x509.ExtKeyUsageClientAuth)},
+	}, "").Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, expected)
+
+	// the server requires a client certificate
+	_, err = newTestConnectorSource(ln.Addr().String(), &tls.Config{RootCAs: roots}, "").Endpoints(context.Background())
+	assert.Error(t, err)
+
+	// the server certificate isn't trusted
+	_, err = newTestConnectorSource(ln.Addr().String(), &tls.Config{
+		Certificates: []tls.Certificate{issue(4, x509.ExtKeyUsageClientAuth)},
+	}, "").Endpoints(context.Background())
+	assert.Error(t, err)
+}
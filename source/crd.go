@@ -20,21 +20,28 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
 
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	gateway "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -43,12 +50,17 @@ import (
 // specified CRD and fetching Endpoints embedded in Spec.
 type crdSource struct {
 	crdClient        rest.Interface
-	namespace        string
+	namespaces       []string
 	crdResource      string
 	codec            runtime.ParameterCodec
 	annotationFilter string
 	labelSelector    labels.Selector
-	informer         *cache.SharedInformer
+	informers        []cache.SharedInformer
+	targetResolver   *targetReferenceResolver
+
+	// rejected are the records of the DNSEndpoints rejected by the last Endpoints call, keyed by DNSEndpoint
+	rejected    map[types.NamespacedName][]endpoint.DNSEndpointRecordStatus
+	rejectedMux sync.Mutex
 }
 
 func addKnownTypes(scheme *runtime.Scheme, groupVersion schema.GroupVersion) error {
@@ -108,41 +120,63 @@ func NewCRDClientForAPIVersionKind(client kubernetes.Interface, kubeConfig, apiS
 }
 
 // NewCRDSource creates a new crdSource with the given config.
-func NewCRDSource(crdClient rest.Interface, namespace, kind string, annotationFilter string, labelSelector labels.Selector, scheme *runtime.Scheme, startInformer bool) (Source, error) {
+// The DNSEndpoints are read from the namespaces, all namespaces if there are none or one of them is empty.
+// The kube and gateway clients are used to resolve the targetsFrom references of endpoints, they may be nil,
+// through informers shared with the other sources by the informer factories, which may be nil too.
+func NewCRDSource(crdClient rest.Interface, namespaces []string, kind string, annotationFilter string, labelSelector labels.Selector, scheme *runtime.Scheme, startInformer bool, kubeClient kubernetes.Interface, gatewayClient gateway.Interface, informerFactories *InformerFactories) (Source, error) {
+	if len(namespaces) == 0 || slices.Contains(namespaces, "") {
+		namespaces = []string{""}
+	}
 	sourceCrd := crdSource{
 		crdResource:      strings.ToLower(kind) + "s",
-		namespace:        namespace,
+		namespaces:       namespaces,
 		annotationFilter: annotationFilter,
 		labelSelector:    labelSelector,
 		crdClient:        crdClient,
 		codec:            runtime.NewParameterCodec(scheme),
+		targetResolver:   &targetReferenceResolver{kubeClient: kubeClient, gatewayClient: gatewayClient, informerFactories: informerFactories},
 	}
 	if startInformer {
 		// external-dns already runs its sync-handler periodically (controlled by `--interval` flag) to ensure any
 		// missed or dropped events are handled.  specify a resync period 0 to avoid unnecessary sync handler invocations.
-		informer := cache.NewSharedInformer(
-			&cache.ListWatch{
-				ListFunc: func(lo metav1.ListOptions) (result runtime.Object, err error) {
-					return sourceCrd.List(context.TODO(), &lo)
+		for _, namespace := range sourceCrd.namespaces {
+			namespace := namespace
+			informer := cache.NewSharedInformer(
+				&cache.ListWatch{
+					ListFunc: func(lo metav1.ListOptions) (result runtime.Object, err error) {
+						return sourceCrd.list(context.TODO(), namespace, &lo)
+					},
+					WatchFunc: func(lo metav1.ListOptions) (watch.Interface, error) {
+						return sourceCrd.watch(context.TODO(), namespace, &lo)
+					},
 				},
-				WatchFunc: func(lo metav1.ListOptions) (watch.Interface, error) {
-					return sourceCrd.watch(context.TODO(), &lo)
-				},
-			},
-			&endpoint.DNSEndpoint{},
-			0)
-		sourceCrd.informer = &informer
-		go informer.Run(wait.NeverStop)
+				&endpoint.DNSEndpoint{},
+				0)
+			sourceCrd.informers = append(sourceCrd.informers, informer)
+			go informer.Run(wait.NeverStop)
+		}
 	}
 	return &sourceCrd, nil
 }
 
+// crdLabelSelector returns the selector of the DNSEndpoints matching both the label filter of all sources and the
+// one of the crd source.
+func crdLabelSelector(labelFilter, crdLabelFilter labels.Selector) labels.Selector {
+	if labelFilter == nil {
+		labelFilter = labels.Everything()
+	}
+	if crdLabelFilter == nil {
+		return labelFilter
+	}
+	requirements, _ := crdLabelFilter.Requirements()
+	return labelFilter.Add(requirements...)
+}
+
 func (cs *crdSource) AddEventHandler(ctx context.Context, handler func()) {
-	if cs.informer != nil {
+	for _, informer := range cs.informers {
 		log.Debug("Adding event handler for CRD")
 		// Right now there is no way to remove event handler from informer, see:
 		// https://github.com/kubernetes/kubernetes/issues/79610
-		informer := *cs.informer
 		informer.AddEventHandler(
 			cache.ResourceEventHandlerFuncs{
 				AddFunc: func(obj interface{}) {
@@ -179,24 +213,31 @@ func (cs *crdSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error
 		return nil, err
 	}
 
+	rejected := map[types.NamespacedName][]endpoint.DNSEndpointRecordStatus{}
 	for _, dnsEndpoint := range result.Items {
+		name := types.NamespacedName{Namespace: dnsEndpoint.Namespace, Name: dnsEndpoint.Name}
+		reject := func(ep *endpoint.Endpoint, reason string) {
+			rejected[name] = append(rejected[name], endpoint.DNSEndpointRecordStatus{
+				DNSName:       ep.DNSName,
+				RecordType:    ep.RecordType,
+				SetIdentifier: ep.SetIdentifier,
+				Error:         reason,
+			})
+		}
+
 		// Make sure that all endpoints have targets for A or CNAME type
 		crdEndpoints := []*endpoint.Endpoint{}
 		for _, ep := range dnsEndpoint.Spec.Endpoints {
-			if (ep.RecordType == "CNAME" || ep.RecordType == "A" || ep.RecordType == "AAAA") && len(ep.Targets) < 1 {
-				log.Warnf("Endpoint %s with DNSName %s has an empty list of targets", dnsEndpoint.ObjectMeta.Name, ep.DNSName)
-				continue
-			}
-
-			illegalTarget := false
-			for _, target := range ep.Targets {
-				if strings.HasSuffix(target, ".") {
-					illegalTarget = true
-					break
+			if len(ep.TargetsFrom) > 0 {
+				if err := cs.targetResolver.resolve(ctx, dnsEndpoint.Namespace, ep); err != nil {
+					log.Warnf("Endpoint %s with DNSName %s is rejected: %v", dnsEndpoint.ObjectMeta.Name, ep.DNSName, err)
+					reject(ep, err.Error())
+					continue
 				}
 			}
-			if illegalTarget {
-				log.Warnf("Endpoint %s with DNSName %s has an illegal target. The subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com')", dnsEndpoint.ObjectMeta.Name, ep.DNSName)
+			if err := validateStaticEndpoint(ep); err != nil {
+				log.Warnf("Endpoint %s with DNSName %s is invalid: %v", dnsEndpoint.ObjectMeta.Name, ep.DNSName, err)
+				reject(ep, err.Error())
 				continue
 			}
 
@@ -208,6 +249,7 @@ func (cs *crdSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error
 		}
 
 		cs.setResourceLabel(&dnsEndpoint, crdEndpoints)
+		setCommitLabel(dnsEndpoint.Annotations, crdEndpoints)
 		endpoints = append(endpoints, crdEndpoints...)
 
 		if dnsEndpoint.Status.ObservedGeneration == dnsEndpoint.Generation {
@@ -222,28 +264,157 @@ func (cs *crdSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error
 		}
 	}
 
+	cs.rejectedMux.Lock()
+	cs.rejected = rejected
+	cs.rejectedMux.Unlock()
+
 	return endpoints, nil
 }
 
+// ReportSync writes the outcome of the synchronization of their records to the status of the DNSEndpoints.
+func (cs *crdSource) ReportSync(ctx context.Context, report SyncReport) {
+	results := map[types.NamespacedName]map[endpoint.EndpointKey]RecordSyncResult{}
+	for _, r := range report.Records {
+		resource, ok := strings.CutPrefix(r.Endpoint.Labels[endpoint.ResourceLabelKey], "crd/")
+		if !ok {
+			continue
+		}
+		namespace, name, ok := strings.Cut(resource, "/")
+		if !ok {
+			continue
+		}
+		key := types.NamespacedName{Namespace: namespace, Name: name}
+		if results[key] == nil {
+			results[key] = map[endpoint.EndpointKey]RecordSyncResult{}
+		}
+		results[key][recordStatusKey(r.Endpoint.DNSName, r.Endpoint.RecordType, r.Endpoint.SetIdentifier)] = r
+	}
+
+	cs.rejectedMux.Lock()
+	rejected := cs.rejected
+	cs.rejectedMux.Unlock()
+
+	list, err := cs.List(ctx, &metav1.ListOptions{LabelSelector: cs.labelSelector.String()})
+	if err == nil {
+		list, err = cs.filterByAnnotations(list)
+	}
+	if err != nil {
+		log.Warnf("Could not list the DNSEndpoints to update their status: %v", err)
+		return
+	}
+
+	for i := range list.Items {
+		dnsEndpoint := &list.Items[i]
+		name := types.NamespacedName{Namespace: dnsEndpoint.Namespace, Name: dnsEndpoint.Name}
+		status := dnsEndpointSyncStatus(dnsEndpoint, results[name], rejected[name], report.Time)
+		if equality.Semantic.DeepEqual(dnsEndpoint.Status, status) {
+			continue
+		}
+		dnsEndpoint.Status = status
+		if _, err := cs.UpdateStatus(ctx, dnsEndpoint); err != nil {
+			log.Warnf("Could not update the status of DNSEndpoint %s: %v", name, err)
+		}
+	}
+}
+
+// dnsEndpointSyncStatus returns the status of the DNSEndpoint given the outcome of the synchronization of its
+// records and the records rejected by the source.
+func dnsEndpointSyncStatus(dnsEndpoint *endpoint.DNSEndpoint, results map[endpoint.EndpointKey]RecordSyncResult, rejected []endpoint.DNSEndpointRecordStatus, now time.Time) endpoint.DNSEndpointStatus {
+	status := *dnsEndpoint.Status.DeepCopy()
+	status.ObservedGeneration = dnsEndpoint.Generation
+	status.Records = nil
+
+	rejectedByKey := make(map[endpoint.EndpointKey]endpoint.DNSEndpointRecordStatus, len(rejected))
+	for _, r := range rejected {
+		rejectedByKey[recordStatusKey(r.DNSName, r.RecordType, r.SetIdentifier)] = r
+	}
+
+	notSynced, firstError, applied := 0, "", false
+	for _, ep := range dnsEndpoint.Spec.Endpoints {
+		record := endpoint.DNSEndpointRecordStatus{DNSName: ep.DNSName, RecordType: ep.RecordType, SetIdentifier: ep.SetIdentifier}
+		key := recordStatusKey(ep.DNSName, ep.RecordType, ep.SetIdentifier)
+		if r, ok := rejectedByKey[key]; ok {
+			record.Error = r.Error
+		} else if r, ok := results[key]; ok {
+			record.Synced, record.Error = r.Synced, r.Error
+			applied = applied || r.Applied
+		} else {
+			record.Error = "the record is not desired, it may be filtered out before being planned"
+		}
+		if !record.Synced {
+			notSynced++
+			if firstError == "" {
+				firstError = fmt.Sprintf("%s %s: %s", record.DNSName, record.RecordType, record.Error)
+			}
+		}
+		status.Records = append(status.Records, record)
+	}
+
+	if applied {
+		status.LastAppliedTime = &metav1.Time{Time: now}
+	}
+
+	condition := metav1.Condition{
+		Type:               endpoint.DNSEndpointConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: dnsEndpoint.Generation,
+		Reason:             "Synced",
+		Message:            fmt.Sprintf("All %d records are synced", len(status.Records)),
+	}
+	if notSynced > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NotSynced"
+		condition.Message = fmt.Sprintf("%d of %d records are not synced, first: %s", notSynced, len(status.Records), firstError)
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	return status
+}
+
+// recordStatusKey identifies the record of an endpoint regardless of the case and of the trailing dot of its name.
+func recordStatusKey(dnsName, recordType, setIdentifier string) endpoint.EndpointKey {
+	return endpoint.EndpointKey{
+		DNSName:       strings.TrimSuffix(strings.ToLower(dnsName), "."),
+		RecordType:    recordType,
+		SetIdentifier: setIdentifier,
+	}
+}
+
 func (cs *crdSource) setResourceLabel(crd *endpoint.DNSEndpoint, endpoints []*endpoint.Endpoint) {
 	for _, ep := range endpoints {
 		ep.Labels[endpoint.ResourceLabelKey] = fmt.Sprintf("crd/%s/%s", crd.ObjectMeta.Namespace, crd.ObjectMeta.Name)
 	}
 }
 
-func (cs *crdSource) watch(ctx context.Context, opts *metav1.ListOptions) (watch.Interface, error) {
+func (cs *crdSource) watch(ctx context.Context, namespace string, opts *metav1.ListOptions) (watch.Interface, error) {
 	opts.Watch = true
 	return cs.crdClient.Get().
-		Namespace(cs.namespace).
+		Namespace(namespace).
 		Resource(cs.crdResource).
 		VersionedParams(opts, cs.codec).
 		Watch(ctx)
 }
 
-func (cs *crdSource) List(ctx context.Context, opts *metav1.ListOptions) (result *endpoint.DNSEndpointList, err error) {
+// List lists the DNSEndpoints of all the namespaces of the source.
+func (cs *crdSource) List(ctx context.Context, opts *metav1.ListOptions) (*endpoint.DNSEndpointList, error) {
+	if len(cs.namespaces) == 1 {
+		return cs.list(ctx, cs.namespaces[0], opts)
+	}
+	result := &endpoint.DNSEndpointList{}
+	for _, namespace := range cs.namespaces {
+		list, err := cs.list(ctx, namespace, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, list.Items...)
+	}
+	return result, nil
+}
+
+func (cs *crdSource) list(ctx context.Context, namespace string, opts *metav1.ListOptions) (result *endpoint.DNSEndpointList, err error) {
 	result = &endpoint.DNSEndpointList{}
 	err = cs.crdClient.Get().
-		Namespace(cs.namespace).
+		Namespace(namespace).
 		Resource(cs.crdResource).
 		VersionedParams(opts, cs.codec).
 		Do(ctx).
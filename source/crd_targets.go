@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	gateway "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+	gatewayinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// targetReferenceResolver resolves the targetsFrom references of DNSEndpoints
+// to the load balancer addresses of the referenced objects. The objects are read
+// from informers, started when the first reference of their kind is resolved.
+type targetReferenceResolver struct {
+	kubeClient    kubernetes.Interface
+	gatewayClient gateway.Interface
+	// informerFactories, when set, share the Service informer with the other sources.
+	informerFactories *InformerFactories
+
+	mu               sync.Mutex
+	kubeInformers    kubeinformers.SharedInformerFactory
+	gatewayInformers gatewayinformers.SharedInformerFactory
+	services         corelisters.ServiceLister
+	gateways         gatewaylisters.GatewayLister
+}
+
+// resolve adds the addresses of the objects referenced by ep to its targets and clears the references.
+// Objects which don't exist or have no address are skipped with a warning, other errors are returned,
+// so that the endpoint is rejected instead of being published with part of its targets.
+func (r *targetReferenceResolver) resolve(ctx context.Context, namespace string, ep *endpoint.Endpoint) error {
+	for _, ref := range ep.TargetsFrom {
+		if ref.Namespace == "" {
+			ref.Namespace = namespace
+		}
+		addresses, err := r.addresses(ctx, ref)
+		if errors.IsNotFound(err) {
+			log.Warnf("Target reference %s of DNSName %s not found", ref, ep.DNSName)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("resolving target reference %s: %w", ref, err)
+		}
+		targets := filterAddressesForRecordType(ep.RecordType, addresses)
+		if len(targets) == 0 {
+			log.Warnf("Target reference %s of DNSName %s has no address usable in a %s record", ref, ep.DNSName, ep.RecordType)
+			continue
+		}
+		ep.Targets = append(ep.Targets, targets...)
+	}
+	ep.TargetsFrom = nil
+	if ep.RecordType == endpoint.RecordTypeCNAME && len(ep.Targets) > 1 {
+		log.Warnf("Using only the first of the targets %v of CNAME DNSName %s", ep.Targets, ep.DNSName)
+		ep.Targets = ep.Targets[:1]
+	}
+	return nil
+}
+
+func (r *targetReferenceResolver) addresses(ctx context.Context, ref endpoint.TargetReference) ([]string, error) {
+	var addresses []string
+	switch ref.Kind {
+	case "Service":
+		services, err := r.serviceLister(ctx)
+		if err != nil {
+			return nil, err
+		}
+		svc, err := services.Services(ref.Namespace).Get(ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				addresses = append(addresses, ingress.IP)
+			}
+			if ingress.Hostname != "" {
+				addresses = append(addresses, ingress.Hostname)
+			}
+		}
+	case "Gateway":
+		gateways, err := r.gatewayLister(ctx)
+		if err != nil {
+			return nil, err
+		}
+		gw, err := gateways.Gateways(ref.Namespace).Get(ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range gw.Status.Addresses {
+			addresses = append(addresses, addr.Value)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", ref.Kind)
+	}
+	return addresses, nil
+}
+
+// serviceLister returns the lister of the Services of all namespaces, starting their informer if needed.
+func (r *targetReferenceResolver) serviceLister(ctx context.Context) (corelisters.ServiceLister, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.services != nil {
+		return r.services, nil
+	}
+	if r.kubeClient == nil {
+		return nil, fmt.Errorf("no Kubernetes client")
+	}
+	if r.kubeInformers == nil {
+		r.kubeInformers = sharedKubeInformerFactory(WithInformerFactories(ctx, r.informerFactories), r.kubeClient, "")
+	}
+	informer := r.kubeInformers.Core().V1().Services()
+	informer.Informer() // Register with factory before starting.
+	r.kubeInformers.Start(wait.NeverStop)
+	if err := waitForCacheSync(ctx, r.kubeInformers); err != nil {
+		return nil, err
+	}
+	r.services = informer.Lister()
+	return r.services, nil
+}
+
+// gatewayLister returns the lister of the Gateways of all namespaces, starting their informer if needed.
+func (r *targetReferenceResolver) gatewayLister(ctx context.Context) (gatewaylisters.GatewayLister, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.gateways != nil {
+		return r.gateways, nil
+	}
+	if r.gatewayClient == nil {
+		return nil, fmt.Errorf("no Gateway API client")
+	}
+	if r.gatewayInformers == nil {
+		r.gatewayInformers = newGatewayInformerFactory(r.gatewayClient, "", labels.Everything())
+	}
+	informer := r.gatewayInformers.Gateway().V1().Gateways()
+	informer.Informer() // Register with factory before starting.
+	r.gatewayInformers.Start(wait.NeverStop)
+	if err := waitForCacheSync(ctx, r.gatewayInformers); err != nil {
+		return nil, err
+	}
+	r.gateways = informer.Lister()
+	return r.gateways, nil
+}
+
+// filterAddressesForRecordType keeps the IPv4 addresses for A records, the IPv6 addresses for AAAA records
+// and the hostnames for CNAME records. Other record types use all addresses.
+func filterAddressesForRecordType(recordType string, addresses []string) []string {
+	var filtered []string
+	for _, address := range addresses {
+		ip, err := netip.ParseAddr(address)
+		isIP := err == nil
+		switch recordType {
+		case endpoint.RecordTypeA:
+			if !isIP || !ip.Is4() {
+				continue
+			}
+		case endpoint.RecordTypeAAAA:
+			if !isIP || !ip.Is6() {
+				continue
+			}
+		case endpoint.RecordTypeCNAME:
+			if isIP {
+				continue
+			}
+		}
+		filtered = append(filtered, address)
+	}
+	return filtered
+}
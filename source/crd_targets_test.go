@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestTargetReferenceResolver(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "default"},
+			Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{
+				{IP: "1.2.3.4"},
+				{IP: "2001:db8::1"},
+			}}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "elb", Namespace: "infra"},
+			Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{
+				{Hostname: "elb.example.org"},
+			}}},
+		},
+	)
+	gatewayClient := gatewayfake.NewSimpleClientset()
+	_, err := gatewayClient.GatewayV1().Gateways("default").Create(context.Background(), &v1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+		Status: v1.GatewayStatus{Addresses: []v1.GatewayStatusAddress{
+			{Value: "5.6.7.8"},
+		}},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	resolver := &targetReferenceResolver{kubeClient: kubeClient, gatewayClient: gatewayClient}
+
+	for _, tc := range []struct {
+		title      string
+		recordType string
+		targets    endpoint.Targets
+		refs       []endpoint.TargetReference
+		expected   endpoint.Targets
+	}{
+		{
+			title:      "service IPv4 address",
+			recordType: endpoint.RecordTypeA,
+			refs:       []endpoint.TargetReference{{Kind: "Service", Name: "lb"}},
+			expected:   endpoint.Targets{"1.2.3.4"},
+		},
+		{
+			title:      "service IPv6 address",
+			recordType: endpoint.RecordTypeAAAA,
+			refs:       []endpoint.TargetReference{{Kind: "Service", Name: "lb"}},
+			expected:   endpoint.Targets{"2001:db8::1"},
+		},
+		{
+			title:      "service hostname in another namespace",
+			recordType: endpoint.RecordTypeCNAME,
+			refs:       []endpoint.TargetReference{{Kind: "Service", Namespace: "infra", Name: "elb"}},
+			expected:   endpoint.Targets{"elb.example.org"},
+		},
+		{
+			title:      "static targets and gateway address",
+			recordType: endpoint.RecordTypeA,
+			targets:    endpoint.Targets{"9.9.9.9"},
+			refs:       []endpoint.TargetReference{{Kind: "Gateway", Name: "gw"}},
+			expected:   endpoint.Targets{"9.9.9.9", "5.6.7.8"},
+		},
+		{
+			title:      "missing service",
+			recordType: endpoint.RecordTypeA,
+			refs:       []endpoint.TargetReference{{Kind: "Service", Name: "missing"}, {Kind: "Service", Name: "lb"}},
+			expected:   endpoint.Targets{"1.2.3.4"},
+		},
+		{
+			title:      "no usable address",
+			recordType: endpoint.RecordTypeA,
+			refs:       []endpoint.TargetReference{{Kind: "Service", Namespace: "infra", Name: "elb"}},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			ep := endpoint.NewEndpoint("foo.example.org", tc.recordType, tc.targets...)
+			ep.TargetsFrom = tc.refs
+			require.NoError(t, resolver.resolve(context.Background(), "default", ep))
+			assert.ElementsMatch(t, tc.expected, ep.Targets)
+			assert.Nil(t, ep.TargetsFrom)
+		})
+	}
+
+	ep := endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA)
+	ep.TargetsFrom = []endpoint.TargetReference{{Kind: "Ingress", Name: "foo"}}
+	assert.Error(t, resolver.resolve(context.Background(), "default", ep), "unsupported kinds should fail")
+}
@@ -25,7 +25,9 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/rest/fake"
 
@@ -103,7 +106,7 @@ func fakeRESTClient(endpoints []*endpoint.Endpoint, apiVersion, kind, namespace,
 
 				var body endpoint.DNSEndpoint
 				decoder.Decode(&body)
-				dnsEndpoint.Status.ObservedGeneration = body.Status.ObservedGeneration
+				dnsEndpoint.Status = body.Status
 				return &http.Response{StatusCode: http.StatusOK, Header: defaultHeader(), Body: objBody(codec, dnsEndpoint)}, nil
 			default:
 				return nil, fmt.Errorf("unexpected request: %#v\n%#v", req.URL, req)
@@ -424,7 +427,7 @@ func testCRDSourceEndpoints(t *testing.T) {
 			// So don't start the informer during testing.
 			startInformer := false
 
-			cs, err := NewCRDSource(restClient, ti.namespace, ti.kind, ti.annotationFilter, labelSelector, scheme, startInformer)
+			cs, err := NewCRDSource(restClient, []string{ti.namespace}, ti.kind, ti.annotationFilter, labelSelector, scheme, startInformer, nil, nil, nil)
 			require.NoError(t, err)
 
 			receivedEndpoints, err := cs.Endpoints(context.Background())
@@ -448,6 +451,162 @@ func testCRDSourceEndpoints(t *testing.T) {
 	}
 }
 
+func TestCRDSourceReportSync(t *testing.T) {
+	apiVersion, kind := "test.k8s.io/v1alpha1", "DNSEndpoint"
+	restClient := fakeRESTClient([]*endpoint.Endpoint{
+		{DNSName: "a.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordType: endpoint.RecordTypeA},
+		{DNSName: "b.example.org", RecordType: endpoint.RecordTypeA},
+		{DNSName: "c.example.org", Targets: endpoint.Targets{"1.2.3.5"}, RecordType: endpoint.RecordTypeA},
+	}, apiVersion, kind, "default", "test", nil, nil, t)
+	groupVersion, err := schema.ParseGroupVersion(apiVersion)
+	require.NoError(t, err)
+	scheme := runtime.NewScheme()
+	require.NoError(t, addKnownTypes(scheme, groupVersion))
+
+	cs, err := NewCRDSource(restClient, []string{"default"}, kind, "", labels.Everything(), scheme, false, nil, nil, nil)
+	require.NoError(t, err)
+
+	endpoints, err := cs.Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cs.(SyncReporter).ReportSync(context.Background(), SyncReport{
+		Time: now,
+		Records: []RecordSyncResult{
+			{Endpoint: endpoints[0], Synced: true, Applied: true},
+			{Endpoint: endpoints[1], Error: "throttled"},
+		},
+	})
+
+	result, err := cs.(*crdSource).List(context.Background(), &metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	status := result.Items[0].Status
+	assert.Equal(t, int64(1), status.ObservedGeneration)
+	require.NotNil(t, status.LastAppliedTime)
+	assert.True(t, now.Equal(status.LastAppliedTime.Time))
+	assert.Equal(t, []endpoint.DNSEndpointRecordStatus{
+		{DNSName: "a.example.org", RecordType: endpoint.RecordTypeA, Synced: true},
+		{DNSName: "b.example.org", RecordType: endpoint.RecordTypeA, Error: "empty list of targets"},
+		{DNSName: "c.example.org", RecordType: endpoint.RecordTypeA, Error: "throttled"},
+	}, status.Records)
+	require.Len(t, status.Conditions, 1)
+	assert.Equal(t, endpoint.DNSEndpointConditionReady, status.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionFalse, status.Conditions[0].Status)
+	assert.Equal(t, "NotSynced", status.Conditions[0].Reason)
+	assert.Equal(t, "2 of 3 records are not synced, first: b.example.org A: empty list of targets", status.Conditions[0].Message)
+}
+
+func TestCRDSourceRejectsUnresolvedTargetReferences(t *testing.T) {
+	apiVersion, kind := "test.k8s.io/v1alpha1", "DNSEndpoint"
+	restClient := fakeRESTClient([]*endpoint.Endpoint{
+		{DNSName: "a.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordType: endpoint.RecordTypeA},
+		{DNSName: "b.example.org", RecordType: endpoint.RecordTypeA, TargetsFrom: []endpoint.TargetReference{{Kind: "Service", Name: "lb"}}},
+	}, apiVersion, kind, "default", "test", nil, nil, t)
+	groupVersion, err := schema.ParseGroupVersion(apiVersion)
+	require.NoError(t, err)
+	scheme := runtime.NewScheme()
+	require.NoError(t, addKnownTypes(scheme, groupVersion))
+
+	// without a Kubernetes client, the Service cannot be resolved
+	cs, err := NewCRDSource(restClient, []string{"default"}, kind, "", labels.Everything(), scheme, false, nil, nil, nil)
+	require.NoError(t, err)
+
+	endpoints, err := cs.Endpoints(context.Background())
+	require.NoError(t, err, "the other endpoints should still be published")
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "a.example.org", endpoints[0].DNSName)
+	assert.Equal(t, []endpoint.DNSEndpointRecordStatus{
+		{DNSName: "b.example.org", RecordType: endpoint.RecordTypeA, Error: "resolving target reference service/default/lb: no Kubernetes client"},
+	}, cs.(*crdSource).rejected[types.NamespacedName{Namespace: "default", Name: "test"}])
+}
+
+func TestCRDSourceNamespaces(t *testing.T) {
+	apiVersion, kind := "test.k8s.io/v1alpha1", "DNSEndpoint"
+	groupVersion, err := schema.ParseGroupVersion(apiVersion)
+	require.NoError(t, err)
+	scheme := runtime.NewScheme()
+	require.NoError(t, addKnownTypes(scheme, groupVersion))
+	codecFactory := serializer.WithoutConversionCodecFactory{CodecFactory: serializer.NewCodecFactory(scheme)}
+
+	dnsEndpoint := func(namespace, dnsName string) endpoint.DNSEndpoint {
+		return endpoint.DNSEndpoint{
+			TypeMeta:   metav1.TypeMeta{APIVersion: apiVersion, Kind: kind},
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: namespace},
+			Spec: endpoint.DNSEndpointSpec{Endpoints: []*endpoint.Endpoint{
+				{DNSName: dnsName, Targets: endpoint.Targets{"1.2.3.4"}, RecordType: endpoint.RecordTypeA},
+			}},
+		}
+	}
+	byNamespace := map[string]endpoint.DNSEndpoint{
+		"team-a": dnsEndpoint("team-a", "a.example.org"),
+		"team-b": dnsEndpoint("team-b", "b.example.org"),
+		"team-c": dnsEndpoint("team-c", "c.example.org"),
+	}
+
+	restClient := &fake.RESTClient{
+		GroupVersion:         groupVersion,
+		VersionedAPIPath:     "/apis/" + apiVersion,
+		NegotiatedSerializer: codecFactory,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			namespace, ok := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/apis/"+apiVersion+"/namespaces/"), "/dnsendpoints")
+			if !ok || req.Method != http.MethodGet {
+				return nil, fmt.Errorf("unexpected request: %#v", req.URL)
+			}
+			list := &endpoint.DNSEndpointList{Items: []endpoint.DNSEndpoint{byNamespace[namespace]}}
+			return &http.Response{StatusCode: http.StatusOK, Header: defaultHeader(), Body: objBody(codecFactory.LegacyCodec(groupVersion), list)}, nil
+		}),
+	}
+
+	cs, err := NewCRDSource(restClient, []string{"team-a", "team-b"}, kind, "", labels.Everything(), scheme, false, nil, nil, nil)
+	require.NoError(t, err)
+
+	endpoints, err := cs.Endpoints(context.Background())
+	require.NoError(t, err)
+	var dnsNames []string
+	for _, ep := range endpoints {
+		dnsNames = append(dnsNames, ep.DNSName)
+	}
+	assert.ElementsMatch(t, []string{"a.example.org", "b.example.org"}, dnsNames)
+}
+
+func TestCRDLabelSelector(t *testing.T) {
+	labelFilter, err := labels.Parse("team=dns")
+	require.NoError(t, err)
+	crdLabelFilter, err := labels.Parse("external-dns.io/instance=public")
+	require.NoError(t, err)
+
+	assert.Equal(t, "external-dns.io/instance=public,team=dns", crdLabelSelector(labelFilter, crdLabelFilter).String())
+	assert.Equal(t, "team=dns", crdLabelSelector(labelFilter, nil).String())
+	assert.Equal(t, "external-dns.io/instance=public", crdLabelSelector(labels.Everything(), crdLabelFilter).String())
+}
+
+func TestDNSEndpointSyncStatus(t *testing.T) {
+	dnsEndpoint := &endpoint.DNSEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec: endpoint.DNSEndpointSpec{Endpoints: []*endpoint.Endpoint{
+			{DNSName: "A.example.org.", RecordType: endpoint.RecordTypeA, SetIdentifier: "eu"},
+		}},
+		Status: endpoint.DNSEndpointStatus{LastAppliedTime: &metav1.Time{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+	results := map[endpoint.EndpointKey]RecordSyncResult{
+		{DNSName: "a.example.org", RecordType: endpoint.RecordTypeA, SetIdentifier: "eu"}: {Synced: true},
+	}
+
+	status := dnsEndpointSyncStatus(dnsEndpoint, results, nil, time.Now())
+
+	assert.Equal(t, int64(2), status.ObservedGeneration)
+	assert.Equal(t, dnsEndpoint.Status.LastAppliedTime, status.LastAppliedTime, "records already in sync are not applied")
+	assert.Equal(t, []endpoint.DNSEndpointRecordStatus{
+		{DNSName: "A.example.org.", RecordType: endpoint.RecordTypeA, SetIdentifier: "eu", Synced: true},
+	}, status.Records)
+	require.Len(t, status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionTrue, status.Conditions[0].Status)
+	assert.Equal(t, int64(2), status.Conditions[0].ObservedGeneration)
+	assert.Equal(t, "Synced", status.Conditions[0].Reason)
+}
+
 func validateCRDResource(t *testing.T, src Source, expectError bool) {
 	cs := src.(*crdSource)
 	result, err := cs.List(context.Background(), &metav1.ListOptions{})
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// endpointsCache caches the endpoints computed from each resource by its resourceVersion, so
+// that the endpoints of the resources which didn't change since the previous synchronization
+// are not computed again. It may only be used for endpoints depending on nothing but the
+// resource and the configuration of the source.
+type endpointsCache struct {
+	mu      sync.Mutex
+	entries map[string]endpointsCacheEntry
+}
+
+type endpointsCacheEntry struct {
+	resourceVersion string
+	endpoints       []*endpoint.Endpoint
+}
+
+// endpointsCacheCycle collects the resources used during one computation of the endpoints of a
+// source, the entries of the other resources are removed once it is finished.
+type endpointsCacheCycle struct {
+	cache *endpointsCache
+	seen  map[string]bool
+}
+
+// cycle starts a computation of the endpoints of the source.
+func (c *endpointsCache) cycle() *endpointsCacheCycle {
+	return &endpointsCacheCycle{cache: c, seen: map[string]bool{}}
+}
+
+// endpoints returns copies of the endpoints cached for the resource, computing them when the
+// resource changed. Resources without resourceVersion are never cached.
+func (cc *endpointsCacheCycle) endpoints(obj metav1.Object, compute func() ([]*endpoint.Endpoint, error)) ([]*endpoint.Endpoint, error) {
+	key := obj.GetNamespace() + "/" + obj.GetName()
+	cc.seen[key] = true
+	c := cc.cache
+
+	rv := obj.GetResourceVersion()
+	if rv != "" {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok && entry.resourceVersion == rv {
+			return copyEndpoints(entry.endpoints), nil
+		}
+	}
+
+	endpoints, err := compute()
+	if err != nil || rv == "" {
+		return endpoints, err
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[string]endpointsCacheEntry{}
+	}
+	c.entries[key] = endpointsCacheEntry{resourceVersion: rv, endpoints: copyEndpoints(endpoints)}
+	c.mu.Unlock()
+	return endpoints, nil
+}
+
+// finish removes the entries of the resources which were not used, e.g. deleted ones.
+func (cc *endpointsCacheCycle) finish() {
+	c := cc.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if !cc.seen[key] {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// copyEndpoints returns deep copies of the endpoints, which are modified by later stages.
+func copyEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	copies := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		copies = append(copies, ep.DeepCopy())
+	}
+	return copies
+}
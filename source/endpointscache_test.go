@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestEndpointsCache(t *testing.T) {
+	var cache endpointsCache
+	computed := 0
+	compute := func(name string) func() ([]*endpoint.Endpoint, error) {
+		return func() ([]*endpoint.Endpoint, error) {
+			computed++
+			return []*endpoint.Endpoint{endpoint.NewEndpoint(name, endpoint.RecordTypeA, "1.2.3.4")}, nil
+		}
+	}
+	obj := &metav1.ObjectMeta{Namespace: "default", Name: "foo", ResourceVersion: "1"}
+
+	cycle := cache.cycle()
+	first, err := cycle.endpoints(obj, compute("foo.example.org"))
+	require.NoError(t, err)
+	cycle.finish()
+	assert.Equal(t, 1, computed)
+
+	// unchanged resources are taken from the cache, as copies
+	first[0].Targets = endpoint.Targets{"5.6.7.8"}
+	cycle = cache.cycle()
+	cached, err := cycle.endpoints(obj, compute("foo.example.org"))
+	require.NoError(t, err)
+	cycle.finish()
+	assert.Equal(t, 1, computed)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, cached[0].Targets)
+
+	// changed resources are computed again
+	obj.ResourceVersion = "2"
+	cycle = cache.cycle()
+	changed, err := cycle.endpoints(obj, compute("bar.example.org"))
+	require.NoError(t, err)
+	cycle.finish()
+	assert.Equal(t, 2, computed)
+	assert.Equal(t, "bar.example.org", changed[0].DNSName)
+
+	// resources which are gone are removed
+	cycle = cache.cycle()
+	cycle.finish()
+	assert.Empty(t, cache.entries)
+
+	// resources without resourceVersion are not cached
+	obj.ResourceVersion = ""
+	for i := 0; i < 2; i++ {
+		cycle = cache.cycle()
+		_, err = cycle.endpoints(obj, compute("foo.example.org"))
+		require.NoError(t, err)
+		cycle.finish()
+	}
+	assert.Equal(t, 4, computed)
+}
+
+func TestIngressEndpointsCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := fake.NewSimpleClientset()
+	ingress := &networkv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", ResourceVersion: "1"},
+		Spec:       networkv1.IngressSpec{Rules: []networkv1.IngressRule{{Host: "foo.example.org"}}},
+		Status: networkv1.IngressStatus{LoadBalancer: networkv1.IngressLoadBalancerStatus{
+			Ingress: []networkv1.IngressLoadBalancerIngress{{IP: "1.2.3.4"}},
+		}},
+	}
+	_, err := client.NetworkingV1().Ingresses("default").Create(ctx, ingress, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	src, err := NewIngressSource(ctx, client, "", "", "", false, false, false, false, labels.Everything(), nil, "", "")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		endpoints, err := src.Endpoints(ctx)
+		require.NoError(t, err)
+		validateEndpoints(t, endpoints, []*endpoint.Endpoint{{
+			DNSName:    "foo.example.org",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+			Labels:     endpoint.Labels{endpoint.ResourceLabelKey: "ingress/default/foo"},
+		}})
+	}
+	assert.Len(t, src.(*ingressSource).cache.entries, 1)
+
+	ingress.ResourceVersion = "2"
+	ingress.Spec.Rules[0].Host = "bar.example.org"
+	_, err = client.NetworkingV1().Ingresses("default").Update(ctx, ingress, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		endpoints, err := src.Endpoints(ctx)
+		return err == nil && len(endpoints) == 1 && endpoints[0].DNSName == "bar.example.org"
+	}, 5*time.Second, 10*time.Millisecond)
+}
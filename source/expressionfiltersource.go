@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// expressionFilterSource is a Source that filters the targets of the endpoints of its wrapped source with an
+// expression, a Go template rendered with the endpoint and each of its targets, available as .Target, which renders
+// "true" for the targets to keep. Expressions not using .Target keep or drop all the targets of an endpoint.
+type expressionFilterSource struct {
+	source     Source
+	expression *template.Template
+}
+
+// NewExpressionFilterSource creates a new expressionFilterSource wrapping the provided Source. It returns an error
+// when the expression is not a valid template.
+func NewExpressionFilterSource(source Source, expression string) (Source, error) {
+	tmpl, err := parseTransformTemplate("endpoint-filter", expression)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl == nil {
+		return nil, errors.New("empty endpoint filter")
+	}
+	return &expressionFilterSource{source: source, expression: tmpl}, nil
+}
+
+// Endpoints collects endpoints from its wrapped source and returns them with the targets matching the expression,
+// skipping the endpoints without such targets.
+func (fs *expressionFilterSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := fs.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		data := newTransformData(ep)
+		targets := make(endpoint.Targets, 0, len(ep.Targets))
+		for _, target := range ep.Targets {
+			data.Target = target
+			matched, err := render(fs.expression, data)
+			if err != nil {
+				return nil, fmt.Errorf("endpoint filter of %s %s: %w", ep.RecordType, ep.DNSName, err)
+			}
+			if matched == "true" {
+				targets = append(targets, target)
+			}
+		}
+		if len(targets) == 0 {
+			log.WithField("endpoint", ep).Debugf("Skipping endpoint because all targets were filtered out by the endpoint filter")
+			continue
+		}
+		if len(targets) < len(ep.Targets) {
+			ep = ep.DeepCopy()
+			ep.Targets = targets
+		}
+		result = append(result, ep)
+	}
+	return result, nil
+}
+
+func (fs *expressionFilterSource) AddEventHandler(ctx context.Context, handler func()) {
+	fs.source.AddEventHandler(ctx, handler)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestExpressionFilterSource(t *testing.T) {
+	internal := endpoint.NewEndpoint("internal.example.org", endpoint.RecordTypeA, "1.2.3.4")
+	internal.Labels[endpoint.ResourceLabelKey] = "service/default/internal"
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("mixed.example.org", endpoint.RecordTypeA, "10.0.0.1", "1.2.3.4", "192.168.1.1"),
+		endpoint.NewEndpoint("private.example.org", endpoint.RecordTypeA, "172.16.0.1"),
+		endpoint.NewEndpoint("v6.example.org", endpoint.RecordTypeAAAA, "fd00::1", "2001:db8::1"),
+		endpoint.NewEndpoint("cname.example.org", endpoint.RecordTypeCNAME, "lb.example.net"),
+		internal,
+	}
+	src, err := NewExpressionFilterSource(NewEchoSource(endpoints),
+		`{{ and (not (isPrivateIP .Target)) (not (inNet .Target "2001:db8::/32")) (ne (index .Labels "resource") "service/default/internal") }}`)
+	require.NoError(t, err)
+
+	result, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "mixed.example.org", result[0].DNSName)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, result[0].Targets)
+	assert.Equal(t, "cname.example.org", result[1].DNSName)
+	assert.Equal(t, endpoint.Targets{"lb.example.net"}, result[1].Targets)
+
+	// the endpoints of the wrapped source are left untouched
+	assert.Len(t, endpoints[0].Targets, 3)
+}
+
+func TestExpressionFilterSourceErrors(t *testing.T) {
+	_, err := NewExpressionFilterSource(NewEchoSource(nil), "")
+	assert.Error(t, err)
+
+	_, err = NewExpressionFilterSource(NewEchoSource(nil), "{{ .DNSName")
+	assert.Error(t, err)
+
+	src, err := NewExpressionFilterSource(NewEchoSource([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	}), `{{ inNet .Target "invalid" }}`)
+	require.NoError(t, err)
+	_, err = src.Endpoints(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/external-dns/endpoint"
+
+	f5 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+)
+
+const defaultF5TransportServerNamespace = "transportserver"
+
+func TestF5TransportServerEndpoints(t *testing.T) {
+	t.Parallel()
+
+	newTransportServer := func(annotations map[string]string, spec f5.TransportServerSpec, status f5.TransportServerStatus) f5.TransportServer {
+		return f5.TransportServer{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: f5TransportServerGVR.GroupVersion().String(),
+				Kind:       "TransportServer",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-ts",
+				Namespace:   defaultF5TransportServerNamespace,
+				Annotations: annotations,
+			},
+			Spec:   spec,
+			Status: status,
+		}
+	}
+
+	tests := []struct {
+		name             string
+		annotationFilter string
+		transportServer  f5.TransportServer
+		expected         []*endpoint.Endpoint
+	}{
+		{
+			name: "F5 TransportServer with target annotation",
+			transportServer: newTransportServer(
+				map[string]string{targetAnnotationKey: "192.168.1.150"},
+				f5.TransportServerSpec{Host: "tcp.example.com", VirtualServerAddress: "192.168.1.100"},
+				f5.TransportServerStatus{VSAddress: "192.168.1.200"},
+			),
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "tcp.example.com",
+					Targets:    []string{"192.168.1.150"},
+					RecordType: endpoint.RecordTypeA,
+					Labels:     endpoint.Labels{"resource": "f5-transportserver/transportserver/test-ts"},
+				},
+			},
+		},
+		{
+			name: "F5 TransportServer with host and virtualServerAddress set",
+			transportServer: newTransportServer(
+				nil,
+				f5.TransportServerSpec{Host: "tcp.example.com", VirtualServerAddress: "192.168.1.100"},
+				f5.TransportServerStatus{VSAddress: "192.168.1.200"},
+			),
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "tcp.example.com",
+					Targets:    []string{"192.168.1.100"},
+					RecordType: endpoint.RecordTypeA,
+					Labels:     endpoint.Labels{"resource": "f5-transportserver/transportserver/test-ts"},
+				},
+			},
+		},
+		{
+			name: "F5 TransportServer with host set and IP address from the status field",
+			transportServer: newTransportServer(
+				nil,
+				f5.TransportServerSpec{Host: "tcp.example.com"},
+				f5.TransportServerStatus{VSAddress: "192.168.1.200"},
+			),
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "tcp.example.com",
+					Targets:    []string{"192.168.1.200"},
+					RecordType: endpoint.RecordTypeA,
+					Labels:     endpoint.Labels{"resource": "f5-transportserver/transportserver/test-ts"},
+				},
+			},
+		},
+		{
+			name: "F5 TransportServer with a hostname target",
+			transportServer: newTransportServer(
+				map[string]string{targetAnnotationKey: "bigip.example.com", ttlAnnotationKey: "600"},
+				f5.TransportServerSpec{Host: "tcp.example.com", VirtualServerAddress: "192.168.1.100"},
+				f5.TransportServerStatus{},
+			),
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "tcp.example.com",
+					Targets:    []string{"bigip.example.com"},
+					RecordType: endpoint.RecordTypeCNAME,
+					RecordTTL:  600,
+					Labels:     endpoint.Labels{"resource": "f5-transportserver/transportserver/test-ts"},
+				},
+			},
+		},
+		{
+			name: "F5 TransportServer without host",
+			transportServer: newTransportServer(
+				nil,
+				f5.TransportServerSpec{VirtualServerAddress: "192.168.1.100"},
+				f5.TransportServerStatus{},
+			),
+			expected: nil,
+		},
+		{
+			name:             "F5 TransportServer with non-matching annotation filter",
+			annotationFilter: "foo=bar",
+			transportServer: newTransportServer(
+				map[string]string{"foo": "baz"},
+				f5.TransportServerSpec{Host: "tcp.example.com", VirtualServerAddress: "192.168.1.100"},
+				f5.TransportServerStatus{},
+			),
+			expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fakeKubernetesClient := fakeKube.NewSimpleClientset()
+			scheme := runtime.NewScheme()
+			scheme.AddKnownTypes(f5TransportServerGVR.GroupVersion(), &f5.TransportServer{}, &f5.TransportServerList{})
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(scheme)
+
+			transportServer := unstructured.Unstructured{}
+
+			transportServerJSON, err := json.Marshal(tc.transportServer)
+			require.NoError(t, err)
+			assert.NoError(t, transportServer.UnmarshalJSON(transportServerJSON))
+
+			_, err = fakeDynamicClient.Resource(f5TransportServerGVR).Namespace(defaultF5TransportServerNamespace).Create(context.Background(), &transportServer, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			source, err := NewF5TransportServerSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultF5TransportServerNamespace, tc.annotationFilter)
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, endpoints)
+		})
+	}
+}
@@ -29,7 +29,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -63,7 +62,7 @@ func NewF5VirtualServerSource(
 	namespace string,
 	annotationFilter string,
 ) (Source, error) {
-	informerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicKubeClient, 0, namespace, nil)
+	informerFactory := sharedDynamicInformerFactory(ctx, dynamicKubeClient, namespace)
 	virtualServerInformer := informerFactory.ForResource(f5VirtualServerGVR)
 
 	virtualServerInformer.Informer().AddEventHandler(
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// fileSourcePollInterval is how often the file source looks for changes to notify the event handlers of.
+const fileSourcePollInterval = 10 * time.Second
+
+// fileSource is a Source reading static endpoints from a YAML or JSON file, e.g. mounted from a ConfigMap, or from
+// the data of a ConfigMap. Every file, or every key of the ConfigMap, holds either a list of endpoints or a
+// DNSEndpoint spec, i.e. the list under endpoints, e.g.
+//
+//   - dnsName: www.example.org
+//     recordType: CNAME
+//     targets: [app.example.org]
+type fileSource struct {
+	// resource is the value of the resource label of the endpoints
+	resource     string
+	read         func(ctx context.Context) (map[string]string, error)
+	pollInterval time.Duration
+}
+
+// NewFileSource creates a new fileSource reading the endpoints from the file.
+func NewFileSource(path string) Source {
+	return &fileSource{
+		resource: "file/" + filepath.Base(path),
+		read: func(context.Context) (map[string]string, error) {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]string{path: string(content)}, nil
+		},
+		pollInterval: fileSourcePollInterval,
+	}
+}
+
+// NewConfigMapFileSource creates a new fileSource reading the endpoints from every key of the ConfigMap, given as
+// <namespace>/<name>.
+func NewConfigMapFileSource(kubeClient kubernetes.Interface, configMap string) (Source, error) {
+	namespace, name, found := strings.Cut(configMap, "/")
+	if !found || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid ConfigMap %q, expected <namespace>/<name>", configMap)
+	}
+	return &fileSource{
+		resource: fmt.Sprintf("configmap/%s/%s", namespace, name),
+		read: func(ctx context.Context) (map[string]string, error) {
+			cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return cm.Data, nil
+		},
+		pollInterval: fileSourcePollInterval,
+	}, nil
+}
+
+// Endpoints returns the endpoints of the file, skipping the invalid ones.
+func (fs *fileSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	files, err := fs.read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the endpoints of %s: %w", fs.resource, err)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	endpoints := []*endpoint.Endpoint{}
+	for _, name := range names {
+		parsed, err := parseStaticEndpoints([]byte(files[name]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the endpoints of %s: %w", name, err)
+		}
+		endpoints = append(endpoints, staticEndpoints(parsed, fs.resource)...)
+	}
+	return endpoints, nil
+}
+
+// AddEventHandler calls the handler whenever the content of the file changes.
+func (fs *fileSource) AddEventHandler(ctx context.Context, handler func()) {
+	last, _ := fs.read(ctx)
+	go func() {
+		ticker := time.NewTicker(fs.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			current, err := fs.read(ctx)
+			if err != nil {
+				log.Debugf("Failed to read the endpoints of %s: %v", fs.resource, err)
+				continue
+			}
+			if !reflect.DeepEqual(current, last) {
+				last = current
+				handler()
+			}
+		}
+	}()
+}
+
+// staticEndpoints returns the valid endpoints read from the resource, labeled with it.
+func staticEndpoints(endpoints []*endpoint.Endpoint, resource string) []*endpoint.Endpoint {
+	valid := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if len(ep.TargetsFrom) > 0 {
+			log.Warnf("Endpoint %s of %s uses targetsFrom, which is only supported by DNSEndpoints", ep.DNSName, resource)
+			continue
+		}
+		if err := validateStaticEndpoint(ep); err != nil {
+			log.Warnf("Endpoint %s of %s is invalid: %v", ep.DNSName, resource, err)
+			continue
+		}
+		if ep.Labels == nil {
+			ep.Labels = endpoint.NewLabels()
+		}
+		ep.Labels[endpoint.ResourceLabelKey] = resource
+		valid = append(valid, ep)
+	}
+	return valid
+}
+
+// parseStaticEndpoints parses a YAML or JSON list of endpoints, or a DNSEndpoint spec.
+func parseStaticEndpoints(content []byte) ([]*endpoint.Endpoint, error) {
+	content, err := utilyaml.ToJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 || bytes.Equal(content, []byte("null")) {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if content[0] == '[' {
+		var endpoints []*endpoint.Endpoint
+		err = decoder.Decode(&endpoints)
+		return endpoints, err
+	}
+	var spec endpoint.DNSEndpointSpec
+	err = decoder.Decode(&spec)
+	return spec.Endpoints, err
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestParseStaticEndpoints(t *testing.T) {
+	for _, tc := range []struct {
+		title    string
+		content  string
+		expected []*endpoint.Endpoint
+		err      bool
+	}{
+		{
+			title:   "YAML list",
+			content: "- dnsName: www.example.org\n  recordType: CNAME\n  targets: [app.example.org]\n  recordTTL: 300\n",
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("www.example.org", endpoint.RecordTypeCNAME, 300, "app.example.org"),
+			},
+		},
+		{
+			title:   "DNSEndpoint spec",
+			content: "endpoints:\n- dnsName: api.example.org\n  recordType: A\n  targets: [1.2.3.4]\n",
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("api.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+			},
+		},
+		{
+			title:   "JSON list",
+			content: `[{"dnsName": "api.example.org", "recordType": "A", "targets": ["1.2.3.4"]}]`,
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("api.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+			},
+		},
+		{
+			title:   "empty",
+			content: "\n",
+		},
+		{
+			title:   "unknown field",
+			content: "- dnsName: www.example.org\n  recordTyp: CNAME\n",
+			err:     true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			endpoints, err := parseStaticEndpoints([]byte(tc.content))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, ep := range endpoints {
+				// NewEndpoint sets empty labels, the parsed endpoints don't have any
+				ep.Labels = endpoint.NewLabels()
+			}
+			assert.Equal(t, tc.expected, endpoints)
+		})
+	}
+}
+
+func TestFileSourceEndpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+- dnsName: www.example.org
+  recordType: CNAME
+  targets: [app.example.org]
+- dnsName: empty.example.org
+  recordType: A
+- dnsName: ref.example.org
+  recordType: A
+  targetsFrom:
+  - kind: Service
+    name: app
+`), 0o600))
+
+	endpoints, err := NewFileSource(path).Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "www.example.org", endpoints[0].DNSName)
+	assert.Equal(t, "file/endpoints.yaml", endpoints[0].Labels[endpoint.ResourceLabelKey])
+
+	_, err = NewFileSource(path + ".missing").Endpoints(context.Background())
+	assert.Error(t, err)
+}
+
+func TestConfigMapFileSourceEndpoints(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "external-dns", Name: "endpoints"},
+		Data: map[string]string{
+			"b.yaml": "- dnsName: b.example.org\n  recordType: A\n  targets: [1.2.3.4]\n",
+			"a.json": `{"endpoints": [{"dnsName": "a.example.org", "recordType": "A", "targets": ["5.6.7.8"]}]}`,
+		},
+	})
+
+	src, err := NewConfigMapFileSource(client, "external-dns/endpoints")
+	require.NoError(t, err)
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, "a.example.org", endpoints[0].DNSName)
+	assert.Equal(t, "b.example.org", endpoints[1].DNSName)
+	assert.Equal(t, "configmap/external-dns/endpoints", endpoints[1].Labels[endpoint.ResourceLabelKey])
+
+	_, err = NewConfigMapFileSource(client, "endpoints")
+	assert.Error(t, err)
+}
+
+func TestFileSourceAddEventHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("[]"), 0o600))
+	src := NewFileSource(path).(*fileSource)
+	src.pollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan struct{}, 10)
+	src.AddEventHandler(ctx, func() { changes <- struct{}{} })
+
+	require.NoError(t, os.WriteFile(path, []byte("- dnsName: www.example.org\n  recordType: A\n  targets: [1.2.3.4]\n"), 0o600))
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the handler was not called when the file changed")
+	}
+}
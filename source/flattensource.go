@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// flattenSource is a Source that replaces CNAME endpoints pointing at other endpoints of its
+// wrapped source, e.g. chains of ExternalName services, with the A and AAAA targets they resolve to.
+type flattenSource struct {
+	source Source
+}
+
+// NewFlattenSource creates a new flattenSource wrapping the provided Source.
+func NewFlattenSource(source Source) Source {
+	return &flattenSource{source: source}
+}
+
+// Endpoints collects endpoints from its wrapped source and flattens the CNAME endpoints
+// whose targets all resolve to A or AAAA endpoints of the same source.
+// CNAME endpoints pointing at names outside of the source are returned unchanged.
+func (fs *flattenSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := fs.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string][]*endpoint.Endpoint{}
+	for _, ep := range endpoints {
+		switch ep.RecordType {
+		case endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME:
+			name := flattenKey(ep.DNSName)
+			byName[name] = append(byName[name], ep)
+		}
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if ep.RecordType != endpoint.RecordTypeCNAME {
+			result = append(result, ep)
+			continue
+		}
+		addresses := map[string]endpoint.Targets{}
+		err := resolveCNAME(byName, ep.Targets, map[string]bool{flattenKey(ep.DNSName): true}, addresses)
+		if err != nil {
+			log.WithField("endpoint", ep).Debugf("Not flattening CNAME: %v", err)
+			result = append(result, ep)
+			continue
+		}
+		for _, recordType := range []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA} {
+			if len(addresses[recordType]) == 0 {
+				continue
+			}
+			flattened := ep.DeepCopy()
+			flattened.RecordType = recordType
+			flattened.Targets = addresses[recordType]
+			result = append(result, flattened)
+		}
+		log.Debugf("Flattened CNAME %s to A %s and AAAA %s", ep.DNSName, addresses[endpoint.RecordTypeA], addresses[endpoint.RecordTypeAAAA])
+	}
+	return result, nil
+}
+
+func (fs *flattenSource) AddEventHandler(ctx context.Context, handler func()) {
+	fs.source.AddEventHandler(ctx, handler)
+}
+
+// resolveCNAME follows the targets through the endpoints indexed by name and collects the
+// addresses they resolve to by record type. It fails if a target is not an endpoint or on cycles.
+func resolveCNAME(byName map[string][]*endpoint.Endpoint, targets endpoint.Targets, visited map[string]bool, addresses map[string]endpoint.Targets) error {
+	for _, target := range targets {
+		name := flattenKey(target)
+		if visited[name] {
+			return fmt.Errorf("CNAME loop through %s", target)
+		}
+		resolved := byName[name]
+		if len(resolved) == 0 {
+			return fmt.Errorf("target %s is not managed", target)
+		}
+		visited[name] = true
+		for _, ep := range resolved {
+			if ep.RecordType == endpoint.RecordTypeCNAME {
+				if err := resolveCNAME(byName, ep.Targets, visited, addresses); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, address := range ep.Targets {
+				if !slices.Contains(addresses[ep.RecordType], address) {
+					addresses[ep.RecordType] = append(addresses[ep.RecordType], address)
+				}
+			}
+		}
+		delete(visited, name)
+	}
+	return nil
+}
+
+func flattenKey(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
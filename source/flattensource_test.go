@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestFlattenSource(t *testing.T) {
+	for _, tc := range []struct {
+		title    string
+		input    []*endpoint.Endpoint
+		expected []*endpoint.Endpoint
+	}{
+		{
+			title: "chain of managed records is flattened",
+			input: []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeCNAME, 300, "www.example.org"),
+				endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "LB.example.org."),
+				endpoint.NewEndpoint("lb.example.org", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8"),
+				endpoint.NewEndpoint("lb.example.org", endpoint.RecordTypeAAAA, "2001:db8::1"),
+			},
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeA, 300, "1.2.3.4", "5.6.7.8"),
+				endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeAAAA, 300, "2001:db8::1"),
+				endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8"),
+				endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeAAAA, "2001:db8::1"),
+				endpoint.NewEndpoint("lb.example.org", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8"),
+				endpoint.NewEndpoint("lb.example.org", endpoint.RecordTypeAAAA, "2001:db8::1"),
+			},
+		},
+		{
+			title: "targets are merged across multiple CNAME targets",
+			input: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("example.org", endpoint.RecordTypeCNAME, "a.example.org", "b.example.org"),
+				endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+				endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8"),
+			},
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("example.org", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8"),
+				endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+				endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "1.2.3.4", "5.6.7.8"),
+			},
+		},
+		{
+			title: "CNAME to an unmanaged name is kept",
+			input: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("example.org", endpoint.RecordTypeCNAME, "a.example.org", "elb.amazonaws.com"),
+				endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+			},
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("example.org", endpoint.RecordTypeCNAME, "a.example.org", "elb.amazonaws.com"),
+				endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+			},
+		},
+		{
+			title: "CNAME loop is kept",
+			input: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeCNAME, "b.example.org"),
+				endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeCNAME, "a.example.org"),
+			},
+			expected: []*endpoint.Endpoint{
+				endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeCNAME, "b.example.org"),
+				endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeCNAME, "a.example.org"),
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			endpoints, err := NewFlattenSource(NewEchoSource(tc.input)).Endpoints(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, endpoints)
+		})
+	}
+}
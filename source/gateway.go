@@ -29,7 +29,6 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
-	kubeinformers "k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	cache "k8s.io/client-go/tools/cache"
 	v1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -91,6 +90,8 @@ type gatewayRouteSource struct {
 	rtInformer    gatewayRouteInformer
 
 	nsInformer coreinformers.NamespaceInformer
+	// svcInformer watches the Services generated by Envoy Gateway, when their addresses are used.
+	svcInformer coreinformers.ServiceInformer
 
 	fqdnTemplate             *template.Template
 	combineFQDNAnnotation    bool
@@ -98,7 +99,7 @@ type gatewayRouteSource struct {
 }
 
 func newGatewayRouteSource(clients ClientGenerator, config *Config, kind string, newInformerFn newGatewayRouteInformerFunc) (Source, error) {
-	ctx := context.TODO()
+	ctx := WithInformerFactories(context.TODO(), config.InformerFactories)
 
 	gwLabels, err := getLabelSelector(config.GatewayLabelFilter)
 	if err != nil {
@@ -138,9 +139,14 @@ func newGatewayRouteSource(clients ClientGenerator, config *Config, kind string,
 		return nil, err
 	}
 
-	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, 0)
-	nsInformer := kubeInformerFactory.Core().V1().Namespaces() // TODO: Namespace informer should be shared across gateway sources.
-	nsInformer.Informer()                                      // Register with factory before starting.
+	kubeInformerFactory := sharedKubeInformerFactory(ctx, kubeClient, "")
+	nsInformer := kubeInformerFactory.Core().V1().Namespaces()
+	nsInformer.Informer() // Register with factory before starting.
+	var svcInformer coreinformers.ServiceInformer
+	if config.GatewayEnvoyServices {
+		svcInformer = kubeInformerFactory.Core().V1().Services()
+		svcInformer.Informer() // Register with factory before starting.
+	}
 
 	informerFactory.Start(wait.NeverStop)
 	kubeInformerFactory.Start(wait.NeverStop)
@@ -169,7 +175,8 @@ func newGatewayRouteSource(clients ClientGenerator, config *Config, kind string,
 		rtAnnotations: rtAnnotations,
 		rtInformer:    rtInformer,
 
-		nsInformer: nsInformer,
+		nsInformer:  nsInformer,
+		svcInformer: svcInformer,
 
 		fqdnTemplate:             tmpl,
 		combineFQDNAnnotation:    config.CombineFQDNAndAnnotation,
@@ -184,6 +191,9 @@ func (src *gatewayRouteSource) AddEventHandler(ctx context.Context, handler func
 	src.gwInformer.Informer().AddEventHandler(eventHandler)
 	src.rtInformer.Informer().AddEventHandler(eventHandler)
 	src.nsInformer.Informer().AddEventHandler(eventHandler)
+	if src.svcInformer != nil {
+		src.svcInformer.Informer().AddEventHandler(eventHandler)
+	}
 }
 
 func (src *gatewayRouteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
@@ -200,8 +210,15 @@ func (src *gatewayRouteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpo
 	if err != nil {
 		return nil, err
 	}
+	var envoyTargets map[types.NamespacedName]endpoint.Targets
+	if src.svcInformer != nil {
+		envoyTargets, err = envoyGatewayServiceTargets(src.svcInformer.Lister())
+		if err != nil {
+			return nil, err
+		}
+	}
 	kind := strings.ToLower(src.rtKind)
-	resolver := newGatewayRouteResolver(src, gateways, namespaces)
+	resolver := newGatewayRouteResolver(src, gateways, namespaces, envoyTargets)
 	for _, rt := range routes {
 		// Filter by annotations.
 		meta := rt.Metadata()
@@ -218,7 +235,7 @@ func (src *gatewayRouteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpo
 		}
 
 		// Get Route hostnames and their targets.
-		hostTargets, err := resolver.resolve(rt)
+		hostTargets, hostTTLs, err := resolver.resolve(rt)
 		if err != nil {
 			return nil, err
 		}
@@ -232,7 +249,13 @@ func (src *gatewayRouteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpo
 		providerSpecific, setIdentifier := getProviderSpecificAnnotations(annots)
 		ttl := getTTLFromAnnotations(annots, resource)
 		for host, targets := range hostTargets {
-			endpoints = append(endpoints, endpointsForHostname(host, targets, ttl, providerSpecific, setIdentifier, resource)...)
+			hostTTL := ttl
+			if !hostTTL.IsConfigured() {
+				hostTTL = hostTTLs[host]
+			}
+			hostEndpoints := withRecordType(annots, withWildcards(annots, endpointsForHostname(host, targets, hostTTL, providerSpecific, setIdentifier, resource)))
+			setCommitLabel(annots, hostEndpoints)
+			endpoints = append(endpoints, hostEndpoints...)
 		}
 		log.Debugf("Endpoints generated from %s %s/%s: %v", src.rtKind, meta.Namespace, meta.Name, endpoints)
 	}
@@ -252,20 +275,47 @@ type gatewayRouteResolver struct {
 type gatewayListeners struct {
 	gateway   *v1.Gateway
 	listeners map[v1.SectionName][]v1.Listener
+	// annotations of the Gateway, including its infrastructure annotations.
+	annotations map[string]string
+	ttl         endpoint.TTL
+	targets     endpoint.Targets
 }
 
-func newGatewayRouteResolver(src *gatewayRouteSource, gateways []*v1.Gateway, namespaces []*corev1.Namespace) *gatewayRouteResolver {
+// newGatewayRouteResolver creates a resolver of the Routes attached to the gateways. The targets of a Gateway are the
+// ones of its target annotation, or the addresses of its Services generated by Envoy Gateway in envoyTargets, or
+// its status addresses.
+func newGatewayRouteResolver(src *gatewayRouteSource, gateways []*v1.Gateway, namespaces []*corev1.Namespace, envoyTargets map[types.NamespacedName]endpoint.Targets) *gatewayRouteResolver {
 	// Create Gateway Listener lookup table.
 	gws := make(map[types.NamespacedName]gatewayListeners, len(gateways))
 	for _, gw := range gateways {
-		lss := make(map[v1.SectionName][]v1.Listener, len(gw.Spec.Listeners)+1)
-		for i, lis := range gw.Spec.Listeners {
-			lss[lis.Name] = gw.Spec.Listeners[i : i+1]
+		annots := gwAnnotations(gw)
+		// Check controller annotation to see if we are responsible for the Routes of the Gateway.
+		if v, ok := annots[controllerAnnotationKey]; ok && v != controllerAnnotationValue {
+			log.Debugf("Skipping Gateway %s/%s because controller value does not match, found: %s, required: %s",
+				gw.Namespace, gw.Name, v, controllerAnnotationValue)
+			continue
+		}
+		listeners := gwAcceptedListeners(gw)
+		lss := make(map[v1.SectionName][]v1.Listener, len(listeners)+1)
+		for i, lis := range listeners {
+			lss[lis.Name] = listeners[i : i+1]
+		}
+		lss[""] = listeners
+		targets := getTargetsFromTargetAnnotation(annots)
+		if len(targets) == 0 {
+			targets = envoyTargets[namespacedName(gw.Namespace, gw.Name)]
+		}
+		if len(targets) == 0 {
+			for _, addr := range gw.Status.Addresses {
+				targets = append(targets, addr.Value)
+			}
 		}
-		lss[""] = gw.Spec.Listeners
 		gws[namespacedName(gw.Namespace, gw.Name)] = gatewayListeners{
-			gateway:   gw,
-			listeners: lss,
+			gateway:     gw,
+			listeners:   lss,
+			annotations: annots,
+			ttl:         getTTLFromAnnotations(annots, fmt.Sprintf("gateway/%s/%s", gw.Namespace, gw.Name)),
+			targets:     targets,
 		}
 	}
 	// Create Namespace lookup table.
@@ -280,12 +330,15 @@ func newGatewayRouteResolver(src *gatewayRouteSource, gateways []*v1.Gateway, na
 	}
 }
 
-func (c *gatewayRouteResolver) resolve(rt gatewayRoute) (map[string]endpoint.Targets, error) {
+// resolve returns the targets of the hostnames of the Route, along with the TTLs set by the annotations of their
+// Gateways.
+func (c *gatewayRouteResolver) resolve(rt gatewayRoute) (map[string]endpoint.Targets, map[string]endpoint.TTL, error) {
 	rtHosts, err := c.hosts(rt)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	hostTargets := make(map[string]endpoint.Targets)
+	hostTTLs := make(map[string]endpoint.TTL)
 
 	meta := rt.Metadata()
 	for _, rps := range rt.RouteStatus().Parents {
@@ -345,12 +398,9 @@ func (c *gatewayRouteResolver) resolve(rt gatewayRoute) (map[string]endpoint.Tar
 				if !ok {
 					continue
 				}
-				override := getTargetsFromTargetAnnotation(gw.gateway.Annotations)
-				hostTargets[host] = append(hostTargets[host], override...)
-				if len(override) == 0 {
-					for _, addr := range gw.gateway.Status.Addresses {
-						hostTargets[host] = append(hostTargets[host], addr.Value)
-					}
+				hostTargets[host] = append(hostTargets[host], gw.targets...)
+				if gw.ttl.IsConfigured() && !hostTTLs[host].IsConfigured() {
+					hostTTLs[host] = gw.ttl
 				}
 				match = true
 			}
@@ -364,7 +414,7 @@ func (c *gatewayRouteResolver) resolve(rt gatewayRoute) (map[string]endpoint.Tar
 	for host, targets := range hostTargets {
 		hostTargets[host] = uniqueTargets(targets)
 	}
-	return hostTargets, nil
+	return hostTargets, hostTTLs, nil
 }
 
 func (c *gatewayRouteResolver) hosts(rt gatewayRoute) ([]string, error) {
@@ -446,6 +496,47 @@ func (c *gatewayRouteResolver) routeIsAllowed(gw *v1.Gateway, lis *v1.Listener,
 	return false
 }
 
+// gwAnnotations returns the annotations of the Gateway merged with the annotations of its spec.infrastructure,
+// which apply to all its Routes. The annotations of the Gateway take precedence.
+func gwAnnotations(gw *v1.Gateway) map[string]string {
+	if gw.Spec.Infrastructure == nil || len(gw.Spec.Infrastructure.Annotations) == 0 {
+		return gw.Annotations
+	}
+	annots := make(map[string]string, len(gw.Annotations)+len(gw.Spec.Infrastructure.Annotations))
+	for k, v := range gw.Spec.Infrastructure.Annotations {
+		annots[string(k)] = string(v)
+	}
+	for k, v := range gw.Annotations {
+		annots[k] = v
+	}
+	return annots
+}
+
+// gwAcceptedListeners returns the Listeners of the Gateway, except those its status reports as not accepted.
+// Listeners without status are kept, since not every implementation reports the status of each Listener.
+func gwAcceptedListeners(gw *v1.Gateway) []v1.Listener {
+	rejected := make(map[v1.SectionName]bool)
+	for _, status := range gw.Status.Listeners {
+		for _, c := range status.Conditions {
+			if v1.ListenerConditionType(c.Type) == v1.ListenerConditionAccepted && c.Status == metav1.ConditionFalse {
+				rejected[status.Name] = true
+			}
+		}
+	}
+	if len(rejected) == 0 {
+		return gw.Spec.Listeners
+	}
+	var listeners []v1.Listener
+	for _, lis := range gw.Spec.Listeners {
+		if rejected[lis.Name] {
+			log.Debugf("Gateway %s/%s section %q is not accepted", gw.Namespace, gw.Name, lis.Name)
+			continue
+		}
+		listeners = append(listeners, lis)
+	}
+	return listeners
+}
+
 func gwRouteIsAccepted(conds []metav1.Condition) bool {
 	for _, c := range conds {
 		if v1.RouteConditionType(c.Type) == v1.RouteConditionAccepted {
@@ -487,38 +578,26 @@ func gwProtocolMatches(a, b v1.ProtocolType) bool {
 
 // gwMatchingHost returns the most-specific overlapping host and a bool indicating if one was found.
 // For example, if one host is "*.foo.com" and the other is "bar.foo.com", "bar.foo.com" will be returned.
-// An empty string matches anything.
+// As in the Gateway API, a wildcard matches one or more labels, so "*.foo.com" also matches "baz.bar.foo.com",
+// but not "foo.com". An empty string matches anything.
 func gwMatchingHost(gwHost, rtHost string) (string, bool) {
 	gwHost = toLowerCaseASCII(gwHost) // TODO: trim "." suffix?
 	rtHost = toLowerCaseASCII(rtHost) // TODO: trim "." suffix?
 
-	if gwHost == "" {
+	switch {
+	case gwHost == "":
 		return rtHost, true
-	}
-	if rtHost == "" {
+	case rtHost == "":
 		return gwHost, true
-	}
-
-	gwParts := strings.Split(gwHost, ".")
-	rtParts := strings.Split(rtHost, ".")
-	if len(gwParts) != len(rtParts) {
+	case gwHost == rtHost:
+		return rtHost, true
+	case strings.HasPrefix(gwHost, "*.") && strings.HasSuffix(rtHost, gwHost[1:]):
+		return rtHost, true // rtHost is more specific
+	case strings.HasPrefix(rtHost, "*.") && strings.HasSuffix(gwHost, rtHost[1:]):
+		return gwHost, true // gwHost is more specific
+	default:
 		return "", false
 	}
-
-	host := rtHost
-	for i, gwPart := range gwParts {
-		switch rtPart := rtParts[i]; {
-		case rtPart == gwPart:
-			// continue
-		case i == 0 && gwPart == "*":
-			// continue
-		case i == 0 && rtPart == "*":
-			host = gwHost // gwHost is more specific
-		default:
-			return "", false
-		}
-	}
-	return host, true
 }
 
 func strVal(ptr *string, def string) string {
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+const (
+	// The labels Envoy Gateway sets on the Services of the Envoy proxies of a Gateway.
+	envoyGatewayOwningGatewayNameLabel      = "gateway.envoyproxy.io/owning-gateway-name"
+	envoyGatewayOwningGatewayNamespaceLabel = "gateway.envoyproxy.io/owning-gateway-namespace"
+)
+
+// envoyGatewayServiceTargets returns the addresses of the Services Envoy Gateway generated for the Envoy proxies of
+// the Gateways, by Gateway: the load balancer addresses and the external IPs, which the custom addresses of the
+// Gateways or of their EnvoyProxy configurations end up in.
+func envoyGatewayServiceTargets(lister corelisters.ServiceLister) (map[types.NamespacedName]endpoint.Targets, error) {
+	owned, err := labels.NewRequirement(envoyGatewayOwningGatewayNameLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	services, err := lister.List(labels.NewSelector().Add(*owned))
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[types.NamespacedName]endpoint.Targets)
+	for _, svc := range services {
+		gw := namespacedName(svc.Labels[envoyGatewayOwningGatewayNamespaceLabel], svc.Labels[envoyGatewayOwningGatewayNameLabel])
+		targets[gw] = append(targets[gw], envoyServiceAddresses(svc)...)
+	}
+	return targets, nil
+}
+
+func envoyServiceAddresses(svc *corev1.Service) endpoint.Targets {
+	var targets endpoint.Targets
+	for _, lb := range svc.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			targets = append(targets, lb.IP)
+		}
+		if lb.Hostname != "" {
+			targets = append(targets, lb.Hostname)
+		}
+	}
+	targets = append(targets, svc.Spec.ExternalIPs...)
+	return targets
+}
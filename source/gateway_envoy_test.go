@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/external-dns/endpoint"
+	v1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+)
+
+func TestGatewayHTTPRouteSourceEnvoyServices(t *testing.T) {
+	t.Parallel()
+
+	envoyService := func(name, gwNamespace, gwName string, lb []corev1.LoadBalancerIngress, externalIPs ...string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "envoy-gateway-system",
+				Labels: map[string]string{
+					envoyGatewayOwningGatewayNameLabel:      gwName,
+					envoyGatewayOwningGatewayNamespaceLabel: gwNamespace,
+				},
+			},
+			Spec:   corev1.ServiceSpec{ExternalIPs: externalIPs},
+			Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: lb}},
+		}
+	}
+	gateway := func(name string, annotations map[string]string, addresses ...string) *v1.Gateway {
+		return &v1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec:       v1.GatewaySpec{Listeners: []v1.Listener{{Protocol: v1.HTTPProtocolType}}},
+			Status:     gatewayStatus(addresses...),
+		}
+	}
+	route := func(name string, hostname v1.Hostname, gwName string) *v1.HTTPRoute {
+		return &v1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1.HTTPRouteSpec{Hostnames: []v1.Hostname{hostname}},
+			Status:     httpRouteStatus(gatewayParentRef("default", gwName)),
+		}
+	}
+
+	gateways := []*v1.Gateway{
+		gateway("load-balancer", nil, "10.0.0.1"),
+		gateway("external-ips", nil, "10.0.0.2"),
+		gateway("annotated", map[string]string{targetAnnotationKey: "4.3.2.1"}, "10.0.0.3"),
+		gateway("no-service", nil, "10.0.0.4"),
+	}
+	routes := []*v1.HTTPRoute{
+		route("load-balancer", "load-balancer.example.internal", "load-balancer"),
+		route("external-ips", "external-ips.example.internal", "external-ips"),
+		route("annotated", "annotated.example.internal", "annotated"),
+		route("no-service", "no-service.example.internal", "no-service"),
+	}
+	services := []*corev1.Service{
+		envoyService("envoy-default-load-balancer", "default", "load-balancer", []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}, {Hostname: "lb.example.com"}}),
+		envoyService("envoy-default-external-ips", "default", "external-ips", nil, "2.3.4.5"),
+		envoyService("envoy-default-annotated", "default", "annotated", []corev1.LoadBalancerIngress{{IP: "3.4.5.6"}}),
+		{ObjectMeta: metav1.ObjectMeta{Name: "not-envoy", Namespace: "default"}, Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "9.9.9.9"}}}}},
+	}
+
+	for _, tt := range []struct {
+		title     string
+		config    Config
+		endpoints []*endpoint.Endpoint
+	}{
+		{
+			title:  "Disabled",
+			config: Config{},
+			endpoints: []*endpoint.Endpoint{
+				newTestEndpoint("load-balancer.example.internal", "A", "10.0.0.1"),
+				newTestEndpoint("external-ips.example.internal", "A", "10.0.0.2"),
+				newTestEndpoint("annotated.example.internal", "A", "4.3.2.1"),
+				newTestEndpoint("no-service.example.internal", "A", "10.0.0.4"),
+			},
+		},
+		{
+			title:  "Enabled",
+			config: Config{GatewayEnvoyServices: true},
+			endpoints: []*endpoint.Endpoint{
+				newTestEndpoint("load-balancer.example.internal", "A", "1.2.3.4"),
+				newTestEndpoint("load-balancer.example.internal", "CNAME", "lb.example.com"),
+				newTestEndpoint("external-ips.example.internal", "A", "2.3.4.5"),
+				newTestEndpoint("annotated.example.internal", "A", "4.3.2.1"),
+				newTestEndpoint("no-service.example.internal", "A", "10.0.0.4"),
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			gwClient := gatewayfake.NewSimpleClientset()
+			for _, gw := range gateways {
+				_, err := gwClient.GatewayV1().Gateways(gw.Namespace).Create(ctx, gw, metav1.CreateOptions{})
+				require.NoError(t, err, "failed to create Gateway")
+			}
+			for _, rt := range routes {
+				_, err := gwClient.GatewayV1().HTTPRoutes(rt.Namespace).Create(ctx, rt, metav1.CreateOptions{})
+				require.NoError(t, err, "failed to create HTTPRoute")
+			}
+			kubeClient := kubefake.NewSimpleClientset()
+			_, err := kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, metav1.CreateOptions{})
+			require.NoError(t, err, "failed to create Namespace")
+			for _, svc := range services {
+				_, err := kubeClient.CoreV1().Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+				require.NoError(t, err, "failed to create Service")
+			}
+
+			clients := new(MockClientGenerator)
+			clients.On("GatewayClient").Return(gwClient, nil)
+			clients.On("KubeClient").Return(kubeClient, nil)
+
+			src, err := NewGatewayHTTPRouteSource(clients, &tt.config)
+			require.NoError(t, err, "failed to create Gateway HTTPRoute Source")
+
+			endpoints, err := src.Endpoints(ctx)
+			require.NoError(t, err, "failed to get Endpoints")
+			validateEndpoints(t, endpoints, tt.endpoints)
+		})
+	}
+}
@@ -598,6 +598,87 @@ func TestGatewayHTTPRouteSourceEndpoints(t *testing.T) {
 				newTestEndpoint("*.example.internal", "A", "1.2.3.4"),
 			},
 		},
+		{
+			title:      "WildcardInGatewayMatchesSubdomains",
+			config:     Config{},
+			namespaces: namespaces("default"),
+			gateways: []*v1.Gateway{{
+				ObjectMeta: objectMeta("default", "test"),
+				Spec: v1.GatewaySpec{
+					Listeners: []v1.Listener{{
+						Protocol: v1.HTTPProtocolType,
+						Hostname: hostnamePtr("*.example.internal"),
+					}},
+				},
+				Status: gatewayStatus("1.2.3.4"),
+			}},
+			routes: []*v1.HTTPRoute{{
+				ObjectMeta: objectMeta("default", "subdomains"),
+				Spec: v1.HTTPRouteSpec{
+					Hostnames: []v1.Hostname{
+						"bar.foo.example.internal",
+						"*.foo.example.internal",
+						"example.internal",
+						"foo.example.com",
+					},
+				},
+				Status: httpRouteStatus(gatewayParentRef("default", "test")),
+			}},
+			endpoints: []*endpoint.Endpoint{
+				newTestEndpoint("bar.foo.example.internal", "A", "1.2.3.4"),
+				newTestEndpoint("*.foo.example.internal", "A", "1.2.3.4"),
+			},
+		},
+		{
+			title:      "ListenerNotAccepted",
+			config:     Config{},
+			namespaces: namespaces("default"),
+			gateways: []*v1.Gateway{{
+				ObjectMeta: objectMeta("default", "test"),
+				Spec: v1.GatewaySpec{
+					Listeners: []v1.Listener{
+						{
+							Name:     "foo",
+							Protocol: v1.HTTPProtocolType,
+							Hostname: hostnamePtr("foo.example.internal"),
+						},
+						{
+							Name:     "bar",
+							Protocol: v1.HTTPProtocolType,
+							Hostname: hostnamePtr("bar.example.internal"),
+						},
+					},
+				},
+				Status: func() v1.GatewayStatus {
+					status := gatewayStatus("1.2.3.4")
+					status.Listeners = []v1.ListenerStatus{
+						{
+							Name: "foo",
+							Conditions: []metav1.Condition{{
+								Type:   string(v1.ListenerConditionAccepted),
+								Status: metav1.ConditionTrue,
+							}},
+						},
+						{
+							Name: "bar",
+							Conditions: []metav1.Condition{{
+								Type:   string(v1.ListenerConditionAccepted),
+								Status: metav1.ConditionFalse,
+							}},
+						},
+					}
+					return status
+				}(),
+			}},
+			routes: []*v1.HTTPRoute{{
+				ObjectMeta: objectMeta("default", "no-hostname"),
+				Spec:       v1.HTTPRouteSpec{},
+				Status:     httpRouteStatus(gatewayParentRef("default", "test")),
+			}},
+			endpoints: []*endpoint.Endpoint{
+				newTestEndpoint("foo.example.internal", "A", "1.2.3.4"),
+			},
+		},
 		{
 			title:      "NoRouteHostname",
 			config:     Config{},
@@ -1071,6 +1152,69 @@ func TestGatewayHTTPRouteSourceEndpoints(t *testing.T) {
 				newTestEndpoint("test.example.internal", "A", "4.3.2.1"),
 			},
 		},
+		{
+			title:      "InfrastructureAnnotations",
+			config:     Config{},
+			namespaces: namespaces("default"),
+			gateways: []*v1.Gateway{
+				{
+					ObjectMeta: objectMeta("default", "infrastructure"),
+					Spec: v1.GatewaySpec{
+						Listeners: []v1.Listener{{Protocol: v1.HTTPProtocolType}},
+						Infrastructure: &v1.GatewayInfrastructure{
+							Annotations: map[v1.AnnotationKey]v1.AnnotationValue{
+								targetAnnotationKey: "4.3.2.1",
+								ttlAnnotationKey:    "60",
+							},
+						},
+					},
+					Status: gatewayStatus("1.2.3.4"),
+				},
+				{
+					ObjectMeta: objectMeta("default", "other-controller"),
+					Spec: v1.GatewaySpec{
+						Listeners: []v1.Listener{{Protocol: v1.HTTPProtocolType}},
+						Infrastructure: &v1.GatewayInfrastructure{
+							Annotations: map[v1.AnnotationKey]v1.AnnotationValue{
+								controllerAnnotationKey: "something-else",
+							},
+						},
+					},
+					Status: gatewayStatus("2.3.4.5"),
+				},
+			},
+			routes: []*v1.HTTPRoute{
+				{
+					ObjectMeta: objectMeta("default", "gateway-ttl"),
+					Spec: v1.HTTPRouteSpec{
+						Hostnames: hostnames("gateway-ttl.internal"),
+					},
+					Status: httpRouteStatus(gatewayParentRef("default", "infrastructure")),
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "route-ttl",
+						Namespace:   "default",
+						Annotations: map[string]string{ttlAnnotationKey: "15s"},
+					},
+					Spec: v1.HTTPRouteSpec{
+						Hostnames: hostnames("route-ttl.internal"),
+					},
+					Status: httpRouteStatus(gatewayParentRef("default", "infrastructure")),
+				},
+				{
+					ObjectMeta: objectMeta("default", "other-controller"),
+					Spec: v1.HTTPRouteSpec{
+						Hostnames: hostnames("other-controller.internal"),
+					},
+					Status: httpRouteStatus(gatewayParentRef("default", "other-controller")),
+				},
+			},
+			endpoints: []*endpoint.Endpoint{
+				newTestEndpointWithTTL("gateway-ttl.internal", "A", 60, "4.3.2.1"),
+				newTestEndpointWithTTL("route-ttl.internal", "A", 15, "4.3.2.1"),
+			},
+		},
 		{
 			title: "MutlipleGatewaysOneAnnotationOverride",
 			config: Config{
@@ -24,7 +24,11 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -43,6 +47,11 @@ var (
 		Version:  "v1",
 		Resource: "virtualservices",
 	}
+	routeTableGVR = schema.GroupVersionResource{
+		Group:    "gateway.solo.io",
+		Version:  "v1",
+		Resource: "routetables",
+	}
 )
 
 // Basic redefinition of "Proxy" CRD : https://github.com/solo-io/gloo/blob/v1.4.6/projects/gloo/pkg/api/v1/proxy.pb.go
@@ -94,19 +103,77 @@ type proxyVirtualHostMetadataSourceResourceRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// Basic redefinition of the "VirtualService" and "RouteTable" CRDs, limited to the fields leading to hostnames:
+// https://github.com/solo-io/gloo/blob/v1.14.0/projects/gateway/api/v1/virtual_service.proto
+// https://github.com/solo-io/gloo/blob/v1.14.0/projects/gateway/api/v1/route_table.proto
+type glooVirtualService struct {
+	Metadata metav1.ObjectMeta      `json:"metadata,omitempty"`
+	Spec     glooVirtualServiceSpec `json:"spec,omitempty"`
+}
+
+type glooVirtualServiceSpec struct {
+	VirtualHost glooVirtualHost `json:"virtualHost,omitempty"`
+	SSLConfig   *glooSSLConfig  `json:"sslConfig,omitempty"`
+}
+
+type glooVirtualHost struct {
+	Routes []glooRoute `json:"routes,omitempty"`
+}
+
+type glooSSLConfig struct {
+	SNIDomains []string `json:"sniDomains,omitempty"`
+}
+
+type glooRouteTable struct {
+	Metadata metav1.ObjectMeta  `json:"metadata,omitempty"`
+	Spec     glooRouteTableSpec `json:"spec,omitempty"`
+}
+
+type glooRouteTableSpec struct {
+	Routes []glooRoute `json:"routes,omitempty"`
+}
+
+type glooRoute struct {
+	DelegateAction *glooDelegateAction `json:"delegateAction,omitempty"`
+}
+
+type glooDelegateAction struct {
+	// Name and Namespace are the deprecated inline form of Ref.
+	Name      string                  `json:"name,omitempty"`
+	Namespace string                  `json:"namespace,omitempty"`
+	Ref       *glooResourceRef        `json:"ref,omitempty"`
+	Selector  *glooRouteTableSelector `json:"selector,omitempty"`
+}
+
+type glooResourceRef struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type glooRouteTableSelector struct {
+	Namespaces []string          `json:"namespaces,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
 type glooSource struct {
 	dynamicKubeClient dynamic.Interface
 	kubeClient        kubernetes.Interface
 	glooNamespaces    []string
+	labelSelector     labels.Selector
 }
 
-// NewGlooSource creates a new glooSource with the given config
+// NewGlooSource creates a new glooSource with the given config. Only the proxies matching labelSelector are
+// considered.
 func NewGlooSource(dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface,
-	glooNamespaces []string) (Source, error) {
+	glooNamespaces []string, labelSelector labels.Selector) (Source, error) {
+	if labelSelector == nil {
+		labelSelector = labels.Everything()
+	}
 	return &glooSource{
 		dynamicKubeClient,
 		kubeClient,
 		glooNamespaces,
+		labelSelector,
 	}, nil
 }
 
@@ -118,7 +185,7 @@ func (gs *glooSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 	endpoints := []*endpoint.Endpoint{}
 
 	for _, ns := range gs.glooNamespaces {
-		proxies, err := gs.dynamicKubeClient.Resource(proxyGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		proxies, err := gs.dynamicKubeClient.Resource(proxyGVR).Namespace(ns).List(ctx, metav1.ListOptions{LabelSelector: gs.labelSelector.String()})
 		if err != nil {
 			return nil, err
 		}
@@ -161,22 +228,33 @@ func (gs *glooSource) generateEndpointsFromProxy(ctx context.Context, proxy *pro
 
 	for _, listener := range proxy.Spec.Listeners {
 		for _, virtualHost := range listener.HTTPListener.VirtualHosts {
-			annotations, err := gs.annotationsFromProxySource(ctx, virtualHost)
+			sources, err := gs.virtualServicesFromProxySource(ctx, virtualHost)
+			if err != nil {
+				return nil, err
+			}
+			annotations := map[string]string{}
+			for _, source := range sources {
+				for key, value := range source.GetAnnotations() {
+					annotations[key] = value
+				}
+			}
+			hostnames, err := gs.hostnamesFromVirtualServices(ctx, virtualHost.Domains, sources)
 			if err != nil {
 				return nil, err
 			}
 			ttl := getTTLFromAnnotations(annotations, resource)
 			providerSpecific, setIdentifier := getProviderSpecificAnnotations(annotations)
-			for _, domain := range virtualHost.Domains {
-				endpoints = append(endpoints, endpointsForHostname(strings.TrimSuffix(domain, "."), targets, ttl, providerSpecific, setIdentifier, "")...)
+			for _, hostname := range hostnames {
+				endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier, "")...)
 			}
 		}
 	}
 	return endpoints, nil
 }
 
-func (gs *glooSource) annotationsFromProxySource(ctx context.Context, virtualHost proxyVirtualHost) (map[string]string, error) {
-	annotations := map[string]string{}
+// virtualServicesFromProxySource returns the VirtualServices the virtual host of a proxy was generated from.
+func (gs *glooSource) virtualServicesFromProxySource(ctx context.Context, virtualHost proxyVirtualHost) ([]*unstructured.Unstructured, error) {
+	var sources []*unstructured.Unstructured
 	for _, src := range virtualHost.Metadata.Source {
 		kind := sourceKind(src.Kind)
 		if kind != nil {
@@ -184,9 +262,7 @@ func (gs *glooSource) annotationsFromProxySource(ctx context.Context, virtualHos
 			if err != nil {
 				return nil, err
 			}
-			for key, value := range source.GetAnnotations() {
-				annotations[key] = value
-			}
+			sources = append(sources, source)
 		}
 	}
 	for _, src := range virtualHost.MetadataStatic.Source {
@@ -196,12 +272,130 @@ func (gs *glooSource) annotationsFromProxySource(ctx context.Context, virtualHos
 			if err != nil {
 				return nil, err
 			}
-			for key, value := range source.GetAnnotations() {
-				annotations[key] = value
+			sources = append(sources, source)
+		}
+	}
+	return sources, nil
+}
+
+// hostnamesFromVirtualServices returns the domains of a virtual host along with the SNI domains of the
+// VirtualServices it was generated from and the hostnames annotated on the RouteTables they delegate to.
+func (gs *glooSource) hostnamesFromVirtualServices(ctx context.Context, domains []string, sources []*unstructured.Unstructured) ([]string, error) {
+	hostnames := []string{}
+	seen := map[string]struct{}{}
+	add := func(names ...string) {
+		for _, name := range names {
+			name = strings.TrimSuffix(name, ".")
+			if _, ok := seen[name]; ok || name == "" {
+				continue
 			}
+			seen[name] = struct{}{}
+			hostnames = append(hostnames, name)
+		}
+	}
+
+	add(domains...)
+	visited := map[string]struct{}{}
+	for _, source := range sources {
+		virtualService := glooVirtualService{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(source.UnstructuredContent(), &virtualService); err != nil {
+			return nil, err
+		}
+		if virtualService.Spec.SSLConfig != nil {
+			add(virtualService.Spec.SSLConfig.SNIDomains...)
+		}
+		delegated, err := gs.delegatedHostnames(ctx, source.GetNamespace(), virtualService.Spec.VirtualHost.Routes, visited)
+		if err != nil {
+			return nil, err
+		}
+		add(delegated...)
+	}
+	return hostnames, nil
+}
+
+// delegatedHostnames returns the hostnames annotated on the RouteTables the routes delegate to, following the
+// delegation chains. Each RouteTable is visited once, which also breaks delegation cycles.
+func (gs *glooSource) delegatedHostnames(ctx context.Context, namespace string, routes []glooRoute, visited map[string]struct{}) ([]string, error) {
+	var hostnames []string
+	for _, route := range routes {
+		if route.DelegateAction == nil {
+			continue
+		}
+		routeTables, err := gs.delegatedRouteTables(ctx, namespace, route.DelegateAction)
+		if err != nil {
+			return nil, err
+		}
+		for _, routeTable := range routeTables {
+			key := routeTable.Metadata.Namespace + "/" + routeTable.Metadata.Name
+			if _, ok := visited[key]; ok {
+				continue
+			}
+			visited[key] = struct{}{}
+			log.Debugf("Gloo: Follow delegation to %s route table", key)
+
+			hostnames = append(hostnames, getHostnamesFromAnnotations(routeTable.Metadata.Annotations)...)
+			nested, err := gs.delegatedHostnames(ctx, routeTable.Metadata.Namespace, routeTable.Spec.Routes, visited)
+			if err != nil {
+				return nil, err
+			}
+			hostnames = append(hostnames, nested...)
+		}
+	}
+	return hostnames, nil
+}
+
+// delegatedRouteTables returns the RouteTables a delegate action refers to, either by reference or by selector. The
+// namespace of the delegating resource is used when the action does not name one.
+func (gs *glooSource) delegatedRouteTables(ctx context.Context, namespace string, action *glooDelegateAction) ([]glooRouteTable, error) {
+	ref := action.Ref
+	if ref == nil && action.Name != "" {
+		ref = &glooResourceRef{Name: action.Name, Namespace: action.Namespace}
+	}
+
+	var objects []unstructured.Unstructured
+	switch {
+	case ref != nil:
+		refNamespace := ref.Namespace
+		if refNamespace == "" {
+			refNamespace = namespace
+		}
+		obj, err := gs.dynamicKubeClient.Resource(routeTableGVR).Namespace(refNamespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			log.Debugf("Gloo: Delegated route table %s/%s not found", refNamespace, ref.Name)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, *obj)
+	case action.Selector != nil:
+		namespaces := action.Selector.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{namespace}
+		}
+		for _, selectorNamespace := range namespaces {
+			if selectorNamespace == "*" {
+				selectorNamespace = ""
+			}
+			list, err := gs.dynamicKubeClient.Resource(routeTableGVR).Namespace(selectorNamespace).List(ctx, metav1.ListOptions{
+				LabelSelector: labels.SelectorFromSet(action.Selector.Labels).String(),
+			})
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, list.Items...)
+		}
+	}
+
+	routeTables := make([]glooRouteTable, 0, len(objects))
+	for _, obj := range objects {
+		routeTable := glooRouteTable{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &routeTable); err != nil {
+			return nil, err
 		}
+		routeTables = append(routeTables, routeTable)
 	}
-	return annotations, nil
+	return routeTables, nil
 }
 
 func (gs *glooSource) proxyTargets(ctx context.Context, name string, namespace string) (endpoint.Targets, error) {
@@ -22,9 +22,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	fakeDynamic "k8s.io/client-go/dynamic/fake"
@@ -401,7 +403,7 @@ func TestGlooSource(t *testing.T) {
 			proxyGVR: "ProxyList",
 		})
 
-	source, err := NewGlooSource(fakeDynamicClient, fakeKubernetesClient, []string{defaultGlooNamespace})
+	source, err := NewGlooSource(fakeDynamicClient, fakeKubernetesClient, []string{defaultGlooNamespace}, labels.Everything())
 	assert.NoError(t, err)
 	assert.NotNil(t, source)
 
@@ -588,3 +590,96 @@ func TestGlooSource(t *testing.T) {
 		},
 	})
 }
+
+func TestGlooSourceDelegation(t *testing.T) {
+	t.Parallel()
+
+	fakeKubernetesClient := fakeKube.NewSimpleClientset()
+	fakeDynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			proxyGVR:      "ProxyList",
+			routeTableGVR: "RouteTableList",
+		})
+
+	newObject := func(gvr schema.GroupVersionResource, kind, namespace, name string, objectLabels map[string]string, annotations map[string]string, spec map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		obj.SetAPIVersion(gvr.GroupVersion().String())
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetLabels(objectLabels)
+		obj.SetAnnotations(annotations)
+		_, err := fakeDynamicClient.Resource(gvr).Namespace(namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		require.NoError(t, err)
+		return obj
+	}
+	newProxy := func(name string, proxyLabels map[string]string, domain, virtualService string) {
+		newObject(proxyGVR, "Proxy", defaultGlooNamespace, name, proxyLabels, nil, map[string]interface{}{
+			"listeners": []interface{}{
+				map[string]interface{}{
+					"httpListener": map[string]interface{}{
+						"virtualHosts": []interface{}{
+							map[string]interface{}{
+								"domains": []interface{}{domain},
+								"metadata": map[string]interface{}{
+									"sources": []interface{}{
+										map[string]interface{}{"kind": "*v1.VirtualService", "name": virtualService, "namespace": "apps"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		_, err := fakeKubernetesClient.CoreV1().Services(defaultGlooNamespace).Create(context.Background(), &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: defaultGlooNamespace},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			Status: corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}},
+			},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	delegateTo := func(delegateAction map[string]interface{}) []interface{} {
+		return []interface{}{map[string]interface{}{"delegateAction": delegateAction}}
+	}
+
+	newProxy("public", map[string]string{"exposure": "public"}, "app.test", "app")
+	newProxy("private", map[string]string{"exposure": "private"}, "private.test", "private")
+
+	newObject(virtualServiceGVR, "VirtualService", "apps", "app", nil, nil, map[string]interface{}{
+		"sslConfig": map[string]interface{}{"sniDomains": []interface{}{"app.test", "tls.app.test"}},
+		"virtualHost": map[string]interface{}{
+			"domains": []interface{}{"app.test"},
+			"routes":  delegateTo(map[string]interface{}{"ref": map[string]interface{}{"name": "team-a"}}),
+		},
+	})
+	newObject(virtualServiceGVR, "VirtualService", "apps", "private", nil, nil, map[string]interface{}{
+		"virtualHost": map[string]interface{}{"domains": []interface{}{"private.test"}},
+	})
+	newObject(routeTableGVR, "RouteTable", "apps", "team-a", nil, map[string]string{hostnameAnnotationKey: "a.app.test"}, map[string]interface{}{
+		"routes": delegateTo(map[string]interface{}{
+			"selector": map[string]interface{}{"namespaces": []interface{}{"*"}, "labels": map[string]interface{}{"team": "b"}},
+		}),
+	})
+	newObject(routeTableGVR, "RouteTable", "team-b", "team-b", map[string]string{"team": "b"}, map[string]string{hostnameAnnotationKey: "b.app.test"}, map[string]interface{}{
+		// A delegation cycle back to the first route table.
+		"routes": delegateTo(map[string]interface{}{"ref": map[string]interface{}{"name": "team-a", "namespace": "apps"}}),
+	})
+	newObject(routeTableGVR, "RouteTable", "team-c", "team-c", map[string]string{"team": "c"}, map[string]string{hostnameAnnotationKey: "c.app.test"}, nil)
+
+	selector, err := labels.Parse("exposure=public")
+	require.NoError(t, err)
+	source, err := NewGlooSource(fakeDynamicClient, fakeKubernetesClient, []string{defaultGlooNamespace}, selector)
+	require.NoError(t, err)
+
+	endpoints, err := source.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		newTestEndpoint("app.test", endpoint.RecordTypeA, "203.0.113.10"),
+		newTestEndpoint("tls.app.test", endpoint.RecordTypeA, "203.0.113.10"),
+		newTestEndpoint("a.app.test", endpoint.RecordTypeA, "203.0.113.10"),
+		newTestEndpoint("b.app.test", endpoint.RecordTypeA, "203.0.113.10"),
+	})
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// httpSourcePollInterval is how often the http source polls the URL for changes to notify the event handlers of.
+const httpSourcePollInterval = time.Minute
+
+// httpSource is a Source getting the endpoints from a URL returning a JSON list of endpoints or a DNSEndpoint spec,
+// so that systems outside of Kubernetes can publish records. The response is cached along with its ETag, and only
+// fetched again when it changed.
+type httpSource struct {
+	url             string
+	bearerTokenFile string
+	client          *http.Client
+	pollInterval    time.Duration
+	// resource is the value of the resource label of the endpoints
+	resource string
+
+	mu        sync.Mutex
+	etag      string
+	body      []byte
+	endpoints []*endpoint.Endpoint
+}
+
+// NewHTTPSource creates a new httpSource getting the endpoints from the URL, authenticated with the bearer token
+// read from bearerTokenFile if set.
+func NewHTTPSource(rawURL, bearerTokenFile string, timeout time.Duration) (Source, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid URL %q: the scheme must be http or https", rawURL)
+	}
+	return &httpSource{
+		url:             rawURL,
+		bearerTokenFile: bearerTokenFile,
+		client:          &http.Client{Timeout: timeout},
+		pollInterval:    httpSourcePollInterval,
+		resource:        "http/" + u.Host,
+	}, nil
+}
+
+// Endpoints gets the endpoints from the URL, or returns the ones got last when they didn't change.
+func (hs *httpSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	if _, err := hs.fetch(ctx); err != nil {
+		return nil, err
+	}
+
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	// the endpoints are copied since they may be modified down the line, while being cached
+	endpoints := make([]*endpoint.Endpoint, 0, len(hs.endpoints))
+	for _, ep := range hs.endpoints {
+		endpoints = append(endpoints, ep.DeepCopy())
+	}
+	return endpoints, nil
+}
+
+// fetch gets the endpoints from the URL unless they didn't change since the ETag of the last response, and tells
+// whether they changed.
+func (hs *httpSource) fetch(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hs.url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if hs.bearerTokenFile != "" {
+		token, err := os.ReadFile(hs.bearerTokenFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to read the bearer token of %s: %w", hs.url, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	hs.mu.Lock()
+	if hs.etag != "" {
+		req.Header.Set("If-None-Match", hs.etag)
+	}
+	hs.mu.Unlock()
+
+	resp, err := hs.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the endpoints of %s: %w", hs.url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		log.Debugf("The endpoints of %s did not change", hs.url)
+		return false, nil
+	case http.StatusOK:
+	default:
+		return false, fmt.Errorf("failed to get the endpoints of %s: unexpected status %s", hs.url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the endpoints of %s: %w", hs.url, err)
+	}
+	parsed, err := parseStaticEndpoints(body)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the endpoints of %s: %w", hs.url, err)
+	}
+
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	changed := !bytes.Equal(body, hs.body)
+	hs.etag, hs.body = resp.Header.Get("ETag"), body
+	hs.endpoints = staticEndpoints(parsed, hs.resource)
+	return changed, nil
+}
+
+// AddEventHandler calls the handler whenever the endpoints returned by the URL change.
+func (hs *httpSource) AddEventHandler(ctx context.Context, handler func()) {
+	go func() {
+		ticker := time.NewTicker(hs.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			changed, err := hs.fetch(ctx)
+			if err != nil {
+				log.Debugf("Failed to poll the endpoints of %s: %v", hs.url, err)
+				continue
+			}
+			if changed {
+				handler()
+			}
+		}
+	}()
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// fakeEndpointsServer serves endpoints with an ETag, requiring a bearer token.
+type fakeEndpointsServer struct {
+	mu       sync.Mutex
+	body     string
+	etag     string
+	requests int
+	sent     int
+}
+
+func (s *fakeEndpointsServer) set(body, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body, s.etag = body, etag
+}
+
+func (s *fakeEndpointsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("If-None-Match") == s.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.sent++
+	w.Header().Set("ETag", s.etag)
+	_, _ = w.Write([]byte(s.body))
+}
+
+func TestHTTPSourceEndpoints(t *testing.T) {
+	server := &fakeEndpointsServer{}
+	server.set(`{"endpoints": [{"dnsName": "www.example.org", "recordType": "CNAME", "targets": ["app.example.org"]}]}`, `"v1"`)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+
+	src, err := NewHTTPSource(ts.URL, tokenFile, time.Second)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		endpoints, err := src.Endpoints(context.Background())
+		require.NoError(t, err)
+		require.Len(t, endpoints, 1)
+		assert.Equal(t, "www.example.org", endpoints[0].DNSName)
+		assert.Equal(t, "http/"+strings.TrimPrefix(ts.URL, "http://"), endpoints[0].Labels[endpoint.ResourceLabelKey])
+		// the cached endpoints are not shared
+		endpoints[0].DNSName = "modified.example.org"
+	}
+	assert.Equal(t, 2, server.requests)
+	assert.Equal(t, 1, server.sent)
+
+	server.set(`[{"dnsName": "api.example.org", "recordType": "A", "targets": ["1.2.3.4"]}]`, `"v2"`)
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "api.example.org", endpoints[0].DNSName)
+
+	src, err = NewHTTPSource(ts.URL, "", time.Second)
+	require.NoError(t, err)
+	_, err = src.Endpoints(context.Background())
+	assert.ErrorContains(t, err, "unexpected status 401 Unauthorized")
+}
+
+func TestNewHTTPSourceInvalidURL(t *testing.T) {
+	_, err := NewHTTPSource("ftp://inventory.example.org/endpoints", "", time.Second)
+	assert.Error(t, err)
+}
+
+func TestHTTPSourceAddEventHandler(t *testing.T) {
+	server := &fakeEndpointsServer{}
+	server.set(`[]`, `"v1"`)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret"), 0o600))
+	src, err := NewHTTPSource(ts.URL, tokenFile, time.Second)
+	require.NoError(t, err)
+	_, err = src.Endpoints(context.Background())
+	require.NoError(t, err)
+	src.(*httpSource).pollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan struct{}, 10)
+	src.AddEventHandler(ctx, func() { changes <- struct{}{} })
+
+	select {
+	case <-changes:
+		t.Fatal("the handler was called while the endpoints didn't change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	server.set(`[{"dnsName": "api.example.org", "recordType": "A", "targets": ["1.2.3.4"]}]`, `"v2"`)
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the handler was not called when the endpoints changed")
+	}
+}
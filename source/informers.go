@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+)
+
+// InformerFactories shares informer factories between sources, so that the sources watching
+// the same resources, e.g. the Services, Pods and Nodes watched by the service, pod and node
+// sources, share one watch and one cache per resource instead of each building their own.
+// The factories are started with the context of the first source using them.
+type InformerFactories struct {
+	mu      sync.Mutex
+	kube    map[informerFactoryKey]kubeinformers.SharedInformerFactory
+	dynamic map[informerFactoryKey]dynamicinformer.DynamicSharedInformerFactory
+}
+
+// informerFactoryKey identifies the informers of a client watching a namespace, all namespaces when empty.
+type informerFactoryKey struct {
+	client    interface{}
+	namespace string
+}
+
+// NewInformerFactories returns informer factories to share between sources.
+func NewInformerFactories() *InformerFactories {
+	return &InformerFactories{
+		kube:    map[informerFactoryKey]kubeinformers.SharedInformerFactory{},
+		dynamic: map[informerFactoryKey]dynamicinformer.DynamicSharedInformerFactory{},
+	}
+}
+
+type informerFactoriesKey struct{}
+
+// WithInformerFactories returns a context making the sources created with it share the factories.
+func WithInformerFactories(ctx context.Context, factories *InformerFactories) context.Context {
+	return context.WithValue(ctx, informerFactoriesKey{}, factories)
+}
+
+// sharedKubeInformerFactory returns the factory of the informers of the namespace shared through the
+// context, or a new factory when the context doesn't share any.
+func sharedKubeInformerFactory(ctx context.Context, client kubernetes.Interface, namespace string) kubeinformers.SharedInformerFactory {
+	factories, _ := ctx.Value(informerFactoriesKey{}).(*InformerFactories)
+	if factories == nil {
+		// Set resync period to 0, to prevent processing when nothing has changed
+		return kubeinformers.NewSharedInformerFactoryWithOptions(client, 0, kubeinformers.WithNamespace(namespace))
+	}
+
+	factories.mu.Lock()
+	defer factories.mu.Unlock()
+	key := informerFactoryKey{client: client, namespace: namespace}
+	if f, ok := factories.kube[key]; ok {
+		return f
+	}
+	f := kubeinformers.NewSharedInformerFactoryWithOptions(client, 0, kubeinformers.WithNamespace(namespace))
+	factories.kube[key] = f
+	return f
+}
+
+// sharedDynamicInformerFactory is the sharedKubeInformerFactory of dynamic clients.
+func sharedDynamicInformerFactory(ctx context.Context, client dynamic.Interface, namespace string) dynamicinformer.DynamicSharedInformerFactory {
+	factories, _ := ctx.Value(informerFactoriesKey{}).(*InformerFactories)
+	if factories == nil {
+		return dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, 0, namespace, nil)
+	}
+
+	factories.mu.Lock()
+	defer factories.mu.Unlock()
+	key := informerFactoryKey{client: client, namespace: namespace}
+	if f, ok := factories.dynamic[key]; ok {
+		return f
+	}
+	f := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, 0, namespace, nil)
+	factories.dynamic[key] = f
+	return f
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSharedKubeInformerFactory(t *testing.T) {
+	client, other := fake.NewSimpleClientset(), fake.NewSimpleClientset()
+
+	ctx := WithInformerFactories(context.Background(), NewInformerFactories())
+	f := sharedKubeInformerFactory(ctx, client, "default")
+	assert.Same(t, f, sharedKubeInformerFactory(ctx, client, "default"))
+	assert.NotSame(t, f, sharedKubeInformerFactory(ctx, client, ""))
+	assert.NotSame(t, f, sharedKubeInformerFactory(ctx, other, "default"))
+
+	// without shared factories every source gets its own
+	assert.NotSame(t, sharedKubeInformerFactory(context.Background(), client, "default"), sharedKubeInformerFactory(context.Background(), client, "default"))
+}
+
+// countWatches returns the number of watches of the resource started on the client.
+func countWatches(client *fake.Clientset, resource string) int {
+	watches := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "watch" && action.GetResource().Resource == resource {
+			watches++
+		}
+	}
+	return watches
+}
+
+func TestSourcesShareInformers(t *testing.T) {
+	for _, tc := range []struct {
+		title   string
+		ctx     func(context.Context) context.Context
+		watches int
+	}{
+		{"separate informers", func(ctx context.Context) context.Context { return ctx }, 3},
+		{"shared informers", func(ctx context.Context) context.Context { return WithInformerFactories(ctx, NewInformerFactories()) }, 1},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctx = tc.ctx(ctx)
+			client := fake.NewSimpleClientset()
+
+			_, err := NewServiceSource(ctx, client, "", "", "", false, "", false, false, false, nil, false, labels.Everything(), false, false)
+			require.NoError(t, err)
+			_, err = NewPodSource(ctx, client, "", "", labels.Everything(), false)
+			require.NoError(t, err)
+			_, err = NewNodeSource(ctx, client, "", "", labels.Everything(), false, "", nil, false, false, "", "")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.watches, countWatches(client, "nodes"))
+		})
+	}
+}
@@ -28,7 +28,7 @@ import (
 	networkv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
-	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	netinformers "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -65,10 +65,15 @@ type ingressSource struct {
 	ignoreIngressTLSSpec     bool
 	ignoreIngressRulesSpec   bool
 	labelSelector            labels.Selector
+	cilium                   *ciliumIngressResolver
+	serviceInformer          coreinformers.ServiceInformer
+	cache                    endpointsCache
 }
 
 // NewIngressSource creates a new ingressSource with the given config.
-func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, namespace, annotationFilter string, fqdnTemplate string, combineFqdnAnnotation bool, ignoreHostnameAnnotation bool, ignoreIngressTLSSpec bool, ignoreIngressRulesSpec bool, labelSelector labels.Selector, ingressClassNames []string) (Source, error) {
+// With a ciliumLoadBalancerMode, the targets of the Ingresses implemented by Cilium are the load balancer addresses
+// of their Cilium Services, in that mode unless the Ingresses set another, the shared one being in ciliumNamespace.
+func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, namespace, annotationFilter string, fqdnTemplate string, combineFqdnAnnotation bool, ignoreHostnameAnnotation bool, ignoreIngressTLSSpec bool, ignoreIngressRulesSpec bool, labelSelector labels.Selector, ingressClassNames []string, ciliumLoadBalancerMode string, ciliumNamespace string) (Source, error) {
 	tmpl, err := parseTemplate(fqdnTemplate)
 	if err != nil {
 		return nil, err
@@ -91,7 +96,7 @@ func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, name
 	}
 	// Use shared informer to listen for add/update/delete of ingresses in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed.
-	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+	informerFactory := sharedKubeInformerFactory(ctx, kubeClient, namespace)
 	ingressInformer := informerFactory.Networking().V1().Ingresses()
 
 	// Add default resource event handlers to properly initialize informer.
@@ -122,6 +127,22 @@ func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, name
 		ignoreIngressRulesSpec:   ignoreIngressRulesSpec,
 		labelSelector:            labelSelector,
 	}
+
+	if ciliumLoadBalancerMode != "" {
+		// The Cilium Services are in the namespaces of the Ingresses and in the one of Cilium.
+		serviceInformerFactory := sharedKubeInformerFactory(ctx, kubeClient, "")
+		sc.serviceInformer = serviceInformerFactory.Core().V1().Services()
+		sc.serviceInformer.Informer()
+		serviceInformerFactory.Start(ctx.Done())
+		if err := waitForCacheSync(context.Background(), serviceInformerFactory); err != nil {
+			return nil, err
+		}
+		sc.cilium = &ciliumIngressResolver{
+			defaultMode: ciliumLoadBalancerMode,
+			namespace:   ciliumNamespace,
+			services:    sc.serviceInformer.Lister(),
+		}
+	}
 	return sc, nil
 }
 
@@ -144,6 +165,8 @@ func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 
 	endpoints := []*endpoint.Endpoint{}
 
+	// the endpoints of the ingresses which didn't change are taken from the cache
+	cycle := sc.cache.cycle()
 	for _, ing := range ingresses {
 		// Check controller annotation to see if we are responsible.
 		controller, ok := ing.Annotations[controllerAnnotationKey]
@@ -153,27 +176,22 @@ func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 			continue
 		}
 
-		ingEndpoints := endpointsFromIngress(ing, sc.ignoreHostnameAnnotation, sc.ignoreIngressTLSSpec, sc.ignoreIngressRulesSpec)
-
-		// apply template if host is missing on ingress
-		if (sc.combineFQDNAnnotation || len(ingEndpoints) == 0) && sc.fqdnTemplate != nil {
-			iEndpoints, err := sc.endpointsFromTemplate(ing)
-			if err != nil {
+		if sc.cilium != nil {
+			if ing, err = sc.cilium.resolve(ing); err != nil {
 				return nil, err
 			}
-
-			ingEndpoints = append(ingEndpoints, iEndpoints...)
 		}
 
-		if len(ingEndpoints) == 0 {
-			log.Debugf("No endpoints could be generated from ingress %s/%s", ing.Namespace, ing.Name)
-			continue
+		ingEndpoints, err := cycle.endpoints(ing, func() ([]*endpoint.Endpoint, error) {
+			return sc.endpointsFromIngress(ing)
+		})
+		if err != nil {
+			return nil, err
 		}
-
-		log.Debugf("Endpoints generated from ingress: %s/%s: %v", ing.Namespace, ing.Name, ingEndpoints)
-		sc.setDualstackLabel(ing, ingEndpoints)
+		ingEndpoints = withRecordType(ing.Annotations, ingEndpoints)
 		endpoints = append(endpoints, ingEndpoints...)
 	}
+	cycle.finish()
 
 	for _, ep := range endpoints {
 		sort.Sort(ep.Targets)
@@ -182,6 +200,33 @@ func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 	return endpoints, nil
 }
 
+// endpointsFromIngress returns the endpoints of the ingress, including the ones of the FQDN template.
+func (sc *ingressSource) endpointsFromIngress(ing *networkv1.Ingress) ([]*endpoint.Endpoint, error) {
+	ingEndpoints := endpointsFromIngress(ing, sc.ignoreHostnameAnnotation, sc.ignoreIngressTLSSpec, sc.ignoreIngressRulesSpec)
+
+	// apply template if host is missing on ingress
+	if (sc.combineFQDNAnnotation || len(ingEndpoints) == 0) && sc.fqdnTemplate != nil {
+		iEndpoints, err := sc.endpointsFromTemplate(ing)
+		if err != nil {
+			return nil, err
+		}
+
+		ingEndpoints = append(ingEndpoints, iEndpoints...)
+	}
+
+	if len(ingEndpoints) == 0 {
+		log.Debugf("No endpoints could be generated from ingress %s/%s", ing.Namespace, ing.Name)
+		return nil, nil
+	}
+
+	log.Debugf("Endpoints generated from ingress: %s/%s: %v", ing.Namespace, ing.Name, ingEndpoints)
+	ingEndpoints = withWildcards(ing.Annotations, ingEndpoints)
+	sc.setDualstackLabel(ing, ingEndpoints)
+	setCommitLabel(ing.Annotations, ingEndpoints)
+	setActiveTargetLabel(ing.Annotations, ingEndpoints)
+	return ingEndpoints, nil
+}
+
 func (sc *ingressSource) endpointsFromTemplate(ing *networkv1.Ingress) ([]*endpoint.Endpoint, error) {
 	hostnames, err := execTemplate(sc.fqdnTemplate, ing)
 	if err != nil {
@@ -368,4 +413,7 @@ func (sc *ingressSource) AddEventHandler(ctx context.Context, handler func()) {
 	// Right now there is no way to remove event handler from informer, see:
 	// https://github.com/kubernetes/kubernetes/issues/79610
 	sc.ingressInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	if sc.serviceInformer != nil {
+		sc.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	}
 }
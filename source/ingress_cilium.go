@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	log "github.com/sirupsen/logrus"
+	networkv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+const (
+	// CiliumLoadBalancerModeDedicated is the load balancer mode of Cilium where each Ingress has its own Service
+	CiliumLoadBalancerModeDedicated = "dedicated"
+	// CiliumLoadBalancerModeShared is the load balancer mode of Cilium where the Ingresses share one Service
+	CiliumLoadBalancerModeShared = "shared"
+
+	ciliumIngressClass                  = "cilium"
+	ciliumLoadBalancerModeAnnotationKey = "ingress.cilium.io/loadbalancer-mode"
+	ciliumSharedIngressService          = "cilium-ingress"
+	ciliumDedicatedIngressServicePrefix = "cilium-ingress-"
+)
+
+// ciliumIngressResolver replaces the status of the Ingresses of the cilium class by the load balancer status of the
+// Service of Cilium implementing them: the Service cilium-ingress-<name> in the namespace of the Ingress in the
+// dedicated mode, or the Service cilium-ingress in the namespace of Cilium in the shared mode.
+type ciliumIngressResolver struct {
+	defaultMode string
+	namespace   string
+	services    corelisters.ServiceLister
+}
+
+// resolve returns the Ingress with the load balancer status of its Cilium Service, or the Ingress itself when it isn't
+// implemented by Cilium or its Service doesn't exist. The resource version of the returned Ingress includes the one
+// of the Service, so that its cached endpoints are computed again when either changes.
+func (r *ciliumIngressResolver) resolve(ing *networkv1.Ingress) (*networkv1.Ingress, error) {
+	if !isCiliumIngress(ing) {
+		return ing, nil
+	}
+
+	mode := r.defaultMode
+	if v, ok := ing.Annotations[ciliumLoadBalancerModeAnnotationKey]; ok {
+		mode = v
+	}
+	namespace, name := ing.Namespace, ciliumDedicatedIngressServicePrefix+ing.Name
+	switch mode {
+	case CiliumLoadBalancerModeDedicated:
+	case CiliumLoadBalancerModeShared:
+		namespace, name = r.namespace, ciliumSharedIngressService
+	default:
+		log.Warnf("Ingress %s/%s has unknown Cilium load balancer mode %q", ing.Namespace, ing.Name, mode)
+		return ing, nil
+	}
+
+	svc, err := r.services.Services(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.Debugf("Cilium Service %s/%s of ingress %s/%s not found", namespace, name, ing.Namespace, ing.Name)
+		return ing, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	clone := *ing
+	clone.ResourceVersion = ing.ResourceVersion + "/" + svc.ResourceVersion
+	clone.Status = networkv1.IngressStatus{}
+	for _, lb := range svc.Status.LoadBalancer.Ingress {
+		clone.Status.LoadBalancer.Ingress = append(clone.Status.LoadBalancer.Ingress, networkv1.IngressLoadBalancerIngress{
+			IP:       lb.IP,
+			Hostname: lb.Hostname,
+		})
+	}
+	return &clone, nil
+}
+
+func isCiliumIngress(ing *networkv1.Ingress) bool {
+	if ing.Spec.IngressClassName != nil && *ing.Spec.IngressClassName != "" {
+		return *ing.Spec.IngressClassName == ciliumIngressClass
+	}
+	return ing.Annotations[IngressClassAnnotationKey] == ciliumIngressClass
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestIngressSourceCilium(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := fake.NewSimpleClientset()
+
+	cilium := ciliumIngressClass
+	other := "nginx"
+	for _, ing := range []*networkv1.Ingress{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dedicated", ResourceVersion: "1"},
+			Spec: networkv1.IngressSpec{
+				IngressClassName: &cilium,
+				Rules:            []networkv1.IngressRule{{Host: "dedicated.example.org"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "default",
+				Name:            "shared",
+				ResourceVersion: "1",
+				Annotations: map[string]string{
+					IngressClassAnnotationKey:           ciliumIngressClass,
+					ciliumLoadBalancerModeAnnotationKey: CiliumLoadBalancerModeShared,
+				},
+			},
+			Spec: networkv1.IngressSpec{Rules: []networkv1.IngressRule{{Host: "shared.example.org"}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "no-service", ResourceVersion: "1"},
+			Spec: networkv1.IngressSpec{
+				IngressClassName: &cilium,
+				Rules:            []networkv1.IngressRule{{Host: "no-service.example.org"}},
+			},
+			Status: networkv1.IngressStatus{LoadBalancer: networkv1.IngressLoadBalancerStatus{
+				Ingress: []networkv1.IngressLoadBalancerIngress{{IP: "4.4.4.4"}},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other", ResourceVersion: "1"},
+			Spec: networkv1.IngressSpec{
+				IngressClassName: &other,
+				Rules:            []networkv1.IngressRule{{Host: "other.example.org"}},
+			},
+			Status: networkv1.IngressStatus{LoadBalancer: networkv1.IngressLoadBalancerStatus{
+				Ingress: []networkv1.IngressLoadBalancerIngress{{IP: "5.5.5.5"}},
+			}},
+		},
+	} {
+		_, err := client.NetworkingV1().Ingresses(ing.Namespace).Create(ctx, ing, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	loadBalancerService := func(namespace, name, resourceVersion, ip string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, ResourceVersion: resourceVersion},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: ip}},
+			}},
+		}
+	}
+	_, err := client.CoreV1().Services("default").Create(ctx, loadBalancerService("default", "cilium-ingress-dedicated", "1", "1.1.1.1"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = client.CoreV1().Services("kube-system").Create(ctx, loadBalancerService("kube-system", "cilium-ingress", "1", "2.2.2.2"), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	src, err := NewIngressSource(ctx, client, "", "", "", false, false, false, false, labels.Everything(), nil, CiliumLoadBalancerModeDedicated, "kube-system")
+	require.NoError(t, err)
+
+	expected := func(dedicatedIP string) []*endpoint.Endpoint {
+		return []*endpoint.Endpoint{
+			{DNSName: "dedicated.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{dedicatedIP}},
+			{DNSName: "shared.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"2.2.2.2"}},
+			{DNSName: "no-service.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"4.4.4.4"}},
+			{DNSName: "other.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"5.5.5.5"}},
+		}
+	}
+	endpoints, err := src.Endpoints(ctx)
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, expected("1.1.1.1"))
+
+	// a change of the Service changes the endpoints of the unchanged Ingress
+	_, err = client.CoreV1().Services("default").Update(ctx, loadBalancerService("default", "cilium-ingress-dedicated", "2", "3.3.3.3"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		endpoints, err := src.Endpoints(ctx)
+		require.NoError(t, err)
+		for _, ep := range endpoints {
+			if ep.DNSName == "dedicated.example.org" {
+				return len(ep.Targets) == 1 && ep.Targets[0] == "3.3.3.3"
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -66,6 +66,8 @@ func (suite *IngressSuite) SetupTest() {
 		false,
 		labels.Everything(),
 		[]string{},
+		"",
+		"",
 	)
 	suite.NoError(err, "should initialize ingress source")
 }
@@ -84,6 +86,33 @@ func (suite *IngressSuite) TestDualstackLabelIsSet() {
 	}
 }
 
+func TestIngressRecordTypeAnnotation(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	ingress := (fakeIngress{
+		name:        "app",
+		namespace:   "default",
+		dnsnames:    []string{"app.example.org"},
+		ips:         []string{"8.8.8.8"},
+		hostnames:   []string{"lb.example.com"},
+		annotations: map[string]string{recordTypeAnnotationKey: "CNAME"},
+	}).Ingress()
+	_, err := fakeClient.NetworkingV1().Ingresses(ingress.Namespace).Create(context.Background(), ingress, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	sc, err := NewIngressSource(context.TODO(), fakeClient, "", "", "", false, false, false, false, labels.Everything(), []string{}, "", "")
+	require.NoError(t, err)
+	endpoints, err := sc.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{
+			DNSName:    "app.example.org",
+			RecordType: endpoint.RecordTypeCNAME,
+			Targets:    endpoint.Targets{"lb.example.com"},
+			Labels:     endpoint.Labels{endpoint.ResourceLabelKey: "ingress/default/app"},
+		},
+	})
+}
+
 func TestIngress(t *testing.T) {
 	t.Parallel()
 
@@ -162,6 +191,8 @@ func TestNewIngressSource(t *testing.T) {
 				false,
 				labels.Everything(),
 				ti.ingressClassNames,
+				"",
+				"",
 			)
 			if ti.expectError {
 				assert.Error(t, err)
@@ -1409,6 +1440,8 @@ func testIngressEndpoints(t *testing.T) {
 				ti.ignoreIngressRulesSpec,
 				ti.ingressLabelSelector,
 				ti.ingressClassNames,
+				"",
+				"",
 			)
 			// Informer cache has all of the ingresses. Retrieve and validate their endpoints.
 			res, err := source.Endpoints(context.Background())
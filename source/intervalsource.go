@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// intervalSource is a Source that only collects endpoints from its wrapped source once per interval,
+// returning the endpoints collected last in between. An event of the wrapped source triggers a refresh
+// on the next call.
+type intervalSource struct {
+	source   Source
+	interval time.Duration
+
+	mutex       sync.Mutex
+	endpoints   []*endpoint.Endpoint
+	refreshTime time.Time
+}
+
+// NewIntervalSource creates a new intervalSource wrapping the provided Source.
+func NewIntervalSource(source Source, interval time.Duration) Source {
+	return &intervalSource{source: source, interval: interval}
+}
+
+// Endpoints returns the endpoints of the wrapped source, collecting them again once the interval elapsed.
+func (is *intervalSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	is.mutex.Lock()
+	defer is.mutex.Unlock()
+
+	if is.refreshTime.IsZero() || time.Since(is.refreshTime) >= is.interval {
+		endpoints, err := is.source.Endpoints(ctx)
+		if err != nil {
+			return nil, err
+		}
+		is.endpoints = endpoints
+		is.refreshTime = time.Now()
+	} else {
+		log.Debugf("Using endpoints collected at %s", is.refreshTime.Format(time.RFC3339))
+	}
+
+	// the endpoints are modified further down the line, hand out copies
+	result := make([]*endpoint.Endpoint, 0, len(is.endpoints))
+	for _, ep := range is.endpoints {
+		result = append(result, ep.DeepCopy())
+	}
+	return result, nil
+}
+
+func (is *intervalSource) AddEventHandler(ctx context.Context, handler func()) {
+	is.source.AddEventHandler(ctx, func() {
+		is.mutex.Lock()
+		is.refreshTime = time.Time{}
+		is.mutex.Unlock()
+		handler()
+	})
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// countingSource counts the calls to Endpoints and keeps the registered event handler.
+type countingSource struct {
+	Source
+	calls   int
+	handler func()
+}
+
+func (cs *countingSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	cs.calls++
+	return cs.Source.Endpoints(ctx)
+}
+
+func (cs *countingSource) AddEventHandler(ctx context.Context, handler func()) {
+	cs.handler = handler
+}
+
+func TestIntervalSource(t *testing.T) {
+	ctx := context.Background()
+	wrapped := &countingSource{Source: NewEchoSource([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	})}
+	src := NewIntervalSource(wrapped, time.Hour)
+
+	endpoints, err := src.Endpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	endpoints[0].Targets = endpoint.Targets{"5.6.7.8"}
+
+	endpoints, err = src.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, wrapped.calls)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, endpoints[0].Targets, "cached endpoints are not modified by callers")
+
+	triggered := false
+	src.AddEventHandler(ctx, func() { triggered = true })
+	wrapped.handler()
+	assert.True(t, triggered)
+
+	_, err = src.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, wrapped.calls, "events trigger a refresh")
+}
+
+func TestIntervalSourceExpires(t *testing.T) {
+	ctx := context.Background()
+	wrapped := &countingSource{Source: NewEchoSource(nil)}
+	src := NewIntervalSource(wrapped, 50*time.Millisecond)
+
+	_, err := src.Endpoints(ctx)
+	require.NoError(t, err)
+	_, err = src.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, wrapped.calls, "empty results are cached as well")
+	time.Sleep(100 * time.Millisecond)
+	_, err = src.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, wrapped.calls)
+}
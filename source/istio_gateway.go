@@ -30,7 +30,6 @@ import (
 	networkingv1alpha3informer "istio.io/client-go/pkg/informers/externalversions/networking/v1alpha3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	kubeinformers "k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -53,6 +52,7 @@ type gatewaySource struct {
 	fqdnTemplate             *template.Template
 	combineFQDNAnnotation    bool
 	ignoreHostnameAnnotation bool
+	serviceNamespace         string
 	serviceInformer          coreinformers.ServiceInformer
 	gatewayInformer          networkingv1alpha3informer.GatewayInformer
 }
@@ -67,6 +67,7 @@ func NewIstioGatewaySource(
 	fqdnTemplate string,
 	combineFQDNAnnotation bool,
 	ignoreHostnameAnnotation bool,
+	gatewayAnyNamespace bool,
 ) (Source, error) {
 	tmpl, err := parseTemplate(fqdnTemplate)
 	if err != nil {
@@ -75,7 +76,12 @@ func NewIstioGatewaySource(
 
 	// Use shared informers to listen for add/update/delete of services/pods/nodes in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed
-	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+	// The Services implementing the Gateways are looked up in all namespaces with gatewayAnyNamespace.
+	serviceNamespace := namespace
+	if gatewayAnyNamespace {
+		serviceNamespace = ""
+	}
+	informerFactory := sharedKubeInformerFactory(ctx, kubeClient, serviceNamespace)
 	serviceInformer := informerFactory.Core().V1().Services()
 	istioInformerFactory := istioinformers.NewSharedInformerFactory(istioClient, 0)
 	gatewayInformer := istioInformerFactory.Networking().V1alpha3().Gateways()
@@ -116,6 +122,7 @@ func NewIstioGatewaySource(
 		fqdnTemplate:             tmpl,
 		combineFQDNAnnotation:    combineFQDNAnnotation,
 		ignoreHostnameAnnotation: ignoreHostnameAnnotation,
+		serviceNamespace:         serviceNamespace,
 		serviceInformer:          serviceInformer,
 		gatewayInformer:          gatewayInformer,
 	}, nil
@@ -181,6 +188,7 @@ func (sc *gatewaySource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 		}
 
 		log.Debugf("Endpoints generated from gateway: %s/%s: %v", gateway.Namespace, gateway.Name, gwEndpoints)
+		gwEndpoints = withRecordType(gateway.Annotations, gwEndpoints)
 		endpoints = append(endpoints, gwEndpoints...)
 	}
 
@@ -278,7 +286,7 @@ func (sc *gatewaySource) targetsFromGateway(ctx context.Context, gateway *networ
 		return
 	}
 
-	services, err := sc.serviceInformer.Lister().Services(sc.namespace).List(labels.Everything())
+	services, err := sc.serviceInformer.Lister().Services(sc.serviceNamespace).List(labels.Everything())
 	if err != nil {
 		log.Error(err)
 		return
@@ -99,6 +99,7 @@ func (suite *GatewaySuite) SetupTest() {
 		"{{.Name}}",
 		false,
 		false,
+		false,
 	)
 	suite.NoError(err, "should initialize gateway source")
 	suite.NoError(err, "should succeed")
@@ -173,6 +174,7 @@ func TestNewIstioGatewaySource(t *testing.T) {
 				ti.fqdnTemplate,
 				ti.combineFQDNAndAnnotation,
 				false,
+				false,
 			)
 			if ti.expectError {
 				assert.Error(t, err)
@@ -481,6 +483,7 @@ func testGatewayEndpoints(t *testing.T) {
 		fqdnTemplate             string
 		combineFQDNAndAnnotation bool
 		ignoreHostnameAnnotation bool
+		gatewayAnyNamespace      bool
 	}{
 		{
 			title:           "no gateway",
@@ -1428,6 +1431,55 @@ func testGatewayEndpoints(t *testing.T) {
 			expected:    []*endpoint.Endpoint{},
 			expectError: true,
 		},
+		{
+			title:           "ingressgateway service in another namespace",
+			targetNamespace: "apps",
+			lbServices: []fakeIngressGatewayService{
+				{
+					namespace: "istio-system",
+					name:      "istio-ingressgateway",
+					ips:       []string{"8.8.8.8"},
+					selector:  map[string]string{"istio": "ingressgateway"},
+				},
+			},
+			configItems: []fakeGatewayConfig{
+				{
+					name:      "fake1",
+					namespace: "apps",
+					dnsnames:  [][]string{{"example.org"}},
+					selector:  map[string]string{"istio": "ingressgateway"},
+				},
+			},
+			expected: []*endpoint.Endpoint{},
+		},
+		{
+			title:           "ingressgateway service in another namespace with gatewayAnyNamespace",
+			targetNamespace: "apps",
+			lbServices: []fakeIngressGatewayService{
+				{
+					namespace: "istio-system",
+					name:      "istio-ingressgateway",
+					ips:       []string{"8.8.8.8"},
+					selector:  map[string]string{"istio": "ingressgateway"},
+				},
+			},
+			configItems: []fakeGatewayConfig{
+				{
+					name:      "fake1",
+					namespace: "apps",
+					dnsnames:  [][]string{{"example.org"}},
+					selector:  map[string]string{"istio": "ingressgateway"},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "example.org",
+					RecordType: endpoint.RecordTypeA,
+					Targets:    endpoint.Targets{"8.8.8.8"},
+				},
+			},
+			gatewayAnyNamespace: true,
+		},
 	} {
 		ti := ti
 		t.Run(ti.title, func(t *testing.T) {
@@ -1463,6 +1515,7 @@ func testGatewayEndpoints(t *testing.T) {
 				ti.fqdnTemplate,
 				ti.combineFQDNAndAnnotation,
 				ti.ignoreHostnameAnnotation,
+				ti.gatewayAnyNamespace,
 			)
 			require.NoError(t, err)
 
@@ -1507,6 +1560,7 @@ func newTestGatewaySource(loadBalancerList []fakeIngressGatewayService, ingressL
 		"{{.Name}}",
 		false,
 		false,
+		false,
 	)
 	if err != nil {
 		return nil, err
@@ -31,7 +31,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	kubeinformers "k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -53,11 +52,15 @@ type virtualServiceSource struct {
 	fqdnTemplate             *template.Template
 	combineFQDNAnnotation    bool
 	ignoreHostnameAnnotation bool
+	serviceNamespace         string
+	publishMeshOnlyHosts     bool
 	serviceInformer          coreinformers.ServiceInformer
 	virtualserviceInformer   networkingv1alpha3informer.VirtualServiceInformer
 }
 
 // NewIstioVirtualServiceSource creates a new virtualServiceSource with the given config.
+// The VirtualServices bound only to the mesh, or not exported to the namespace of any of their gateways,
+// are skipped unless publishMeshOnlyHosts is set.
 func NewIstioVirtualServiceSource(
 	ctx context.Context,
 	kubeClient kubernetes.Interface,
@@ -67,6 +70,8 @@ func NewIstioVirtualServiceSource(
 	fqdnTemplate string,
 	combineFQDNAnnotation bool,
 	ignoreHostnameAnnotation bool,
+	publishMeshOnlyHosts bool,
+	gatewayAnyNamespace bool,
 ) (Source, error) {
 	tmpl, err := parseTemplate(fqdnTemplate)
 	if err != nil {
@@ -75,7 +80,12 @@ func NewIstioVirtualServiceSource(
 
 	// Use shared informers to listen for add/update/delete of services/pods/nodes in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed
-	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+	// The Services implementing the Gateways are looked up in all namespaces with gatewayAnyNamespace.
+	serviceNamespace := namespace
+	if gatewayAnyNamespace {
+		serviceNamespace = ""
+	}
+	informerFactory := sharedKubeInformerFactory(ctx, kubeClient, serviceNamespace)
 	serviceInformer := informerFactory.Core().V1().Services()
 	istioInformerFactory := istioinformers.NewSharedInformerFactoryWithOptions(istioClient, 0, istioinformers.WithNamespace(namespace))
 	virtualServiceInformer := istioInformerFactory.Networking().V1alpha3().VirtualServices()
@@ -116,6 +126,8 @@ func NewIstioVirtualServiceSource(
 		fqdnTemplate:             tmpl,
 		combineFQDNAnnotation:    combineFQDNAnnotation,
 		ignoreHostnameAnnotation: ignoreHostnameAnnotation,
+		serviceNamespace:         serviceNamespace,
+		publishMeshOnlyHosts:     publishMeshOnlyHosts,
 		serviceInformer:          serviceInformer,
 		virtualserviceInformer:   virtualServiceInformer,
 	}, nil
@@ -144,6 +156,11 @@ func (sc *virtualServiceSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 			continue
 		}
 
+		if !sc.publishMeshOnlyHosts && virtualServiceIsMeshOnly(virtualService) {
+			log.Debugf("Skipping VirtualService %s/%s because it is bound only to the mesh", virtualService.Namespace, virtualService.Name)
+			continue
+		}
+
 		gwEndpoints, err := sc.endpointsFromVirtualService(ctx, virtualService)
 		if err != nil {
 			return nil, err
@@ -169,6 +186,7 @@ func (sc *virtualServiceSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 		}
 
 		log.Debugf("Endpoints generated from VirtualService: %s/%s: %v", virtualService.Namespace, virtualService.Name, gwEndpoints)
+		gwEndpoints = withRecordType(virtualService.Annotations, gwEndpoints)
 		endpoints = append(endpoints, gwEndpoints...)
 	}
 
@@ -361,20 +379,46 @@ func (sc *virtualServiceSource) endpointsFromVirtualService(ctx context.Context,
 	return endpoints, nil
 }
 
-// checks if the given VirtualService should actually bind to the given gateway
-// see requirements here: https://istio.io/docs/reference/config/networking/gateway/#Server
-func virtualServiceBindsToGateway(virtualService *networkingv1alpha3.VirtualService, gateway *networkingv1alpha3.Gateway, vsHost string) bool {
-	isValid := false
+// virtualServiceIsMeshOnly returns whether the VirtualService serves only the sidecars of the mesh: it is bound to
+// no gateway other than the "mesh" gateway, which is the default, or is exported to the namespace of none of them.
+func virtualServiceIsMeshOnly(virtualService *networkingv1alpha3.VirtualService) bool {
+	for _, gateway := range virtualService.Spec.Gateways {
+		if gateway == "" || gateway == IstioMeshGateway {
+			continue
+		}
+		namespace, _, err := parseGateway(gateway)
+		if err != nil {
+			// Let the gateway lookup report the invalid name.
+			return false
+		}
+		if namespace == "" {
+			namespace = virtualService.Namespace
+		}
+		if virtualServiceIsExportedTo(virtualService, namespace) {
+			return false
+		}
+	}
+	return true
+}
+
+// virtualServiceIsExportedTo returns whether the spec.exportTo of the VirtualService includes the namespace,
+// all namespaces when empty.
+func virtualServiceIsExportedTo(virtualService *networkingv1alpha3.VirtualService, namespace string) bool {
 	if len(virtualService.Spec.ExportTo) == 0 {
-		isValid = true
-	} else {
-		for _, ns := range virtualService.Spec.ExportTo {
-			if ns == "*" || ns == gateway.Namespace || (ns == "." && gateway.Namespace == virtualService.Namespace) {
-				isValid = true
-			}
+		return true
+	}
+	for _, ns := range virtualService.Spec.ExportTo {
+		if ns == "*" || ns == namespace || (ns == "." && namespace == virtualService.Namespace) {
+			return true
 		}
 	}
-	if !isValid {
+	return false
+}
+
+// checks if the given VirtualService should actually bind to the given gateway
+// see requirements here: https://istio.io/docs/reference/config/networking/gateway/#Server
+func virtualServiceBindsToGateway(virtualService *networkingv1alpha3.VirtualService, gateway *networkingv1alpha3.Gateway, vsHost string) bool {
+	if !virtualServiceIsExportedTo(virtualService, gateway.Namespace) {
 		return false
 	}
 
@@ -459,7 +503,7 @@ func (sc *virtualServiceSource) targetsFromGateway(ctx context.Context, gateway
 		return
 	}
 
-	services, err := sc.serviceInformer.Lister().Services(sc.namespace).List(labels.Everything())
+	services, err := sc.serviceInformer.Lister().Services(sc.serviceNamespace).List(labels.Everything())
 	if err != nil {
 		log.Error(err)
 		return
@@ -123,6 +123,8 @@ func (suite *VirtualServiceSuite) SetupTest() {
 		"{{.Name}}",
 		false,
 		false,
+		false,
+		false,
 	)
 	suite.NoError(err, "should initialize virtualservice source")
 }
@@ -200,6 +202,8 @@ func TestNewIstioVirtualServiceSource(t *testing.T) {
 				ti.fqdnTemplate,
 				ti.combineFQDNAndAnnotation,
 				false,
+				false,
+				false,
 			)
 			if ti.expectError {
 				assert.Error(t, err)
@@ -677,6 +681,7 @@ func testVirtualServiceEndpoints(t *testing.T) {
 		fqdnTemplate             string
 		combineFQDNAndAnnotation bool
 		ignoreHostnameAnnotation bool
+		publishMeshOnlyHosts     bool
 	}{
 		{
 			title: "two simple virtualservices with one gateway each, one ingressgateway loadbalancer service",
@@ -1808,6 +1813,102 @@ func testVirtualServiceEndpoints(t *testing.T) {
 			},
 			fqdnTemplate: "{{.Name}}.ext-dns.test.com",
 		},
+		{
+			title: "mesh-only virtualservices are skipped",
+			vsConfigs: []fakeVirtualServiceConfig{
+				{
+					name:        "gateway",
+					namespace:   namespace,
+					gateways:    []string{"istio-system/fake1"},
+					annotations: map[string]string{targetAnnotationKey: "1.2.3.4"},
+					dnsnames:    []string{"gateway.example.org"},
+				},
+				{
+					name:        "mesh",
+					namespace:   namespace,
+					gateways:    []string{"mesh"},
+					annotations: map[string]string{targetAnnotationKey: "1.2.3.4"},
+					dnsnames:    []string{"mesh.example.org"},
+				},
+				{
+					name:        "no-gateway",
+					namespace:   namespace,
+					annotations: map[string]string{targetAnnotationKey: "1.2.3.4"},
+					dnsnames:    []string{"no-gateway.example.org"},
+				},
+				{
+					name:        "not-exported",
+					namespace:   namespace,
+					gateways:    []string{"istio-system/fake1"},
+					annotations: map[string]string{targetAnnotationKey: "1.2.3.4"},
+					dnsnames:    []string{"not-exported.example.org"},
+					exportTo:    ".",
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "gateway.example.org",
+					Targets:    endpoint.Targets{"1.2.3.4"},
+					RecordType: endpoint.RecordTypeA,
+				},
+			},
+		},
+		{
+			title: "mesh-only virtualservices are published with publishMeshOnlyHosts",
+			vsConfigs: []fakeVirtualServiceConfig{
+				{
+					name:        "gateway",
+					namespace:   namespace,
+					gateways:    []string{"istio-system/fake1"},
+					annotations: map[string]string{targetAnnotationKey: "1.2.3.4"},
+					dnsnames:    []string{"gateway.example.org"},
+				},
+				{
+					name:        "mesh",
+					namespace:   namespace,
+					gateways:    []string{"mesh"},
+					annotations: map[string]string{targetAnnotationKey: "1.2.3.4"},
+					dnsnames:    []string{"mesh.example.org"},
+				},
+				{
+					name:        "no-gateway",
+					namespace:   namespace,
+					annotations: map[string]string{targetAnnotationKey: "1.2.3.4"},
+					dnsnames:    []string{"no-gateway.example.org"},
+				},
+				{
+					name:        "not-exported",
+					namespace:   namespace,
+					gateways:    []string{"istio-system/fake1"},
+					annotations: map[string]string{targetAnnotationKey: "1.2.3.4"},
+					dnsnames:    []string{"not-exported.example.org"},
+					exportTo:    ".",
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "gateway.example.org",
+					Targets:    endpoint.Targets{"1.2.3.4"},
+					RecordType: endpoint.RecordTypeA,
+				},
+				{
+					DNSName:    "mesh.example.org",
+					Targets:    endpoint.Targets{"1.2.3.4"},
+					RecordType: endpoint.RecordTypeA,
+				},
+				{
+					DNSName:    "no-gateway.example.org",
+					Targets:    endpoint.Targets{"1.2.3.4"},
+					RecordType: endpoint.RecordTypeA,
+				},
+				{
+					DNSName:    "not-exported.example.org",
+					Targets:    endpoint.Targets{"1.2.3.4"},
+					RecordType: endpoint.RecordTypeA,
+				},
+			},
+			publishMeshOnlyHosts: true,
+		},
 	} {
 		ti := ti
 		t.Run(ti.title, func(t *testing.T) {
@@ -1858,6 +1959,8 @@ func testVirtualServiceEndpoints(t *testing.T) {
 				ti.fqdnTemplate,
 				ti.combineFQDNAndAnnotation,
 				ti.ignoreHostnameAnnotation,
+				ti.publishMeshOnlyHosts,
+				false,
 			)
 			require.NoError(t, err)
 
@@ -1944,6 +2047,8 @@ func newTestVirtualServiceSource(loadBalancerList []fakeIngressGatewayService, i
 		"{{.Name}}",
 		false,
 		false,
+		false,
+		false,
 	)
 	if err != nil {
 		return nil, err
@@ -2072,6 +2177,8 @@ func TestVirtualServiceSourceGetGateway(t *testing.T) {
 					"{{.Name}}",
 					false,
 					false,
+					false,
+					false,
 				)
 				return vs.(*virtualServiceSource)
 			}(),
@@ -0,0 +1,488 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var (
+	knativeDomainMappingGVR = schema.GroupVersionResource{
+		Group:    "serving.knative.dev",
+		Version:  "v1beta1",
+		Resource: "domainmappings",
+	}
+	knativeRouteGVR = schema.GroupVersionResource{
+		Group:    "serving.knative.dev",
+		Version:  "v1",
+		Resource: "routes",
+	}
+)
+
+const (
+	// knativeVisibilityLabelKey is the label making Knative Routes only reachable from within the cluster
+	knativeVisibilityLabelKey = "networking.knative.dev/visibility"
+	// knativeDomainClaimedCondition is the condition of a DomainMapping telling whether its namespace owns the domain
+	knativeDomainClaimedCondition = "DomainClaimed"
+)
+
+// knativeSource is an implementation of Source for Knative Serving DomainMapping objects, and Route objects
+// when enabled.
+type knativeSource struct {
+	annotationFilter         string
+	ignoreHostnameAnnotation bool
+	domainMappingInformer    informers.GenericInformer
+	routeInformer            informers.GenericInformer
+	serviceInformer          coreinformers.ServiceInformer
+	kubeClient               kubernetes.Interface
+	namespace                string
+	ingressService           string
+	unstructuredConverter    *unstructuredConverter
+}
+
+// NewKnativeSource creates a new knativeSource with the given config. The DomainMappings, and the Routes with
+// routes set, get the load balancer addresses of the Knative ingress Service (<namespace>/<name>) as targets,
+// unless they have a target annotation.
+func NewKnativeSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, ignoreHostnameAnnotation bool, routes bool, ingressService string) (Source, error) {
+	// Use shared informer to listen for add/update/delete of DomainMappings and Routes in the specified namespace.
+	// Set resync period to 0, to prevent processing when nothing has changed.
+	informerFactory := sharedDynamicInformerFactory(ctx, dynamicKubeClient, namespace)
+	domainMappingInformer := informerFactory.ForResource(knativeDomainMappingGVR)
+	domainMappingInformer.Informer() // Register with factory before starting.
+	var routeInformer informers.GenericInformer
+	if routes {
+		routeInformer = informerFactory.ForResource(knativeRouteGVR)
+		routeInformer.Informer() // Register with factory before starting.
+	}
+
+	informerFactory.Start(ctx.Done())
+
+	// wait for the local cache to be populated.
+	if err := waitForDynamicCacheSync(context.Background(), informerFactory); err != nil {
+		return nil, err
+	}
+
+	serviceInformer, err := proxyServiceInformer(ctx, kubeClient, ingressService)
+	if err != nil {
+		return nil, err
+	}
+
+	uc, err := newKnativeUnstructuredConverter()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to setup Unstructured Converter")
+	}
+
+	return &knativeSource{
+		annotationFilter:         annotationFilter,
+		ignoreHostnameAnnotation: ignoreHostnameAnnotation,
+		domainMappingInformer:    domainMappingInformer,
+		routeInformer:            routeInformer,
+		serviceInformer:          serviceInformer,
+		kubeClient:               kubeClient,
+		namespace:                namespace,
+		ingressService:           ingressService,
+		unstructuredConverter:    uc,
+	}, nil
+}
+
+// Endpoints returns endpoint objects for each host-target combination that should be processed.
+// Retrieves all DomainMappings, and Routes when enabled, in the source's namespace(s).
+func (sc *knativeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	selector, err := getLabelSelector(sc.annotationFilter)
+	if err != nil {
+		return nil, err
+	}
+	services := &proxyServiceTargets{
+		kubeClient:     sc.kubeClient,
+		proxy:          "Knative ingress",
+		annotationKey:  knativeIngressServiceAnnotationKey,
+		defaultService: sc.ingressService,
+	}
+	if sc.serviceInformer != nil {
+		services.lister = sc.serviceInformer.Lister()
+	}
+
+	domainMappings, err := sc.list(sc.domainMappingInformer, func() runtime.Object { return &DomainMapping{} })
+	if err != nil {
+		return nil, err
+	}
+	var endpoints []*endpoint.Endpoint
+	for _, obj := range domainMappings {
+		dm := obj.(*DomainMapping)
+		if !matchLabelSelector(selector, dm.Annotations) {
+			continue
+		}
+		if knativeConditionFalse(dm.Status.Conditions, knativeDomainClaimedCondition) {
+			log.Debugf("Skipping DomainMapping %s/%s, as its domain is claimed by another namespace", dm.Namespace, dm.Name)
+			continue
+		}
+		eps, err := sc.endpointsFor(ctx, services, "domainmapping", dm.ObjectMeta, []string{dm.Name})
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, eps...)
+	}
+
+	if sc.routeInformer != nil {
+		routes, err := sc.list(sc.routeInformer, func() runtime.Object { return &KnativeRoute{} })
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range routes {
+			rt := obj.(*KnativeRoute)
+			if !matchLabelSelector(selector, rt.Annotations) {
+				continue
+			}
+			if rt.Labels[knativeVisibilityLabelKey] == "cluster-local" {
+				log.Debugf("Skipping Route %s/%s, as it is only visible within the cluster", rt.Namespace, rt.Name)
+				continue
+			}
+			eps, err := sc.endpointsFor(ctx, services, "route", rt.ObjectMeta, knativeRouteHostnames(rt))
+			if err != nil {
+				return nil, err
+			}
+			endpoints = append(endpoints, eps...)
+		}
+	}
+
+	for _, ep := range endpoints {
+		sort.Sort(ep.Targets)
+	}
+
+	return endpoints, nil
+}
+
+// list returns the objects of the informer, converted to the type of the new objects.
+func (sc *knativeSource) list(informer informers.GenericInformer, newObject func() runtime.Object) ([]runtime.Object, error) {
+	objs, err := informer.Lister().ByNamespace(sc.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var converted []runtime.Object
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, errors.New("could not convert")
+		}
+		o := newObject()
+		if err := sc.unstructuredConverter.scheme.Convert(u, o, nil); err != nil {
+			return nil, err
+		}
+		converted = append(converted, o)
+	}
+	return converted, nil
+}
+
+// endpointsFor returns the endpoints of the hostnames of a DomainMapping or Route, plus the ones of its hostname
+// annotation.
+func (sc *knativeSource) endpointsFor(ctx context.Context, services *proxyServiceTargets, kind string, meta metav1.ObjectMeta, hostnames []string) ([]*endpoint.Endpoint, error) {
+	resource := fmt.Sprintf("%s/%s/%s", kind, meta.Namespace, meta.Name)
+	if !sc.ignoreHostnameAnnotation {
+		hostnames = append(hostnames, getHostnamesFromAnnotations(meta.Annotations)...)
+	}
+	if len(hostnames) == 0 {
+		return nil, nil
+	}
+
+	targets, err := services.targets(ctx, meta.Namespace, meta.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		log.Debugf("No targets could be found for %s", resource)
+		return nil, nil
+	}
+
+	ttl := getTTLFromAnnotations(meta.Annotations, resource)
+	providerSpecific, setIdentifier := getProviderSpecificAnnotations(meta.Annotations)
+
+	var endpoints []*endpoint.Endpoint
+	for _, hostname := range hostnames {
+		endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier, resource)...)
+	}
+	log.Debugf("Endpoints generated from %s: %v", resource, endpoints)
+	return endpoints, nil
+}
+
+// knativeRouteHostnames returns the hostnames of the URL of a Route and of the URLs of its tagged traffic targets.
+func knativeRouteHostnames(rt *KnativeRoute) []string {
+	urls := []string{rt.Status.URL}
+	for _, traffic := range rt.Status.Traffic {
+		if traffic.Tag != "" {
+			urls = append(urls, traffic.URL)
+		}
+	}
+	var hostnames []string
+	for _, rawURL := range urls {
+		if rawURL == "" {
+			continue
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			log.Warnf("Invalid URL %q of Route %s/%s: %v", rawURL, rt.Namespace, rt.Name, err)
+			continue
+		}
+		if u.Hostname() == "" || strings.HasSuffix(u.Hostname(), ".svc.cluster.local") {
+			continue
+		}
+		hostnames = append(hostnames, u.Hostname())
+	}
+	return hostnames
+}
+
+func knativeConditionFalse(conditions []knativeCondition, conditionType string) bool {
+	for _, c := range conditions {
+		if c.Type == conditionType {
+			return c.Status == metav1.ConditionFalse
+		}
+	}
+	return false
+}
+
+func (sc *knativeSource) AddEventHandler(ctx context.Context, handler func()) {
+	log.Debug("Adding event handler for Knative DomainMappings and Routes")
+
+	// Right now there is no way to remove event handler from informer, see:
+	// https://github.com/kubernetes/kubernetes/issues/79610
+	sc.domainMappingInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	if sc.routeInformer != nil {
+		sc.routeInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	}
+	if sc.serviceInformer != nil {
+		sc.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	}
+}
+
+// newKnativeUnstructuredConverter returns a new unstructuredConverter initialized
+func newKnativeUnstructuredConverter() (*unstructuredConverter, error) {
+	uc := &unstructuredConverter{
+		scheme: runtime.NewScheme(),
+	}
+
+	// Add the core types we need
+	uc.scheme.AddKnownTypes(knativeDomainMappingGVR.GroupVersion(), &DomainMapping{}, &DomainMappingList{})
+	uc.scheme.AddKnownTypeWithName(knativeRouteGVR.GroupVersion().WithKind("Route"), &KnativeRoute{})
+	uc.scheme.AddKnownTypeWithName(knativeRouteGVR.GroupVersion().WithKind("RouteList"), &KnativeRouteList{})
+	if err := scheme.AddToScheme(uc.scheme); err != nil {
+		return nil, err
+	}
+
+	return uc, nil
+}
+
+// Knative types based on https://github.com/knative/serving/tree/v1.13.0/pkg/apis/serving, reduced to the
+// fields used here, see TCPIngress.
+type DomainMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   domainMappingSpec   `json:"spec,omitempty"`
+	Status domainMappingStatus `json:"status,omitempty"`
+}
+
+type DomainMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DomainMapping `json:"items"`
+}
+
+type domainMappingSpec struct {
+	Ref knativeReference `json:"ref"`
+}
+
+type knativeReference struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+type domainMappingStatus struct {
+	Conditions []knativeCondition `json:"conditions,omitempty"`
+	URL        string             `json:"url,omitempty"`
+}
+
+type knativeCondition struct {
+	Type   string                 `json:"type"`
+	Status metav1.ConditionStatus `json:"status"`
+}
+
+// KnativeRoute is a Route of Knative Serving.
+type KnativeRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status knativeRouteStatus `json:"status,omitempty"`
+}
+
+type KnativeRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KnativeRoute `json:"items"`
+}
+
+type knativeRouteStatus struct {
+	Conditions []knativeCondition     `json:"conditions,omitempty"`
+	URL        string                 `json:"url,omitempty"`
+	Traffic    []knativeTrafficTarget `json:"traffic,omitempty"`
+}
+
+type knativeTrafficTarget struct {
+	Tag string `json:"tag,omitempty"`
+	URL string `json:"url,omitempty"`
+}
+
+func (in *domainMappingStatus) DeepCopyInto(out *domainMappingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]knativeCondition, len(in.Conditions))
+		copy(out.Conditions, in.Conditions)
+	}
+}
+
+func (in *DomainMapping) DeepCopyInto(out *DomainMapping) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+func (in *DomainMapping) DeepCopy() *DomainMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *DomainMapping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (in *DomainMappingList) DeepCopyInto(out *DomainMappingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]DomainMapping, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+func (in *DomainMappingList) DeepCopy() *DomainMappingList {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainMappingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *DomainMappingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (in *knativeRouteStatus) DeepCopyInto(out *knativeRouteStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]knativeCondition, len(in.Conditions))
+		copy(out.Conditions, in.Conditions)
+	}
+	if in.Traffic != nil {
+		out.Traffic = make([]knativeTrafficTarget, len(in.Traffic))
+		copy(out.Traffic, in.Traffic)
+	}
+}
+
+func (in *KnativeRoute) DeepCopyInto(out *KnativeRoute) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+func (in *KnativeRoute) DeepCopy() *KnativeRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(KnativeRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KnativeRoute) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (in *KnativeRouteList) DeepCopyInto(out *KnativeRouteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]KnativeRoute, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+func (in *KnativeRouteList) DeepCopy() *KnativeRouteList {
+	if in == nil {
+		return nil
+	}
+	out := new(KnativeRouteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KnativeRouteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
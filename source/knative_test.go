@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// This is a compile-time validation that knativeSource is a Source.
+var _ Source = &knativeSource{}
+
+func TestKnativeSourceEndpoints(t *testing.T) {
+	t.Parallel()
+
+	domainMapping := func(name string, annotations map[string]string, conditions ...knativeCondition) *DomainMapping {
+		return &DomainMapping{
+			TypeMeta:   metav1.TypeMeta{APIVersion: knativeDomainMappingGVR.GroupVersion().String(), Kind: "DomainMapping"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec:       domainMappingSpec{Ref: knativeReference{APIVersion: "serving.knative.dev/v1", Kind: "Service", Name: "hello"}},
+			Status:     domainMappingStatus{URL: "https://" + name, Conditions: conditions},
+		}
+	}
+	route := func(name string, labels map[string]string, status knativeRouteStatus) *KnativeRoute {
+		return &KnativeRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: knativeRouteGVR.GroupVersion().String(), Kind: "Route"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Status:     status,
+		}
+	}
+
+	for _, ti := range []struct {
+		title                    string
+		domainMappings           []*DomainMapping
+		routes                   []*KnativeRoute
+		knativeRoutes            bool
+		ingressService           string
+		ignoreHostnameAnnotation bool
+		expected                 []*endpoint.Endpoint
+	}{
+		{
+			title:          "DomainMapping with targets from the ingress Service",
+			domainMappings: []*DomainMapping{domainMapping("app.example.com", nil)},
+			ingressService: "kourier-system/kourier",
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("app.example.com", "A", "1.2.3.4"),
+				newTestEndpoint("app.example.com", "CNAME", "lb.example.com"),
+			},
+		},
+		{
+			title: "DomainMapping with target annotation",
+			domainMappings: []*DomainMapping{domainMapping("app.example.com", map[string]string{
+				targetAnnotationKey: "5.6.7.8",
+			})},
+			ingressService: "kourier-system/kourier",
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("app.example.com", "A", "5.6.7.8"),
+			},
+		},
+		{
+			title: "DomainMapping with ingress Service annotation and hostname annotation",
+			domainMappings: []*DomainMapping{domainMapping("app.example.com", map[string]string{
+				knativeIngressServiceAnnotationKey: "kourier-system/kourier-internal",
+				hostnameAnnotationKey:              "alias.example.com",
+			})},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("app.example.com", "A", "10.0.0.1"),
+				newTestEndpoint("alias.example.com", "A", "10.0.0.1"),
+			},
+		},
+		{
+			title: "DomainMapping with ignored hostname annotation",
+			domainMappings: []*DomainMapping{domainMapping("app.example.com", map[string]string{
+				hostnameAnnotationKey: "alias.example.com",
+			})},
+			ingressService:           "kourier-system/kourier-internal",
+			ignoreHostnameAnnotation: true,
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("app.example.com", "A", "10.0.0.1"),
+			},
+		},
+		{
+			title: "DomainMapping with a domain claimed by another namespace",
+			domainMappings: []*DomainMapping{domainMapping("app.example.com", nil,
+				knativeCondition{Type: knativeDomainClaimedCondition, Status: metav1.ConditionFalse})},
+			ingressService: "kourier-system/kourier",
+			expected:       nil,
+		},
+		{
+			title:          "DomainMapping without targets",
+			domainMappings: []*DomainMapping{domainMapping("app.example.com", nil)},
+			expected:       nil,
+		},
+		{
+			title: "Routes not published by default",
+			routes: []*KnativeRoute{route("hello", nil, knativeRouteStatus{
+				URL: "https://hello.default.example.com",
+			})},
+			ingressService: "kourier-system/kourier-internal",
+			expected:       nil,
+		},
+		{
+			title: "Routes with tagged traffic",
+			routes: []*KnativeRoute{
+				route("hello", nil, knativeRouteStatus{
+					URL: "https://hello.default.example.com",
+					Traffic: []knativeTrafficTarget{
+						{URL: "https://hello.default.example.com"},
+						{Tag: "canary", URL: "https://canary-hello.default.example.com"},
+					},
+				}),
+				route("private", map[string]string{knativeVisibilityLabelKey: "cluster-local"}, knativeRouteStatus{
+					URL: "http://private.default.svc.cluster.local",
+				}),
+			},
+			knativeRoutes:  true,
+			ingressService: "kourier-system/kourier-internal",
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("hello.default.example.com", "A", "10.0.0.1"),
+				newTestEndpoint("canary-hello.default.example.com", "A", "10.0.0.1"),
+			},
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			kubeClient := fakeKube.NewSimpleClientset()
+			for name, ingress := range map[string][]corev1.LoadBalancerIngress{
+				"kourier":          {{IP: "1.2.3.4"}, {Hostname: "lb.example.com"}},
+				"kourier-internal": {{IP: "10.0.0.1"}},
+			} {
+				svc := &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kourier-system"},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+					Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingress}},
+				}
+				_, err := kubeClient.CoreV1().Services(svc.Namespace).Create(context.Background(), svc, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				knativeDomainMappingGVR: "DomainMappingList",
+				knativeRouteGVR:         "RouteList",
+			})
+			create := func(gvr schema.GroupVersionResource, obj interface{}) {
+				data, err := json.Marshal(obj)
+				require.NoError(t, err)
+				u := &unstructured.Unstructured{}
+				require.NoError(t, u.UnmarshalJSON(data))
+				_, err = fakeDynamicClient.Resource(gvr).Namespace(u.GetNamespace()).Create(context.Background(), u, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			for _, dm := range ti.domainMappings {
+				create(knativeDomainMappingGVR, dm)
+			}
+			for _, rt := range ti.routes {
+				create(knativeRouteGVR, rt)
+			}
+
+			source, err := NewKnativeSource(context.TODO(), fakeDynamicClient, kubeClient, "", "", ti.ignoreHostnameAnnotation, ti.knativeRoutes, ti.ingressService)
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, ti.expected)
+		})
+	}
+}
@@ -30,8 +30,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/cache"
@@ -51,18 +51,22 @@ type kongTCPIngressSource struct {
 	ignoreHostnameAnnotation bool
 	dynamicKubeClient        dynamic.Interface
 	kongTCPIngressInformer   informers.GenericInformer
+	serviceInformer          coreinformers.ServiceInformer
 	kubeClient               kubernetes.Interface
 	namespace                string
+	kongProxyService         string
 	unstructuredConverter    *unstructuredConverter
 }
 
-// NewKongTCPIngressSource creates a new kongTCPIngressSource with the given config.
-func NewKongTCPIngressSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, ignoreHostnameAnnotation bool) (Source, error) {
+// NewKongTCPIngressSource creates a new kongTCPIngressSource with the given config. TCPIngresses without a target
+// annotation get the load balancer addresses of the kongProxyService (<namespace>/<name>) as targets, or of the
+// Service of their kong-proxy-service annotation, and their load balancer status otherwise.
+func NewKongTCPIngressSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, ignoreHostnameAnnotation bool, kongProxyService string) (Source, error) {
 	var err error
 
 	// Use shared informer to listen for add/update/delete of Host in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed.
-	informerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicKubeClient, 0, namespace, nil)
+	informerFactory := sharedDynamicInformerFactory(ctx, dynamicKubeClient, namespace)
 	kongTCPIngressInformer := informerFactory.ForResource(kongGroupdVersionResource)
 
 	// Add default resource event handlers to properly initialize informer.
@@ -80,6 +84,11 @@ func NewKongTCPIngressSource(ctx context.Context, dynamicKubeClient dynamic.Inte
 		return nil, err
 	}
 
+	serviceInformer, err := proxyServiceInformer(ctx, kubeClient, kongProxyService)
+	if err != nil {
+		return nil, err
+	}
+
 	uc, err := newKongUnstructuredConverter()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to setup Unstructured Converter")
@@ -90,8 +99,10 @@ func NewKongTCPIngressSource(ctx context.Context, dynamicKubeClient dynamic.Inte
 		ignoreHostnameAnnotation: ignoreHostnameAnnotation,
 		dynamicKubeClient:        dynamicKubeClient,
 		kongTCPIngressInformer:   kongTCPIngressInformer,
+		serviceInformer:          serviceInformer,
 		kubeClient:               kubeClient,
 		namespace:                namespace,
+		kongProxyService:         kongProxyService,
 		unstructuredConverter:    uc,
 	}, nil
 }
@@ -124,18 +135,12 @@ func (sc *kongTCPIngressSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 		return nil, errors.Wrap(err, "failed to filter TCPIngresses")
 	}
 
+	services := newKongProxyServiceTargets(sc.kubeClient, sc.serviceInformer, sc.kongProxyService)
 	var endpoints []*endpoint.Endpoint
 	for _, tcpIngress := range tcpIngresses {
-		targets := getTargetsFromTargetAnnotation(tcpIngress.Annotations)
-		if len(targets) == 0 {
-			for _, lb := range tcpIngress.Status.LoadBalancer.Ingress {
-				if lb.IP != "" {
-					targets = append(targets, lb.IP)
-				}
-				if lb.Hostname != "" {
-					targets = append(targets, lb.Hostname)
-				}
-			}
+		targets, err := kongTargets(ctx, services, tcpIngress.Namespace, tcpIngress.Annotations, tcpIngress.Status.LoadBalancer)
+		if err != nil {
+			return nil, err
 		}
 
 		fullname := fmt.Sprintf("%s/%s", tcpIngress.Namespace, tcpIngress.Name)
@@ -151,6 +156,7 @@ func (sc *kongTCPIngressSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 
 		log.Debugf("Endpoints generated from TCPIngress: %s: %v", fullname, ingressEndpoints)
 		sc.setDualstackLabel(tcpIngress, ingressEndpoints)
+		ingressEndpoints = withRecordType(tcpIngress.Annotations, ingressEndpoints)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -236,6 +242,40 @@ func (sc *kongTCPIngressSource) AddEventHandler(ctx context.Context, handler fun
 	// Right now there is no way to remove event handler from informer, see:
 	// https://github.com/kubernetes/kubernetes/issues/79610
 	sc.kongTCPIngressInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	if sc.serviceInformer != nil {
+		sc.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	}
+}
+
+func newKongProxyServiceTargets(kubeClient kubernetes.Interface, serviceInformer coreinformers.ServiceInformer, kongProxyService string) *proxyServiceTargets {
+	services := &proxyServiceTargets{
+		kubeClient:     kubeClient,
+		proxy:          "Kong proxy",
+		annotationKey:  kongProxyServiceAnnotationKey,
+		defaultService: kongProxyService,
+	}
+	if serviceInformer != nil {
+		services.lister = serviceInformer.Lister()
+	}
+	return services
+}
+
+// kongTargets returns the targets of a Kong resource: the ones of its target annotation, or the load balancer
+// addresses of the Kong proxy Service, or the ones of its own load balancer status.
+func kongTargets(ctx context.Context, services *proxyServiceTargets, namespace string, annotations map[string]string, status corev1.LoadBalancerStatus) (endpoint.Targets, error) {
+	targets, err := services.targets(ctx, namespace, annotations)
+	if err != nil || len(targets) > 0 {
+		return targets, err
+	}
+	for _, lb := range status.Ingress {
+		if lb.IP != "" {
+			targets = append(targets, lb.IP)
+		}
+		if lb.Hostname != "" {
+			targets = append(targets, lb.Hostname)
+		}
+	}
+	return targets, nil
 }
 
 // newUnstructuredConverter returns a new unstructuredConverter initialized
@@ -245,7 +285,7 @@ func newKongUnstructuredConverter() (*unstructuredConverter, error) {
 	}
 
 	// Add the core types we need
-	uc.scheme.AddKnownTypes(kongGroupdVersionResource.GroupVersion(), &TCPIngress{}, &TCPIngressList{})
+	uc.scheme.AddKnownTypes(kongGroupdVersionResource.GroupVersion(), &TCPIngress{}, &TCPIngressList{}, &UDPIngress{}, &UDPIngressList{})
 	if err := scheme.AddToScheme(uc.scheme); err != nil {
 		return nil, err
 	}
@@ -43,6 +43,7 @@ func TestKongTCPIngressEndpoints(t *testing.T) {
 		title                    string
 		tcpProxy                 TCPIngress
 		ignoreHostnameAnnotation bool
+		kongProxyService         string
 		expected                 []*endpoint.Endpoint
 	}{
 		{
@@ -341,12 +342,57 @@ func TestKongTCPIngressEndpoints(t *testing.T) {
 				},
 			},
 		},
+		{
+			title:            "TCPIngress with targets from the Kong proxy Service",
+			kongProxyService: "kong/kong-proxy",
+			tcpProxy: TCPIngress{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: kongGroupdVersionResource.GroupVersion().String(),
+					Kind:       "TCPIngress",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tcp-ingress-proxy-service",
+					Namespace: defaultKongNamespace,
+					Annotations: map[string]string{
+						"kubernetes.io/ingress.class": "kong",
+					},
+				},
+				Spec: tcpIngressSpec{
+					Rules: []tcpIngressRule{
+						{
+							Port: 30004,
+							Host: "d.example.com",
+						},
+					},
+				},
+				Status: tcpIngressStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{
+								IP: "203.2.45.8",
+							},
+						},
+					},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "d.example.com",
+					Targets:    []string{"198.51.100.1"},
+					RecordType: endpoint.RecordTypeA,
+					Labels: endpoint.Labels{
+						"resource": "tcpingress/kong/tcp-ingress-proxy-service",
+					},
+					ProviderSpecific: endpoint.ProviderSpecific{},
+				},
+			},
+		},
 	} {
 		ti := ti
 		t.Run(ti.title, func(t *testing.T) {
 			t.Parallel()
 
-			fakeKubernetesClient := fakeKube.NewSimpleClientset()
+			fakeKubernetesClient := fakeKube.NewSimpleClientset(newKongProxyService())
 			scheme := runtime.NewScheme()
 			scheme.AddKnownTypes(kongGroupdVersionResource.GroupVersion(), &TCPIngress{}, &TCPIngressList{})
 			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(scheme)
@@ -362,7 +408,7 @@ func TestKongTCPIngressEndpoints(t *testing.T) {
 			_, err = fakeDynamicClient.Resource(kongGroupdVersionResource).Namespace(defaultKongNamespace).Create(context.Background(), &tcpi, metav1.CreateOptions{})
 			assert.NoError(t, err)
 
-			source, err := NewKongTCPIngressSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultKongNamespace, "kubernetes.io/ingress.class=kong", ti.ignoreHostnameAnnotation)
+			source, err := NewKongTCPIngressSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultKongNamespace, "kubernetes.io/ingress.class=kong", ti.ignoreHostnameAnnotation, ti.kongProxyService)
 			assert.NoError(t, err)
 			assert.NotNil(t, source)
 
@@ -378,3 +424,13 @@ func TestKongTCPIngressEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func newKongProxyService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "kong-proxy", Namespace: defaultKongNamespace},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "198.51.100.1"}}},
+		},
+	}
+}
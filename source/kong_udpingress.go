@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var kongUDPIngressGVR = schema.GroupVersionResource{
+	Group:    "configuration.konghq.com",
+	Version:  "v1beta1",
+	Resource: "udpingresses",
+}
+
+// kongUDPIngressSource is an implementation of Source for Kong UDPIngress objects. As UDP has no SNI, the hostnames
+// of an UDPIngress come from its hostname annotation only.
+type kongUDPIngressSource struct {
+	annotationFilter         string
+	ignoreHostnameAnnotation bool
+	kongUDPIngressInformer   informers.GenericInformer
+	serviceInformer          coreinformers.ServiceInformer
+	kubeClient               kubernetes.Interface
+	namespace                string
+	kongProxyService         string
+	unstructuredConverter    *unstructuredConverter
+}
+
+// NewKongUDPIngressSource creates a new kongUDPIngressSource with the given config. The targets of the UDPIngresses
+// are resolved like the ones of the TCPIngresses, see NewKongTCPIngressSource.
+func NewKongUDPIngressSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, ignoreHostnameAnnotation bool, kongProxyService string) (Source, error) {
+	// Use shared informer to listen for add/update/delete of UDPIngress in the specified namespace.
+	// Set resync period to 0, to prevent processing when nothing has changed.
+	informerFactory := sharedDynamicInformerFactory(ctx, dynamicKubeClient, namespace)
+	kongUDPIngressInformer := informerFactory.ForResource(kongUDPIngressGVR)
+	kongUDPIngressInformer.Informer() // Register with factory before starting.
+
+	informerFactory.Start(ctx.Done())
+
+	// wait for the local cache to be populated.
+	if err := waitForDynamicCacheSync(context.Background(), informerFactory); err != nil {
+		return nil, err
+	}
+
+	serviceInformer, err := proxyServiceInformer(ctx, kubeClient, kongProxyService)
+	if err != nil {
+		return nil, err
+	}
+
+	uc, err := newKongUnstructuredConverter()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to setup Unstructured Converter")
+	}
+
+	return &kongUDPIngressSource{
+		annotationFilter:         annotationFilter,
+		ignoreHostnameAnnotation: ignoreHostnameAnnotation,
+		kongUDPIngressInformer:   kongUDPIngressInformer,
+		serviceInformer:          serviceInformer,
+		kubeClient:               kubeClient,
+		namespace:                namespace,
+		kongProxyService:         kongProxyService,
+		unstructuredConverter:    uc,
+	}, nil
+}
+
+// Endpoints returns endpoint objects for each host-target combination that should be processed.
+// Retrieves all UDPIngresses in the source's namespace(s).
+func (sc *kongUDPIngressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	uis, err := sc.kongUDPIngressInformer.Lister().ByNamespace(sc.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := getLabelSelector(sc.annotationFilter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to filter UDPIngresses")
+	}
+
+	services := newKongProxyServiceTargets(sc.kubeClient, sc.serviceInformer, sc.kongProxyService)
+	var endpoints []*endpoint.Endpoint
+	for _, udpIngressObj := range uis {
+		unstructuredUDPIngress, ok := udpIngressObj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, errors.New("could not convert")
+		}
+
+		udpIngress := &UDPIngress{}
+		if err := sc.unstructuredConverter.scheme.Convert(unstructuredUDPIngress, udpIngress, nil); err != nil {
+			return nil, err
+		}
+		if !matchLabelSelector(selector, udpIngress.Annotations) {
+			continue
+		}
+
+		fullname := fmt.Sprintf("%s/%s", udpIngress.Namespace, udpIngress.Name)
+		if sc.ignoreHostnameAnnotation {
+			log.Debugf("No endpoints could be generated from UDPIngress %s, as hostname annotations are ignored", fullname)
+			continue
+		}
+
+		targets, err := kongTargets(ctx, services, udpIngress.Namespace, udpIngress.Annotations, udpIngress.Status.LoadBalancer)
+		if err != nil {
+			return nil, err
+		}
+
+		resource := fmt.Sprintf("udpingress/%s", fullname)
+		ttl := getTTLFromAnnotations(udpIngress.Annotations, resource)
+		providerSpecific, setIdentifier := getProviderSpecificAnnotations(udpIngress.Annotations)
+
+		var ingressEndpoints []*endpoint.Endpoint
+		for _, hostname := range getHostnamesFromAnnotations(udpIngress.Annotations) {
+			ingressEndpoints = append(ingressEndpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier, resource)...)
+		}
+		if len(ingressEndpoints) == 0 {
+			log.Debugf("No endpoints could be generated from UDPIngress %s", fullname)
+			continue
+		}
+
+		log.Debugf("Endpoints generated from UDPIngress: %s: %v", fullname, ingressEndpoints)
+		ingressEndpoints = withRecordType(udpIngress.Annotations, ingressEndpoints)
+		endpoints = append(endpoints, ingressEndpoints...)
+	}
+
+	for _, ep := range endpoints {
+		sort.Sort(ep.Targets)
+	}
+
+	return endpoints, nil
+}
+
+func (sc *kongUDPIngressSource) AddEventHandler(ctx context.Context, handler func()) {
+	log.Debug("Adding event handler for UDPIngress")
+
+	// Right now there is no way to remove event handler from informer, see:
+	// https://github.com/kubernetes/kubernetes/issues/79610
+	sc.kongUDPIngressInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	if sc.serviceInformer != nil {
+		sc.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	}
+}
+
+// Kong types based on https://github.com/Kong/kubernetes-ingress-controller/blob/v2.12.0/pkg/apis/configuration/v1beta1/udpingress_types.go,
+// see TCPIngress.
+type UDPIngress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   udpIngressSpec   `json:"spec,omitempty"`
+	Status udpIngressStatus `json:"status,omitempty"`
+}
+
+type UDPIngressList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UDPIngress `json:"items"`
+}
+
+type udpIngressSpec struct {
+	Rules []udpIngressRule `json:"rules,omitempty"`
+}
+
+type udpIngressRule struct {
+	Port    int               `json:"port,omitempty"`
+	Backend tcpIngressBackend `json:"backend"`
+}
+
+type udpIngressStatus struct {
+	LoadBalancer corev1.LoadBalancerStatus `json:"loadBalancer,omitempty"`
+}
+
+func (in *udpIngressSpec) DeepCopyInto(out *udpIngressSpec) {
+	*out = *in
+	if in.Rules != nil {
+		out.Rules = make([]udpIngressRule, len(in.Rules))
+		copy(out.Rules, in.Rules)
+	}
+}
+
+func (in *UDPIngress) DeepCopyInto(out *UDPIngress) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.LoadBalancer.DeepCopyInto(&out.Status.LoadBalancer)
+}
+
+func (in *UDPIngress) DeepCopy() *UDPIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(UDPIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *UDPIngress) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (in *UDPIngressList) DeepCopyInto(out *UDPIngressList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]UDPIngress, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+func (in *UDPIngressList) DeepCopy() *UDPIngressList {
+	if in == nil {
+		return nil
+	}
+	out := new(UDPIngressList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *UDPIngressList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// This is a compile-time validation that kongUDPIngressSource is a Source.
+var _ Source = &kongUDPIngressSource{}
+
+func TestKongUDPIngressEndpoints(t *testing.T) {
+	t.Parallel()
+
+	udpIngress := func(name string, annotations map[string]string, lb ...corev1.LoadBalancerIngress) UDPIngress {
+		annotations["kubernetes.io/ingress.class"] = "kong"
+		return UDPIngress{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: kongUDPIngressGVR.GroupVersion().String(),
+				Kind:       "UDPIngress",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   defaultKongNamespace,
+				Annotations: annotations,
+			},
+			Spec: udpIngressSpec{
+				Rules: []udpIngressRule{{Port: 9999, Backend: tcpIngressBackend{ServiceName: "dns", ServicePort: 53}}},
+			},
+			Status: udpIngressStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: lb}},
+		}
+	}
+
+	for _, ti := range []struct {
+		title                    string
+		udpIngress               UDPIngress
+		ignoreHostnameAnnotation bool
+		kongProxyService         string
+		expected                 []*endpoint.Endpoint
+	}{
+		{
+			title: "UDPIngress with hostname annotation",
+			udpIngress: udpIngress("udp-ingress", map[string]string{
+				hostnameAnnotationKey: "a.example.com",
+			}, corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("a.example.com", "CNAME", "lb.example.com"),
+			},
+		},
+		{
+			title: "UDPIngress without hostname annotation",
+			udpIngress: udpIngress("udp-ingress", map[string]string{},
+				corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			expected: nil,
+		},
+		{
+			title: "UDPIngress with ignored hostname annotation",
+			udpIngress: udpIngress("udp-ingress", map[string]string{
+				hostnameAnnotationKey: "a.example.com",
+			}, corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			ignoreHostnameAnnotation: true,
+			expected:                 nil,
+		},
+		{
+			title: "UDPIngress with target annotation",
+			udpIngress: udpIngress("udp-ingress", map[string]string{
+				hostnameAnnotationKey: "a.example.com",
+				targetAnnotationKey:   "203.0.113.1",
+			}, corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			kongProxyService: "kong/kong-proxy",
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("a.example.com", "A", "203.0.113.1"),
+			},
+		},
+		{
+			title: "UDPIngress with targets from the Kong proxy Service",
+			udpIngress: udpIngress("udp-ingress", map[string]string{
+				hostnameAnnotationKey: "a.example.com",
+			}, corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			kongProxyService: "kong/kong-proxy",
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("a.example.com", "A", "198.51.100.1"),
+			},
+		},
+		{
+			title: "UDPIngress with Kong proxy Service annotation",
+			udpIngress: udpIngress("udp-ingress", map[string]string{
+				hostnameAnnotationKey:         "a.example.com",
+				kongProxyServiceAnnotationKey: "kong-proxy",
+			}, corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("a.example.com", "A", "198.51.100.1"),
+			},
+		},
+		{
+			title: "UDPIngress not matching the annotation filter",
+			udpIngress: func() UDPIngress {
+				ui := udpIngress("udp-ingress", map[string]string{hostnameAnnotationKey: "a.example.com"},
+					corev1.LoadBalancerIngress{Hostname: "lb.example.com"})
+				ui.Annotations["kubernetes.io/ingress.class"] = "other"
+				return ui
+			}(),
+			expected: nil,
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			fakeKubernetesClient := fakeKube.NewSimpleClientset(newKongProxyService())
+			scheme := runtime.NewScheme()
+			scheme.AddKnownTypes(kongUDPIngressGVR.GroupVersion(), &UDPIngress{}, &UDPIngressList{})
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(scheme)
+
+			data, err := json.Marshal(ti.udpIngress)
+			require.NoError(t, err)
+			udpi := &unstructured.Unstructured{}
+			require.NoError(t, udpi.UnmarshalJSON(data))
+			_, err = fakeDynamicClient.Resource(kongUDPIngressGVR).Namespace(defaultKongNamespace).Create(context.Background(), udpi, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			source, err := NewKongUDPIngressSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultKongNamespace, "kubernetes.io/ingress.class=kong", ti.ignoreHostnameAnnotation, ti.kongProxyService)
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, ti.expected)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	openshift "github.com/openshift/client-go/route/clientset/versioned"
+	log "github.com/sirupsen/logrus"
+	istioclient "istio.io/client-go/pkg/clientset/versioned"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	gateway "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+)
+
+// MemberCluster is a cluster whose resources are read in addition to the ones of the cluster ExternalDNS runs in.
+type MemberCluster struct {
+	// Name identifies the cluster in the cluster label of its endpoints.
+	Name string
+	// KubeConfig is the path of the kubeconfig of the cluster.
+	KubeConfig string
+	// Context is the context of the kubeconfig to use, its current context when empty.
+	Context string
+}
+
+// ParseMemberClusters parses the member clusters in the form name=<name>,kubeconfig=<path>[,context=<context>].
+func ParseMemberClusters(values []string) ([]MemberCluster, error) {
+	members := make([]MemberCluster, 0, len(values))
+	names := map[string]bool{}
+	for _, value := range values {
+		var member MemberCluster
+		for _, field := range strings.Split(value, ",") {
+			key, val, _ := strings.Cut(field, "=")
+			switch strings.TrimSpace(key) {
+			case "name":
+				member.Name = strings.TrimSpace(val)
+			case "kubeconfig":
+				member.KubeConfig = strings.TrimSpace(val)
+			case "context":
+				member.Context = strings.TrimSpace(val)
+			default:
+				return nil, fmt.Errorf("invalid member cluster %q: unknown field %q", value, key)
+			}
+		}
+		if member.Name == "" || member.KubeConfig == "" {
+			return nil, fmt.Errorf("invalid member cluster %q: expected name=<name>,kubeconfig=<path>[,context=<context>]", value)
+		}
+		if names[member.Name] {
+			return nil, fmt.Errorf("duplicate member cluster %q", member.Name)
+		}
+		names[member.Name] = true
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// NewMemberClusterSources creates the sources of the given names for each member cluster. The endpoints of each
+// member cluster are labeled with its name as cluster.
+func NewMemberClusterSources(ctx context.Context, members []MemberCluster, names []string, cfg *Config, requestTimeout time.Duration) ([]Source, error) {
+	sources := make([]Source, 0, len(members))
+	for _, member := range members {
+		p := &memberClusterClientGenerator{member: member, requestTimeout: requestTimeout}
+		// The sources reading the kubeconfig themselves get the one of the member cluster.
+		memberCfg := *cfg
+		memberCfg.KubeConfig = member.KubeConfig
+		memberCfg.APIServerURL = ""
+		memberSources, err := ByNames(ctx, p, names, &memberCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the sources of member cluster %s: %w", member.Name, err)
+		}
+		log.Infof("Reading the resources of member cluster %s", member.Name)
+		sources = append(sources, NewClusterSource(NewMultiSource(memberSources, nil, ConflictPolicyNone), member.Name))
+	}
+	return sources, nil
+}
+
+// memberClusterClientGenerator is a ClientGenerator of the clients of a member cluster, which are created once.
+type memberClusterClientGenerator struct {
+	member         MemberCluster
+	requestTimeout time.Duration
+
+	configOnce      sync.Once
+	config          *rest.Config
+	configErr       error
+	kubeOnce        sync.Once
+	kubeClient      kubernetes.Interface
+	gatewayOnce     sync.Once
+	gatewayClient   gateway.Interface
+	istioOnce       sync.Once
+	istioClient     istioclient.Interface
+	dynCliOnce      sync.Once
+	dynKubeClient   dynamic.Interface
+	openshiftOnce   sync.Once
+	openshiftClient openshift.Interface
+}
+
+// restConfig returns the instrumented config of the context of the kubeconfig of the member cluster.
+func (p *memberClusterClientGenerator) restConfig() (*rest.Config, error) {
+	p.configOnce.Do(func() {
+		p.config, p.configErr = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: p.member.KubeConfig},
+			&clientcmd.ConfigOverrides{CurrentContext: p.member.Context},
+		).ClientConfig()
+		if p.configErr != nil {
+			p.configErr = fmt.Errorf("failed to load the kubeconfig of member cluster %s: %w", p.member.Name, p.configErr)
+			return
+		}
+		instrumentRESTConfig(p.config, p.requestTimeout)
+	})
+	return p.config, p.configErr
+}
+
+// KubeClient generates a kube client of the member cluster if it was not created before
+func (p *memberClusterClientGenerator) KubeClient() (kubernetes.Interface, error) {
+	var err error
+	p.kubeOnce.Do(func() {
+		var config *rest.Config
+		if config, err = p.restConfig(); err == nil {
+			p.kubeClient, err = kubernetes.NewForConfig(config)
+		}
+	})
+	return p.kubeClient, err
+}
+
+// GatewayClient generates a gateway client of the member cluster if it was not created before
+func (p *memberClusterClientGenerator) GatewayClient() (gateway.Interface, error) {
+	var err error
+	p.gatewayOnce.Do(func() {
+		var config *rest.Config
+		if config, err = p.restConfig(); err == nil {
+			p.gatewayClient, err = gateway.NewForConfig(config)
+		}
+	})
+	return p.gatewayClient, err
+}
+
+// IstioClient generates an istio client of the member cluster if it was not created before
+func (p *memberClusterClientGenerator) IstioClient() (istioclient.Interface, error) {
+	var err error
+	p.istioOnce.Do(func() {
+		var config *rest.Config
+		if config, err = p.restConfig(); err == nil {
+			p.istioClient, err = istioclient.NewForConfig(config)
+		}
+	})
+	return p.istioClient, err
+}
+
+// CloudFoundryClient isn't supported for member clusters, Cloud Foundry isn't a Kubernetes cluster.
+func (p *memberClusterClientGenerator) CloudFoundryClient(cfAPIEndpoint string, cfUsername string, cfPassword string) (*cfclient.Client, error) {
+	return nil, errors.New("the cloudfoundry source is not supported for member clusters")
+}
+
+// DynamicKubernetesClient generates a dynamic client of the member cluster if it was not created before
+func (p *memberClusterClientGenerator) DynamicKubernetesClient() (dynamic.Interface, error) {
+	var err error
+	p.dynCliOnce.Do(func() {
+		var config *rest.Config
+		if config, err = p.restConfig(); err == nil {
+			p.dynKubeClient, err = dynamic.NewForConfig(config)
+		}
+	})
+	return p.dynKubeClient, err
+}
+
+// OpenShiftClient generates an openshift client of the member cluster if it was not created before
+func (p *memberClusterClientGenerator) OpenShiftClient() (openshift.Interface, error) {
+	var err error
+	p.openshiftOnce.Do(func() {
+		var config *rest.Config
+		if config, err = p.restConfig(); err == nil {
+			p.openshiftClient, err = openshift.NewForConfig(config)
+		}
+	})
+	return p.openshiftClient, err
+}
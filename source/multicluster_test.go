@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+const testFleetKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: eu
+  cluster:
+    server: https://eu.example.com:6443
+- name: us
+  cluster:
+    server: https://us.example.com:6443
+users:
+- name: external-dns
+  user:
+    token: secret
+contexts:
+- name: eu
+  context:
+    cluster: eu
+    user: external-dns
+- name: us
+  context:
+    cluster: us
+    user: external-dns
+current-context: eu
+`
+
+func TestParseMemberClusters(t *testing.T) {
+	for _, tt := range []struct {
+		title    string
+		values   []string
+		expected []MemberCluster
+		err      bool
+	}{
+		{
+			title:    "none",
+			expected: []MemberCluster{},
+		},
+		{
+			title:  "with and without context",
+			values: []string{"name=eu,kubeconfig=/etc/kubeconfigs/eu", "name=us, kubeconfig=/etc/kubeconfigs/fleet, context=us"},
+			expected: []MemberCluster{
+				{Name: "eu", KubeConfig: "/etc/kubeconfigs/eu"},
+				{Name: "us", KubeConfig: "/etc/kubeconfigs/fleet", Context: "us"},
+			},
+		},
+		{
+			title:  "missing kubeconfig",
+			values: []string{"name=eu"},
+			err:    true,
+		},
+		{
+			title:  "unknown field",
+			values: []string{"name=eu,kubeconfig=/etc/kubeconfigs/eu,namespace=default"},
+			err:    true,
+		},
+		{
+			title:  "duplicate name",
+			values: []string{"name=eu,kubeconfig=/etc/kubeconfigs/eu", "name=eu,kubeconfig=/etc/kubeconfigs/fleet"},
+			err:    true,
+		},
+	} {
+		t.Run(tt.title, func(t *testing.T) {
+			members, err := ParseMemberClusters(tt.values)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, members)
+		})
+	}
+}
+
+func TestMemberClusterClientGeneratorContext(t *testing.T) {
+	kubeConfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeConfig, []byte(testFleetKubeConfig), 0o600))
+
+	for _, tt := range []struct {
+		context string
+		host    string
+	}{
+		{context: "", host: "https://eu.example.com:6443"},
+		{context: "us", host: "https://us.example.com:6443"},
+	} {
+		p := &memberClusterClientGenerator{
+			member:         MemberCluster{Name: "fleet", KubeConfig: kubeConfig, Context: tt.context},
+			requestTimeout: 5 * time.Second,
+		}
+		config, err := p.restConfig()
+		require.NoError(t, err)
+		assert.Equal(t, tt.host, config.Host)
+		assert.Equal(t, 5*time.Second, config.Timeout)
+
+		_, err = p.KubeClient()
+		assert.NoError(t, err)
+	}
+
+	p := &memberClusterClientGenerator{member: MemberCluster{Name: "missing", KubeConfig: filepath.Join(t.TempDir(), "missing")}}
+	_, err := p.KubeClient()
+	assert.Error(t, err)
+}
+
+func TestMemberClusterSources(t *testing.T) {
+	kubeConfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeConfig, []byte(testFleetKubeConfig), 0o600))
+
+	members := []MemberCluster{
+		{Name: "eu", KubeConfig: kubeConfig, Context: "eu"},
+		{Name: "us", KubeConfig: kubeConfig, Context: "us"},
+	}
+	sources, err := NewMemberClusterSources(context.Background(), members, []string{"fake"}, &Config{FQDNTemplate: "{{.Name}}.example.com"}, 0)
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+
+	// The cluster label of the member clusters takes precedence over the one of the cluster ExternalDNS runs in.
+	for i, name := range []string{"eu", "us"} {
+		endpoints, err := NewClusterSource(sources[i], "local").Endpoints(context.Background())
+		require.NoError(t, err)
+		require.NotEmpty(t, endpoints)
+		for _, ep := range endpoints {
+			assert.Equal(t, name, ep.Labels[endpoint.ClusterLabelKey])
+		}
+	}
+}
@@ -22,35 +22,39 @@ import (
 	"sigs.k8s.io/external-dns/endpoint"
 )
 
-// multiSource is a Source that merges the endpoints of its nested Sources.
+// multiSource is a Source that merges the endpoints of its nested Sources, resolving the conflicts between
+// them according to the conflict policy.
 type multiSource struct {
 	children       []Source
 	defaultTargets []string
+	conflictPolicy string
 }
 
 // Endpoints collects endpoints of all nested Sources and returns them in a single slice.
 func (ms *multiSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
-	result := []*endpoint.Endpoint{}
+	result := []sourceEndpoint{}
 
-	for _, s := range ms.children {
+	for i, s := range ms.children {
 		endpoints, err := s.Endpoints(ctx)
 		if err != nil {
 			return nil, err
 		}
 		if len(ms.defaultTargets) > 0 {
-			for i := range endpoints {
-				eps := endpointsForHostname(endpoints[i].DNSName, ms.defaultTargets, endpoints[i].RecordTTL, endpoints[i].ProviderSpecific, endpoints[i].SetIdentifier, "")
+			for j := range endpoints {
+				eps := endpointsForHostname(endpoints[j].DNSName, ms.defaultTargets, endpoints[j].RecordTTL, endpoints[j].ProviderSpecific, endpoints[j].SetIdentifier, "")
 				for _, ep := range eps {
-					ep.Labels = endpoints[i].Labels
+					ep.Labels = endpoints[j].Labels
+					result = append(result, sourceEndpoint{endpoint: ep, source: i})
 				}
-				result = append(result, eps...)
 			}
 		} else {
-			result = append(result, endpoints...)
+			for _, ep := range endpoints {
+				result = append(result, sourceEndpoint{endpoint: ep, source: i})
+			}
 		}
 	}
 
-	return result, nil
+	return resolveConflicts(result, ms.conflictPolicy)
 }
 
 func (ms *multiSource) AddEventHandler(ctx context.Context, handler func()) {
@@ -60,6 +64,6 @@ func (ms *multiSource) AddEventHandler(ctx context.Context, handler func()) {
 }
 
 // NewMultiSource creates a new multiSource.
-func NewMultiSource(children []Source, defaultTargets []string) Source {
-	return &multiSource{children: children, defaultTargets: defaultTargets}
+func NewMultiSource(children []Source, defaultTargets []string, conflictPolicy string) Source {
+	return &multiSource{children: children, defaultTargets: defaultTargets, conflictPolicy: conflictPolicy}
 }
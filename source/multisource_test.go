@@ -89,7 +89,7 @@ func testMultiSourceEndpoints(t *testing.T) {
 			}
 
 			// Create our object under test and get the endpoints.
-			source := NewMultiSource(sources, nil)
+			source := NewMultiSource(sources, nil, ConflictPolicyNone)
 
 			// Get endpoints from the source.
 			endpoints, err := source.Endpoints(context.Background())
@@ -116,7 +116,7 @@ func testMultiSourceEndpointsWithError(t *testing.T) {
 	src.On("Endpoints").Return(nil, errSomeError)
 
 	// Create our object under test and get the endpoints.
-	source := NewMultiSource([]Source{src}, nil)
+	source := NewMultiSource([]Source{src}, nil, ConflictPolicyNone)
 
 	// Get endpoints from our source.
 	_, err := source.Endpoints(context.Background())
@@ -156,7 +156,7 @@ func testMultiSourceEndpointsDefaultTargets(t *testing.T) {
 	src.On("Endpoints").Return(sourceEndpoints, nil)
 
 	// Create our object under test with non-empty defaultTargets and get the endpoints.
-	source := NewMultiSource([]Source{src}, defaultTargets)
+	source := NewMultiSource([]Source{src}, defaultTargets, ConflictPolicyNone)
 
 	// Get endpoints from our source.
 	endpoints, err := source.Endpoints(context.Background())
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// nat64PrefixBits is the length of the NAT64 prefixes supported, which embed the IPv4 address in the last 32 bits
+// of the IPv6 address, like the well-known prefix 64:ff9b::/96 of RFC 6052.
+const nat64PrefixBits = 96
+
+// nat64Source is a Source that adapts the endpoints of its wrapped source to IPv6-only clusters behind NAT64.
+// It either synthesizes A endpoints from the AAAA targets within the NAT64 prefixes, or drops all A endpoints.
+type nat64Source struct {
+	source    Source
+	prefixes  []netip.Prefix
+	suppressA bool
+}
+
+// ParseNAT64Prefixes parses NAT64 prefixes, which must be /96 prefixes.
+func ParseNAT64Prefixes(networks []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(networks))
+	for _, network := range networks {
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NAT64 prefix %q: %w", network, err)
+		}
+		if !prefix.Addr().Is6() || prefix.Addr().Is4In6() || prefix.Bits() != nat64PrefixBits {
+			return nil, fmt.Errorf("the NAT64 prefix %s is not an IPv6 /%d prefix", network, nat64PrefixBits)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// NewNAT64Source creates a new nat64Source wrapping the provided Source, synthesizing A endpoints from the AAAA
+// targets within the prefixes, or dropping all A endpoints when suppressA is set.
+func NewNAT64Source(source Source, prefixes []netip.Prefix, suppressA bool) Source {
+	return &nat64Source{source: source, prefixes: prefixes, suppressA: suppressA}
+}
+
+// Endpoints collects endpoints from its wrapped source and adds an A endpoint for every AAAA endpoint with targets
+// within the NAT64 prefixes, holding the IPv4 addresses embedded in these targets. With suppressA, the A endpoints
+// are dropped instead.
+func (ns *nat64Source) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := ns.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	var synthesized []*endpoint.Endpoint
+	for _, ep := range endpoints {
+		if ep.RecordType == endpoint.RecordTypeA && ns.suppressA {
+			log.WithField("endpoint", ep).Debugf("Skipping A endpoint in an IPv6-only cluster")
+			continue
+		}
+		result = append(result, ep)
+		if ep.RecordType != endpoint.RecordTypeAAAA {
+			continue
+		}
+
+		targets := endpoint.Targets{}
+		for _, target := range ep.Targets {
+			if v4, ok := ns.embeddedIPv4(target); ok {
+				targets = append(targets, v4)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		v4 := ep.DeepCopy()
+		v4.RecordType = endpoint.RecordTypeA
+		v4.Targets = targets
+		synthesized = append(synthesized, v4)
+	}
+	return append(result, synthesized...), nil
+}
+
+// embeddedIPv4 returns the IPv4 address embedded in the target, if it is within one of the NAT64 prefixes.
+func (ns *nat64Source) embeddedIPv4(target string) (string, bool) {
+	addr, err := netip.ParseAddr(target)
+	if err != nil {
+		return "", false
+	}
+	for _, prefix := range ns.prefixes {
+		if prefix.Contains(addr) {
+			b := addr.As16()
+			return netip.AddrFrom4([4]byte(b[12:16])).String(), true
+		}
+	}
+	return "", false
+}
+
+func (ns *nat64Source) AddEventHandler(ctx context.Context, handler func()) {
+	ns.source.AddEventHandler(ctx, handler)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestNAT64Source(t *testing.T) {
+	prefixes, err := ParseNAT64Prefixes([]string{"64:ff9b::/96", "2001:db8:64::/96"})
+	require.NoError(t, err)
+	src := NewNAT64Source(NewEchoSource([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("web.example.org", endpoint.RecordTypeAAAA, "64:ff9b::c000:201", "2001:db8:64::cb00:7101", "2001:db8::1"),
+		endpoint.NewEndpoint("v6.example.org", endpoint.RecordTypeAAAA, "2001:db8::2"),
+		endpoint.NewEndpoint("v4.example.org", endpoint.RecordTypeA, "192.0.2.10"),
+	}), prefixes, false)
+
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("web.example.org", endpoint.RecordTypeAAAA, "64:ff9b::c000:201", "2001:db8:64::cb00:7101", "2001:db8::1"),
+		endpoint.NewEndpoint("v6.example.org", endpoint.RecordTypeAAAA, "2001:db8::2"),
+		endpoint.NewEndpoint("v4.example.org", endpoint.RecordTypeA, "192.0.2.10"),
+		endpoint.NewEndpoint("web.example.org", endpoint.RecordTypeA, "192.0.2.1", "203.0.113.1"),
+	})
+}
+
+func TestNAT64SourceSuppressA(t *testing.T) {
+	src := NewNAT64Source(NewEchoSource([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("web.example.org", endpoint.RecordTypeAAAA, "2001:db8::1"),
+		endpoint.NewEndpoint("web.example.org", endpoint.RecordTypeA, "192.0.2.10"),
+		endpoint.NewEndpoint("alias.example.org", endpoint.RecordTypeCNAME, "web.example.org"),
+	}), nil, true)
+
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("web.example.org", endpoint.RecordTypeAAAA, "2001:db8::1"),
+		endpoint.NewEndpoint("alias.example.org", endpoint.RecordTypeCNAME, "web.example.org"),
+	})
+}
+
+func TestParseNAT64Prefixes(t *testing.T) {
+	for _, network := range []string{"64:ff9b::/64", "192.0.2.0/24", "::ffff:0:0/96", "invalid"} {
+		_, err := ParseNAT64Prefixes([]string{network})
+		assert.Error(t, err, network)
+	}
+}
@@ -19,13 +19,14 @@ package source
 import (
 	"context"
 	"fmt"
+	"strings"
 	"text/template"
 
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	kubeinformers "k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -34,23 +35,41 @@ import (
 )
 
 type nodeSource struct {
-	client           kubernetes.Interface
-	annotationFilter string
-	fqdnTemplate     *template.Template
-	nodeInformer     coreinformers.NodeInformer
-	labelSelector    labels.Selector
+	client               kubernetes.Interface
+	annotationFilter     string
+	fqdnTemplate         *template.Template
+	nodeInformer         coreinformers.NodeInformer
+	labelSelector        labels.Selector
+	sshfp                bool
+	sshfpSecret          string
+	nodeSelector         labels.Selector
+	excludeNotReady      bool
+	excludeUnschedulable bool
+	ipv4AddressType      v1.NodeAddressType
+	ipv6AddressType      v1.NodeAddressType
 }
 
-// NewNodeSource creates a new nodeSource with the given config.
-func NewNodeSource(ctx context.Context, kubeClient kubernetes.Interface, annotationFilter, fqdnTemplate string, labelSelector labels.Selector) (Source, error) {
+// NewNodeSource creates a new nodeSource with the given config. With sshfp, SSHFP records are created from the SSH
+// host keys of the nodes, read from their ssh-host-keys annotation or, if they have none, from the entry named after
+// them in sshfpSecret, given as <namespace>/<name>, if set.
+//
+// Only the nodes matching nodeSelector are considered, and with excludeNotReady and excludeUnschedulable the nodes
+// that are not ready or cordoned are skipped. ipv4AddressType and ipv6AddressType, ExternalIP or InternalIP, choose the
+// type of the node addresses preferred for each address family; when both are empty, the external addresses are
+// preferred along with the internal IPv6 addresses.
+func NewNodeSource(ctx context.Context, kubeClient kubernetes.Interface, annotationFilter, fqdnTemplate string, labelSelector labels.Selector, sshfp bool, sshfpSecret string, nodeSelector labels.Selector, excludeNotReady, excludeUnschedulable bool, ipv4AddressType, ipv6AddressType string) (Source, error) {
 	tmpl, err := parseTemplate(fqdnTemplate)
 	if err != nil {
 		return nil, err
 	}
 
+	if nodeSelector == nil {
+		nodeSelector = labels.Everything()
+	}
+
 	// Use shared informers to listen for add/update/delete of nodes.
 	// Set resync period to 0, to prevent processing when nothing has changed
-	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0)
+	informerFactory := sharedKubeInformerFactory(ctx, kubeClient, "")
 	nodeInformer := informerFactory.Core().V1().Nodes()
 
 	// Add default resource event handler to properly initialize informer.
@@ -70,11 +89,18 @@ func NewNodeSource(ctx context.Context, kubeClient kubernetes.Interface, annotat
 	}
 
 	return &nodeSource{
-		client:           kubeClient,
-		annotationFilter: annotationFilter,
-		fqdnTemplate:     tmpl,
-		nodeInformer:     nodeInformer,
-		labelSelector:    labelSelector,
+		client:               kubeClient,
+		annotationFilter:     annotationFilter,
+		fqdnTemplate:         tmpl,
+		nodeInformer:         nodeInformer,
+		labelSelector:        labelSelector,
+		sshfp:                sshfp,
+		sshfpSecret:          sshfpSecret,
+		nodeSelector:         nodeSelector,
+		excludeNotReady:      excludeNotReady,
+		excludeUnschedulable: excludeUnschedulable,
+		ipv4AddressType:      v1.NodeAddressType(ipv4AddressType),
+		ipv6AddressType:      v1.NodeAddressType(ipv6AddressType),
 	}, nil
 }
 
@@ -90,6 +116,13 @@ func (ns *nodeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 		return nil, err
 	}
 
+	var hostKeys map[string][]byte
+	if ns.sshfp && ns.sshfpSecret != "" {
+		if hostKeys, err = ns.sshHostKeysSecret(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	endpoints := map[endpoint.EndpointKey]*endpoint.Endpoint{}
 
 	// create endpoints for all nodes
@@ -102,6 +135,19 @@ func (ns *nodeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 			continue
 		}
 
+		if !ns.nodeSelector.Matches(labels.Set(node.Labels)) {
+			log.Debugf("Skipping node %s because it does not match the node label filter", node.Name)
+			continue
+		}
+		if ns.excludeNotReady && !nodeReady(node) {
+			log.Debugf("Skipping node %s because it is not ready", node.Name)
+			continue
+		}
+		if ns.excludeUnschedulable && node.Spec.Unschedulable {
+			log.Debugf("Skipping node %s because it is unschedulable", node.Name)
+			continue
+		}
+
 		log.Debugf("creating endpoint for node %s", node.Name)
 
 		ttl := getTTLFromAnnotations(node.Annotations, fmt.Sprintf("node/%s", node.Name))
@@ -149,6 +195,28 @@ func (ns *nodeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 			}
 			endpoints[key].Targets = append(endpoints[key].Targets, addr)
 		}
+
+		if ns.sshfp {
+			keys, ok := node.Annotations[sshHostKeysAnnotationKey]
+			if !ok {
+				keys = string(hostKeys[node.Name])
+			}
+			targets, err := sshfpTargets(keys)
+			if err != nil {
+				log.Warnf("Skipping the SSHFP records of node %s: %v", node.Name, err)
+				continue
+			}
+			if len(targets) == 0 {
+				continue
+			}
+			key := endpoint.EndpointKey{DNSName: ep.DNSName, RecordType: endpoint.RecordTypeSSHFP}
+			if _, ok := endpoints[key]; !ok {
+				epCopy := *ep
+				epCopy.RecordType = key.RecordType
+				endpoints[key] = &epCopy
+			}
+			endpoints[key].Targets = append(endpoints[key].Targets, targets...)
+		}
 	}
 
 	endpointsSlice := []*endpoint.Endpoint{}
@@ -162,9 +230,40 @@ func (ns *nodeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 func (ns *nodeSource) AddEventHandler(ctx context.Context, handler func()) {
 }
 
+// sshHostKeysSecret returns the SSH host keys of the nodes held by the secret, by node name.
+func (ns *nodeSource) sshHostKeysSecret(ctx context.Context) (map[string][]byte, error) {
+	namespace, name, found := strings.Cut(ns.sshfpSecret, "/")
+	if !found {
+		return nil, fmt.Errorf("invalid SSH host keys secret %q, expected <namespace>/<name>", ns.sshfpSecret)
+	}
+	secret, err := ns.client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		log.Warnf("The SSH host keys secret %s does not exist", ns.sshfpSecret)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the SSH host keys secret %s: %w", ns.sshfpSecret, err)
+	}
+	return secret.Data, nil
+}
+
+// nodeReady reports whether the Ready condition of the node is True.
+func nodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
 // nodeAddress returns node's externalIP and if that's not found, node's internalIP
 // basically what k8s.io/kubernetes/pkg/util/node.GetPreferredNodeAddress does
 func (ns *nodeSource) nodeAddresses(node *v1.Node) ([]string, error) {
+	if ns.ipv4AddressType != "" || ns.ipv6AddressType != "" {
+		return ns.preferredNodeAddresses(node)
+	}
+
 	addresses := map[v1.NodeAddressType][]string{
 		v1.NodeExternalIP: {},
 		v1.NodeInternalIP: {},
@@ -190,6 +289,50 @@ func (ns *nodeSource) nodeAddresses(node *v1.Node) ([]string, error) {
 	return nil, fmt.Errorf("could not find node address for %s", node.Name)
 }
 
+// preferredNodeAddresses returns the node addresses of each address family of the type preferred for it, or of the
+// other type if the node has none. Without preference, the IPv4 external addresses are preferred and the IPv6
+// addresses of both types are returned.
+func (ns *nodeSource) preferredNodeAddresses(node *v1.Node) ([]string, error) {
+	var targets []string
+	for _, family := range []struct {
+		recordType string
+		preferred  v1.NodeAddressType
+	}{
+		{endpoint.RecordTypeA, ns.ipv4AddressType},
+		{endpoint.RecordTypeAAAA, ns.ipv6AddressType},
+	} {
+		addresses := map[v1.NodeAddressType][]string{}
+		for _, addr := range node.Status.Addresses {
+			if suitableType(addr.Address) == family.recordType {
+				addresses[addr.Type] = append(addresses[addr.Type], addr.Address)
+			}
+		}
+
+		preferred, other := family.preferred, v1.NodeInternalIP
+		if preferred == "" && family.recordType == endpoint.RecordTypeA {
+			preferred = v1.NodeExternalIP
+		}
+		if preferred == v1.NodeInternalIP {
+			other = v1.NodeExternalIP
+		}
+
+		switch {
+		case preferred == "":
+			targets = append(targets, addresses[v1.NodeExternalIP]...)
+			targets = append(targets, addresses[v1.NodeInternalIP]...)
+		case len(addresses[preferred]) > 0:
+			targets = append(targets, addresses[preferred]...)
+		default:
+			targets = append(targets, addresses[other]...)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("could not find node address for %s", node.Name)
+	}
+	return targets, nil
+}
+
 // filterByAnnotations filters a list of nodes by a given annotation selector.
 func (ns *nodeSource) filterByAnnotations(nodes []*v1.Node) ([]*v1.Node, error) {
 	labelSelector, err := metav1.ParseToLabelSelector(ns.annotationFilter)
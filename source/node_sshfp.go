@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// sshfpAlgorithms are the SSHFP algorithm numbers of the types of SSH host keys, see RFC 4255, 6594 and 7479.
+var sshfpAlgorithms = map[string]int{
+	"ssh-rsa":             1,
+	"ssh-dss":             2,
+	"ecdsa-sha2-nistp256": 3,
+	"ecdsa-sha2-nistp384": 3,
+	"ecdsa-sha2-nistp521": 3,
+	"ssh-ed25519":         4,
+	"ssh-ed448":           6,
+}
+
+// sshfpSHA256 is the SSHFP fingerprint type of SHA-256 fingerprints.
+const sshfpSHA256 = 2
+
+// sshfpRecordRegex matches SSHFP records in the form "<algorithm> <fingerprint type> <fingerprint>".
+var sshfpRecordRegex = regexp.MustCompile(`^[0-9]+\s+[0-9]+\s+[0-9a-fA-F]+$`)
+
+// sshfpTargets returns the targets of the SSHFP records of SSH host keys, given one per line in the format of
+// OpenSSH public key files, e.g. "ssh-ed25519 AAAAC3Nza... root@node", or as SSHFP records, e.g. "4 2 9a1f...".
+// The SHA-256 fingerprints of the public keys are published. Empty lines and comments are ignored.
+func sshfpTargets(hostKeys string) (endpoint.Targets, error) {
+	targets := endpoint.Targets{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(hostKeys, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		var target string
+		if sshfpRecordRegex.MatchString(line) {
+			target = fmt.Sprintf("%s %s %s", fields[0], fields[1], strings.ToLower(fields[2]))
+		} else {
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("invalid SSH host key %q", line)
+			}
+			algorithm, ok := sshfpAlgorithms[fields[0]]
+			if !ok {
+				return nil, fmt.Errorf("unsupported SSH host key type %s", fields[0])
+			}
+			key, err := base64.StdEncoding.DecodeString(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid SSH host key of type %s: %w", fields[0], err)
+			}
+			sum := sha256.Sum256(key)
+			target = fmt.Sprintf("%d %d %s", algorithm, sshfpSHA256, hex.EncodeToString(sum[:]))
+		}
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	sort.Sort(targets)
+	return targets, nil
+}
@@ -77,6 +77,13 @@ func testNodeSourceNewNodeSource(t *testing.T) {
 				ti.annotationFilter,
 				ti.fqdnTemplate,
 				labels.Everything(),
+				false,
+				"",
+				nil,
+				false,
+				false,
+				"",
+				"",
 			)
 
 			if ti.expectError {
@@ -357,6 +364,13 @@ func testNodeSourceEndpoints(t *testing.T) {
 				tc.annotationFilter,
 				tc.fqdnTemplate,
 				labelSelector,
+				false,
+				"",
+				nil,
+				false,
+				false,
+				"",
+				"",
 			)
 			require.NoError(t, err)
 
@@ -372,3 +386,313 @@ func testNodeSourceEndpoints(t *testing.T) {
 		})
 	}
 }
+
+const (
+	testSSHEd25519Key         = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAABAgMEBQYHCAkKCwwNDg8QERITFBUWFxgZGhscHR4f root@node1"
+	testSSHEd25519Fingerprint = "4 2 66402c9468c58941dd19ffd650bf2b42f9226f83d3bd06ad515d0e5104a77020"
+	testSSHECDSAKey           = "ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAABBAAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8gISIjJCUmJygpKissLS4vMDEyMzQ1Njc4OTo7PD0+P0A="
+	testSSHECDSAFingerprint   = "3 2 9204f63878cdd5c4fd4802f3a313d65cbc89e4c49c29091779b6f851294a5bcf"
+)
+
+func TestSSHFPTargets(t *testing.T) {
+	for _, tc := range []struct {
+		title     string
+		hostKeys  string
+		expected  endpoint.Targets
+		expectErr bool
+	}{
+		{
+			title:    "empty",
+			expected: endpoint.Targets{},
+		},
+		{
+			title:    "public keys",
+			hostKeys: testSSHEd25519Key + "\n\n# comment\n" + testSSHECDSAKey + "\n",
+			expected: endpoint.Targets{testSSHECDSAFingerprint, testSSHEd25519Fingerprint},
+		},
+		{
+			title:    "SSHFP records",
+			hostKeys: "4 2 ABCDEF0123\n1 1 0123456789abcdef\n4 2 abcdef0123",
+			expected: endpoint.Targets{"1 1 0123456789abcdef", "4 2 abcdef0123"},
+		},
+		{
+			title:     "unsupported key type",
+			hostKeys:  "ssh-foo AAAA",
+			expectErr: true,
+		},
+		{
+			title:     "invalid key",
+			hostKeys:  "ssh-ed25519 !!!",
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			targets, err := sshfpTargets(tc.hostKeys)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, targets)
+		})
+	}
+}
+
+func TestNodeSourceSSHFP(t *testing.T) {
+	kubernetes := fake.NewSimpleClientset()
+
+	for _, node := range []*v1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "node1",
+				Annotations: map[string]string{sshHostKeysAnnotationKey: testSSHEd25519Key},
+			},
+			Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "1.2.3.4"}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+			Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "1.2.3.5"}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "node3",
+				Annotations: map[string]string{sshHostKeysAnnotationKey: "ssh-foo AAAA"},
+			},
+			Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "1.2.3.6"}}},
+		},
+	} {
+		_, err := kubernetes.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	_, err := kubernetes.CoreV1().Secrets("kube-system").Create(context.Background(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "ssh-host-keys"},
+		Data: map[string][]byte{
+			"node1": []byte(testSSHECDSAKey),
+			"node2": []byte(testSSHECDSAKey),
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		title       string
+		sshfp       bool
+		sshfpSecret string
+		expected    []*endpoint.Endpoint
+	}{
+		{
+			title: "disabled",
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+				{RecordType: "A", DNSName: "node2", Targets: endpoint.Targets{"1.2.3.5"}},
+				{RecordType: "A", DNSName: "node3", Targets: endpoint.Targets{"1.2.3.6"}},
+			},
+		},
+		{
+			title: "annotations",
+			sshfp: true,
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+				{RecordType: "SSHFP", DNSName: "node1", Targets: endpoint.Targets{testSSHEd25519Fingerprint}},
+				{RecordType: "A", DNSName: "node2", Targets: endpoint.Targets{"1.2.3.5"}},
+				{RecordType: "A", DNSName: "node3", Targets: endpoint.Targets{"1.2.3.6"}},
+			},
+		},
+		{
+			title:       "annotations take precedence over the secret",
+			sshfp:       true,
+			sshfpSecret: "kube-system/ssh-host-keys",
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+				{RecordType: "SSHFP", DNSName: "node1", Targets: endpoint.Targets{testSSHEd25519Fingerprint}},
+				{RecordType: "A", DNSName: "node2", Targets: endpoint.Targets{"1.2.3.5"}},
+				{RecordType: "SSHFP", DNSName: "node2", Targets: endpoint.Targets{testSSHECDSAFingerprint}},
+				{RecordType: "A", DNSName: "node3", Targets: endpoint.Targets{"1.2.3.6"}},
+			},
+		},
+		{
+			title:       "missing secret",
+			sshfp:       true,
+			sshfpSecret: "kube-system/missing",
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+				{RecordType: "SSHFP", DNSName: "node1", Targets: endpoint.Targets{testSSHEd25519Fingerprint}},
+				{RecordType: "A", DNSName: "node2", Targets: endpoint.Targets{"1.2.3.5"}},
+				{RecordType: "A", DNSName: "node3", Targets: endpoint.Targets{"1.2.3.6"}},
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			client, err := NewNodeSource(context.TODO(), kubernetes, "", "", labels.Everything(), tc.sshfp, tc.sshfpSecret, nil, false, false, "", "")
+			require.NoError(t, err)
+
+			endpoints, err := client.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, tc.expected)
+		})
+	}
+}
+
+func TestNodeSourceNodeFilters(t *testing.T) {
+	kubernetes := fake.NewSimpleClientset()
+
+	ready := []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}
+	for _, node := range []*v1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"role": "worker"}},
+			Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "1.2.3.4"}}, Conditions: ready},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"role": "worker"}},
+			Status: v1.NodeStatus{
+				Addresses:  []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "1.2.3.5"}},
+				Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionUnknown}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node3", Labels: map[string]string{"role": "worker"}},
+			Spec:       v1.NodeSpec{Unschedulable: true},
+			Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "1.2.3.6"}}, Conditions: ready},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node4", Labels: map[string]string{"role": "control-plane"}},
+			Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "1.2.3.7"}}, Conditions: ready},
+		},
+	} {
+		_, err := kubernetes.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	for _, tc := range []struct {
+		title                string
+		nodeSelector         string
+		excludeNotReady      bool
+		excludeUnschedulable bool
+		expected             []*endpoint.Endpoint
+	}{
+		{
+			title: "all nodes",
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+				{RecordType: "A", DNSName: "node2", Targets: endpoint.Targets{"1.2.3.5"}},
+				{RecordType: "A", DNSName: "node3", Targets: endpoint.Targets{"1.2.3.6"}},
+				{RecordType: "A", DNSName: "node4", Targets: endpoint.Targets{"1.2.3.7"}},
+			},
+		},
+		{
+			title:        "nodes matching the node selector",
+			nodeSelector: "role=worker",
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+				{RecordType: "A", DNSName: "node2", Targets: endpoint.Targets{"1.2.3.5"}},
+				{RecordType: "A", DNSName: "node3", Targets: endpoint.Targets{"1.2.3.6"}},
+			},
+		},
+		{
+			title:           "ready nodes",
+			excludeNotReady: true,
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+				{RecordType: "A", DNSName: "node3", Targets: endpoint.Targets{"1.2.3.6"}},
+				{RecordType: "A", DNSName: "node4", Targets: endpoint.Targets{"1.2.3.7"}},
+			},
+		},
+		{
+			title:                "ready schedulable workers",
+			nodeSelector:         "role=worker",
+			excludeNotReady:      true,
+			excludeUnschedulable: true,
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			nodeSelector, err := labels.Parse(tc.nodeSelector)
+			require.NoError(t, err)
+
+			client, err := NewNodeSource(context.TODO(), kubernetes, "", "", labels.Everything(), false, "", nodeSelector, tc.excludeNotReady, tc.excludeUnschedulable, "", "")
+			require.NoError(t, err)
+
+			endpoints, err := client.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, tc.expected)
+		})
+	}
+}
+
+func TestNodeSourceAddressTypes(t *testing.T) {
+	for _, tc := range []struct {
+		title           string
+		ipv4AddressType string
+		ipv6AddressType string
+		nodeAddresses   []v1.NodeAddress
+		expected        []*endpoint.Endpoint
+	}{
+		{
+			title:           "internal IPv4 addresses preferred",
+			ipv4AddressType: "InternalIP",
+			nodeAddresses: []v1.NodeAddress{
+				{Type: v1.NodeExternalIP, Address: "1.2.3.4"},
+				{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+				{Type: v1.NodeInternalIP, Address: "2001:db8::1"},
+			},
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"10.0.0.1"}},
+				{RecordType: "AAAA", DNSName: "node1", Targets: endpoint.Targets{"2001:db8::1"}},
+			},
+		},
+		{
+			title:           "external IPv4 addresses preferred falling back to internal ones",
+			ipv4AddressType: "ExternalIP",
+			nodeAddresses: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+				{Type: v1.NodeExternalIP, Address: "2001:db8::2"},
+			},
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"10.0.0.1"}},
+				{RecordType: "AAAA", DNSName: "node1", Targets: endpoint.Targets{"2001:db8::2"}},
+			},
+		},
+		{
+			title:           "external IPv6 addresses preferred",
+			ipv6AddressType: "ExternalIP",
+			nodeAddresses: []v1.NodeAddress{
+				{Type: v1.NodeExternalIP, Address: "1.2.3.4"},
+				{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+				{Type: v1.NodeInternalIP, Address: "2001:db8::1"},
+				{Type: v1.NodeExternalIP, Address: "2001:db8::2"},
+			},
+			expected: []*endpoint.Endpoint{
+				{RecordType: "A", DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+				{RecordType: "AAAA", DNSName: "node1", Targets: endpoint.Targets{"2001:db8::2"}},
+			},
+		},
+		{
+			title:           "internal IPv6 addresses preferred",
+			ipv6AddressType: "InternalIP",
+			nodeAddresses: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "2001:db8::1"},
+				{Type: v1.NodeExternalIP, Address: "2001:db8::2"},
+			},
+			expected: []*endpoint.Endpoint{
+				{RecordType: "AAAA", DNSName: "node1", Targets: endpoint.Targets{"2001:db8::1"}},
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			kubernetes := fake.NewSimpleClientset()
+			_, err := kubernetes.CoreV1().Nodes().Create(context.Background(), &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Status:     v1.NodeStatus{Addresses: tc.nodeAddresses},
+			}, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			client, err := NewNodeSource(context.TODO(), kubernetes, "", "", labels.Everything(), false, "", nil, false, false, tc.ipv4AddressType, tc.ipv6AddressType)
+			require.NoError(t, err)
+
+			endpoints, err := client.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, tc.expected)
+		})
+	}
+}
@@ -136,6 +136,14 @@ func (ors *ocpRouteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint,
 			continue
 		}
 
+		// Routes which the given router rejected or didn't admit yet, such as the routes of another router
+		// shard, aren't served by it, whatever their annotations or the FQDN template.
+		if ors.ocpRouterName != "" && !admittedByRouter(ocpRoute.Status, ors.ocpRouterName) {
+			log.Debugf("Skipping OpenShift Route %s/%s because it is not admitted by router %s",
+				ocpRoute.Namespace, ocpRoute.Name, ors.ocpRouterName)
+			continue
+		}
+
 		orEndpoints := ors.endpointsFromOcpRoute(ocpRoute, ors.ignoreHostnameAnnotation)
 
 		// apply template if host is missing on OpenShift Route
@@ -158,6 +166,7 @@ func (ors *ocpRouteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint,
 		}
 
 		log.Debugf("Endpoints generated from OpenShift Route: %s/%s: %v", ocpRoute.Namespace, ocpRoute.Name, orEndpoints)
+		orEndpoints = withRecordType(ocpRoute.Annotations, orEndpoints)
 		endpoints = append(endpoints, orEndpoints...)
 	}
 
@@ -277,6 +286,16 @@ func (ors *ocpRouteSource) getTargetsFromRouteStatus(status routev1.RouteStatus)
 	return endpoint.Targets{}, ""
 }
 
+// admittedByRouter returns whether the route status shows an Admitted condition from the given router.
+func admittedByRouter(status routev1.RouteStatus, routerName string) bool {
+	for _, ing := range status.Ingress {
+		if ing.RouterName == routerName && ingressConditionStatus(&ing, routev1.RouteAdmitted) == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 func ingressConditionStatus(ingress *routev1.RouteIngress, t routev1.RouteIngressConditionType) corev1.ConditionStatus {
 	for _, condition := range ingress.Conditions {
 		if t != condition.Type {
@@ -321,6 +321,91 @@ func testOcpRouteSourceEndpoints(t *testing.T) {
 			ocpRouterName: "test",
 			expected:      []*endpoint.Endpoint{},
 		},
+		{
+			title: "route with annotations not admitted by the given router",
+			ocpRoute: &routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "route-with-annotations",
+					Annotations: map[string]string{
+						"external-dns.alpha.kubernetes.io/hostname": "my-annotation-domain.com",
+						"external-dns.alpha.kubernetes.io/target":   "my.site.foo.com",
+					},
+				},
+				Status: routev1.RouteStatus{
+					Ingress: []routev1.RouteIngress{
+						{
+							Host:                    "my-domain.com",
+							RouterName:              "internal",
+							RouterCanonicalHostname: "router-internal.my-domain.com",
+							Conditions: []routev1.RouteIngressCondition{
+								{
+									Type:   routev1.RouteAdmitted,
+									Status: corev1.ConditionTrue,
+								},
+							},
+						},
+						{
+							Host:                    "my-domain.com",
+							RouterName:              "test",
+							RouterCanonicalHostname: "router-test.my-domain.com",
+							Conditions: []routev1.RouteIngressCondition{
+								{
+									Type:   routev1.RouteAdmitted,
+									Status: corev1.ConditionFalse,
+								},
+							},
+						},
+					},
+				},
+			},
+			ocpRouterName: "test",
+			expected:      []*endpoint.Endpoint{},
+		},
+		{
+			title: "route with annotations admitted by the given router",
+			ocpRoute: &routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "route-with-annotations",
+					Annotations: map[string]string{
+						"external-dns.alpha.kubernetes.io/hostname": "my-annotation-domain.com",
+					},
+				},
+				Status: routev1.RouteStatus{
+					Ingress: []routev1.RouteIngress{
+						{
+							Host:                    "my-domain.com",
+							RouterName:              "test",
+							RouterCanonicalHostname: "router-test.my-domain.com",
+							Conditions: []routev1.RouteIngressCondition{
+								{
+									Type:   routev1.RouteAdmitted,
+									Status: corev1.ConditionTrue,
+								},
+							},
+						},
+					},
+				},
+			},
+			ocpRouterName: "test",
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "my-domain.com",
+					RecordType: endpoint.RecordTypeCNAME,
+					Targets: []string{
+						"router-test.my-domain.com",
+					},
+				},
+				{
+					DNSName:    "my-annotation-domain.com",
+					RecordType: endpoint.RecordTypeCNAME,
+					Targets: []string{
+						"router-test.my-domain.com",
+					},
+				},
+			},
+		},
 		{
 			title: "route not admitted by any router",
 			ocpRoute: &routev1.Route{
@@ -24,7 +24,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	kubeinformers "k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -36,11 +35,14 @@ type podSource struct {
 	podInformer   coreinformers.PodInformer
 	nodeInformer  coreinformers.NodeInformer
 	compatibility string
+	labelSelector labels.Selector
+	// publishPodIP publishes the pods not using the host network, with the hostname annotations pointing to their IPs
+	publishPodIP bool
 }
 
 // NewPodSource creates a new podSource with the given config.
-func NewPodSource(ctx context.Context, kubeClient kubernetes.Interface, namespace string, compatibility string) (Source, error) {
-	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+func NewPodSource(ctx context.Context, kubeClient kubernetes.Interface, namespace string, compatibility string, labelSelector labels.Selector, publishPodIP bool) (Source, error) {
+	informerFactory := sharedKubeInformerFactory(ctx, kubeClient, namespace)
 	podInformer := informerFactory.Core().V1().Pods()
 	nodeInformer := informerFactory.Core().V1().Nodes()
 
@@ -70,6 +72,8 @@ func NewPodSource(ctx context.Context, kubeClient kubernetes.Interface, namespac
 		nodeInformer:  nodeInformer,
 		namespace:     namespace,
 		compatibility: compatibility,
+		labelSelector: labelSelector,
+		publishPodIP:  publishPodIP,
 	}, nil
 }
 
@@ -77,7 +81,7 @@ func (*podSource) AddEventHandler(ctx context.Context, handler func()) {
 }
 
 func (ps *podSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
-	pods, err := ps.podInformer.Lister().Pods(ps.namespace).List(labels.Everything())
+	pods, err := ps.podInformer.Lister().Pods(ps.namespace).List(ps.labelSelector)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +89,11 @@ func (ps *podSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error
 	endpointMap := make(map[endpoint.EndpointKey][]string)
 	for _, pod := range pods {
 		if !pod.Spec.HostNetwork {
-			log.Debugf("skipping pod %s. hostNetwork=false", pod.Name)
+			if ps.publishPodIP {
+				addPodIPsToEndpointMap(endpointMap, pod)
+			} else {
+				log.Debugf("skipping pod %s. hostNetwork=false", pod.Name)
+			}
 			continue
 		}
 
@@ -154,6 +162,40 @@ func (ps *podSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error
 	return endpoints, nil
 }
 
+// addPodIPsToEndpointMap adds the hostnames of the annotations of a pod not using the host network, pointing to the
+// IPs of the pod, e.g. to publish the pods of a batch workload without a headless Service.
+func addPodIPsToEndpointMap(endpointMap map[endpoint.EndpointKey][]string, pod *corev1.Pod) {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		log.Debugf("skipping pod %s. phase=%s", pod.Name, pod.Status.Phase)
+		return
+	}
+	targets := getTargetsFromTargetAnnotation(pod.Annotations)
+	if len(targets) == 0 {
+		for _, podIP := range pod.Status.PodIPs {
+			targets = append(targets, podIP.IP)
+		}
+		if len(targets) == 0 && pod.Status.PodIP != "" {
+			targets = append(targets, pod.Status.PodIP)
+		}
+	}
+	if len(targets) == 0 {
+		log.Debugf("skipping pod %s. no pod IP", pod.Name)
+		return
+	}
+
+	for _, key := range []string{hostnameAnnotationKey, internalHostnameAnnotationKey} {
+		domainAnnotation, ok := pod.Annotations[key]
+		if !ok {
+			continue
+		}
+		for _, domain := range splitHostnameAnnotation(domainAnnotation) {
+			for _, target := range targets {
+				addToEndpointMap(endpointMap, domain, suitableType(target), target)
+			}
+		}
+	}
+}
+
 func addToEndpointMap(endpointMap map[endpoint.EndpointKey][]string, domain string, recordType string, address string) {
 	key := endpoint.EndpointKey{
 		DNSName:    domain,
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -662,7 +663,7 @@ func TestPodSource(t *testing.T) {
 				}
 			}
 
-			client, err := NewPodSource(context.TODO(), kubernetes, tc.targetNamespace, tc.compatibility)
+			client, err := NewPodSource(context.TODO(), kubernetes, tc.targetNamespace, tc.compatibility, labels.Everything(), false)
 			require.NoError(t, err)
 
 			endpoints, err := client.Endpoints(ctx)
@@ -678,3 +679,84 @@ func TestPodSource(t *testing.T) {
 
 	}
 }
+
+func TestPodSourcePublishPodIP(t *testing.T) {
+	kubernetes := fake.NewSimpleClientset()
+	ctx := context.Background()
+	for _, pod := range []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "worker-0",
+				Namespace:   "batch",
+				Labels:      map[string]string{"app": "worker"},
+				Annotations: map[string]string{hostnameAnnotationKey: "worker-0.example.org"},
+			},
+			Status: corev1.PodStatus{
+				Phase:  corev1.PodRunning,
+				PodIP:  "10.1.0.1",
+				PodIPs: []corev1.PodIP{{IP: "10.1.0.1"}, {IP: "fd00::1"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "worker-1",
+				Namespace: "batch",
+				Labels:    map[string]string{"app": "worker"},
+				Annotations: map[string]string{
+					internalHostnameAnnotationKey: "worker-1.internal.example.org",
+					targetAnnotationKey:           "192.0.2.1",
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.1.0.2"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "worker-2",
+				Namespace:   "batch",
+				Labels:      map[string]string{"app": "worker"},
+				Annotations: map[string]string{hostnameAnnotationKey: "worker-2.example.org"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded, PodIP: "10.1.0.3"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "worker-3",
+				Namespace:   "batch",
+				Labels:      map[string]string{"app": "worker"},
+				Annotations: map[string]string{hostnameAnnotationKey: "worker-3.example.org"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "other",
+				Namespace:   "batch",
+				Labels:      map[string]string{"app": "other"},
+				Annotations: map[string]string{hostnameAnnotationKey: "other.example.org"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.1.0.4"},
+		},
+	} {
+		_, err := kubernetes.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	selector, err := labels.Parse("app=worker")
+	require.NoError(t, err)
+	client, err := NewPodSource(ctx, kubernetes, "", "", selector, true)
+	require.NoError(t, err)
+	endpoints, err := client.Endpoints(ctx)
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "worker-0.example.org", Targets: endpoint.Targets{"10.1.0.1"}, RecordType: endpoint.RecordTypeA},
+		{DNSName: "worker-0.example.org", Targets: endpoint.Targets{"fd00::1"}, RecordType: endpoint.RecordTypeAAAA},
+		{DNSName: "worker-1.internal.example.org", Targets: endpoint.Targets{"192.0.2.1"}, RecordType: endpoint.RecordTypeA},
+	})
+
+	// the pods not using the host network are only published with publishPodIP
+	client, err = NewPodSource(ctx, kubernetes, "", "", labels.Everything(), false)
+	require.NoError(t, err)
+	endpoints, err = client.Endpoints(ctx)
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{})
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// proxyServiceTargets resolves the targets of the routes of a proxy, such as Traefik or Kong, from the Service
+// exposing the proxy. The target annotation takes precedence, otherwise the load balancer addresses of the
+// Service named by the annotationKey annotation or of the default Service are used. The addresses of each
+// Service are looked up once per instance, in the lister when set, which caches the Services of all namespaces.
+type proxyServiceTargets struct {
+	kubeClient     kubernetes.Interface
+	lister         corelisters.ServiceLister
+	proxy          string
+	annotationKey  string
+	defaultService string
+	cache          map[string]endpoint.Targets
+}
+
+// proxyServiceInformer returns an informer of the Services of all namespaces, to follow the load balancer addresses
+// of the defaultService of a proxy, or nil without a defaultService. Services named only by annotations are read
+// on demand.
+func proxyServiceInformer(ctx context.Context, kubeClient kubernetes.Interface, defaultService string) (coreinformers.ServiceInformer, error) {
+	if defaultService == "" {
+		return nil, nil
+	}
+	kubeInformerFactory := sharedKubeInformerFactory(ctx, kubeClient, "")
+	serviceInformer := kubeInformerFactory.Core().V1().Services()
+	serviceInformer.Informer()
+	kubeInformerFactory.Start(ctx.Done())
+	if err := waitForCacheSync(context.Background(), kubeInformerFactory); err != nil {
+		return nil, err
+	}
+	return serviceInformer, nil
+}
+
+func (t *proxyServiceTargets) targets(ctx context.Context, namespace string, annotations map[string]string) (endpoint.Targets, error) {
+	if targets := getTargetsFromTargetAnnotation(annotations); len(targets) > 0 {
+		return targets, nil
+	}
+
+	service := t.defaultService
+	if value, ok := annotations[t.annotationKey]; ok {
+		service = strings.TrimSpace(value)
+		if service != "" && !strings.Contains(service, "/") {
+			service = namespace + "/" + service
+		}
+	}
+	if service == "" {
+		return nil, nil
+	}
+
+	if targets, ok := t.cache[service]; ok {
+		return append(endpoint.Targets(nil), targets...), nil
+	}
+	targets, err := t.loadBalancerTargets(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	if t.cache == nil {
+		t.cache = make(map[string]endpoint.Targets)
+	}
+	t.cache[service] = targets
+	return append(endpoint.Targets(nil), targets...), nil
+}
+
+// loadBalancerTargets returns the load balancer ingress addresses of the Service <namespace>/<name>.
+// A Service which doesn't exist yields no targets, so that its routes are skipped until it is created.
+func (t *proxyServiceTargets) loadBalancerTargets(ctx context.Context, service string) (endpoint.Targets, error) {
+	namespace, name, found := strings.Cut(service, "/")
+	if !found || namespace == "" || name == "" {
+		log.Warnf("Invalid %s Service %q, expected <namespace>/<name>", t.proxy, service)
+		return nil, nil
+	}
+	var svc *corev1.Service
+	var err error
+	if t.lister != nil {
+		svc, err = t.lister.Services(namespace).Get(name)
+	} else {
+		svc, err = t.kubeClient.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		log.Warnf("%s Service %s not found", t.proxy, service)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s Service %s: %w", t.proxy, service, err)
+	}
+
+	var targets endpoint.Targets
+	for _, lb := range svc.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			targets = append(targets, lb.IP)
+		}
+		if lb.Hostname != "" {
+			targets = append(targets, lb.Hostname)
+		}
+	}
+	if len(targets) == 0 {
+		log.Debugf("%s Service %s has no load balancer address", t.proxy, service)
+	}
+	return targets, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// hostnameLookupTimeout bounds the resolution of a hostname target
+	hostnameLookupTimeout = 5 * time.Second
+	// hostnameCacheTTL is how long the addresses of a hostname target are reused before resolving it again
+	hostnameCacheTTL = time.Minute
+	// hostnameRetention is how long the addresses of a hostname target no longer requested are kept
+	hostnameRetention = time.Hour
+)
+
+// hostnameResolver resolves hostname targets to their addresses. Lookups are bounded by a timeout and their results
+// are cached, so that listing the endpoints of the sources neither blocks on nor floods the resolver. When resolving
+// a hostname fails, the addresses it last resolved to are served instead; failures themselves are not cached.
+type hostnameResolver struct {
+	lookup    func(ctx context.Context, network, host string) ([]net.IP, error)
+	timeout   time.Duration
+	ttl       time.Duration
+	retention time.Duration
+	now       func() time.Time
+
+	mutex sync.Mutex
+	cache map[string]resolvedHostname
+}
+
+type resolvedHostname struct {
+	ips      []net.IP
+	resolved time.Time
+	used     time.Time
+}
+
+func newHostnameResolver(lookup func(ctx context.Context, network, host string) ([]net.IP, error), timeout, ttl time.Duration) *hostnameResolver {
+	return &hostnameResolver{
+		lookup:    lookup,
+		timeout:   timeout,
+		ttl:       ttl,
+		retention: hostnameRetention,
+		now:       time.Now,
+		cache:     map[string]resolvedHostname{},
+	}
+}
+
+// lookupIP returns the addresses of the hostname, from the cache if they were resolved less than the TTL ago. It
+// returns the addresses the hostname last resolved to when resolving it fails, and an error if it never resolved.
+func (r *hostnameResolver) lookupIP(hostname string) ([]net.IP, error) {
+	now := r.now()
+	r.mutex.Lock()
+	cached, ok := r.cache[hostname]
+	if ok {
+		cached.used = now
+		r.cache[hostname] = cached
+	}
+	r.mutex.Unlock()
+	if ok && now.Before(cached.resolved.Add(r.ttl)) {
+		return cached.ips, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	ips, err := r.lookup(ctx, "ip", hostname)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for host, resolved := range r.cache {
+		if now.Sub(resolved.used) >= r.retention {
+			delete(r.cache, host)
+		}
+	}
+	if err != nil {
+		if ok {
+			log.Warnf("Unable to resolve %q, using the addresses it resolved to at %s: %v", hostname, cached.resolved.Format(time.RFC3339), err)
+			return cached.ips, nil
+		}
+		return nil, err
+	}
+	r.cache[hostname] = resolvedHostname{ips: ips, resolved: now, used: now}
+	return ips, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostnameResolverCache(t *testing.T) {
+	lookups := 0
+	resolver := newHostnameResolver(func(_ context.Context, _, host string) ([]net.IP, error) {
+		lookups++
+		if host == "lb.example.com" {
+			return []net.IP{net.ParseIP("1.2.3.4")}, nil
+		}
+		return nil, errors.New("no such host")
+	}, time.Second, time.Minute)
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	ips, err := resolver.lookupIP("lb.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("1.2.3.4")}, ips)
+	_, err = resolver.lookupIP("lb.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, lookups, "addresses should be cached")
+
+	_, err = resolver.lookupIP("unknown.example.com")
+	assert.Error(t, err)
+	_, err = resolver.lookupIP("unknown.example.com")
+	assert.Error(t, err)
+	assert.Equal(t, 3, lookups, "failures should not be cached")
+
+	now = now.Add(time.Minute)
+	_, err = resolver.lookupIP("lb.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 4, lookups, "expired addresses should be resolved again")
+
+	now = now.Add(time.Hour)
+	_, err = resolver.lookupIP("other.example.com")
+	assert.Error(t, err)
+	assert.Empty(t, resolver.cache, "addresses no longer requested should be evicted")
+}
+
+func TestHostnameResolverServesLastAddresses(t *testing.T) {
+	var failure error
+	resolver := newHostnameResolver(func(_ context.Context, _, _ string) ([]net.IP, error) {
+		if failure != nil {
+			return nil, failure
+		}
+		return []net.IP{net.ParseIP("1.2.3.4")}, nil
+	}, time.Second, time.Minute)
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	_, err := resolver.lookupIP("lb.example.com")
+	require.NoError(t, err)
+
+	failure = errors.New("server misbehaving")
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Hour)
+		ips, err := resolver.lookupIP("lb.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []net.IP{net.ParseIP("1.2.3.4")}, ips, "the last addresses should be served while resolving fails")
+	}
+}
+
+func TestHostnameResolverTimeout(t *testing.T) {
+	resolver := newHostnameResolver(func(ctx context.Context, _, _ string) ([]net.IP, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, 10*time.Millisecond, time.Minute)
+
+	_, err := resolver.lookupIP("slow.example.com")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns/v1alpha1"
+)
+
+// rewriteRule is a DNSRewrite with its compiled regular expression.
+type rewriteRule struct {
+	name         string
+	namespaces   []string
+	match        *regexp.Regexp
+	replacement  string
+	fromSuffix   string
+	toSuffix     string
+	keepOriginal bool
+}
+
+// newRewriteRule returns the rule of a DNSRewrite, or an error if it is invalid.
+func newRewriteRule(rw *v1alpha1.DNSRewrite) (*rewriteRule, error) {
+	rule := &rewriteRule{
+		name:         rw.Name,
+		namespaces:   rw.Spec.Namespaces,
+		keepOriginal: rw.Spec.KeepOriginal,
+	}
+	switch {
+	case rw.Spec.Match != "":
+		if rw.Spec.Replacement == "" {
+			return nil, errors.New("a replacement is required with match")
+		}
+		match, err := regexp.Compile("^(?:" + rw.Spec.Match + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid match: %w", err)
+		}
+		rule.match = match
+		rule.replacement = rw.Spec.Replacement
+	case rw.Spec.FromSuffix != "":
+		rule.fromSuffix = strings.ToLower(strings.Trim(rw.Spec.FromSuffix, "."))
+		rule.toSuffix = strings.ToLower(strings.Trim(rw.Spec.ToSuffix, "."))
+		if rule.toSuffix == "" {
+			return nil, errors.New("toSuffix is required with fromSuffix")
+		}
+	default:
+		return nil, errors.New("either match or fromSuffix is required")
+	}
+	return rule, nil
+}
+
+// appliesTo returns true when the rule applies to the records of resources in the namespace.
+func (r *rewriteRule) appliesTo(namespace string) bool {
+	if len(r.namespaces) == 0 {
+		return true
+	}
+	for _, n := range r.namespaces {
+		if n == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// rewrite returns the rewritten DNS name, and false if the rule doesn't match the name.
+func (r *rewriteRule) rewrite(name string) (string, bool) {
+	if r.match != nil {
+		if !r.match.MatchString(name) {
+			return "", false
+		}
+		return r.match.ReplaceAllString(name, r.replacement), true
+	}
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	switch {
+	case name == r.fromSuffix:
+		return r.toSuffix, true
+	case strings.HasSuffix(name, "."+r.fromSuffix):
+		return strings.TrimSuffix(name, r.fromSuffix) + r.toSuffix, true
+	}
+	return "", false
+}
+
+// rewriteSource is a Source that rewrites the DNS names of the endpoints of its wrapped source with the
+// DNSRewrites of the cluster.
+type rewriteSource struct {
+	source          Source
+	rewriteInformer informers.GenericInformer
+}
+
+// NewRewriteSource creates a new rewriteSource wrapping the provided Source, watching the DNSRewrites with
+// dynamicKubeClient.
+func NewRewriteSource(ctx context.Context, dynamicKubeClient dynamic.Interface, source Source) (Source, error) {
+	factory := sharedDynamicInformerFactory(ctx, dynamicKubeClient, "")
+	rewriteInformer := factory.ForResource(v1alpha1.DNSRewriteResource)
+	// Add default resource event handlers to properly initialize informer.
+	rewriteInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+			},
+		},
+	)
+
+	factory.Start(ctx.Done())
+
+	// wait for the local cache to be populated.
+	if err := waitForDynamicCacheSync(context.Background(), factory); err != nil {
+		return nil, err
+	}
+
+	return &rewriteSource{source: source, rewriteInformer: rewriteInformer}, nil
+}
+
+// rules returns the rules of the valid DNSRewrites, in the order of their names.
+func (rs *rewriteSource) rules() ([]*rewriteRule, error) {
+	objects, err := rs.rewriteInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*rewriteRule, 0, len(objects))
+	for _, obj := range objects {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected DNSRewrite object %T", obj)
+		}
+		rw := &v1alpha1.DNSRewrite{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), rw); err != nil {
+			log.Warnf("Ignoring DNSRewrite %s which cannot be decoded: %v", u.GetName(), err)
+			continue
+		}
+		rule, err := newRewriteRule(rw)
+		if err != nil {
+			log.Warnf("Ignoring invalid DNSRewrite %s: %v", rw.Name, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].name < rules[j].name })
+	return rules, nil
+}
+
+// Endpoints collects endpoints from its wrapped source and rewrites their DNS names with the first matching rule.
+// The endpoints are copied before being rewritten, so that the endpoints cached by the wrapped source are not
+// rewritten twice.
+func (rs *rewriteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := rs.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := rs.rules()
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return endpoints, nil
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		_, namespace, _ := ep.Labels.Resource()
+		rewritten := false
+		for _, rule := range rules {
+			if !rule.appliesTo(namespace) {
+				continue
+			}
+			name, ok := rule.rewrite(ep.DNSName)
+			if !ok {
+				continue
+			}
+			log.Debugf("Rewriting %s to %s by DNSRewrite %s", ep.DNSName, name, rule.name)
+			copied := ep.DeepCopy()
+			copied.DNSName = name
+			result = append(result, copied)
+			if rule.keepOriginal {
+				result = append(result, ep)
+			}
+			rewritten = true
+			break
+		}
+		if !rewritten {
+			result = append(result, ep)
+		}
+	}
+	return result, nil
+}
+
+func (rs *rewriteSource) AddEventHandler(ctx context.Context, handler func()) {
+	log.Debug("Adding event handler for DNSRewrite")
+
+	rs.source.AddEventHandler(ctx, handler)
+	rs.rewriteInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/pkg/apis/externaldns/v1alpha1"
+)
+
+func newTestRewriteSource(t *testing.T, endpoints []*endpoint.Endpoint, rewrites ...*v1alpha1.DNSRewrite) Source {
+	ctx := context.Background()
+	dynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		v1alpha1.DNSRewriteResource: "DNSRewriteList",
+	})
+	for _, rw := range rewrites {
+		rw.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "DNSRewrite"}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(rw)
+		require.NoError(t, err)
+		_, err = dynamicClient.Resource(v1alpha1.DNSRewriteResource).Create(ctx, &unstructured.Unstructured{Object: content}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	src, err := NewRewriteSource(ctx, dynamicClient, NewEchoSource(endpoints))
+	require.NoError(t, err)
+	return src
+}
+
+func newDNSRewrite(name string, spec v1alpha1.DNSRewriteSpec) *v1alpha1.DNSRewrite {
+	return &v1alpha1.DNSRewrite{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+}
+
+func newRewriteTestEndpoint(dnsName, resource string) *endpoint.Endpoint {
+	ep := endpoint.NewEndpoint(dnsName, endpoint.RecordTypeA, "1.2.3.4")
+	ep.Labels[endpoint.ResourceLabelKey] = resource
+	return ep
+}
+
+func TestRewriteSource(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		newRewriteTestEndpoint("shop-7f9c.apps.internal.example.net", "ingress/shop/web"),
+		newRewriteTestEndpoint("api.team-a.apps.internal.example.net", "ingress/team-a/api"),
+		newRewriteTestEndpoint("legacy.apps.internal.example.net", "ingress/legacy/web"),
+		newRewriteTestEndpoint("other.example.org", "ingress/shop/other"),
+	}
+	src := newTestRewriteSource(t, endpoints,
+		newDNSRewrite("10-customers", v1alpha1.DNSRewriteSpec{
+			Namespaces:  []string{"shop"},
+			Match:       `(?P<app>[a-z]+)-[0-9a-f]+\.apps\.internal\.example\.net`,
+			Replacement: "${app}.example.com",
+		}),
+		newDNSRewrite("20-apps", v1alpha1.DNSRewriteSpec{
+			FromSuffix:   "apps.internal.example.net.",
+			ToSuffix:     "example.com",
+			KeepOriginal: true,
+		}),
+		newDNSRewrite("30-invalid", v1alpha1.DNSRewriteSpec{Match: "("}),
+	)
+
+	result, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, result, []*endpoint.Endpoint{
+		newRewriteTestEndpoint("shop.example.com", "ingress/shop/web"),
+		newRewriteTestEndpoint("api.team-a.example.com", "ingress/team-a/api"),
+		newRewriteTestEndpoint("api.team-a.apps.internal.example.net", "ingress/team-a/api"),
+		newRewriteTestEndpoint("legacy.example.com", "ingress/legacy/web"),
+		newRewriteTestEndpoint("legacy.apps.internal.example.net", "ingress/legacy/web"),
+		newRewriteTestEndpoint("other.example.org", "ingress/shop/other"),
+	})
+
+	// the endpoints of the wrapped source are left untouched
+	assert.Equal(t, "shop-7f9c.apps.internal.example.net", endpoints[0].DNSName)
+}
+
+func TestNewRewriteRule(t *testing.T) {
+	for _, spec := range []v1alpha1.DNSRewriteSpec{
+		{},
+		{Match: "(", Replacement: "example.com"},
+		{Match: "app.example.net"},
+		{FromSuffix: "example.net"},
+	} {
+		_, err := newRewriteRule(newDNSRewrite("invalid", spec))
+		assert.Error(t, err, "%+v", spec)
+	}
+
+	rule, err := newRewriteRule(newDNSRewrite("suffix", v1alpha1.DNSRewriteSpec{FromSuffix: "example.net", ToSuffix: "example.com"}))
+	require.NoError(t, err)
+	name, ok := rule.rewrite("example.net")
+	assert.True(t, ok)
+	assert.Equal(t, "example.com", name)
+	_, ok = rule.rewrite("badexample.net")
+	assert.False(t, ok)
+}
@@ -26,10 +26,11 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	kubeinformers "k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
@@ -59,8 +60,9 @@ type serviceSource struct {
 	publishHostIP                  bool
 	alwaysPublishNotReadyAddresses bool
 	resolveLoadBalancerHostname    bool
+	topologyHostnames              bool
 	serviceInformer                coreinformers.ServiceInformer
-	endpointsInformer              coreinformers.EndpointsInformer
+	endpointSlicesInformer         discoveryinformers.EndpointSliceInformer
 	podInformer                    coreinformers.PodInformer
 	nodeInformer                   coreinformers.NodeInformer
 	serviceTypeFilter              map[string]struct{}
@@ -68,7 +70,7 @@ type serviceSource struct {
 }
 
 // NewServiceSource creates a new serviceSource with the given config.
-func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, namespace, annotationFilter string, fqdnTemplate string, combineFqdnAnnotation bool, compatibility string, publishInternal bool, publishHostIP bool, alwaysPublishNotReadyAddresses bool, serviceTypeFilter []string, ignoreHostnameAnnotation bool, labelSelector labels.Selector, resolveLoadBalancerHostname bool) (Source, error) {
+func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, namespace, annotationFilter string, fqdnTemplate string, combineFqdnAnnotation bool, compatibility string, publishInternal bool, publishHostIP bool, alwaysPublishNotReadyAddresses bool, serviceTypeFilter []string, ignoreHostnameAnnotation bool, labelSelector labels.Selector, resolveLoadBalancerHostname bool, topologyHostnames bool) (Source, error) {
 	tmpl, err := parseTemplate(fqdnTemplate)
 	if err != nil {
 		return nil, err
@@ -76,9 +78,9 @@ func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, name
 
 	// Use shared informers to listen for add/update/delete of services/pods/nodes in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed
-	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+	informerFactory := sharedKubeInformerFactory(ctx, kubeClient, namespace)
 	serviceInformer := informerFactory.Core().V1().Services()
-	endpointsInformer := informerFactory.Core().V1().Endpoints()
+	endpointSlicesInformer := informerFactory.Discovery().V1().EndpointSlices()
 	podInformer := informerFactory.Core().V1().Pods()
 	nodeInformer := informerFactory.Core().V1().Nodes()
 
@@ -89,7 +91,7 @@ func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, name
 			},
 		},
 	)
-	endpointsInformer.Informer().AddEventHandler(
+	endpointSlicesInformer.Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 			},
@@ -134,12 +136,13 @@ func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, name
 		publishHostIP:                  publishHostIP,
 		alwaysPublishNotReadyAddresses: alwaysPublishNotReadyAddresses,
 		serviceInformer:                serviceInformer,
-		endpointsInformer:              endpointsInformer,
+		endpointSlicesInformer:         endpointSlicesInformer,
 		podInformer:                    podInformer,
 		nodeInformer:                   nodeInformer,
 		serviceTypeFilter:              serviceTypes,
 		labelSelector:                  labelSelector,
 		resolveLoadBalancerHostname:    resolveLoadBalancerHostname,
+		topologyHostnames:              topologyHostnames,
 	}, nil
 }
 
@@ -161,6 +164,13 @@ func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 
 	endpoints := []*endpoint.Endpoint{}
 
+	var nodeZones map[string]string
+	if sc.topologyHostnames {
+		if nodeZones, err = sc.nodeAddressZones(); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, svc := range services {
 		// Check controller annotation to see if we are responsible.
 		controller, ok := svc.Annotations[controllerAnnotationKey]
@@ -200,7 +210,14 @@ func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 		}
 
 		log.Debugf("Endpoints generated from service: %s/%s: %v", svc.Namespace, svc.Name, svcEndpoints)
+		svcEndpoints = withWildcards(svc.Annotations, svcEndpoints)
+		svcEndpoints = withRecordType(svc.Annotations, svcEndpoints)
+		if sc.topologyHostnames {
+			svcEndpoints = append(svcEndpoints, zoneEndpoints(svcEndpoints, sc.serviceAddressZones(svc, nodeZones))...)
+		}
 		sc.setResourceLabel(svc, svcEndpoints)
+		setCommitLabel(svc.Annotations, svcEndpoints)
+		setActiveTargetLabel(svc.Annotations, svcEndpoints)
 		endpoints = append(endpoints, svcEndpoints...)
 	}
 
@@ -244,7 +261,7 @@ func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 	return endpoints, nil
 }
 
-// extractHeadlessEndpoints extracts endpoints from a headless service using the "Endpoints" Kubernetes API resource
+// extractHeadlessEndpoints extracts endpoints from a headless service using the "EndpointSlice" Kubernetes API resources
 func (sc *serviceSource) extractHeadlessEndpoints(svc *v1.Service, hostname string, ttl endpoint.TTL) []*endpoint.Endpoint {
 	var endpoints []*endpoint.Endpoint
 
@@ -257,9 +274,10 @@ func (sc *serviceSource) extractHeadlessEndpoints(svc *v1.Service, hostname stri
 		return nil
 	}
 
-	endpointsObject, err := sc.endpointsInformer.Lister().Endpoints(svc.Namespace).Get(svc.GetName())
+	sliceSelector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: svc.GetName()})
+	endpointSlices, err := sc.endpointSlicesInformer.Lister().EndpointSlices(svc.Namespace).List(sliceSelector)
 	if err != nil {
-		log.Errorf("Get endpoints of service[%s] error:%v", svc.GetName(), err)
+		log.Errorf("List endpoint slices of service[%s] error:%v", svc.GetName(), err)
 		return endpoints
 	}
 
@@ -270,44 +288,54 @@ func (sc *serviceSource) extractHeadlessEndpoints(svc *v1.Service, hostname stri
 	}
 
 	endpointsType := getEndpointsTypeFromAnnotations(svc.Annotations)
+	publishNotReadyAddresses := svc.Spec.PublishNotReadyAddresses || sc.alwaysPublishNotReadyAddresses
 
 	targetsByHeadlessDomainAndType := make(map[endpoint.EndpointKey]endpoint.Targets)
-	for _, subset := range endpointsObject.Subsets {
-		addresses := subset.Addresses
-		if svc.Spec.PublishNotReadyAddresses || sc.alwaysPublishNotReadyAddresses {
-			addresses = append(addresses, subset.NotReadyAddresses...)
+	for _, endpointSlice := range endpointSlices {
+		if endpointSlice.AddressType == discoveryv1.AddressTypeFQDN {
+			log.Debugf("Skipping EndpointSlice %s/%s because its addresses are FQDNs", endpointSlice.Namespace, endpointSlice.Name)
+			continue
 		}
 
-		for _, address := range addresses {
-			// find pod for this address
-			if address.TargetRef == nil || address.TargetRef.APIVersion != "" || address.TargetRef.Kind != "Pod" {
-				log.Debugf("Skipping address because its target is not a pod: %v", address)
+		for _, ep := range endpointSlice.Endpoints {
+			if !publishEndpointSliceEndpoint(ep.Conditions, publishNotReadyAddresses) {
+				log.Debugf("Skipping endpoint %v because it is not ready", ep.Addresses)
+				continue
+			}
+
+			// find pod for this endpoint
+			if ep.TargetRef == nil || ep.TargetRef.APIVersion != "" || ep.TargetRef.Kind != "Pod" {
+				log.Debugf("Skipping endpoint because its target is not a pod: %v", ep)
 				continue
 			}
 			var pod *v1.Pod
 			for _, v := range pods {
-				if v.Name == address.TargetRef.Name {
+				if v.Name == ep.TargetRef.Name {
 					pod = v
 					break
 				}
 			}
 			if pod == nil {
-				log.Errorf("Pod %s not found for address %v", address.TargetRef.Name, address)
+				log.Errorf("Pod %s not found for endpoint %v", ep.TargetRef.Name, ep.Addresses)
 				continue
 			}
 
 			headlessDomains := []string{hostname}
-			if pod.Spec.Hostname != "" {
-				headlessDomains = append(headlessDomains, fmt.Sprintf("%s.%s", pod.Spec.Hostname, hostname))
+			if podHostname := endpointSliceHostname(ep, pod); podHostname != "" {
+				headlessDomains = append(headlessDomains, fmt.Sprintf("%s.%s", podHostname, hostname))
 			}
 
 			for _, headlessDomain := range headlessDomains {
 				targets := getTargetsFromTargetAnnotation(pod.Annotations)
 				if len(targets) == 0 {
 					if endpointsType == EndpointsTypeNodeExternalIP {
-						node, err := sc.nodeInformer.Lister().Get(pod.Spec.NodeName)
+						nodeName := pod.Spec.NodeName
+						if ep.NodeName != nil && *ep.NodeName != "" {
+							nodeName = *ep.NodeName
+						}
+						node, err := sc.nodeInformer.Lister().Get(nodeName)
 						if err != nil {
-							log.Errorf("Get node[%s] of pod[%s] error: %v; not adding any NodeExternalIP endpoints", pod.Spec.NodeName, pod.GetName(), err)
+							log.Errorf("Get node[%s] of pod[%s] error: %v; not adding any NodeExternalIP endpoints", nodeName, pod.GetName(), err)
 							return endpoints
 						}
 						for _, address := range node.Status.Addresses {
@@ -320,8 +348,10 @@ func (sc *serviceSource) extractHeadlessEndpoints(svc *v1.Service, hostname stri
 						targets = endpoint.Targets{pod.Status.HostIP}
 						log.Debugf("Generating matching endpoint %s with HostIP %s", headlessDomain, pod.Status.HostIP)
 					} else {
-						targets = endpoint.Targets{address.IP}
-						log.Debugf("Generating matching endpoint %s with EndpointAddress IP %s", headlessDomain, address.IP)
+						for _, address := range ep.Addresses {
+							targets = append(targets, address)
+							log.Debugf("Generating matching endpoint %s with EndpointSlice address %s", headlessDomain, address)
+						}
 					}
 				}
 				for _, target := range targets {
@@ -370,6 +400,30 @@ func (sc *serviceSource) extractHeadlessEndpoints(svc *v1.Service, hostname stri
 	return endpoints
 }
 
+// publishEndpointSliceEndpoint reports whether an EndpointSlice endpoint should be published. Ready endpoints
+// always are; when not ready addresses are published as well, only endpoints that are terminating and no longer
+// serving are left out. A nil condition is interpreted as true, as documented by the EndpointSlice API.
+func publishEndpointSliceEndpoint(conditions discoveryv1.EndpointConditions, publishNotReadyAddresses bool) bool {
+	if conditions.Ready == nil || *conditions.Ready {
+		return true
+	}
+	if !publishNotReadyAddresses {
+		return false
+	}
+	terminating := conditions.Terminating != nil && *conditions.Terminating
+	serving := conditions.Serving == nil || *conditions.Serving
+	return !terminating || serving
+}
+
+// endpointSliceHostname returns the hostname of the pod behind an EndpointSlice endpoint, preferring the one
+// recorded on the endpoint by the EndpointSlice controller.
+func endpointSliceHostname(ep discoveryv1.Endpoint, pod *v1.Pod) string {
+	if ep.Hostname != nil && *ep.Hostname != "" {
+		return *ep.Hostname
+	}
+	return pod.Spec.Hostname
+}
+
 func (sc *serviceSource) endpointsFromTemplate(svc *v1.Service) ([]*endpoint.Endpoint, error) {
 	hostnames, err := execTemplate(sc.fqdnTemplate, svc)
 	if err != nil {
@@ -513,7 +567,15 @@ func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, pro
 				log.Errorf("Unable to extract targets from service %s/%s error: %v", svc.Namespace, svc.Name, err)
 				return endpoints
 			}
-			endpoints = append(endpoints, sc.extractNodePortEndpoints(svc, hostname, ttl)...)
+			if service, protocol, host, ok := splitSRVHostname(hostname); ok {
+				// the SRV hostname points to the host holding the addresses of the nodes
+				epA.DNSName, epAAAA.DNSName, epCNAME.DNSName = host, host, host
+				if ep := extractNodePortSRVEndpoint(svc, hostname, service, protocol, host, ttl); ep != nil {
+					endpoints = append(endpoints, ep)
+				}
+			} else {
+				endpoints = append(endpoints, sc.extractNodePortEndpoints(svc, hostname, ttl)...)
+			}
 		case v1.ServiceTypeExternalName:
 			targets = extractServiceExternalName(svc)
 		}
@@ -749,6 +811,147 @@ func (sc *serviceSource) extractNodePortEndpoints(svc *v1.Service, hostname stri
 	return endpoints
 }
 
+// nodeAddressZones returns the topology zones of the nodes, keyed by node name and by node address.
+func (sc *serviceSource) nodeAddressZones() (map[string]string, error) {
+	nodes, err := sc.nodeInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	zones := map[string]string{}
+	for _, node := range nodes {
+		zone := node.Labels[v1.LabelTopologyZone]
+		if zone == "" {
+			continue
+		}
+		zones[node.Name] = zone
+		for _, address := range node.Status.Addresses {
+			zones[address.Address] = zone
+		}
+	}
+	return zones, nil
+}
+
+// serviceAddressZones returns the topology zones of the addresses of the nodes and of the endpoints of the service,
+// keyed by address.
+func (sc *serviceSource) serviceAddressZones(svc *v1.Service, nodeZones map[string]string) map[string]string {
+	sliceSelector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: svc.Name})
+	endpointSlices, err := sc.endpointSlicesInformer.Lister().EndpointSlices(svc.Namespace).List(sliceSelector)
+	if err != nil || len(endpointSlices) == 0 {
+		return nodeZones
+	}
+
+	zones := make(map[string]string, len(nodeZones))
+	for address, zone := range nodeZones {
+		zones[address] = zone
+	}
+	for _, endpointSlice := range endpointSlices {
+		for _, ep := range endpointSlice.Endpoints {
+			var zone string
+			switch {
+			case ep.Zone != nil:
+				zone = *ep.Zone
+			case ep.NodeName != nil:
+				zone = nodeZones[*ep.NodeName]
+			}
+			if zone == "" {
+				continue
+			}
+			for _, address := range ep.Addresses {
+				zones[address] = zone
+			}
+		}
+	}
+	return zones
+}
+
+// zoneEndpoints returns, for every A and AAAA endpoint, the endpoints of the zone-scoped hostnames of the zones of its
+// targets, e.g. svc.eu-west-1a.example.com for svc.example.com. Targets of unknown zones are not published.
+func zoneEndpoints(endpoints []*endpoint.Endpoint, zones map[string]string) []*endpoint.Endpoint {
+	var zoned []*endpoint.Endpoint
+	for _, ep := range endpoints {
+		if ep.RecordType != endpoint.RecordTypeA && ep.RecordType != endpoint.RecordTypeAAAA {
+			continue
+		}
+		if strings.HasPrefix(ep.DNSName, "*") {
+			continue
+		}
+		targetsByZone := map[string]endpoint.Targets{}
+		for _, target := range ep.Targets {
+			if zone := strings.ToLower(zones[target]); zone != "" {
+				targetsByZone[zone] = append(targetsByZone[zone], target)
+			}
+		}
+		zoneNames := make([]string, 0, len(targetsByZone))
+		for zone := range targetsByZone {
+			zoneNames = append(zoneNames, zone)
+		}
+		sort.Strings(zoneNames)
+		for _, zone := range zoneNames {
+			zoneEndpoint := endpoint.NewEndpointWithTTL(zoneHostname(ep.DNSName, zone), ep.RecordType, ep.RecordTTL, targetsByZone[zone]...)
+			zoneEndpoint.ProviderSpecific = ep.ProviderSpecific
+			zoneEndpoint.SetIdentifier = ep.SetIdentifier
+			zoned = append(zoned, zoneEndpoint)
+		}
+	}
+	return zoned
+}
+
+// zoneHostname inserts the zone after the first label of the hostname.
+func zoneHostname(hostname, zone string) string {
+	if first, rest, found := strings.Cut(hostname, "."); found {
+		return first + "." + zone + "." + rest
+	}
+	return hostname + "." + zone
+}
+
+// splitSRVHostname splits a hostname in the format of SRV records, _service._proto.host, into its parts.
+func splitSRVHostname(hostname string) (service, protocol, host string, ok bool) {
+	parts := strings.SplitN(hostname, ".", 3)
+	if len(parts) != 3 || len(parts[0]) < 2 || !strings.HasPrefix(parts[0], "_") || parts[2] == "" {
+		return "", "", "", false
+	}
+	switch protocol = strings.ToLower(parts[1]); protocol {
+	case "_tcp", "_udp", "_sctp":
+		return parts[0][1:], protocol[1:], parts[2], true
+	default:
+		return "", "", "", false
+	}
+}
+
+// extractNodePortSRVEndpoint returns the SRV endpoint of a NodePort service annotated with an SRV hostname, pointing
+// to the node ports of the protocol at host. When some ports are named after the service of the SRV hostname, only
+// these are published.
+func extractNodePortSRVEndpoint(svc *v1.Service, hostname, service, protocol, host string, ttl endpoint.TTL) *endpoint.Endpoint {
+	var named, all endpoint.Targets
+	for _, port := range svc.Spec.Ports {
+		portProtocol := strings.ToLower(string(port.Protocol))
+		if portProtocol == "" {
+			portProtocol = "tcp"
+		}
+		if port.NodePort <= 0 || portProtocol != protocol {
+			continue
+		}
+		target := fmt.Sprintf("0 50 %d %s", port.NodePort, host)
+		all = append(all, target)
+		if port.Name == service {
+			named = append(named, target)
+		}
+	}
+
+	targets := all
+	if len(named) > 0 {
+		targets = named
+	}
+	if len(targets) == 0 {
+		log.Debugf("Service %s/%s has no %s node port for the SRV hostname %s", svc.Namespace, svc.Name, protocol, hostname)
+		return nil
+	}
+	if ttl.IsConfigured() {
+		return endpoint.NewEndpointWithTTL(hostname, endpoint.RecordTypeSRV, ttl, targets...)
+	}
+	return endpoint.NewEndpoint(hostname, endpoint.RecordTypeSRV, targets...)
+}
+
 func (sc *serviceSource) AddEventHandler(ctx context.Context, handler func()) {
 	log.Debug("Adding event handler for service")
 
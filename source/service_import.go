@@ -0,0 +1,273 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var serviceImportGVR = schema.GroupVersionResource{
+	Group:    "multicluster.x-k8s.io",
+	Version:  "v1alpha1",
+	Resource: "serviceimports",
+}
+
+const (
+	// ServiceImportNamingClusterSet names the records of ServiceImports after the Multi-Cluster DNS specification,
+	// e.g. <service>.<namespace>.svc.clusterset.local, in addition to their hostname annotations
+	ServiceImportNamingClusterSet = "clusterset"
+	// ServiceImportNamingAnnotation names the records of ServiceImports after their hostname annotations only
+	ServiceImportNamingAnnotation = "annotation"
+
+	serviceImportTypeHeadless = "Headless"
+	// mcsServiceNameLabel is the label of the EndpointSlices imported for a ServiceImport holding its name
+	mcsServiceNameLabel = "multicluster.kubernetes.io/service-name"
+	// mcsSourceClusterLabel is the label of the EndpointSlices imported for a ServiceImport holding the ID of
+	// the cluster exporting them
+	mcsSourceClusterLabel = "multicluster.kubernetes.io/source-cluster"
+)
+
+// serviceImport is a ServiceImport of the Multi-Cluster Services API, limited to the fields used by the source.
+type serviceImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              serviceImportSpec `json:"spec,omitempty"`
+}
+
+type serviceImportSpec struct {
+	// Type is ClusterSetIP or Headless
+	Type string   `json:"type,omitempty"`
+	IPs  []string `json:"ips,omitempty"`
+}
+
+// serviceImportSource is an implementation of Source for the ServiceImports of the Multi-Cluster Services API.
+// ClusterSetIP ServiceImports resolve to their IPs, headless ServiceImports to the ready addresses of the
+// EndpointSlices imported for them.
+type serviceImportSource struct {
+	namespace                string
+	annotationFilter         string
+	ignoreHostnameAnnotation bool
+	naming                   string
+	clusterSetDomain         string
+	serviceImportInformer    informers.GenericInformer
+	endpointSlicesLister     discoverylisters.EndpointSliceLister
+	endpointSlicesInformer   cache.SharedIndexInformer
+}
+
+// NewServiceImportSource creates a new serviceImportSource naming the records with the given strategy,
+// ServiceImportNamingClusterSet or ServiceImportNamingAnnotation, under clusterSetDomain.
+func NewServiceImportSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace, annotationFilter string, ignoreHostnameAnnotation bool, naming, clusterSetDomain string) (Source, error) {
+	switch naming {
+	case ServiceImportNamingClusterSet, ServiceImportNamingAnnotation:
+	default:
+		return nil, fmt.Errorf("unknown ServiceImport naming strategy %q", naming)
+	}
+
+	dynamicFactory := sharedDynamicInformerFactory(ctx, dynamicKubeClient, namespace)
+	serviceImportInformer := dynamicFactory.ForResource(serviceImportGVR)
+	// Add default resource event handlers to properly initialize informer.
+	serviceImportInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+			},
+		},
+	)
+
+	kubeFactory := sharedKubeInformerFactory(ctx, kubeClient, namespace)
+	endpointSlicesInformer := kubeFactory.Discovery().V1().EndpointSlices()
+	endpointSlicesInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+			},
+		},
+	)
+
+	dynamicFactory.Start(ctx.Done())
+	kubeFactory.Start(ctx.Done())
+
+	// wait for the local cache to be populated.
+	if err := waitForDynamicCacheSync(context.Background(), dynamicFactory); err != nil {
+		return nil, err
+	}
+	if err := waitForCacheSync(context.Background(), kubeFactory); err != nil {
+		return nil, err
+	}
+
+	return &serviceImportSource{
+		namespace:                namespace,
+		annotationFilter:         annotationFilter,
+		ignoreHostnameAnnotation: ignoreHostnameAnnotation,
+		naming:                   naming,
+		clusterSetDomain:         strings.TrimSuffix(clusterSetDomain, "."),
+		serviceImportInformer:    serviceImportInformer,
+		endpointSlicesLister:     endpointSlicesInformer.Lister(),
+		endpointSlicesInformer:   endpointSlicesInformer.Informer(),
+	}, nil
+}
+
+// Endpoints returns the endpoints of the ServiceImports in the source's namespace(s).
+func (sc *serviceImportSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	objects, err := sc.serviceImportInformer.Lister().ByNamespace(sc.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	selector, err := getLabelSelector(sc.annotationFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []*endpoint.Endpoint{}
+	for _, obj := range objects {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected ServiceImport object %T", obj)
+		}
+		si := &serviceImport{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), si); err != nil {
+			return nil, fmt.Errorf("decoding ServiceImport %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+		}
+		if !matchLabelSelector(selector, si.Annotations) {
+			continue
+		}
+		if controller, ok := si.Annotations[controllerAnnotationKey]; ok && controller != controllerAnnotationValue {
+			log.Debugf("Skipping ServiceImport %s/%s because controller value does not match, found: %s, required: %s",
+				si.Namespace, si.Name, controller, controllerAnnotationValue)
+			continue
+		}
+
+		siEndpoints, err := sc.endpointsFromServiceImport(si)
+		if err != nil {
+			return nil, err
+		}
+		if len(siEndpoints) == 0 {
+			log.Debugf("No endpoints could be generated from ServiceImport %s/%s", si.Namespace, si.Name)
+			continue
+		}
+		log.Debugf("Endpoints generated from ServiceImport %s/%s: %v", si.Namespace, si.Name, siEndpoints)
+		endpoints = append(endpoints, siEndpoints...)
+	}
+
+	for _, ep := range endpoints {
+		sort.Sort(ep.Targets)
+	}
+	return endpoints, nil
+}
+
+// endpointsFromServiceImport returns the endpoints of a ServiceImport.
+func (sc *serviceImportSource) endpointsFromServiceImport(si *serviceImport) ([]*endpoint.Endpoint, error) {
+	resource := fmt.Sprintf("serviceimport/%s/%s", si.Namespace, si.Name)
+	ttl := getTTLFromAnnotations(si.Annotations, resource)
+	providerSpecific, setIdentifier := getProviderSpecificAnnotations(si.Annotations)
+
+	targets := getTargetsFromTargetAnnotation(si.Annotations)
+	var hosts map[string]endpoint.Targets
+	if si.Spec.Type == serviceImportTypeHeadless {
+		endpointSlices, err := sc.endpointSlicesLister.EndpointSlices(si.Namespace).List(labels.SelectorFromSet(labels.Set{mcsServiceNameLabel: si.Name}))
+		if err != nil {
+			return nil, err
+		}
+		var addresses endpoint.Targets
+		addresses, hosts = headlessServiceImportTargets(endpointSlices)
+		if len(targets) == 0 {
+			targets = addresses
+		}
+	} else if len(targets) == 0 {
+		targets = si.Spec.IPs
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	hostnames := []string{}
+	if !sc.ignoreHostnameAnnotation {
+		hostnames = append(hostnames, getHostnamesFromAnnotations(si.Annotations)...)
+	}
+	if sc.naming == ServiceImportNamingClusterSet {
+		hostnames = append(hostnames, sc.clusterSetName(si))
+	}
+
+	endpoints := []*endpoint.Endpoint{}
+	for _, hostname := range hostnames {
+		endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier, resource)...)
+	}
+	if sc.naming == ServiceImportNamingClusterSet {
+		// the endpoints of headless services with a hostname are named after it and their cluster
+		names := make([]string, 0, len(hosts))
+		for name := range hosts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			endpoints = append(endpoints, endpointsForHostname(name+"."+sc.clusterSetName(si), hosts[name], ttl, providerSpecific, setIdentifier, resource)...)
+		}
+	}
+	return endpoints, nil
+}
+
+// clusterSetName returns the name of the ServiceImport following the Multi-Cluster DNS specification.
+func (sc *serviceImportSource) clusterSetName(si *serviceImport) string {
+	return fmt.Sprintf("%s.%s.svc.%s", si.Name, si.Namespace, sc.clusterSetDomain)
+}
+
+// headlessServiceImportTargets returns the ready addresses of the EndpointSlices of a headless ServiceImport,
+// along with the addresses of the endpoints with a hostname by <hostname>.<cluster ID>.
+func headlessServiceImportTargets(endpointSlices []*discoveryv1.EndpointSlice) (endpoint.Targets, map[string]endpoint.Targets) {
+	addresses := endpoint.Targets{}
+	hosts := map[string]endpoint.Targets{}
+	for _, slice := range endpointSlices {
+		if slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		cluster := slice.Labels[mcsSourceClusterLabel]
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			addresses = append(addresses, ep.Addresses...)
+			if ep.Hostname != nil && *ep.Hostname != "" && cluster != "" {
+				name := *ep.Hostname + "." + cluster
+				hosts[name] = append(hosts[name], ep.Addresses...)
+			}
+		}
+	}
+	return addresses, hosts
+}
+
+func (sc *serviceImportSource) AddEventHandler(ctx context.Context, handler func()) {
+	log.Debug("Adding event handler for ServiceImport")
+
+	sc.serviceImportInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	sc.endpointSlicesInformer.AddEventHandler(eventHandlerFunc(handler))
+}
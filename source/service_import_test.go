@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// This is a compile-time validation that serviceImportSource is a Source.
+var _ Source = &serviceImportSource{}
+
+func newTestServiceImport(t *testing.T, name, importType string, ips []string, annotations map[string]string) *unstructured.Unstructured {
+	si := &serviceImport{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: serviceImportGVR.GroupVersion().String(),
+			Kind:       "ServiceImport",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+		Spec: serviceImportSpec{Type: importType, IPs: ips},
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(si)
+	require.NoError(t, err)
+	return &unstructured.Unstructured{Object: content}
+}
+
+func newTestImportedEndpointSlice(name, service, cluster string, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				mcsServiceNameLabel:   service,
+				mcsSourceClusterLabel: cluster,
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   endpoints,
+	}
+}
+
+func newServiceImportTestSource(t *testing.T, naming string, objects []*unstructured.Unstructured, slices ...*discoveryv1.EndpointSlice) Source {
+	ctx := context.Background()
+	dynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		serviceImportGVR: "ServiceImportList",
+	})
+	for _, obj := range objects {
+		_, err := dynamicClient.Resource(serviceImportGVR).Namespace("default").Create(ctx, obj, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	kubeClient := fakeKube.NewSimpleClientset()
+	for _, slice := range slices {
+		_, err := kubeClient.DiscoveryV1().EndpointSlices("default").Create(ctx, slice, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	src, err := NewServiceImportSource(ctx, dynamicClient, kubeClient, "", "", false, naming, "clusterset.example.org.")
+	require.NoError(t, err)
+	return src
+}
+
+func newServiceImportEndpoint(dnsName, service string, targets ...string) *endpoint.Endpoint {
+	ep := endpoint.NewEndpoint(dnsName, endpoint.RecordTypeA, targets...)
+	ep.Labels[endpoint.ResourceLabelKey] = "serviceimport/default/" + service
+	return ep
+}
+
+func TestServiceImportSourceClusterSetNaming(t *testing.T) {
+	ready, notReady := true, false
+	web, db := "web-0", "db-0"
+	src := newServiceImportTestSource(t, ServiceImportNamingClusterSet,
+		[]*unstructured.Unstructured{
+			newTestServiceImport(t, "api", "ClusterSetIP", []string{"10.0.0.10"}, map[string]string{
+				hostnameAnnotationKey: "api.example.org",
+			}),
+			newTestServiceImport(t, "web", serviceImportTypeHeadless, nil, nil),
+			newTestServiceImport(t, "pending", "ClusterSetIP", nil, nil),
+		},
+		newTestImportedEndpointSlice("web-east", "web", "east",
+			discoveryv1.Endpoint{Addresses: []string{"10.1.0.1"}, Hostname: &web, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+			discoveryv1.Endpoint{Addresses: []string{"10.1.0.2"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+		),
+		newTestImportedEndpointSlice("web-west", "web", "west",
+			discoveryv1.Endpoint{Addresses: []string{"10.2.0.1"}, Hostname: &web},
+		),
+		newTestImportedEndpointSlice("db-west", "db", "west",
+			discoveryv1.Endpoint{Addresses: []string{"10.2.0.9"}, Hostname: &db},
+		),
+	)
+
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		newServiceImportEndpoint("api.example.org", "api", "10.0.0.10"),
+		newServiceImportEndpoint("api.default.svc.clusterset.example.org", "api", "10.0.0.10"),
+		newServiceImportEndpoint("web.default.svc.clusterset.example.org", "web", "10.1.0.1", "10.2.0.1"),
+		newServiceImportEndpoint("web-0.east.web.default.svc.clusterset.example.org", "web", "10.1.0.1"),
+		newServiceImportEndpoint("web-0.west.web.default.svc.clusterset.example.org", "web", "10.2.0.1"),
+	})
+}
+
+func TestServiceImportSourceAnnotationNaming(t *testing.T) {
+	src := newServiceImportTestSource(t, ServiceImportNamingAnnotation, []*unstructured.Unstructured{
+		newTestServiceImport(t, "api", "ClusterSetIP", []string{"10.0.0.10"}, map[string]string{
+			hostnameAnnotationKey: "api.example.org",
+		}),
+		newTestServiceImport(t, "internal", "ClusterSetIP", []string{"10.0.0.11"}, nil),
+	})
+
+	endpoints, err := src.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		newServiceImportEndpoint("api.example.org", "api", "10.0.0.10"),
+	})
+}
+
+func TestServiceImportSourceUnknownNaming(t *testing.T) {
+	_, err := NewServiceImportSource(context.Background(), nil, nil, "", "", false, "unknown", "clusterset.local")
+	assert.Error(t, err)
+}
@@ -18,6 +18,7 @@ package source
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"sort"
 	"strings"
@@ -27,6 +28,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/fake"
@@ -79,6 +81,7 @@ func (suite *ServiceSuite) SetupTest() {
 		false,
 		labels.Everything(),
 		false,
+		false,
 	)
 	suite.NoError(err, "should initialize service source")
 }
@@ -160,6 +163,7 @@ func testServiceSourceNewServiceSource(t *testing.T) {
 				false,
 				labels.Everything(),
 				false,
+				false,
 			)
 
 			if ti.expectError {
@@ -1126,6 +1130,7 @@ func testServiceSourceEndpoints(t *testing.T) {
 				tc.ignoreHostnameAnnotation,
 				sourceLabel,
 				tc.resolveLoadBalancerHostname,
+				false,
 			)
 
 			require.NoError(t, err)
@@ -1316,6 +1321,7 @@ func testMultipleServicesEndpoints(t *testing.T) {
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
 				false,
+				false,
 			)
 			require.NoError(t, err)
 
@@ -1608,6 +1614,7 @@ func TestClusterIpServices(t *testing.T) {
 				tc.ignoreHostnameAnnotation,
 				labelSelector,
 				false,
+				false,
 			)
 			require.NoError(t, err)
 
@@ -1690,6 +1697,41 @@ func TestServiceSourceNodePortServices(t *testing.T) {
 				},
 			}},
 		},
+		{
+			title:            "NodePort services annotated with an SRV hostname return an SRV endpoint pointing to the nodes",
+			svcNamespace:     "testing",
+			svcName:          "foo",
+			svcType:          v1.ServiceTypeNodePort,
+			svcTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeCluster,
+			annotations: map[string]string{
+				hostnameAnnotationKey: "_minecraft._tcp.mc.example.org",
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "_minecraft._tcp.mc.example.org", Targets: endpoint.Targets{"0 50 30192 mc.example.org"}, RecordType: endpoint.RecordTypeSRV},
+				{DNSName: "mc.example.org", Targets: endpoint.Targets{"54.10.11.1", "54.10.11.2"}, RecordType: endpoint.RecordTypeA},
+			},
+			nodes: []*v1.Node{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node1",
+				},
+				Status: v1.NodeStatus{
+					Addresses: []v1.NodeAddress{
+						{Type: v1.NodeExternalIP, Address: "54.10.11.1"},
+						{Type: v1.NodeInternalIP, Address: "10.0.1.1"},
+					},
+				},
+			}, {
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node2",
+				},
+				Status: v1.NodeStatus{
+					Addresses: []v1.NodeAddress{
+						{Type: v1.NodeExternalIP, Address: "54.10.11.2"},
+						{Type: v1.NodeInternalIP, Address: "10.0.1.2"},
+					},
+				},
+			}},
+		},
 		{
 			title:                    "hostname annotated NodePort services are ignored",
 			svcNamespace:             "testing",
@@ -2326,6 +2368,7 @@ func TestServiceSourceNodePortServices(t *testing.T) {
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
 				false,
+				false,
 			)
 			require.NoError(t, err)
 
@@ -2342,6 +2385,124 @@ func TestServiceSourceNodePortServices(t *testing.T) {
 	}
 }
 
+func TestServiceSourceTopologyHostnames(t *testing.T) {
+	ctx := context.Background()
+	kubernetes := fake.NewSimpleClientset()
+
+	for i, zone := range []string{"eu-west-1a", "eu-west-1b", ""} {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("node%d", i+1),
+				Labels: map[string]string{},
+			},
+			Status: v1.NodeStatus{
+				Addresses: []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: fmt.Sprintf("54.10.11.%d", i+1)}},
+			},
+		}
+		if zone != "" {
+			node.Labels[v1.LabelTopologyZone] = zone
+		}
+		_, err := kubernetes.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "testing",
+			Name:        "foo",
+			Annotations: map[string]string{hostnameAnnotationKey: "foo.example.org"},
+		},
+		Spec: v1.ServiceSpec{
+			Type:                  v1.ServiceTypeNodePort,
+			ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeCluster,
+			Ports:                 []v1.ServicePort{{NodePort: 30192}},
+		},
+	}
+	_, err := kubernetes.CoreV1().Services(service.Namespace).Create(ctx, service, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	client, err := NewServiceSource(ctx, kubernetes, "", "", "", false, "", false, false, false, []string{}, false, labels.Everything(), false, true)
+	require.NoError(t, err)
+
+	endpoints, err := client.Endpoints(ctx)
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "_foo._tcp.foo.example.org", Targets: endpoint.Targets{"0 50 30192 foo.example.org"}, RecordType: endpoint.RecordTypeSRV},
+		{DNSName: "foo.example.org", Targets: endpoint.Targets{"54.10.11.1", "54.10.11.2", "54.10.11.3"}, RecordType: endpoint.RecordTypeA},
+		{DNSName: "foo.eu-west-1a.example.org", Targets: endpoint.Targets{"54.10.11.1"}, RecordType: endpoint.RecordTypeA},
+		{DNSName: "foo.eu-west-1b.example.org", Targets: endpoint.Targets{"54.10.11.2"}, RecordType: endpoint.RecordTypeA},
+	})
+}
+
+func TestZoneEndpoints(t *testing.T) {
+	zones := map[string]string{
+		"10.0.0.1":    "eu-west-1a",
+		"10.0.0.2":    "eu-west-1a",
+		"10.0.0.3":    "EU-West-1b",
+		"2001:db8::1": "eu-west-1b",
+	}
+	a := endpoint.NewEndpointWithTTL("svc.example.org", endpoint.RecordTypeA, 60, "10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4")
+	a.SetIdentifier = "eu"
+
+	assert.Equal(t, []*endpoint.Endpoint{
+		{DNSName: "svc.eu-west-1a.example.org", Targets: endpoint.Targets{"10.0.0.1", "10.0.0.2"}, RecordType: endpoint.RecordTypeA, RecordTTL: 60, SetIdentifier: "eu", Labels: endpoint.Labels{}},
+		{DNSName: "svc.eu-west-1b.example.org", Targets: endpoint.Targets{"10.0.0.3"}, RecordType: endpoint.RecordTypeA, RecordTTL: 60, SetIdentifier: "eu", Labels: endpoint.Labels{}},
+		{DNSName: "svc.eu-west-1b.example.org", Targets: endpoint.Targets{"2001:db8::1"}, RecordType: endpoint.RecordTypeAAAA, Labels: endpoint.Labels{}},
+	}, zoneEndpoints([]*endpoint.Endpoint{
+		a,
+		endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeAAAA, "2001:db8::1"),
+		endpoint.NewEndpoint("*.svc.example.org", endpoint.RecordTypeA, "10.0.0.1"),
+		endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeCNAME, "lb.example.org"),
+	}, zones))
+
+	assert.Equal(t, "svc.eu-west-1a", zoneHostname("svc", "eu-west-1a"))
+}
+
+func TestExtractNodePortSRVEndpoint(t *testing.T) {
+	svc := &v1.Service{
+		Spec: v1.ServiceSpec{
+			Type: v1.ServiceTypeNodePort,
+			Ports: []v1.ServicePort{
+				{Name: "minecraft", Protocol: v1.ProtocolTCP, NodePort: 30565},
+				{Name: "rcon", Protocol: v1.ProtocolTCP, NodePort: 30575},
+				{Name: "query", Protocol: v1.ProtocolUDP, NodePort: 30566},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		hostname string
+		expected *endpoint.Endpoint
+	}{
+		{
+			hostname: "_minecraft._tcp.mc.example.org",
+			expected: endpoint.NewEndpoint("_minecraft._tcp.mc.example.org", endpoint.RecordTypeSRV, "0 50 30565 mc.example.org"),
+		},
+		{
+			hostname: "_game._tcp.mc.example.org",
+			expected: endpoint.NewEndpoint("_game._tcp.mc.example.org", endpoint.RecordTypeSRV, "0 50 30565 mc.example.org", "0 50 30575 mc.example.org"),
+		},
+		{
+			hostname: "_query._UDP.mc.example.org",
+			expected: endpoint.NewEndpoint("_query._UDP.mc.example.org", endpoint.RecordTypeSRV, "0 50 30566 mc.example.org"),
+		},
+		{
+			hostname: "_minecraft._sctp.mc.example.org",
+		},
+	} {
+		t.Run(tc.hostname, func(t *testing.T) {
+			service, protocol, host, ok := splitSRVHostname(tc.hostname)
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, extractNodePortSRVEndpoint(svc, tc.hostname, service, protocol, host, 0))
+		})
+	}
+
+	for _, hostname := range []string{"mc.example.org", "_minecraft.mc.example.org", "_._tcp.example.org", "_minecraft._tcp"} {
+		_, _, _, ok := splitSRVHostname(hostname)
+		assert.False(t, ok, hostname)
+	}
+}
+
 // TestHeadlessServices tests that headless services generate the correct endpoints.
 func TestHeadlessServices(t *testing.T) {
 	t.Parallel()
@@ -2982,7 +3143,7 @@ func TestHeadlessServices(t *testing.T) {
 			_, err := kubernetes.CoreV1().Services(service.Namespace).Create(context.Background(), service, metav1.CreateOptions{})
 			require.NoError(t, err)
 
-			var addresses, notReadyAddresses []v1.EndpointAddress
+			var sliceEndpoints []discoveryv1.Endpoint
 			for i, podname := range tc.podnames {
 				pod := &v1.Pod{
 					Spec: v1.PodSpec{
@@ -3004,34 +3165,20 @@ func TestHeadlessServices(t *testing.T) {
 				_, err = kubernetes.CoreV1().Pods(tc.svcNamespace).Create(context.Background(), pod, metav1.CreateOptions{})
 				require.NoError(t, err)
 
-				address := v1.EndpointAddress{
-					IP: tc.podIPs[i],
+				sliceEndpoints = append(sliceEndpoints, discoveryv1.Endpoint{
+					Addresses: []string{tc.podIPs[i]},
+					Conditions: discoveryv1.EndpointConditions{
+						Ready: &tc.podsReady[i],
+					},
 					TargetRef: &v1.ObjectReference{
 						APIVersion: "",
 						Kind:       "Pod",
 						Name:       podname,
 					},
-				}
-				if tc.podsReady[i] {
-					addresses = append(addresses, address)
-				} else {
-					notReadyAddresses = append(notReadyAddresses, address)
-				}
+				})
 			}
-			endpointsObject := &v1.Endpoints{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: tc.svcNamespace,
-					Name:      tc.svcName,
-					Labels:    tc.labels,
-				},
-				Subsets: []v1.EndpointSubset{
-					{
-						Addresses:         addresses,
-						NotReadyAddresses: notReadyAddresses,
-					},
-				},
-			}
-			_, err = kubernetes.CoreV1().Endpoints(tc.svcNamespace).Create(context.Background(), endpointsObject, metav1.CreateOptions{})
+			endpointSlice := newTestEndpointSlice(tc.svcNamespace, tc.svcName, tc.labels, sliceEndpoints)
+			_, err = kubernetes.DiscoveryV1().EndpointSlices(tc.svcNamespace).Create(context.Background(), endpointSlice, metav1.CreateOptions{})
 			require.NoError(t, err)
 			for _, node := range tc.nodes {
 				_, err = kubernetes.CoreV1().Nodes().Create(context.Background(), &node, metav1.CreateOptions{})
@@ -3054,6 +3201,7 @@ func TestHeadlessServices(t *testing.T) {
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
 				false,
+				false,
 			)
 			require.NoError(t, err)
 
@@ -3449,8 +3597,7 @@ func TestHeadlessServicesHostIP(t *testing.T) {
 			_, err := kubernetes.CoreV1().Services(service.Namespace).Create(context.Background(), service, metav1.CreateOptions{})
 			require.NoError(t, err)
 
-			var addresses []v1.EndpointAddress
-			var notReadyAddresses []v1.EndpointAddress
+			var sliceEndpoints []discoveryv1.Endpoint
 			for i, podname := range tc.podnames {
 				pod := &v1.Pod{
 					Spec: v1.PodSpec{
@@ -3471,30 +3618,16 @@ func TestHeadlessServicesHostIP(t *testing.T) {
 				_, err = kubernetes.CoreV1().Pods(tc.svcNamespace).Create(context.Background(), pod, metav1.CreateOptions{})
 				require.NoError(t, err)
 
-				address := v1.EndpointAddress{
-					IP:        "4.3.2.1",
-					TargetRef: tc.targetRefs[i],
-				}
-				if tc.podsReady[i] {
-					addresses = append(addresses, address)
-				} else {
-					notReadyAddresses = append(notReadyAddresses, address)
-				}
-			}
-			endpointsObject := &v1.Endpoints{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: tc.svcNamespace,
-					Name:      tc.svcName,
-					Labels:    tc.labels,
-				},
-				Subsets: []v1.EndpointSubset{
-					{
-						Addresses:         addresses,
-						NotReadyAddresses: notReadyAddresses,
+				sliceEndpoints = append(sliceEndpoints, discoveryv1.Endpoint{
+					Addresses: []string{"4.3.2.1"},
+					Conditions: discoveryv1.EndpointConditions{
+						Ready: &tc.podsReady[i],
 					},
-				},
+					TargetRef: tc.targetRefs[i],
+				})
 			}
-			_, err = kubernetes.CoreV1().Endpoints(tc.svcNamespace).Create(context.Background(), endpointsObject, metav1.CreateOptions{})
+			endpointSlice := newTestEndpointSlice(tc.svcNamespace, tc.svcName, tc.labels, sliceEndpoints)
+			_, err = kubernetes.DiscoveryV1().EndpointSlices(tc.svcNamespace).Create(context.Background(), endpointSlice, metav1.CreateOptions{})
 			require.NoError(t, err)
 
 			// Create our object under test and get the endpoints.
@@ -3513,6 +3646,7 @@ func TestHeadlessServicesHostIP(t *testing.T) {
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
 				false,
+				false,
 			)
 			require.NoError(t, err)
 
@@ -3529,6 +3663,175 @@ func TestHeadlessServicesHostIP(t *testing.T) {
 	}
 }
 
+// TestHeadlessServicesEndpointSlices tests that headless services honor the conditions of EndpointSlice endpoints.
+func TestHeadlessServicesEndpointSlices(t *testing.T) {
+	t.Parallel()
+
+	ready := func(ready, serving, terminating bool) discoveryv1.EndpointConditions {
+		return discoveryv1.EndpointConditions{Ready: &ready, Serving: &serving, Terminating: &terminating}
+	}
+	podRef := func(name string) *v1.ObjectReference {
+		return &v1.ObjectReference{Kind: "Pod", Name: name}
+	}
+	hostname := func(hostname string) *string {
+		return &hostname
+	}
+
+	for _, tc := range []struct {
+		title                    string
+		publishNotReadyAddresses bool
+		slices                   []*discoveryv1.EndpointSlice
+		expected                 []*endpoint.Endpoint
+	}{
+		{
+			title: "endpoints with unset conditions are ready",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("testing", "foo", nil, []discoveryv1.Endpoint{
+					{Addresses: []string{"1.1.1.1"}, TargetRef: podRef("foo-0")},
+				}),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "service.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.1.1.1"}},
+			},
+		},
+		{
+			title: "terminating endpoints are skipped",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("testing", "foo", nil, []discoveryv1.Endpoint{
+					{Addresses: []string{"1.1.1.1"}, TargetRef: podRef("foo-0"), Conditions: ready(true, true, false)},
+					{Addresses: []string{"1.1.1.2"}, TargetRef: podRef("foo-1"), Conditions: ready(false, true, true)},
+				}),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "service.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.1.1.1"}},
+			},
+		},
+		{
+			title:                    "terminating endpoints that still serve are published with publishNotReadyAddresses",
+			publishNotReadyAddresses: true,
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("testing", "foo", nil, []discoveryv1.Endpoint{
+					{Addresses: []string{"1.1.1.1"}, TargetRef: podRef("foo-0"), Conditions: ready(false, false, false)},
+					{Addresses: []string{"1.1.1.2"}, TargetRef: podRef("foo-1"), Conditions: ready(false, true, true)},
+					{Addresses: []string{"1.1.1.3"}, TargetRef: podRef("foo-2"), Conditions: ready(false, false, true)},
+				}),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "service.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.1.1.1", "1.1.1.2"}},
+			},
+		},
+		{
+			title: "endpoints spread over several slices are merged",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("testing", "foo", nil, []discoveryv1.Endpoint{
+					{Addresses: []string{"1.1.1.1"}, TargetRef: podRef("foo-0")},
+				}),
+				newTestEndpointSlice("testing", "foo", nil, []discoveryv1.Endpoint{
+					{Addresses: []string{"1.1.1.2"}, TargetRef: podRef("foo-1")},
+				}),
+				newTestEndpointSlice("testing", "foo", nil, []discoveryv1.Endpoint{
+					{Addresses: []string{"2001:db8::1"}, TargetRef: podRef("foo-0")},
+				}),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "service.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.1.1.1", "1.1.1.2"}},
+				{DNSName: "service.example.org", RecordType: endpoint.RecordTypeAAAA, Targets: endpoint.Targets{"2001:db8::1"}},
+			},
+		},
+		{
+			title: "slices of other services are ignored",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("testing", "foo", nil, []discoveryv1.Endpoint{
+					{Addresses: []string{"1.1.1.1"}, TargetRef: podRef("foo-0")},
+				}),
+				newTestEndpointSlice("testing", "bar", nil, []discoveryv1.Endpoint{
+					{Addresses: []string{"1.1.1.2"}, TargetRef: podRef("foo-1")},
+				}),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "service.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.1.1.1"}},
+			},
+		},
+		{
+			title: "the hostname recorded on the endpoint is used",
+			slices: []*discoveryv1.EndpointSlice{
+				newTestEndpointSlice("testing", "foo", nil, []discoveryv1.Endpoint{
+					{Addresses: []string{"1.1.1.1"}, TargetRef: podRef("foo-0"), Hostname: hostname("web-0")},
+				}),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "service.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.1.1.1"}},
+				{DNSName: "web-0.service.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.1.1.1"}},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+
+			kubernetes := fake.NewSimpleClientset()
+
+			service := &v1.Service{
+				Spec: v1.ServiceSpec{
+					Type:                     v1.ServiceTypeClusterIP,
+					ClusterIP:                v1.ClusterIPNone,
+					Selector:                 map[string]string{"component": "foo"},
+					PublishNotReadyAddresses: tc.publishNotReadyAddresses,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "testing",
+					Name:        "foo",
+					Annotations: map[string]string{hostnameAnnotationKey: "service.example.org"},
+				},
+			}
+			_, err := kubernetes.CoreV1().Services(service.Namespace).Create(context.Background(), service, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			for _, podname := range []string{"foo-0", "foo-1", "foo-2"} {
+				pod := &v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "testing",
+						Name:      podname,
+						Labels:    map[string]string{"component": "foo"},
+					},
+				}
+				_, err = kubernetes.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			for i, endpointSlice := range tc.slices {
+				endpointSlice.Name = fmt.Sprintf("%s-%d", endpointSlice.Name, i)
+				_, err = kubernetes.DiscoveryV1().EndpointSlices(endpointSlice.Namespace).Create(context.Background(), endpointSlice, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			client, err := NewServiceSource(
+				context.TODO(),
+				kubernetes,
+				"",
+				"",
+				"",
+				false,
+				"",
+				true,
+				false,
+				false,
+				[]string{},
+				false,
+				labels.Everything(),
+				false,
+				false,
+			)
+			require.NoError(t, err)
+
+			endpoints, err := client.Endpoints(context.Background())
+			require.NoError(t, err)
+
+			validateEndpoints(t, endpoints, tc.expected)
+		})
+	}
+}
+
 // TestExternalServices tests that external services generate the correct endpoints.
 func TestExternalServices(t *testing.T) {
 	t.Parallel()
@@ -3691,6 +3994,7 @@ func TestExternalServices(t *testing.T) {
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
 				false,
+				false,
 			)
 			require.NoError(t, err)
 
@@ -3746,6 +4050,7 @@ func BenchmarkServiceEndpoints(b *testing.B) {
 		false,
 		labels.Everything(),
 		false,
+		false,
 	)
 	require.NoError(b, err)
 
@@ -3754,3 +4059,26 @@ func BenchmarkServiceEndpoints(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+// newTestEndpointSlice returns an EndpointSlice of the given service holding the given endpoints.
+func newTestEndpointSlice(namespace, service string, sliceLabels map[string]string, endpoints []discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	addressType := discoveryv1.AddressTypeIPv4
+	if len(endpoints) > 0 && len(endpoints[0].Addresses) > 0 && suitableType(endpoints[0].Addresses[0]) == endpoint.RecordTypeAAAA {
+		addressType = discoveryv1.AddressTypeIPv6
+	}
+
+	objectLabels := map[string]string{discoveryv1.LabelServiceName: service}
+	for k, v := range sliceLabels {
+		objectLabels[k] = v
+	}
+
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      service,
+			Labels:    objectLabels,
+		},
+		AddressType: addressType,
+		Endpoints:   endpoints,
+	}
+}
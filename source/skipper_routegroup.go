@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -46,6 +47,16 @@ const (
 	routeGroupNamespacedResource = "/apis/%s/namespaces/%s/routegroups"
 )
 
+var (
+	// routeGroupHostPredicate matches a Host() predicate with its regular expression given as a string or as a
+	// regular expression literal, e.g. Host("^www[.]example[.]org$") or Host(/^www\.example\.org$/).
+	routeGroupHostPredicate = regexp.MustCompile(`^Host\(\s*(?:"((?:[^"\\]|\\.)*)"|/((?:[^/\\]|\\.)*)/)\s*\)$`)
+	// routeGroupHostPort matches the optional port suffix commonly added to the Host() regular expressions.
+	routeGroupHostPort = regexp.MustCompile(`\(:(?:\\d|\[0-9\])(?:\+|\*)\)\?$`)
+	// routeGroupLiteralHost matches a hostname once the escaped dots of a regular expression are unescaped.
+	routeGroupLiteralHost = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?$`)
+)
+
 type routeGroupSource struct {
 	cli                      routeGroupListClient
 	apiServer                string
@@ -281,6 +292,7 @@ func (sc *routeGroupSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint
 
 		log.Debugf("Endpoints generated from ingress: %s/%s: %v", rg.Metadata.Namespace, rg.Metadata.Name, eps)
 		sc.setRouteGroupDualstackLabel(rg, eps)
+		eps = withRecordType(rg.Metadata.Annotations, eps)
 		endpoints = append(endpoints, eps...)
 	}
 
@@ -344,22 +356,17 @@ func (sc *routeGroupSource) endpointsFromRouteGroup(rg *routeGroup) []*endpoint.
 
 	targets := getTargetsFromTargetAnnotation(rg.Metadata.Annotations)
 	if len(targets) == 0 {
-		for _, lb := range rg.Status.LoadBalancer.RouteGroup {
-			if lb.IP != "" {
-				targets = append(targets, lb.IP)
-			}
-			if lb.Hostname != "" {
-				targets = append(targets, lb.Hostname)
-			}
-		}
+		targets = targetsFromRouteGroupStatus(rg.Status)
 	}
 
 	providerSpecific, setIdentifier := getProviderSpecificAnnotations(rg.Metadata.Annotations)
 
-	for _, src := range rg.Spec.Hosts {
-		if src == "" {
+	hosts := map[string]struct{}{}
+	for _, src := range append(rg.Spec.Hosts, hostnamesFromRouteGroupPredicates(rg.Spec.Routes)...) {
+		if _, ok := hosts[src]; ok || src == "" {
 			continue
 		}
+		hosts[src] = struct{}{}
 		endpoints = append(endpoints, endpointsForHostname(src, targets, ttl, providerSpecific, setIdentifier, resource)...)
 	}
 
@@ -397,6 +404,49 @@ func (sc *routeGroupSource) filterByAnnotations(rgs *routeGroupList) (*routeGrou
 	return rgs, nil
 }
 
+// hostnamesFromRouteGroupPredicates returns the hostnames matched by the Host() predicates of the routes of a
+// RouteGroup. Predicates whose regular expression matches more than a single literal hostname are skipped.
+func hostnamesFromRouteGroupPredicates(routes []routeGroupRoute) []string {
+	var hostnames []string
+	for _, route := range routes {
+		for _, predicates := range route.Predicates {
+			for _, predicate := range strings.Split(predicates, "&&") {
+				hostname, ok := hostnameFromHostPredicate(strings.TrimSpace(predicate))
+				if ok {
+					hostnames = append(hostnames, hostname)
+				}
+			}
+		}
+	}
+	return hostnames
+}
+
+// hostnameFromHostPredicate returns the hostname matched by a Host() predicate, if its regular expression matches a
+// single hostname, optionally followed by a port.
+func hostnameFromHostPredicate(predicate string) (string, bool) {
+	match := routeGroupHostPredicate.FindStringSubmatch(predicate)
+	if match == nil {
+		return "", false
+	}
+
+	var expr string
+	if strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(predicate, "Host(")), `"`) {
+		expr = strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(match[1])
+	} else {
+		expr = strings.ReplaceAll(match[2], `\/`, `/`)
+	}
+
+	expr = strings.TrimPrefix(expr, "^")
+	expr = strings.TrimSuffix(expr, "$")
+	expr = routeGroupHostPort.ReplaceAllString(expr, "")
+	hostname := strings.NewReplacer(`\.`, ".", "[.]", ".").Replace(expr)
+	if !routeGroupLiteralHost.MatchString(hostname) {
+		log.Debugf("Skipping predicate %s not matching a single hostname", predicate)
+		return "", false
+	}
+	return strings.ToLower(hostname), true
+}
+
 func targetsFromRouteGroupStatus(status routeGroupStatus) endpoint.Targets {
 	var targets endpoint.Targets
 
@@ -437,7 +487,12 @@ type itemMetadata struct {
 }
 
 type routeGroupSpec struct {
-	Hosts []string `json:"hosts"`
+	Hosts  []string          `json:"hosts"`
+	Routes []routeGroupRoute `json:"routes,omitempty"`
+}
+
+type routeGroupRoute struct {
+	Predicates []string `json:"predicates,omitempty"`
 }
 
 type routeGroupStatus struct {
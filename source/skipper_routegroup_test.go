@@ -224,6 +224,52 @@ func TestEndpointsFromRouteGroups(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "Routegroup with Host() predicates creates endpoints for the hosts they match",
+			source: &routeGroupSource{},
+			rg: func() *routeGroup {
+				rg := createTestRouteGroup(
+					"namespace1",
+					"rg1",
+					map[string]string{
+						ttlAnnotationKey: "60",
+					},
+					[]string{"rg1.k8s.example"},
+					[]routeGroupLoadBalancer{
+						{
+							Hostname: "lb.example.org",
+						},
+					},
+				)
+				rg.Spec.Routes = []routeGroupRoute{
+					{Predicates: []string{`Host("^rg1[.]k8s[.]example$")`}},
+					{Predicates: []string{`Path("/api")`, `Host(/^api\.k8s\.example(:\d+)?$/)`}},
+					{Predicates: []string{`Method("GET") && Host("^www\\.k8s\\.example$")`}},
+					{Predicates: []string{`Host(/^.*[.]k8s[.]example$/)`}},
+				}
+				return rg
+			}(),
+			want: []*endpoint.Endpoint{
+				{
+					DNSName:    "rg1.k8s.example",
+					RecordType: endpoint.RecordTypeCNAME,
+					Targets:    endpoint.Targets([]string{"lb.example.org"}),
+					RecordTTL:  endpoint.TTL(60),
+				},
+				{
+					DNSName:    "api.k8s.example",
+					RecordType: endpoint.RecordTypeCNAME,
+					Targets:    endpoint.Targets([]string{"lb.example.org"}),
+					RecordTTL:  endpoint.TTL(60),
+				},
+				{
+					DNSName:    "www.k8s.example",
+					RecordType: endpoint.RecordTypeCNAME,
+					Targets:    endpoint.Targets([]string{"lb.example.org"}),
+					RecordTTL:  endpoint.TTL(60),
+				},
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			got := tt.source.endpointsFromRouteGroup(tt.rg)
@@ -233,6 +279,29 @@ func TestEndpointsFromRouteGroups(t *testing.T) {
 	}
 }
 
+func TestHostnameFromHostPredicate(t *testing.T) {
+	for _, tt := range []struct {
+		predicate string
+		hostname  string
+		ok        bool
+	}{
+		{predicate: `Host("^www[.]example[.]org$")`, hostname: "www.example.org", ok: true},
+		{predicate: `Host("^www\\.example\\.org$")`, hostname: "www.example.org", ok: true},
+		{predicate: `Host(/^www\.example\.org$/)`, hostname: "www.example.org", ok: true},
+		{predicate: `Host(/^WWW\.example\.org(:[0-9]+)?$/)`, hostname: "www.example.org", ok: true},
+		{predicate: `Host("www.example.org")`, hostname: "www.example.org", ok: true},
+		{predicate: `Host(/^.*\.example\.org$/)`},
+		{predicate: `Host(/^(www|api)\.example\.org$/)`},
+		{predicate: `Path("/www.example.org")`},
+	} {
+		t.Run(tt.predicate, func(t *testing.T) {
+			hostname, ok := hostnameFromHostPredicate(tt.predicate)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.hostname, hostname)
+		})
+	}
+}
+
 type fakeRouteGroupClient struct {
 	returnErr bool
 	rg        *routeGroupList
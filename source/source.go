@@ -19,6 +19,7 @@ package source
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"net"
@@ -53,6 +54,16 @@ const (
 	ttlAnnotationKey = "external-dns.alpha.kubernetes.io/ttl"
 	// The annotation used for switching to the alias record types e. g. AWS Alias records instead of a normal CNAME
 	aliasAnnotationKey = "external-dns.alpha.kubernetes.io/alias"
+	// The annotations used for swapping the targets of a hostname between two predefined sets of targets
+	blueTargetAnnotationKey   = "external-dns.alpha.kubernetes.io/blue-target"
+	greenTargetAnnotationKey  = "external-dns.alpha.kubernetes.io/green-target"
+	activeTargetAnnotationKey = "external-dns.alpha.kubernetes.io/active-target"
+	// The annotation used for requesting a percentage of the traffic of a hostname shared with other resources
+	canaryWeightAnnotationKey = "external-dns.alpha.kubernetes.io/canary-weight"
+	// The annotation used for pinning the records of a resource to a zone, given by its ID
+	zoneIDAnnotationKey = "external-dns.alpha.kubernetes.io/zone-id"
+	// The annotation used to prefer the endpoints of a resource over the conflicting endpoints of other sources
+	sourceWeightAnnotationKey = "external-dns.alpha.kubernetes.io/source-weight"
 	// The annotation used to determine the source of hostnames for ingresses.  This is an optional field - all
 	// available hostname sources are used if not specified.
 	ingressHostnameSourceKey = "external-dns.alpha.kubernetes.io/ingress-hostname-source"
@@ -60,8 +71,29 @@ const (
 	controllerAnnotationValue = "dns-controller"
 	// The annotation used for defining the desired hostname
 	internalHostnameAnnotationKey = "external-dns.alpha.kubernetes.io/internal-hostname"
+	// The annotation used for recording the git commit the resource was deployed from
+	commitAnnotationKey = "external-dns.alpha.kubernetes.io/commit"
+	// The annotation used for publishing the wildcard of each hostname along with the hostname
+	publishWildcardAnnotationKey = "external-dns.alpha.kubernetes.io/publish-wildcard"
+	// The annotation used for listing the hostnames of the Traefik HostRegexp matchers which match any hostname
+	hostRegexpHostnamesAnnotationKey = "external-dns.alpha.kubernetes.io/host-regexp-hostnames"
+	// The annotation used for publishing SSHFP records of the SSH host keys of nodes
+	sshHostKeysAnnotationKey = "external-dns.alpha.kubernetes.io/ssh-host-keys"
+	// The annotation used for resolving the targets of Traefik routes from the load balancer of a Traefik Service
+	traefikServiceAnnotationKey = "external-dns.alpha.kubernetes.io/traefik-service"
+	// The annotation used for resolving the targets of Kong TCPIngresses and UDPIngresses from the load balancer of a Kong proxy Service
+	kongProxyServiceAnnotationKey = "external-dns.alpha.kubernetes.io/kong-proxy-service"
+	// The annotation used for resolving the targets of Knative DomainMappings and Routes from the load balancer of a Knative ingress Service
+	knativeIngressServiceAnnotationKey = "external-dns.alpha.kubernetes.io/knative-ingress-service"
+	// The annotation used for defining the hostnames of the preview Service of a blue-green Argo Rollout
+	previewHostnameAnnotationKey = "external-dns.alpha.kubernetes.io/preview-hostname"
+	// The annotation used for forcing the record type the targets of a resource are published with
+	recordTypeAnnotationKey = "external-dns.alpha.kubernetes.io/record-type"
 )
 
+// targetResolver resolves the hostname targets published as A or AAAA records with the record-type annotation.
+var targetResolver = newHostnameResolver(net.DefaultResolver.LookupIP, hostnameLookupTimeout, hostnameCacheTTL)
+
 const (
 	EndpointsTypeNodeExternalIP = "NodeExternalIP"
 	EndpointsTypeHostIP         = "HostIP"
@@ -71,6 +103,8 @@ const (
 const (
 	// The annotation used for determining if traffic will go through Cloudflare
 	CloudflareProxiedKey = "external-dns.alpha.kubernetes.io/cloudflare-proxied"
+	// The annotation used for routing traffic through a Cloudflare Tunnel
+	CloudflareTunnelIDKey = "external-dns.alpha.kubernetes.io/cloudflare-tunnel-id"
 
 	SetIdentifierKey = "external-dns.alpha.kubernetes.io/set-identifier"
 )
@@ -87,6 +121,31 @@ type Source interface {
 	AddEventHandler(context.Context, func())
 }
 
+// SyncReporter is implemented by the sources which report the outcome of the synchronizations back to the
+// resources their endpoints originate from.
+type SyncReporter interface {
+	ReportSync(ctx context.Context, report SyncReport)
+}
+
+// SyncReport is the outcome of a synchronization for the desired endpoints.
+type SyncReport struct {
+	// Time is when the synchronization finished.
+	Time time.Time
+	// Records are the outcomes of the desired endpoints.
+	Records []RecordSyncResult
+}
+
+// RecordSyncResult is the outcome of a synchronization for a desired endpoint.
+type RecordSyncResult struct {
+	Endpoint *endpoint.Endpoint
+	// Synced is whether the record is in the DNS provider as desired.
+	Synced bool
+	// Applied is whether the record was created or updated by the synchronization.
+	Applied bool
+	// Error is why the record is not synced.
+	Error string
+}
+
 func getTTLFromAnnotations(annotations map[string]string, resource string) endpoint.TTL {
 	ttlNotConfigured := endpoint.TTL(0)
 	ttlAnnotation, exists := annotations[ttlAnnotationKey]
@@ -161,6 +220,14 @@ func getHostnamesFromAnnotations(annotations map[string]string) []string {
 	return splitHostnameAnnotation(hostnameAnnotation)
 }
 
+func getPreviewHostnamesFromAnnotations(annotations map[string]string) []string {
+	previewHostnameAnnotation, exists := annotations[previewHostnameAnnotationKey]
+	if !exists {
+		return nil
+	}
+	return splitHostnameAnnotation(previewHostnameAnnotation)
+}
+
 func getAccessFromAnnotations(annotations map[string]string) string {
 	return annotations[accessAnnotationKey]
 }
@@ -196,12 +263,36 @@ func getProviderSpecificAnnotations(annotations map[string]string) (endpoint.Pro
 			Value: v,
 		})
 	}
+	if v, exists := annotations[CloudflareTunnelIDKey]; exists {
+		providerSpecificAnnotations = append(providerSpecificAnnotations, endpoint.ProviderSpecificProperty{
+			Name:  CloudflareTunnelIDKey,
+			Value: v,
+		})
+	}
 	if getAliasFromAnnotations(annotations) {
 		providerSpecificAnnotations = append(providerSpecificAnnotations, endpoint.ProviderSpecificProperty{
 			Name:  "alias",
 			Value: "true",
 		})
 	}
+	if v, exists := annotations[canaryWeightAnnotationKey]; exists {
+		providerSpecificAnnotations = append(providerSpecificAnnotations, endpoint.ProviderSpecificProperty{
+			Name:  endpoint.ProviderSpecificCanaryWeight,
+			Value: v,
+		})
+	}
+	if v, exists := annotations[zoneIDAnnotationKey]; exists && v != "" {
+		providerSpecificAnnotations = append(providerSpecificAnnotations, endpoint.ProviderSpecificProperty{
+			Name:  endpoint.ProviderSpecificZoneID,
+			Value: v,
+		})
+	}
+	if v, exists := annotations[sourceWeightAnnotationKey]; exists && v != "" {
+		providerSpecificAnnotations = append(providerSpecificAnnotations, endpoint.ProviderSpecificProperty{
+			Name:  endpoint.ProviderSpecificSourceWeight,
+			Value: v,
+		})
+	}
 	setIdentifier := ""
 	for k, v := range annotations {
 		if k == SetIdentifierKey {
@@ -229,14 +320,18 @@ func getProviderSpecificAnnotations(annotations map[string]string) (endpoint.Pro
 	return providerSpecificAnnotations, setIdentifier
 }
 
-// getTargetsFromTargetAnnotation gets endpoints from optional "target" annotation.
+// getTargetsFromTargetAnnotation gets endpoints from optional "target" annotation,
+// or else from the blue or green target annotation selected by the "active-target" annotation.
 // Returns empty endpoints array if none are found.
 func getTargetsFromTargetAnnotation(annotations map[string]string) endpoint.Targets {
 	var targets endpoint.Targets
 
 	// Get the desired hostname of the ingress from the annotation.
 	targetAnnotation, exists := annotations[targetAnnotationKey]
-	if exists && targetAnnotation != "" {
+	if !exists || targetAnnotation == "" {
+		_, targetAnnotation = getActiveTargetAnnotation(annotations)
+	}
+	if targetAnnotation != "" {
 		// splits the hostname annotation and removes the trailing periods
 		targetsList := strings.Split(strings.Replace(targetAnnotation, " ", "", -1), ",")
 		for _, targetHostname := range targetsList {
@@ -333,6 +428,195 @@ func endpointsForHostname(hostname string, targets endpoint.Targets, ttl endpoin
 	return endpoints
 }
 
+// withWildcards returns the endpoints along with an endpoint for the wildcard of each A, AAAA and CNAME
+// endpoint, with the same targets, if the publish-wildcard annotation of the resource is set to true.
+func withWildcards(annotations map[string]string, endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	if annotations[publishWildcardAnnotationKey] != "true" {
+		return endpoints
+	}
+	existing := make(map[endpoint.EndpointKey]bool, len(endpoints))
+	for _, ep := range endpoints {
+		existing[ep.Key()] = true
+	}
+	result := endpoints
+	for _, ep := range endpoints {
+		switch ep.RecordType {
+		case endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME:
+		default:
+			continue
+		}
+		if strings.HasPrefix(ep.DNSName, "*.") {
+			continue
+		}
+		wildcard := ep.DeepCopy()
+		wildcard.DNSName = "*." + ep.DNSName
+		if existing[wildcard.Key()] {
+			continue
+		}
+		existing[wildcard.Key()] = true
+		result = append(result, wildcard)
+	}
+	return result
+}
+
+// withRecordType returns the endpoints published with the record type set with the record-type annotation of the
+// resource, if any: the A, AAAA and CNAME endpoints of each name are replaced with a single endpoint of that type,
+// e.g. to publish a CNAME to the hostname of a load balancer also having IPs, or an A record at the apex of a zone
+// instead of a CNAME, in which case the CNAME targets are resolved. The endpoints of a name are kept as they are
+// when none of their targets suits the record type, e.g. when forcing a CNAME with IP targets only, and dropped
+// when one of their CNAME targets can't be resolved, rather than published with a type the annotation excludes.
+func withRecordType(annotations map[string]string, endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	value, exists := annotations[recordTypeAnnotationKey]
+	if !exists {
+		return endpoints
+	}
+	recordType := strings.ToUpper(strings.TrimSpace(value))
+	switch recordType {
+	case endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME:
+	default:
+		log.Warnf("Ignoring %s annotation %q, expected A, AAAA or CNAME", recordTypeAnnotationKey, value)
+		return endpoints
+	}
+
+	type name struct{ dnsName, setIdentifier string }
+	groups := map[name][]int{}
+	for i, ep := range endpoints {
+		switch ep.RecordType {
+		case endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME:
+			key := name{ep.DNSName, ep.SetIdentifier}
+			groups[key] = append(groups[key], i)
+		}
+	}
+
+	// replaced maps the index of an endpoint to the endpoint it is replaced with, nil to drop it.
+	replaced := map[int]*endpoint.Endpoint{}
+	for key, group := range groups {
+		var targets endpoint.Targets
+		seen := map[string]bool{}
+		add := func(target string) {
+			if !seen[target] {
+				seen[target] = true
+				targets = append(targets, target)
+			}
+		}
+		var unresolved error
+		for _, i := range group {
+			ep := endpoints[i]
+			switch {
+			case ep.RecordType == recordType:
+				for _, target := range ep.Targets {
+					add(target)
+				}
+			case ep.RecordType == endpoint.RecordTypeCNAME:
+				resolved, err := resolveTargets(ep.Targets, recordType)
+				if err != nil {
+					unresolved = err
+				}
+				for _, target := range resolved {
+					add(target)
+				}
+			}
+		}
+		if unresolved != nil {
+			log.Errorf("Skipping %s this time: %v", key.dnsName, unresolved)
+			for _, i := range group {
+				replaced[i] = nil
+			}
+			continue
+		}
+		if len(targets) == 0 {
+			log.Warnf("Publishing %s as it is: none of its targets can be published as %s record", key.dnsName, recordType)
+			continue
+		}
+
+		forced := endpoints[group[0]].DeepCopy()
+		forced.RecordType = recordType
+		forced.Targets = targets
+		for _, i := range group {
+			replaced[i] = nil
+		}
+		replaced[group[0]] = forced
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for i, ep := range endpoints {
+		if r, ok := replaced[i]; ok {
+			if r == nil {
+				continue
+			}
+			ep = r
+		}
+		result = append(result, ep)
+	}
+	return result
+}
+
+// resolveTargets resolves the hostname targets to the addresses of the A or AAAA record type. It fails when one of
+// them can't be resolved rather than returning a part of the addresses.
+func resolveTargets(hostnames endpoint.Targets, recordType string) (endpoint.Targets, error) {
+	var targets endpoint.Targets
+	for _, hostname := range hostnames {
+		ips, err := targetResolver.lookupIP(hostname)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve %q: %w", hostname, err)
+		}
+		for _, ip := range ips {
+			if suitableType(ip.String()) == recordType {
+				targets = append(targets, ip.String())
+			}
+		}
+	}
+	return targets, nil
+}
+
+// getActiveTargetAnnotation returns which of the blue or green target annotations is selected by the
+// "active-target" annotation, and its value.
+func getActiveTargetAnnotation(annotations map[string]string) (string, string) {
+	switch active := annotations[activeTargetAnnotationKey]; active {
+	case "":
+		return "", ""
+	case "blue":
+		return active, annotations[blueTargetAnnotationKey]
+	case "green":
+		return active, annotations[greenTargetAnnotationKey]
+	default:
+		log.Warnf("Ignoring %s annotation %q, expected blue or green", activeTargetAnnotationKey, active)
+		return "", ""
+	}
+}
+
+// setActiveTargetLabel sets the active target label of the endpoints when their targets are swapped between
+// the blue and green target annotations of the resource, so that the plan records their previous targets.
+func setActiveTargetLabel(annotations map[string]string, endpoints []*endpoint.Endpoint) {
+	if annotations[targetAnnotationKey] != "" {
+		return
+	}
+	active, targets := getActiveTargetAnnotation(annotations)
+	if targets == "" {
+		return
+	}
+	for _, ep := range endpoints {
+		if ep.Labels == nil {
+			ep.Labels = endpoint.NewLabels()
+		}
+		ep.Labels[endpoint.ActiveTargetLabelKey] = active
+	}
+}
+
+// setCommitLabel copies the commit annotation of the resource to the endpoints labels, if present.
+func setCommitLabel(annotations map[string]string, endpoints []*endpoint.Endpoint) {
+	commit, ok := annotations[commitAnnotationKey]
+	if !ok || commit == "" {
+		return
+	}
+	for _, ep := range endpoints {
+		if ep.Labels == nil {
+			ep.Labels = endpoint.NewLabels()
+		}
+		ep.Labels[endpoint.CommitLabelKey] = commit
+	}
+}
+
 func getLabelSelector(annotationFilter string) (labels.Selector, error) {
 	labelSelector, err := metav1.ParseToLabelSelector(annotationFilter)
 	if err != nil {
@@ -397,3 +681,23 @@ func isIPv6String(ip string) bool {
 	netIP := net.ParseIP(ip)
 	return netIP != nil && netIP.To4() == nil
 }
+
+// validateStaticEndpoint normalizes the DNS name of an endpoint specified by users, e.g. with a DNSEndpoint,
+// and tells why it is invalid, if it is.
+func validateStaticEndpoint(ep *endpoint.Endpoint) error {
+	dnsName, err := endpoint.NormalizeDNSName(ep.DNSName)
+	if err != nil {
+		return fmt.Errorf("invalid DNS name: %w", err)
+	}
+	ep.DNSName = dnsName
+
+	if (ep.RecordType == endpoint.RecordTypeCNAME || ep.RecordType == endpoint.RecordTypeA || ep.RecordType == endpoint.RecordTypeAAAA) && len(ep.Targets) < 1 {
+		return errors.New("empty list of targets")
+	}
+	for _, target := range ep.Targets {
+		if strings.HasSuffix(target, ".") {
+			return errors.New("illegal target: targets must not end with a dot")
+		}
+	}
+	return nil
+}
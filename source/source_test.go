@@ -17,10 +17,14 @@ limitations under the License.
 package source
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -96,3 +100,192 @@ func TestSuitableType(t *testing.T) {
 		}
 	}
 }
+
+func TestSetCommitLabel(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		{DNSName: "bar.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.2.3.5"}},
+	}
+
+	setCommitLabel(map[string]string{}, endpoints)
+	assert.NotContains(t, endpoints[0].Labels, endpoint.CommitLabelKey)
+
+	setCommitLabel(map[string]string{commitAnnotationKey: "1f3a2b4"}, endpoints)
+	for _, ep := range endpoints {
+		assert.Equal(t, "1f3a2b4", ep.Labels[endpoint.CommitLabelKey])
+	}
+}
+
+func TestGetProviderSpecificAnnotationsCanaryWeight(t *testing.T) {
+	providerSpecific, setIdentifier := getProviderSpecificAnnotations(map[string]string{
+		SetIdentifierKey:          "canary",
+		canaryWeightAnnotationKey: "10",
+	})
+	assert.Equal(t, "canary", setIdentifier)
+	assert.Equal(t, endpoint.ProviderSpecific{{Name: endpoint.ProviderSpecificCanaryWeight, Value: "10"}}, providerSpecific)
+}
+
+func TestGetProviderSpecificAnnotationsSourceWeight(t *testing.T) {
+	providerSpecific, _ := getProviderSpecificAnnotations(map[string]string{sourceWeightAnnotationKey: "10"})
+	assert.Equal(t, endpoint.ProviderSpecific{{Name: endpoint.ProviderSpecificSourceWeight, Value: "10"}}, providerSpecific)
+}
+
+func TestGetProviderSpecificAnnotationsZoneID(t *testing.T) {
+	providerSpecific, _ := getProviderSpecificAnnotations(map[string]string{zoneIDAnnotationKey: "Z0123456789"})
+	assert.Equal(t, endpoint.ProviderSpecific{{Name: endpoint.ProviderSpecificZoneID, Value: "Z0123456789"}}, providerSpecific)
+
+	providerSpecific, _ = getProviderSpecificAnnotations(map[string]string{zoneIDAnnotationKey: ""})
+	assert.Empty(t, providerSpecific)
+}
+
+func TestGetTargetsFromBlueGreenAnnotations(t *testing.T) {
+	annotations := map[string]string{
+		blueTargetAnnotationKey:   "blue.example.org.",
+		greenTargetAnnotationKey:  "192.0.2.1, 192.0.2.2",
+		activeTargetAnnotationKey: "green",
+	}
+	assert.Equal(t, endpoint.Targets{"192.0.2.1", "192.0.2.2"}, getTargetsFromTargetAnnotation(annotations))
+
+	endpoints := []*endpoint.Endpoint{endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "192.0.2.1", "192.0.2.2")}
+	setActiveTargetLabel(annotations, endpoints)
+	assert.Equal(t, "green", endpoints[0].Labels[endpoint.ActiveTargetLabelKey])
+
+	annotations[activeTargetAnnotationKey] = "blue"
+	assert.Equal(t, endpoint.Targets{"blue.example.org"}, getTargetsFromTargetAnnotation(annotations))
+
+	// the target annotation takes precedence
+	annotations[targetAnnotationKey] = "target.example.org"
+	assert.Equal(t, endpoint.Targets{"target.example.org"}, getTargetsFromTargetAnnotation(annotations))
+	endpoints = []*endpoint.Endpoint{endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeCNAME, "target.example.org")}
+	setActiveTargetLabel(annotations, endpoints)
+	assert.NotContains(t, endpoints[0].Labels, endpoint.ActiveTargetLabelKey)
+
+	delete(annotations, targetAnnotationKey)
+	annotations[activeTargetAnnotationKey] = "red"
+	assert.Empty(t, getTargetsFromTargetAnnotation(annotations))
+}
+
+func TestWithRecordType(t *testing.T) {
+	resolver := targetResolver
+	targetResolver = newHostnameResolver(func(_ context.Context, _, host string) ([]net.IP, error) {
+		if host == "lb.cloud.example.com" {
+			return []net.IP{net.ParseIP("1.2.3.5"), net.ParseIP("2001:db8::2")}, nil
+		}
+		return nil, fmt.Errorf("no such host %s", host)
+	}, time.Second, time.Minute)
+	defer func() { targetResolver = resolver }()
+
+	newEndpoints := func() []*endpoint.Endpoint {
+		endpoints := []*endpoint.Endpoint{
+			endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeCNAME, "lb.cloud.example.com"),
+			endpoint.NewEndpoint("ips.example.org", endpoint.RecordTypeA, "1.2.3.6"),
+			endpoint.NewEndpoint("_http._tcp.app.example.org", endpoint.RecordTypeSRV, "0 50 80 app.example.org"),
+		}
+		endpoints[0].Labels[endpoint.ResourceLabelKey] = "service/default/app"
+		return endpoints
+	}
+	labeled := func(ep *endpoint.Endpoint) *endpoint.Endpoint {
+		ep.Labels[endpoint.ResourceLabelKey] = "service/default/app"
+		return ep
+	}
+
+	for _, tc := range []struct {
+		title      string
+		recordType string
+		expected   []*endpoint.Endpoint
+	}{
+		{
+			title:      "no annotation",
+			recordType: "",
+			expected:   newEndpoints(),
+		},
+		{
+			title:      "invalid record type",
+			recordType: "MX",
+			expected:   newEndpoints(),
+		},
+		{
+			title:      "CNAME",
+			recordType: "CNAME",
+			expected: []*endpoint.Endpoint{
+				labeled(endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeCNAME, "lb.cloud.example.com")),
+				endpoint.NewEndpoint("ips.example.org", endpoint.RecordTypeA, "1.2.3.6"),
+				endpoint.NewEndpoint("_http._tcp.app.example.org", endpoint.RecordTypeSRV, "0 50 80 app.example.org"),
+			},
+		},
+		{
+			title:      "A resolving the CNAME targets",
+			recordType: "a",
+			expected: []*endpoint.Endpoint{
+				labeled(endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.2.3.4", "1.2.3.5")),
+				endpoint.NewEndpoint("ips.example.org", endpoint.RecordTypeA, "1.2.3.6"),
+				endpoint.NewEndpoint("_http._tcp.app.example.org", endpoint.RecordTypeSRV, "0 50 80 app.example.org"),
+			},
+		},
+		{
+			title:      "AAAA",
+			recordType: "AAAA",
+			expected: []*endpoint.Endpoint{
+				labeled(endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeAAAA, "2001:db8::2")),
+				endpoint.NewEndpoint("ips.example.org", endpoint.RecordTypeA, "1.2.3.6"),
+				endpoint.NewEndpoint("_http._tcp.app.example.org", endpoint.RecordTypeSRV, "0 50 80 app.example.org"),
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tc.recordType != "" {
+				annotations[recordTypeAnnotationKey] = tc.recordType
+			}
+			endpoints := newEndpoints()
+			assert.Equal(t, tc.expected, withRecordType(annotations, endpoints))
+			// the endpoints, which may be cached by the sources, are not modified
+			assert.Equal(t, newEndpoints(), endpoints)
+		})
+	}
+}
+
+func TestWithRecordTypeUnresolved(t *testing.T) {
+	resolver := targetResolver
+	targetResolver = newHostnameResolver(func(_ context.Context, _, host string) ([]net.IP, error) {
+		return nil, fmt.Errorf("no such host %s", host)
+	}, time.Second, time.Minute)
+	defer func() { targetResolver = resolver }()
+
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeCNAME, "lb.cloud.example.com"),
+		endpoint.NewEndpoint("ips.example.org", endpoint.RecordTypeA, "1.2.3.6"),
+	}
+
+	// the apex is skipped rather than published with a part of its addresses, or as a CNAME
+	assert.Equal(t, endpoints[2:], withRecordType(map[string]string{recordTypeAnnotationKey: "A"}, endpoints))
+}
+
+func TestWithWildcards(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+		endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeAAAA, "2001:db8::1"),
+		endpoint.NewEndpoint("lb.example.org", endpoint.RecordTypeCNAME, "lb.cloud.example.com"),
+		endpoint.NewEndpoint("_http._tcp.app.example.org", endpoint.RecordTypeSRV, "0 50 80 app.example.org"),
+		endpoint.NewEndpoint("*.other.example.org", endpoint.RecordTypeA, "1.2.3.5"),
+		endpoint.NewEndpoint("other.example.org", endpoint.RecordTypeA, "1.2.3.5"),
+	}
+	endpoints[0].Labels[endpoint.ResourceLabelKey] = "ingress/default/app"
+
+	assert.Equal(t, endpoints, withWildcards(map[string]string{}, endpoints))
+	assert.Equal(t, endpoints, withWildcards(map[string]string{publishWildcardAnnotationKey: "false"}, endpoints))
+
+	result := withWildcards(map[string]string{publishWildcardAnnotationKey: "true"}, endpoints)
+	require.Len(t, result, 9)
+	assert.Equal(t, endpoints, result[:6])
+	assert.Equal(t, "*.app.example.org", result[6].DNSName)
+	assert.Equal(t, endpoint.RecordTypeA, result[6].RecordType)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, result[6].Targets)
+	assert.Equal(t, "ingress/default/app", result[6].Labels[endpoint.ResourceLabelKey])
+	assert.Equal(t, "*.app.example.org", result[7].DNSName)
+	assert.Equal(t, endpoint.RecordTypeAAAA, result[7].RecordType)
+	assert.Equal(t, "*.lb.example.org", result[8].DNSName)
+	assert.Equal(t, endpoint.Targets{"lb.cloud.example.com"}, result[8].Targets)
+}
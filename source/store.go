@@ -18,6 +18,7 @@ package source
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"os"
 	"strings"
@@ -36,6 +37,8 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	gateway "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+
+	"sigs.k8s.io/external-dns/pkg/tlsutils"
 )
 
 // ErrSourceNotFound is returned when a requested source doesn't exist.
@@ -54,13 +57,30 @@ type Config struct {
 	IgnoreIngressRulesSpec         bool
 	GatewayNamespace               string
 	GatewayLabelFilter             string
+	GatewayEnvoyServices           bool
 	Compatibility                  string
 	PublishInternal                bool
 	PublishHostIP                  bool
 	AlwaysPublishNotReadyAddresses bool
+	NodeSSHFP                      bool
+	NodeSSHFPSecret                string
+	NodeLabelFilter                labels.Selector
+	NodeExcludeNotReady            bool
+	NodeExcludeUnschedulable       bool
+	NodeIPv4AddressType            string
+	NodeIPv6AddressType            string
+	PodLabelSelector               labels.Selector
+	PodPublishPodIP                bool
 	ConnectorServer                string
+	ConnectorTLS                   bool
+	ConnectorTLSCA                 string
+	ConnectorTLSCert               string
+	ConnectorTLSKey                string
+	ConnectorTokenFile             string
 	CRDSourceAPIVersion            string
 	CRDSourceKind                  string
+	CRDSourceNamespaces            []string
+	CRDSourceLabelFilter           labels.Selector
 	KubeConfig                     string
 	APIServerURL                   string
 	ServiceTypeFilter              []string
@@ -69,11 +89,32 @@ type Config struct {
 	CFPassword                     string
 	GlooNamespaces                 []string
 	SkipperRouteGroupVersion       string
+	IstioPublishMeshOnlyHosts      bool
+	IstioGatewayAnyNamespace       bool
+	CiliumLoadBalancerMode         string
+	CiliumNamespace                string
 	RequestTimeout                 time.Duration
 	DefaultTargets                 []string
 	OCPRouterName                  string
 	UpdateEvents                   bool
 	ResolveLoadBalancerHostname    bool
+	ServiceTopologyHostnames       bool
+	TraefikDisableLegacy           bool
+	TraefikDisableTCP              bool
+	TraefikDisableUDP              bool
+	TraefikService                 string
+	KongProxyService               string
+	AmbassadorService              string
+	FileSourcePath                 string
+	FileSourceConfigMap            string
+	HTTPSourceURL                  string
+	HTTPSourceBearerTokenFile      string
+	KnativeRoutes                  bool
+	KnativeIngressService          string
+	ServiceImportNaming            string
+	ClusterSetDomain               string
+	// InformerFactories, when set, are shared by the sources, so that identical informers are created once.
+	InformerFactories *InformerFactories
 }
 
 // ClientGenerator provides clients
@@ -204,31 +245,34 @@ func ByNames(ctx context.Context, p ClientGenerator, names []string, cfg *Config
 
 // BuildWithConfig allows to generate a Source implementation from the shared config
 func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg *Config) (Source, error) {
+	if cfg.InformerFactories != nil {
+		ctx = WithInformerFactories(ctx, cfg.InformerFactories)
+	}
 	switch source {
 	case "node":
 		client, err := p.KubeClient()
 		if err != nil {
 			return nil, err
 		}
-		return NewNodeSource(ctx, client, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.LabelFilter)
+		return NewNodeSource(ctx, client, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.LabelFilter, cfg.NodeSSHFP, cfg.NodeSSHFPSecret, cfg.NodeLabelFilter, cfg.NodeExcludeNotReady, cfg.NodeExcludeUnschedulable, cfg.NodeIPv4AddressType, cfg.NodeIPv6AddressType)
 	case "service":
 		client, err := p.KubeClient()
 		if err != nil {
 			return nil, err
 		}
-		return NewServiceSource(ctx, client, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.Compatibility, cfg.PublishInternal, cfg.PublishHostIP, cfg.AlwaysPublishNotReadyAddresses, cfg.ServiceTypeFilter, cfg.IgnoreHostnameAnnotation, cfg.LabelFilter, cfg.ResolveLoadBalancerHostname)
+		return NewServiceSource(ctx, client, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.Compatibility, cfg.PublishInternal, cfg.PublishHostIP, cfg.AlwaysPublishNotReadyAddresses, cfg.ServiceTypeFilter, cfg.IgnoreHostnameAnnotation, cfg.LabelFilter, cfg.ResolveLoadBalancerHostname, cfg.ServiceTopologyHostnames)
 	case "ingress":
 		client, err := p.KubeClient()
 		if err != nil {
 			return nil, err
 		}
-		return NewIngressSource(ctx, client, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.IgnoreHostnameAnnotation, cfg.IgnoreIngressTLSSpec, cfg.IgnoreIngressRulesSpec, cfg.LabelFilter, cfg.IngressClassNames)
+		return NewIngressSource(ctx, client, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.IgnoreHostnameAnnotation, cfg.IgnoreIngressTLSSpec, cfg.IgnoreIngressRulesSpec, cfg.LabelFilter, cfg.IngressClassNames, cfg.CiliumLoadBalancerMode, cfg.CiliumNamespace)
 	case "pod":
 		client, err := p.KubeClient()
 		if err != nil {
 			return nil, err
 		}
-		return NewPodSource(ctx, client, cfg.Namespace, cfg.Compatibility)
+		return NewPodSource(ctx, client, cfg.Namespace, cfg.Compatibility, cfg.PodLabelSelector, cfg.PodPublishPodIP)
 	case "gateway-httproute":
 		return NewGatewayHTTPRouteSource(p, cfg)
 	case "gateway-grpcroute":
@@ -248,7 +292,7 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		if err != nil {
 			return nil, err
 		}
-		return NewIstioGatewaySource(ctx, kubernetesClient, istioClient, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.IgnoreHostnameAnnotation)
+		return NewIstioGatewaySource(ctx, kubernetesClient, istioClient, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.IgnoreHostnameAnnotation, cfg.IstioGatewayAnyNamespace)
 	case "istio-virtualservice":
 		kubernetesClient, err := p.KubeClient()
 		if err != nil {
@@ -258,7 +302,7 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		if err != nil {
 			return nil, err
 		}
-		return NewIstioVirtualServiceSource(ctx, kubernetesClient, istioClient, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.IgnoreHostnameAnnotation)
+		return NewIstioVirtualServiceSource(ctx, kubernetesClient, istioClient, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.IgnoreHostnameAnnotation, cfg.IstioPublishMeshOnlyHosts, cfg.IstioGatewayAnyNamespace)
 	case "cloudfoundry":
 		cfClient, err := p.CloudFoundryClient(cfg.CFAPIEndpoint, cfg.CFUsername, cfg.CFPassword)
 		if err != nil {
@@ -274,7 +318,7 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		if err != nil {
 			return nil, err
 		}
-		return NewAmbassadorHostSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace)
+		return NewAmbassadorHostSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AmbassadorService)
 	case "contour-httpproxy":
 		dynamicClient, err := p.DynamicKubernetesClient()
 		if err != nil {
@@ -290,7 +334,7 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		if err != nil {
 			return nil, err
 		}
-		return NewGlooSource(dynamicClient, kubernetesClient, cfg.GlooNamespaces)
+		return NewGlooSource(dynamicClient, kubernetesClient, cfg.GlooNamespaces, cfg.LabelFilter)
 	case "traefik-proxy":
 		kubernetesClient, err := p.KubeClient()
 		if err != nil {
@@ -300,7 +344,7 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		if err != nil {
 			return nil, err
 		}
-		return NewTraefikSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.IgnoreHostnameAnnotation)
+		return NewTraefikSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.IgnoreHostnameAnnotation, cfg.TraefikDisableLegacy, cfg.TraefikDisableTCP, cfg.TraefikDisableUDP, cfg.TraefikService)
 	case "openshift-route":
 		ocpClient, err := p.OpenShiftClient()
 		if err != nil {
@@ -310,7 +354,26 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 	case "fake":
 		return NewFakeSource(cfg.FQDNTemplate)
 	case "connector":
-		return NewConnectorSource(cfg.ConnectorServer)
+		var tlsConfig *tls.Config
+		if cfg.ConnectorTLS {
+			var err error
+			tlsConfig, err = tlsutils.NewTLSConfig(cfg.ConnectorTLSCert, cfg.ConnectorTLSKey, cfg.ConnectorTLSCA, "", false, tls.VersionTLS12)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return NewConnectorSource(cfg.ConnectorServer, tlsConfig, cfg.ConnectorTokenFile)
+	case "file":
+		if cfg.FileSourceConfigMap == "" {
+			return NewFileSource(cfg.FileSourcePath), nil
+		}
+		client, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewConfigMapFileSource(client, cfg.FileSourceConfigMap)
+	case "http":
+		return NewHTTPSource(cfg.HTTPSourceURL, cfg.HTTPSourceBearerTokenFile, cfg.RequestTimeout)
 	case "crd":
 		client, err := p.KubeClient()
 		if err != nil {
@@ -320,7 +383,15 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		if err != nil {
 			return nil, err
 		}
-		return NewCRDSource(crdClient, cfg.Namespace, cfg.CRDSourceKind, cfg.AnnotationFilter, cfg.LabelFilter, scheme, cfg.UpdateEvents)
+		gatewayClient, err := p.GatewayClient()
+		if err != nil {
+			return nil, err
+		}
+		namespaces := cfg.CRDSourceNamespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{cfg.Namespace}
+		}
+		return NewCRDSource(crdClient, namespaces, cfg.CRDSourceKind, cfg.AnnotationFilter, crdLabelSelector(cfg.LabelFilter, cfg.CRDSourceLabelFilter), scheme, cfg.UpdateEvents, client, gatewayClient, cfg.InformerFactories)
 	case "skipper-routegroup":
 		apiServerURL := cfg.APIServerURL
 		tokenPath := ""
@@ -341,7 +412,37 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		if err != nil {
 			return nil, err
 		}
-		return NewKongTCPIngressSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.IgnoreHostnameAnnotation)
+		return NewKongTCPIngressSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.IgnoreHostnameAnnotation, cfg.KongProxyService)
+	case "kong-udpingress":
+		kubernetesClient, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := p.DynamicKubernetesClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewKongUDPIngressSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.IgnoreHostnameAnnotation, cfg.KongProxyService)
+	case "knative-domainmapping":
+		kubernetesClient, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := p.DynamicKubernetesClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewKnativeSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.IgnoreHostnameAnnotation, cfg.KnativeRoutes, cfg.KnativeIngressService)
+	case "argo-rollout":
+		kubernetesClient, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := p.DynamicKubernetesClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewArgoRolloutSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter)
 	case "f5-virtualserver":
 		kubernetesClient, err := p.KubeClient()
 		if err != nil {
@@ -352,6 +453,26 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 			return nil, err
 		}
 		return NewF5VirtualServerSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter)
+	case "f5-transportserver":
+		kubernetesClient, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := p.DynamicKubernetesClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewF5TransportServerSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter)
+	case "service-import":
+		kubernetesClient, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := p.DynamicKubernetesClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewServiceImportSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.IgnoreHostnameAnnotation, cfg.ServiceImportNaming, cfg.ClusterSetDomain)
 	}
 
 	return nil, ErrSourceNotFound
@@ -362,6 +483,12 @@ func instrumentedRESTConfig(kubeConfig, apiServerURL string, requestTimeout time
 	if err != nil {
 		return nil, err
 	}
+	instrumentRESTConfig(config, requestTimeout)
+	return config, nil
+}
+
+// instrumentRESTConfig sets up the request metrics and the request timeout of the clients of the config.
+func instrumentRESTConfig(config *rest.Config, requestTimeout time.Duration) {
 	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
 		return instrumented_http.NewTransport(rt, &instrumented_http.Callbacks{
 			PathProcessor: func(path string) string {
@@ -371,7 +498,6 @@ func instrumentedRESTConfig(kubeConfig, apiServerURL string, requestTimeout time
 		})
 	}
 	config.Timeout = requestTimeout
-	return config, nil
 }
 
 // GetRestConfig returns the rest clients config to get automatically
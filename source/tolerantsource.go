@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var (
+	sourceFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "source",
+			Name:      "failures_total",
+			Help:      "Number of failures of each source to collect its endpoints.",
+		},
+		[]string{"source"},
+	)
+	sourceStale = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "source",
+			Name:      "stale",
+			Help:      "Whether the endpoints of each source are the ones it collected last before failing (1) or not (0).",
+		},
+		[]string{"source"},
+	)
+	sourceLastSuccessTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "source",
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Timestamp of the last successful collection of the endpoints of each source.",
+		},
+		[]string{"source"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(sourceFailuresTotal)
+	prometheus.MustRegister(sourceStale)
+	prometheus.MustRegister(sourceLastSuccessTimestamp)
+}
+
+// tolerantSource is a Source returning the endpoints its wrapped source collected last when the
+// wrapped source fails, e.g. because the API server timed out, so that the failure of one source
+// doesn't abort the synchronization of the others. The endpoints are only returned while they are
+// not older than maxStaleness, the failure is returned afterwards. A source which never succeeded,
+// e.g. because its CRD is not installed, returns no endpoints and is reported as unavailable
+// through the context, so that no records are deleted meanwhile.
+type tolerantSource struct {
+	source       Source
+	name         string
+	maxStaleness time.Duration
+
+	mutex       sync.Mutex
+	endpoints   []*endpoint.Endpoint
+	successTime time.Time
+}
+
+// NewTolerantSource creates a new tolerantSource wrapping the provided Source, named name in the
+// logs and metrics.
+func NewTolerantSource(source Source, name string, maxStaleness time.Duration) Source {
+	return &tolerantSource{source: source, name: name, maxStaleness: maxStaleness}
+}
+
+// Endpoints returns the endpoints of the wrapped source, or the ones it collected last while they
+// are not too old when it fails.
+func (ts *tolerantSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	endpoints, err := ts.source.Endpoints(ctx)
+	if err == nil {
+		// the endpoints are only copied when falling back to them: the changes made to them further
+		// down the line, e.g. filtering their targets, are made again to the copies without effect
+		ts.endpoints = endpoints
+		ts.successTime = time.Now()
+		sourceStale.WithLabelValues(ts.name).Set(0)
+		sourceLastSuccessTimestamp.WithLabelValues(ts.name).Set(float64(ts.successTime.Unix()))
+		return endpoints, nil
+	}
+
+	sourceFailuresTotal.WithLabelValues(ts.name).Inc()
+	if ts.successTime.IsZero() {
+		log.Warnf("Source %s failed and has no endpoints to fall back on, keeping all records: %v", ts.name, err)
+		sourceStale.WithLabelValues(ts.name).Set(1)
+		if unavailable, ok := ctx.Value(unavailableSourcesKey{}).(*UnavailableSources); ok {
+			unavailable.add(ts.name)
+		}
+		return []*endpoint.Endpoint{}, nil
+	}
+	if time.Since(ts.successTime) > ts.maxStaleness {
+		return nil, fmt.Errorf("source %s failed: %w", ts.name, err)
+	}
+
+	log.Warnf("Source %s failed, using its endpoints collected at %s: %v", ts.name, ts.successTime.Format(time.RFC3339), err)
+	sourceStale.WithLabelValues(ts.name).Set(1)
+	return copyEndpoints(ts.endpoints), nil
+}
+
+func (ts *tolerantSource) AddEventHandler(ctx context.Context, handler func()) {
+	ts.source.AddEventHandler(ctx, handler)
+}
+
+type unavailableSourcesKey struct{}
+
+// UnavailableSources collects the names of the sources which failed without endpoints to fall back
+// on while collecting the endpoints. The records of such sources are unknown, so none may be deleted.
+type UnavailableSources struct {
+	mutex sync.Mutex
+	names []string
+}
+
+// WithUnavailableSources returns a context collecting the sources which are unavailable during the
+// calls to Endpoints made with it.
+func WithUnavailableSources(ctx context.Context) (context.Context, *UnavailableSources) {
+	unavailable := &UnavailableSources{}
+	return context.WithValue(ctx, unavailableSourcesKey{}, unavailable), unavailable
+}
+
+// Names returns the names of the unavailable sources.
+func (u *UnavailableSources) Names() []string {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return append([]string(nil), u.names...)
+}
+
+func (u *UnavailableSources) add(name string) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.names = append(u.names, name)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// failingSource returns its endpoints or its error, when set.
+type failingSource struct {
+	Source
+	endpoints []*endpoint.Endpoint
+	err       error
+}
+
+func (fs *failingSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	if fs.err != nil {
+		return nil, fs.err
+	}
+	return fs.endpoints, nil
+}
+
+func TestTolerantSource(t *testing.T) {
+	ctx := context.Background()
+	wrapped := &failingSource{endpoints: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	}}
+	src := NewTolerantSource(wrapped, "tolerant-test", 100*time.Millisecond)
+
+	endpoints, err := src.Endpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, 0.0, testutil.ToFloat64(sourceStale.WithLabelValues("tolerant-test")))
+
+	wrapped.err = errors.New("the server could not find the requested resource")
+	endpoints, err = src.Endpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, endpoints[0].Targets)
+	assert.Equal(t, 1.0, testutil.ToFloat64(sourceFailuresTotal.WithLabelValues("tolerant-test")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(sourceStale.WithLabelValues("tolerant-test")))
+	endpoints[0].Targets = endpoint.Targets{"5.6.7.8"}
+
+	endpoints, err = src.Endpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, endpoints[0].Targets, "last endpoints are not modified by callers")
+
+	time.Sleep(150 * time.Millisecond)
+	_, err = src.Endpoints(ctx)
+	assert.ErrorContains(t, err, "source tolerant-test failed: the server could not find the requested resource")
+	assert.Equal(t, 3.0, testutil.ToFloat64(sourceFailuresTotal.WithLabelValues("tolerant-test")))
+
+	wrapped.err = nil
+	endpoints, err = src.Endpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, 0.0, testutil.ToFloat64(sourceStale.WithLabelValues("tolerant-test")))
+}
+
+func TestTolerantSourceWithoutSuccess(t *testing.T) {
+	ctx, unavailable := WithUnavailableSources(context.Background())
+	wrapped := &failingSource{err: errors.New("no matches for kind DNSEndpoint")}
+	src := NewTolerantSource(wrapped, "tolerant-initial", time.Hour)
+
+	endpoints, err := src.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, endpoints)
+	assert.Equal(t, []string{"tolerant-initial"}, unavailable.Names())
+
+	endpoints, err = src.Endpoints(context.Background())
+	require.NoError(t, err, "the source is unavailable without a context collecting the unavailable sources too")
+	assert.Empty(t, endpoints)
+
+	ctx, unavailable = WithUnavailableSources(context.Background())
+	wrapped.err = nil
+	_, err = src.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, unavailable.Names())
+}
+
+func TestTolerantSourceInMultiSource(t *testing.T) {
+	ctx := context.Background()
+	failing := &failingSource{endpoints: []*endpoint.Endpoint{
+		endpoint.NewEndpoint("crd.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	}}
+	src := NewMultiSource([]Source{
+		NewTolerantSource(failing, "tolerant-crd", time.Hour),
+		NewEchoSource([]*endpoint.Endpoint{endpoint.NewEndpoint("svc.example.org", endpoint.RecordTypeA, "5.6.7.8")}),
+	}, nil, ConflictPolicyNone)
+
+	_, err := src.Endpoints(ctx)
+	require.NoError(t, err)
+
+	failing.err = errors.New("no matches for kind DNSEndpoint")
+	endpoints, err := src.Endpoints(ctx)
+	require.NoError(t, err)
+	assert.Len(t, endpoints, 2, "the endpoints of the failing source are kept")
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxHostRegexpHostnames bounds the number of hostnames a HostRegexp matcher is expanded to.
+const maxHostRegexpHostnames = 64
+
+var (
+	traefikHostRegexpExtractor = regexp.MustCompile(`(?:HostSNIRegexp|HostRegexp)\s*\(\s*(\x60.*?\x60)\s*\)`)
+	// traefikHostTemplateVariable matches the variables of the host templates of Traefik v2, e.g. {subdomain:[a-z]+}
+	traefikHostTemplateVariable = regexp.MustCompile(`\{[A-Za-z_][A-Za-z0-9_]*(?::([^{}]*))?\}`)
+	hostRegexpHostname          = regexp.MustCompile(`^[a-z0-9_-]+(\.[a-z0-9_-]+)*$`)
+)
+
+// hostRegexpHostnames returns the hostnames matched by the HostRegexp and HostSNIRegexp matchers of the rule
+// which match a finite number of hostnames, and whether the rule has matchers which don't, e.g. wildcards.
+func hostRegexpHostnames(match string) (hostnames []string, unresolved bool) {
+	for _, entry := range traefikHostRegexpExtractor.FindAllStringSubmatch(match, -1) {
+		for _, value := range splitHostRegexpValues(entry[1]) {
+			expanded, ok := expandHostRegexp(value)
+			if !ok {
+				log.Debugf("Skipping the HostRegexp %q, it doesn't match a finite number of hostnames", value)
+				unresolved = true
+				continue
+			}
+			hostnames = append(hostnames, expanded...)
+		}
+	}
+	return hostnames, unresolved
+}
+
+// splitHostRegexpValues returns the values of the arguments of a matcher, e.g. "`a`, `b`".
+func splitHostRegexpValues(arguments string) []string {
+	var values []string
+	for i, part := range strings.Split(arguments, "`") {
+		// the values are the odd parts, between the backquotes
+		if i%2 == 1 {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// expandHostRegexp returns the hostnames matched by the regular expression of a HostRegexp matcher, or false if it
+// matches hostnames which can't be enumerated. Both the regular expressions of Traefik v3, e.g.
+// ^(app|api)\.example\.com$, and the host templates of Traefik v2, e.g. {subdomain:(app|api)}.example.com, are
+// supported. A dot matching any single character is read as a dot, as in app.example.com.
+func expandHostRegexp(value string) ([]string, bool) {
+	expr := value
+	if traefikHostTemplateVariable.MatchString(value) {
+		expr = hostTemplateToRegexp(value)
+	}
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, false
+	}
+	hostnames, ok := expandRegexp(re.Simplify())
+	if !ok || len(hostnames) == 0 {
+		return nil, false
+	}
+	for i, hostname := range hostnames {
+		hostnames[i] = strings.ToLower(hostname)
+		if !hostRegexpHostname.MatchString(hostnames[i]) {
+			return nil, false
+		}
+	}
+	return hostnames, true
+}
+
+// hostTemplateToRegexp converts a host template of Traefik v2, whose literal parts are not regular expressions,
+// to a regular expression. Variables without pattern match any label.
+func hostTemplateToRegexp(template string) string {
+	var expr strings.Builder
+	last := 0
+	for _, loc := range traefikHostTemplateVariable.FindAllStringSubmatchIndex(template, -1) {
+		expr.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		if loc[2] >= 0 {
+			expr.WriteString("(?:" + template[loc[2]:loc[3]] + ")")
+		} else {
+			expr.WriteString(`[^.]+`)
+		}
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	return expr.String()
+}
+
+// expandRegexp returns the strings matched by the regular expression, or false if there are too many of them.
+func expandRegexp(re *syntax.Regexp) ([]string, bool) {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return []string{""}, true
+	case syntax.OpLiteral:
+		return []string{string(re.Rune)}, true
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		return []string{"."}, true
+	case syntax.OpCharClass:
+		var expanded []string
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				if len(expanded) == maxHostRegexpHostnames {
+					return nil, false
+				}
+				expanded = append(expanded, string(r))
+			}
+		}
+		return expanded, true
+	case syntax.OpCapture:
+		return expandRegexp(re.Sub[0])
+	case syntax.OpQuest:
+		expanded, ok := expandRegexp(re.Sub[0])
+		if !ok || len(expanded) == maxHostRegexpHostnames {
+			return nil, false
+		}
+		return append(expanded, ""), true
+	case syntax.OpAlternate:
+		var expanded []string
+		for _, sub := range re.Sub {
+			alternatives, ok := expandRegexp(sub)
+			if !ok || len(expanded)+len(alternatives) > maxHostRegexpHostnames {
+				return nil, false
+			}
+			expanded = append(expanded, alternatives...)
+		}
+		return expanded, true
+	case syntax.OpConcat:
+		expanded := []string{""}
+		for _, sub := range re.Sub {
+			parts, ok := expandRegexp(sub)
+			if !ok || len(expanded)*len(parts) > maxHostRegexpHostnames {
+				return nil, false
+			}
+			product := make([]string, 0, len(expanded)*len(parts))
+			for _, prefix := range expanded {
+				for _, part := range parts {
+					product = append(product, prefix+part)
+				}
+			}
+			expanded = product
+		}
+		return expanded, true
+	default:
+		// repetitions and the like match more hostnames than can be enumerated
+		return nil, false
+	}
+}
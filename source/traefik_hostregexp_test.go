@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+)
+
+func TestExpandHostRegexp(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected []string
+	}{
+		{value: `^app\.example\.com$`, expected: []string{"app.example.com"}},
+		{value: `app.example.com`, expected: []string{"app.example.com"}},
+		{value: `(?i)^App\.Example\.com$`, expected: []string{"app.example.com"}},
+		{value: `^(app|api)\.example\.com$`, expected: []string{"api.example.com", "app.example.com"}},
+		{value: `^(www\.)?example\.com$`, expected: []string{"www.example.com", "example.com"}},
+		{value: `^app-[1-3]\.example\.com$`, expected: []string{"app-1.example.com", "app-2.example.com", "app-3.example.com"}},
+		{value: `{subdomain:(app|api)}.example.com`, expected: []string{"api.example.com", "app.example.com"}},
+		{value: `example.com`, expected: []string{"example.com"}},
+		{value: `^.+\.example\.com$`},
+		{value: `^[a-z]+\.example\.com$`},
+		{value: `{subdomain:[a-z]+}.example.com`},
+		{value: `{subdomain}.example.com`},
+		{value: `^[a-z][a-z]\.example\.com$`},
+		{value: `^(app|api)\.example\.com/path$`},
+		{value: `^(`},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			hostnames, ok := expandHostRegexp(tc.value)
+			if tc.expected == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.ElementsMatch(t, tc.expected, hostnames)
+		})
+	}
+}
+
+func TestHostRegexpHostnames(t *testing.T) {
+	hostnames, unresolved := hostRegexpHostnames("Host(`a.example.com`) || HostRegexp(`b.example.com`, `{sub:[a-z]+}.example.com`)")
+	assert.Equal(t, []string{"b.example.com"}, hostnames)
+	assert.True(t, unresolved)
+
+	hostnames, unresolved = hostRegexpHostnames("HostSNIRegexp(`^c\\.example\\.com$`)")
+	assert.Equal(t, []string{"c.example.com"}, hostnames)
+	assert.False(t, unresolved)
+
+	hostnames, unresolved = hostRegexpHostnames("Host(`a.example.com`)")
+	assert.Empty(t, hostnames)
+	assert.False(t, unresolved)
+}
+
+func TestTraefikProxyHostRegexp(t *testing.T) {
+	t.Parallel()
+
+	for _, ti := range []struct {
+		title       string
+		match       string
+		annotations map[string]string
+		expected    []string
+	}{
+		{
+			title:    "resolvable HostRegexp",
+			match:    "HostRegexp(`^(a|b)\\.example\\.com$`)",
+			expected: []string{"a.example.com", "b.example.com"},
+		},
+		{
+			title: "unresolvable HostRegexp",
+			match: "HostRegexp(`^.+\\.example\\.com$`)",
+		},
+		{
+			title:       "unresolvable HostRegexp with annotation",
+			match:       "HostRegexp(`^.+\\.example\\.com$`)",
+			annotations: map[string]string{hostRegexpHostnamesAnnotationKey: "c.example.com, *.d.example.com"},
+			expected:    []string{"c.example.com", "*.d.example.com"},
+		},
+		{
+			title:       "annotation ignored without unresolvable HostRegexp",
+			match:       "Host(`a.example.com`)",
+			annotations: map[string]string{hostRegexpHostnamesAnnotationKey: "c.example.com"},
+			expected:    []string{"a.example.com"},
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			annotations := map[string]string{"external-dns.alpha.kubernetes.io/target": "target.domain.tld"}
+			for k, v := range ti.annotations {
+				annotations[k] = v
+			}
+			ir := IngressRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: ingressrouteGVR.GroupVersion().String(),
+					Kind:       "IngressRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ingressroute-host-regexp",
+					Namespace:   defaultTraefikNamespace,
+					Annotations: annotations,
+				},
+				Spec: traefikIngressRouteSpec{
+					Routes: []traefikRoute{{Match: ti.match}},
+				},
+			}
+			u := &unstructured.Unstructured{}
+			data, err := json.Marshal(ir)
+			require.NoError(t, err)
+			require.NoError(t, u.UnmarshalJSON(data))
+
+			scheme := runtime.NewScheme()
+			scheme.AddKnownTypes(ingressrouteGVR.GroupVersion(), &IngressRoute{}, &IngressRouteList{})
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(scheme)
+			_, err = fakeDynamicClient.Resource(ingressrouteGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), u, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, newTraefikFakeKubeClient(ingressrouteGVR), defaultTraefikNamespace, "", false, true, false, false, "")
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			var names []string
+			for _, ep := range endpoints {
+				names = append(names, ep.DNSName)
+			}
+			assert.ElementsMatch(t, ti.expected, names)
+		})
+	}
+}
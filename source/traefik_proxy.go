@@ -20,19 +20,23 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/cache"
@@ -88,53 +92,49 @@ type traefikSource struct {
 	oldIngressRouteInformer    informers.GenericInformer
 	oldIngressRouteTcpInformer informers.GenericInformer
 	oldIngressRouteUdpInformer informers.GenericInformer
+	serviceInformer            coreinformers.ServiceInformer
 	kubeClient                 kubernetes.Interface
 	namespace                  string
+	traefikService             string
 	unstructuredConverter      *unstructuredConverter
 }
 
-func NewTraefikSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, ignoreHostnameAnnotation bool) (Source, error) {
+// NewTraefikSource creates a source of the IngressRoutes, IngressRouteTCPs and IngressRouteUDPs of both the
+// traefik.io API group of Traefik v3 and the legacy traefik.containo.us API group of earlier versions, unless
+// disableLegacy is set. The IngressRouteTCPs and IngressRouteUDPs aren't watched with disableTCP and disableUDP.
+// Only the resources served by the cluster are watched, so that the source works with the CRDs of either API group
+// installed. Routes without a target annotation get the load balancer addresses of the
+// traefikService (<namespace>/<name>) as targets, unless it is empty.
+func NewTraefikSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, ignoreHostnameAnnotation bool, disableLegacy bool, disableTCP bool, disableUDP bool, traefikService string) (Source, error) {
+	gvrs := []schema.GroupVersionResource{ingressrouteGVR, oldIngressrouteGVR}
+	if !disableTCP {
+		gvrs = append(gvrs, ingressrouteTCPGVR, oldIngressrouteTCPGVR)
+	}
+	if !disableUDP {
+		gvrs = append(gvrs, ingressrouteUDPGVR, oldIngressrouteUDPGVR)
+	}
+	if disableLegacy {
+		gvrs = slices.DeleteFunc(gvrs, func(gvr schema.GroupVersionResource) bool {
+			return gvr.Group == oldIngressrouteGVR.Group
+		})
+	}
+	served, err := servedResources(kubeClient.Discovery(), gvrs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to discover the Traefik resources")
+	}
+	if len(served) == 0 {
+		log.Warn("The cluster serves none of the Traefik IngressRoute resources, are the Traefik CRDs installed?")
+	}
+
 	// Use shared informer to listen for add/update/delete of Host in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed.
-	informerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicKubeClient, 0, namespace, nil)
-	ingressRouteInformer := informerFactory.ForResource(ingressrouteGVR)
-	ingressRouteTcpInformer := informerFactory.ForResource(ingressrouteTCPGVR)
-	ingressRouteUdpInformer := informerFactory.ForResource(ingressrouteUDPGVR)
-	oldIngressRouteInformer := informerFactory.ForResource(oldIngressrouteGVR)
-	oldIngressRouteTcpInformer := informerFactory.ForResource(oldIngressrouteTCPGVR)
-	oldIngressRouteUdpInformer := informerFactory.ForResource(oldIngressrouteUDPGVR)
-
-	// Add default resource event handlers to properly initialize informers.
-	ingressRouteInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {},
-		},
-	)
-	ingressRouteTcpInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {},
-		},
-	)
-	ingressRouteUdpInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {},
-		},
-	)
-	oldIngressRouteInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {},
-		},
-	)
-	oldIngressRouteTcpInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {},
-		},
-	)
-	oldIngressRouteUdpInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {},
-		},
-	)
+	informerFactory := sharedDynamicInformerFactory(ctx, dynamicKubeClient, namespace)
+	ingressRouteInformer := traefikInformer(informerFactory, ingressrouteGVR, served)
+	ingressRouteTcpInformer := traefikInformer(informerFactory, ingressrouteTCPGVR, served)
+	ingressRouteUdpInformer := traefikInformer(informerFactory, ingressrouteUDPGVR, served)
+	oldIngressRouteInformer := traefikInformer(informerFactory, oldIngressrouteGVR, served)
+	oldIngressRouteTcpInformer := traefikInformer(informerFactory, oldIngressrouteTCPGVR, served)
+	oldIngressRouteUdpInformer := traefikInformer(informerFactory, oldIngressrouteUDPGVR, served)
 
 	informerFactory.Start((ctx.Done()))
 
@@ -143,6 +143,11 @@ func NewTraefikSource(ctx context.Context, dynamicKubeClient dynamic.Interface,
 		return nil, err
 	}
 
+	serviceInformer, err := proxyServiceInformer(ctx, kubeClient, traefikService)
+	if err != nil {
+		return nil, err
+	}
+
 	uc, err := newTraefikUnstructuredConverter()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to setup Unstructured Converter")
@@ -158,8 +163,10 @@ func NewTraefikSource(ctx context.Context, dynamicKubeClient dynamic.Interface,
 		oldIngressRouteInformer:    oldIngressRouteInformer,
 		oldIngressRouteTcpInformer: oldIngressRouteTcpInformer,
 		oldIngressRouteUdpInformer: oldIngressRouteUdpInformer,
+		serviceInformer:            serviceInformer,
 		kubeClient:                 kubeClient,
 		namespace:                  namespace,
+		traefikService:             traefikService,
 		unstructuredConverter:      uc,
 	}, nil
 }
@@ -167,27 +174,37 @@ func NewTraefikSource(ctx context.Context, dynamicKubeClient dynamic.Interface,
 func (ts *traefikSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	var endpoints []*endpoint.Endpoint
 
-	ingressRouteEndpoints, err := ts.ingressRouteEndpoints()
+	services := &proxyServiceTargets{
+		kubeClient:     ts.kubeClient,
+		proxy:          "Traefik",
+		annotationKey:  traefikServiceAnnotationKey,
+		defaultService: ts.traefikService,
+	}
+	if ts.serviceInformer != nil {
+		services.lister = ts.serviceInformer.Lister()
+	}
+
+	ingressRouteEndpoints, err := ts.ingressRouteEndpoints(ctx, services)
 	if err != nil {
 		return nil, err
 	}
-	oldIngressRouteEndpoints, err := ts.oldIngressRouteEndpoints()
+	oldIngressRouteEndpoints, err := ts.oldIngressRouteEndpoints(ctx, services)
 	if err != nil {
 		return nil, err
 	}
-	ingressRouteTCPEndpoints, err := ts.ingressRouteTCPEndpoints()
+	ingressRouteTCPEndpoints, err := ts.ingressRouteTCPEndpoints(ctx, services)
 	if err != nil {
 		return nil, err
 	}
-	oldIngressRouteTCPEndpoints, err := ts.oldIngressRouteTCPEndpoints()
+	oldIngressRouteTCPEndpoints, err := ts.oldIngressRouteTCPEndpoints(ctx, services)
 	if err != nil {
 		return nil, err
 	}
-	ingressRouteUDPEndpoints, err := ts.ingressRouteUDPEndpoints()
+	ingressRouteUDPEndpoints, err := ts.ingressRouteUDPEndpoints(ctx, services)
 	if err != nil {
 		return nil, err
 	}
-	oldIngressRouteUDPEndpoints, err := ts.oldIngressRouteUDPEndpoints()
+	oldIngressRouteUDPEndpoints, err := ts.oldIngressRouteUDPEndpoints(ctx, services)
 	if err != nil {
 		return nil, err
 	}
@@ -207,7 +224,11 @@ func (ts *traefikSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 }
 
 // ingressRouteEndpoints extracts endpoints from all IngressRoute objects
-func (ts *traefikSource) ingressRouteEndpoints() ([]*endpoint.Endpoint, error) {
+func (ts *traefikSource) ingressRouteEndpoints(ctx context.Context, services *proxyServiceTargets) ([]*endpoint.Endpoint, error) {
+	if ts.ingressRouteInformer == nil {
+		return nil, nil
+	}
+
 	var endpoints []*endpoint.Endpoint
 
 	irs, err := ts.ingressRouteInformer.Lister().ByNamespace(ts.namespace).List(labels.Everything())
@@ -236,9 +257,10 @@ func (ts *traefikSource) ingressRouteEndpoints() ([]*endpoint.Endpoint, error) {
 	}
 
 	for _, ingressRoute := range ingressRoutes {
-		var targets endpoint.Targets
-
-		targets = append(targets, getTargetsFromTargetAnnotation(ingressRoute.Annotations)...)
+		targets, err := services.targets(ctx, ingressRoute.Namespace, ingressRoute.Annotations)
+		if err != nil {
+			return nil, err
+		}
 
 		fullname := fmt.Sprintf("%s/%s", ingressRoute.Namespace, ingressRoute.Name)
 
@@ -253,6 +275,7 @@ func (ts *traefikSource) ingressRouteEndpoints() ([]*endpoint.Endpoint, error) {
 
 		log.Debugf("Endpoints generated from IngressRoute: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRoute(ingressRoute, ingressEndpoints)
+		ingressEndpoints = withRecordType(ingressRoute.Annotations, ingressEndpoints)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -260,7 +283,11 @@ func (ts *traefikSource) ingressRouteEndpoints() ([]*endpoint.Endpoint, error) {
 }
 
 // ingressRouteTCPEndpoints extracts endpoints from all IngressRouteTCP objects
-func (ts *traefikSource) ingressRouteTCPEndpoints() ([]*endpoint.Endpoint, error) {
+func (ts *traefikSource) ingressRouteTCPEndpoints(ctx context.Context, services *proxyServiceTargets) ([]*endpoint.Endpoint, error) {
+	if ts.ingressRouteTcpInformer == nil {
+		return nil, nil
+	}
+
 	var endpoints []*endpoint.Endpoint
 
 	irs, err := ts.ingressRouteTcpInformer.Lister().ByNamespace(ts.namespace).List(labels.Everything())
@@ -289,9 +316,10 @@ func (ts *traefikSource) ingressRouteTCPEndpoints() ([]*endpoint.Endpoint, error
 	}
 
 	for _, ingressRouteTCP := range ingressRouteTCPs {
-		var targets endpoint.Targets
-
-		targets = append(targets, getTargetsFromTargetAnnotation(ingressRouteTCP.Annotations)...)
+		targets, err := services.targets(ctx, ingressRouteTCP.Namespace, ingressRouteTCP.Annotations)
+		if err != nil {
+			return nil, err
+		}
 
 		fullname := fmt.Sprintf("%s/%s", ingressRouteTCP.Namespace, ingressRouteTCP.Name)
 
@@ -306,6 +334,7 @@ func (ts *traefikSource) ingressRouteTCPEndpoints() ([]*endpoint.Endpoint, error
 
 		log.Debugf("Endpoints generated from IngressRouteTCP: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRouteTCP(ingressRouteTCP, ingressEndpoints)
+		ingressEndpoints = withRecordType(ingressRouteTCP.Annotations, ingressEndpoints)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -313,7 +342,11 @@ func (ts *traefikSource) ingressRouteTCPEndpoints() ([]*endpoint.Endpoint, error
 }
 
 // ingressRouteUDPEndpoints extracts endpoints from all IngressRouteUDP objects
-func (ts *traefikSource) ingressRouteUDPEndpoints() ([]*endpoint.Endpoint, error) {
+func (ts *traefikSource) ingressRouteUDPEndpoints(ctx context.Context, services *proxyServiceTargets) ([]*endpoint.Endpoint, error) {
+	if ts.ingressRouteUdpInformer == nil {
+		return nil, nil
+	}
+
 	var endpoints []*endpoint.Endpoint
 
 	irs, err := ts.ingressRouteUdpInformer.Lister().ByNamespace(ts.namespace).List(labels.Everything())
@@ -342,9 +375,10 @@ func (ts *traefikSource) ingressRouteUDPEndpoints() ([]*endpoint.Endpoint, error
 	}
 
 	for _, ingressRouteUDP := range ingressRouteUDPs {
-		var targets endpoint.Targets
-
-		targets = append(targets, getTargetsFromTargetAnnotation(ingressRouteUDP.Annotations)...)
+		targets, err := services.targets(ctx, ingressRouteUDP.Namespace, ingressRouteUDP.Annotations)
+		if err != nil {
+			return nil, err
+		}
 
 		fullname := fmt.Sprintf("%s/%s", ingressRouteUDP.Namespace, ingressRouteUDP.Name)
 
@@ -359,6 +393,7 @@ func (ts *traefikSource) ingressRouteUDPEndpoints() ([]*endpoint.Endpoint, error
 
 		log.Debugf("Endpoints generated from IngressRouteUDP: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRouteUDP(ingressRouteUDP, ingressEndpoints)
+		ingressEndpoints = withRecordType(ingressRouteUDP.Annotations, ingressEndpoints)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -366,7 +401,11 @@ func (ts *traefikSource) ingressRouteUDPEndpoints() ([]*endpoint.Endpoint, error
 }
 
 // oldIngressRouteEndpoints extracts endpoints from all IngressRoute objects
-func (ts *traefikSource) oldIngressRouteEndpoints() ([]*endpoint.Endpoint, error) {
+func (ts *traefikSource) oldIngressRouteEndpoints(ctx context.Context, services *proxyServiceTargets) ([]*endpoint.Endpoint, error) {
+	if ts.oldIngressRouteInformer == nil {
+		return nil, nil
+	}
+
 	var endpoints []*endpoint.Endpoint
 
 	irs, err := ts.oldIngressRouteInformer.Lister().ByNamespace(ts.namespace).List(labels.Everything())
@@ -395,9 +434,10 @@ func (ts *traefikSource) oldIngressRouteEndpoints() ([]*endpoint.Endpoint, error
 	}
 
 	for _, ingressRoute := range ingressRoutes {
-		var targets endpoint.Targets
-
-		targets = append(targets, getTargetsFromTargetAnnotation(ingressRoute.Annotations)...)
+		targets, err := services.targets(ctx, ingressRoute.Namespace, ingressRoute.Annotations)
+		if err != nil {
+			return nil, err
+		}
 
 		fullname := fmt.Sprintf("%s/%s", ingressRoute.Namespace, ingressRoute.Name)
 
@@ -412,6 +452,7 @@ func (ts *traefikSource) oldIngressRouteEndpoints() ([]*endpoint.Endpoint, error
 
 		log.Debugf("Endpoints generated from IngressRoute: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRoute(ingressRoute, ingressEndpoints)
+		ingressEndpoints = withRecordType(ingressRoute.Annotations, ingressEndpoints)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -419,7 +460,11 @@ func (ts *traefikSource) oldIngressRouteEndpoints() ([]*endpoint.Endpoint, error
 }
 
 // oldIngressRouteTCPEndpoints extracts endpoints from all IngressRouteTCP objects
-func (ts *traefikSource) oldIngressRouteTCPEndpoints() ([]*endpoint.Endpoint, error) {
+func (ts *traefikSource) oldIngressRouteTCPEndpoints(ctx context.Context, services *proxyServiceTargets) ([]*endpoint.Endpoint, error) {
+	if ts.oldIngressRouteTcpInformer == nil {
+		return nil, nil
+	}
+
 	var endpoints []*endpoint.Endpoint
 
 	irs, err := ts.oldIngressRouteTcpInformer.Lister().ByNamespace(ts.namespace).List(labels.Everything())
@@ -448,9 +493,10 @@ func (ts *traefikSource) oldIngressRouteTCPEndpoints() ([]*endpoint.Endpoint, er
 	}
 
 	for _, ingressRouteTCP := range ingressRouteTCPs {
-		var targets endpoint.Targets
-
-		targets = append(targets, getTargetsFromTargetAnnotation(ingressRouteTCP.Annotations)...)
+		targets, err := services.targets(ctx, ingressRouteTCP.Namespace, ingressRouteTCP.Annotations)
+		if err != nil {
+			return nil, err
+		}
 
 		fullname := fmt.Sprintf("%s/%s", ingressRouteTCP.Namespace, ingressRouteTCP.Name)
 
@@ -465,6 +511,7 @@ func (ts *traefikSource) oldIngressRouteTCPEndpoints() ([]*endpoint.Endpoint, er
 
 		log.Debugf("Endpoints generated from IngressRouteTCP: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRouteTCP(ingressRouteTCP, ingressEndpoints)
+		ingressEndpoints = withRecordType(ingressRouteTCP.Annotations, ingressEndpoints)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -472,7 +519,11 @@ func (ts *traefikSource) oldIngressRouteTCPEndpoints() ([]*endpoint.Endpoint, er
 }
 
 // oldIngressRouteUDPEndpoints extracts endpoints from all IngressRouteUDP objects
-func (ts *traefikSource) oldIngressRouteUDPEndpoints() ([]*endpoint.Endpoint, error) {
+func (ts *traefikSource) oldIngressRouteUDPEndpoints(ctx context.Context, services *proxyServiceTargets) ([]*endpoint.Endpoint, error) {
+	if ts.oldIngressRouteUdpInformer == nil {
+		return nil, nil
+	}
+
 	var endpoints []*endpoint.Endpoint
 
 	irs, err := ts.oldIngressRouteUdpInformer.Lister().ByNamespace(ts.namespace).List(labels.Everything())
@@ -501,9 +552,10 @@ func (ts *traefikSource) oldIngressRouteUDPEndpoints() ([]*endpoint.Endpoint, er
 	}
 
 	for _, ingressRouteUDP := range ingressRouteUDPs {
-		var targets endpoint.Targets
-
-		targets = append(targets, getTargetsFromTargetAnnotation(ingressRouteUDP.Annotations)...)
+		targets, err := services.targets(ctx, ingressRouteUDP.Namespace, ingressRouteUDP.Annotations)
+		if err != nil {
+			return nil, err
+		}
 
 		fullname := fmt.Sprintf("%s/%s", ingressRouteUDP.Namespace, ingressRouteUDP.Name)
 
@@ -518,6 +570,7 @@ func (ts *traefikSource) oldIngressRouteUDPEndpoints() ([]*endpoint.Endpoint, er
 
 		log.Debugf("Endpoints generated from IngressRouteUDP: %s: %v", fullname, ingressEndpoints)
 		ts.setDualstackLabelIngressRouteUDP(ingressRouteUDP, ingressEndpoints)
+		ingressEndpoints = withRecordType(ingressRouteUDP.Annotations, ingressEndpoints)
 		endpoints = append(endpoints, ingressEndpoints...)
 	}
 
@@ -662,6 +715,7 @@ func (ts *traefikSource) endpointsFromIngressRoute(ingressRoute *IngressRoute, t
 		}
 	}
 
+	unresolvedHostRegexp := false
 	for _, route := range ingressRoute.Spec.Routes {
 		match := route.Match
 
@@ -676,6 +730,19 @@ func (ts *traefikSource) endpointsFromIngressRoute(ingressRoute *IngressRoute, t
 				}
 			}
 		}
+
+		hostnames, unresolved := hostRegexpHostnames(match)
+		for _, host := range hostnames {
+			endpoints = append(endpoints, endpointsForHostname(host, targets, ttl, providerSpecific, setIdentifier, resource)...)
+		}
+		unresolvedHostRegexp = unresolvedHostRegexp || unresolved
+	}
+
+	// the hostnames of the HostRegexp matchers which can't be enumerated are listed by an annotation
+	if hostnames, ok := ingressRoute.Annotations[hostRegexpHostnamesAnnotationKey]; ok && unresolvedHostRegexp {
+		for _, hostname := range splitHostnameAnnotation(hostnames) {
+			endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier, resource)...)
+		}
 	}
 
 	return endpoints, nil
@@ -698,6 +765,7 @@ func (ts *traefikSource) endpointsFromIngressRouteTCP(ingressRoute *IngressRoute
 		}
 	}
 
+	unresolvedHostRegexp := false
 	for _, route := range ingressRoute.Spec.Routes {
 		match := route.Match
 
@@ -713,6 +781,19 @@ func (ts *traefikSource) endpointsFromIngressRouteTCP(ingressRoute *IngressRoute
 				}
 			}
 		}
+
+		hostnames, unresolved := hostRegexpHostnames(match)
+		for _, host := range hostnames {
+			endpoints = append(endpoints, endpointsForHostname(host, targets, ttl, providerSpecific, setIdentifier, resource)...)
+		}
+		unresolvedHostRegexp = unresolvedHostRegexp || unresolved
+	}
+
+	// the hostnames of the HostRegexp matchers which can't be enumerated are listed by an annotation
+	if hostnames, ok := ingressRoute.Annotations[hostRegexpHostnamesAnnotationKey]; ok && unresolvedHostRegexp {
+		for _, hostname := range splitHostnameAnnotation(hostnames) {
+			endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier, resource)...)
+		}
 	}
 
 	return endpoints, nil
@@ -741,15 +822,67 @@ func (ts *traefikSource) endpointsFromIngressRouteUDP(ingressRoute *IngressRoute
 func (ts *traefikSource) AddEventHandler(ctx context.Context, handler func()) {
 	// Right now there is no way to remove event handler from informer, see:
 	// https://github.com/kubernetes/kubernetes/issues/79610
-	log.Debug("Adding event handler for IngressRoute")
-	ts.ingressRouteInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
-	ts.oldIngressRouteInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
-	log.Debug("Adding event handler for IngressRouteTCP")
-	ts.ingressRouteTcpInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
-	ts.oldIngressRouteTcpInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
-	log.Debug("Adding event handler for IngressRouteUDP")
-	ts.ingressRouteUdpInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
-	ts.oldIngressRouteUdpInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	log.Debug("Adding event handler for IngressRoute, IngressRouteTCP and IngressRouteUDP")
+	for _, informer := range []informers.GenericInformer{
+		ts.ingressRouteInformer,
+		ts.oldIngressRouteInformer,
+		ts.ingressRouteTcpInformer,
+		ts.oldIngressRouteTcpInformer,
+		ts.ingressRouteUdpInformer,
+		ts.oldIngressRouteUdpInformer,
+	} {
+		if informer != nil {
+			informer.Informer().AddEventHandler(eventHandlerFunc(handler))
+		}
+	}
+	if ts.serviceInformer != nil {
+		log.Debug("Adding event handler for the Traefik Service")
+		ts.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	}
+}
+
+// traefikInformer returns the informer of the resource, or nil if the resource is not served.
+func traefikInformer(factory dynamicinformer.DynamicSharedInformerFactory, gvr schema.GroupVersionResource, served map[schema.GroupVersionResource]bool) informers.GenericInformer {
+	if !served[gvr] {
+		log.Debugf("Not watching %s, it is not served by the cluster or disabled", gvr)
+		return nil
+	}
+	informer := factory.ForResource(gvr)
+	// Add default resource event handlers to properly initialize informers.
+	informer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {},
+		},
+	)
+	return informer
+}
+
+// servedResources returns which of the resources are served by the cluster.
+func servedResources(client discovery.DiscoveryInterface, gvrs []schema.GroupVersionResource) (map[schema.GroupVersionResource]bool, error) {
+	served := map[schema.GroupVersionResource]bool{}
+	resourceLists := map[schema.GroupVersion]*metav1.APIResourceList{}
+	for _, gvr := range gvrs {
+		gv := gvr.GroupVersion()
+		resourceList, ok := resourceLists[gv]
+		if !ok {
+			var err error
+			resourceList, err = client.ServerResourcesForGroupVersion(gv.String())
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			resourceLists[gv] = resourceList
+		}
+		if resourceList == nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if resource.Name == gvr.Resource {
+				served[gvr] = true
+				break
+			}
+		}
+	}
+	return served, nil
 }
 
 // newTraefikUnstructuredConverter returns a new unstructuredConverter initialized
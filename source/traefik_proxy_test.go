@@ -20,11 +20,15 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	fakeDynamic "k8s.io/client-go/dynamic/fake"
 	fakeKube "k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/external-dns/endpoint"
@@ -35,6 +39,25 @@ var _ Source = &traefikSource{}
 
 const defaultTraefikNamespace = "traefik"
 
+// newTraefikFakeKubeClient returns a fake client whose discovery serves the given resources.
+func newTraefikFakeKubeClient(gvrs ...schema.GroupVersionResource) *fakeKube.Clientset {
+	client := fakeKube.NewSimpleClientset()
+	for _, gvr := range gvrs {
+		var resourceList *metav1.APIResourceList
+		for _, list := range client.Fake.Resources {
+			if list.GroupVersion == gvr.GroupVersion().String() {
+				resourceList = list
+			}
+		}
+		if resourceList == nil {
+			resourceList = &metav1.APIResourceList{GroupVersion: gvr.GroupVersion().String()}
+			client.Fake.Resources = append(client.Fake.Resources, resourceList)
+		}
+		resourceList.APIResources = append(resourceList.APIResources, metav1.APIResource{Name: gvr.Resource, Namespaced: true})
+	}
+	return client
+}
+
 func TestTraefikProxyIngressRouteEndpoints(t *testing.T) {
 	t.Parallel()
 
@@ -322,12 +345,43 @@ func TestTraefikProxyIngressRouteEndpoints(t *testing.T) {
 			},
 			expected: nil,
 		},
+		{
+			title: "IngressRoute with TTL annotation",
+			ingressRoute: IngressRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: ingressrouteGVR.GroupVersion().String(),
+					Kind:       "IngressRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingressroute-ttl",
+					Namespace: defaultTraefikNamespace,
+					Annotations: map[string]string{
+						"external-dns.alpha.kubernetes.io/hostname": "a.example.com",
+						"external-dns.alpha.kubernetes.io/target":   "target.domain.tld",
+						"external-dns.alpha.kubernetes.io/ttl":      "10m",
+						"kubernetes.io/ingress.class":               "traefik",
+					},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "a.example.com",
+					Targets:    []string{"target.domain.tld"},
+					RecordType: endpoint.RecordTypeCNAME,
+					RecordTTL:  600,
+					Labels: endpoint.Labels{
+						"resource": "ingressroute/traefik/ingressroute-ttl",
+					},
+					ProviderSpecific: endpoint.ProviderSpecific{},
+				},
+			},
+		},
 	} {
 		ti := ti
 		t.Run(ti.title, func(t *testing.T) {
 			t.Parallel()
 
-			fakeKubernetesClient := fakeKube.NewSimpleClientset()
+			fakeKubernetesClient := newTraefikFakeKubeClient(ingressrouteGVR, ingressrouteTCPGVR, ingressrouteUDPGVR, oldIngressrouteGVR, oldIngressrouteTCPGVR, oldIngressrouteUDPGVR)
 			scheme := runtime.NewScheme()
 			scheme.AddKnownTypes(ingressrouteGVR.GroupVersion(), &IngressRoute{}, &IngressRouteList{})
 			scheme.AddKnownTypes(ingressrouteTCPGVR.GroupVersion(), &IngressRouteTCP{}, &IngressRouteTCPList{})
@@ -348,7 +402,7 @@ func TestTraefikProxyIngressRouteEndpoints(t *testing.T) {
 			_, err = fakeDynamicClient.Resource(ingressrouteGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), &ir, metav1.CreateOptions{})
 			assert.NoError(t, err)
 
-			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation)
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation, false, false, false, "")
 			assert.NoError(t, err)
 			assert.NotNil(t, source)
 
@@ -616,12 +670,43 @@ func TestTraefikProxyIngressRouteTCPEndpoints(t *testing.T) {
 			},
 			expected: nil,
 		},
+		{
+			title: "IngressRouteTCP with TTL annotation",
+			ingressRouteTCP: IngressRouteTCP{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: ingressrouteTCPGVR.GroupVersion().String(),
+					Kind:       "IngressRouteTCP",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingressroutetcp-ttl",
+					Namespace: defaultTraefikNamespace,
+					Annotations: map[string]string{
+						"external-dns.alpha.kubernetes.io/hostname": "a.example.com",
+						"external-dns.alpha.kubernetes.io/target":   "target.domain.tld",
+						"external-dns.alpha.kubernetes.io/ttl":      "10m",
+						"kubernetes.io/ingress.class":               "traefik",
+					},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "a.example.com",
+					Targets:    []string{"target.domain.tld"},
+					RecordType: endpoint.RecordTypeCNAME,
+					RecordTTL:  600,
+					Labels: endpoint.Labels{
+						"resource": "ingressroutetcp/traefik/ingressroutetcp-ttl",
+					},
+					ProviderSpecific: endpoint.ProviderSpecific{},
+				},
+			},
+		},
 	} {
 		ti := ti
 		t.Run(ti.title, func(t *testing.T) {
 			t.Parallel()
 
-			fakeKubernetesClient := fakeKube.NewSimpleClientset()
+			fakeKubernetesClient := newTraefikFakeKubeClient(ingressrouteGVR, ingressrouteTCPGVR, ingressrouteUDPGVR, oldIngressrouteGVR, oldIngressrouteTCPGVR, oldIngressrouteUDPGVR)
 			scheme := runtime.NewScheme()
 			scheme.AddKnownTypes(ingressrouteGVR.GroupVersion(), &IngressRoute{}, &IngressRouteList{})
 			scheme.AddKnownTypes(ingressrouteTCPGVR.GroupVersion(), &IngressRouteTCP{}, &IngressRouteTCPList{})
@@ -642,7 +727,7 @@ func TestTraefikProxyIngressRouteTCPEndpoints(t *testing.T) {
 			_, err = fakeDynamicClient.Resource(ingressrouteTCPGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), &ir, metav1.CreateOptions{})
 			assert.NoError(t, err)
 
-			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation)
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation, false, false, false, "")
 			assert.NoError(t, err)
 			assert.NotNil(t, source)
 
@@ -758,12 +843,43 @@ func TestTraefikProxyIngressRouteUDPEndpoints(t *testing.T) {
 			ignoreHostnameAnnotation: true,
 			expected:                 nil,
 		},
+		{
+			title: "IngressRouteUDP with TTL annotation",
+			ingressRouteUDP: IngressRouteUDP{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: ingressrouteUDPGVR.GroupVersion().String(),
+					Kind:       "IngressRouteUDP",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingressrouteudp-ttl",
+					Namespace: defaultTraefikNamespace,
+					Annotations: map[string]string{
+						"external-dns.alpha.kubernetes.io/hostname": "a.example.com",
+						"external-dns.alpha.kubernetes.io/target":   "target.domain.tld",
+						"external-dns.alpha.kubernetes.io/ttl":      "10m",
+						"kubernetes.io/ingress.class":               "traefik",
+					},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName:    "a.example.com",
+					Targets:    []string{"target.domain.tld"},
+					RecordType: endpoint.RecordTypeCNAME,
+					RecordTTL:  600,
+					Labels: endpoint.Labels{
+						"resource": "ingressrouteudp/traefik/ingressrouteudp-ttl",
+					},
+					ProviderSpecific: endpoint.ProviderSpecific{},
+				},
+			},
+		},
 	} {
 		ti := ti
 		t.Run(ti.title, func(t *testing.T) {
 			t.Parallel()
 
-			fakeKubernetesClient := fakeKube.NewSimpleClientset()
+			fakeKubernetesClient := newTraefikFakeKubeClient(ingressrouteGVR, ingressrouteTCPGVR, ingressrouteUDPGVR, oldIngressrouteGVR, oldIngressrouteTCPGVR, oldIngressrouteUDPGVR)
 			scheme := runtime.NewScheme()
 			scheme.AddKnownTypes(ingressrouteGVR.GroupVersion(), &IngressRoute{}, &IngressRouteList{})
 			scheme.AddKnownTypes(ingressrouteTCPGVR.GroupVersion(), &IngressRouteTCP{}, &IngressRouteTCPList{})
@@ -784,7 +900,7 @@ func TestTraefikProxyIngressRouteUDPEndpoints(t *testing.T) {
 			_, err = fakeDynamicClient.Resource(ingressrouteUDPGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), &ir, metav1.CreateOptions{})
 			assert.NoError(t, err)
 
-			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation)
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation, false, false, false, "")
 			assert.NoError(t, err)
 			assert.NotNil(t, source)
 
@@ -1093,7 +1209,7 @@ func TestTraefikProxyOldIngressRouteEndpoints(t *testing.T) {
 		t.Run(ti.title, func(t *testing.T) {
 			t.Parallel()
 
-			fakeKubernetesClient := fakeKube.NewSimpleClientset()
+			fakeKubernetesClient := newTraefikFakeKubeClient(ingressrouteGVR, ingressrouteTCPGVR, ingressrouteUDPGVR, oldIngressrouteGVR, oldIngressrouteTCPGVR, oldIngressrouteUDPGVR)
 			scheme := runtime.NewScheme()
 			scheme.AddKnownTypes(ingressrouteGVR.GroupVersion(), &IngressRoute{}, &IngressRouteList{})
 			scheme.AddKnownTypes(ingressrouteTCPGVR.GroupVersion(), &IngressRouteTCP{}, &IngressRouteTCPList{})
@@ -1114,7 +1230,7 @@ func TestTraefikProxyOldIngressRouteEndpoints(t *testing.T) {
 			_, err = fakeDynamicClient.Resource(oldIngressrouteGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), &ir, metav1.CreateOptions{})
 			assert.NoError(t, err)
 
-			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation)
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation, false, false, false, "")
 			assert.NoError(t, err)
 			assert.NotNil(t, source)
 
@@ -1387,7 +1503,7 @@ func TestTraefikProxyOldIngressRouteTCPEndpoints(t *testing.T) {
 		t.Run(ti.title, func(t *testing.T) {
 			t.Parallel()
 
-			fakeKubernetesClient := fakeKube.NewSimpleClientset()
+			fakeKubernetesClient := newTraefikFakeKubeClient(ingressrouteGVR, ingressrouteTCPGVR, ingressrouteUDPGVR, oldIngressrouteGVR, oldIngressrouteTCPGVR, oldIngressrouteUDPGVR)
 			scheme := runtime.NewScheme()
 			scheme.AddKnownTypes(ingressrouteGVR.GroupVersion(), &IngressRoute{}, &IngressRouteList{})
 			scheme.AddKnownTypes(ingressrouteTCPGVR.GroupVersion(), &IngressRouteTCP{}, &IngressRouteTCPList{})
@@ -1408,7 +1524,7 @@ func TestTraefikProxyOldIngressRouteTCPEndpoints(t *testing.T) {
 			_, err = fakeDynamicClient.Resource(oldIngressrouteTCPGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), &ir, metav1.CreateOptions{})
 			assert.NoError(t, err)
 
-			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation)
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation, false, false, false, "")
 			assert.NoError(t, err)
 			assert.NotNil(t, source)
 
@@ -1529,7 +1645,7 @@ func TestTraefikProxyOldIngressRouteUDPEndpoints(t *testing.T) {
 		t.Run(ti.title, func(t *testing.T) {
 			t.Parallel()
 
-			fakeKubernetesClient := fakeKube.NewSimpleClientset()
+			fakeKubernetesClient := newTraefikFakeKubeClient(ingressrouteGVR, ingressrouteTCPGVR, ingressrouteUDPGVR, oldIngressrouteGVR, oldIngressrouteTCPGVR, oldIngressrouteUDPGVR)
 			scheme := runtime.NewScheme()
 			scheme.AddKnownTypes(ingressrouteGVR.GroupVersion(), &IngressRoute{}, &IngressRouteList{})
 			scheme.AddKnownTypes(ingressrouteTCPGVR.GroupVersion(), &IngressRouteTCP{}, &IngressRouteTCPList{})
@@ -1550,7 +1666,7 @@ func TestTraefikProxyOldIngressRouteUDPEndpoints(t *testing.T) {
 			_, err = fakeDynamicClient.Resource(oldIngressrouteUDPGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), &ir, metav1.CreateOptions{})
 			assert.NoError(t, err)
 
-			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation)
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "kubernetes.io/ingress.class=traefik", ti.ignoreHostnameAnnotation, false, false, false, "")
 			assert.NoError(t, err)
 			assert.NotNil(t, source)
 
@@ -1566,3 +1682,160 @@ func TestTraefikProxyOldIngressRouteUDPEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestTraefikProxyAPIGroups(t *testing.T) {
+	t.Parallel()
+
+	newIngressRoute := func(gvr schema.GroupVersionResource, name, hostname string) *unstructured.Unstructured {
+		ir := IngressRoute{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: gvr.GroupVersion().String(),
+				Kind:       "IngressRoute",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: defaultTraefikNamespace,
+				Annotations: map[string]string{
+					"external-dns.alpha.kubernetes.io/target": "target.domain.tld",
+				},
+			},
+			Spec: traefikIngressRouteSpec{
+				Routes: []traefikRoute{{Match: "Host(`" + hostname + "`)"}},
+			},
+		}
+		u := &unstructured.Unstructured{}
+		data, err := json.Marshal(ir)
+		assert.NoError(t, err)
+		assert.NoError(t, u.UnmarshalJSON(data))
+		return u
+	}
+
+	for _, ti := range []struct {
+		title         string
+		served        []schema.GroupVersionResource
+		disableLegacy bool
+		expected      []string
+	}{
+		{
+			title:    "Traefik v3 CRDs only",
+			served:   []schema.GroupVersionResource{ingressrouteGVR, ingressrouteTCPGVR, ingressrouteUDPGVR},
+			expected: []string{"a.example.com"},
+		},
+		{
+			title:    "legacy CRDs only",
+			served:   []schema.GroupVersionResource{oldIngressrouteGVR, oldIngressrouteTCPGVR, oldIngressrouteUDPGVR},
+			expected: []string{"b.example.com"},
+		},
+		{
+			title:    "both API groups",
+			served:   []schema.GroupVersionResource{ingressrouteGVR, oldIngressrouteGVR},
+			expected: []string{"a.example.com", "b.example.com"},
+		},
+		{
+			title:         "legacy API group disabled",
+			served:        []schema.GroupVersionResource{ingressrouteGVR, oldIngressrouteGVR},
+			disableLegacy: true,
+			expected:      []string{"a.example.com"},
+		},
+		{
+			title: "no CRDs",
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			// the dynamic client only knows the served resources, like the API server
+			scheme := runtime.NewScheme()
+			listKinds := map[schema.GroupVersionResource]string{}
+			for _, gvr := range ti.served {
+				listKinds[gvr] = "IngressRouteList"
+			}
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds)
+			for gvr, name := range map[schema.GroupVersionResource]string{ingressrouteGVR: "a.example.com", oldIngressrouteGVR: "b.example.com"} {
+				if _, ok := listKinds[gvr]; ok {
+					_, err := fakeDynamicClient.Resource(gvr).Namespace(defaultTraefikNamespace).Create(context.Background(), newIngressRoute(gvr, "ingressroute", name), metav1.CreateOptions{})
+					assert.NoError(t, err)
+				}
+			}
+
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, newTraefikFakeKubeClient(ti.served...), defaultTraefikNamespace, "", false, ti.disableLegacy, false, false, "")
+			assert.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			assert.NoError(t, err)
+			var names []string
+			for _, ep := range endpoints {
+				names = append(names, ep.DNSName)
+			}
+			assert.ElementsMatch(t, ti.expected, names)
+		})
+	}
+}
+
+func TestTraefikProxyAddEventHandler(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypes(ingressrouteGVR.GroupVersion(), &IngressRoute{}, &IngressRouteList{})
+	fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(scheme)
+	kubeClient := newTraefikFakeKubeClient(ingressrouteGVR)
+
+	source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, kubeClient, defaultTraefikNamespace, "", false, true, false, false, "traefik/traefik")
+	require.NoError(t, err)
+
+	events := make(chan struct{}, 10)
+	source.AddEventHandler(context.Background(), func() { events <- struct{}{} })
+	expectEvent := func(what string) {
+		select {
+		case <-events:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event after %s", what)
+		}
+	}
+
+	ir := &unstructured.Unstructured{}
+	ir.SetAPIVersion(ingressrouteGVR.GroupVersion().String())
+	ir.SetKind("IngressRoute")
+	ir.SetName("ingressroute")
+	ir.SetNamespace(defaultTraefikNamespace)
+	_, err = fakeDynamicClient.Resource(ingressrouteGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), ir, metav1.CreateOptions{})
+	require.NoError(t, err)
+	expectEvent("creating an IngressRoute")
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "traefik", Namespace: defaultTraefikNamespace}}
+	_, err = kubeClient.CoreV1().Services(defaultTraefikNamespace).Create(context.Background(), svc, metav1.CreateOptions{})
+	require.NoError(t, err)
+	expectEvent("creating the Traefik Service")
+}
+
+func TestTraefikProxyDisabledKinds(t *testing.T) {
+	t.Parallel()
+
+	all := []schema.GroupVersionResource{
+		ingressrouteGVR, ingressrouteTCPGVR, ingressrouteUDPGVR,
+		oldIngressrouteGVR, oldIngressrouteTCPGVR, oldIngressrouteUDPGVR,
+	}
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvr := range all {
+		listKinds[gvr] = "IngressRouteList"
+	}
+	fakeDynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+
+	src, err := NewTraefikSource(context.TODO(), fakeDynamicClient, newTraefikFakeKubeClient(all...), defaultTraefikNamespace, "", false, false, true, true, "")
+	require.NoError(t, err)
+	_, err = src.Endpoints(context.Background())
+	require.NoError(t, err)
+
+	ts := src.(*traefikSource)
+	assert.NotNil(t, ts.ingressRouteInformer)
+	assert.NotNil(t, ts.oldIngressRouteInformer)
+	assert.Nil(t, ts.ingressRouteTcpInformer)
+	assert.Nil(t, ts.oldIngressRouteTcpInformer)
+	assert.Nil(t, ts.ingressRouteUdpInformer)
+	assert.Nil(t, ts.oldIngressRouteUdpInformer)
+
+	for _, action := range fakeDynamicClient.Actions() {
+		assert.Equal(t, "ingressroutes", action.GetResource().Resource, "unexpected %s of %s", action.GetVerb(), action.GetResource())
+	}
+}
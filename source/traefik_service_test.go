@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestTraefikProxyServiceTargets(t *testing.T) {
+	t.Parallel()
+
+	for _, ti := range []struct {
+		title          string
+		traefikService string
+		annotations    map[string]string
+		expected       endpoint.Targets
+	}{
+		{
+			title:    "no Traefik Service",
+			expected: nil,
+		},
+		{
+			title:          "Traefik Service",
+			traefikService: "traefik/traefik",
+			expected:       endpoint.Targets{"1.2.3.4", "lb.example.com"},
+		},
+		{
+			title:          "target annotation takes precedence",
+			traefikService: "traefik/traefik",
+			annotations:    map[string]string{targetAnnotationKey: "target.domain.tld"},
+			expected:       endpoint.Targets{"target.domain.tld"},
+		},
+		{
+			title:       "annotation with name in the namespace of the route",
+			annotations: map[string]string{traefikServiceAnnotationKey: "traefik-internal"},
+			expected:    endpoint.Targets{"10.0.0.1"},
+		},
+		{
+			title:          "annotation overrides the Traefik Service",
+			traefikService: "traefik/traefik",
+			annotations:    map[string]string{traefikServiceAnnotationKey: "traefik/traefik-internal"},
+			expected:       endpoint.Targets{"10.0.0.1"},
+		},
+		{
+			title:          "missing Traefik Service",
+			traefikService: "traefik/missing",
+			expected:       nil,
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			ir := IngressRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: ingressrouteGVR.GroupVersion().String(),
+					Kind:       "IngressRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ingressroute-service",
+					Namespace:   defaultTraefikNamespace,
+					Annotations: ti.annotations,
+				},
+				Spec: traefikIngressRouteSpec{
+					Routes: []traefikRoute{{Match: "Host(`a.example.com`)"}},
+				},
+			}
+			u := &unstructured.Unstructured{}
+			data, err := json.Marshal(ir)
+			require.NoError(t, err)
+			require.NoError(t, u.UnmarshalJSON(data))
+
+			scheme := runtime.NewScheme()
+			scheme.AddKnownTypes(ingressrouteGVR.GroupVersion(), &IngressRoute{}, &IngressRouteList{})
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(scheme)
+			_, err = fakeDynamicClient.Resource(ingressrouteGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), u, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			kubeClient := newTraefikFakeKubeClient(ingressrouteGVR)
+			for name, ingress := range map[string][]corev1.LoadBalancerIngress{
+				"traefik":          {{IP: "1.2.3.4"}, {Hostname: "lb.example.com"}},
+				"traefik-internal": {{IP: "10.0.0.1"}},
+			} {
+				svc := &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: defaultTraefikNamespace},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+					Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingress}},
+				}
+				_, err = kubeClient.CoreV1().Services(defaultTraefikNamespace).Create(context.Background(), svc, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, kubeClient, defaultTraefikNamespace, "", false, true, false, false, ti.traefikService)
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			var targets endpoint.Targets
+			for _, ep := range endpoints {
+				assert.Equal(t, "a.example.com", ep.DNSName)
+				targets = append(targets, ep.Targets...)
+			}
+			assert.ElementsMatch(t, ti.expected, targets)
+		})
+	}
+}
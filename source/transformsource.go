@@ -0,0 +1,354 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// TransformationRule describes a transformation of the endpoints matching a condition. The condition and the
+// values set are Go templates rendered with the endpoint, see transformData.
+type TransformationRule struct {
+	// Name identifies the rule in logs and errors
+	Name string `yaml:"name"`
+	// If is a template rendering "true" for the endpoints the rule applies to, all endpoints if empty
+	If string `yaml:"if,omitempty"`
+	// Drop removes the endpoints the rule applies to
+	Drop bool `yaml:"drop,omitempty"`
+	// DNSName is a template rendering the new DNS name
+	DNSName string `yaml:"dnsName,omitempty"`
+	// Target is a template rendered for every target, available as .Target, rendering the new target.
+	// Targets rendering an empty string are removed, and endpoints without targets left are dropped.
+	Target string `yaml:"target,omitempty"`
+	// TTL is a template rendering the new TTL in seconds
+	TTL string `yaml:"ttl,omitempty"`
+	// Labels are templates rendering the values of the labels to set, labels rendering an empty string are removed
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// ProviderSpecific are templates rendering the values of the provider specific properties to set,
+	// properties rendering an empty string are removed
+	ProviderSpecific map[string]string `yaml:"providerSpecific,omitempty"`
+}
+
+// transformData is the data the templates of the transformation rules are rendered with.
+type transformData struct {
+	DNSName          string
+	RecordType       string
+	SetIdentifier    string
+	Targets          []string
+	TTL              int64
+	Labels           map[string]string
+	ProviderSpecific map[string]string
+	// Target is the target being rendered by the target template
+	Target string
+}
+
+// transformFuncs are the functions available to the templates of the transformation rules.
+var transformFuncs = template.FuncMap{
+	"contains":    strings.Contains,
+	"hasPrefix":   strings.HasPrefix,
+	"hasSuffix":   strings.HasSuffix,
+	"inNet":       inNet,
+	"isPrivateIP": isPrivateIP,
+	"lower":       strings.ToLower,
+	"replace":     strings.ReplaceAll,
+	"trimPrefix":  strings.TrimPrefix,
+	"trimSuffix":  strings.TrimSuffix,
+	"upper":       strings.ToUpper,
+}
+
+// inNet returns true when the address is an IP address within one of the networks, given in CIDR notation.
+func inNet(address string, cidrs ...string) (bool, error) {
+	ip := net.ParseIP(address)
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return false, err
+		}
+		if ip != nil && network.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isPrivateIP returns true when the address is a private IP address, in the sense of RFC 1918 or RFC 4193.
+func isPrivateIP(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsPrivate()
+}
+
+// transformation is a TransformationRule with its parsed templates.
+type transformation struct {
+	name             string
+	condition        *template.Template
+	drop             bool
+	dnsName          *template.Template
+	target           *template.Template
+	ttl              *template.Template
+	labels           map[string]*template.Template
+	providerSpecific map[string]*template.Template
+}
+
+// LoadTransformationRules reads the transformation rules from a YAML file holding a list of rules.
+func LoadTransformationRules(path string) ([]TransformationRule, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []TransformationRule
+	if err := yaml.UnmarshalStrict(content, &rules); err != nil {
+		return nil, fmt.Errorf("parsing transformation rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// transformSource is a Source that transforms the endpoints of its wrapped source with transformation rules,
+// applied in order.
+type transformSource struct {
+	source          Source
+	transformations []transformation
+}
+
+// NewTransformSource creates a new transformSource wrapping the provided Source. It returns an error when a
+// template of the rules is invalid.
+func NewTransformSource(source Source, rules []TransformationRule) (Source, error) {
+	transformations := make([]transformation, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("transformation rule %d has no name", i)
+		}
+		t, err := newTransformation(rule)
+		if err != nil {
+			return nil, fmt.Errorf("transformation rule %s: %w", rule.Name, err)
+		}
+		transformations = append(transformations, t)
+	}
+	return &transformSource{source: source, transformations: transformations}, nil
+}
+
+func newTransformation(rule TransformationRule) (transformation, error) {
+	t := transformation{name: rule.Name, drop: rule.Drop}
+	var err error
+	if t.condition, err = parseTransformTemplate("if", rule.If); err != nil {
+		return t, err
+	}
+	if t.dnsName, err = parseTransformTemplate("dnsName", rule.DNSName); err != nil {
+		return t, err
+	}
+	if t.target, err = parseTransformTemplate("target", rule.Target); err != nil {
+		return t, err
+	}
+	if t.ttl, err = parseTransformTemplate("ttl", rule.TTL); err != nil {
+		return t, err
+	}
+	if t.labels, err = parseTransformTemplates("labels", rule.Labels); err != nil {
+		return t, err
+	}
+	if t.providerSpecific, err = parseTransformTemplates("providerSpecific", rule.ProviderSpecific); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// parseTransformTemplate parses a template of a rule, which is nil if text is empty.
+func parseTransformTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Funcs(transformFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func parseTransformTemplates(name string, texts map[string]string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(texts))
+	for key, text := range texts {
+		tmpl, err := template.New(name + "." + key).Funcs(transformFuncs).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s template of %s: %w", name, key, err)
+		}
+		templates[key] = tmpl
+	}
+	return templates, nil
+}
+
+// Endpoints collects endpoints from its wrapped source and transforms copies of them, so that the endpoints
+// cached by the wrapped source are not transformed twice.
+func (ts *transformSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := ts.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		ep = ep.DeepCopy()
+		keep := true
+		for _, t := range ts.transformations {
+			if keep, err = t.apply(ep); err != nil {
+				return nil, fmt.Errorf("transformation rule %s of %s %s: %w", t.name, ep.RecordType, ep.DNSName, err)
+			}
+			if !keep {
+				log.WithField("endpoint", ep).Debugf("Dropping endpoint by transformation rule %s", t.name)
+				break
+			}
+		}
+		if keep {
+			result = append(result, ep)
+		}
+	}
+	return result, nil
+}
+
+// apply transforms the endpoint when the rule applies to it. It returns false when the endpoint has to be dropped.
+func (t *transformation) apply(ep *endpoint.Endpoint) (bool, error) {
+	data := newTransformData(ep)
+	if t.condition != nil {
+		matched, err := render(t.condition, data)
+		if err != nil {
+			return true, err
+		}
+		if matched != "true" {
+			return true, nil
+		}
+	}
+	if t.drop {
+		return false, nil
+	}
+
+	if t.dnsName != nil {
+		name, err := render(t.dnsName, data)
+		if err != nil {
+			return true, err
+		}
+		if name == "" {
+			return true, errors.New("the DNS name rendered empty")
+		}
+		ep.DNSName = name
+	}
+	if t.target != nil {
+		targets := make(endpoint.Targets, 0, len(ep.Targets))
+		for _, target := range ep.Targets {
+			data.Target = target
+			rendered, err := render(t.target, data)
+			if err != nil {
+				return true, err
+			}
+			if rendered != "" {
+				targets = append(targets, rendered)
+			}
+		}
+		data.Target = ""
+		// endpoints without targets cannot be planned
+		if len(targets) == 0 {
+			return false, nil
+		}
+		sort.Sort(targets)
+		ep.Targets = targets
+	}
+	if t.ttl != nil {
+		rendered, err := render(t.ttl, data)
+		if err != nil {
+			return true, err
+		}
+		ttl, err := strconv.ParseInt(rendered, 10, 64)
+		if err != nil || ttl < 0 {
+			return true, fmt.Errorf("invalid TTL %q", rendered)
+		}
+		ep.RecordTTL = endpoint.TTL(ttl)
+	}
+	for _, key := range sortedKeys(t.labels) {
+		value, err := render(t.labels[key], data)
+		if err != nil {
+			return true, err
+		}
+		if ep.Labels == nil {
+			ep.Labels = endpoint.NewLabels()
+		}
+		if value == "" {
+			delete(ep.Labels, key)
+		} else {
+			ep.Labels[key] = value
+		}
+	}
+	for _, name := range sortedKeys(t.providerSpecific) {
+		value, err := render(t.providerSpecific[name], data)
+		if err != nil {
+			return true, err
+		}
+		if value == "" {
+			ep.DeleteProviderSpecificProperty(name)
+		} else {
+			ep.SetProviderSpecificProperty(name, value)
+		}
+	}
+	return true, nil
+}
+
+func newTransformData(ep *endpoint.Endpoint) transformData {
+	data := transformData{
+		DNSName:          ep.DNSName,
+		RecordType:       ep.RecordType,
+		SetIdentifier:    ep.SetIdentifier,
+		Targets:          ep.Targets,
+		TTL:              int64(ep.RecordTTL),
+		Labels:           ep.Labels,
+		ProviderSpecific: map[string]string{},
+	}
+	for _, p := range ep.ProviderSpecific {
+		data.ProviderSpecific[p.Name] = p.Value
+	}
+	return data
+}
+
+// render executes the template and returns its output without surrounding whitespace.
+func render(tmpl *template.Template, data transformData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func sortedKeys(templates map[string]*template.Template) []string {
+	keys := make([]string, 0, len(templates))
+	for key := range templates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (ts *transformSource) AddEventHandler(ctx context.Context, handler func()) {
+	ts.source.AddEventHandler(ctx, handler)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestTransformSource(t *testing.T) {
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeCNAME, "app.elb.amazonaws.com"),
+		endpoint.NewEndpointWithTTL("db.example.org", endpoint.RecordTypeA, 30, "10.0.0.1", "192.168.0.1"),
+		endpoint.NewEndpoint("tmp.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	}
+	endpoints[0].WithProviderSpecific("alias", "false")
+	src, err := NewTransformSource(NewEchoSource(endpoints), []TransformationRule{
+		{
+			Name:             "alias-elbs",
+			If:               `{{ and (eq .RecordType "CNAME") (hasSuffix (index .Targets 0) ".elb.amazonaws.com") }}`,
+			ProviderSpecific: map[string]string{"alias": "true"},
+			Labels:           map[string]string{"team": "{{ trimSuffix .DNSName \".example.org\" }}"},
+		},
+		{
+			Name:   "private-targets",
+			If:     `{{ eq .RecordType "A" }}`,
+			Target: `{{ if not (hasPrefix .Target "10.") }}{{ .Target }}{{ end }}`,
+			TTL:    `{{ if lt .TTL 60 }}60{{ else }}{{ .TTL }}{{ end }}`,
+		},
+		{
+			Name: "drop-tmp",
+			If:   `{{ hasPrefix .DNSName "tmp." }}`,
+			Drop: true,
+		},
+		{
+			Name:    "rename",
+			DNSName: `{{ replace .DNSName ".example.org" ".example.com" }}`,
+		},
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		result, err := src.Endpoints(context.Background())
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		assert.Equal(t, "app.example.com", result[0].DNSName)
+		alias, _ := result[0].GetProviderSpecificProperty("alias")
+		assert.Equal(t, "true", alias)
+		assert.Equal(t, "app", result[0].Labels["team"])
+
+		assert.Equal(t, "db.example.com", result[1].DNSName)
+		assert.Equal(t, endpoint.Targets{"192.168.0.1"}, result[1].Targets)
+		assert.Equal(t, endpoint.TTL(60), result[1].RecordTTL)
+	}
+	// the endpoints of the wrapped source are left untouched
+	assert.Equal(t, "app.example.org", endpoints[0].DNSName)
+	assert.Equal(t, endpoint.TTL(30), endpoints[1].RecordTTL)
+}
+
+func TestTransformSourceErrors(t *testing.T) {
+	_, err := NewTransformSource(NewEchoSource(nil), []TransformationRule{{If: "true"}})
+	assert.Error(t, err)
+
+	_, err = NewTransformSource(NewEchoSource(nil), []TransformationRule{{Name: "invalid", DNSName: "{{ .DNSName"}})
+	assert.Error(t, err)
+
+	src, err := NewTransformSource(NewEchoSource([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("app.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	}), []TransformationRule{{Name: "ttl", TTL: "{{ .DNSName }}"}})
+	require.NoError(t, err)
+	_, err = src.Endpoints(context.Background())
+	assert.Error(t, err)
+}
+
+func TestLoadTransformationRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+- name: ttl
+  if: '{{ eq .RecordType "A" }}'
+  ttl: "300"
+  labels:
+    team: platform
+`), 0o600))
+
+	rules, err := LoadTransformationRules(path)
+	require.NoError(t, err)
+	assert.Equal(t, []TransformationRule{{
+		Name:   "ttl",
+		If:     `{{ eq .RecordType "A" }}`,
+		TTL:    "300",
+		Labels: map[string]string{"team": "platform"},
+	}}, rules)
+
+	require.NoError(t, os.WriteFile(path, []byte("- name: ttl\n  unknown: true\n"), 0o600))
+	_, err = LoadTransformationRules(path)
+	assert.Error(t, err)
+}